@@ -18,6 +18,8 @@ type ApiClusterGroup struct {
 
 	Members []ApiMember `json:"members,omitempty"`
 
+	MemberSelector *ApiMemberSelector `json:"memberSelector,omitempty"`
+
 	Name string `json:"name,omitempty"`
 
 	OrganizationId int32 `json:"organizationId,omitempty"`
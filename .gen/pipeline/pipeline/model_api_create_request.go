@@ -14,5 +14,7 @@ type ApiCreateRequest struct {
 
 	Members []int32 `json:"members,omitempty"`
 
+	MemberSelector *ApiMemberSelector `json:"memberSelector,omitempty"`
+
 	Name string `json:"name,omitempty"`
 }
@@ -0,0 +1,22 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ApiMemberSelector struct {
+
+	Cloud string `json:"cloud,omitempty"`
+
+	Distribution string `json:"distribution,omitempty"`
+
+	Location string `json:"location,omitempty"`
+
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
@@ -0,0 +1,35 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// ApplyClusterNodePool - Desired state of a node pool.
+type ApplyClusterNodePool struct {
+
+	// Node pool size.
+	Size int32 `json:"size,omitempty"`
+
+	Autoscaling NodePoolAutoScaling `json:"autoscaling,omitempty"`
+
+	InstanceType string `json:"instanceType"`
+
+	Image string `json:"image,omitempty"`
+
+	SpotPrice string `json:"spotPrice,omitempty"`
+
+	// Subnet of the node pool. Only used when creating the node pool.
+	SubnetId string `json:"subnetId,omitempty"`
+
+	// Node pool labels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints registered with the nodes. Only used when creating the node pool.
+	Taints []NodeTaint `json:"taints,omitempty"`
+}
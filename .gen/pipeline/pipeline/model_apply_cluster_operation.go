@@ -0,0 +1,22 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ApplyClusterOperation struct {
+
+	Type string `json:"type"`
+
+	// Name of the node pool or integrated service the operation applies to.
+	Target string `json:"target,omitempty"`
+
+	// Changed properties of an updated resource.
+	Changes []string `json:"changes,omitempty"`
+}
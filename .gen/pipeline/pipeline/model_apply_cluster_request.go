@@ -0,0 +1,23 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// ApplyClusterRequest - Desired state of a cluster. Omitted sections are left untouched.
+type ApplyClusterRequest struct {
+
+	// Complete set of node pools keyed by node pool name. Node pools missing from the set are deleted.
+	NodePools map[string]ApplyClusterNodePool `json:"nodePools,omitempty"`
+
+	ScaleOptions *ScaleOptions `json:"scaleOptions,omitempty"`
+
+	// Complete set of active integrated service specs keyed by service name. Active services missing from the set are deactivated.
+	IntegratedServices map[string]map[string]interface{} `json:"integratedServices,omitempty"`
+}
@@ -0,0 +1,22 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ApplyClusterResponse struct {
+
+	// Cluster apply process ID. Empty in dry-run mode or when there is nothing to execute.
+	ProcessId string `json:"processId,omitempty"`
+
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Operations required to reach the desired state.
+	Operations []ApplyClusterOperation `json:"operations,omitempty"`
+}
@@ -13,7 +13,7 @@ package pipeline
 // BaseUpdateNodePoolRequest - Base node pool update request object for all cluster distributions.
 type BaseUpdateNodePoolRequest struct {
 
-	// Node pool size.
+	// Node pool size. Autoscaling is disabled unless it is enabled in the request.
 	Size int32 `json:"size"`
 
 	// Node pool labels.
@@ -0,0 +1,32 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type CloneClusterRequest struct {
+
+	// Name of the new cluster
+	Name string `json:"name"`
+
+	// Location of the new cluster (defaults to the location of the source cluster)
+	Location string `json:"location,omitempty"`
+
+	// ID of the secret used by the new cluster (defaults to the secret of the source cluster)
+	SecretId string `json:"secretId,omitempty"`
+
+	// Name of the secret used by the new cluster (defaults to the secret of the source cluster)
+	SecretName string `json:"secretName,omitempty"`
+
+	// Labels of the new cluster (defaults to the labels of the source cluster)
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Copy the specifications of the active integrated services of the source cluster
+	CopyIntegratedServices bool `json:"copyIntegratedServices,omitempty"`
+}
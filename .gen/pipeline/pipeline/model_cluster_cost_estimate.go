@@ -0,0 +1,42 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+type ClusterCostEstimate struct {
+
+	// Identifier of the cluster
+	ClusterId int32 `json:"clusterId,omitempty"`
+
+	// Name of the cluster
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Cloud provider of the cluster
+	Cloud string `json:"cloud,omitempty"`
+
+	// Kubernetes distribution of the cluster
+	Distribution string `json:"distribution,omitempty"`
+
+	// Region of the cluster
+	Region string `json:"region,omitempty"`
+
+	// Currency of the estimated costs
+	Currency string `json:"currency,omitempty"`
+
+	NodePools []NodePoolCostEstimate `json:"nodePools,omitempty"`
+
+	// Hourly fee of the managed control plane
+	ControlPlaneHourlyCost float64 `json:"controlPlaneHourlyCost,omitempty"`
+
+	// Estimated hourly cost of the cluster
+	HourlyCost float64 `json:"hourlyCost,omitempty"`
+
+	// Estimated monthly cost of the cluster
+	MonthlyCost float64 `json:"monthlyCost,omitempty"`
+}
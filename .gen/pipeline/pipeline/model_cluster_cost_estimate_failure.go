@@ -0,0 +1,22 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+type ClusterCostEstimateFailure struct {
+
+	// Identifier of the cluster
+	ClusterId int32 `json:"clusterId,omitempty"`
+
+	// Name of the cluster
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Reason of the failure
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,29 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type ClusterHealth struct {
+
+	// Overall health status of the cluster
+	Status string `json:"status,omitempty"`
+
+	Checks []ClusterHealthCheck `json:"checks,omitempty"`
+
+	// Time of the latest health check
+	CheckedAt time.Time `json:"checkedAt,omitempty"`
+
+	// Time the cluster got into its current health status
+	TransitionedAt time.Time `json:"transitionedAt,omitempty"`
+}
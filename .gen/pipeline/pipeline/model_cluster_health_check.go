@@ -0,0 +1,22 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+type ClusterHealthCheck struct {
+
+	// Name of the health check
+	Name string `json:"name,omitempty"`
+
+	// Status of the health check
+	Status string `json:"status,omitempty"`
+
+	// Details of the health check result
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,41 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ClusterPolicy struct {
+
+	// Cloud providers clusters can be created in. Empty allows every cloud.
+	AllowedClouds []string `json:"allowedClouds,omitempty"`
+
+	// Kubernetes distributions clusters can be created with. Empty allows every distribution.
+	AllowedDistributions []string `json:"allowedDistributions,omitempty"`
+
+	// Locations clusters can be created in. Empty allows every location.
+	AllowedLocations []string `json:"allowedLocations,omitempty"`
+
+	// Instance types node pools can use. Empty allows every instance type.
+	AllowedInstanceTypes []string `json:"allowedInstanceTypes,omitempty"`
+
+	// Kubernetes versions clusters can run. A minor version (eg. 1.15) allows every patch version. Empty allows every version.
+	AllowedKubernetesVersions []string `json:"allowedKubernetesVersions,omitempty"`
+
+	// Maximum number of nodes a node pool can scale to. Zero means no limit.
+	MaxNodesPerNodePool int32 `json:"maxNodesPerNodePool,omitempty"`
+
+	// Maximum number of nodes a cluster can scale to. Zero means no limit.
+	MaxNodesPerCluster int32 `json:"maxNodesPerCluster,omitempty"`
+
+	// Maximum number of clusters in the organization. Zero means no limit.
+	MaxClusters int32 `json:"maxClusters,omitempty"`
+
+	// Label keys every new cluster must have.
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+}
@@ -0,0 +1,37 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type ClusterTemplate struct {
+
+	Id int32 `json:"id,omitempty"`
+
+	OrganizationId int32 `json:"organizationId,omitempty"`
+
+	Name string `json:"name,omitempty"`
+
+	Description string `json:"description,omitempty"`
+
+	// Incremented on every update of the template
+	Version int32 `json:"version,omitempty"`
+
+	Parameters []ClusterTemplateParameter `json:"parameters,omitempty"`
+
+	Template string `json:"template,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,27 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ClusterTemplateParameter struct {
+
+	// Name of the parameter as referenced in the template
+	Name string `json:"name"`
+
+	// Type of the parameter: string, integer, number or boolean
+	Type string `json:"type"`
+
+	Description string `json:"description,omitempty"`
+
+	Required bool `json:"required,omitempty"`
+
+	// Value used when the parameter is not supplied
+	Default interface{} `json:"default,omitempty"`
+}
@@ -0,0 +1,23 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ClusterTemplateRequest struct {
+
+	Name string `json:"name"`
+
+	Description string `json:"description,omitempty"`
+
+	Parameters []ClusterTemplateParameter `json:"parameters,omitempty"`
+
+	// Go template rendering a cluster creation request JSON document. String parameters are printed JSON escaped, the json function prints them as quoted JSON strings.
+	Template string `json:"template"`
+}
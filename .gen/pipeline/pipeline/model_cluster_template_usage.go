@@ -0,0 +1,26 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type ClusterTemplateUsage struct {
+
+	ClusterId int32 `json:"clusterId,omitempty"`
+
+	TemplateId int32 `json:"templateId,omitempty"`
+
+	TemplateVersion int32 `json:"templateVersion,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,42 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type ClusterTimelineEntry struct {
+
+	// Time of the entry
+	Timestamp time.Time `json:"timestamp,omitempty"`
+
+	// Type of the entry
+	Type string `json:"type,omitempty"`
+
+	// New cluster status for status changes, process (event) status otherwise
+	Status string `json:"status,omitempty"`
+
+	// Previous cluster status (status changes only)
+	PreviousStatus string `json:"previousStatus,omitempty"`
+
+	// Status message or process log
+	Message string `json:"message,omitempty"`
+
+	// ID of the user who triggered the status change
+	UserId int32 `json:"userId,omitempty"`
+
+	// ID of the related process
+	ProcessId string `json:"processId,omitempty"`
+
+	// Type of the related process or process event
+	ProcessType string `json:"processType,omitempty"`
+}
@@ -0,0 +1,41 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type CostUsageReport struct {
+
+	// Start of the report time range
+	From time.Time `json:"from,omitempty"`
+
+	// End of the report time range
+	To time.Time `json:"to,omitempty"`
+
+	// Dimension the report is grouped by
+	GroupBy string `json:"groupBy,omitempty"`
+
+	// Cluster label key the report is grouped by
+	LabelKey string `json:"labelKey,omitempty"`
+
+	// Currency of the costs
+	Currency string `json:"currency,omitempty"`
+
+	// Total node hours during the time range
+	NodeHours float64 `json:"nodeHours,omitempty"`
+
+	// Total cost during the time range
+	Cost float64 `json:"cost,omitempty"`
+
+	Items []CostUsageReportItem `json:"items,omitempty"`
+}
@@ -0,0 +1,31 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+type CostUsageReportItem struct {
+
+	// Identifier of the cluster
+	ClusterId int32 `json:"clusterId,omitempty"`
+
+	// Name of the cluster
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Name of the node pool (empty for the managed control plane fee)
+	NodePool string `json:"nodePool,omitempty"`
+
+	// Value of the cluster label
+	LabelValue string `json:"labelValue,omitempty"`
+
+	// Node hours of the group during the time range
+	NodeHours float64 `json:"nodeHours,omitempty"`
+
+	// Cost of the group during the time range
+	Cost float64 `json:"cost,omitempty"`
+}
@@ -28,5 +28,11 @@ type CreateClusterRequest struct {
 
 	ScaleOptions ScaleOptions `json:"scaleOptions,omitempty"`
 
+	// Cluster labels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Prevents the cluster from being deleted until the protection is disabled.
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
 	Properties map[string]interface{} `json:"properties"`
 }
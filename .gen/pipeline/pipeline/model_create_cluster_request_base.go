@@ -22,5 +22,11 @@ type CreateClusterRequestBase struct {
 
 	ScaleOptions ScaleOptions `json:"scaleOptions,omitempty"`
 
+	// Cluster labels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Prevents the cluster from being deleted until the protection is disabled.
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
 	Type string `json:"type"`
 }
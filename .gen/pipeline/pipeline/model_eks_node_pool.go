@@ -26,6 +26,8 @@ type EksNodePool struct {
 
 	Labels map[string]string `json:"labels,omitempty"`
 
+	Taints []NodeTaint `json:"taints,omitempty"`
+
 	Image string `json:"image,omitempty"`
 
 	Subnet EksSubnet `json:"subnet,omitempty"`
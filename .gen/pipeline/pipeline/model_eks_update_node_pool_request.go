@@ -13,8 +13,8 @@ package pipeline
 // EksUpdateNodePoolRequest - Node pool update request object for an EKS cluster.
 type EksUpdateNodePoolRequest struct {
 
-	// Node pool size.
-	Size int32 `json:"size"`
+	// Node pool size. Disables autoscaling when set without enabling autoscaling.
+	Size int32 `json:"size,omitempty"`
 
 	// Node pool labels.
 	Labels map[string]string `json:"labels,omitempty"`
@@ -29,4 +29,18 @@ type EksUpdateNodePoolRequest struct {
 
 	// The upper limit price for the requested spot instance. If this field is empty or 0 on-demand instances are used instead of spot instances.
 	SpotPrice string `json:"spotPrice,omitempty"`
+
+	// Size of the root volume of the nodes in GiB. Changing it replaces the nodes.
+	VolumeSize int32 `json:"volumeSize,omitempty"`
+
+	// Type of the root volume of the nodes. Changing it replaces the nodes.
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// Additional security groups attached to the nodes. Changing them replaces the nodes.
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+
+	// Taints registered with the nodes. They are reconciled on the existing nodes, an empty list removes them.
+	Taints []NodeTaint `json:"taints,omitempty"`
+
+	Options NodePoolUpdateOptions `json:"options,omitempty"`
 }
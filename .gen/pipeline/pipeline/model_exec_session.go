@@ -0,0 +1,52 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type ExecSession struct {
+
+	Id int32 `json:"id,omitempty"`
+
+	UserId int32 `json:"userId,omitempty"`
+
+	UserLogin string `json:"userLogin,omitempty"`
+
+	// exec or attach
+	Mode string `json:"mode,omitempty"`
+
+	Namespace string `json:"namespace,omitempty"`
+
+	Pod string `json:"pod,omitempty"`
+
+	Container string `json:"container,omitempty"`
+
+	Command []string `json:"command,omitempty"`
+
+	Tty bool `json:"tty,omitempty"`
+
+	StartedAt time.Time `json:"startedAt,omitempty"`
+
+	EndedAt time.Time `json:"endedAt,omitempty"`
+
+	// Duration of the session (or the time elapsed since its start if it is still running)
+	Duration string `json:"duration,omitempty"`
+
+	// completed, timeout, idle or error
+	EndReason string `json:"endReason,omitempty"`
+
+	Error string `json:"error,omitempty"`
+
+	// Whether an asciicast recording is available for the session
+	Recorded bool `json:"recorded,omitempty"`
+}
@@ -21,4 +21,7 @@ type GenericNodePool struct {
 
 	// Node pool labels.
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints registered with the nodes.
+	Taints []NodeTaint `json:"taints,omitempty"`
 }
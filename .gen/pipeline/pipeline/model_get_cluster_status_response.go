@@ -48,6 +48,8 @@ type GetClusterStatusResponse struct {
 
 	Oidc OidcConfig `json:"oidc,omitempty"`
 
+	Labels map[string]string `json:"labels,omitempty"`
+
 	NodePools map[string]NodePoolStatus `json:"nodePools,omitempty"`
 
 	TotalSummary ResourceSummary `json:"totalSummary,omitempty"`
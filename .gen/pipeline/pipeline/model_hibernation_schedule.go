@@ -0,0 +1,23 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type HibernationSchedule struct {
+
+	// Cron expression (UTC) for hibernating the cluster.
+	Hibernate string `json:"hibernate,omitempty"`
+
+	// Cron expression (UTC) for resuming the cluster.
+	Resume string `json:"resume,omitempty"`
+
+	// Name of a node pool kept running with a single node during scheduled hibernations.
+	KeepNodePool string `json:"keepNodePool,omitempty"`
+}
@@ -0,0 +1,40 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type KubernetesEvent struct {
+
+	Namespace string `json:"namespace,omitempty"`
+
+	Name string `json:"name,omitempty"`
+
+	// Normal or Warning
+	Type string `json:"type,omitempty"`
+
+	Reason string `json:"reason,omitempty"`
+
+	Message string `json:"message,omitempty"`
+
+	InvolvedObject KubernetesEventObjectReference `json:"involvedObject,omitempty"`
+
+	// Component reporting the event
+	Source string `json:"source,omitempty"`
+
+	Count int32 `json:"count,omitempty"`
+
+	FirstTimestamp time.Time `json:"firstTimestamp,omitempty"`
+
+	LastTimestamp time.Time `json:"lastTimestamp,omitempty"`
+}
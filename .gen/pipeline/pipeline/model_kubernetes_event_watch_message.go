@@ -0,0 +1,19 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type KubernetesEventWatchMessage struct {
+
+	// Type of the change (ADDED, MODIFIED or DELETED)
+	Type string `json:"type,omitempty"`
+
+	Event KubernetesEvent `json:"event,omitempty"`
+}
@@ -0,0 +1,23 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// NamespaceLimitRange - Default and allowed resources of containers in the namespace
+type NamespaceLimitRange struct {
+
+	Default map[string]string `json:"default,omitempty"`
+
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+
+	Max map[string]string `json:"max,omitempty"`
+
+	Min map[string]string `json:"min,omitempty"`
+}
@@ -0,0 +1,20 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type NamespaceRoleBinding struct {
+
+	// Organization members with this role are bound. Every member has the member role. The bound members follow the changes of the organization membership.
+	OrganizationRole string `json:"organizationRole"`
+
+	// Name of the cluster role granted in the namespace (eg. admin, edit or view)
+	NamespaceRole string `json:"namespaceRole"`
+}
@@ -0,0 +1,29 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type NamespaceSpec struct {
+
+	Labels map[string]string `json:"labels,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Hard limits of the namespace resource quota (eg. requests.cpu)
+	ResourceQuota map[string]string `json:"resourceQuota,omitempty"`
+
+	LimitRange NamespaceLimitRange `json:"limitRange,omitempty"`
+
+	RoleBindings []NamespaceRoleBinding `json:"roleBindings,omitempty"`
+
+	Vault NamespaceVaultSettings `json:"vault,omitempty"`
+
+	NetworkPolicy NamespaceNetworkPolicy `json:"networkPolicy,omitempty"`
+}
@@ -21,6 +21,9 @@ type NodePool struct {
 	// Node pool labels.
 	Labels map[string]string `json:"labels,omitempty"`
 
+	// Taints registered with the nodes.
+	Taints []NodeTaint `json:"taints,omitempty"`
+
 	Autoscaling NodePoolAutoScaling `json:"autoscaling,omitempty"`
 
 	// Machine instance type.
@@ -0,0 +1,34 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+type NodePoolCostEstimate struct {
+
+	// Name of the node pool
+	Name string `json:"name,omitempty"`
+
+	// Instance type of the nodes
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// Number of nodes in the node pool
+	Count int32 `json:"count,omitempty"`
+
+	// Whether the nodes are spot or preemptible instances
+	Spot bool `json:"spot,omitempty"`
+
+	// Hourly price of a single node
+	NodeHourlyPrice float64 `json:"nodeHourlyPrice,omitempty"`
+
+	// Estimated hourly cost of the node pool
+	HourlyCost float64 `json:"hourlyCost,omitempty"`
+
+	// Estimated monthly cost of the node pool
+	MonthlyCost float64 `json:"monthlyCost,omitempty"`
+}
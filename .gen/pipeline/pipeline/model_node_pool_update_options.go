@@ -0,0 +1,24 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// NodePoolUpdateOptions - Controls how nodes are replaced during a node pool update.
+type NodePoolUpdateOptions struct {
+
+	// Maximum number of extra nodes that can be created during the update.
+	MaxSurge int32 `json:"maxSurge,omitempty"`
+
+	// Maximum number of nodes that can be unavailable during the update.
+	MaxUnavailable int32 `json:"maxUnavailable,omitempty"`
+
+	// Maximum number of seconds to wait for a node to be drained.
+	DrainTimeout int32 `json:"drainTimeout,omitempty"`
+}
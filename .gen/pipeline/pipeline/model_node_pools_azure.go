@@ -23,4 +23,6 @@ type NodePoolsAzure struct {
 	InstanceType string `json:"instanceType"`
 
 	Labels map[string]string `json:"labels,omitempty"`
+
+	Taints []NodeTaint `json:"taints,omitempty"`
 }
@@ -25,4 +25,6 @@ type NodePoolsGoogle struct {
 	InstanceType string `json:"instanceType"`
 
 	Labels map[string]string `json:"labels,omitempty"`
+
+	Taints []NodeTaint `json:"taints,omitempty"`
 }
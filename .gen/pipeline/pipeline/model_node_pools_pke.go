@@ -19,6 +19,9 @@ type NodePoolsPke struct {
 	// user provided custom node labels to be placed onto the nodes of the node pool
 	Labels map[string]string `json:"labels,omitempty"`
 
+	// Taints registered with the nodes of the node pool.
+	Taints []NodeTaint `json:"taints,omitempty"`
+
 	// Enables/disables autoscaling of this node pool through Kubernetes cluster autoscaler.
 	Autoscaling bool `json:"autoscaling"`
 
@@ -0,0 +1,26 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+type OrganizationCostEstimate struct {
+
+	// Currency of the estimated costs
+	Currency string `json:"currency,omitempty"`
+
+	// Estimated hourly cost of the organization's clusters
+	HourlyCost float64 `json:"hourlyCost,omitempty"`
+
+	// Estimated monthly cost of the organization's clusters
+	MonthlyCost float64 `json:"monthlyCost,omitempty"`
+
+	Clusters []ClusterCostEstimate `json:"clusters,omitempty"`
+
+	FailedClusters []ClusterCostEstimateFailure `json:"failedClusters,omitempty"`
+}
@@ -18,6 +18,8 @@ type PkeOnAzureNodePool struct {
 
 	Labels map[string]string `json:"labels,omitempty"`
 
+	Taints []NodeTaint `json:"taints,omitempty"`
+
 	Subnet PkeOnAzureNodePoolSubnet `json:"subnet,omitempty"`
 
 	Zones []string `json:"zones,omitempty"`
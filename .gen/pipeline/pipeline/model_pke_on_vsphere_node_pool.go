@@ -18,6 +18,8 @@ type PkeOnVsphereNodePool struct {
 
 	Labels map[string]string `json:"labels,omitempty"`
 
+	Taints []NodeTaint `json:"taints,omitempty"`
+
 	Size int32 `json:"size,omitempty"`
 
 	// Number of VCPUs to attach to each node.
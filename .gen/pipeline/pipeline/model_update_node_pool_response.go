@@ -14,4 +14,7 @@ type UpdateNodePoolResponse struct {
 
 	// Node pool update process ID.
 	ProcessId string `json:"processId,omitempty"`
+
+	// Changed node pool properties that require replacing the existing nodes. The nodes are updated in place when empty.
+	NodeReplacementChanges []string `json:"nodeReplacementChanges,omitempty"`
 }
@@ -35,4 +35,7 @@ type UpdateNodePoolsPke struct {
 
 	// user provided custom node labels to be placed onto the nodes of the node pool
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints registered with the nodes of the node pool. They are reconciled on the existing nodes, an empty list removes them.
+	Taints []NodeTaint `json:"taints,omitempty"`
 }
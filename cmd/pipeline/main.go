@@ -61,6 +61,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/cadence/.gen/go/shared"
+	watermilllog "logur.dev/integration/watermill"
 	zaplog "logur.dev/integration/zap"
 	"logur.dev/logur"
 
@@ -76,7 +77,11 @@ import (
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/cap/capdriver"
 	googleproject "github.com/banzaicloud/pipeline/internal/app/pipeline/cloud/google/project"
 	googleprojectdriver "github.com/banzaicloud/pipeline/internal/app/pipeline/cloud/google/project/projectdriver"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate"
+	clustertemplateapp "github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate/app"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate/clustertemplateadapter"
 	process "github.com/banzaicloud/pipeline/internal/app/pipeline/process/app"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/process/processadapter"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/secrettype"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/secrettype/secrettypedriver"
 	arkClusterManager "github.com/banzaicloud/pipeline/internal/ark/clustermanager"
@@ -85,12 +90,32 @@ import (
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intClusterAuth "github.com/banzaicloud/pipeline/internal/cluster/auth"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply/clusterapplyadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply/clusterapplydriver"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost/clustercostadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusterdriver"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec/clusterexecadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth/clusterhealthadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace/clusternamespaceadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace/clusternamespaceworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy/clusterpolicyadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpreflight"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpreflight/clusterpreflightadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret/clustersecretadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustertimeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustertimeline/clustertimelineadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksadapter"
 	eksDriver "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksprovider/driver"
+	pkeDistribution "github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke/pkeadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/endpoints"
 	prometheusMetrics "github.com/banzaicloud/pipeline/internal/cluster/metrics/adapters/prometheus"
 	"github.com/banzaicloud/pipeline/internal/clustergroup"
@@ -115,11 +140,14 @@ import (
 	"github.com/banzaicloud/pipeline/internal/integratedservices/services/ingress"
 	integratedServiceLogging "github.com/banzaicloud/pipeline/internal/integratedservices/services/logging"
 	featureMonitoring "github.com/banzaicloud/pipeline/internal/integratedservices/services/monitoring"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services/secretsync"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services/secretsync/secretsyncadapter"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/services/securityscan"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/services/securityscan/securityscanadapter"
 	integratedServiceVault "github.com/banzaicloud/pipeline/internal/integratedservices/services/vault"
 	cgFeatureIstio "github.com/banzaicloud/pipeline/internal/istio/istiofeature"
 	"github.com/banzaicloud/pipeline/internal/kubernetes"
+	"github.com/banzaicloud/pipeline/internal/kubernetes/kubernetesadapter"
 	"github.com/banzaicloud/pipeline/internal/monitor"
 	intPKE "github.com/banzaicloud/pipeline/internal/pke"
 	"github.com/banzaicloud/pipeline/internal/platform/appkit"
@@ -145,6 +173,11 @@ import (
 	"github.com/banzaicloud/pipeline/internal/secret/restricted"
 	"github.com/banzaicloud/pipeline/internal/secret/secretadapter"
 	"github.com/banzaicloud/pipeline/internal/secret/types"
+	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/internal/security/securityadapter"
+	"github.com/banzaicloud/pipeline/internal/security/securityworkflow"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport/vulnreportadapter"
 	pkgAuth "github.com/banzaicloud/pipeline/pkg/auth"
 	"github.com/banzaicloud/pipeline/pkg/cloudinfo"
 	"github.com/banzaicloud/pipeline/pkg/ctxutil"
@@ -158,8 +191,10 @@ import (
 	"github.com/banzaicloud/pipeline/src/api/ark/buckets"
 	"github.com/banzaicloud/pipeline/src/api/ark/restores"
 	"github.com/banzaicloud/pipeline/src/api/ark/schedules"
+	"github.com/banzaicloud/pipeline/src/api/cluster/event"
 	"github.com/banzaicloud/pipeline/src/api/cluster/namespace"
 	"github.com/banzaicloud/pipeline/src/api/cluster/pke"
+	"github.com/banzaicloud/pipeline/src/api/cluster/pod"
 	cgroupAPI "github.com/banzaicloud/pipeline/src/api/clustergroup"
 	"github.com/banzaicloud/pipeline/src/api/common"
 	"github.com/banzaicloud/pipeline/src/auth"
@@ -277,6 +312,9 @@ func main() {
 	secret.InitSecretStore(secretStore, secretTypes)
 	restricted.InitSecretStore(secret.Store)
 
+	secretEventBus := evbus.New()
+	secret.Store.Events = secret.NewSecretEvents(secretEventBus)
+
 	// Connect to database
 	db, err := database.Connect(config.Database.Config)
 	emperror.Panic(errors.WithMessage(err, "failed to initialize db"))
@@ -307,6 +345,7 @@ func main() {
 
 	const organizationTopic = "organization"
 	var organizationSyncer auth.OIDCOrganizationSyncer
+	var organizationEvents auth.OrganizationEvents
 	{
 		eventBus, _ := cqrs.NewEventBus(
 			publisher,
@@ -314,6 +353,7 @@ func main() {
 			eventMarshaler,
 		)
 		eventDispatcher := auth.NewOrganizationEventDispatcher(eventBus)
+		organizationEvents = eventDispatcher
 
 		roleBinder, err := auth.NewRoleBinder(config.Auth.Role.Default, config.Auth.Role.Binding)
 		emperror.Panic(err)
@@ -337,7 +377,7 @@ func main() {
 	)
 	tokenManager := pkgAuth.NewTokenManager(tokenGenerator, tokenStore)
 	serviceAccountService := auth.NewServiceAccountService()
-	auth.Init(db, config.Auth, tokenStore, tokenManager, organizationSyncer, serviceAccountService)
+	auth.Init(db, config.Auth, tokenStore, tokenManager, organizationSyncer, organizationEvents, serviceAccountService)
 
 	if config.Database.AutoMigrate {
 		logger.Info("running automatic schema migrations")
@@ -410,11 +450,40 @@ func main() {
 
 	releaseDeleter := cmd.CreateReleaseDeleter(config.Helm, db, commonSecretStore, commonLogger)
 
-	clusterManager := cluster.NewManager(clusters, secretValidator, clusterEvents, statusChangeDurationMetric, clusterTotalMetric, workflowClient, logrusLogger, errorHandler, clusteradapter.NewStore(db, clusters), releaseDeleter)
+	clusterStore := clusteradapter.NewStore(db, clusters)
+	clusterManager := cluster.NewManager(clusters, secretValidator, clusterEvents, statusChangeDurationMetric, clusterTotalMetric, workflowClient, logrusLogger, errorHandler, clusterStore, releaseDeleter)
 	commonClusterGetter := common.NewClusterGetter(clusterManager, logrusLogger, errorHandler)
 
 	var group run.Group
 
+	{
+		router, err := watermill.NewRouter(watermill.RouterConfig{CloseTimeout: 5 * time.Second}, logger)
+		emperror.Panic(err)
+
+		organizationEventProcessor, err := cqrs.NewEventProcessor(
+			[]cqrs.EventHandler{
+				clusternamespace.NewMembershipChangedHandler(clusternamespaceworkflow.NewRoleBindingSyncStarter(workflowClient)),
+			},
+			func(eventName string) string { return organizationTopic },
+			func(handlerName string) (message.Subscriber, error) { return subscriber, nil },
+			eventMarshaler,
+			watermilllog.New(logur.WithFields(logger, map[string]interface{}{"component": "watermill"})),
+		)
+		emperror.Panic(err)
+
+		err = organizationEventProcessor.AddHandlersToRouter(router)
+		emperror.Panic(err)
+
+		group.Add(
+			func() error {
+				return router.Run(context.Background())
+			},
+			func(err error) {
+				_ = router.Close()
+			},
+		)
+	}
+
 	if config.SpotMetrics.Enabled {
 		ctx, cancel := context.WithCancel(context.Background())
 		exporter := monitor.NewSpotMetricsExporter(
@@ -493,7 +562,7 @@ func main() {
 	)
 
 	cgroupAdapter := cgroupAdapter.NewClusterGetter(clusterManager)
-	clusterGroupManager := clustergroup.NewManager(cgroupAdapter, clustergroup.NewClusterGroupRepository(db, logrusLogger), logrusLogger, errorHandler)
+	clusterGroupManager := clustergroup.NewManager(cgroupAdapter, clusterStore, clustergroup.NewClusterGroupRepository(db, logrusLogger), logrusLogger, errorHandler)
 	federationHandler := federation.NewFederationHandler(cgroupAdapter, config.Cluster.Namespace, logrusLogger, errorHandler, config.Cluster.Federation, config.Cluster.DNS.Config, unifiedHelmReleaser)
 	deploymentManager := deployment.NewCGDeploymentManager(db, cgroupAdapter, logrusLogger, errorHandler)
 
@@ -532,6 +601,13 @@ func main() {
 	clientFactory := kubernetes.NewClientFactory(configFactory)
 	dynamicClientFactory := kubernetes.NewDynamicClientFactory(configFactory)
 
+	clusterHealthStore := clusterhealthadapter.NewGormStore(db)
+
+	// the health checks are run by the workers, the results are exported from the store
+	if config.Cluster.Health.Enabled {
+		prometheus.MustRegister(clusterhealth.NewPrometheusCollector(clusterHealthStore, 10*time.Second))
+	}
+
 	// Initialise Gin router
 	engine := gin.New()
 
@@ -629,7 +705,7 @@ func main() {
 	clusterAuthService, err := intClusterAuth.NewDexClusterAuthService(clusterSecretStore)
 	emperror.Panic(errors.WrapIf(err, "failed to create DexClusterAuthService"))
 
-	dashboardAPI := dashboard.NewDashboardAPI(clusterManager, clusterGroupManager, logrusLogger, errorHandler, config.Auth, clusterAuthService)
+	dashboardAPI := dashboard.NewDashboardAPI(clusterManager, clusterGroupManager, logrusLogger, errorHandler, config.Auth, clusterAuthService, clusterStore)
 	dgroup := base.Group(path.Join("dashboard", "orgs"))
 	dgroup.Use(auth.InternalHandler)
 	dgroup.Use(auth.Handler)
@@ -657,6 +733,24 @@ func main() {
 		}
 	}
 
+	clusterTemplateService := clustertemplate.NewService(clustertemplateadapter.NewGormStore(db))
+
+	clusterPolicyStore := clusterpolicyadapter.NewGormStore(db)
+	clusterPolicyValidator := clusterpolicy.NewValidator(
+		clusterPolicyStore,
+		clusterpolicyadapter.NewClusterCounter(db),
+		clusterpolicyadapter.NewNodePoolLister(clusterManager),
+	)
+
+	clusterPreflightChecker := clusterpreflight.NewChecker(
+		clusterpreflightadapter.NewClusterNameChecker(db),
+		clusterpreflightadapter.NewSecretVerifier(secret.Store),
+		cloudinfoClient,
+		clusterpreflightadapter.NewNetworkServiceFactory(secret.Store, logrusLogger),
+		clusterpreflightadapter.NewAmazonAccountChecker(secret.Store),
+		clusterPolicyValidator,
+	)
+
 	clusterAPI := api.NewClusterAPI(
 		clusterManager,
 		commonClusterGetter,
@@ -671,6 +765,10 @@ func main() {
 		unifiedHelmReleaser,
 		config.Auth,
 		clusterAuthService,
+		clusterStore,
+		clusterTemplateService,
+		clusterPolicyValidator,
+		clusterPreflightChecker,
 	)
 
 	v1 := base.Group("api/v1")
@@ -712,6 +810,13 @@ func main() {
 			// cluster API
 			cRouter := orgs.Group("/:orgid/clusters/:id")
 			clusterRouter := orgRouter.PathPrefix("/clusters/{clusterId}").Subrouter()
+
+			// Node pool components shared by the cluster and the cluster apply APIs
+			var (
+				nodePoolValidator        intCluster.NodePoolValidator
+				nodePoolProcessor        intCluster.NodePoolProcessor
+				validNodePoolLabelSource intCluster.NodePoolLabelSource
+			)
 			{
 				logger := commonadapter.NewLogger(logger) // TODO: make this a context aware logger
 
@@ -784,7 +889,6 @@ func main() {
 
 				cRouter.GET("/deployments/:name/images", api.GetDeploymentImages)
 				{
-					clusterStore := clusteradapter.NewStore(db, clusters)
 
 					labelValidator := kubernetes2.LabelValidator{
 						ForbiddenDomains: append([]string{config.Cluster.Labels.Domain}, config.Cluster.Labels.ForbiddenDomains...),
@@ -806,11 +910,28 @@ func main() {
 						clusteradapter.NewCloudinfoNodePoolLabelSource(cloudinfoClient),
 					}
 
-					// Used by legacy node pool label code
-					globalcluster.SetNodePoolLabelSource(intCluster.NodePoolLabelSources{
+					validNodePoolLabelSource = intCluster.NodePoolLabelSources{
 						intCluster.NewFilterValidNodePoolLabelSource(labelValidator),
 						labelSource,
-					})
+					}
+
+					// Used by legacy node pool label code
+					globalcluster.SetNodePoolLabelSource(validNodePoolLabelSource)
+
+					nodePoolValidator = intCluster.NodePoolValidators{
+						intCluster.NewCommonNodePoolValidator(labelValidator),
+						intCluster.NewDistributionNodePoolValidator(map[string]intCluster.NodePoolValidator{
+							"eks": eksadapter.NewNodePoolValidator(db),
+						}),
+						clusterPolicyValidator,
+					}
+
+					nodePoolProcessor = intCluster.NodePoolProcessors{
+						intCluster.NewCommonNodePoolProcessor(labelSource),
+						intCluster.NewDistributionNodePoolProcessor(map[string]intCluster.NodePoolProcessor{
+							"eks": eksadapter.NewNodePoolProcessor(db),
+						}),
+					}
 
 					service := intCluster.NewService(
 						clusterStore,
@@ -819,33 +940,26 @@ func main() {
 						map[string]intCluster.Service{
 							"eks": clusteradapter.NewEKSService(eks.NewService(
 								clusterStore,
+								eksadapter.NewClusterStore(db),
+								eksadapter.NewClusterManager(workflowClient, config.Pipeline.Enterprise),
 								eksadapter.NewNodePoolStore(db),
 								eksadapter.NewNodePoolManager(workflowClient, config.Pipeline.Enterprise),
+								validNodePoolLabelSource,
+								clusteradapter.NewHibernationStore(db),
+								clusterPolicyValidator,
+							)),
+							"pke": clusteradapter.NewPKEService(clusterStore, pkeDistribution.NewService(
+								clusterStore,
+								pkeadapter.NewClusterManager(workflowClient),
+								pkeadapter.NewNodePoolStore(db, clusterStore, azurePKEClusterStore, gormVspherePKEClusterStore),
+								clusteradapter.NewHibernationStore(db),
 							)),
 						},
 						clusteradapter.NewNodePoolStore(db, clusterStore),
-						intCluster.NodePoolValidators{
-							intCluster.NewCommonNodePoolValidator(labelValidator),
-							intCluster.NewDistributionNodePoolValidator(map[string]intCluster.NodePoolValidator{
-								"eks": eksadapter.NewNodePoolValidator(db),
-							}),
-						},
-						intCluster.NodePoolProcessors{
-							intCluster.NewCommonNodePoolProcessor(labelSource),
-							intCluster.NewDistributionNodePoolProcessor(map[string]intCluster.NodePoolProcessor{
-								"eks": eksadapter.NewNodePoolProcessor(db),
-							}),
-						},
-						clusteradapter.NewNodePoolManager(
-							workflowClient,
-							func(ctx context.Context) uint {
-								if currentUser := ctx.Value(auth2.CurrentUser); currentUser != nil {
-									return currentUser.(*auth.User).ID
-								}
-
-								return 0
-							},
-						),
+						nodePoolValidator,
+						nodePoolProcessor,
+						clusteradapter.NewNodePoolManager(workflowClient, getCurrentUserID),
+						clusterPolicyValidator,
 					)
 
 					endpoints := clusterdriver.MakeEndpoints(
@@ -863,6 +977,12 @@ func main() {
 					cRouter.Any("/nodepools", gin.WrapH(router))
 					cRouter.Any("/nodepools/:nodePoolName", gin.WrapH(router))
 					cRouter.Any("/nodepools/:nodePoolName/update", gin.WrapH(router))
+					cRouter.Any("/upgrade", gin.WrapH(router))
+					cRouter.Any("/labels", gin.WrapH(router))
+					cRouter.Any("/deletion-protection", gin.WrapH(router))
+					cRouter.Any("/hibernate", gin.WrapH(router))
+					cRouter.Any("/resume", gin.WrapH(router))
+					cRouter.Any("/hibernation/schedule", gin.WrapH(router))
 				}
 			}
 
@@ -926,7 +1046,24 @@ func main() {
 
 					securityApiHandler := api.NewSecurityApiHandlers(commonClusterGetter, commonErrorHandler, commonLogger)
 
-					anchoreProxy := api.NewAnchoreProxy(basePath, configProvider, commonErrorHandler, commonLogger)
+					imageScanners := map[string]anchore.ImageScanner{
+						anchore.ScannerAnchore: anchore.NewAnchoreImageScanner(configProvider, commonLogger),
+					}
+
+					if config.Cluster.SecurityScan.Trivy.Enabled {
+						imageScanners[anchore.ScannerTrivy] = anchore.NewTrivyImageScanner(securityadapter.NewTrivyScanStore(db))
+					}
+
+					imageScanHandler := api.NewImageScanHandler(
+						commonClusterGetter,
+						anchore.NewImageScannerSelector(securityscan.NewScannerProvider(featureRepository), imageScanners),
+						securityscan.NewScannerProvider(featureRepository),
+						securityworkflow.NewImageScanStarter(workflowClient, config.Cluster.SecurityScan.Trivy.ScanTimeout),
+						commonErrorHandler,
+						commonLogger,
+					)
+
+					anchoreProxy := api.NewAnchoreProxy(basePath, configProvider, securityscan.NewScannerProvider(featureRepository), commonErrorHandler, commonLogger)
 					proxyHandler := anchoreProxy.Proxy()
 
 					// forthcoming endpoint for all requests proxied to Anchore
@@ -939,6 +1076,17 @@ func main() {
 					cRouter.GET("/whitelists", securityApiHandler.GetWhiteLists)
 					cRouter.POST("/whitelists", securityApiHandler.CreateWhiteList)
 					cRouter.DELETE("/whitelists/:name", securityApiHandler.DeleteWhiteList)
+
+					cRouter.GET("/images/:imageDigest/vulnerabilities", imageScanHandler.GetImageVulnerabilities)
+					cRouter.POST("/images/:imageDigest/scan", imageScanHandler.ScanImage)
+
+					vulnReportHandler := api.NewVulnerabilityReportHandler(
+						vulnreport.NewService(vulnreportadapter.NewGormStore(db)),
+						commonErrorHandler,
+					)
+
+					// organization resources
+					orgs.GET("/:orgid/security/vulnerabilities", vulnReportHandler.GetReport)
 				}
 
 				if config.Cluster.Expiry.Enabled {
@@ -954,8 +1102,34 @@ func main() {
 					))
 				}
 
+				if config.Cluster.SecretSync.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, secretsync.MakeIntegratedServiceManager(
+						clusterGetter,
+						secretsyncadapter.NewSecretStore(secret.Store),
+						kubernetes.NewService(
+							kubernetesadapter.NewConfigSecretGetter(clusters),
+							configFactory,
+							commonLogger,
+						),
+						commonLogger,
+					))
+				}
+
 				integratedServiceManagerRegistry := integratedservices.MakeIntegratedServiceManagerRegistry(integratedServiceManagers)
 				integratedServiceOperationDispatcher := integratedserviceadapter.MakeCadenceIntegratedServiceOperationDispatcher(workflowClient, commonLogger)
+
+				if config.Cluster.SecretSync.Enabled {
+					secretChangeHandler := secretsync.NewSecretChangeHandler(
+						secretsyncadapter.NewClusterFinder(db),
+						featureRepository,
+						integratedServiceOperationDispatcher,
+						commonLogger,
+					)
+
+					secretsyncadapter.NewSecretEvents(secretEventBus).NotifySecretChanged(func(organizationID uint, secretID string) {
+						commonErrorHandler.Handle(secretChangeHandler.SecretChanged(context.Background(), organizationID, secretID))
+					})
+				}
 				integratedServicesService = integratedservices.MakeIntegratedServiceService(integratedServiceOperationDispatcher, integratedServiceManagerRegistry, featureRepository, commonLogger)
 				endpoints := integratedservicesdriver.MakeEndpoints(
 					integratedServicesService,
@@ -991,13 +1165,110 @@ func main() {
 			cRouter.PUT("/hpa", hpaApi.PutHpaResource)
 			cRouter.DELETE("/hpa", hpaApi.DeleteHpaResource)
 
+			clusterCloneAPI := api.NewClusterCloneAPI(clusterAPI, integratedServicesService, errorHandler)
+			cRouter.POST("/clone", clusterCloneAPI.CloneCluster)
+
+			clusterCostAPI := api.NewClusterCostAPI(
+				clusterAPI,
+				clustercost.NewEstimator(cloudinfoClient),
+				clustercost.NewUsageReporter(clustercostadapter.NewGormLedgerStore(db)),
+				errorHandler,
+			)
+			cRouter.GET("/cost", clusterCostAPI.GetClusterCost)
+			cRouter.POST("/cost/estimate", clusterCostAPI.EstimateClusterUpdateCost)
+			orgs.GET("/:orgid/cost", clusterCostAPI.GetOrganizationCost)
+			orgs.POST("/:orgid/cost/estimate", clusterCostAPI.EstimateClusterCreateCost)
+			orgs.GET("/:orgid/cost/usage", clusterCostAPI.GetUsageReport)
+
+			clusterPolicyAPI := api.NewClusterPolicyAPI(clusterpolicy.NewService(clusterPolicyStore), errorHandler)
+			orgs.GET("/:orgid/cluster-policy", clusterPolicyAPI.GetClusterPolicy)
+			orgs.PUT("/:orgid/cluster-policy", clusterPolicyAPI.SetClusterPolicy)
+			orgs.DELETE("/:orgid/cluster-policy", clusterPolicyAPI.DeleteClusterPolicy)
+
+			clusterNamespaceService := clusternamespace.NewService(
+				clusternamespaceadapter.NewGormTemplateStore(db),
+				clusternamespaceadapter.NewMemberLister(db),
+			)
+
+			namespaceTemplateAPI := api.NewNamespaceTemplateAPI(clusterNamespaceService, errorHandler)
+			orgs.GET("/:orgid/namespace-templates", namespaceTemplateAPI.ListNamespaceTemplates)
+			orgs.GET("/:orgid/namespace-templates/:name", namespaceTemplateAPI.GetNamespaceTemplate)
+			orgs.PUT("/:orgid/namespace-templates/:name", namespaceTemplateAPI.SaveNamespaceTemplate)
+			orgs.DELETE("/:orgid/namespace-templates/:name", namespaceTemplateAPI.DeleteNamespaceTemplate)
+
+			clusterHealthAPI := api.NewClusterHealthAPI(clusterAPI, clusterHealthStore, errorHandler)
+			cRouter.GET("/health", clusterHealthAPI.GetClusterHealth)
+
+			clusterTimelineAPI := api.NewClusterTimelineAPI(
+				clusterAPI,
+				clustertimeline.NewService(
+					clustertimelineadapter.NewGormStatusHistoryStore(db),
+					processadapter.NewGormStore(db),
+				),
+				errorHandler,
+			)
+			cRouter.GET("/timeline", clusterTimelineAPI.GetClusterTimeline)
+
+			{
+				service := clusterapply.NewService(
+					clusterStore,
+					eksadapter.NewNodePoolStore(db),
+					nodePoolValidator,
+					clusterPolicyValidator,
+					nodePoolProcessor,
+					validNodePoolLabelSource,
+					clusterapplyadapter.NewScaleOptionsStore(db),
+					integratedServicesService,
+					clusterapplyadapter.NewManager(workflowClient, config.Pipeline.Enterprise, getCurrentUserID),
+				)
+
+				endpoints := clusterapplydriver.MakeEndpoints(
+					service,
+					kitxendpoint.Combine(endpointMiddleware...),
+				)
+
+				clusterapplydriver.RegisterHTTPHandlers(
+					endpoints,
+					clusterRouter,
+					kitxhttp.ServerOptions(httpServerOptions),
+				)
+
+				cRouter.POST("/apply", gin.WrapH(router))
+			}
+
 			// ClusterGroupAPI
 			cgroupsAPI := cgroupAPI.NewAPI(clusterGroupManager, deploymentManager, logrusLogger, errorHandler)
 			cgroupsAPI.AddRoutes(orgs.Group("/:orgid/clustergroups"))
 
-			namespaceAPI := namespace.NewAPI(commonClusterGetter, clientFactory, errorHandler)
+			namespaceAPI := namespace.NewAPI(commonClusterGetter, clientFactory, clusterNamespaceService, errorHandler)
 			namespaceAPI.RegisterRoutes(cRouter.Group("/namespaces"))
 
+			execSessionService := clusterexec.NewService(
+				clusterexec.Config{
+					MaxSessionDuration: config.Cluster.Exec.MaxSessionDuration,
+					IdleTimeout:        config.Cluster.Exec.IdleTimeout,
+					RecordingEnabled:   config.Cluster.Exec.Recording.Enabled,
+					MaxRecordingSize:   config.Cluster.Exec.Recording.MaxSize,
+				},
+				clusterexecadapter.NewExecutorFactory(configFactory),
+				clusterexecadapter.NewGormStore(db),
+				commonLogger,
+			)
+
+			originChecker, err := common.NewOriginChecker(
+				config.CORS.AllowOrigins,
+				config.CORS.AllowOriginsRegexp,
+				config.Pipeline.External.URL,
+			)
+			emperror.Panic(errors.WrapIf(err, "failed to create origin checker"))
+
+			podAPI := pod.NewAPI(commonClusterGetter, clientFactory, execSessionService, organizationStore, originChecker, errorHandler)
+			podAPI.RegisterRoutes(cRouter.Group("/pods"))
+			podAPI.RegisterSessionRoutes(cRouter.Group("/exec-sessions"))
+
+			eventAPI := event.NewAPI(commonClusterGetter, clientFactory, errorHandler)
+			eventAPI.RegisterRoutes(cRouter.Group("/events"))
+
 			pkeGroup := cRouter.Group("/pke")
 
 			leaderRepository, err := pke.NewVaultLeaderRepository()
@@ -1155,6 +1426,25 @@ func main() {
 			orgs.Any("/:orgid/processes/*path", gin.WrapH(router))
 		}
 
+		{
+			err := clustertemplateapp.RegisterApp(
+				orgRouter,
+				clusterTemplateService,
+				commonLogger,
+				commonErrorHandler,
+			)
+			emperror.Panic(err)
+
+			orgs.GET("/:orgid/clustertemplates", gin.WrapH(router))
+			orgs.POST("/:orgid/clustertemplates", gin.WrapH(router))
+			orgs.GET("/:orgid/clustertemplates/:id", gin.WrapH(router))
+			orgs.PUT("/:orgid/clustertemplates/:id", gin.WrapH(router))
+			orgs.DELETE("/:orgid/clustertemplates/:id", gin.WrapH(router))
+			orgs.POST("/:orgid/clustertemplates/:id/render", gin.WrapH(router))
+			orgs.GET("/:orgid/clustertemplates/:id/clusters", gin.WrapH(router))
+			orgs.POST("/:orgid/clustertemplates/:id/clusters", clusterAPI.CreateClusterFromTemplate)
+		}
+
 		backups.AddRoutes(orgs.Group("/:orgid/clusters/:id/backups"))
 		backupservice.AddRoutes(orgs.Group("/:orgid/clusters/:id/backupservice"), unifiedHelmReleaser)
 		restores.AddRoutes(orgs.Group("/:orgid/clusters/:id/restores"))
@@ -1276,3 +1566,12 @@ func createInternalAPIRouter(
 	internalGroup.PUT("/:orgid/clusters/:id/nodepools", clusterAPI.UpdateNodePools)
 	return internalRouter
 }
+
+// getCurrentUserID returns the ID of the authenticated user from the request context.
+func getCurrentUserID(ctx context.Context) uint {
+	if currentUser := ctx.Value(auth2.CurrentUser); currentUser != nil {
+		return currentUser.(*auth.User).ID
+	}
+
+	return 0
+}
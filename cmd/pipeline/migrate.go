@@ -20,6 +20,11 @@ import (
 
 	"github.com/banzaicloud/pipeline/internal/app/frontend/notification/notificationadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost/clustercostadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec/clusterexecadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth/clusterhealthadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace/clusternamespaceadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy/clusterpolicyadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksmodel"
 	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
 	"github.com/banzaicloud/pipeline/internal/common"
@@ -28,9 +33,12 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/alibaba/alibabaadapter"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/azureadapter"
 	"github.com/banzaicloud/pipeline/internal/providers/kubernetes/kubernetesadapter"
+	"github.com/banzaicloud/pipeline/internal/security/securityadapter"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport/vulnreportadapter"
 	"github.com/banzaicloud/pipeline/src/model"
 
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/api/middleware/audit"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate/clustertemplateadapter"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/process/processadapter"
 	"github.com/banzaicloud/pipeline/internal/ark"
 	"github.com/banzaicloud/pipeline/internal/clustergroup"
@@ -105,9 +113,41 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger, commonLogger common.Logger)
 		return err
 	}
 
+	if err := vulnreportadapter.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := securityadapter.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := clustercostadapter.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := clusterhealthadapter.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := clusterpolicyadapter.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := clusternamespaceadapter.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := clusterexecadapter.Migrate(db, logger); err != nil {
+		return err
+	}
+
 	if err := processadapter.Migrate(db, commonLogger); err != nil {
 		return err
 	}
 
+	if err := clustertemplateadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -16,9 +16,12 @@ package main
 
 import (
 	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
 	"go.uber.org/cadence/activity"
 	"go.uber.org/cadence/workflow"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksprovider/adapter"
 	eksworkflow "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksprovider/workflow"
 	eksworkflow2 "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksworkflow"
@@ -26,7 +29,12 @@ import (
 	"github.com/banzaicloud/pipeline/src/cluster"
 )
 
-func registerEKSWorkflows(secretStore eksworkflow.SecretStore, clusterManager *adapter.ClusterManagerAdapter) error {
+func registerEKSWorkflows(
+	db *gorm.DB,
+	secretStore eksworkflow.SecretStore,
+	clusterManager *adapter.ClusterManagerAdapter,
+	clientFactory eksworkflow2.ClientFactory,
+) error {
 	vpcTemplate, err := eksworkflow.GetVPCTemplate()
 	if err != nil {
 		return errors.WrapIf(err, "failed to get CloudFormation template for VPC")
@@ -47,6 +55,11 @@ func registerEKSWorkflows(secretStore eksworkflow.SecretStore, clusterManager *a
 		return errors.WrapIf(err, "failed to get CloudFormation template for node pools")
 	}
 
+	nodePoolUpdateTemplate, err := eksworkflow.GetNodePoolUpdateTemplate()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get CloudFormation template for node pool updates")
+	}
+
 	workflow.RegisterWithOptions(cluster.EKSCreateClusterWorkflow, workflow.RegisterOptions{Name: cluster.EKSCreateClusterWorkflowName})
 	workflow.RegisterWithOptions(eksworkflow.CreateInfrastructureWorkflow, workflow.RegisterOptions{Name: eksworkflow.CreateInfraWorkflowName})
 
@@ -138,8 +151,37 @@ func registerEKSWorkflows(secretStore eksworkflow.SecretStore, clusterManager *a
 	eksworkflow2.NewUpdateNodePoolWorkflow(processlog.New()).Register()
 
 	eksworkflow2.NewCalculateNodePoolVersionActivity().Register()
-	eksworkflow2.NewUpdateNodeGroupActivity(awsSessionFactory, nodePoolTemplate).Register()
+	eksworkflow2.NewUpdateNodeGroupActivity(awsSessionFactory, nodePoolUpdateTemplate).Register()
 	eksworkflow2.NewWaitCloudFormationStackUpdateActivity(awsSessionFactory).Register()
+	eksworkflow2.NewListNodePoolNodesActivity(clientFactory).Register()
+	eksworkflow2.NewWaitNodePoolNodesReadyActivity(clientFactory).Register()
+	eksworkflow2.NewDetachNodeInstancesActivity(awsSessionFactory).Register()
+	eksworkflow2.NewDrainNodeActivity(clientFactory).Register()
+	eksworkflow2.NewTerminateNodeInstanceActivity(awsSessionFactory).Register()
+	eksworkflow2.NewAttachNodeInstancesActivity(awsSessionFactory).Register()
+	eksworkflow2.NewUncordonNodeActivity(clientFactory).Register()
+	eksworkflow2.NewSaveNodePoolActivity(eksadapter.NewNodePoolStore(db)).Register()
+
+	// Cluster upgrade
+	eksworkflow2.NewUpgradeClusterWorkflow(processlog.New()).Register()
+
+	eksworkflow2.NewUpdateClusterVersionActivity(awsSessionFactory).Register()
+	eksworkflow2.NewWaitClusterVersionUpdateActivity(awsSessionFactory).Register()
+	eksworkflow2.NewSaveClusterVersionActivity(eksadapter.NewClusterStore(db)).Register()
+	eksworkflow2.NewUpdateAddonsActivity(clientFactory).Register()
+	eksworkflow2.NewSaveNodePoolImageActivity(eksadapter.NewNodePoolStore(db)).Register()
+
+	// Cluster hibernation
+	eksworkflow2.NewHibernateClusterWorkflow(processlog.New()).Register()
+	eksworkflow2.NewResumeClusterWorkflow(processlog.New()).Register()
+
+	clusterStore := clusteradapter.NewStore(db, clusteradapter.NewClusters(db))
+	hibernationStore := clusteradapter.NewHibernationStore(db)
+
+	eksworkflow2.NewPrepareHibernateClusterActivity(clusterStore, eksadapter.NewNodePoolStore(db), hibernationStore).Register()
+	eksworkflow2.NewPrepareResumeClusterActivity(clusterStore, hibernationStore).Register()
+	eksworkflow2.NewScaleNodeGroupActivity(awsSessionFactory).Register()
+	eksworkflow2.NewDeleteHibernatedNodePoolsActivity(hibernationStore).Register()
 
 	return nil
 }
@@ -25,6 +25,7 @@ import (
 	"emperror.dev/emperror"
 	"emperror.dev/errors"
 	"emperror.dev/errors/match"
+	evbus "github.com/asaskevich/EventBus"
 	bauth "github.com/banzaicloud/bank-vaults/pkg/sdk/auth"
 	"github.com/banzaicloud/bank-vaults/pkg/sdk/vault"
 	"github.com/mitchellh/mapstructure"
@@ -46,6 +47,17 @@ import (
 	cluster2 "github.com/banzaicloud/pipeline/internal/cluster"
 	intClusterAuth "github.com/banzaicloud/pipeline/internal/cluster/auth"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply/clusterapplyadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply/clusterapplyworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost/clustercostadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost/clustercostworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth/clusterhealthadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth/clusterhealthworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace/clusternamespaceadapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace/clusternamespaceworkflow"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret/clustersecretadapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersetup"
@@ -54,6 +66,8 @@ import (
 	eksClusterAdapter "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksprovider/adapter"
 	eksClusterDriver "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksprovider/driver"
 	eksworkflow "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksprovider/workflow"
+	eksDistributionWorkflow "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksworkflow"
+	pkeDistributionWorkflow "github.com/banzaicloud/pipeline/internal/cluster/distribution/pke/pkeworkflow"
 	intClusterDNS "github.com/banzaicloud/pipeline/internal/cluster/dns"
 	"github.com/banzaicloud/pipeline/internal/cluster/endpoints"
 	intClusterK8s "github.com/banzaicloud/pipeline/internal/cluster/kubernetes"
@@ -78,6 +92,8 @@ import (
 	intsvcingressadapter "github.com/banzaicloud/pipeline/internal/integratedservices/services/ingress/ingressadapter"
 	integratedServiceLogging "github.com/banzaicloud/pipeline/internal/integratedservices/services/logging"
 	integratedServiceMonitoring "github.com/banzaicloud/pipeline/internal/integratedservices/services/monitoring"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services/secretsync"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services/secretsync/secretsyncadapter"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/services/securityscan"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/services/securityscan/securityscanadapter"
 	integratedServiceVault "github.com/banzaicloud/pipeline/internal/integratedservices/services/vault"
@@ -103,10 +119,16 @@ import (
 	"github.com/banzaicloud/pipeline/internal/secret/secretadapter"
 	"github.com/banzaicloud/pipeline/internal/secret/types"
 	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/internal/security/securityadapter"
+	"github.com/banzaicloud/pipeline/internal/security/securityworkflow"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport/vulnreportadapter"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport/vulnreportworkflow"
 	pkgAuth "github.com/banzaicloud/pipeline/pkg/auth"
 	"github.com/banzaicloud/pipeline/pkg/cloudinfo"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	"github.com/banzaicloud/pipeline/pkg/hook"
+	"github.com/banzaicloud/pipeline/pkg/sdk/cadence/lib/pipeline/processlog"
 	"github.com/banzaicloud/pipeline/src/auth"
 	"github.com/banzaicloud/pipeline/src/auth/authdriver"
 	"github.com/banzaicloud/pipeline/src/cluster"
@@ -392,7 +414,7 @@ func main() {
 		registerAzureWorkflows(secretStore, tokenGenerator, azurePKEClusterStore)
 
 		// Register EKS specific workflows
-		err = registerEKSWorkflows(secret.Store, eksClusters)
+		err = registerEKSWorkflows(db, secret.Store, eksClusters, kubernetes.NewClientFactory(configFactory))
 		if err != nil {
 			emperror.Panic(errors.WrapIf(err, "failed to register EKS workflows"))
 		}
@@ -401,7 +423,7 @@ func main() {
 		vsphereClusterStore := vsphereadapter.NewClusterStore(db)
 
 		cgroupAdapter := cgroupAdapter.NewClusterGetter(clusterManager)
-		clusterGroupManager := clustergroup.NewManager(cgroupAdapter, clustergroup.NewClusterGroupRepository(db, logrusLogger), logrusLogger, errorHandler)
+		clusterGroupManager := clustergroup.NewManager(cgroupAdapter, clusterStore, clustergroup.NewClusterGroupRepository(db, logrusLogger), logrusLogger, errorHandler)
 		{
 			workflow.RegisterWithOptions(clusterworkflow.DeleteClusterWorkflow, workflow.RegisterOptions{Name: clusterworkflow.DeleteClusterWorkflowName})
 
@@ -412,9 +434,24 @@ func main() {
 			clusterGroupManager.RegisterFeatureHandler(deployment.FeatureName, deploymentManager)
 			clusterGroupManager.RegisterFeatureHandler(cgFeatureIstio.FeatureName, serviceMeshFeatureHandler)
 
+			checkClusterDeletionProtectionActivity := clusterworkflow.MakeCheckClusterDeletionProtectionActivity(clusterStore)
+			activity.RegisterWithOptions(checkClusterDeletionProtectionActivity.Execute, activity.RegisterOptions{Name: clusterworkflow.CheckClusterDeletionProtectionActivityName})
+
 			removeClusterFromGroupActivity := clusterworkflow.MakeRemoveClusterFromGroupActivity(clusterGroupManager)
 			activity.RegisterWithOptions(removeClusterFromGroupActivity.Execute, activity.RegisterOptions{Name: clusterworkflow.RemoveClusterFromGroupActivityName})
 
+			reconcileClusterGroupMembersActivity := clusterworkflow.MakeReconcileClusterGroupMembersActivity(clusterGroupManager)
+			activity.RegisterWithOptions(reconcileClusterGroupMembersActivity.Execute, activity.RegisterOptions{Name: clusterworkflow.ReconcileClusterGroupMembersActivityName})
+
+			cancelHibernationSchedulesActivity := clusterworkflow.MakeCancelHibernationSchedulesActivity(
+				workflowClient,
+				eksDistributionWorkflow.HibernateClusterWorkflowName,
+				eksDistributionWorkflow.ResumeClusterWorkflowName,
+				pkeDistributionWorkflow.HibernateClusterWorkflowName,
+				pkeDistributionWorkflow.ResumeClusterWorkflowName,
+			)
+			activity.RegisterWithOptions(cancelHibernationSchedulesActivity.Execute, activity.RegisterOptions{Name: clusterworkflow.CancelHibernationSchedulesActivityName})
+
 			commonClusterDeleter := legacyclusteradapter.NewCommonClusterDeleterAdapter(
 				clusterManager,
 				clusterManager,
@@ -524,12 +561,22 @@ func main() {
 			activity.RegisterWithOptions(setClusterStatusActivity.Execute, activity.RegisterOptions{Name: clusterworkflow.SetClusterStatusActivityName})
 		}
 
+		{
+			clusterapplyworkflow.NewApplyClusterWorkflow(processlog.New()).Register()
+
+			setScaleOptionsActivity := clusterapplyworkflow.NewSetScaleOptionsActivity(clusterapplyadapter.NewScaleOptionsStore(db))
+			activity.RegisterWithOptions(setScaleOptionsActivity.Execute, activity.RegisterOptions{Name: clusterapplyworkflow.SetScaleOptionsActivityName})
+		}
+
 		k8sConfigGetter := kubesecret.MakeKubeSecretStore(secret.Store)
 
 		// Register vsphere specific workflows
 
 		registerVsphereWorkflows(secretStore, tokenGenerator, vsphereClusterStore, k8sConfigGetter)
 
+		// Register PKE hibernation workflows (all cloud providers)
+		registerPKEHibernationWorkflows(db, secretStore, azurePKEClusterStore, vsphereClusterStore)
+
 		generateCertificatesActivity := pkeworkflow.NewGenerateCertificatesActivity(clusterSecretStore)
 		activity.RegisterWithOptions(generateCertificatesActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.GenerateCertificatesActivityName})
 
@@ -622,6 +669,153 @@ func main() {
 			featureAnchoreService := securityscan.NewIntegratedServiceAnchoreService(anchoreUserService, logger)
 			featureWhitelistService := securityscan.NewIntegratedServiceWhitelistService(clusterGetter, anchore.NewSecurityResourceService(logger), logger)
 
+			var trivyScanRunner anchore.ImageScanner
+			if config.Cluster.SecurityScan.Enabled && config.Cluster.SecurityScan.Trivy.Enabled {
+				trivyScanRunner = anchore.NewTrivyScanRunner(anchore.TrivyScannerConfig{
+					Namespace: config.Cluster.SecurityScan.Webhook.Namespace,
+					ServerURL: config.Cluster.SecurityScan.Trivy.ServerURL(config.Cluster.SecurityScan.Webhook.Namespace),
+					Image:     config.Cluster.SecurityScan.Trivy.Image,
+					Timeout:   config.Cluster.SecurityScan.Trivy.ScanTimeout,
+				}, securityadapter.NewTrivyScanStore(db), logger)
+
+				scanImageActivity := securityworkflow.NewScanImageActivity(clusterGetter, trivyScanRunner)
+
+				workflow.RegisterWithOptions(securityworkflow.ScanImageWorkflow, workflow.RegisterOptions{Name: securityworkflow.ScanImageWorkflowName})
+				activity.RegisterWithOptions(scanImageActivity.Execute, activity.RegisterOptions{Name: securityworkflow.ScanImageActivityName})
+			}
+
+			if config.Cluster.SecurityScan.Enabled && config.Cluster.SecurityScan.Rescan.Enabled {
+				scanConfigProvider := anchore2.ConfigProviderChain{customAnchoreConfigProvider}
+
+				if config.Cluster.SecurityScan.Anchore.Enabled {
+					scanConfigProvider = append(scanConfigProvider, securityscan.NewClusterAnchoreConfigProvider(
+						config.Cluster.SecurityScan.Anchore.Endpoint,
+						securityscanadapter.NewUserNameGenerator(securityscanadapter.NewClusterService(clusterManager)),
+						securityscanadapter.NewUserSecretStore(commonSecretStore),
+						config.Cluster.SecurityScan.Anchore.Insecure,
+					))
+				}
+
+				imageScanners := map[string]anchore.ImageScanner{
+					anchore.ScannerAnchore: anchore.NewAnchoreImageScanner(scanConfigProvider, logger),
+				}
+
+				// rescans refresh the cached results of Trivy
+				if trivyScanRunner != nil {
+					imageScanners[anchore.ScannerTrivy] = trivyScanRunner
+				}
+
+				vulnReportStore := vulnreportadapter.NewGormStore(db)
+				rescanActivities := vulnreportworkflow.NewRescanActivities(
+					vulnreportadapter.NewClusterFinder(db, securityscan.IntegratedServiceName),
+					vulnreport.NewClusterScanner(
+						vulnreportadapter.NewImageLister(clusterGetter),
+						vulnreportadapter.NewImageScanner(
+							clusterGetter,
+							anchore.NewImageScannerSelector(securityscan.NewScannerProvider(featureRepository), imageScanners),
+						),
+						vulnReportStore,
+						logger,
+					),
+					vulnReportStore,
+				)
+
+				workflow.RegisterWithOptions(vulnreportworkflow.RescanWorkflow, workflow.RegisterOptions{Name: vulnreportworkflow.RescanWorkflowName})
+				activity.RegisterWithOptions(rescanActivities.ListOrganizations, activity.RegisterOptions{Name: vulnreportworkflow.ListOrganizationsActivityName})
+				activity.RegisterWithOptions(rescanActivities.ListClusters, activity.RegisterOptions{Name: vulnreportworkflow.ListClustersActivityName})
+				activity.RegisterWithOptions(rescanActivities.CreateSnapshot, activity.RegisterOptions{Name: vulnreportworkflow.CreateSnapshotActivityName})
+				activity.RegisterWithOptions(rescanActivities.ScanCluster, activity.RegisterOptions{Name: vulnreportworkflow.ScanClusterActivityName})
+				activity.RegisterWithOptions(rescanActivities.MarkClusterUnscanned, activity.RegisterOptions{Name: vulnreportworkflow.MarkClusterUnscannedActivityName})
+				activity.RegisterWithOptions(rescanActivities.CompleteSnapshot, activity.RegisterOptions{Name: vulnreportworkflow.CompleteSnapshotActivityName})
+
+				err := vulnreportworkflow.StartRescanWorkflow(
+					context.Background(),
+					workflowClient,
+					config.Cluster.SecurityScan.Rescan.Schedule,
+					config.Cluster.SecurityScan.Rescan.Timeout,
+					vulnreportworkflow.RescanWorkflowInput{
+						KeepSnapshots:    config.Cluster.SecurityScan.Rescan.KeepSnapshots,
+						ImageScanTimeout: config.Cluster.SecurityScan.Trivy.ScanTimeout,
+					},
+				)
+				if err != nil {
+					errorHandler.Handle(err)
+				}
+			}
+
+			if config.Cluster.Cost.Ledger.Enabled {
+				collectUsageActivities := clustercostworkflow.NewCollectUsageActivities(
+					clustercostadapter.NewClusterFinder(db),
+					clustercost.NewUsageCollector(
+						clusterStore,
+						clustercostadapter.NewNodeLister(clusterGetter),
+						clustercost.NewEstimator(cloudinfoClient),
+						clustercostadapter.NewGormLedgerStore(db),
+					),
+				)
+
+				workflow.RegisterWithOptions(clustercostworkflow.CollectUsageWorkflow, workflow.RegisterOptions{Name: clustercostworkflow.CollectUsageWorkflowName})
+				activity.RegisterWithOptions(collectUsageActivities.ListClusters, activity.RegisterOptions{Name: clustercostworkflow.ListClustersActivityName})
+				activity.RegisterWithOptions(collectUsageActivities.CollectClusterUsage, activity.RegisterOptions{Name: clustercostworkflow.CollectClusterUsageActivityName})
+
+				err := clustercostworkflow.StartCollectUsageWorkflow(
+					context.Background(),
+					workflowClient,
+					config.Cluster.Cost.Ledger.Schedule,
+					config.Cluster.Cost.Ledger.Timeout,
+				)
+				if err != nil {
+					errorHandler.Handle(err)
+				}
+			}
+
+			if config.Cluster.Health.Enabled {
+				// subscribers of the health transitions are registered on this bus
+				healthEventBus := evbus.New()
+
+				checkHealthActivities := clusterhealthworkflow.NewCheckActivities(
+					clusterhealthadapter.NewClusterFinder(db),
+					clusterhealth.NewChecker(
+						clusterhealth.NewKubernetesProber(
+							kubernetes.NewService(kubernetesadapter.NewConfigSecretGetter(clusteradapter.NewClusters(db)), configFactory, commonLogger),
+							clusterhealth.ProberConfig{
+								Timeout:                  config.Cluster.Health.Timeout,
+								PipelineNamespace:        config.Cluster.Namespace,
+								CertificateExpiryWarning: config.Cluster.Health.CertificateExpiryWarning,
+							},
+						),
+						clusterhealthadapter.NewGormStore(db),
+						clusterhealth.NewEventBusEvents(healthEventBus),
+						commonLogger.WithFields(map[string]interface{}{"subsystem": "cluster-health-checker"}),
+					),
+				)
+
+				workflow.RegisterWithOptions(clusterhealthworkflow.CheckWorkflow, workflow.RegisterOptions{Name: clusterhealthworkflow.CheckWorkflowName})
+				activity.RegisterWithOptions(checkHealthActivities.ListClusters, activity.RegisterOptions{Name: clusterhealthworkflow.ListClustersActivityName})
+				activity.RegisterWithOptions(checkHealthActivities.CheckCluster, activity.RegisterOptions{Name: clusterhealthworkflow.CheckClusterActivityName})
+				activity.RegisterWithOptions(checkHealthActivities.RemoveStaleHealth, activity.RegisterOptions{Name: clusterhealthworkflow.RemoveStaleHealthActivityName})
+
+				err := clusterhealthworkflow.StartCheckWorkflow(context.Background(), workflowClient, config.Cluster.Health.Schedule)
+				if err != nil {
+					errorHandler.Handle(err)
+				}
+			}
+
+			{
+				syncRoleBindingsActivities := clusternamespaceworkflow.NewSyncRoleBindingsActivities(
+					clusternamespaceadapter.NewClusterFinder(db),
+					kubernetes.NewService(kubernetesadapter.NewConfigSecretGetter(clusteradapter.NewClusters(db)), configFactory, commonLogger),
+					clusternamespace.NewService(
+						clusternamespaceadapter.NewGormTemplateStore(db),
+						clusternamespaceadapter.NewMemberLister(db),
+					),
+				)
+
+				workflow.RegisterWithOptions(clusternamespaceworkflow.SyncRoleBindingsWorkflow, workflow.RegisterOptions{Name: clusternamespaceworkflow.SyncRoleBindingsWorkflowName})
+				activity.RegisterWithOptions(syncRoleBindingsActivities.ListClusters, activity.RegisterOptions{Name: clusternamespaceworkflow.ListClustersActivityName})
+				activity.RegisterWithOptions(syncRoleBindingsActivities.SyncCluster, activity.RegisterOptions{Name: clusternamespaceworkflow.SyncClusterActivityName})
+			}
+
 			// expiry integrated service
 			workflow.RegisterWithOptions(expiryWorkflow.ExpiryJobWorkflow, workflow.RegisterOptions{Name: expiryWorkflow.ExpiryJobWorkflowName})
 
@@ -680,6 +874,13 @@ func main() {
 					commonSecretStore,
 				),
 				expiry.NewExpiryServiceOperator(expirerService, services.BindIntegratedServiceSpec, logger),
+				secretsync.MakeIntegratedServiceOperator(
+					clusterGetter,
+					clusterService,
+					secretsyncadapter.NewSecretStore(secret.Store),
+					kubernetesService,
+					logger,
+				),
 				intsvcingress.NewOperator(
 					intsvcingressadapter.NewOperatorClusterStore(clusterStore),
 					clusterService,
@@ -690,6 +891,74 @@ func main() {
 			})
 
 			registerClusterFeatureWorkflows(featureOperatorRegistry, featureRepository)
+
+			// integrated service operations of cluster apply processes
+			{
+				clusterPropertyGetter := dnsadapter.NewClusterPropertyGetter(clusterManager)
+				integratedServiceManagers := []integratedservices.IntegratedServiceManager{
+					securityscan.MakeIntegratedServiceManager(logger, config.Cluster.SecurityScan.Config),
+				}
+
+				if config.Cluster.DNS.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, integratedServiceDNS.NewIntegratedServicesManager(clusterPropertyGetter, clusterPropertyGetter, config.Cluster.DNS.Config))
+				}
+
+				if config.Cluster.Vault.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, integratedServiceVault.MakeIntegratedServiceManager(clusterGetter, commonSecretStore, config.Cluster.Vault.Config, logger))
+				}
+
+				if config.Cluster.Monitoring.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, integratedServiceMonitoring.MakeIntegratedServiceManager(
+						clusterGetter,
+						commonSecretStore,
+						endpointManager,
+						unifiedHelmReleaser,
+						config.Cluster.Monitoring.Config,
+						logger,
+					))
+				}
+
+				if config.Cluster.Logging.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, integratedServiceLogging.MakeIntegratedServiceManager(
+						clusterGetter,
+						commonSecretStore,
+						endpointManager,
+						config.Cluster.Logging.Config,
+						logger,
+					))
+				}
+
+				if config.Cluster.Expiry.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, expiry.NewExpiryServiceManager(services.BindIntegratedServiceSpec))
+				}
+
+				if config.Cluster.Ingress.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, intsvcingress.NewManager(
+						config.Cluster.Ingress.Config,
+						unifiedHelmReleaser,
+						logger,
+					))
+				}
+
+				if config.Cluster.SecretSync.Enabled {
+					integratedServiceManagers = append(integratedServiceManagers, secretsync.MakeIntegratedServiceManager(
+						clusterGetter,
+						secretsyncadapter.NewSecretStore(secret.Store),
+						kubernetesService,
+						logger,
+					))
+				}
+
+				integratedServicesService := integratedservices.MakeIntegratedServiceService(
+					integratedserviceadapter.MakeCadenceIntegratedServiceOperationDispatcher(workflowClient, logger),
+					integratedservices.MakeIntegratedServiceManagerRegistry(integratedServiceManagers),
+					featureRepository,
+					logger,
+				)
+
+				applyIntegratedServiceActivity := clusterapplyworkflow.NewApplyIntegratedServiceActivity(integratedServicesService)
+				activity.RegisterWithOptions(applyIntegratedServiceActivity.Execute, activity.RegisterOptions{Name: clusterapplyworkflow.ApplyIntegratedServiceActivityName})
+			}
 		}
 
 		group.Add(appkitrun.CadenceWorkerRun(worker))
@@ -15,9 +15,19 @@
 package main
 
 import (
+	"github.com/jinzhu/gorm"
 	"go.uber.org/cadence/activity"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke/pkeadapter"
+	pkeDistributionWorkflow "github.com/banzaicloud/pipeline/internal/cluster/distribution/pke/pkeworkflow"
 	pkeworkflow "github.com/banzaicloud/pipeline/internal/pke/workflow"
+	azurePKE "github.com/banzaicloud/pipeline/internal/providers/azure/pke"
+	azurePKEWorkflow "github.com/banzaicloud/pipeline/internal/providers/azure/pke/workflow"
+	providerPKEWorkflow "github.com/banzaicloud/pipeline/internal/providers/pke/pkeworkflow"
+	vspherePKE "github.com/banzaicloud/pipeline/internal/providers/vsphere/pke"
+	vspherePKEWorkflow "github.com/banzaicloud/pipeline/internal/providers/vsphere/pke/workflow"
+	"github.com/banzaicloud/pipeline/pkg/sdk/cadence/lib/pipeline/processlog"
 )
 
 func registerPKEWorkflows(passwordSecrets pkeworkflow.PasswordSecretStore) {
@@ -26,3 +36,31 @@ func registerPKEWorkflows(passwordSecrets pkeworkflow.PasswordSecretStore) {
 		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: pkeworkflow.AssembleHTTPProxySettingsActivityName})
 	}
 }
+
+func registerPKEHibernationWorkflows(
+	db *gorm.DB,
+	secretStore providerPKEWorkflow.SecretStore,
+	azureClusters azurePKE.ClusterStore,
+	vsphereClusters vspherePKE.ClusterStore,
+) {
+	pkeDistributionWorkflow.NewHibernateClusterWorkflow(processlog.New()).Register()
+	pkeDistributionWorkflow.NewResumeClusterWorkflow(processlog.New()).Register()
+
+	clusterStore := clusteradapter.NewStore(db, clusteradapter.NewClusters(db))
+	hibernationStore := clusteradapter.NewHibernationStore(db)
+
+	nodePoolStore := pkeadapter.NewNodePoolStore(db, clusterStore, azureClusters, vsphereClusters)
+	nodePoolScaler := pkeadapter.NewNodePoolScaler(
+		clusterStore,
+		providerPKEWorkflow.NewAWSClientFactory(secretStore),
+		azureClusters,
+		azurePKEWorkflow.NewAzureClientFactory(secretStore),
+		vsphereClusters,
+		vspherePKEWorkflow.NewVMOMIClientFactory(secretStore),
+	)
+
+	pkeDistributionWorkflow.NewPrepareHibernateClusterActivity(clusterStore, nodePoolStore, hibernationStore).Register()
+	pkeDistributionWorkflow.NewPrepareResumeClusterActivity(clusterStore, hibernationStore).Register()
+	pkeDistributionWorkflow.NewScaleNodePoolActivity(nodePoolScaler).Register()
+	pkeDistributionWorkflow.NewDeleteHibernatedNodePoolsActivity(hibernationStore).Register()
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `security_vulnerability_findings`;
+DROP TABLE IF EXISTS `security_vulnerability_snapshots`;
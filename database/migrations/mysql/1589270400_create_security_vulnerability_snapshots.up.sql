@@ -0,0 +1,27 @@
+CREATE TABLE `security_vulnerability_snapshots` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `organization_id` int(10) unsigned DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `completed` tinyint(1) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_security_vulnerability_snapshots_org_id` (`organization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+CREATE TABLE `security_vulnerability_findings` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `snapshot_id` int(10) unsigned DEFAULT NULL,
+  `cluster_id` int(10) unsigned DEFAULT NULL,
+  `cluster_name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `namespace` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `release` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `image` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `image_digest` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `vulnerability_id` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `severity` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `package` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `fixed_version` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_security_vulnerability_findings_snapshot_id` (`snapshot_id`),
+  CONSTRAINT `security_vulnerability_findings_snapshot_id_security_vulnerability_snapshots_id_foreign` FOREIGN KEY (`snapshot_id`) REFERENCES `security_vulnerability_snapshots` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
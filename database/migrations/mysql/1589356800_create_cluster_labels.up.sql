@@ -0,0 +1,9 @@
+CREATE TABLE `cluster_labels` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned DEFAULT NULL,
+  `key` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `value` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_labels_cluster_id_key` (`cluster_id`,`key`),
+  CONSTRAINT `cluster_labels_cluster_id_clusters_id_foreign` FOREIGN KEY (`cluster_id`) REFERENCES `clusters` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `cluster_hibernation_schedules`;
+DROP TABLE IF EXISTS `cluster_hibernated_node_pools`;
@@ -0,0 +1,23 @@
+CREATE TABLE `cluster_hibernated_node_pools` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned DEFAULT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `size` int(11) DEFAULT NULL,
+  `autoscaling` tinyint(1) DEFAULT NULL,
+  `min_size` int(11) DEFAULT NULL,
+  `max_size` int(11) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_hibernated_node_pools_cluster_id_name` (`cluster_id`,`name`),
+  CONSTRAINT `cluster_hibernated_node_pools_cluster_id_clusters_id_foreign` FOREIGN KEY (`cluster_id`) REFERENCES `clusters` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `cluster_hibernation_schedules` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned DEFAULT NULL,
+  `hibernate` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `resume` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `keep_node_pool` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_hibernation_schedules_cluster_id` (`cluster_id`),
+  CONSTRAINT `cluster_hibernation_schedules_cluster_id_clusters_id_foreign` FOREIGN KEY (`cluster_id`) REFERENCES `clusters` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,21 @@
+CREATE TABLE `cluster_cost_usage_records` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `organization_id` int(10) unsigned DEFAULT NULL,
+  `cluster_id` int(10) unsigned DEFAULT NULL,
+  `cluster_name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `cluster_labels` text COLLATE utf8mb4_unicode_ci,
+  `cloud` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `distribution` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `region` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `resource` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `node_pool` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `instance_type` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `lifecycle` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `count` int(11) DEFAULT NULL,
+  `hourly_price` double DEFAULT NULL,
+  `period_start` timestamp NULL DEFAULT NULL,
+  `period_end` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_cluster_cost_usage_records_org_id_period` (`organization_id`,`period_start`),
+  KEY `idx_cluster_cost_usage_records_cluster_id_period` (`cluster_id`,`period_start`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
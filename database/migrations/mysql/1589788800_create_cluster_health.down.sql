@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `cluster_health`;
@@ -0,0 +1,2 @@
+ALTER TABLE `cluster_status_history` ADD COLUMN `user_id` int(10) unsigned DEFAULT NULL;
+ALTER TABLE `cluster_status_history` ADD COLUMN `process_id` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL;
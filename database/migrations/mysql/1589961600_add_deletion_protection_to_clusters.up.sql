@@ -0,0 +1 @@
+ALTER TABLE `clusters` ADD COLUMN `deletion_protection` tinyint(1) DEFAULT 0 NOT NULL;
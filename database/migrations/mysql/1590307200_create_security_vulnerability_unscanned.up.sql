@@ -0,0 +1,12 @@
+CREATE TABLE `security_vulnerability_unscanned` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `snapshot_id` int(10) unsigned DEFAULT NULL,
+  `cluster_id` int(10) unsigned DEFAULT NULL,
+  `cluster_name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `image` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `image_digest` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `reason` text COLLATE utf8mb4_unicode_ci,
+  PRIMARY KEY (`id`),
+  KEY `idx_security_vulnerability_unscanned_snapshot_id` (`snapshot_id`),
+  CONSTRAINT `security_vulnerability_unscanned_snapshot_id_security_vulnerability_snapshots_id_foreign` FOREIGN KEY (`snapshot_id`) REFERENCES `security_vulnerability_snapshots` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+ALTER TABLE `amazon_node_pools` ADD COLUMN `taints` text COLLATE utf8mb4_unicode_ci DEFAULT NULL;
+ALTER TABLE `azure_aks_node_pools` ADD COLUMN `taints` text COLLATE utf8mb4_unicode_ci DEFAULT NULL;
+ALTER TABLE `google_gke_node_pools` ADD COLUMN `taints` text COLLATE utf8mb4_unicode_ci DEFAULT NULL;
+ALTER TABLE `topology_nodepools` ADD COLUMN `taints` text COLLATE utf8mb4_unicode_ci DEFAULT NULL;
+ALTER TABLE `azure_pke_node_pools` ADD COLUMN `taints` text COLLATE utf8mb4_unicode_ci DEFAULT NULL;
+ALTER TABLE `vsphere_pke_node_pools` ADD COLUMN `taints` text COLLATE utf8mb4_unicode_ci DEFAULT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE `amazon_node_pools` DROP COLUMN `node_volume_size`;
+ALTER TABLE `amazon_node_pools` DROP COLUMN `node_volume_type`;
+ALTER TABLE `amazon_node_pools` DROP COLUMN `node_security_groups`;
@@ -0,0 +1,3 @@
+ALTER TABLE `amazon_node_pools` ADD COLUMN `node_volume_size` int(11) DEFAULT NULL;
+ALTER TABLE `amazon_node_pools` ADD COLUMN `node_volume_type` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL;
+ALTER TABLE `amazon_node_pools` ADD COLUMN `node_security_groups` text COLLATE utf8mb4_unicode_ci DEFAULT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE `cluster_template_versions` (
+  `template_id` int(10) unsigned NOT NULL,
+  `version` int(11) NOT NULL,
+  `parameters` json DEFAULT NULL,
+  `template` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`template_id`,`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO `cluster_template_versions` (`template_id`, `version`, `parameters`, `template`, `created_at`)
+SELECT `id`, `version`, `parameters`, `template`, `updated_at` FROM `cluster_templates`;
@@ -0,0 +1,27 @@
+CREATE TABLE "security_vulnerability_snapshots" (
+  "id" serial,
+  "organization_id" integer,
+  "created_at" timestamp with time zone,
+  "completed" boolean,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_security_vulnerability_snapshots_org_id ON "security_vulnerability_snapshots"(organization_id);
+
+CREATE TABLE "security_vulnerability_findings" (
+  "id" serial,
+  "snapshot_id" integer REFERENCES security_vulnerability_snapshots(id),
+  "cluster_id" integer,
+  "cluster_name" text,
+  "namespace" text,
+  "release" text,
+  "image" text,
+  "image_digest" text,
+  "vulnerability_id" text,
+  "severity" text,
+  "package" text,
+  "fixed_version" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_security_vulnerability_findings_snapshot_id ON "security_vulnerability_findings"(snapshot_id);
@@ -0,0 +1,9 @@
+CREATE TABLE "cluster_labels" (
+  "id" serial,
+  "cluster_id" integer REFERENCES clusters(id),
+  "key" text,
+  "value" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_labels_cluster_id_key ON "cluster_labels"(cluster_id, "key");
@@ -0,0 +1 @@
+ALTER TABLE "clustergroups" ADD COLUMN "member_selector" json;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "cluster_template_clusters";
+DROP TABLE IF EXISTS "cluster_templates";
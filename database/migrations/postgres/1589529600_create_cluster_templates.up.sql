@@ -0,0 +1,24 @@
+CREATE TABLE "cluster_templates" (
+  "id" serial,
+  "organization_id" integer NOT NULL,
+  "name" text NOT NULL,
+  "description" text,
+  "version" integer NOT NULL,
+  "parameters" json,
+  "template" text NOT NULL,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_templates_org_id_name ON "cluster_templates"(organization_id, "name");
+
+CREATE TABLE "cluster_template_clusters" (
+  "cluster_id" integer NOT NULL,
+  "template_id" integer NOT NULL,
+  "template_version" integer NOT NULL,
+  "created_at" timestamp with time zone,
+  PRIMARY KEY ("cluster_id")
+);
+
+CREATE INDEX idx_cluster_template_clusters_template_id ON "cluster_template_clusters"(template_id);
@@ -0,0 +1,23 @@
+CREATE TABLE "cluster_hibernated_node_pools" (
+  "id" serial,
+  "cluster_id" integer REFERENCES clusters(id),
+  "name" text,
+  "size" integer,
+  "autoscaling" boolean,
+  "min_size" integer,
+  "max_size" integer,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_hibernated_node_pools_cluster_id_name ON "cluster_hibernated_node_pools"(cluster_id, "name");
+
+CREATE TABLE "cluster_hibernation_schedules" (
+  "id" serial,
+  "cluster_id" integer REFERENCES clusters(id),
+  "hibernate" text,
+  "resume" text,
+  "keep_node_pool" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_hibernation_schedules_cluster_id ON "cluster_hibernation_schedules"(cluster_id);
@@ -0,0 +1,22 @@
+CREATE TABLE "cluster_cost_usage_records" (
+  "id" serial,
+  "organization_id" integer,
+  "cluster_id" integer,
+  "cluster_name" text,
+  "cluster_labels" text,
+  "cloud" text,
+  "distribution" text,
+  "region" text,
+  "resource" text,
+  "node_pool" text,
+  "instance_type" text,
+  "lifecycle" text,
+  "count" integer,
+  "hourly_price" numeric,
+  "period_start" timestamp with time zone,
+  "period_end" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_cluster_cost_usage_records_org_id_period ON "cluster_cost_usage_records"(organization_id, period_start);
+CREATE INDEX idx_cluster_cost_usage_records_cluster_id_period ON "cluster_cost_usage_records"(cluster_id, period_start);
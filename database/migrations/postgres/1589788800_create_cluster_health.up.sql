@@ -0,0 +1,13 @@
+CREATE TABLE "cluster_health" (
+  "id" serial,
+  "cluster_id" integer,
+  "organization_id" integer,
+  "cluster_name" text,
+  "status" text,
+  "checks" text,
+  "checked_at" timestamp with time zone,
+  "transitioned_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_health_cluster_id ON "cluster_health"(cluster_id);
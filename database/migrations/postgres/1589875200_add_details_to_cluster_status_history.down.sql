@@ -0,0 +1,2 @@
+ALTER TABLE "cluster_status_history" DROP COLUMN "process_id";
+ALTER TABLE "cluster_status_history" DROP COLUMN "user_id";
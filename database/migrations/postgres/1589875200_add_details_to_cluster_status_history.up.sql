@@ -0,0 +1,2 @@
+ALTER TABLE "cluster_status_history" ADD COLUMN "user_id" integer;
+ALTER TABLE "cluster_status_history" ADD COLUMN "process_id" text;
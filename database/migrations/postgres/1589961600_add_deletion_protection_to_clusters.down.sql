@@ -0,0 +1 @@
+ALTER TABLE "clusters" DROP COLUMN "deletion_protection";
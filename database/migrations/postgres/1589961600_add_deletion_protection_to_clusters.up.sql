@@ -0,0 +1 @@
+ALTER TABLE "clusters" ADD COLUMN "deletion_protection" boolean DEFAULT false NOT NULL;
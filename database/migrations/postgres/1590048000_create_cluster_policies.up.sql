@@ -0,0 +1,8 @@
+CREATE TABLE "cluster_policies" (
+  "id" serial,
+  "organization_id" integer,
+  "policy" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_policies_organization_id ON "cluster_policies"(organization_id);
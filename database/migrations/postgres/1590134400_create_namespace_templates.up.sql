@@ -0,0 +1,10 @@
+CREATE TABLE "namespace_templates" (
+  "id" serial,
+  "organization_id" integer,
+  "name" text,
+  "description" text,
+  "spec" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_namespace_templates_organization_id_name ON "namespace_templates"(organization_id, name);
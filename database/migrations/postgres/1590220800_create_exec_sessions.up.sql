@@ -0,0 +1,22 @@
+CREATE TABLE "exec_sessions" (
+  "id" serial,
+  "organization_id" integer,
+  "cluster_id" integer,
+  "user_id" integer,
+  "user_login" text,
+  "mode" text,
+  "namespace" text,
+  "pod" text,
+  "container" text,
+  "command" text,
+  "tty" boolean,
+  "started_at" timestamp with time zone,
+  "ended_at" timestamp with time zone,
+  "end_reason" text,
+  "error" text,
+  "recorded" boolean,
+  "recording" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_exec_sessions_organization_id_cluster_id ON "exec_sessions"(organization_id, cluster_id);
@@ -0,0 +1,12 @@
+CREATE TABLE "security_vulnerability_unscanned" (
+  "id" serial,
+  "snapshot_id" integer REFERENCES security_vulnerability_snapshots(id),
+  "cluster_id" integer,
+  "cluster_name" text,
+  "image" text,
+  "image_digest" text,
+  "reason" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_security_vulnerability_unscanned_snapshot_id ON "security_vulnerability_unscanned"(snapshot_id);
@@ -0,0 +1,3 @@
+ALTER TABLE "amazon_node_pools" ADD COLUMN "node_volume_size" integer;
+ALTER TABLE "amazon_node_pools" ADD COLUMN "node_volume_type" text;
+ALTER TABLE "amazon_node_pools" ADD COLUMN "node_security_groups" text;
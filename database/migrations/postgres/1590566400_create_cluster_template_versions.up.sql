@@ -0,0 +1,11 @@
+CREATE TABLE "cluster_template_versions" (
+  "template_id" integer NOT NULL,
+  "version" integer NOT NULL,
+  "parameters" json,
+  "template" text NOT NULL,
+  "created_at" timestamp with time zone,
+  PRIMARY KEY ("template_id", "version")
+);
+
+INSERT INTO "cluster_template_versions" ("template_id", "version", "parameters", "template", "created_at")
+SELECT "id", "version", "parameters", "template", "updated_at" FROM "cluster_templates";
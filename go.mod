@@ -42,6 +42,7 @@ require (
 	github.com/denisenkom/go-mssqldb v0.0.0-20200206145737-bbfc9a55622e // indirect
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/didip/tollbooth v4.0.2+incompatible
+	github.com/docker/distribution v2.7.1+incompatible
 	github.com/erikstmartin/go-testdb v0.0.0-20160219214506-8d10e4a1bae5 // indirect
 	github.com/ghodss/yaml v1.0.0
 	github.com/gin-contrib/cors v1.3.0
@@ -86,6 +87,7 @@ require (
 	github.com/qor/render v0.0.0-20171201033449-63566e46f01b // indirect
 	github.com/qor/responder v0.0.0-20160314063933-ecae0be66c1a // indirect
 	github.com/qor/session v0.0.0-20170907035918-8206b0adab70
+	github.com/robfig/cron v1.2.0
 	github.com/rubenv/sql-migrate v0.0.0-20200212082348-64f95ea68aa3 // indirect
 	github.com/sagikazarmark/appkit v0.8.0
 	github.com/sagikazarmark/kitx v0.12.0
@@ -122,6 +124,7 @@ require (
 	k8s.io/cluster-bootstrap v0.17.5
 	k8s.io/helm v2.16.3+incompatible
 	k8s.io/klog v1.0.0
+	k8s.io/kubectl v0.17.5
 	k8s.io/kubernetes v1.17.3
 	logur.dev/adapter/logrus v0.4.1
 	logur.dev/adapter/zap v0.4.1
@@ -0,0 +1,70 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertemplate
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/tracing/opencensus"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	appkitendpoint "github.com/sagikazarmark/appkit/endpoint"
+	"github.com/sagikazarmark/kitx/correlation"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+	kitxtransport "github.com/sagikazarmark/kitx/transport"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate/clustertemplatedriver"
+	apphttp "github.com/banzaicloud/pipeline/internal/platform/appkit/transport/http"
+)
+
+// RegisterApp registers a new HTTP application for cluster templates.
+func RegisterApp(
+	router *mux.Router,
+	service clustertemplate.Service,
+	logger clustertemplate.Logger,
+	errorHandler clustertemplate.ErrorHandler,
+) error {
+	endpointMiddleware := []endpoint.Middleware{
+		correlation.Middleware(),
+		opencensus.TraceEndpoint("", opencensus.WithSpanName(func(ctx context.Context, _ string) string {
+			name, _ := kitxendpoint.OperationName(ctx)
+
+			return name
+		})),
+		appkitendpoint.LoggingMiddleware(logger),
+	}
+
+	endpoints := clustertemplatedriver.MakeEndpoints(
+		service,
+		kitxendpoint.Combine(endpointMiddleware...),
+	)
+
+	httpServerOptions := []kithttp.ServerOption{
+		kithttp.ServerErrorHandler(kitxtransport.NewErrorHandler(errorHandler)),
+		kithttp.ServerErrorEncoder(kitxhttp.NewJSONProblemErrorEncoder(apphttp.NewDefaultProblemConverter())),
+		kithttp.ServerBefore(correlation.HTTPToContext()),
+	}
+
+	clustertemplatedriver.RegisterHTTPHandlers(
+		endpoints,
+		router.PathPrefix("/clustertemplates").Subrouter(),
+		kitxhttp.ServerOptions(httpServerOptions),
+	)
+
+	return nil
+}
@@ -0,0 +1,364 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertemplate
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// ClusterTemplate is a reusable, parameterized cluster creation request of an organization.
+type ClusterTemplate struct {
+	ID             uint        `json:"id"`
+	OrganizationID uint        `json:"organizationId"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description,omitempty"`
+	Version        int         `json:"version"`
+	Parameters     []Parameter `json:"parameters,omitempty"`
+	Template       string      `json:"template"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+}
+
+// ClusterTemplateInput contains the user supplied fields of a cluster template.
+type ClusterTemplateInput struct {
+	Name        string
+	Description string
+	Parameters  []Parameter
+
+	// Template is a Go template rendering a cluster creation request JSON document.
+	Template string
+}
+
+// ClusterTemplateUsage records the cluster template version a cluster was created from.
+type ClusterTemplateUsage struct {
+	ClusterID       uint      `json:"clusterId"`
+	TemplateID      uint      `json:"templateId"`
+	TemplateVersion int       `json:"templateVersion"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// RenderedClusterTemplate is a cluster creation request rendered from a cluster template.
+type RenderedClusterTemplate struct {
+	TemplateID      uint                   `json:"templateId"`
+	TemplateVersion int                    `json:"templateVersion"`
+	Request         map[string]interface{} `json:"request"`
+}
+
+// +kit:endpoint:errorStrategy=service
+
+// Service manages cluster templates.
+type Service interface {
+	// CreateClusterTemplate creates a new cluster template.
+	CreateClusterTemplate(ctx context.Context, organizationID uint, input ClusterTemplateInput) (template ClusterTemplate, err error)
+
+	// ListClusterTemplates lists the cluster templates of an organization.
+	ListClusterTemplates(ctx context.Context, organizationID uint) (templates []ClusterTemplate, err error)
+
+	// GetClusterTemplate returns a single cluster template.
+	GetClusterTemplate(ctx context.Context, organizationID uint, id uint) (template ClusterTemplate, err error)
+
+	// UpdateClusterTemplate replaces a cluster template and increments its version.
+	UpdateClusterTemplate(ctx context.Context, organizationID uint, id uint, input ClusterTemplateInput) (template ClusterTemplate, err error)
+
+	// DeleteClusterTemplate deletes a cluster template.
+	DeleteClusterTemplate(ctx context.Context, organizationID uint, id uint) (err error)
+
+	// RenderClusterTemplate renders a cluster creation request from a cluster template version.
+	// Version 0 renders the latest version.
+	RenderClusterTemplate(ctx context.Context, organizationID uint, id uint, version int, params map[string]interface{}) (rendered RenderedClusterTemplate, err error)
+
+	// ListClusterTemplateUsages lists the clusters created from a cluster template.
+	ListClusterTemplateUsages(ctx context.Context, organizationID uint, id uint) (usages []ClusterTemplateUsage, err error)
+
+	// RecordClusterTemplateUsage records that a cluster was created from a cluster template version.
+	RecordClusterTemplateUsage(ctx context.Context, usage ClusterTemplateUsage) (err error)
+}
+
+// NewService returns a new Service.
+func NewService(store Store) Service {
+	return service{store: store}
+}
+
+type service struct {
+	store Store
+}
+
+// Store persists cluster templates in a persistent store.
+type Store interface {
+	// Create persists a new cluster template and returns it with its ID.
+	Create(ctx context.Context, template ClusterTemplate) (ClusterTemplate, error)
+
+	// List returns the cluster templates of an organization.
+	List(ctx context.Context, organizationID uint) ([]ClusterTemplate, error)
+
+	// Get returns a cluster template of an organization.
+	Get(ctx context.Context, organizationID uint, id uint) (ClusterTemplate, error)
+
+	// GetVersion returns a version of a cluster template of an organization.
+	GetVersion(ctx context.Context, organizationID uint, id uint, version int) (ClusterTemplate, error)
+
+	// Update replaces a cluster template and keeps its new version.
+	Update(ctx context.Context, template ClusterTemplate) error
+
+	// Delete deletes a cluster template along with its usage records.
+	Delete(ctx context.Context, organizationID uint, id uint) error
+
+	// RecordUsage records that a cluster was created from a cluster template.
+	RecordUsage(ctx context.Context, usage ClusterTemplateUsage) error
+
+	// ListUsages returns the usage records of a cluster template.
+	ListUsages(ctx context.Context, templateID uint) ([]ClusterTemplateUsage, error)
+}
+
+// NotFoundError is returned if a cluster template cannot be found.
+type NotFoundError struct {
+	ID uint
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "cluster template not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"clusterTemplateId", e.ID}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to eg. status code.
+func (NotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (NotFoundError) ServiceError() bool {
+	return true
+}
+
+// VersionNotFoundError is returned if a cluster template version cannot be found.
+type VersionNotFoundError struct {
+	ID      uint
+	Version int
+}
+
+// Error implements the error interface.
+func (VersionNotFoundError) Error() string {
+	return "cluster template version not found"
+}
+
+// Details returns error details.
+func (e VersionNotFoundError) Details() []interface{} {
+	return []interface{}{"clusterTemplateId", e.ID, "clusterTemplateVersion", e.Version}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to eg. status code.
+func (VersionNotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (VersionNotFoundError) ServiceError() bool {
+	return true
+}
+
+// AlreadyExistsError is returned if a cluster template with the same name already exists in an organization.
+type AlreadyExistsError struct {
+	Name string
+}
+
+// Error implements the error interface.
+func (AlreadyExistsError) Error() string {
+	return "cluster template already exists"
+}
+
+// Details returns error details.
+func (e AlreadyExistsError) Details() []interface{} {
+	return []interface{}{"clusterTemplateName", e.Name}
+}
+
+// Conflict tells a client that this error is related to a conflicting request.
+// Can be used to translate the error to eg. status code.
+func (AlreadyExistsError) Conflict() bool {
+	return true
+}
+
+// ServiceError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (AlreadyExistsError) ServiceError() bool {
+	return true
+}
+
+// ValidationError is returned when a cluster template or its parameters are invalid.
+type ValidationError struct {
+	message    string
+	violations []string
+}
+
+// NewValidationError returns a new ValidationError.
+func NewValidationError(message string, violations []string) ValidationError {
+	return ValidationError{
+		message:    message,
+		violations: violations,
+	}
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+
+	return "invalid cluster template"
+}
+
+// Violations returns details of the failed validation.
+func (e ValidationError) Violations() []string {
+	return e.violations[:]
+}
+
+// Validation tells a client that this error is related to a semantic validation of the request.
+// Can be used to translate the error to status codes for example.
+func (ValidationError) Validation() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (ValidationError) ServiceError() bool {
+	return true
+}
+
+func (s service) CreateClusterTemplate(ctx context.Context, organizationID uint, input ClusterTemplateInput) (ClusterTemplate, error) {
+	if err := input.Validate(); err != nil {
+		return ClusterTemplate{}, err
+	}
+
+	if err := s.checkNameAvailable(ctx, organizationID, 0, input.Name); err != nil {
+		return ClusterTemplate{}, err
+	}
+
+	template := ClusterTemplate{
+		OrganizationID: organizationID,
+		Name:           input.Name,
+		Description:    input.Description,
+		Version:        1,
+		Parameters:     input.Parameters,
+		Template:       input.Template,
+	}
+
+	return s.store.Create(ctx, template)
+}
+
+func (s service) ListClusterTemplates(ctx context.Context, organizationID uint) ([]ClusterTemplate, error) {
+	return s.store.List(ctx, organizationID)
+}
+
+func (s service) GetClusterTemplate(ctx context.Context, organizationID uint, id uint) (ClusterTemplate, error) {
+	return s.store.Get(ctx, organizationID, id)
+}
+
+func (s service) UpdateClusterTemplate(ctx context.Context, organizationID uint, id uint, input ClusterTemplateInput) (ClusterTemplate, error) {
+	template, err := s.store.Get(ctx, organizationID, id)
+	if err != nil {
+		return ClusterTemplate{}, err
+	}
+
+	if err := input.Validate(); err != nil {
+		return ClusterTemplate{}, err
+	}
+
+	if err := s.checkNameAvailable(ctx, organizationID, id, input.Name); err != nil {
+		return ClusterTemplate{}, err
+	}
+
+	template.Name = input.Name
+	template.Description = input.Description
+	template.Parameters = input.Parameters
+	template.Template = input.Template
+	template.Version++
+
+	if err := s.store.Update(ctx, template); err != nil {
+		return ClusterTemplate{}, err
+	}
+
+	return s.store.Get(ctx, organizationID, id)
+}
+
+func (s service) DeleteClusterTemplate(ctx context.Context, organizationID uint, id uint) error {
+	if _, err := s.store.Get(ctx, organizationID, id); err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, organizationID, id)
+}
+
+func (s service) RenderClusterTemplate(ctx context.Context, organizationID uint, id uint, version int, params map[string]interface{}) (RenderedClusterTemplate, error) {
+	template, err := s.store.Get(ctx, organizationID, id)
+	if err != nil {
+		return RenderedClusterTemplate{}, err
+	}
+
+	if version != 0 && version != template.Version {
+		template, err = s.store.GetVersion(ctx, organizationID, id, version)
+		if err != nil {
+			return RenderedClusterTemplate{}, err
+		}
+	}
+
+	request, err := template.Render(params)
+	if err != nil {
+		return RenderedClusterTemplate{}, err
+	}
+
+	return RenderedClusterTemplate{
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+		Request:         request,
+	}, nil
+}
+
+func (s service) ListClusterTemplateUsages(ctx context.Context, organizationID uint, id uint) ([]ClusterTemplateUsage, error) {
+	if _, err := s.store.Get(ctx, organizationID, id); err != nil {
+		return nil, err
+	}
+
+	return s.store.ListUsages(ctx, id)
+}
+
+func (s service) RecordClusterTemplateUsage(ctx context.Context, usage ClusterTemplateUsage) error {
+	return s.store.RecordUsage(ctx, usage)
+}
+
+func (s service) checkNameAvailable(ctx context.Context, organizationID uint, id uint, name string) error {
+	templates, err := s.store.List(ctx, organizationID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to list cluster templates")
+	}
+
+	for _, template := range templates {
+		if template.Name == name && template.ID != id {
+			return errors.WithStack(AlreadyExistsError{Name: name})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStore struct {
+	Store
+
+	versions map[int]ClusterTemplate
+	latest   int
+}
+
+func (s testStore) Get(_ context.Context, _ uint, _ uint) (ClusterTemplate, error) {
+	return s.versions[s.latest], nil
+}
+
+func (s testStore) GetVersion(_ context.Context, _ uint, id uint, version int) (ClusterTemplate, error) {
+	template, ok := s.versions[version]
+	if !ok {
+		return ClusterTemplate{}, VersionNotFoundError{ID: id, Version: version}
+	}
+
+	return template, nil
+}
+
+func TestService_RenderClusterTemplate_Version(t *testing.T) {
+	store := testStore{
+		versions: map[int]ClusterTemplate{
+			1: {ID: 1, Version: 1, Template: `{"cloud": "amazon"}`},
+			2: {ID: 1, Version: 2, Template: `{"cloud": "azure"}`},
+		},
+		latest: 2,
+	}
+
+	s := NewService(store)
+
+	rendered, err := s.RenderClusterTemplate(context.Background(), 1, 1, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, RenderedClusterTemplate{TemplateID: 1, TemplateVersion: 2, Request: map[string]interface{}{"cloud": "azure"}}, rendered)
+
+	rendered, err = s.RenderClusterTemplate(context.Background(), 1, 1, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, RenderedClusterTemplate{TemplateID: 1, TemplateVersion: 1, Request: map[string]interface{}{"cloud": "amazon"}}, rendered)
+
+	_, err = s.RenderClusterTemplate(context.Background(), 1, 1, 3, nil)
+	assert.IsType(t, VersionNotFoundError{}, err)
+}
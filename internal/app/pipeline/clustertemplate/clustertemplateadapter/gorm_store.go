@@ -0,0 +1,331 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertemplateadapter
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate"
+)
+
+// TableName constants
+const (
+	clusterTemplateTableName        = "cluster_templates"
+	clusterTemplateVersionTableName = "cluster_template_versions"
+	clusterTemplateUsageTableName   = "cluster_template_clusters"
+)
+
+type clusterTemplateModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_cluster_templates_org_id_name;not null"`
+	Name           string `gorm:"unique_index:idx_cluster_templates_org_id_name;not null"`
+	Description    string `gorm:"type:text"`
+	Version        int    `gorm:"not null"`
+	Parameters     []byte `sql:"type:json"`
+	Template       string `gorm:"type:text;not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TableName changes the default table name.
+func (clusterTemplateModel) TableName() string {
+	return clusterTemplateTableName
+}
+
+type clusterTemplateVersionModel struct {
+	TemplateID uint   `gorm:"primary_key;auto_increment:false"`
+	Version    int    `gorm:"primary_key;auto_increment:false"`
+	Parameters []byte `sql:"type:json"`
+	Template   string `gorm:"type:text;not null"`
+	CreatedAt  time.Time
+}
+
+// TableName changes the default table name.
+func (clusterTemplateVersionModel) TableName() string {
+	return clusterTemplateVersionTableName
+}
+
+type clusterTemplateUsageModel struct {
+	ClusterID       uint `gorm:"primary_key;auto_increment:false"`
+	TemplateID      uint `gorm:"index;not null"`
+	TemplateVersion int  `gorm:"not null"`
+	CreatedAt       time.Time
+}
+
+// TableName changes the default table name.
+func (clusterTemplateUsageModel) TableName() string {
+	return clusterTemplateUsageTableName
+}
+
+// GormStore is a cluster template store using Gorm for data persistence.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{
+		db: db,
+	}
+}
+
+// Create persists a new cluster template.
+func (s *GormStore) Create(ctx context.Context, template clustertemplate.ClusterTemplate) (clustertemplate.ClusterTemplate, error) {
+	model, err := templateToModel(template)
+	if err != nil {
+		return clustertemplate.ClusterTemplate{}, err
+	}
+
+	tx := s.db.Begin()
+
+	err = tx.Create(&model).Error
+	if err != nil {
+		tx.Rollback()
+
+		return clustertemplate.ClusterTemplate{}, errors.WrapIf(err, "failed to create cluster template")
+	}
+
+	err = tx.Create(versionModel(model)).Error
+	if err != nil {
+		tx.Rollback()
+
+		return clustertemplate.ClusterTemplate{}, errors.WrapIf(err, "failed to create cluster template version")
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return clustertemplate.ClusterTemplate{}, errors.WrapIf(err, "failed to commit transaction")
+	}
+
+	return modelToTemplate(model)
+}
+
+// List returns the cluster templates of an organization.
+func (s *GormStore) List(ctx context.Context, organizationID uint) ([]clustertemplate.ClusterTemplate, error) {
+	var models []clusterTemplateModel
+
+	err := s.db.Where("organization_id = ?", organizationID).Order("name").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to find cluster templates")
+	}
+
+	templates := make([]clustertemplate.ClusterTemplate, 0, len(models))
+	for _, model := range models {
+		template, err := modelToTemplate(model)
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// Get returns a cluster template of an organization.
+func (s *GormStore) Get(ctx context.Context, organizationID uint, id uint) (clustertemplate.ClusterTemplate, error) {
+	var model clusterTemplateModel
+
+	err := s.db.Where("organization_id = ? AND id = ?", organizationID, id).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return clustertemplate.ClusterTemplate{}, errors.WithStack(clustertemplate.NotFoundError{ID: id})
+	} else if err != nil {
+		return clustertemplate.ClusterTemplate{}, errors.WrapIfWithDetails(err, "failed to find cluster template", "clusterTemplateId", id)
+	}
+
+	return modelToTemplate(model)
+}
+
+// GetVersion returns a version of a cluster template of an organization.
+func (s *GormStore) GetVersion(ctx context.Context, organizationID uint, id uint, version int) (clustertemplate.ClusterTemplate, error) {
+	template, err := s.Get(ctx, organizationID, id)
+	if err != nil {
+		return clustertemplate.ClusterTemplate{}, err
+	}
+
+	var model clusterTemplateVersionModel
+
+	err = s.db.Where("template_id = ? AND version = ?", id, version).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return clustertemplate.ClusterTemplate{}, errors.WithStack(clustertemplate.VersionNotFoundError{ID: id, Version: version})
+	} else if err != nil {
+		return clustertemplate.ClusterTemplate{}, errors.WrapIfWithDetails(
+			err, "failed to find cluster template version",
+			"clusterTemplateId", id,
+			"clusterTemplateVersion", version,
+		)
+	}
+
+	template.Version = model.Version
+	template.Template = model.Template
+	template.Parameters = nil
+
+	if len(model.Parameters) > 0 {
+		err := json.Unmarshal(model.Parameters, &template.Parameters)
+		if err != nil {
+			return template, errors.WrapIfWithDetails(err, "failed to unmarshal cluster template parameters", "clusterTemplateId", id)
+		}
+	}
+
+	return template, nil
+}
+
+// Update replaces a cluster template and keeps its new version.
+func (s *GormStore) Update(ctx context.Context, template clustertemplate.ClusterTemplate) error {
+	model, err := templateToModel(template)
+	if err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+
+	err = tx.Save(&model).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to update cluster template", "clusterTemplateId", template.ID)
+	}
+
+	err = tx.Create(versionModel(model)).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to create cluster template version", "clusterTemplateId", template.ID)
+	}
+
+	return errors.WrapIf(tx.Commit().Error, "failed to commit transaction")
+}
+
+// Delete deletes a cluster template along with its usage records.
+func (s *GormStore) Delete(ctx context.Context, organizationID uint, id uint) error {
+	tx := s.db.Begin()
+
+	err := tx.Where("template_id = ?", id).Delete(&clusterTemplateUsageModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete cluster template usages", "clusterTemplateId", id)
+	}
+
+	err = tx.Where("template_id = ?", id).Delete(&clusterTemplateVersionModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete cluster template versions", "clusterTemplateId", id)
+	}
+
+	err = tx.Where("organization_id = ? AND id = ?", organizationID, id).Delete(&clusterTemplateModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete cluster template", "clusterTemplateId", id)
+	}
+
+	return errors.WrapIf(tx.Commit().Error, "failed to commit transaction")
+}
+
+// RecordUsage records that a cluster was created from a cluster template.
+func (s *GormStore) RecordUsage(ctx context.Context, usage clustertemplate.ClusterTemplateUsage) error {
+	model := clusterTemplateUsageModel{
+		ClusterID:       usage.ClusterID,
+		TemplateID:      usage.TemplateID,
+		TemplateVersion: usage.TemplateVersion,
+	}
+
+	err := s.db.Create(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to record cluster template usage", "clusterId", usage.ClusterID)
+	}
+
+	return nil
+}
+
+// ListUsages returns the usage records of a cluster template.
+func (s *GormStore) ListUsages(ctx context.Context, templateID uint) ([]clustertemplate.ClusterTemplateUsage, error) {
+	var models []clusterTemplateUsageModel
+
+	err := s.db.Where("template_id = ?", templateID).Order("cluster_id").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to find cluster template usages", "clusterTemplateId", templateID)
+	}
+
+	usages := make([]clustertemplate.ClusterTemplateUsage, 0, len(models))
+	for _, model := range models {
+		usages = append(usages, clustertemplate.ClusterTemplateUsage{
+			ClusterID:       model.ClusterID,
+			TemplateID:      model.TemplateID,
+			TemplateVersion: model.TemplateVersion,
+			CreatedAt:       model.CreatedAt,
+		})
+	}
+
+	return usages, nil
+}
+
+func templateToModel(template clustertemplate.ClusterTemplate) (clusterTemplateModel, error) {
+	model := clusterTemplateModel{
+		ID:             template.ID,
+		OrganizationID: template.OrganizationID,
+		Name:           template.Name,
+		Description:    template.Description,
+		Version:        template.Version,
+		Template:       template.Template,
+		CreatedAt:      template.CreatedAt,
+	}
+
+	parameters, err := json.Marshal(template.Parameters)
+	if err != nil {
+		return model, errors.WrapIf(err, "failed to marshal cluster template parameters")
+	}
+	model.Parameters = parameters
+
+	return model, nil
+}
+
+func versionModel(model clusterTemplateModel) *clusterTemplateVersionModel {
+	return &clusterTemplateVersionModel{
+		TemplateID: model.ID,
+		Version:    model.Version,
+		Parameters: model.Parameters,
+		Template:   model.Template,
+	}
+}
+
+func modelToTemplate(model clusterTemplateModel) (clustertemplate.ClusterTemplate, error) {
+	template := clustertemplate.ClusterTemplate{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		Name:           model.Name,
+		Description:    model.Description,
+		Version:        model.Version,
+		Template:       model.Template,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+
+	if len(model.Parameters) > 0 {
+		err := json.Unmarshal(model.Parameters, &template.Parameters)
+		if err != nil {
+			return template, errors.WrapIfWithDetails(err, "failed to unmarshal cluster template parameters", "clusterTemplateId", model.ID)
+		}
+	}
+
+	return template, nil
+}
@@ -0,0 +1,251 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertemplatedriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate"
+	apphttp "github.com/banzaicloud/pipeline/internal/platform/appkit/transport/http"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into an http.Handler.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	errorEncoder := kitxhttp.NewJSONProblemErrorResponseEncoder(apphttp.NewDefaultProblemConverter())
+
+	router.Methods(http.MethodGet).Path("").Handler(kithttp.NewServer(
+		endpoints.ListClusterTemplates,
+		decodeListClusterTemplatesHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeListClusterTemplatesHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPost).Path("").Handler(kithttp.NewServer(
+		endpoints.CreateClusterTemplate,
+		decodeCreateClusterTemplateHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeCreateClusterTemplateHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/{id}").Handler(kithttp.NewServer(
+		endpoints.GetClusterTemplate,
+		decodeGetClusterTemplateHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeGetClusterTemplateHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("/{id}").Handler(kithttp.NewServer(
+		endpoints.UpdateClusterTemplate,
+		decodeUpdateClusterTemplateHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeUpdateClusterTemplateHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodDelete).Path("/{id}").Handler(kithttp.NewServer(
+		endpoints.DeleteClusterTemplate,
+		decodeDeleteClusterTemplateHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPost).Path("/{id}/render").Handler(kithttp.NewServer(
+		endpoints.RenderClusterTemplate,
+		decodeRenderClusterTemplateHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeRenderClusterTemplateHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/{id}/clusters").Handler(kithttp.NewServer(
+		endpoints.ListClusterTemplateUsages,
+		decodeListClusterTemplateUsagesHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeListClusterTemplateUsagesHTTPResponse, errorEncoder),
+		options...,
+	))
+}
+
+func decodeListClusterTemplatesHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	org := auth.GetCurrentOrganization(r)
+
+	return ListClusterTemplatesRequest{OrganizationID: org.ID}, nil
+}
+
+func encodeListClusterTemplatesHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(ListClusterTemplatesResponse)
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, resp.Templates)
+}
+
+func decodeCreateClusterTemplateHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	org := auth.GetCurrentOrganization(r)
+
+	input, err := decodeClusterTemplateInput(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateClusterTemplateRequest{OrganizationID: org.ID, Input: input}, nil
+}
+
+func encodeCreateClusterTemplateHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(CreateClusterTemplateResponse)
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(resp.Template, http.StatusCreated))
+}
+
+func decodeGetClusterTemplateHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	org := auth.GetCurrentOrganization(r)
+
+	id, err := getClusterTemplateID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetClusterTemplateRequest{OrganizationID: org.ID, Id: id}, nil
+}
+
+func encodeGetClusterTemplateHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(GetClusterTemplateResponse)
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, resp.Template)
+}
+
+func decodeUpdateClusterTemplateHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	org := auth.GetCurrentOrganization(r)
+
+	id, err := getClusterTemplateID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := decodeClusterTemplateInput(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateClusterTemplateRequest{OrganizationID: org.ID, Id: id, Input: input}, nil
+}
+
+func encodeUpdateClusterTemplateHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(UpdateClusterTemplateResponse)
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, resp.Template)
+}
+
+func decodeDeleteClusterTemplateHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	org := auth.GetCurrentOrganization(r)
+
+	id, err := getClusterTemplateID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeleteClusterTemplateRequest{OrganizationID: org.ID, Id: id}, nil
+}
+
+func decodeRenderClusterTemplateHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	org := auth.GetCurrentOrganization(r)
+
+	id, err := getClusterTemplateID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var request pipeline.ClusterTemplateParametersRequest
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return RenderClusterTemplateRequest{
+		OrganizationID: org.ID,
+		Id:             id,
+		Version:        int(request.Version),
+		Params:         request.Parameters,
+	}, nil
+}
+
+func encodeRenderClusterTemplateHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(RenderClusterTemplateResponse)
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, resp.Rendered)
+}
+
+func decodeListClusterTemplateUsagesHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	org := auth.GetCurrentOrganization(r)
+
+	id, err := getClusterTemplateID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ListClusterTemplateUsagesRequest{OrganizationID: org.ID, Id: id}, nil
+}
+
+func encodeListClusterTemplateUsagesHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(ListClusterTemplateUsagesResponse)
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, resp.Usages)
+}
+
+func decodeClusterTemplateInput(r *http.Request) (clustertemplate.ClusterTemplateInput, error) {
+	var request pipeline.ClusterTemplateRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return clustertemplate.ClusterTemplateInput{}, errors.Wrap(err, "failed to decode request")
+	}
+
+	input := clustertemplate.ClusterTemplateInput{
+		Name:        request.Name,
+		Description: request.Description,
+		Template:    request.Template,
+	}
+
+	for _, param := range request.Parameters {
+		input.Parameters = append(input.Parameters, clustertemplate.Parameter{
+			Name:        param.Name,
+			Type:        param.Type,
+			Description: param.Description,
+			Required:    param.Required,
+			Default:     param.Default,
+		})
+	}
+
+	return input, nil
+}
+
+func getClusterTemplateID(r *http.Request) (uint, error) {
+	vars := mux.Vars(r)
+
+	idStr, ok := vars["id"]
+	if !ok || idStr == "" {
+		return 0, errors.NewWithDetails("missing parameter from the URL", "param", "id")
+	}
+
+	id, err := strconv.ParseUint(idStr, 0, 0)
+
+	return uint(id), errors.WrapIf(err, "invalid cluster template ID format")
+}
@@ -0,0 +1,237 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"text/template"
+)
+
+// Parameter types supported by cluster templates.
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeInteger = "integer"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+)
+
+// Parameter is a declared input of a cluster template.
+type Parameter struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+}
+
+// Validate validates the cluster template input.
+func (i ClusterTemplateInput) Validate() error {
+	var violations []string
+
+	if i.Name == "" {
+		violations = append(violations, "name must not be empty")
+	}
+
+	names := make(map[string]bool, len(i.Parameters))
+	for _, param := range i.Parameters {
+		if param.Name == "" {
+			violations = append(violations, "parameter name must not be empty")
+
+			continue
+		}
+
+		if names[param.Name] {
+			violations = append(violations, fmt.Sprintf("parameter %q is declared more than once", param.Name))
+		}
+		names[param.Name] = true
+
+		switch param.Type {
+		case ParameterTypeString, ParameterTypeInteger, ParameterTypeNumber, ParameterTypeBoolean:
+			if param.Default != nil {
+				if _, err := param.convert(param.Default); err != nil {
+					violations = append(violations, fmt.Sprintf("parameter %q has an invalid default: %s", param.Name, err.Error()))
+				}
+			}
+
+		default:
+			violations = append(violations, fmt.Sprintf("parameter %q has an unsupported type %q", param.Name, param.Type))
+		}
+	}
+
+	if i.Template == "" {
+		violations = append(violations, "template must not be empty")
+	} else if _, err := parseTemplate(i.Template); err != nil {
+		violations = append(violations, fmt.Sprintf("template cannot be parsed: %s", err.Error()))
+	}
+
+	if len(violations) > 0 {
+		return NewValidationError("invalid cluster template", violations)
+	}
+
+	return nil
+}
+
+// Render renders the cluster creation request of the template with the given parameter values.
+// Parameters which are not supplied fall back to their default values.
+// Referencing an undeclared parameter in the template is an error.
+//
+// String values are escaped as JSON string contents when printed,
+// so that they cannot alter the structure of the rendered request.
+func (t ClusterTemplate) Render(params map[string]interface{}) (map[string]interface{}, error) {
+	values, err := t.resolveParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, err := parseTemplate(t.Template)
+	if err != nil {
+		return nil, NewValidationError("invalid cluster template", []string{err.Error()})
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, values); err != nil {
+		return nil, NewValidationError("failed to render cluster template", []string{err.Error()})
+	}
+
+	var request map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &request); err != nil {
+		return nil, NewValidationError("rendered cluster template is not a valid JSON object", []string{err.Error()})
+	}
+
+	return request, nil
+}
+
+func (t ClusterTemplate) resolveParameters(params map[string]interface{}) (map[string]interface{}, error) {
+	var violations []string
+
+	declared := make(map[string]bool, len(t.Parameters))
+	values := make(map[string]interface{}, len(t.Parameters))
+
+	for _, param := range t.Parameters {
+		declared[param.Name] = true
+
+		value, ok := params[param.Name]
+		if !ok || value == nil {
+			if param.Default != nil {
+				value = param.Default
+			} else if param.Required {
+				violations = append(violations, fmt.Sprintf("parameter %q is required", param.Name))
+
+				continue
+			} else {
+				// optional parameters are still present, so that templates can check them with "if" or "with"
+				values[param.Name] = nil
+
+				continue
+			}
+		}
+
+		converted, err := param.convert(value)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("parameter %q: %s", param.Name, err.Error()))
+
+			continue
+		}
+
+		values[param.Name] = converted
+	}
+
+	var unknown []string
+	for name := range params {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		violations = append(violations, fmt.Sprintf("parameter %q is not declared by the template", name))
+	}
+
+	if len(violations) > 0 {
+		return nil, NewValidationError("invalid cluster template parameters", violations)
+	}
+
+	return values, nil
+}
+
+// convert checks a (JSON decoded) value against the parameter type.
+func (p Parameter) convert(value interface{}) (interface{}, error) {
+	switch p.Type {
+	case ParameterTypeString:
+		switch v := value.(type) {
+		case string:
+			return jsonString(v), nil
+		case jsonString:
+			return v, nil
+		}
+
+	case ParameterTypeInteger:
+		switch v := value.(type) {
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), nil
+			}
+		}
+
+	case ParameterTypeNumber:
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+
+	case ParameterTypeBoolean:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("value must be of type %s", p.Type)
+}
+
+// jsonString is a string parameter value which is escaped when printed into a template.
+type jsonString string
+
+// String returns the value escaped as the contents of a JSON string.
+func (s jsonString) String() string {
+	b, _ := json.Marshal(string(s))
+
+	return string(b[1 : len(b)-1])
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("cluster").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"json": func(v interface{}) (string, error) {
+				b, err := json.Marshal(v)
+
+				return string(b), err
+			},
+		}).
+		Parse(text)
+}
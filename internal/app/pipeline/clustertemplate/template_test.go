@@ -0,0 +1,134 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertemplate
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterTemplate_Render(t *testing.T) {
+	template := ClusterTemplate{
+		Parameters: []Parameter{
+			{Name: "name", Type: ParameterTypeString, Required: true},
+			{Name: "location", Type: ParameterTypeString, Default: "eu-west-1"},
+			{Name: "nodeCount", Type: ParameterTypeInteger, Default: float64(3)},
+			{Name: "spotPrice", Type: ParameterTypeNumber},
+		},
+		Template: `{
+			"name": {{ json .name }},
+			"location": {{ json .location }},
+			"nodes": {{ .nodeCount }}
+			{{- with .spotPrice }}, "spotPrice": {{ . }}{{ end }}
+		}`,
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		request, err := template.Render(map[string]interface{}{"name": "my-cluster"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"name":     "my-cluster",
+			"location": "eu-west-1",
+			"nodes":    float64(3),
+		}, request)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		request, err := template.Render(map[string]interface{}{
+			"name":      "my-cluster",
+			"location":  "us-east-2",
+			"nodeCount": float64(5),
+			"spotPrice": 0.5,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"name":      "my-cluster",
+			"location":  "us-east-2",
+			"nodes":     float64(5),
+			"spotPrice": 0.5,
+		}, request)
+	})
+
+	t.Run("InvalidParameters", func(t *testing.T) {
+		_, err := template.Render(map[string]interface{}{
+			"nodeCount": 2.5,
+			"unknown":   true,
+		})
+		require.Error(t, err)
+
+		var validationErr ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, []string{
+			`parameter "name" is required`,
+			`parameter "nodeCount": value must be of type integer`,
+			`parameter "unknown" is not declared by the template`,
+		}, validationErr.Violations())
+	})
+
+	t.Run("Injection", func(t *testing.T) {
+		template := ClusterTemplate{
+			Parameters: []Parameter{
+				{Name: "name", Type: ParameterTypeString, Required: true},
+				{Name: "location", Type: ParameterTypeString, Default: "eu-west-1"},
+			},
+			Template: `{"name": "{{ .name }}", "location": {{ json .location }}}`,
+		}
+
+		request, err := template.Render(map[string]interface{}{
+			"name": `my-cluster", "location": "us-east-2`,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"name":     `my-cluster", "location": "us-east-2`,
+			"location": "eu-west-1",
+		}, request)
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		template := ClusterTemplate{Template: `{"name": {{ "unquoted" }}}`}
+
+		_, err := template.Render(nil)
+		require.Error(t, err)
+
+		var validationErr ValidationError
+		require.True(t, errors.As(err, &validationErr))
+	})
+}
+
+func TestClusterTemplateInput_Validate(t *testing.T) {
+	input := ClusterTemplateInput{
+		Name: "template",
+		Parameters: []Parameter{
+			{Name: "name", Type: ParameterTypeString},
+			{Name: "name", Type: ParameterTypeString},
+			{Name: "count", Type: "list"},
+			{Name: "enabled", Type: ParameterTypeBoolean, Default: "yes"},
+		},
+		Template: `{{ .name `,
+	}
+
+	err := input.Validate()
+	require.Error(t, err)
+
+	var validationErr ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Violations(), 4)
+}
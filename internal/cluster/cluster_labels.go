@@ -0,0 +1,80 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateLabels validates a set of cluster labels and returns the violations.
+// Label keys and values follow the Kubernetes label syntax.
+func ValidateLabels(clusterLabels map[string]string) []string {
+	keys := make([]string, 0, len(clusterLabels))
+	for key := range clusterLabels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var violations []string
+
+	for _, key := range keys {
+		for _, v := range validation.IsQualifiedName(key) {
+			violations = append(violations, fmt.Sprintf("invalid label key %q: %s", key, v))
+		}
+
+		for _, v := range validation.IsValidLabelValue(clusterLabels[key]) {
+			violations = append(violations, fmt.Sprintf("invalid value %q for label %q: %s", clusterLabels[key], key, v))
+		}
+	}
+
+	return violations
+}
+
+// ValidateClusterLabels validates the labels of a cluster.
+// The returned error lists the violations in its message in a readable format for legacy APIs.
+func ValidateClusterLabels(clusterLabels map[string]string) error {
+	violations := ValidateLabels(clusterLabels)
+	if len(violations) > 0 {
+		return errors.WithStack(NewValidationError(
+			fmt.Sprintf("invalid cluster labels: %s", strings.Join(violations, ", ")),
+			violations,
+		))
+	}
+
+	return nil
+}
+
+// ParseLabelSelector parses a label selector in the Kubernetes label selector syntax
+// (eg. "env=prod,team in (a,b),!deprecated").
+// An empty selector matches every cluster.
+func ParseLabelSelector(selector string) (labels.Selector, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, errors.WithStack(NewValidationError("invalid label selector", []string{err.Error()}))
+	}
+
+	return s, nil
+}
+
+// MatchesLabelSelector returns true if the labels of the cluster match the selector.
+func (c Cluster) MatchesLabelSelector(selector labels.Selector) bool {
+	return selector.Matches(labels.Set(c.Labels))
+}
@@ -0,0 +1,95 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cluster
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLabels(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		violations := ValidateLabels(map[string]string{
+			"env":                      "prod",
+			"example.com/team":         "platform",
+			"banzaicloud.io/no-value":  "",
+			"cost-center_1.department": "r-and-d",
+		})
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		violations := ValidateLabels(map[string]string{
+			"env":     "not a valid value",
+			"-prefix": "value",
+		})
+
+		require.Len(t, violations, 2)
+		assert.Contains(t, violations[0], "invalid label key \"-prefix\"")
+		assert.Contains(t, violations[1], "invalid value \"not a valid value\" for label \"env\"")
+	})
+}
+
+func TestValidateClusterLabels(t *testing.T) {
+	assert.NoError(t, ValidateClusterLabels(nil))
+
+	err := ValidateClusterLabels(map[string]string{"env": "not a valid value"})
+	require.Error(t, err)
+
+	var validationErr ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Violations(), 1)
+	assert.Contains(t, err.Error(), "invalid cluster labels: ")
+}
+
+func TestCluster_MatchesLabelSelector(t *testing.T) {
+	cluster := Cluster{
+		Labels: map[string]string{
+			"env":  "prod",
+			"team": "platform",
+		},
+	}
+
+	tests := []struct {
+		selector string
+		matches  bool
+	}{
+		{selector: "", matches: true},
+		{selector: "env=prod", matches: true},
+		{selector: "env=prod,team in (platform,data)", matches: true},
+		{selector: "env!=prod", matches: false},
+		{selector: "!deprecated", matches: true},
+		{selector: "deprecated", matches: false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.selector, func(t *testing.T) {
+			selector, err := ParseLabelSelector(test.selector)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.matches, cluster.MatchesLabelSelector(selector))
+		})
+	}
+
+	t.Run("InvalidSelector", func(t *testing.T) {
+		_, err := ParseLabelSelector("env in prod")
+		require.Error(t, err)
+	})
+}
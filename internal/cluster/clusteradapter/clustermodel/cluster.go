@@ -53,6 +53,11 @@ type ClusterModel struct {
 	OidcEnabled    bool         `gorm:"default:false;not null"`
 	StatusMessage  string       `sql:"type:text;"`
 	ScaleOptions   ScaleOptions `gorm:"foreignkey:ClusterID"`
+
+	DeletionProtection bool `gorm:"default:false;not null"`
+
+	// Labels are only saved together with a new cluster, use the label store to change them.
+	Labels []LabelModel `gorm:"foreignkey:ClusterID;association_autoupdate:false"`
 }
 
 // TableName changes the default table name.
@@ -15,7 +15,11 @@
 package clustermodel
 
 import (
+	"context"
 	"time"
+
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+	"github.com/banzaicloud/pipeline/src/auth"
 )
 
 // StatusHistoryModel records the status transitions of a cluster and stores it in a database.
@@ -30,9 +34,23 @@ type StatusHistoryModel struct {
 	FromStatusMessage string `sql:"type:text;" gorm:"not null"`
 	ToStatus          string `gorm:"not null"`
 	ToStatusMessage   string `sql:"type:text;" gorm:"not null"`
+
+	UserID    uint
+	ProcessID string
 }
 
 // TableName changes the default table name.
 func (StatusHistoryModel) TableName() string {
 	return "cluster_status_history"
 }
+
+// SetActor records the user and the process acting on the cluster (if any) from a context.
+func (m *StatusHistoryModel) SetActor(ctx context.Context) {
+	if userID, ok := (auth.UserExtractor{}).GetUserID(ctx); ok {
+		m.UserID = userID
+	}
+
+	if processID, ok := ctxutil.ProcessID(ctx); ok {
+		m.ProcessID = processID
+	}
+}
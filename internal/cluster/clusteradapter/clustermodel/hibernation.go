@@ -0,0 +1,44 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustermodel
+
+// HibernatedNodePoolModel records the scaling settings of a node pool of a hibernated cluster.
+type HibernatedNodePoolModel struct {
+	ID          uint   `gorm:"primary_key"`
+	ClusterID   uint   `gorm:"unique_index:idx_cluster_hibernated_node_pools_cluster_id_name"`
+	Name        string `gorm:"unique_index:idx_cluster_hibernated_node_pools_cluster_id_name"`
+	Size        int
+	Autoscaling bool
+	MinSize     int
+	MaxSize     int
+}
+
+// TableName changes the default table name.
+func (HibernatedNodePoolModel) TableName() string {
+	return "cluster_hibernated_node_pools"
+}
+
+// HibernationScheduleModel describes the periodic hibernation and resume of a cluster.
+type HibernationScheduleModel struct {
+	ID           uint `gorm:"primary_key"`
+	ClusterID    uint `gorm:"unique_index:idx_cluster_hibernation_schedules_cluster_id"`
+	Hibernate    string
+	Resume       string
+	KeepNodePool string
+}
+
+// TableName changes the default table name.
+func (HibernationScheduleModel) TableName() string {
+	return "cluster_hibernation_schedules"
+}
@@ -0,0 +1,54 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustermodel
+
+import (
+	"sort"
+)
+
+// LabelModel describes a key/value label attached to a cluster.
+type LabelModel struct {
+	ID        uint   `gorm:"primary_key"`
+	ClusterID uint   `gorm:"unique_index:idx_cluster_labels_cluster_id_key"`
+	Key       string `gorm:"unique_index:idx_cluster_labels_cluster_id_key"`
+	Value     string
+}
+
+// TableName changes the default table name.
+func (LabelModel) TableName() string {
+	return "cluster_labels"
+}
+
+// NewLabelModels creates label models (in key order) from a label map.
+func NewLabelModels(labels map[string]string) []LabelModel {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	labelModels := make([]LabelModel, 0, len(keys))
+	for _, key := range keys {
+		labelModels = append(labelModels, LabelModel{
+			Key:   key,
+			Value: labels[key],
+		})
+	}
+
+	return labelModels
+}
@@ -30,6 +30,9 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		&ClusterModel{},
 		&ScaleOptions{},
 		&StatusHistoryModel{},
+		&LabelModel{},
+		&HibernatedNodePoolModel{},
+		&HibernationScheduleModel{},
 	}
 
 	var tableNames string
@@ -51,5 +54,20 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		return err
 	}
 
+	err = gormhelper.AddForeignKey(db, logger, &ClusterModel{}, &LabelModel{}, "ClusterID")
+	if err != nil {
+		return err
+	}
+
+	err = gormhelper.AddForeignKey(db, logger, &ClusterModel{}, &HibernatedNodePoolModel{}, "ClusterID")
+	if err != nil {
+		return err
+	}
+
+	err = gormhelper.AddForeignKey(db, logger, &ClusterModel{}, &HibernationScheduleModel{}, "ClusterID")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
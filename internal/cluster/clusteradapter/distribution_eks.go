@@ -43,13 +43,18 @@ func (s eksService) CreateNodePool(ctx context.Context, clusterID uint, rawNodeP
 	panic("implement me")
 }
 
-func (s eksService) UpdateNodePool(ctx context.Context, clusterID uint, nodePoolName string, rawNodePoolUpdate cluster.RawNodePoolUpdate) (string, error) {
+func (s eksService) UpdateNodePool(
+	ctx context.Context,
+	clusterID uint,
+	nodePoolName string,
+	rawNodePoolUpdate cluster.RawNodePoolUpdate,
+) (string, []string, error) {
 	var nodePoolUpdate eks.NodePoolUpdate
 
 	err := mapstructure.Decode(rawNodePoolUpdate, &nodePoolUpdate)
 	if err != nil {
 		// TODO: return a service error
-		return "", errors.Wrap(err, "failed to decode node pool update")
+		return "", nil, errors.Wrap(err, "failed to decode node pool update")
 	}
 
 	return s.service.UpdateNodePool(ctx, clusterID, nodePoolName, nodePoolUpdate)
@@ -58,3 +63,31 @@ func (s eksService) UpdateNodePool(ctx context.Context, clusterID uint, nodePool
 func (s eksService) DeleteNodePool(ctx context.Context, clusterID uint, name string) (deleted bool, err error) {
 	panic("implement me")
 }
+
+func (s eksService) UpgradeCluster(ctx context.Context, clusterID uint, kubernetesVersion string) (string, error) {
+	return s.service.UpgradeCluster(ctx, clusterID, kubernetesVersion)
+}
+
+func (s eksService) UpdateClusterLabels(ctx context.Context, clusterID uint, labels map[string]string) error {
+	panic("implement me")
+}
+
+func (s eksService) HibernateCluster(ctx context.Context, clusterID uint, options cluster.HibernateClusterOptions) (string, error) {
+	return s.service.HibernateCluster(ctx, clusterID, options)
+}
+
+func (s eksService) ResumeCluster(ctx context.Context, clusterID uint) (string, error) {
+	return s.service.ResumeCluster(ctx, clusterID)
+}
+
+func (s eksService) GetHibernationSchedule(ctx context.Context, clusterID uint) (cluster.HibernationSchedule, error) {
+	return s.service.GetHibernationSchedule(ctx, clusterID)
+}
+
+func (s eksService) SetHibernationSchedule(ctx context.Context, clusterID uint, schedule cluster.HibernationSchedule) error {
+	return s.service.SetHibernationSchedule(ctx, clusterID, schedule)
+}
+
+func (s eksService) SetDeletionProtection(ctx context.Context, clusterID uint, enabled bool) error {
+	panic("implement me")
+}
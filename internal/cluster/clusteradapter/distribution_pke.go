@@ -0,0 +1,103 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusteradapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+)
+
+// NewPKEService returns a new PKE distribution service.
+//
+// PKE clusters are managed by the legacy cluster APIs, every other operation is rejected as not supported.
+func NewPKEService(clusters cluster.Store, service pke.Service) cluster.Service {
+	return pkeService{
+		clusters: clusters,
+		service:  service,
+	}
+}
+
+type pkeService struct {
+	clusters cluster.Store
+	service  pke.Service
+}
+
+func (s pkeService) notSupported(ctx context.Context, clusterID uint) error {
+	c, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithStack(cluster.NotSupportedDistributionError{
+		ID:           c.ID,
+		Cloud:        c.Cloud,
+		Distribution: c.Distribution,
+
+		Message: "not supported distribution",
+	})
+}
+
+func (s pkeService) DeleteCluster(ctx context.Context, clusterIdentifier cluster.Identifier, options cluster.DeleteClusterOptions) (deleted bool, err error) {
+	return false, s.notSupported(ctx, clusterIdentifier.ClusterID)
+}
+
+func (s pkeService) CreateNodePool(ctx context.Context, clusterID uint, rawNodePool cluster.NewRawNodePool) error {
+	return s.notSupported(ctx, clusterID)
+}
+
+func (s pkeService) UpdateNodePool(
+	ctx context.Context,
+	clusterID uint,
+	nodePoolName string,
+	rawNodePoolUpdate cluster.RawNodePoolUpdate,
+) (string, []string, error) {
+	return "", nil, s.notSupported(ctx, clusterID)
+}
+
+func (s pkeService) DeleteNodePool(ctx context.Context, clusterID uint, name string) (deleted bool, err error) {
+	return false, s.notSupported(ctx, clusterID)
+}
+
+func (s pkeService) UpgradeCluster(ctx context.Context, clusterID uint, kubernetesVersion string) (string, error) {
+	return "", s.notSupported(ctx, clusterID)
+}
+
+func (s pkeService) UpdateClusterLabels(ctx context.Context, clusterID uint, labels map[string]string) error {
+	return s.notSupported(ctx, clusterID)
+}
+
+func (s pkeService) HibernateCluster(ctx context.Context, clusterID uint, options cluster.HibernateClusterOptions) (string, error) {
+	return s.service.HibernateCluster(ctx, clusterID, options)
+}
+
+func (s pkeService) ResumeCluster(ctx context.Context, clusterID uint) (string, error) {
+	return s.service.ResumeCluster(ctx, clusterID)
+}
+
+func (s pkeService) GetHibernationSchedule(ctx context.Context, clusterID uint) (cluster.HibernationSchedule, error) {
+	return s.service.GetHibernationSchedule(ctx, clusterID)
+}
+
+func (s pkeService) SetHibernationSchedule(ctx context.Context, clusterID uint, schedule cluster.HibernationSchedule) error {
+	return s.service.SetHibernationSchedule(ctx, clusterID, schedule)
+}
+
+func (s pkeService) SetDeletionProtection(ctx context.Context, clusterID uint, enabled bool) error {
+	return s.notSupported(ctx, clusterID)
+}
@@ -0,0 +1,145 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusteradapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
+)
+
+// HibernationStore is a cluster.HibernationStore implementation using Gorm.
+type HibernationStore struct {
+	db *gorm.DB
+}
+
+// NewHibernationStore returns a new HibernationStore.
+func NewHibernationStore(db *gorm.DB) HibernationStore {
+	return HibernationStore{
+		db: db,
+	}
+}
+
+// SaveHibernatedNodePools replaces the recorded scaling settings of node pools of a cluster.
+func (s HibernationStore) SaveHibernatedNodePools(ctx context.Context, clusterID uint, nodePools []cluster.HibernatedNodePool) error {
+	tx := s.db.Begin()
+	if err := tx.Error; err != nil {
+		return errors.WrapIfWithDetails(err, "failed to begin transaction", "clusterId", clusterID)
+	}
+
+	err := tx.Where("cluster_id = ?", clusterID).Delete(clustermodel.HibernatedNodePoolModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete hibernated node pools", "clusterId", clusterID)
+	}
+
+	for _, nodePool := range nodePools {
+		nodePoolModel := clustermodel.HibernatedNodePoolModel{
+			ClusterID:   clusterID,
+			Name:        nodePool.Name,
+			Size:        nodePool.Size,
+			Autoscaling: nodePool.Autoscaling,
+			MinSize:     nodePool.MinSize,
+			MaxSize:     nodePool.MaxSize,
+		}
+
+		if err := tx.Create(&nodePoolModel).Error; err != nil {
+			tx.Rollback()
+
+			return errors.WrapIfWithDetails(err, "failed to save hibernated node pool", "clusterId", clusterID, "nodePool", nodePool.Name)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return errors.WrapIfWithDetails(err, "failed to commit hibernated node pools", "clusterId", clusterID)
+	}
+
+	return nil
+}
+
+// GetHibernatedNodePools returns the recorded scaling settings of node pools of a cluster.
+func (s HibernationStore) GetHibernatedNodePools(ctx context.Context, clusterID uint) ([]cluster.HibernatedNodePool, error) {
+	var nodePoolModels []clustermodel.HibernatedNodePoolModel
+
+	err := s.db.Where("cluster_id = ?", clusterID).Order("name").Find(&nodePoolModels).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to get hibernated node pools", "clusterId", clusterID)
+	}
+
+	nodePools := make([]cluster.HibernatedNodePool, 0, len(nodePoolModels))
+	for _, nodePoolModel := range nodePoolModels {
+		nodePools = append(nodePools, cluster.HibernatedNodePool{
+			Name:        nodePoolModel.Name,
+			Size:        nodePoolModel.Size,
+			Autoscaling: nodePoolModel.Autoscaling,
+			MinSize:     nodePoolModel.MinSize,
+			MaxSize:     nodePoolModel.MaxSize,
+		})
+	}
+
+	return nodePools, nil
+}
+
+// DeleteHibernatedNodePools deletes the recorded scaling settings of node pools of a cluster.
+func (s HibernationStore) DeleteHibernatedNodePools(ctx context.Context, clusterID uint) error {
+	err := s.db.Where("cluster_id = ?", clusterID).Delete(clustermodel.HibernatedNodePoolModel{}).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete hibernated node pools", "clusterId", clusterID)
+	}
+
+	return nil
+}
+
+// GetHibernationSchedule returns the hibernation schedule of a cluster.
+// An empty schedule is returned when the cluster has no schedule.
+func (s HibernationStore) GetHibernationSchedule(ctx context.Context, clusterID uint) (cluster.HibernationSchedule, error) {
+	var scheduleModel clustermodel.HibernationScheduleModel
+
+	err := s.db.Where(clustermodel.HibernationScheduleModel{ClusterID: clusterID}).First(&scheduleModel).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return cluster.HibernationSchedule{}, nil
+	} else if err != nil {
+		return cluster.HibernationSchedule{}, errors.WrapIfWithDetails(err, "failed to get hibernation schedule", "clusterId", clusterID)
+	}
+
+	return cluster.HibernationSchedule{
+		Hibernate:    scheduleModel.Hibernate,
+		Resume:       scheduleModel.Resume,
+		KeepNodePool: scheduleModel.KeepNodePool,
+	}, nil
+}
+
+// SetHibernationSchedule saves the hibernation schedule of a cluster.
+func (s HibernationStore) SetHibernationSchedule(ctx context.Context, clusterID uint, schedule cluster.HibernationSchedule) error {
+	var scheduleModel clustermodel.HibernationScheduleModel
+
+	err := s.db.
+		Where(clustermodel.HibernationScheduleModel{ClusterID: clusterID}).
+		Assign(map[string]interface{}{
+			"hibernate":      schedule.Hibernate,
+			"resume":         schedule.Resume,
+			"keep_node_pool": schedule.KeepNodePool,
+		}).
+		FirstOrCreate(&scheduleModel).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to save hibernation schedule", "clusterId", clusterID)
+	}
+
+	return nil
+}
@@ -48,7 +48,7 @@ func (s Store) GetCluster(ctx context.Context, id uint) (cluster.Cluster, error)
 		return cluster.Cluster{}, err
 	}
 
-	return clusterModelToEntity(clusterModel), nil
+	return s.clusterModelToEntityWithLabels(ctx, clusterModel)
 }
 
 func clusterModelToEntity(m *model.ClusterModel) cluster.Cluster {
@@ -64,6 +64,8 @@ func clusterModelToEntity(m *model.ClusterModel) cluster.Cluster {
 		Location:       m.Location,
 		SecretID:       brn.New(m.OrganizationId, brn.SecretResourceType, m.SecretId),
 		ConfigSecretID: brn.New(m.OrganizationId, brn.SecretResourceType, m.ConfigSecretId),
+
+		DeletionProtection: m.DeletionProtection,
 	}
 }
 
@@ -73,7 +75,20 @@ func (s Store) GetClusterByName(ctx context.Context, orgID uint, clusterName str
 		return cluster.Cluster{}, err
 	}
 
-	return clusterModelToEntity(clusterModel), nil
+	return s.clusterModelToEntityWithLabels(ctx, clusterModel)
+}
+
+func (s Store) clusterModelToEntityWithLabels(ctx context.Context, m *model.ClusterModel) (cluster.Cluster, error) {
+	c := clusterModelToEntity(m)
+
+	labels, err := s.GetLabels(ctx, m.ID)
+	if err != nil {
+		return cluster.Cluster{}, err
+	}
+
+	c.Labels = labels
+
+	return c, nil
 }
 
 // Exists returns true if the cluster exists in the store and is not deleted
@@ -143,6 +158,7 @@ func (s Store) SetStatus(ctx context.Context, id uint, status string, message st
 			ToStatus:          status,
 			ToStatusMessage:   message,
 		}
+		statusHistory.SetActor(ctx)
 		if err := s.db.Save(&statusHistory).Error; err != nil {
 			return errors.WrapIfWithDetails(err, "failed to save status history", "cluster_id", id)
 		}
@@ -155,3 +171,94 @@ func (s Store) SetStatus(ctx context.Context, id uint, status string, message st
 
 	return nil
 }
+
+// SetDeletionProtection enables or disables the deletion protection of a cluster.
+func (s Store) SetDeletionProtection(ctx context.Context, id uint, enabled bool) error {
+	clusterModel, err := s.findModel(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Model(&clusterModel).Update("deletion_protection", enabled).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to update cluster deletion protection", "clusterId", id)
+	}
+
+	return nil
+}
+
+// GetLabels returns the labels of a cluster.
+func (s Store) GetLabels(ctx context.Context, id uint) (map[string]string, error) {
+	var labelModels []clustermodel.LabelModel
+
+	err := s.db.Where("cluster_id = ?", id).Find(&labelModels).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to get cluster labels", "clusterId", id)
+	}
+
+	labels := make(map[string]string, len(labelModels))
+	for _, labelModel := range labelModels {
+		labels[labelModel.Key] = labelModel.Value
+	}
+
+	return labels, nil
+}
+
+// GetLabelsByOrganization returns the labels of every cluster in an organization indexed by cluster ID.
+func (s Store) GetLabelsByOrganization(ctx context.Context, orgID uint) (map[uint]map[string]string, error) {
+	var labelModels []clustermodel.LabelModel
+
+	err := s.db.
+		Joins("JOIN clusters ON clusters.id = cluster_labels.cluster_id").
+		Where("clusters.organization_id = ? AND clusters.deleted_at IS NULL", orgID).
+		Find(&labelModels).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to get cluster labels", "orgId", orgID)
+	}
+
+	labels := make(map[uint]map[string]string)
+	for _, labelModel := range labelModels {
+		if labels[labelModel.ClusterID] == nil {
+			labels[labelModel.ClusterID] = make(map[string]string)
+		}
+
+		labels[labelModel.ClusterID][labelModel.Key] = labelModel.Value
+	}
+
+	return labels, nil
+}
+
+// SetLabels replaces the labels of a cluster.
+func (s Store) SetLabels(ctx context.Context, id uint, labels map[string]string) error {
+	tx := s.db.Begin()
+	if err := tx.Error; err != nil {
+		return errors.WrapIfWithDetails(err, "failed to begin transaction", "clusterId", id)
+	}
+
+	err := tx.Where("cluster_id = ?", id).Delete(clustermodel.LabelModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete cluster labels", "clusterId", id)
+	}
+
+	for key, value := range labels {
+		labelModel := clustermodel.LabelModel{
+			ClusterID: id,
+			Key:       key,
+			Value:     value,
+		}
+
+		if err := tx.Create(&labelModel).Error; err != nil {
+			tx.Rollback()
+
+			return errors.WrapIfWithDetails(err, "failed to save cluster label", "clusterId", id, "label", key)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return errors.WrapIfWithDetails(err, "failed to commit cluster labels", "clusterId", id)
+	}
+
+	return nil
+}
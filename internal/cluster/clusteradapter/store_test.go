@@ -0,0 +1,74 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusteradapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
+)
+
+func TestStore_GetLabels_SavedWithCluster(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	err = db.AutoMigrate(&clustermodel.ClusterModel{}, &clustermodel.ScaleOptions{}, &clustermodel.LabelModel{}).Error
+	require.NoError(t, err)
+
+	model := clustermodel.ClusterModel{
+		Name:               "my-cluster",
+		OrganizationID:     1,
+		DeletionProtection: true,
+		Labels: clustermodel.NewLabelModels(map[string]string{
+			"env":  "prod",
+			"team": "ops",
+		}),
+	}
+
+	err = db.Save(&model).Error
+	require.NoError(t, err)
+
+	store := NewStore(db, nil)
+
+	labels, err := store.GetLabels(context.Background(), model.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"env": "prod", "team": "ops"}, labels)
+
+	err = store.SetLabels(context.Background(), model.ID, map[string]string{"env": "dev"})
+	require.NoError(t, err)
+
+	// saving the cluster again must not overwrite labels changed in the meantime
+	err = db.Save(&model).Error
+	require.NoError(t, err)
+
+	labels, err = store.GetLabels(context.Background(), model.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"env": "dev"}, labels)
+
+	var savedModel clustermodel.ClusterModel
+	err = db.First(&savedModel, model.ID).Error
+	require.NoError(t, err)
+
+	assert.True(t, savedModel.DeletionProtection)
+}
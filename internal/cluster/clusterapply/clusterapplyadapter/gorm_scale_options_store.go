@@ -0,0 +1,88 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapplyadapter
+
+import (
+	"context"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
+)
+
+type scaleOptionsStore struct {
+	db *gorm.DB
+}
+
+// NewScaleOptionsStore returns a new clusterapply.ScaleOptionsStore
+// that persists scale options in the cluster scale options table.
+func NewScaleOptionsStore(db *gorm.DB) clusterapply.ScaleOptionsStore {
+	return scaleOptionsStore{
+		db: db,
+	}
+}
+
+func (s scaleOptionsStore) GetScaleOptions(_ context.Context, clusterID uint) (clusterapply.ScaleOptions, error) {
+	var model clustermodel.ScaleOptions
+
+	err := s.db.Where(clustermodel.ScaleOptions{ClusterID: clusterID}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return clusterapply.ScaleOptions{}, nil
+	} else if err != nil {
+		return clusterapply.ScaleOptions{}, errors.WrapWithDetails(err, "failed to get scale options", "clusterId", clusterID)
+	}
+
+	scaleOptions := clusterapply.ScaleOptions{
+		Enabled:             model.Enabled,
+		DesiredCPU:          model.DesiredCpu,
+		DesiredMem:          model.DesiredMem,
+		DesiredGPU:          model.DesiredGpu,
+		OnDemandPct:         model.OnDemandPct,
+		KeepDesiredCapacity: model.KeepDesiredCapacity,
+	}
+
+	if model.Excludes != "" {
+		scaleOptions.Excludes = strings.Split(model.Excludes, clustermodel.InstanceTypeSeparator)
+	}
+
+	return scaleOptions, nil
+}
+
+func (s scaleOptionsStore) SetScaleOptions(_ context.Context, clusterID uint, scaleOptions clusterapply.ScaleOptions) error {
+	var model clustermodel.ScaleOptions
+
+	err := s.db.Where(clustermodel.ScaleOptions{ClusterID: clusterID}).First(&model).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return errors.WrapWithDetails(err, "failed to get scale options", "clusterId", clusterID)
+	}
+
+	model.ClusterID = clusterID
+	model.Enabled = scaleOptions.Enabled
+	model.DesiredCpu = scaleOptions.DesiredCPU
+	model.DesiredMem = scaleOptions.DesiredMem
+	model.DesiredGpu = scaleOptions.DesiredGPU
+	model.OnDemandPct = scaleOptions.OnDemandPct
+	model.Excludes = strings.Join(scaleOptions.Excludes, clustermodel.InstanceTypeSeparator)
+	model.KeepDesiredCapacity = scaleOptions.KeepDesiredCapacity
+
+	err = s.db.Save(&model).Error
+	if err != nil {
+		return errors.WrapWithDetails(err, "failed to save scale options", "clusterId", clusterID)
+	}
+
+	return nil
+}
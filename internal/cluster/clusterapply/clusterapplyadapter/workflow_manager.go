@@ -0,0 +1,113 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapplyadapter
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply/clusterapplyworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksadapter"
+)
+
+type manager struct {
+	workflowClient client.Client
+	enterprise     bool
+	getUserID      func(ctx context.Context) uint
+}
+
+// NewManager returns a new clusterapply.Manager
+// that executes cluster apply operations asynchronously via Cadence workflows.
+func NewManager(workflowClient client.Client, enterprise bool, getUserID func(ctx context.Context) uint) clusterapply.Manager {
+	return manager{
+		workflowClient: workflowClient,
+		enterprise:     enterprise,
+		getUserID:      getUserID,
+	}
+}
+
+func (m manager) ApplyCluster(ctx context.Context, c cluster.Cluster, operations []clusterapply.Operation) (string, error) {
+	taskList := "pipeline"
+	if m.enterprise {
+		taskList = "pipeline-enterprise"
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     taskList,
+		ExecutionStartToCloseTimeout: 30 * 24 * 60 * time.Minute,
+	}
+
+	input := clusterapplyworkflow.ApplyClusterWorkflowInput{
+		OrganizationID: c.OrganizationID,
+		ClusterID:      c.ID,
+		Operations:     make([]clusterapplyworkflow.ApplyClusterOperation, 0, len(operations)),
+	}
+
+	for _, operation := range operations {
+		var workflowOperation clusterapplyworkflow.ApplyClusterOperation
+
+		switch operation.Type {
+		case clusterapply.OperationCreateNodePool:
+			workflowOperation.CreateNodePool = &clusterworkflow.CreateNodePoolWorkflowInput{
+				ClusterID:   c.ID,
+				UserID:      m.getUserID(ctx),
+				RawNodePool: operation.NewNodePool,
+			}
+
+		case clusterapply.OperationUpdateNodePool:
+			updateInput := eksadapter.NewUpdateNodePoolWorkflowInput(c, operation.Target, operation.NodePoolUpdate, operation.ReplaceNodes)
+			workflowOperation.UpdateNodePool = &updateInput
+
+		case clusterapply.OperationDeleteNodePool:
+			workflowOperation.DeleteNodePool = &clusterworkflow.DeleteNodePoolWorkflowInput{
+				ClusterID:    c.ID,
+				NodePoolName: operation.Target,
+			}
+
+		case clusterapply.OperationUpdateScaleOptions:
+			workflowOperation.SetScaleOptions = &clusterapplyworkflow.SetScaleOptionsActivityInput{
+				ClusterID:    c.ID,
+				ScaleOptions: operation.ScaleOptions,
+			}
+
+		case clusterapply.OperationActivateIntegratedService,
+			clusterapply.OperationUpdateIntegratedService,
+			clusterapply.OperationDeactivateIntegratedService:
+			workflowOperation.IntegratedService = &clusterapplyworkflow.ApplyIntegratedServiceActivityInput{
+				ClusterID:             c.ID,
+				Operation:             operation.Type,
+				IntegratedServiceName: operation.Target,
+				Spec:                  operation.IntegratedServiceSpec,
+			}
+
+		default:
+			return "", errors.NewWithDetails("unsupported cluster apply operation", "operation", operation.Type)
+		}
+
+		input.Operations = append(input.Operations, workflowOperation)
+	}
+
+	e, err := m.workflowClient.StartWorkflow(ctx, workflowOptions, clusterapplyworkflow.ApplyClusterWorkflowName, input)
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", clusterapplyworkflow.ApplyClusterWorkflowName)
+	}
+
+	return e.ID, nil
+}
@@ -0,0 +1,147 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapplydriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+	apphttp "github.com/banzaicloud/pipeline/internal/platform/appkit/transport/http"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into an http.Handler.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	errorEncoder := kitxhttp.NewJSONProblemErrorResponseEncoder(apphttp.NewDefaultProblemConverter())
+
+	router.Methods(http.MethodPost).Path("/apply").Handler(kithttp.NewServer(
+		endpoints.ApplyCluster,
+		decodeApplyClusterHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeApplyClusterHTTPResponse, errorEncoder),
+		options...,
+	))
+}
+
+func decodeApplyClusterHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var request pipeline.ApplyClusterRequest
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	desiredState := clusterapply.DesiredState{
+		IntegratedServices: request.IntegratedServices,
+	}
+
+	if request.NodePools != nil {
+		desiredState.NodePools = make(map[string]clusterapply.NodePool, len(request.NodePools))
+
+		for name, nodePool := range request.NodePools {
+			taints := make([]cluster.Taint, 0, len(nodePool.Taints))
+			for _, taint := range nodePool.Taints {
+				taints = append(taints, cluster.Taint{
+					Key:    taint.Key,
+					Value:  taint.Value,
+					Effect: taint.Effect,
+				})
+			}
+
+			desiredState.NodePools[name] = clusterapply.NodePool{
+				Labels: nodePool.Labels,
+				Taints: taints,
+				Size:   int(nodePool.Size),
+				Autoscaling: eks.NodePoolAutoscaling{
+					Enabled: nodePool.Autoscaling.Enabled,
+					MinSize: int(nodePool.Autoscaling.MinSize),
+					MaxSize: int(nodePool.Autoscaling.MaxSize),
+				},
+				InstanceType: nodePool.InstanceType,
+				Image:        nodePool.Image,
+				SpotPrice:    nodePool.SpotPrice,
+				SubnetID:     nodePool.SubnetId,
+			}
+		}
+	}
+
+	if request.ScaleOptions != nil {
+		desiredState.ScaleOptions = &clusterapply.ScaleOptions{
+			Enabled:             request.ScaleOptions.Enabled,
+			DesiredCPU:          request.ScaleOptions.DesiredCpu,
+			DesiredMem:          request.ScaleOptions.DesiredMem,
+			DesiredGPU:          int(request.ScaleOptions.DesiredGpu),
+			OnDemandPct:         int(request.ScaleOptions.OnDemandPct),
+			Excludes:            request.ScaleOptions.Excludes,
+			KeepDesiredCapacity: request.ScaleOptions.KeepDesiredCapacity,
+		}
+	}
+
+	return ApplyClusterRequest{
+		ClusterID:    clusterID,
+		DesiredState: desiredState,
+		DryRun:       r.URL.Query().Get("dryRun") == "true",
+	}, nil
+}
+
+func encodeApplyClusterHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(ApplyClusterResponse)
+
+	apiResp := pipeline.ApplyClusterResponse{
+		ProcessId:  resp.Result.ProcessID,
+		DryRun:     resp.Result.DryRun,
+		Operations: make([]pipeline.ApplyClusterOperation, 0, len(resp.Result.Operations)),
+	}
+
+	for _, operation := range resp.Result.Operations {
+		apiResp.Operations = append(apiResp.Operations, pipeline.ApplyClusterOperation{
+			Type:    string(operation.Type),
+			Target:  operation.Target,
+			Changes: operation.Changes,
+		})
+	}
+
+	statusCode := http.StatusAccepted
+	if resp.Result.DryRun {
+		statusCode = http.StatusOK
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(apiResp, statusCode))
+}
+
+func getClusterID(req *http.Request) (uint, error) {
+	vars := mux.Vars(req)
+
+	clusterIDStr, ok := vars["clusterId"]
+	if !ok {
+		return 0, errors.New("cluster ID not found in path variables")
+	}
+
+	clusterID, err := strconv.ParseUint(clusterIDStr, 0, 0)
+	return uint(clusterID), errors.WrapIf(err, "invalid cluster ID format")
+}
@@ -0,0 +1,69 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapplyworkflow
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply"
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/pkg/cadence"
+)
+
+const ApplyIntegratedServiceActivityName = "cluster-apply-integrated-service"
+
+// ApplyIntegratedServiceActivity activates, updates or deactivates an integrated service of a cluster.
+type ApplyIntegratedServiceActivity struct {
+	integratedServices integratedservices.Service
+}
+
+func NewApplyIntegratedServiceActivity(integratedServices integratedservices.Service) ApplyIntegratedServiceActivity {
+	return ApplyIntegratedServiceActivity{
+		integratedServices: integratedServices,
+	}
+}
+
+type ApplyIntegratedServiceActivityInput struct {
+	ClusterID             uint
+	Operation             clusterapply.OperationType
+	IntegratedServiceName string
+	Spec                  integratedservices.IntegratedServiceSpec
+}
+
+func (a ApplyIntegratedServiceActivity) Execute(ctx context.Context, input ApplyIntegratedServiceActivityInput) error {
+	var err error
+
+	switch input.Operation {
+	case clusterapply.OperationActivateIntegratedService:
+		err = a.integratedServices.Activate(ctx, input.ClusterID, input.IntegratedServiceName, input.Spec)
+	case clusterapply.OperationUpdateIntegratedService:
+		err = a.integratedServices.Update(ctx, input.ClusterID, input.IntegratedServiceName, input.Spec)
+	case clusterapply.OperationDeactivateIntegratedService:
+		err = a.integratedServices.Deactivate(ctx, input.ClusterID, input.IntegratedServiceName)
+	default:
+		err = errors.NewWithDetails("unsupported integrated service operation", "operation", input.Operation)
+	}
+
+	if err != nil {
+		return cadence.WrapClientError(errors.WrapIfWithDetails(
+			err, "failed to apply integrated service",
+			"integratedService", input.IntegratedServiceName,
+		))
+	}
+
+	return nil
+}
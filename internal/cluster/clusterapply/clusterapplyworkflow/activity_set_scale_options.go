@@ -0,0 +1,47 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapplyworkflow
+
+import (
+	"context"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterapply"
+	"github.com/banzaicloud/pipeline/pkg/cadence"
+)
+
+const SetScaleOptionsActivityName = "cluster-apply-set-scale-options"
+
+type SetScaleOptionsActivity struct {
+	store clusterapply.ScaleOptionsStore
+}
+
+func NewSetScaleOptionsActivity(store clusterapply.ScaleOptionsStore) SetScaleOptionsActivity {
+	return SetScaleOptionsActivity{
+		store: store,
+	}
+}
+
+type SetScaleOptionsActivityInput struct {
+	ClusterID    uint
+	ScaleOptions clusterapply.ScaleOptions
+}
+
+func (a SetScaleOptionsActivity) Execute(ctx context.Context, input SetScaleOptionsActivityInput) error {
+	err := a.store.SetScaleOptions(ctx, input.ClusterID, input.ScaleOptions)
+	if err != nil {
+		return cadence.WrapClientError(err)
+	}
+
+	return nil
+}
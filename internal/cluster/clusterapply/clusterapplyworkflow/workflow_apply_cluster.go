@@ -0,0 +1,170 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapplyworkflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksworkflow"
+	"github.com/banzaicloud/pipeline/pkg/sdk/brn"
+	"github.com/banzaicloud/pipeline/pkg/sdk/cadence/lib/pipeline/processlog"
+)
+
+const ApplyClusterWorkflowName = "cluster-apply"
+
+// ApplyClusterWorkflow executes the operations required to reach the desired state of a cluster.
+//
+// Operations are executed one by one (in the order of the input) as child workflows or activities.
+type ApplyClusterWorkflow struct {
+	processLogger processlog.ProcessLogger
+}
+
+// NewApplyClusterWorkflow returns a new ApplyClusterWorkflow.
+func NewApplyClusterWorkflow(processLogger processlog.ProcessLogger) ApplyClusterWorkflow {
+	return ApplyClusterWorkflow{
+		processLogger: processLogger,
+	}
+}
+
+type ApplyClusterWorkflowInput struct {
+	OrganizationID uint
+	ClusterID      uint
+
+	Operations []ApplyClusterOperation
+}
+
+// ApplyClusterOperation describes a single operation of a cluster apply.
+// Exactly one of the operation inputs is set.
+type ApplyClusterOperation struct {
+	CreateNodePool    *clusterworkflow.CreateNodePoolWorkflowInput
+	UpdateNodePool    *eksworkflow.UpdateNodePoolWorkflowInput
+	DeleteNodePool    *clusterworkflow.DeleteNodePoolWorkflowInput
+	SetScaleOptions   *SetScaleOptionsActivityInput
+	IntegratedService *ApplyIntegratedServiceActivityInput
+}
+
+func (w ApplyClusterWorkflow) Register() {
+	workflow.RegisterWithOptions(w.Execute, workflow.RegisterOptions{Name: ApplyClusterWorkflowName})
+}
+
+func (w ApplyClusterWorkflow) Execute(ctx workflow.Context, input ApplyClusterWorkflowInput) (err error) {
+	activityOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: time.Duration(workflow.GetInfo(ctx).ExecutionStartToCloseTimeoutSeconds) * time.Second,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	clusterID := brn.New(input.OrganizationID, brn.ClusterResourceType, fmt.Sprint(input.ClusterID))
+
+	process := w.processLogger.StartProcess(ctx, clusterID.String())
+	defer func() {
+		process.Finish(ctx, err)
+	}()
+	defer func() {
+		status := cluster.Running
+		statusMessage := cluster.RunningMessage
+
+		if err != nil {
+			if cadence.IsCanceledError(err) {
+				ctx, _ = workflow.NewDisconnectedContext(ctx)
+			}
+
+			status = cluster.Warning
+			statusMessage = fmt.Sprintf("failed to apply desired state: %s", err.Error())
+		}
+
+		_ = setClusterStatus(ctx, input.ClusterID, status, statusMessage)
+	}()
+
+	childWorkflowOptions := workflow.ChildWorkflowOptions{
+		ExecutionStartToCloseTimeout: time.Duration(workflow.GetInfo(ctx).ExecutionStartToCloseTimeoutSeconds) * time.Second,
+		TaskStartToCloseTimeout:      time.Minute,
+	}
+	childCtx := workflow.WithChildOptions(ctx, childWorkflowOptions)
+
+	for _, operation := range input.Operations {
+		switch {
+		case operation.CreateNodePool != nil:
+			processActivity := process.StartActivity(ctx, clusterworkflow.CreateNodePoolWorkflowName)
+			err = workflow.ExecuteChildWorkflow(childCtx, clusterworkflow.CreateNodePoolWorkflowName, *operation.CreateNodePool).Get(ctx, nil)
+			processActivity.Finish(ctx, err)
+
+		case operation.UpdateNodePool != nil:
+			// The update workflow records its own (child) process
+			err = workflow.ExecuteChildWorkflow(childCtx, eksworkflow.UpdateNodePoolWorkflowName, *operation.UpdateNodePool).Get(ctx, nil)
+
+		case operation.DeleteNodePool != nil:
+			processActivity := process.StartActivity(ctx, clusterworkflow.DeleteNodePoolWorkflowName)
+			err = workflow.ExecuteChildWorkflow(childCtx, clusterworkflow.DeleteNodePoolWorkflowName, *operation.DeleteNodePool).Get(ctx, nil)
+			processActivity.Finish(ctx, err)
+
+		case operation.SetScaleOptions != nil:
+			activityOptions := activityOptions
+			activityOptions.StartToCloseTimeout = 30 * time.Second
+			activityOptions.RetryPolicy = &cadence.RetryPolicy{
+				InitialInterval:    10 * time.Second,
+				BackoffCoefficient: 1.01,
+				MaximumInterval:    10 * time.Minute,
+				MaximumAttempts:    30,
+			}
+
+			processActivity := process.StartActivity(ctx, SetScaleOptionsActivityName)
+			err = workflow.ExecuteActivity(
+				workflow.WithActivityOptions(ctx, activityOptions),
+				SetScaleOptionsActivityName,
+				*operation.SetScaleOptions,
+			).Get(ctx, nil)
+			processActivity.Finish(ctx, err)
+
+		case operation.IntegratedService != nil:
+			activityOptions := activityOptions
+			activityOptions.StartToCloseTimeout = time.Minute
+
+			// Integrated service operations only start their own jobs, so they are not waited for
+			processActivity := process.StartActivity(ctx, ApplyIntegratedServiceActivityName)
+			err = workflow.ExecuteActivity(
+				workflow.WithActivityOptions(ctx, activityOptions),
+				ApplyIntegratedServiceActivityName,
+				*operation.IntegratedService,
+			).Get(ctx, nil)
+			processActivity.Finish(ctx, err)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+func setClusterStatus(ctx workflow.Context, clusterID uint, status, statusMessage string) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    2 * time.Minute,
+		WaitForCancellation:    true,
+	})
+
+	return workflow.ExecuteActivity(ctx, clusterworkflow.SetClusterStatusActivityName, clusterworkflow.SetClusterStatusActivityInput{
+		ClusterID:     clusterID,
+		Status:        status,
+		StatusMessage: statusMessage,
+	}).Get(ctx, nil)
+}
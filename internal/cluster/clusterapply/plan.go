@@ -0,0 +1,464 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapply
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/pkg/cloud"
+)
+
+func (s service) planNodePools(
+	ctx context.Context,
+	c cluster.Cluster,
+	desiredNodePools map[string]NodePool,
+	dryRun bool,
+) ([]Operation, error) {
+	if c.Cloud != cloud.Amazon || c.Distribution != "eks" {
+		return nil, errors.WithStack(cluster.NotSupportedDistributionError{
+			ID:           c.ID,
+			Cloud:        c.Cloud,
+			Distribution: c.Distribution,
+
+			Message: "applying node pools is not supported for this distribution yet",
+		})
+	}
+
+	names, err := s.nodePools.ListNodePoolNames(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentNodePools := make(map[string]eks.NodePool, len(names))
+	for _, name := range names {
+		nodePool, err := s.nodePools.GetNodePool(ctx, c.ID, name)
+		if err != nil {
+			return nil, err
+		}
+
+		currentNodePools[name] = nodePool
+	}
+
+	desiredNames := make([]string, 0, len(desiredNodePools))
+	for name := range desiredNodePools {
+		desiredNames = append(desiredNames, name)
+	}
+	sort.Strings(desiredNames)
+
+	var operations []Operation
+
+	// The policy limits apply to the node pools of the cluster after every operation is executed
+	nodePoolSpecs := make([]clusterpolicy.NodePoolSpec, 0, len(desiredNames))
+
+	for _, name := range desiredNames {
+		desiredNodePool := desiredNodePools[name]
+
+		currentNodePool, ok := currentNodePools[name]
+		if !ok {
+			rawNodePool := desiredNodePool.toNewRawNodePool(name)
+
+			err := s.nodePoolValidator.ValidateNew(ctx, c, rawNodePool)
+			if err != nil {
+				return nil, err
+			}
+
+			nodePoolSpec, err := clusterpolicy.NodePoolSpecFromNew(rawNodePool)
+			if err != nil {
+				return nil, err
+			}
+
+			nodePoolSpecs = append(nodePoolSpecs, nodePoolSpec)
+
+			// Processing is skipped in dry-run mode, because it may depend on external services
+			if !dryRun {
+				rawNodePool, err = s.nodePoolProcessor.ProcessNew(ctx, c, rawNodePool)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			operations = append(operations, Operation{
+				Type:        OperationCreateNodePool,
+				Target:      name,
+				NewNodePool: rawNodePool,
+			})
+
+			continue
+		}
+
+		nodePoolUpdate := desiredNodePool.toNodePoolUpdate()
+
+		err := nodePoolUpdate.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		if validator, ok := s.nodePoolValidator.(cluster.NodePoolUpdateValidator); ok {
+			err := validator.ValidateUpdate(ctx, c, name, desiredNodePool.toRawNodePoolUpdate())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		nodePoolSpecs = append(nodePoolSpecs, nodePoolSpecFromNodePool(currentNodePool.Apply(nodePoolUpdate)))
+
+		// Labels are replaced as a whole, so common node pool labels have to be added again
+		if len(nodePoolUpdate.Labels) > 0 {
+			nodePoolUpdate.Labels, err = s.nodePoolLabelSource.GetLabels(ctx, c, currentNodePool.Apply(nodePoolUpdate))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		changes := nodePoolChanges(currentNodePool, nodePoolUpdate)
+		if len(changes) == 0 {
+			continue
+		}
+
+		nodePoolUpdate.Options = nodePoolUpdate.Options.WithDefaults()
+
+		operations = append(operations, Operation{
+			Type:           OperationUpdateNodePool,
+			Target:         name,
+			Changes:        changes,
+			NodePoolUpdate: nodePoolUpdate,
+			ReplaceNodes:   len(nodePoolUpdate.NodeReplacementChanges(currentNodePool)) > 0,
+		})
+	}
+
+	err = s.policies.ValidateClusterUpdate(ctx, c.OrganizationID, clusterpolicy.ClusterSpec{NodePools: nodePoolSpecs})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if _, ok := desiredNodePools[name]; ok {
+			continue
+		}
+
+		operations = append(operations, Operation{
+			Type:   OperationDeleteNodePool,
+			Target: name,
+		})
+	}
+
+	return operations, nil
+}
+
+func (n NodePool) toNewRawNodePool(name string) cluster.NewRawNodePool {
+	rawNodePool := cluster.NewRawNodePool{
+		"name": name,
+		"size": n.Size,
+		"autoscaling": map[string]interface{}{
+			"enabled": n.Autoscaling.Enabled,
+			"minSize": n.Autoscaling.MinSize,
+			"maxSize": n.Autoscaling.MaxSize,
+		},
+		"instanceType": n.InstanceType,
+		"image":        n.Image,
+		"spotPrice":    n.SpotPrice,
+		"subnetId":     n.SubnetID,
+	}
+
+	if len(n.Labels) > 0 {
+		labels := make(map[string]interface{}, len(n.Labels))
+		for key, value := range n.Labels {
+			labels[key] = value
+		}
+
+		rawNodePool["labels"] = labels
+	}
+
+	if len(n.Taints) > 0 {
+		taints := make([]interface{}, 0, len(n.Taints))
+		for _, taint := range n.Taints {
+			taints = append(taints, map[string]interface{}{
+				"key":    taint.Key,
+				"value":  taint.Value,
+				"effect": taint.Effect,
+			})
+		}
+
+		rawNodePool["taints"] = taints
+	}
+
+	return rawNodePool
+}
+
+func (n NodePool) toRawNodePoolUpdate() cluster.RawNodePoolUpdate {
+	rawNodePoolUpdate := cluster.RawNodePoolUpdate{
+		"size": n.Size,
+		"autoscaling": map[string]interface{}{
+			"enabled": n.Autoscaling.Enabled,
+			"minSize": n.Autoscaling.MinSize,
+			"maxSize": n.Autoscaling.MaxSize,
+		},
+		"instanceType": n.InstanceType,
+		"image":        n.Image,
+		"spotPrice":    n.SpotPrice,
+	}
+
+	if len(n.Labels) > 0 {
+		labels := make(map[string]interface{}, len(n.Labels))
+		for key, value := range n.Labels {
+			labels[key] = value
+		}
+
+		rawNodePoolUpdate["labels"] = labels
+	}
+
+	return rawNodePoolUpdate
+}
+
+func nodePoolSpecFromNodePool(nodePool eks.NodePool) clusterpolicy.NodePoolSpec {
+	maxNodes := nodePool.Size
+	if nodePool.Autoscaling.Enabled && nodePool.Autoscaling.MaxSize > maxNodes {
+		maxNodes = nodePool.Autoscaling.MaxSize
+	}
+
+	return clusterpolicy.NodePoolSpec{
+		Name:         nodePool.Name,
+		InstanceType: nodePool.InstanceType,
+		MaxNodes:     maxNodes,
+	}
+}
+
+func (n NodePool) toNodePoolUpdate() eks.NodePoolUpdate {
+	return eks.NodePoolUpdate{
+		Size:         n.Size,
+		Labels:       n.Labels,
+		Autoscaling:  n.Autoscaling,
+		InstanceType: n.InstanceType,
+		Image:        n.Image,
+		SpotPrice:    n.SpotPrice,
+	}
+}
+
+// nodePoolChanges returns the node pool properties changed by an update.
+//
+// Empty properties of the update are considered unchanged.
+// Taints are not tracked by existing node pools, so they are only applied when creating a node pool.
+func nodePoolChanges(current eks.NodePool, update eks.NodePoolUpdate) []string {
+	var changes []string
+
+	if update.Autoscaling != current.Autoscaling {
+		changes = append(changes, "autoscaling")
+	}
+
+	if !update.Autoscaling.Enabled && update.Size > 0 && update.Size != current.Size {
+		changes = append(changes, "size")
+	}
+
+	if len(update.Labels) > 0 && !labelsEqual(update.Labels, current.Labels) {
+		changes = append(changes, "labels")
+	}
+
+	if update.InstanceType != "" && update.InstanceType != current.InstanceType {
+		changes = append(changes, "instanceType")
+	}
+
+	if update.Image != "" && update.Image != current.Image {
+		changes = append(changes, "image")
+	}
+
+	if update.SpotPrice != "" && !isSameSpotPrice(update.SpotPrice, current.SpotPrice) {
+		changes = append(changes, "spotPrice")
+	}
+
+	return changes
+}
+
+func labelsEqual(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		if v, ok := b[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isSameSpotPrice(a string, b string) bool {
+	priceA, _ := strconv.ParseFloat(a, 64)
+	priceB, _ := strconv.ParseFloat(b, 64)
+
+	return priceA == priceB
+}
+
+func planScaleOptions(current ScaleOptions, desired ScaleOptions) []Operation {
+	var changes []string
+
+	if desired.Enabled != current.Enabled {
+		changes = append(changes, "enabled")
+	}
+
+	if desired.DesiredCPU != current.DesiredCPU {
+		changes = append(changes, "desiredCpu")
+	}
+
+	if desired.DesiredMem != current.DesiredMem {
+		changes = append(changes, "desiredMem")
+	}
+
+	if desired.DesiredGPU != current.DesiredGPU {
+		changes = append(changes, "desiredGpu")
+	}
+
+	if desired.OnDemandPct != current.OnDemandPct {
+		changes = append(changes, "onDemandPct")
+	}
+
+	if !stringsEqual(desired.Excludes, current.Excludes) {
+		changes = append(changes, "excludes")
+	}
+
+	if desired.KeepDesiredCapacity != current.KeepDesiredCapacity {
+		changes = append(changes, "keepDesiredCapacity")
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return []Operation{
+		{
+			Type:         OperationUpdateScaleOptions,
+			Changes:      changes,
+			ScaleOptions: desired,
+		},
+	}
+}
+
+func stringsEqual(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s service) planIntegratedServices(
+	ctx context.Context,
+	clusterID uint,
+	desiredServices map[string]integratedservices.IntegratedServiceSpec,
+) ([]Operation, error) {
+	currentServices, err := s.integratedServices.List(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	activeServices := make(map[string]bool, len(currentServices))
+	for _, currentService := range currentServices {
+		if currentService.Status == integratedservices.IntegratedServiceStatusInactive {
+			continue
+		}
+
+		activeServices[currentService.Name] = true
+	}
+
+	desiredNames := make([]string, 0, len(desiredServices))
+	for name := range desiredServices {
+		desiredNames = append(desiredNames, name)
+	}
+	sort.Strings(desiredNames)
+
+	var operations []Operation
+
+	for _, name := range desiredNames {
+		desiredSpec := desiredServices[name]
+
+		if !activeServices[name] {
+			operations = append(operations, Operation{
+				Type:                  OperationActivateIntegratedService,
+				Target:                name,
+				IntegratedServiceSpec: desiredSpec,
+			})
+
+			continue
+		}
+
+		details, err := s.integratedServices.Details(ctx, clusterID, name)
+		if err != nil {
+			return nil, err
+		}
+
+		equal, err := specsEqual(details.Spec, desiredSpec)
+		if err != nil {
+			return nil, err
+		}
+
+		if equal {
+			continue
+		}
+
+		operations = append(operations, Operation{
+			Type:                  OperationUpdateIntegratedService,
+			Target:                name,
+			Changes:               []string{"spec"},
+			IntegratedServiceSpec: desiredSpec,
+		})
+	}
+
+	for _, currentService := range currentServices {
+		if !activeServices[currentService.Name] {
+			continue
+		}
+
+		if _, ok := desiredServices[currentService.Name]; ok {
+			continue
+		}
+
+		operations = append(operations, Operation{
+			Type:   OperationDeactivateIntegratedService,
+			Target: currentService.Name,
+		})
+	}
+
+	return operations, nil
+}
+
+// specsEqual compares integrated service specs by their JSON representation,
+// so that stored and requested specs are compared regardless of their Go types.
+func specsEqual(a integratedservices.IntegratedServiceSpec, b integratedservices.IntegratedServiceSpec) (bool, error) {
+	rawA, err := json.Marshal(a)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to marshal integrated service spec")
+	}
+
+	rawB, err := json.Marshal(b)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to marshal integrated service spec")
+	}
+
+	return string(rawA) == string(rawB), nil
+}
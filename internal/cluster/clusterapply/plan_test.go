@@ -0,0 +1,248 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/pkg/cloud"
+)
+
+type testNodePoolStore struct {
+	eks.NodePoolStore
+
+	nodePools map[string]eks.NodePool
+}
+
+func (s testNodePoolStore) ListNodePoolNames(_ context.Context, _ uint) ([]string, error) {
+	names := make([]string, 0, len(s.nodePools))
+	for name := range s.nodePools {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func (s testNodePoolStore) GetNodePool(_ context.Context, _ uint, nodePoolName string) (eks.NodePool, error) {
+	return s.nodePools[nodePoolName], nil
+}
+
+type testNodePoolValidator struct {
+	updates map[string]cluster.RawNodePoolUpdate
+}
+
+func (v *testNodePoolValidator) ValidateNew(_ context.Context, _ cluster.Cluster, _ cluster.NewRawNodePool) error {
+	return nil
+}
+
+func (v *testNodePoolValidator) ValidateUpdate(
+	_ context.Context,
+	_ cluster.Cluster,
+	nodePoolName string,
+	rawNodePoolUpdate cluster.RawNodePoolUpdate,
+) error {
+	v.updates[nodePoolName] = rawNodePoolUpdate
+
+	return nil
+}
+
+type testPolicyValidator struct {
+	err  error
+	spec clusterpolicy.ClusterSpec
+}
+
+func (v *testPolicyValidator) ValidateClusterUpdate(_ context.Context, _ uint, spec clusterpolicy.ClusterSpec) error {
+	v.spec = spec
+
+	return v.err
+}
+
+func TestService_PlanNodePools_Policies(t *testing.T) {
+	ctx := context.Background()
+	c := cluster.Cluster{ID: 1, OrganizationID: 2, Cloud: cloud.Amazon, Distribution: "eks"}
+
+	nodePoolValidator := &testNodePoolValidator{updates: make(map[string]cluster.RawNodePoolUpdate)}
+	policies := &testPolicyValidator{
+		err: cluster.NewValidationError("cluster violates organization policy", []string{"too many nodes"}),
+	}
+
+	s := service{
+		nodePools: testNodePoolStore{nodePools: map[string]eks.NodePool{
+			"pool0": {Name: "pool0", Size: 2, InstanceType: "t2.medium"},
+			"pool1": {Name: "pool1", Size: 5, InstanceType: "t2.medium"},
+		}},
+		nodePoolValidator: nodePoolValidator,
+		policies:          policies,
+	}
+
+	_, err := s.planNodePools(ctx, c, map[string]NodePool{
+		"pool0": {
+			Size:        2,
+			Autoscaling: eks.NodePoolAutoscaling{Enabled: true, MinSize: 2, MaxSize: 6},
+		},
+		"pool2": {
+			Size:         3,
+			InstanceType: "t2.large",
+		},
+	}, true)
+	require.Error(t, err)
+	assert.IsType(t, cluster.ValidationError{}, err)
+
+	assert.Contains(t, nodePoolValidator.updates, "pool0")
+	assert.Equal(
+		t,
+		clusterpolicy.ClusterSpec{
+			NodePools: []clusterpolicy.NodePoolSpec{
+				{Name: "pool0", InstanceType: "t2.medium", MaxNodes: 6},
+				{Name: "pool2", InstanceType: "t2.large", MaxNodes: 3},
+			},
+		},
+		policies.spec,
+	)
+}
+
+func TestNodePoolChanges(t *testing.T) {
+	current := eks.NodePool{
+		Name:         "pool0",
+		Labels:       map[string]string{"key": "value"},
+		Size:         2,
+		InstanceType: "m4.xlarge",
+		Image:        "ami-123",
+		SpotPrice:    "0.2",
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		update := eks.NodePoolUpdate{
+			Size:         2,
+			Labels:       map[string]string{"key": "value"},
+			InstanceType: "m4.xlarge",
+			SpotPrice:    "0.20",
+		}
+
+		assert.Empty(t, nodePoolChanges(current, update))
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		update := eks.NodePoolUpdate{
+			Size:         3,
+			Labels:       map[string]string{"key": "other"},
+			InstanceType: "m5.xlarge",
+			Image:        "ami-456",
+			SpotPrice:    "0.3",
+		}
+
+		assert.Equal(
+			t,
+			[]string{"size", "labels", "instanceType", "image", "spotPrice"},
+			nodePoolChanges(current, update),
+		)
+	})
+
+	t.Run("EnableAutoscaling", func(t *testing.T) {
+		update := eks.NodePoolUpdate{
+			Size:        2,
+			Autoscaling: eks.NodePoolAutoscaling{Enabled: true, MinSize: 1, MaxSize: 3},
+		}
+
+		assert.Equal(t, []string{"autoscaling"}, nodePoolChanges(current, update))
+	})
+}
+
+func TestPlanScaleOptions(t *testing.T) {
+	current := ScaleOptions{
+		Enabled:    true,
+		DesiredCPU: 4,
+		DesiredMem: 8,
+		Excludes:   []string{"t2.micro"},
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		assert.Empty(t, planScaleOptions(current, current))
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		desired := current
+		desired.DesiredCPU = 8
+		desired.Excludes = nil
+
+		operations := planScaleOptions(current, desired)
+
+		require.Len(t, operations, 1)
+		assert.Equal(t, OperationUpdateScaleOptions, operations[0].Type)
+		assert.Equal(t, []string{"desiredCpu", "excludes"}, operations[0].Changes)
+		assert.Equal(t, desired, operations[0].ScaleOptions)
+	})
+}
+
+func TestService_PlanIntegratedServices(t *testing.T) {
+	ctx := context.Background()
+	const clusterID = uint(1)
+
+	integratedServices := new(integratedservices.MockService)
+	integratedServices.On("List", ctx, clusterID).Return([]integratedservices.IntegratedService{
+		{Name: "dns", Status: integratedservices.IntegratedServiceStatusActive},
+		{Name: "logging", Status: integratedservices.IntegratedServiceStatusActive},
+		{Name: "monitoring", Status: integratedservices.IntegratedServiceStatusActive},
+		{Name: "vault", Status: integratedservices.IntegratedServiceStatusInactive},
+	}, nil)
+	integratedServices.On("Details", ctx, clusterID, "dns").Return(integratedservices.IntegratedService{
+		Name: "dns",
+		Spec: integratedservices.IntegratedServiceSpec{"replicas": float64(1)},
+	}, nil)
+	integratedServices.On("Details", ctx, clusterID, "monitoring").Return(integratedservices.IntegratedService{
+		Name: "monitoring",
+		Spec: integratedservices.IntegratedServiceSpec{"enabled": true},
+	}, nil)
+
+	s := service{integratedServices: integratedServices}
+
+	operations, err := s.planIntegratedServices(ctx, clusterID, map[string]integratedservices.IntegratedServiceSpec{
+		"dns":        {"replicas": 1},
+		"monitoring": {"enabled": false},
+		"vault":      {},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]Operation{
+			{
+				Type:                  OperationUpdateIntegratedService,
+				Target:                "monitoring",
+				Changes:               []string{"spec"},
+				IntegratedServiceSpec: integratedservices.IntegratedServiceSpec{"enabled": false},
+			},
+			{
+				Type:                  OperationActivateIntegratedService,
+				Target:                "vault",
+				IntegratedServiceSpec: integratedservices.IntegratedServiceSpec{},
+			},
+			{
+				Type:   OperationDeactivateIntegratedService,
+				Target: "logging",
+			},
+		},
+		operations,
+	)
+
+	integratedServices.AssertExpectations(t)
+}
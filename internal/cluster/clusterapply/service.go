@@ -0,0 +1,240 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterapply
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+)
+
+// DesiredState describes the desired state of a cluster.
+//
+// Every section is optional: omitted (nil) sections leave the corresponding part of the cluster untouched.
+type DesiredState struct {
+	// NodePools is the complete set of node pools of the cluster keyed by node pool name.
+	// Existing node pools missing from the set are deleted.
+	NodePools map[string]NodePool
+
+	// ScaleOptions are the desired cluster scaling options.
+	ScaleOptions *ScaleOptions
+
+	// IntegratedServices is the complete set of active integrated services keyed by service name.
+	// Active integrated services missing from the set are deactivated.
+	IntegratedServices map[string]integratedservices.IntegratedServiceSpec
+}
+
+// NodePool describes the desired state of a node pool.
+type NodePool struct {
+	Labels       map[string]string
+	Taints       []cluster.Taint
+	Size         int
+	Autoscaling  eks.NodePoolAutoscaling
+	InstanceType string
+	Image        string
+	SpotPrice    string
+	SubnetID     string
+}
+
+// ScaleOptions describes the cluster scaling options.
+type ScaleOptions struct {
+	Enabled             bool
+	DesiredCPU          float64
+	DesiredMem          float64
+	DesiredGPU          int
+	OnDemandPct         int
+	Excludes            []string
+	KeepDesiredCapacity bool
+}
+
+// OperationType identifies an operation required to reach the desired state of a cluster.
+type OperationType string
+
+// Supported operation types.
+const (
+	OperationCreateNodePool              OperationType = "createNodePool"
+	OperationUpdateNodePool              OperationType = "updateNodePool"
+	OperationDeleteNodePool              OperationType = "deleteNodePool"
+	OperationUpdateScaleOptions          OperationType = "updateScaleOptions"
+	OperationActivateIntegratedService   OperationType = "activateIntegratedService"
+	OperationUpdateIntegratedService     OperationType = "updateIntegratedService"
+	OperationDeactivateIntegratedService OperationType = "deactivateIntegratedService"
+)
+
+// Operation is a single change required to reach the desired state of a cluster.
+type Operation struct {
+	Type OperationType
+
+	// Target is the name of the node pool or integrated service the operation applies to.
+	Target string
+
+	// Changes lists the changed properties of an updated resource.
+	Changes []string
+
+	NewNodePool           cluster.NewRawNodePool
+	NodePoolUpdate        eks.NodePoolUpdate
+	ReplaceNodes          bool
+	ScaleOptions          ScaleOptions
+	IntegratedServiceSpec integratedservices.IntegratedServiceSpec
+}
+
+// ApplyResult describes the outcome of applying a desired state.
+type ApplyResult struct {
+	// ProcessID is the ID of the process executing the operations (empty in dry-run mode or when there is nothing to do).
+	ProcessID  string
+	DryRun     bool
+	Operations []Operation
+}
+
+// +kit:endpoint:errorStrategy=service
+
+// Service applies declarative desired states to clusters.
+type Service interface {
+	// ApplyCluster computes the operations required to reach the desired state of a cluster and executes them.
+	// In dry-run mode the operations are only returned.
+	ApplyCluster(ctx context.Context, clusterID uint, desiredState DesiredState, dryRun bool) (result ApplyResult, err error)
+}
+
+// NewService returns a new Service.
+func NewService(
+	clusters cluster.Store,
+	nodePools eks.NodePoolStore,
+	nodePoolValidator cluster.NodePoolValidator,
+	policies PolicyValidator,
+	nodePoolProcessor cluster.NodePoolProcessor,
+	nodePoolLabelSource cluster.NodePoolLabelSource,
+	scaleOptions ScaleOptionsStore,
+	integratedServices integratedservices.Service,
+	manager Manager,
+) Service {
+	return service{
+		clusters:            clusters,
+		nodePools:           nodePools,
+		nodePoolValidator:   nodePoolValidator,
+		policies:            policies,
+		nodePoolProcessor:   nodePoolProcessor,
+		nodePoolLabelSource: nodePoolLabelSource,
+		scaleOptions:        scaleOptions,
+		integratedServices:  integratedServices,
+		manager:             manager,
+	}
+}
+
+type service struct {
+	clusters            cluster.Store
+	nodePools           eks.NodePoolStore
+	nodePoolValidator   cluster.NodePoolValidator
+	policies            PolicyValidator
+	nodePoolProcessor   cluster.NodePoolProcessor
+	nodePoolLabelSource cluster.NodePoolLabelSource
+	scaleOptions        ScaleOptionsStore
+	integratedServices  integratedservices.Service
+	manager             Manager
+}
+
+// PolicyValidator checks the desired state of a cluster against organization policies.
+type PolicyValidator interface {
+	// ValidateClusterUpdate checks the desired state of an updated cluster against the policy of an organization.
+	ValidateClusterUpdate(ctx context.Context, organizationID uint, spec clusterpolicy.ClusterSpec) error
+}
+
+// ScaleOptionsStore provides an interface for cluster scale options persistence.
+type ScaleOptionsStore interface {
+	// GetScaleOptions returns the scale options of a cluster.
+	// A zero value is returned if the cluster has no scale options.
+	GetScaleOptions(ctx context.Context, clusterID uint) (ScaleOptions, error)
+
+	// SetScaleOptions saves the scale options of a cluster.
+	SetScaleOptions(ctx context.Context, clusterID uint, scaleOptions ScaleOptions) error
+}
+
+// Manager executes cluster operations.
+type Manager interface {
+	// ApplyCluster executes the operations as a single process and returns its ID.
+	ApplyCluster(ctx context.Context, c cluster.Cluster, operations []Operation) (string, error)
+}
+
+func (s service) ApplyCluster(
+	ctx context.Context,
+	clusterID uint,
+	desiredState DesiredState,
+	dryRun bool,
+) (ApplyResult, error) {
+	c, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	if !dryRun && c.Status != cluster.Running && c.Status != cluster.Warning {
+		return ApplyResult{}, errors.WithStack(cluster.NotReadyError{ID: c.ID})
+	}
+
+	var operations []Operation
+
+	if desiredState.NodePools != nil {
+		ops, err := s.planNodePools(ctx, c, desiredState.NodePools, dryRun)
+		if err != nil {
+			return ApplyResult{}, err
+		}
+
+		operations = append(operations, ops...)
+	}
+
+	if desiredState.ScaleOptions != nil {
+		current, err := s.scaleOptions.GetScaleOptions(ctx, clusterID)
+		if err != nil {
+			return ApplyResult{}, err
+		}
+
+		operations = append(operations, planScaleOptions(current, *desiredState.ScaleOptions)...)
+	}
+
+	if desiredState.IntegratedServices != nil {
+		ops, err := s.planIntegratedServices(ctx, clusterID, desiredState.IntegratedServices)
+		if err != nil {
+			return ApplyResult{}, err
+		}
+
+		operations = append(operations, ops...)
+	}
+
+	result := ApplyResult{
+		DryRun:     dryRun,
+		Operations: operations,
+	}
+
+	if dryRun || len(operations) == 0 {
+		return result, nil
+	}
+
+	err = s.clusters.SetStatus(ctx, clusterID, cluster.Updating, "applying desired state")
+	if err != nil {
+		return result, err
+	}
+
+	result.ProcessID, err = s.manager.ApplyCluster(ctx, c, operations)
+	if err != nil {
+		// Nothing has been changed, so the cluster returns to its previous status
+		statusErr := s.clusters.SetStatus(ctx, clusterID, c.Status, c.StatusMessage)
+
+		return result, errors.Combine(err, statusErr)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapply
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+type testClusterStore struct {
+	cluster.Store
+
+	cluster  cluster.Cluster
+	statuses []string
+}
+
+func (s *testClusterStore) GetCluster(_ context.Context, _ uint) (cluster.Cluster, error) {
+	return s.cluster, nil
+}
+
+func (s *testClusterStore) SetStatus(_ context.Context, _ uint, status string, _ string) error {
+	s.statuses = append(s.statuses, status)
+
+	return nil
+}
+
+type testManager struct {
+	err        error
+	operations []Operation
+}
+
+func (m *testManager) ApplyCluster(_ context.Context, _ cluster.Cluster, operations []Operation) (string, error) {
+	m.operations = operations
+
+	return "", m.err
+}
+
+func TestService_ApplyCluster_ResetsStatus(t *testing.T) {
+	clusters := &testClusterStore{
+		cluster: cluster.Cluster{ID: 1, Status: cluster.Warning, StatusMessage: "something went wrong"},
+	}
+	manager := &testManager{err: errors.New("failed to start workflow")}
+
+	s := NewService(clusters, nil, nil, nil, nil, nil, testScaleOptionsStore{}, nil, manager)
+
+	_, err := s.ApplyCluster(context.Background(), 1, DesiredState{ScaleOptions: &ScaleOptions{Enabled: true}}, false)
+	require.Error(t, err)
+
+	assert.Len(t, manager.operations, 1)
+	assert.Equal(t, []string{cluster.Updating, cluster.Warning}, clusters.statuses)
+}
+
+type testScaleOptionsStore struct {
+	ScaleOptionsStore
+}
+
+func (testScaleOptionsStore) GetScaleOptions(_ context.Context, _ uint) (ScaleOptions, error) {
+	return ScaleOptions{}, nil
+}
@@ -0,0 +1,57 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercostadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// ClusterFinder finds the clusters with provisioned infrastructure.
+type ClusterFinder struct {
+	db *gorm.DB
+}
+
+// NewClusterFinder returns a new ClusterFinder.
+func NewClusterFinder(db *gorm.DB) ClusterFinder {
+	return ClusterFinder{
+		db: db,
+	}
+}
+
+// FindClusters returns the IDs of the clusters that may have running resources.
+func (f ClusterFinder) FindClusters(ctx context.Context) ([]uint, error) {
+	var clusterIDs []uint
+
+	err := f.db.
+		Table("clusters").
+		Where("deleted_at IS NULL AND status IN (?)", []string{
+			pkgCluster.Running,
+			pkgCluster.Updating,
+			pkgCluster.Warning,
+			pkgCluster.Hibernated,
+		}).
+		Order("id").
+		Pluck("id", &clusterIDs).
+		Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to query clusters")
+	}
+
+	return clusterIDs, nil
+}
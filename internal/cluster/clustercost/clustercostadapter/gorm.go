@@ -0,0 +1,177 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercostadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
+)
+
+const usageRecordTableName = "cluster_cost_usage_records"
+
+type usageRecordModel struct {
+	ID             uint `gorm:"primary_key"`
+	OrganizationID uint `gorm:"index:idx_cluster_cost_usage_records_org_id_period"`
+	ClusterID      uint `gorm:"index:idx_cluster_cost_usage_records_cluster_id_period"`
+	ClusterName    string
+	ClusterLabels  string `gorm:"type:text"`
+	Cloud          string
+	Distribution   string
+	Region         string
+	Resource       string
+	NodePool       string
+	InstanceType   string
+	Lifecycle      string
+	Count          int
+	HourlyPrice    float64
+	PeriodStart    time.Time `gorm:"index:idx_cluster_cost_usage_records_org_id_period;index:idx_cluster_cost_usage_records_cluster_id_period"`
+	PeriodEnd      time.Time
+}
+
+func (usageRecordModel) TableName() string {
+	return usageRecordTableName
+}
+
+// Migrate executes the table migrations for the usage ledger.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	tables := []interface{}{
+		&usageRecordModel{},
+	}
+
+	var tableNames string
+	for _, table := range tables {
+		tableNames += fmt.Sprintf(" %s", db.NewScope(table).TableName())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.TrimSpace(tableNames),
+	}).Info("migrating cluster cost tables")
+
+	return db.AutoMigrate(tables...).Error
+}
+
+// GormLedgerStore implements the clustercost.LedgerStore interface using gorm.
+type GormLedgerStore struct {
+	db *gorm.DB
+}
+
+// NewGormLedgerStore returns a new GormLedgerStore.
+func NewGormLedgerStore(db *gorm.DB) GormLedgerStore {
+	return GormLedgerStore{
+		db: db,
+	}
+}
+
+// ReplaceRecords implements the clustercost.LedgerStore interface.
+func (s GormLedgerStore) ReplaceRecords(ctx context.Context, clusterID uint, periodStart time.Time, records []clustercost.UsageRecord) error {
+	tx := s.db.Begin()
+	if err := tx.Error; err != nil {
+		return errors.WrapIf(err, "failed to begin transaction")
+	}
+
+	err := tx.Where("cluster_id = ? AND period_start = ?", clusterID, periodStart).Delete(&usageRecordModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete usage records", "clusterId", clusterID, "periodStart", periodStart)
+	}
+
+	for _, record := range records {
+		labels, err := json.Marshal(record.ClusterLabels)
+		if err != nil {
+			tx.Rollback()
+
+			return errors.WrapIfWithDetails(err, "failed to marshal cluster labels", "clusterId", clusterID)
+		}
+
+		model := usageRecordModel{
+			OrganizationID: record.OrganizationID,
+			ClusterID:      record.ClusterID,
+			ClusterName:    record.ClusterName,
+			ClusterLabels:  string(labels),
+			Cloud:          record.Cloud,
+			Distribution:   record.Distribution,
+			Region:         record.Region,
+			Resource:       record.Resource,
+			NodePool:       record.NodePool,
+			InstanceType:   record.InstanceType,
+			Lifecycle:      record.Lifecycle,
+			Count:          record.Count,
+			HourlyPrice:    record.HourlyPrice,
+			PeriodStart:    record.PeriodStart,
+			PeriodEnd:      record.PeriodEnd,
+		}
+
+		if err := tx.Create(&model).Error; err != nil {
+			tx.Rollback()
+
+			return errors.WrapIfWithDetails(err, "failed to add usage record", "clusterId", clusterID, "periodStart", periodStart)
+		}
+	}
+
+	return errors.WrapIf(tx.Commit().Error, "failed to commit transaction")
+}
+
+// ListRecords implements the clustercost.LedgerStore interface.
+func (s GormLedgerStore) ListRecords(ctx context.Context, organizationID uint, from time.Time, to time.Time) ([]clustercost.UsageRecord, error) {
+	var models []usageRecordModel
+
+	err := s.db.
+		Where("organization_id = ? AND period_start < ? AND period_end > ?", organizationID, to, from).
+		Order("period_start, id").
+		Find(&models).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list usage records", "organizationId", organizationID)
+	}
+
+	records := make([]clustercost.UsageRecord, 0, len(models))
+	for _, model := range models {
+		var labels map[string]string
+		if model.ClusterLabels != "" {
+			if err := json.Unmarshal([]byte(model.ClusterLabels), &labels); err != nil {
+				return nil, errors.WrapIfWithDetails(err, "failed to unmarshal cluster labels", "recordId", model.ID)
+			}
+		}
+
+		records = append(records, clustercost.UsageRecord{
+			OrganizationID: model.OrganizationID,
+			ClusterID:      model.ClusterID,
+			ClusterName:    model.ClusterName,
+			ClusterLabels:  labels,
+			Cloud:          model.Cloud,
+			Distribution:   model.Distribution,
+			Region:         model.Region,
+			Resource:       model.Resource,
+			NodePool:       model.NodePool,
+			InstanceType:   model.InstanceType,
+			Lifecycle:      model.Lifecycle,
+			Count:          model.Count,
+			HourlyPrice:    model.HourlyPrice,
+			PeriodStart:    model.PeriodStart,
+			PeriodEnd:      model.PeriodEnd,
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,117 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercostadapter
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+const (
+	instanceTypeLabelKey       = "node.kubernetes.io/instance-type"
+	legacyInstanceTypeLabelKey = "beta.kubernetes.io/instance-type"
+	onDemandLabelKey           = "node.banzaicloud.io/ondemand"
+)
+
+// NodeLister lists the running nodes of a cluster through its stored kubeconfig.
+type NodeLister struct {
+	clusterGetter integratedserviceadapter.ClusterGetter
+}
+
+// NewNodeLister returns a new NodeLister.
+func NewNodeLister(clusterGetter integratedserviceadapter.ClusterGetter) NodeLister {
+	return NodeLister{
+		clusterGetter: clusterGetter,
+	}
+}
+
+// ListRunningNodes implements the clustercost.NodeLister interface.
+func (l NodeLister) ListRunningNodes(ctx context.Context, clusterID uint) ([]clustercost.RunningNodes, error) {
+	c, err := l.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get cluster")
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get kubeconfig")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list nodes")
+	}
+
+	counts := make(map[clustercost.RunningNodes]int)
+
+	for _, node := range nodes.Items {
+		instanceType := node.Labels[instanceTypeLabelKey]
+		if instanceType == "" {
+			instanceType = node.Labels[legacyInstanceTypeLabelKey]
+		}
+
+		// nodes of unknown type cannot be priced
+		if instanceType == "" {
+			continue
+		}
+
+		lifecycle := clustercost.LifecycleOnDemand
+		if onDemand, err := strconv.ParseBool(node.Labels[onDemandLabelKey]); err == nil && !onDemand {
+			lifecycle = clustercost.LifecycleSpot
+		}
+
+		counts[clustercost.RunningNodes{
+			NodePool:     node.Labels[cluster.NodePoolNameLabelKey],
+			InstanceType: instanceType,
+			Lifecycle:    lifecycle,
+		}]++
+	}
+
+	runningNodes := make([]clustercost.RunningNodes, 0, len(counts))
+	for key, count := range counts {
+		key.Count = count
+
+		runningNodes = append(runningNodes, key)
+	}
+
+	sort.Slice(runningNodes, func(i, j int) bool {
+		a, b := runningNodes[i], runningNodes[j]
+
+		if a.NodePool != b.NodePool {
+			return a.NodePool < b.NodePool
+		}
+
+		if a.InstanceType != b.InstanceType {
+			return a.InstanceType < b.InstanceType
+		}
+
+		return a.Lifecycle < b.Lifecycle
+	})
+
+	return runningNodes, nil
+}
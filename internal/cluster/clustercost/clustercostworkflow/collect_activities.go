@@ -0,0 +1,60 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercostworkflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
+)
+
+const ListClustersActivityName = "cluster-cost-collect-usage-list-clusters"
+
+type ListClustersActivityInput struct{}
+
+const CollectClusterUsageActivityName = "cluster-cost-collect-usage-collect-cluster"
+
+type CollectClusterUsageActivityInput struct {
+	ClusterID   uint
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// ClusterFinder finds the clusters that may have running resources.
+type ClusterFinder interface {
+	FindClusters(ctx context.Context) ([]uint, error)
+}
+
+// CollectUsageActivities implements the activities of the usage collection workflow.
+type CollectUsageActivities struct {
+	clusterFinder ClusterFinder
+	collector     clustercost.UsageCollector
+}
+
+// NewCollectUsageActivities returns a new CollectUsageActivities.
+func NewCollectUsageActivities(clusterFinder ClusterFinder, collector clustercost.UsageCollector) CollectUsageActivities {
+	return CollectUsageActivities{
+		clusterFinder: clusterFinder,
+		collector:     collector,
+	}
+}
+
+func (a CollectUsageActivities) ListClusters(ctx context.Context, _ ListClustersActivityInput) ([]uint, error) {
+	return a.clusterFinder.FindClusters(ctx)
+}
+
+func (a CollectUsageActivities) CollectClusterUsage(ctx context.Context, input CollectClusterUsageActivityInput) error {
+	return a.collector.CollectClusterUsage(ctx, input.ClusterID, input.PeriodStart, input.PeriodEnd)
+}
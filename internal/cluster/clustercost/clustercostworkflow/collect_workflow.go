@@ -0,0 +1,83 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercostworkflow
+
+import (
+	"time"
+
+	"github.com/robfig/cron"
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+)
+
+// CollectUsageWorkflowName is the name of the workflow recording the running nodes of every cluster in the usage ledger.
+const CollectUsageWorkflowName = "cluster-cost-collect-usage"
+
+// CollectUsageWorkflowInput defines the inputs of the usage collection workflow.
+type CollectUsageWorkflowInput struct {
+	// Schedule is the cron schedule of the workflow.
+	// Each run records usage until the next scheduled run.
+	Schedule string
+}
+
+// CollectUsageWorkflow records the running nodes of every cluster in the usage ledger.
+// The recorded usage covers the period from the current run until the next scheduled run.
+func CollectUsageWorkflow(ctx workflow.Context, input CollectUsageWorkflowInput) error {
+	logger := workflow.GetLogger(ctx).Sugar()
+
+	schedule, err := cron.ParseStandard(input.Schedule)
+	if err != nil {
+		return cadence.NewCustomError("invalid schedule", err.Error())
+	}
+
+	periodStart := workflow.Now(ctx).UTC().Truncate(time.Minute)
+	periodEnd := schedule.Next(periodStart)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    5 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy: &cadence.RetryPolicy{
+			InitialInterval:    15 * time.Second,
+			BackoffCoefficient: 1.5,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    3,
+		},
+	})
+
+	var clusterIDs []uint
+	if err := workflow.ExecuteActivity(ctx, ListClustersActivityName, ListClustersActivityInput{}).Get(ctx, &clusterIDs); err != nil {
+		return err
+	}
+
+	futures := make([]workflow.Future, 0, len(clusterIDs))
+	for _, clusterID := range clusterIDs {
+		activityInput := CollectClusterUsageActivityInput{
+			ClusterID:   clusterID,
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd,
+		}
+
+		futures = append(futures, workflow.ExecuteActivity(ctx, CollectClusterUsageActivityName, activityInput))
+	}
+
+	for i, future := range futures {
+		if err := future.Get(ctx, nil); err != nil {
+			// unreachable clusters are left out of the period
+			logger.Errorw("failed to collect cluster usage", "clusterId", clusterIDs[i], "error", err.Error())
+		}
+	}
+
+	return nil
+}
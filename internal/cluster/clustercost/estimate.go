@@ -0,0 +1,180 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercost
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/.gen/cloudinfo"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// HoursPerMonth is the average number of hours in a month used for monthly estimates.
+const HoursPerMonth = 730
+
+// Currency is the currency of every price returned by cloudinfo.
+const Currency = "USD"
+
+// PriceSource provides instance prices.
+type PriceSource interface {
+	// GetProductDetails returns details (including prices) for a single product.
+	GetProductDetails(ctx context.Context, cloud string, service string, region string, productType string) (cloudinfo.ProductDetails, error)
+}
+
+// ClusterSpec describes the billable parts of a cluster.
+type ClusterSpec struct {
+	Cloud        string
+	Distribution string
+	Region       string
+	NodePools    []NodePool
+}
+
+// NodePool describes the billable parts of a node pool.
+type NodePool struct {
+	Name         string
+	InstanceType string
+	Count        int
+	Spot         bool
+}
+
+// ClusterEstimate is the estimated cost of a cluster.
+type ClusterEstimate struct {
+	Cloud        string
+	Distribution string
+	Region       string
+	Currency     string
+
+	NodePools []NodePoolEstimate
+
+	ControlPlaneHourlyCost float64
+	HourlyCost             float64
+	MonthlyCost            float64
+}
+
+// NodePoolEstimate is the estimated cost of a node pool.
+type NodePoolEstimate struct {
+	Name         string
+	InstanceType string
+	Count        int
+	Spot         bool
+
+	NodeHourlyPrice float64
+	HourlyCost      float64
+	MonthlyCost     float64
+}
+
+// Estimator estimates cluster costs from instance prices.
+type Estimator struct {
+	prices PriceSource
+}
+
+// NewEstimator returns a new Estimator.
+func NewEstimator(prices PriceSource) Estimator {
+	return Estimator{
+		prices: prices,
+	}
+}
+
+// EstimateCluster returns the estimated hourly and monthly cost of a cluster.
+func (e Estimator) EstimateCluster(ctx context.Context, spec ClusterSpec) (ClusterEstimate, error) {
+	estimate := ClusterEstimate{
+		Cloud:                  spec.Cloud,
+		Distribution:           spec.Distribution,
+		Region:                 spec.Region,
+		Currency:               Currency,
+		NodePools:              make([]NodePoolEstimate, 0, len(spec.NodePools)),
+		ControlPlaneHourlyCost: ControlPlaneHourlyFee(spec.Distribution),
+	}
+
+	for _, nodePool := range spec.NodePools {
+		price, err := e.NodeHourlyPrice(ctx, spec.Cloud, spec.Distribution, spec.Region, nodePool.InstanceType, nodePool.Spot)
+		if err != nil {
+			return ClusterEstimate{}, errors.WithDetails(err, "nodePool", nodePool.Name)
+		}
+
+		hourlyCost := price * float64(nodePool.Count)
+
+		estimate.NodePools = append(estimate.NodePools, NodePoolEstimate{
+			Name:            nodePool.Name,
+			InstanceType:    nodePool.InstanceType,
+			Count:           nodePool.Count,
+			Spot:            nodePool.Spot,
+			NodeHourlyPrice: roundPrice(price),
+			HourlyCost:      roundPrice(hourlyCost),
+			MonthlyCost:     roundPrice(hourlyCost * HoursPerMonth),
+		})
+
+		estimate.HourlyCost += hourlyCost
+	}
+
+	sort.Slice(estimate.NodePools, func(i, j int) bool {
+		return estimate.NodePools[i].Name < estimate.NodePools[j].Name
+	})
+
+	estimate.HourlyCost += estimate.ControlPlaneHourlyCost
+	estimate.MonthlyCost = roundPrice(estimate.HourlyCost * HoursPerMonth)
+	estimate.HourlyCost = roundPrice(estimate.HourlyCost)
+
+	return estimate, nil
+}
+
+// NodeHourlyPrice returns the hourly price of a single node.
+// Spot nodes are priced at the average spot price of the region, falling back to the on-demand price.
+func (e Estimator) NodeHourlyPrice(ctx context.Context, cloud string, distribution string, region string, instanceType string, spot bool) (float64, error) {
+	product, err := e.prices.GetProductDetails(ctx, cloud, distribution, region, instanceType)
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "failed to get instance price", "instanceType", instanceType)
+	}
+
+	if spot {
+		return averageSpotPrice(product), nil
+	}
+
+	return product.OnDemandPrice, nil
+}
+
+// ControlPlaneHourlyFee returns the hourly fee of a managed Kubernetes control plane.
+// Other distributions run their control plane on regular (priced) nodes.
+func ControlPlaneHourlyFee(distribution string) float64 {
+	switch distribution {
+	case pkgCluster.EKS, pkgCluster.GKE:
+		return 0.10
+	default: // AKS control planes are free
+		return 0
+	}
+}
+
+// averageSpotPrice returns the average spot price of an instance type across zones.
+// Falls back to the on-demand price when no spot price is available.
+func averageSpotPrice(product cloudinfo.ProductDetails) float64 {
+	if len(product.SpotPrice) == 0 {
+		return product.OnDemandPrice
+	}
+
+	var sum float64
+	for _, zonePrice := range product.SpotPrice {
+		sum += zonePrice.Price
+	}
+
+	return sum / float64(len(product.SpotPrice))
+}
+
+// roundPrice rounds prices to a hundredth of a cent.
+func roundPrice(price float64) float64 {
+	return math.Round(price*10000) / 10000
+}
@@ -0,0 +1,191 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercost
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/.gen/cloudinfo"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/ekscluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/aks"
+)
+
+type fakePriceSource map[string]cloudinfo.ProductDetails
+
+func (s fakePriceSource) GetProductDetails(_ context.Context, _ string, _ string, _ string, productType string) (cloudinfo.ProductDetails, error) {
+	product, ok := s[productType]
+	if !ok {
+		return cloudinfo.ProductDetails{}, errors.New("no product info found")
+	}
+
+	return product, nil
+}
+
+func TestEstimator_EstimateCluster(t *testing.T) {
+	prices := fakePriceSource{
+		"m5.large": {
+			OnDemandPrice: 0.096,
+			SpotPrice: []cloudinfo.ZonePrice{
+				{Zone: "us-east-1a", Price: 0.03},
+				{Zone: "us-east-1b", Price: 0.04},
+			},
+		},
+		"t3.small": {
+			OnDemandPrice: 0.0208,
+		},
+	}
+
+	estimator := NewEstimator(prices)
+
+	t.Run("Success", func(t *testing.T) {
+		estimate, err := estimator.EstimateCluster(context.Background(), ClusterSpec{
+			Cloud:        pkgCluster.Amazon,
+			Distribution: pkgCluster.EKS,
+			Region:       "us-east-1",
+			NodePools: []NodePool{
+				{Name: "pool2", InstanceType: "t3.small", Count: 1, Spot: true},
+				{Name: "pool1", InstanceType: "m5.large", Count: 2, Spot: true},
+				{Name: "pool0", InstanceType: "m5.large", Count: 3},
+			},
+		})
+		require.NoError(t, err)
+
+		expected := ClusterEstimate{
+			Cloud:        pkgCluster.Amazon,
+			Distribution: pkgCluster.EKS,
+			Region:       "us-east-1",
+			Currency:     Currency,
+			NodePools: []NodePoolEstimate{
+				{
+					Name:            "pool0",
+					InstanceType:    "m5.large",
+					Count:           3,
+					NodeHourlyPrice: 0.096,
+					HourlyCost:      0.288,
+					MonthlyCost:     210.24,
+				},
+				{
+					Name:            "pool1",
+					InstanceType:    "m5.large",
+					Count:           2,
+					Spot:            true,
+					NodeHourlyPrice: 0.035,
+					HourlyCost:      0.07,
+					MonthlyCost:     51.1,
+				},
+				{
+					Name:            "pool2",
+					InstanceType:    "t3.small",
+					Count:           1,
+					Spot:            true,
+					NodeHourlyPrice: 0.0208,
+					HourlyCost:      0.0208,
+					MonthlyCost:     15.184,
+				},
+			},
+			ControlPlaneHourlyCost: 0.1,
+			HourlyCost:             0.4788,
+			MonthlyCost:            349.524,
+		}
+
+		assert.Equal(t, expected, estimate)
+	})
+
+	t.Run("UnknownInstanceType", func(t *testing.T) {
+		_, err := estimator.EstimateCluster(context.Background(), ClusterSpec{
+			Cloud:        pkgCluster.Amazon,
+			Distribution: pkgCluster.EKS,
+			Region:       "us-east-1",
+			NodePools: []NodePool{
+				{Name: "pool0", InstanceType: "x1.huge", Count: 1},
+			},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestSpecFromCreateRequest(t *testing.T) {
+	t.Run("EKS", func(t *testing.T) {
+		spec, err := SpecFromCreateRequest(pkgCluster.CreateClusterRequest{
+			Cloud:    pkgCluster.Amazon,
+			Location: "us-east-1",
+			Properties: &pkgCluster.CreateClusterProperties{
+				CreateClusterEKS: &ekscluster.CreateClusterEKS{
+					NodePools: map[string]*ekscluster.NodePool{
+						"pool0": {InstanceType: "m5.large", Count: 2, SpotPrice: "0.05"},
+						"pool1": {InstanceType: "t3.small", Autoscaling: true, MinCount: 1, MaxCount: 3},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, pkgCluster.EKS, spec.Distribution)
+		assert.ElementsMatch(
+			t,
+			[]NodePool{
+				{Name: "pool0", InstanceType: "m5.large", Count: 2, Spot: true},
+				{Name: "pool1", InstanceType: "t3.small", Count: 1},
+			},
+			spec.NodePools,
+		)
+	})
+
+	t.Run("NotSupported", func(t *testing.T) {
+		_, err := SpecFromCreateRequest(pkgCluster.CreateClusterRequest{
+			Cloud:      pkgCluster.Oracle,
+			Properties: &pkgCluster.CreateClusterProperties{},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestApplyUpdateRequest(t *testing.T) {
+	spec := ClusterSpec{
+		Cloud:        pkgCluster.Azure,
+		Distribution: pkgCluster.AKS,
+		Region:       "westeurope",
+		NodePools: []NodePool{
+			{Name: "pool0", InstanceType: "Standard_D2s_v3", Count: 1},
+			{Name: "pool1", InstanceType: "Standard_D4s_v3", Count: 2},
+		},
+	}
+
+	updatedSpec, err := ApplyUpdateRequest(spec, pkgCluster.UpdateClusterRequest{
+		Cloud: pkgCluster.Azure,
+		UpdateProperties: pkgCluster.UpdateProperties{
+			AKS: &aks.UpdateClusterAzure{
+				NodePools: map[string]*aks.NodePoolUpdate{
+					"pool1": {Count: 5},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(
+		t,
+		[]NodePool{
+			{Name: "pool0", InstanceType: "Standard_D2s_v3", Count: 1},
+			{Name: "pool1", InstanceType: "Standard_D4s_v3", Count: 5},
+		},
+		updatedSpec.NodePools,
+	)
+}
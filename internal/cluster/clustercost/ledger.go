@@ -0,0 +1,206 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercost
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Node lifecycles recorded in the usage ledger.
+const (
+	LifecycleOnDemand = "on-demand"
+	LifecycleSpot     = "spot"
+)
+
+// Resources recorded in the usage ledger.
+const (
+	ResourceNodes        = "nodes"
+	ResourceControlPlane = "control-plane"
+)
+
+// RunningNodes is a group of running nodes of a node pool sharing the same instance type and lifecycle.
+type RunningNodes struct {
+	NodePool     string
+	InstanceType string
+	Lifecycle    string
+	Count        int
+}
+
+// UsageRecord is an entry of the usage ledger.
+// It describes the resources of a cluster running during a period, priced at the time of collection.
+//
+// Cluster details are copied into the record, so that deleted clusters remain part of past periods.
+type UsageRecord struct {
+	OrganizationID uint
+	ClusterID      uint
+	ClusterName    string
+	ClusterLabels  map[string]string
+
+	Cloud        string
+	Distribution string
+	Region       string
+
+	Resource     string
+	NodePool     string
+	InstanceType string
+	Lifecycle    string
+	Count        int
+
+	HourlyPrice float64
+
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// Hours returns the number of hours of the record period overlapping with the given time range.
+func (r UsageRecord) Hours(from time.Time, to time.Time) float64 {
+	start := r.PeriodStart
+	if from.After(start) {
+		start = from
+	}
+
+	end := r.PeriodEnd
+	if to.Before(end) {
+		end = to
+	}
+
+	if !end.After(start) {
+		return 0
+	}
+
+	return end.Sub(start).Hours()
+}
+
+// LedgerStore persists usage records.
+type LedgerStore interface {
+	// ReplaceRecords replaces the records of a cluster starting at the given time.
+	ReplaceRecords(ctx context.Context, clusterID uint, periodStart time.Time, records []UsageRecord) error
+
+	// ListRecords returns the records of an organization overlapping with the given time range.
+	ListRecords(ctx context.Context, organizationID uint, from time.Time, to time.Time) ([]UsageRecord, error)
+}
+
+// NodeLister lists the running nodes of a cluster.
+type NodeLister interface {
+	// ListRunningNodes returns the running nodes of a cluster grouped by node pool, instance type and lifecycle.
+	ListRunningNodes(ctx context.Context, clusterID uint) ([]RunningNodes, error)
+}
+
+// ClusterStore returns the details of a cluster.
+type ClusterStore interface {
+	// GetCluster returns a generic Cluster.
+	GetCluster(ctx context.Context, id uint) (cluster.Cluster, error)
+}
+
+// UsageCollector records the running nodes of clusters in the usage ledger.
+type UsageCollector struct {
+	clusters  ClusterStore
+	nodes     NodeLister
+	estimator Estimator
+	ledger    LedgerStore
+}
+
+// NewUsageCollector returns a new UsageCollector.
+func NewUsageCollector(clusters ClusterStore, nodes NodeLister, estimator Estimator, ledger LedgerStore) UsageCollector {
+	return UsageCollector{
+		clusters:  clusters,
+		nodes:     nodes,
+		estimator: estimator,
+		ledger:    ledger,
+	}
+}
+
+// CollectClusterUsage records the currently running nodes of a cluster for the given period.
+// Collecting the same period again replaces the previously recorded usage.
+func (c UsageCollector) CollectClusterUsage(ctx context.Context, clusterID uint, periodStart time.Time, periodEnd time.Time) error {
+	if !periodEnd.After(periodStart) {
+		return errors.NewWithDetails("period end must be after period start", "periodStart", periodStart, "periodEnd", periodEnd)
+	}
+
+	clusterInfo, err := c.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := c.nodes.ListRunningNodes(ctx, clusterID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list running nodes", "clusterId", clusterID)
+	}
+
+	region := regionFromLocation(clusterInfo.Cloud, clusterInfo.Location)
+
+	newRecord := func(resource string) UsageRecord {
+		return UsageRecord{
+			OrganizationID: clusterInfo.OrganizationID,
+			ClusterID:      clusterInfo.ID,
+			ClusterName:    clusterInfo.Name,
+			ClusterLabels:  clusterInfo.Labels,
+			Cloud:          clusterInfo.Cloud,
+			Distribution:   clusterInfo.Distribution,
+			Region:         region,
+			Resource:       resource,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodEnd,
+		}
+	}
+
+	records := make([]UsageRecord, 0, len(nodes)+1)
+
+	if fee := ControlPlaneHourlyFee(clusterInfo.Distribution); fee > 0 {
+		record := newRecord(ResourceControlPlane)
+		record.Count = 1
+		record.HourlyPrice = fee
+
+		records = append(records, record)
+	}
+
+	for _, node := range nodes {
+		if node.Count == 0 {
+			continue
+		}
+
+		price, err := c.estimator.NodeHourlyPrice(ctx, clusterInfo.Cloud, clusterInfo.Distribution, region, node.InstanceType, node.Lifecycle == LifecycleSpot)
+		if err != nil {
+			return errors.WithDetails(err, "clusterId", clusterID, "nodePool", node.NodePool)
+		}
+
+		record := newRecord(ResourceNodes)
+		record.NodePool = node.NodePool
+		record.InstanceType = node.InstanceType
+		record.Lifecycle = node.Lifecycle
+		record.Count = node.Count
+		record.HourlyPrice = price
+
+		records = append(records, record)
+	}
+
+	return c.ledger.ReplaceRecords(ctx, clusterID, periodStart, records)
+}
+
+// regionFromLocation returns the region of a cluster location.
+// GKE clusters may be zonal, in which case the zone suffix is removed.
+func regionFromLocation(cloud string, location string) string {
+	if cloud == pkgCluster.Google && strings.Count(location, "-") == 2 {
+		return location[:strings.LastIndex(location, "-")]
+	}
+
+	return location
+}
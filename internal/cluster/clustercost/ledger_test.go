@@ -0,0 +1,265 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercost
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/.gen/cloudinfo"
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type fakeClusterStore map[uint]cluster.Cluster
+
+func (s fakeClusterStore) GetCluster(_ context.Context, id uint) (cluster.Cluster, error) {
+	return s[id], nil
+}
+
+type fakeNodeLister map[uint][]RunningNodes
+
+func (l fakeNodeLister) ListRunningNodes(_ context.Context, clusterID uint) ([]RunningNodes, error) {
+	return l[clusterID], nil
+}
+
+type fakeLedgerStore struct {
+	records []UsageRecord
+}
+
+func (s *fakeLedgerStore) ReplaceRecords(_ context.Context, clusterID uint, periodStart time.Time, records []UsageRecord) error {
+	kept := records
+	for _, record := range s.records {
+		if record.ClusterID != clusterID || !record.PeriodStart.Equal(periodStart) {
+			kept = append(kept, record)
+		}
+	}
+
+	s.records = kept
+
+	return nil
+}
+
+func (s *fakeLedgerStore) ListRecords(_ context.Context, organizationID uint, from time.Time, to time.Time) ([]UsageRecord, error) {
+	var records []UsageRecord
+	for _, record := range s.records {
+		if record.OrganizationID == organizationID && record.PeriodStart.Before(to) && record.PeriodEnd.After(from) {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func TestUsageCollector_CollectClusterUsage(t *testing.T) {
+	clusters := fakeClusterStore{
+		1: {
+			ID:             1,
+			Name:           "eks-cluster",
+			OrganizationID: 2,
+			Cloud:          pkgCluster.Amazon,
+			Distribution:   pkgCluster.EKS,
+			Location:       "us-east-1",
+			Labels:         map[string]string{"team": "a"},
+		},
+	}
+
+	nodes := fakeNodeLister{
+		1: {
+			{NodePool: "pool0", InstanceType: "m5.large", Lifecycle: LifecycleOnDemand, Count: 2},
+			{NodePool: "pool1", InstanceType: "m5.large", Lifecycle: LifecycleSpot, Count: 3},
+		},
+	}
+
+	prices := fakePriceSource{
+		"m5.large": {
+			OnDemandPrice: 0.096,
+			SpotPrice:     []cloudinfo.ZonePrice{{Zone: "us-east-1a", Price: 0.03}},
+		},
+	}
+
+	ledger := &fakeLedgerStore{}
+
+	collector := NewUsageCollector(clusters, nodes, NewEstimator(prices), ledger)
+
+	periodStart := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.Add(time.Hour)
+
+	// collecting the same period twice replaces the records
+	for i := 0; i < 2; i++ {
+		err := collector.CollectClusterUsage(context.Background(), 1, periodStart, periodEnd)
+		require.NoError(t, err)
+	}
+
+	newRecord := func(resource string, nodePool string, lifecycle string, count int, price float64) UsageRecord {
+		record := UsageRecord{
+			OrganizationID: 2,
+			ClusterID:      1,
+			ClusterName:    "eks-cluster",
+			ClusterLabels:  map[string]string{"team": "a"},
+			Cloud:          pkgCluster.Amazon,
+			Distribution:   pkgCluster.EKS,
+			Region:         "us-east-1",
+			Resource:       resource,
+			NodePool:       nodePool,
+			Lifecycle:      lifecycle,
+			Count:          count,
+			HourlyPrice:    price,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodEnd,
+		}
+
+		if resource == ResourceNodes {
+			record.InstanceType = "m5.large"
+		}
+
+		return record
+	}
+
+	expected := []UsageRecord{
+		newRecord(ResourceControlPlane, "", "", 1, 0.10),
+		newRecord(ResourceNodes, "pool0", LifecycleOnDemand, 2, 0.096),
+		newRecord(ResourceNodes, "pool1", LifecycleSpot, 3, 0.03),
+	}
+
+	assert.Equal(t, expected, ledger.records)
+
+	t.Run("InvalidPeriod", func(t *testing.T) {
+		err := collector.CollectClusterUsage(context.Background(), 1, periodEnd, periodStart)
+		require.Error(t, err)
+	})
+}
+
+func TestRegionFromLocation(t *testing.T) {
+	assert.Equal(t, "us-central1", regionFromLocation(pkgCluster.Google, "us-central1-a"))
+	assert.Equal(t, "us-central1", regionFromLocation(pkgCluster.Google, "us-central1"))
+	assert.Equal(t, "us-east-1", regionFromLocation(pkgCluster.Amazon, "us-east-1"))
+}
+
+func TestUsageReporter_GetUsageReport(t *testing.T) {
+	day := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	newRecord := func(clusterID uint, team string, resource string, nodePool string, count int, price float64, start time.Time, hours int) UsageRecord {
+		return UsageRecord{
+			OrganizationID: 1,
+			ClusterID:      clusterID,
+			ClusterName:    map[uint]string{1: "cluster1", 2: "cluster2"}[clusterID],
+			ClusterLabels:  map[string]string{"team": team},
+			Resource:       resource,
+			NodePool:       nodePool,
+			Count:          count,
+			HourlyPrice:    price,
+			PeriodStart:    start,
+			PeriodEnd:      start.Add(time.Duration(hours) * time.Hour),
+		}
+	}
+
+	ledger := &fakeLedgerStore{
+		records: []UsageRecord{
+			newRecord(1, "a", ResourceControlPlane, "", 1, 0.1, day, 10),
+			newRecord(1, "a", ResourceNodes, "pool0", 2, 0.5, day, 10),
+			newRecord(1, "a", ResourceNodes, "pool1", 1, 1, day, 10),
+			// deleted cluster
+			newRecord(2, "b", ResourceNodes, "pool0", 4, 0.25, day, 4),
+			// outside of the time range
+			newRecord(2, "b", ResourceNodes, "pool0", 4, 0.25, day.Add(-24*time.Hour), 1),
+		},
+	}
+
+	reporter := NewUsageReporter(ledger)
+
+	options := UsageReportOptions{
+		From: day,
+		To:   day.Add(24 * time.Hour),
+	}
+
+	t.Run("Organization", func(t *testing.T) {
+		options := options
+		options.GroupBy = GroupByOrganization
+
+		report, err := reporter.GetUsageReport(context.Background(), 1, options)
+		require.NoError(t, err)
+
+		assert.Equal(t, 25.0, report.Cost)
+		assert.Equal(t, 46.0, report.NodeHours)
+		assert.Equal(t, []UsageReportItem{{Cost: 25, NodeHours: 46}}, report.Items)
+	})
+
+	t.Run("Cluster", func(t *testing.T) {
+		options := options
+		options.GroupBy = GroupByCluster
+
+		report, err := reporter.GetUsageReport(context.Background(), 1, options)
+		require.NoError(t, err)
+
+		expected := []UsageReportItem{
+			{ClusterID: 1, ClusterName: "cluster1", Cost: 21, NodeHours: 30},
+			{ClusterID: 2, ClusterName: "cluster2", Cost: 4, NodeHours: 16},
+		}
+		assert.Equal(t, expected, report.Items)
+	})
+
+	t.Run("NodePool", func(t *testing.T) {
+		options := options
+		options.GroupBy = GroupByNodePool
+		options.ClusterID = 1
+
+		report, err := reporter.GetUsageReport(context.Background(), 1, options)
+		require.NoError(t, err)
+
+		expected := []UsageReportItem{
+			{ClusterID: 1, ClusterName: "cluster1", NodePool: "pool0", Cost: 10, NodeHours: 20},
+			{ClusterID: 1, ClusterName: "cluster1", NodePool: "pool1", Cost: 10, NodeHours: 10},
+			{ClusterID: 1, ClusterName: "cluster1", NodePool: "", Cost: 1},
+		}
+		assert.Equal(t, expected, report.Items)
+	})
+
+	t.Run("LabelProrated", func(t *testing.T) {
+		options := options
+		options.GroupBy = GroupByLabel
+		options.LabelKey = "team"
+		options.To = day.Add(2 * time.Hour)
+
+		report, err := reporter.GetUsageReport(context.Background(), 1, options)
+		require.NoError(t, err)
+
+		expected := []UsageReportItem{
+			{LabelValue: "a", Cost: 4.2, NodeHours: 6},
+			{LabelValue: "b", Cost: 2, NodeHours: 8},
+		}
+		assert.Equal(t, expected, report.Items)
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteUsageReportCSV(&buf, report))
+
+		expectedCSV := "from,to,clusterId,clusterName,nodePool,labelKey,labelValue,nodeHours,cost,currency\n" +
+			"2020-05-01T00:00:00Z,2020-05-01T02:00:00Z,,,,team,a,6,4.2,USD\n" +
+			"2020-05-01T00:00:00Z,2020-05-01T02:00:00Z,,,,team,b,8,2,USD\n"
+		assert.Equal(t, expectedCSV, buf.String())
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		options := options
+		options.GroupBy = GroupByLabel
+
+		_, err := reporter.GetUsageReport(context.Background(), 1, options)
+		require.Error(t, err)
+	})
+}
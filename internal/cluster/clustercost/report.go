@@ -0,0 +1,258 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercost
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Dimensions usage reports can be grouped by.
+const (
+	GroupByOrganization = "organization"
+	GroupByCluster      = "cluster"
+	GroupByNodePool     = "nodePool"
+	GroupByLabel        = "label"
+)
+
+// UsageReportOptions describes the time range and the grouping of a usage report.
+type UsageReportOptions struct {
+	From time.Time
+	To   time.Time
+
+	GroupBy string
+
+	// LabelKey is the cluster label used for grouping when grouping by label.
+	LabelKey string
+
+	// ClusterID optionally restricts the report to a single cluster.
+	ClusterID uint
+}
+
+// Validate validates the report options.
+func (o UsageReportOptions) Validate() error {
+	if o.From.IsZero() || o.To.IsZero() {
+		return errors.New("report time range is required")
+	}
+
+	if !o.To.After(o.From) {
+		return errors.New("report end must be after report start")
+	}
+
+	switch o.GroupBy {
+	case GroupByOrganization, GroupByCluster, GroupByNodePool:
+	case GroupByLabel:
+		if o.LabelKey == "" {
+			return errors.New("label key is required when grouping by label")
+		}
+	default:
+		return errors.Errorf("unsupported grouping: %q", o.GroupBy)
+	}
+
+	return nil
+}
+
+// UsageReport is the aggregated cost and usage of an organization during a time range.
+type UsageReport struct {
+	OrganizationID uint
+	From           time.Time
+	To             time.Time
+	GroupBy        string
+	LabelKey       string
+	Currency       string
+
+	Cost      float64
+	NodeHours float64
+
+	Items []UsageReportItem
+}
+
+// UsageReportItem is the aggregated cost and usage of a group.
+// Fields not part of the grouping are left empty.
+//
+// The managed control plane fee of a cluster is reported with an empty node pool name.
+type UsageReportItem struct {
+	ClusterID   uint
+	ClusterName string
+	NodePool    string
+	LabelValue  string
+
+	Cost      float64
+	NodeHours float64
+}
+
+// UsageReporter aggregates the usage ledger into reports.
+type UsageReporter struct {
+	ledger LedgerStore
+}
+
+// NewUsageReporter returns a new UsageReporter.
+func NewUsageReporter(ledger LedgerStore) UsageReporter {
+	return UsageReporter{
+		ledger: ledger,
+	}
+}
+
+// GetUsageReport returns the cost and usage of an organization aggregated according to the options.
+// Records partially overlapping with the time range are prorated.
+func (r UsageReporter) GetUsageReport(ctx context.Context, organizationID uint, options UsageReportOptions) (UsageReport, error) {
+	if err := options.Validate(); err != nil {
+		return UsageReport{}, err
+	}
+
+	records, err := r.ledger.ListRecords(ctx, organizationID, options.From, options.To)
+	if err != nil {
+		return UsageReport{}, err
+	}
+
+	report := UsageReport{
+		OrganizationID: organizationID,
+		From:           options.From,
+		To:             options.To,
+		GroupBy:        options.GroupBy,
+		LabelKey:       options.LabelKey,
+		Currency:       Currency,
+		Items:          []UsageReportItem{},
+	}
+
+	items := make(map[UsageReportItem]*UsageReportItem)
+
+	for _, record := range records {
+		if options.ClusterID != 0 && record.ClusterID != options.ClusterID {
+			continue
+		}
+
+		hours := record.Hours(options.From, options.To)
+		if hours == 0 {
+			continue
+		}
+
+		cost := hours * float64(record.Count) * record.HourlyPrice
+
+		var nodeHours float64
+		if record.Resource == ResourceNodes {
+			nodeHours = hours * float64(record.Count)
+		}
+
+		key := groupKey(record, options)
+
+		item, ok := items[key]
+		if !ok {
+			item = &UsageReportItem{
+				ClusterID:  key.ClusterID,
+				NodePool:   key.NodePool,
+				LabelValue: key.LabelValue,
+			}
+			if key.ClusterID != 0 {
+				item.ClusterName = record.ClusterName
+			}
+			items[key] = item
+		}
+
+		item.Cost += cost
+		item.NodeHours += nodeHours
+
+		report.Cost += cost
+		report.NodeHours += nodeHours
+	}
+
+	for _, item := range items {
+		item.Cost = roundPrice(item.Cost)
+		item.NodeHours = roundPrice(item.NodeHours)
+
+		report.Items = append(report.Items, *item)
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+
+		if a.ClusterID != b.ClusterID {
+			return a.ClusterID < b.ClusterID
+		}
+
+		if a.NodePool != b.NodePool {
+			return a.NodePool < b.NodePool
+		}
+
+		return a.LabelValue < b.LabelValue
+	})
+
+	report.Cost = roundPrice(report.Cost)
+	report.NodeHours = roundPrice(report.NodeHours)
+
+	return report, nil
+}
+
+// groupKey returns the identifying fields of the group a record belongs to.
+func groupKey(record UsageRecord, options UsageReportOptions) UsageReportItem {
+	switch options.GroupBy {
+	case GroupByCluster:
+		return UsageReportItem{ClusterID: record.ClusterID}
+	case GroupByNodePool:
+		return UsageReportItem{ClusterID: record.ClusterID, NodePool: record.NodePool}
+	case GroupByLabel:
+		return UsageReportItem{LabelValue: record.ClusterLabels[options.LabelKey]}
+	default:
+		return UsageReportItem{}
+	}
+}
+
+// WriteUsageReportCSV writes the items of a usage report as CSV.
+func WriteUsageReportCSV(w io.Writer, report UsageReport) error {
+	writer := csv.NewWriter(w)
+
+	records := [][]string{
+		{"from", "to", "clusterId", "clusterName", "nodePool", "labelKey", "labelValue", "nodeHours", "cost", "currency"},
+	}
+
+	from := report.From.UTC().Format(time.RFC3339)
+	to := report.To.UTC().Format(time.RFC3339)
+
+	for _, item := range report.Items {
+		var clusterID string
+		if item.ClusterID != 0 {
+			clusterID = strconv.FormatUint(uint64(item.ClusterID), 10)
+		}
+
+		var labelKey string
+		if report.GroupBy == GroupByLabel {
+			labelKey = report.LabelKey
+		}
+
+		records = append(records, []string{
+			from,
+			to,
+			clusterID,
+			item.ClusterName,
+			item.NodePool,
+			labelKey,
+			item.LabelValue,
+			strconv.FormatFloat(item.NodeHours, 'f', -1, 64),
+			strconv.FormatFloat(item.Cost, 'f', -1, 64),
+			report.Currency,
+		})
+	}
+
+	return errors.WithStack(writer.WriteAll(records))
+}
@@ -0,0 +1,252 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustercost
+
+import (
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/banzaicloud/pipeline/internal/providers/pke"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// SpecFromClusterStatus returns the billable parts of an existing cluster.
+//
+// Node counts observed in the cluster override the node pool sizes stored in the cluster status.
+// Node pools missing from the observed counts are considered empty when counts are available.
+func SpecFromClusterStatus(status *pkgCluster.GetClusterStatusResponse, nodeCounts map[string]int) ClusterSpec {
+	spec := ClusterSpec{
+		Cloud:        status.Cloud,
+		Distribution: status.Distribution,
+		Region:       status.Region,
+	}
+
+	if spec.Region == "" {
+		spec.Region = status.Location
+	}
+
+	for name, nodePool := range status.NodePools {
+		count := nodePool.Count
+		if nodeCounts != nil {
+			count = nodeCounts[name]
+		}
+
+		spec.NodePools = append(spec.NodePools, NodePool{
+			Name:         name,
+			InstanceType: nodePool.InstanceType,
+			Count:        count,
+			Spot:         isSpot(nodePool.SpotPrice, nodePool.Preemptible),
+		})
+	}
+
+	return spec
+}
+
+// SpecFromCreateRequest returns the billable parts of a cluster described by a creation request.
+func SpecFromCreateRequest(request pkgCluster.CreateClusterRequest) (ClusterSpec, error) {
+	spec := ClusterSpec{
+		Cloud:  request.Cloud,
+		Region: request.Location,
+	}
+
+	if request.Properties == nil {
+		return ClusterSpec{}, errors.New("cluster properties are required")
+	}
+
+	switch {
+	case request.Properties.CreateClusterEKS != nil:
+		spec.Distribution = pkgCluster.EKS
+
+		for name, nodePool := range request.Properties.CreateClusterEKS.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			spec.NodePools = append(spec.NodePools, NodePool{
+				Name:         name,
+				InstanceType: nodePool.InstanceType,
+				Count:        initialCount(nodePool.Count, nodePool.MinCount),
+				Spot:         isSpot(nodePool.SpotPrice, false),
+			})
+		}
+
+	case request.Properties.CreateClusterAKS != nil:
+		spec.Distribution = pkgCluster.AKS
+
+		for name, nodePool := range request.Properties.CreateClusterAKS.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			spec.NodePools = append(spec.NodePools, NodePool{
+				Name:         name,
+				InstanceType: nodePool.NodeInstanceType,
+				Count:        initialCount(nodePool.Count, nodePool.MinCount),
+			})
+		}
+
+	case request.Properties.CreateClusterGKE != nil:
+		spec.Distribution = pkgCluster.GKE
+
+		for name, nodePool := range request.Properties.CreateClusterGKE.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			spec.NodePools = append(spec.NodePools, NodePool{
+				Name:         name,
+				InstanceType: nodePool.NodeInstanceType,
+				Count:        initialCount(nodePool.Count, nodePool.MinCount),
+				Spot:         nodePool.Preemptible,
+			})
+		}
+
+	case request.Properties.CreateClusterPKE != nil && request.Cloud == pkgCluster.Amazon:
+		spec.Distribution = pkgCluster.PKE
+
+		for _, nodePool := range request.Properties.CreateClusterPKE.NodePools {
+			var providerConfig pke.NodePoolProviderConfigAmazon
+
+			err := mapstructure.Decode(nodePool.ProviderConfig, &providerConfig)
+			if err != nil {
+				return ClusterSpec{}, errors.WrapIfWithDetails(err, "failed to decode node pool provider config", "nodePool", nodePool.Name)
+			}
+
+			asg := providerConfig.AutoScalingGroup
+
+			spec.NodePools = append(spec.NodePools, NodePool{
+				Name:         nodePool.Name,
+				InstanceType: asg.InstanceType,
+				Count:        initialCount(asg.Size.Desired, asg.Size.Min),
+				Spot:         isSpot(asg.SpotPrice, false),
+			})
+		}
+
+	default:
+		return ClusterSpec{}, errors.NewWithDetails("cost estimation is not supported for the cluster type", "cloud", request.Cloud)
+	}
+
+	return spec, nil
+}
+
+// ApplyUpdateRequest returns the billable parts of a cluster after applying an update request.
+//
+// EKS, GKE and PKE updates describe every node pool (missing ones are deleted),
+// AKS updates only change the listed node pools.
+// Instance types are inherited from the existing node pools when not specified.
+func ApplyUpdateRequest(spec ClusterSpec, request pkgCluster.UpdateClusterRequest) (ClusterSpec, error) {
+	current := make(map[string]NodePool, len(spec.NodePools))
+	for _, nodePool := range spec.NodePools {
+		current[nodePool.Name] = nodePool
+	}
+
+	var nodePools []NodePool
+
+	switch {
+	case request.EKS != nil:
+		for name, nodePool := range request.EKS.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			nodePools = append(nodePools, NodePool{
+				Name:         name,
+				InstanceType: inheritInstanceType(nodePool.InstanceType, current[name]),
+				Count:        initialCount(nodePool.Count, nodePool.MinCount),
+				Spot:         isSpot(nodePool.SpotPrice, false),
+			})
+		}
+
+	case request.GKE != nil:
+		for name, nodePool := range request.GKE.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			nodePools = append(nodePools, NodePool{
+				Name:         name,
+				InstanceType: inheritInstanceType(nodePool.NodeInstanceType, current[name]),
+				Count:        initialCount(nodePool.Count, nodePool.MinCount),
+				Spot:         nodePool.Preemptible,
+			})
+		}
+
+	case request.PKE != nil:
+		// master node pools cannot be changed by updates
+		if master, ok := current["master"]; ok {
+			nodePools = append(nodePools, master)
+		}
+
+		for name, nodePool := range request.PKE.NodePools {
+			nodePools = append(nodePools, NodePool{
+				Name:         name,
+				InstanceType: inheritInstanceType(nodePool.InstanceType, current[name]),
+				Count:        initialCount(nodePool.Count, nodePool.MinCount),
+				Spot:         isSpot(nodePool.SpotPrice, false),
+			})
+		}
+
+	case request.AKS != nil:
+		for name, nodePool := range current {
+			if nodePoolUpdate, ok := request.AKS.NodePools[name]; ok && nodePoolUpdate != nil {
+				nodePool.Count = initialCount(nodePoolUpdate.Count, nodePoolUpdate.MinCount)
+			}
+
+			nodePools = append(nodePools, nodePool)
+		}
+
+	default:
+		return ClusterSpec{}, errors.NewWithDetails("cost estimation is not supported for the cluster type", "cloud", request.Cloud)
+	}
+
+	for _, nodePool := range nodePools {
+		if nodePool.InstanceType == "" {
+			return ClusterSpec{}, errors.NewWithDetails("instance type is required for new node pools", "nodePool", nodePool.Name)
+		}
+	}
+
+	spec.NodePools = nodePools
+
+	return spec, nil
+}
+
+// initialCount returns the node count a node pool starts with.
+func initialCount(count int, minCount int) int {
+	if count > 0 {
+		return count
+	}
+
+	return minCount
+}
+
+func inheritInstanceType(instanceType string, current NodePool) string {
+	if instanceType != "" {
+		return instanceType
+	}
+
+	return current.InstanceType
+}
+
+// isSpot determines whether a node pool runs on spot (or preemptible) instances.
+func isSpot(spotPrice string, preemptible bool) bool {
+	if preemptible {
+		return true
+	}
+
+	price, err := strconv.ParseFloat(spotPrice, 64)
+
+	return err == nil && price > 0
+}
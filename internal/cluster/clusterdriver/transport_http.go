@@ -17,6 +17,7 @@ package clusterdriver
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -42,6 +43,55 @@ func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...ki
 		options...,
 	))
 
+	router.Methods(http.MethodPost).Path("/upgrade").Handler(kithttp.NewServer(
+		endpoints.UpgradeCluster,
+		decodeUpgradeClusterHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeUpgradeClusterHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPost).Path("/hibernate").Handler(kithttp.NewServer(
+		endpoints.HibernateCluster,
+		decodeHibernateClusterHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeHibernateClusterHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPost).Path("/resume").Handler(kithttp.NewServer(
+		endpoints.ResumeCluster,
+		decodeResumeClusterHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeResumeClusterHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/hibernation/schedule").Handler(kithttp.NewServer(
+		endpoints.GetHibernationSchedule,
+		decodeGetHibernationScheduleHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeGetHibernationScheduleHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("/hibernation/schedule").Handler(kithttp.NewServer(
+		endpoints.SetHibernationSchedule,
+		decodeSetHibernationScheduleHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("/labels").Handler(kithttp.NewServer(
+		endpoints.UpdateClusterLabels,
+		decodeUpdateClusterLabelsHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("/deletion-protection").Handler(kithttp.NewServer(
+		endpoints.SetDeletionProtection,
+		decodeSetDeletionProtectionHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+
 	router.Methods(http.MethodPost).Path("/nodepools").Handler(kithttp.NewServer(
 		endpoints.CreateNodePool,
 		decodeCreateNodePoolHTTPRequest,
@@ -143,6 +193,172 @@ func encodeDeleteClusterHTTPResponse(_ context.Context, w http.ResponseWriter, r
 	return nil
 }
 
+func decodeUpgradeClusterHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var request pipeline.UpgradeClusterRequest
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return UpgradeClusterRequest{
+		ClusterID:         clusterID,
+		KubernetesVersion: request.Version,
+	}, nil
+}
+
+func encodeUpgradeClusterHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(UpgradeClusterResponse)
+
+	apiResp := pipeline.UpgradeClusterResponse{
+		ProcessId: resp.ProcessID,
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(apiResp, http.StatusAccepted))
+}
+
+func decodeHibernateClusterHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var request pipeline.HibernateClusterRequest
+
+	// the request body is optional
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return HibernateClusterRequest{
+		ClusterID: clusterID,
+		Options: cluster.HibernateClusterOptions{
+			KeepNodePool: request.KeepNodePool,
+		},
+	}, nil
+}
+
+func encodeHibernateClusterHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(HibernateClusterResponse)
+
+	apiResp := pipeline.HibernateClusterResponse{
+		ProcessId: resp.ProcessID,
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(apiResp, http.StatusAccepted))
+}
+
+func decodeResumeClusterHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResumeClusterRequest{
+		ClusterID: clusterID,
+	}, nil
+}
+
+func encodeResumeClusterHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(ResumeClusterResponse)
+
+	apiResp := pipeline.ResumeClusterResponse{
+		ProcessId: resp.ProcessID,
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(apiResp, http.StatusAccepted))
+}
+
+func decodeGetHibernationScheduleHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetHibernationScheduleRequest{
+		ClusterID: clusterID,
+	}, nil
+}
+
+func encodeGetHibernationScheduleHTTPResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(GetHibernationScheduleResponse)
+
+	apiResp := pipeline.HibernationSchedule{
+		Hibernate:    resp.Schedule.Hibernate,
+		Resume:       resp.Schedule.Resume,
+		KeepNodePool: resp.Schedule.KeepNodePool,
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, apiResp)
+}
+
+func decodeSetHibernationScheduleHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var request pipeline.HibernationSchedule
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return SetHibernationScheduleRequest{
+		ClusterID: clusterID,
+		Schedule: cluster.HibernationSchedule{
+			Hibernate:    request.Hibernate,
+			Resume:       request.Resume,
+			KeepNodePool: request.KeepNodePool,
+		},
+	}, nil
+}
+
+func decodeUpdateClusterLabelsHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var request pipeline.UpdateClusterLabelsRequest
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return UpdateClusterLabelsRequest{
+		ClusterID: clusterID,
+		Labels:    request.Labels,
+	}, nil
+}
+
+func decodeSetDeletionProtectionHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var request pipeline.ClusterDeletionProtection
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return SetDeletionProtectionRequest{
+		ClusterID: clusterID,
+		Enabled:   request.Enabled,
+	}, nil
+}
+
 func decodeCreateNodePoolHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	vars := mux.Vars(r)
 
@@ -234,7 +450,8 @@ func encodeUpdateNodePoolHTTPResponse(ctx context.Context, w http.ResponseWriter
 	resp := response.(UpdateNodePoolResponse)
 
 	apiResp := pipeline.UpdateNodePoolResponse{
-		ProcessId: resp.ProcessID,
+		ProcessId:              resp.ProcessID,
+		NodeReplacementChanges: resp.NodeReplacementChanges,
 	}
 
 	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(apiResp, http.StatusAccepted))
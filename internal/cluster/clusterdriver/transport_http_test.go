@@ -49,6 +49,13 @@ func TestRegisterHTTPHandlers_DeleteCluster(t *testing.T) {
 			},
 			expectedStatusCode: http.StatusAccepted,
 		},
+		{
+			name: "deletion_protected",
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return DeleteClusterResponse{Err: cluster.DeletionProtectedError{ClusterID: 1}}, nil
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
 	}
 
 	t.Run("no_field", func(t *testing.T) {
@@ -160,6 +167,111 @@ func TestRegisterHTTPHandlers_DeleteCluster(t *testing.T) {
 	})
 }
 
+func TestRegisterHTTPHandlers_UpdateClusterLabels(t *testing.T) {
+	tests := []struct {
+		name               string
+		endpointFunc       func(ctx context.Context, request interface{}) (response interface{}, err error)
+		expectedStatusCode int
+	}{
+		{
+			name: "invalid",
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return UpdateClusterLabelsResponse{Err: cluster.NewValidationError(
+					"invalid cluster labels",
+					[]string{"invalid label key"},
+				)}, nil
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "not_found",
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return UpdateClusterLabelsResponse{Err: cluster.NotFoundError{ClusterID: 1}}, nil
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "success",
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				req := request.(UpdateClusterLabelsRequest)
+				if req.ClusterID != 1 || req.Labels["env"] != "prod" {
+					return nil, fmt.Errorf("unexpected request: %+v", req)
+				}
+
+				return UpdateClusterLabelsResponse{}, nil
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			const clusterID = uint(1)
+
+			handler := mux.NewRouter()
+			RegisterHTTPHandlers(
+				Endpoints{
+					UpdateClusterLabels: test.endpointFunc,
+				},
+				handler.PathPrefix("/clusters/{clusterId}").Subrouter(),
+			)
+
+			ts := httptest.NewServer(handler)
+			defer ts.Close()
+
+			req, err := http.NewRequest(
+				http.MethodPut,
+				fmt.Sprintf("%s/clusters/%d/labels", ts.URL, clusterID),
+				strings.NewReader(`{"labels": {"env": "prod"}}`),
+			)
+			require.NoError(t, err)
+
+			resp, err := ts.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestRegisterHTTPHandlers_SetDeletionProtection(t *testing.T) {
+	const clusterID = uint(1)
+
+	var actualRequest SetDeletionProtectionRequest
+
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			SetDeletionProtection: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				actualRequest = request.(SetDeletionProtectionRequest)
+
+				return SetDeletionProtectionResponse{}, nil
+			},
+		},
+		handler.PathPrefix("/clusters/{clusterId}").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest(
+		http.MethodPut,
+		fmt.Sprintf("%s/clusters/%d/deletion-protection", ts.URL, clusterID),
+		strings.NewReader(`{"enabled": true}`),
+	)
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, SetDeletionProtectionRequest{ClusterID: clusterID, Enabled: true}, actualRequest)
+}
+
 func TestRegisterHTTPHandlers_CreateNodePool(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -351,3 +463,136 @@ func TestRegisterHTTPHandlers_DeleteNodePool(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterHTTPHandlers_HibernateCluster(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		endpointFunc       func(ctx context.Context, request interface{}) (response interface{}, err error)
+		expectedStatusCode int
+	}{
+		{
+			name: "invalid",
+			body: `{"keepNodePool": "pool1"}`,
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return HibernateClusterResponse{Err: cluster.NewValidationError(
+					"invalid cluster hibernation request",
+					[]string{`node pool "pool1" does not exist`},
+				)}, nil
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "success_without_body",
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				req := request.(HibernateClusterRequest)
+				if req.ClusterID != 1 || req.Options.KeepNodePool != "" {
+					return nil, fmt.Errorf("unexpected request: %+v", req)
+				}
+
+				return HibernateClusterResponse{ProcessID: "process"}, nil
+			},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name: "success",
+			body: `{"keepNodePool": "system"}`,
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				req := request.(HibernateClusterRequest)
+				if req.ClusterID != 1 || req.Options.KeepNodePool != "system" {
+					return nil, fmt.Errorf("unexpected request: %+v", req)
+				}
+
+				return HibernateClusterResponse{ProcessID: "process"}, nil
+			},
+			expectedStatusCode: http.StatusAccepted,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			const clusterID = uint(1)
+
+			handler := mux.NewRouter()
+			RegisterHTTPHandlers(
+				Endpoints{
+					HibernateCluster: test.endpointFunc,
+				},
+				handler.PathPrefix("/clusters/{clusterId}").Subrouter(),
+			)
+
+			ts := httptest.NewServer(handler)
+			defer ts.Close()
+
+			req, err := http.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("%s/clusters/%d/hibernate", ts.URL, clusterID),
+				strings.NewReader(test.body),
+			)
+			require.NoError(t, err)
+
+			resp, err := ts.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestRegisterHTTPHandlers_ResumeCluster(t *testing.T) {
+	tests := []struct {
+		name               string
+		endpointFunc       func(ctx context.Context, request interface{}) (response interface{}, err error)
+		expectedStatusCode int
+	}{
+		{
+			name: "not_hibernated",
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return ResumeClusterResponse{Err: cluster.NotHibernatedError{ID: 1}}, nil
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: "success",
+			endpointFunc: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return ResumeClusterResponse{ProcessID: "process"}, nil
+			},
+			expectedStatusCode: http.StatusAccepted,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			const clusterID = uint(1)
+
+			handler := mux.NewRouter()
+			RegisterHTTPHandlers(
+				Endpoints{
+					ResumeCluster: test.endpointFunc,
+				},
+				handler.PathPrefix("/clusters/{clusterId}").Subrouter(),
+			)
+
+			ts := httptest.NewServer(handler)
+			defer ts.Close()
+
+			req, err := http.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("%s/clusters/%d/resume", ts.URL, clusterID),
+				nil,
+			)
+			require.NoError(t, err)
+
+			resp, err := ts.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
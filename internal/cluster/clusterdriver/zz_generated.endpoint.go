@@ -26,10 +26,17 @@ type serviceError interface {
 // meant to be used as a helper struct, to collect all of the endpoints into a
 // single parameter.
 type Endpoints struct {
-	CreateNodePool endpoint.Endpoint
-	DeleteCluster  endpoint.Endpoint
-	DeleteNodePool endpoint.Endpoint
-	UpdateNodePool endpoint.Endpoint
+	CreateNodePool         endpoint.Endpoint
+	DeleteCluster          endpoint.Endpoint
+	DeleteNodePool         endpoint.Endpoint
+	GetHibernationSchedule endpoint.Endpoint
+	HibernateCluster       endpoint.Endpoint
+	ResumeCluster          endpoint.Endpoint
+	SetDeletionProtection  endpoint.Endpoint
+	SetHibernationSchedule endpoint.Endpoint
+	UpdateClusterLabels    endpoint.Endpoint
+	UpdateNodePool         endpoint.Endpoint
+	UpgradeCluster         endpoint.Endpoint
 }
 
 // MakeEndpoints returns a(n) Endpoints struct where each endpoint invokes
@@ -38,10 +45,17 @@ func MakeEndpoints(service cluster.Service, middleware ...endpoint.Middleware) E
 	mw := kitxendpoint.Combine(middleware...)
 
 	return Endpoints{
-		CreateNodePool: kitxendpoint.OperationNameMiddleware("cluster.CreateNodePool")(mw(MakeCreateNodePoolEndpoint(service))),
-		DeleteCluster:  kitxendpoint.OperationNameMiddleware("cluster.DeleteCluster")(mw(MakeDeleteClusterEndpoint(service))),
-		DeleteNodePool: kitxendpoint.OperationNameMiddleware("cluster.DeleteNodePool")(mw(MakeDeleteNodePoolEndpoint(service))),
-		UpdateNodePool: kitxendpoint.OperationNameMiddleware("cluster.UpdateNodePool")(mw(MakeUpdateNodePoolEndpoint(service))),
+		CreateNodePool:         kitxendpoint.OperationNameMiddleware("cluster.CreateNodePool")(mw(MakeCreateNodePoolEndpoint(service))),
+		DeleteCluster:          kitxendpoint.OperationNameMiddleware("cluster.DeleteCluster")(mw(MakeDeleteClusterEndpoint(service))),
+		DeleteNodePool:         kitxendpoint.OperationNameMiddleware("cluster.DeleteNodePool")(mw(MakeDeleteNodePoolEndpoint(service))),
+		GetHibernationSchedule: kitxendpoint.OperationNameMiddleware("cluster.GetHibernationSchedule")(mw(MakeGetHibernationScheduleEndpoint(service))),
+		HibernateCluster:       kitxendpoint.OperationNameMiddleware("cluster.HibernateCluster")(mw(MakeHibernateClusterEndpoint(service))),
+		ResumeCluster:          kitxendpoint.OperationNameMiddleware("cluster.ResumeCluster")(mw(MakeResumeClusterEndpoint(service))),
+		SetDeletionProtection:  kitxendpoint.OperationNameMiddleware("cluster.SetDeletionProtection")(mw(MakeSetDeletionProtectionEndpoint(service))),
+		SetHibernationSchedule: kitxendpoint.OperationNameMiddleware("cluster.SetHibernationSchedule")(mw(MakeSetHibernationScheduleEndpoint(service))),
+		UpdateClusterLabels:    kitxendpoint.OperationNameMiddleware("cluster.UpdateClusterLabels")(mw(MakeUpdateClusterLabelsEndpoint(service))),
+		UpdateNodePool:         kitxendpoint.OperationNameMiddleware("cluster.UpdateNodePool")(mw(MakeUpdateNodePoolEndpoint(service))),
+		UpgradeCluster:         kitxendpoint.OperationNameMiddleware("cluster.UpgradeCluster")(mw(MakeUpgradeClusterEndpoint(service))),
 	}
 }
 
@@ -161,6 +175,229 @@ func MakeDeleteNodePoolEndpoint(service cluster.Service) endpoint.Endpoint {
 	}
 }
 
+// GetHibernationScheduleRequest is a request struct for GetHibernationSchedule endpoint.
+type GetHibernationScheduleRequest struct {
+	ClusterID uint
+}
+
+// GetHibernationScheduleResponse is a response struct for GetHibernationSchedule endpoint.
+type GetHibernationScheduleResponse struct {
+	Schedule cluster.HibernationSchedule
+	Err      error
+}
+
+func (r GetHibernationScheduleResponse) Failed() error {
+	return r.Err
+}
+
+// MakeGetHibernationScheduleEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeGetHibernationScheduleEndpoint(service cluster.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetHibernationScheduleRequest)
+
+		schedule, err := service.GetHibernationSchedule(ctx, req.ClusterID)
+
+		if err != nil {
+			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
+				return GetHibernationScheduleResponse{
+					Err:      err,
+					Schedule: schedule,
+				}, nil
+			}
+
+			return GetHibernationScheduleResponse{
+				Err:      err,
+				Schedule: schedule,
+			}, err
+		}
+
+		return GetHibernationScheduleResponse{Schedule: schedule}, nil
+	}
+}
+
+// HibernateClusterRequest is a request struct for HibernateCluster endpoint.
+type HibernateClusterRequest struct {
+	ClusterID uint
+	Options   cluster.HibernateClusterOptions
+}
+
+// HibernateClusterResponse is a response struct for HibernateCluster endpoint.
+type HibernateClusterResponse struct {
+	ProcessID string
+	Err       error
+}
+
+func (r HibernateClusterResponse) Failed() error {
+	return r.Err
+}
+
+// MakeHibernateClusterEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeHibernateClusterEndpoint(service cluster.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(HibernateClusterRequest)
+
+		processID, err := service.HibernateCluster(ctx, req.ClusterID, req.Options)
+
+		if err != nil {
+			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
+				return HibernateClusterResponse{
+					Err:       err,
+					ProcessID: processID,
+				}, nil
+			}
+
+			return HibernateClusterResponse{
+				Err:       err,
+				ProcessID: processID,
+			}, err
+		}
+
+		return HibernateClusterResponse{ProcessID: processID}, nil
+	}
+}
+
+// ResumeClusterRequest is a request struct for ResumeCluster endpoint.
+type ResumeClusterRequest struct {
+	ClusterID uint
+}
+
+// ResumeClusterResponse is a response struct for ResumeCluster endpoint.
+type ResumeClusterResponse struct {
+	ProcessID string
+	Err       error
+}
+
+func (r ResumeClusterResponse) Failed() error {
+	return r.Err
+}
+
+// MakeResumeClusterEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeResumeClusterEndpoint(service cluster.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ResumeClusterRequest)
+
+		processID, err := service.ResumeCluster(ctx, req.ClusterID)
+
+		if err != nil {
+			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
+				return ResumeClusterResponse{
+					Err:       err,
+					ProcessID: processID,
+				}, nil
+			}
+
+			return ResumeClusterResponse{
+				Err:       err,
+				ProcessID: processID,
+			}, err
+		}
+
+		return ResumeClusterResponse{ProcessID: processID}, nil
+	}
+}
+
+// SetDeletionProtectionRequest is a request struct for SetDeletionProtection endpoint.
+type SetDeletionProtectionRequest struct {
+	ClusterID uint
+	Enabled   bool
+}
+
+// SetDeletionProtectionResponse is a response struct for SetDeletionProtection endpoint.
+type SetDeletionProtectionResponse struct {
+	Err error
+}
+
+func (r SetDeletionProtectionResponse) Failed() error {
+	return r.Err
+}
+
+// MakeSetDeletionProtectionEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeSetDeletionProtectionEndpoint(service cluster.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SetDeletionProtectionRequest)
+
+		err := service.SetDeletionProtection(ctx, req.ClusterID, req.Enabled)
+
+		if err != nil {
+			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
+				return SetDeletionProtectionResponse{Err: err}, nil
+			}
+
+			return SetDeletionProtectionResponse{Err: err}, err
+		}
+
+		return SetDeletionProtectionResponse{}, nil
+	}
+}
+
+// SetHibernationScheduleRequest is a request struct for SetHibernationSchedule endpoint.
+type SetHibernationScheduleRequest struct {
+	ClusterID uint
+	Schedule  cluster.HibernationSchedule
+}
+
+// SetHibernationScheduleResponse is a response struct for SetHibernationSchedule endpoint.
+type SetHibernationScheduleResponse struct {
+	Err error
+}
+
+func (r SetHibernationScheduleResponse) Failed() error {
+	return r.Err
+}
+
+// MakeSetHibernationScheduleEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeSetHibernationScheduleEndpoint(service cluster.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SetHibernationScheduleRequest)
+
+		err := service.SetHibernationSchedule(ctx, req.ClusterID, req.Schedule)
+
+		if err != nil {
+			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
+				return SetHibernationScheduleResponse{Err: err}, nil
+			}
+
+			return SetHibernationScheduleResponse{Err: err}, err
+		}
+
+		return SetHibernationScheduleResponse{}, nil
+	}
+}
+
+// UpdateClusterLabelsRequest is a request struct for UpdateClusterLabels endpoint.
+type UpdateClusterLabelsRequest struct {
+	ClusterID uint
+	Labels    map[string]string
+}
+
+// UpdateClusterLabelsResponse is a response struct for UpdateClusterLabels endpoint.
+type UpdateClusterLabelsResponse struct {
+	Err error
+}
+
+func (r UpdateClusterLabelsResponse) Failed() error {
+	return r.Err
+}
+
+// MakeUpdateClusterLabelsEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeUpdateClusterLabelsEndpoint(service cluster.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(UpdateClusterLabelsRequest)
+
+		err := service.UpdateClusterLabels(ctx, req.ClusterID, req.Labels)
+
+		if err != nil {
+			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
+				return UpdateClusterLabelsResponse{Err: err}, nil
+			}
+
+			return UpdateClusterLabelsResponse{Err: err}, err
+		}
+
+		return UpdateClusterLabelsResponse{}, nil
+	}
+}
+
 // UpdateNodePoolRequest is a request struct for UpdateNodePool endpoint.
 type UpdateNodePoolRequest struct {
 	ClusterID         uint
@@ -170,8 +407,9 @@ type UpdateNodePoolRequest struct {
 
 // UpdateNodePoolResponse is a response struct for UpdateNodePool endpoint.
 type UpdateNodePoolResponse struct {
-	ProcessID string
-	Err       error
+	ProcessID              string
+	NodeReplacementChanges []string
+	Err                    error
 }
 
 func (r UpdateNodePoolResponse) Failed() error {
@@ -183,22 +421,68 @@ func MakeUpdateNodePoolEndpoint(service cluster.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(UpdateNodePoolRequest)
 
-		processID, err := service.UpdateNodePool(ctx, req.ClusterID, req.NodePoolName, req.RawNodePoolUpdate)
+		processID, nodeReplacementChanges, err := service.UpdateNodePool(ctx, req.ClusterID, req.NodePoolName, req.RawNodePoolUpdate)
 
 		if err != nil {
 			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
 				return UpdateNodePoolResponse{
+					Err:                    err,
+					NodeReplacementChanges: nodeReplacementChanges,
+					ProcessID:              processID,
+				}, nil
+			}
+
+			return UpdateNodePoolResponse{
+				Err:                    err,
+				NodeReplacementChanges: nodeReplacementChanges,
+				ProcessID:              processID,
+			}, err
+		}
+
+		return UpdateNodePoolResponse{
+			NodeReplacementChanges: nodeReplacementChanges,
+			ProcessID:              processID,
+		}, nil
+	}
+}
+
+// UpgradeClusterRequest is a request struct for UpgradeCluster endpoint.
+type UpgradeClusterRequest struct {
+	ClusterID         uint
+	KubernetesVersion string
+}
+
+// UpgradeClusterResponse is a response struct for UpgradeCluster endpoint.
+type UpgradeClusterResponse struct {
+	ProcessID string
+	Err       error
+}
+
+func (r UpgradeClusterResponse) Failed() error {
+	return r.Err
+}
+
+// MakeUpgradeClusterEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeUpgradeClusterEndpoint(service cluster.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(UpgradeClusterRequest)
+
+		processID, err := service.UpgradeCluster(ctx, req.ClusterID, req.KubernetesVersion)
+
+		if err != nil {
+			if serviceErr := serviceError(nil); errors.As(err, &serviceErr) && serviceErr.ServiceError() {
+				return UpgradeClusterResponse{
 					Err:       err,
 					ProcessID: processID,
 				}, nil
 			}
 
-			return UpdateNodePoolResponse{
+			return UpgradeClusterResponse{
 				Err:       err,
 				ProcessID: processID,
 			}, err
 		}
 
-		return UpdateNodePoolResponse{ProcessID: processID}, nil
+		return UpgradeClusterResponse{ProcessID: processID}, nil
 	}
 }
@@ -0,0 +1,142 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterexec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+// Recorder records the output of a terminal session in the asciicast v2 format.
+//
+// See https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md
+type Recorder struct {
+	start   time.Time
+	command string
+	maxSize int
+
+	mu        sync.Mutex
+	width     uint16
+	height    uint16
+	events    bytes.Buffer
+	truncated bool
+}
+
+type asciicastHeader struct {
+	Version   int    `json:"version"`
+	Width     uint16 `json:"width"`
+	Height    uint16 `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Command   string `json:"command,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// NewRecorder returns a new Recorder.
+// Events are dropped once the recording reaches maxSize bytes.
+func NewRecorder(start time.Time, command []string, maxSize int) *Recorder {
+	return &Recorder{
+		start:   start,
+		command: strings.Join(command, " "),
+		maxSize: maxSize,
+		width:   defaultTerminalWidth,
+		height:  defaultTerminalHeight,
+	}
+}
+
+// Output records terminal output.
+func (r *Recorder) Output(t time.Time, data []byte) {
+	r.record(t, "o", string(data))
+}
+
+// Resize records a terminal size change.
+// The size before the first output event becomes the initial terminal size of the recording.
+func (r *Recorder) Resize(t time.Time, width uint16, height uint16) {
+	r.mu.Lock()
+	if r.events.Len() == 0 {
+		r.width, r.height = width, height
+		r.mu.Unlock()
+
+		return
+	}
+	r.mu.Unlock()
+
+	r.record(t, "r", fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *Recorder) record(t time.Time, eventType string, data string) {
+	elapsed := math.Round(t.Sub(r.start).Seconds()*1e6) / 1e6
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	line, err := json.Marshal([]interface{}{elapsed, eventType, data})
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.truncated {
+		return
+	}
+
+	if r.maxSize > 0 && r.events.Len()+len(line)+1 > r.maxSize {
+		r.truncated = true
+
+		return
+	}
+
+	r.events.Write(line)
+	r.events.WriteByte('\n')
+}
+
+// Bytes returns the recording.
+func (r *Recorder) Bytes() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	header := asciicastHeader{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Command:   r.command,
+	}
+
+	if r.truncated {
+		header.Title = "truncated recording"
+	}
+
+	// marshaling a struct of basic types cannot fail
+	headerLine, _ := json.Marshal(header)
+
+	recording := make([]byte, 0, len(headerLine)+1+r.events.Len())
+	recording = append(recording, headerLine...)
+	recording = append(recording, '\n')
+	recording = append(recording, r.events.Bytes()...)
+
+	return recording
+}
@@ -0,0 +1,57 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterexec
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2020, 5, 23, 10, 0, 0, 0, time.UTC)
+
+	recorder := NewRecorder(start, []string{"sh", "-c", "ls"}, 0)
+	recorder.Resize(start, 120, 40)
+	recorder.Output(start.Add(1500*time.Millisecond), []byte("hello\r\n"))
+	recorder.Resize(start.Add(2*time.Second), 100, 30)
+
+	assert.Equal(
+		t,
+		`{"version":2,"width":120,"height":40,"timestamp":1590228000,"command":"sh -c ls"}`+"\n"+
+			`[1.5,"o","hello\r\n"]`+"\n"+
+			`[2,"r","100x30"]`+"\n",
+		string(recorder.Bytes()),
+	)
+}
+
+func TestRecorder_Truncated(t *testing.T) {
+	start := time.Date(2020, 5, 23, 10, 0, 0, 0, time.UTC)
+
+	// every event line is 21 bytes long, so three of them fit
+	recorder := NewRecorder(start, nil, 64)
+	for i := 0; i < 10; i++ {
+		recorder.Output(start, []byte("0123456789"))
+	}
+
+	assert.Equal(
+		t,
+		`{"version":2,"width":80,"height":24,"timestamp":1590228000,"title":"truncated recording"}`+"\n"+
+			strings.Repeat(`[0,"o","0123456789"]`+"\n", 3),
+		string(recorder.Bytes()),
+	)
+}
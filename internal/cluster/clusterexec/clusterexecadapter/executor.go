@@ -0,0 +1,163 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterexecadapter
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+)
+
+// ConfigFactory returns a Kubernetes configuration.
+type ConfigFactory interface {
+	// FromSecret returns a config from a secret.
+	FromSecret(ctx context.Context, secretID string) (*rest.Config, error)
+}
+
+// ExecutorFactory creates SPDY executors for the exec and attach subresources of pods.
+type ExecutorFactory struct {
+	configs ConfigFactory
+}
+
+// NewExecutorFactory returns a new ExecutorFactory.
+func NewExecutorFactory(configs ConfigFactory) ExecutorFactory {
+	return ExecutorFactory{
+		configs: configs,
+	}
+}
+
+// NewExecutor implements the clusterexec.ExecutorFactory interface.
+func (f ExecutorFactory) NewExecutor(ctx context.Context, request clusterexec.Request) (clusterexec.Executor, error) {
+	config, err := f.configs.FromSecret(ctx, request.Target.ConfigSecretID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get cluster config")
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	req := client.CoreV1().RESTClient().
+		Post().
+		Namespace(request.Target.Namespace).
+		Resource("pods").
+		Name(request.Target.Pod).
+		SubResource(request.Mode)
+
+	switch request.Mode {
+	case clusterexec.ModeExec:
+		req = req.VersionedParams(&corev1.PodExecOptions{
+			Container: request.Target.Container,
+			Command:   request.Command,
+			Stdin:     request.Stdin,
+			Stdout:    true,
+			Stderr:    !request.TTY,
+			TTY:       request.TTY,
+		}, scheme.ParameterCodec)
+
+	case clusterexec.ModeAttach:
+		req = req.VersionedParams(&corev1.PodAttachOptions{
+			Container: request.Target.Container,
+			Stdin:     request.Stdin,
+			Stdout:    true,
+			Stderr:    !request.TTY,
+			TTY:       request.TTY,
+		}, scheme.ParameterCodec)
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create SPDY transport")
+	}
+
+	closableUpgrader := &closableUpgrader{Upgrader: upgrader}
+
+	executor, err := remotecommand.NewSPDYExecutorForTransports(transport, closableUpgrader, http.MethodPost, req.URL())
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create executor")
+	}
+
+	return spdyExecutor{
+		Executor: executor,
+		upgrader: closableUpgrader,
+	}, nil
+}
+
+type spdyExecutor struct {
+	remotecommand.Executor
+
+	upgrader *closableUpgrader
+}
+
+// Close implements the clusterexec.Executor interface.
+func (e spdyExecutor) Close() error {
+	return e.upgrader.Close()
+}
+
+// closableUpgrader keeps track of the upgraded connection so that streaming can be interrupted.
+type closableUpgrader struct {
+	spdy.Upgrader
+
+	mu     sync.Mutex
+	conn   httpstream.Connection
+	closed bool
+}
+
+func (u *closableUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		_ = conn.Close()
+
+		return nil, errors.New("executor closed")
+	}
+
+	u.conn = conn
+
+	return conn, nil
+}
+
+func (u *closableUpgrader) Close() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed || u.conn == nil {
+		u.closed = true
+
+		return nil
+	}
+
+	u.closed = true
+
+	return u.conn.Close()
+}
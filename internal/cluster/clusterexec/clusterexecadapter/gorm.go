@@ -0,0 +1,238 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterexecadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+)
+
+const sessionTableName = "exec_sessions"
+
+// sessionColumns are the columns of a session record without the recording.
+const sessionColumns = "id, organization_id, cluster_id, user_id, user_login, mode, namespace, pod, container, " +
+	"command, tty, started_at, ended_at, end_reason, error, recorded"
+
+type sessionModel struct {
+	ID             uint `gorm:"primary_key"`
+	OrganizationID uint `gorm:"index:idx_exec_sessions_organization_id_cluster_id"`
+	ClusterID      uint `gorm:"index:idx_exec_sessions_organization_id_cluster_id"`
+	UserID         uint
+	UserLogin      string
+	Mode           string
+	Namespace      string
+	Pod            string
+	Container      string
+	Command        string `gorm:"type:text"`
+	TTY            bool   `gorm:"column:tty"`
+	StartedAt      time.Time
+	EndedAt        *time.Time
+	EndReason      string
+	Error          string `gorm:"type:text"`
+	Recorded       bool
+	Recording      string `gorm:"type:text"`
+}
+
+func (sessionModel) TableName() string {
+	return sessionTableName
+}
+
+// Migrate executes the table migrations for the interactive sessions.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	tables := []interface{}{
+		&sessionModel{},
+	}
+
+	var tableNames string
+	for _, table := range tables {
+		tableNames += fmt.Sprintf(" %s", db.NewScope(table).TableName())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.TrimSpace(tableNames),
+	}).Info("migrating exec session tables")
+
+	return db.AutoMigrate(tables...).Error
+}
+
+// GormStore implements the clusterexec.Store interface using gorm.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+// Create implements the clusterexec.Store interface.
+func (s GormStore) Create(ctx context.Context, session clusterexec.Session) (clusterexec.Session, error) {
+	command, err := json.Marshal(session.Command)
+	if err != nil {
+		return session, errors.WrapIf(err, "failed to marshal session command")
+	}
+
+	model := sessionModel{
+		OrganizationID: session.OrganizationID,
+		ClusterID:      session.ClusterID,
+		UserID:         session.UserID,
+		UserLogin:      session.UserLogin,
+		Mode:           session.Mode,
+		Namespace:      session.Namespace,
+		Pod:            session.Pod,
+		Container:      session.Container,
+		Command:        string(command),
+		TTY:            session.TTY,
+		StartedAt:      session.StartedAt,
+		Recorded:       session.Recorded,
+	}
+
+	if err := s.db.Create(&model).Error; err != nil {
+		return session, errors.WrapIfWithDetails(err, "failed to create session", "clusterId", session.ClusterID)
+	}
+
+	session.ID = model.ID
+
+	return session, nil
+}
+
+// Finish implements the clusterexec.Store interface.
+func (s GormStore) Finish(
+	ctx context.Context,
+	id uint,
+	endedAt time.Time,
+	endReason string,
+	errorMessage string,
+	recording []byte,
+) error {
+	err := s.db.
+		Model(&sessionModel{ID: id}).
+		Updates(map[string]interface{}{
+			"ended_at":   endedAt,
+			"end_reason": endReason,
+			"error":      errorMessage,
+			"recording":  string(recording),
+		}).
+		Error
+
+	return errors.WrapIfWithDetails(err, "failed to finish session", "sessionId", id)
+}
+
+// List implements the clusterexec.Store interface.
+func (s GormStore) List(ctx context.Context, organizationID uint, clusterID uint) ([]clusterexec.Session, error) {
+	var models []sessionModel
+
+	err := s.db.
+		Select(sessionColumns).
+		Where(sessionModel{OrganizationID: organizationID, ClusterID: clusterID}).
+		Order("started_at DESC").
+		Find(&models).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list sessions", "clusterId", clusterID)
+	}
+
+	sessions := make([]clusterexec.Session, 0, len(models))
+	for _, model := range models {
+		session, err := sessionFromModel(model)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// Get implements the clusterexec.Store interface.
+func (s GormStore) Get(ctx context.Context, organizationID uint, clusterID uint, id uint) (clusterexec.Session, error) {
+	model, err := s.get(organizationID, clusterID, id, sessionColumns)
+	if err != nil {
+		return clusterexec.Session{}, err
+	}
+
+	return sessionFromModel(model)
+}
+
+// GetRecording implements the clusterexec.Store interface.
+func (s GormStore) GetRecording(ctx context.Context, organizationID uint, clusterID uint, id uint) ([]byte, error) {
+	model, err := s.get(organizationID, clusterID, id, "id, recorded, recording")
+	if err != nil {
+		return nil, err
+	}
+
+	if !model.Recorded || model.Recording == "" {
+		return nil, errors.WithStack(clusterexec.NotFoundError{ClusterID: clusterID, ID: id})
+	}
+
+	return []byte(model.Recording), nil
+}
+
+func (s GormStore) get(organizationID uint, clusterID uint, id uint, columns string) (sessionModel, error) {
+	var model sessionModel
+
+	err := s.db.
+		Select(columns).
+		Where(sessionModel{ID: id, OrganizationID: organizationID, ClusterID: clusterID}).
+		First(&model).
+		Error
+	if gorm.IsRecordNotFoundError(err) {
+		return model, errors.WithStack(clusterexec.NotFoundError{ClusterID: clusterID, ID: id})
+	} else if err != nil {
+		return model, errors.WrapIfWithDetails(err, "failed to get session", "clusterId", clusterID, "sessionId", id)
+	}
+
+	return model, nil
+}
+
+func sessionFromModel(model sessionModel) (clusterexec.Session, error) {
+	var command []string
+	if model.Command != "" {
+		if err := json.Unmarshal([]byte(model.Command), &command); err != nil {
+			return clusterexec.Session{}, errors.WrapIfWithDetails(err, "failed to unmarshal session command", "sessionId", model.ID)
+		}
+	}
+
+	return clusterexec.Session{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		ClusterID:      model.ClusterID,
+		UserID:         model.UserID,
+		UserLogin:      model.UserLogin,
+		Mode:           model.Mode,
+		Namespace:      model.Namespace,
+		Pod:            model.Pod,
+		Container:      model.Container,
+		Command:        command,
+		TTY:            model.TTY,
+		StartedAt:      model.StartedAt,
+		EndedAt:        model.EndedAt,
+		EndReason:      model.EndReason,
+		Error:          model.Error,
+		Recorded:       model.Recorded,
+	}, nil
+}
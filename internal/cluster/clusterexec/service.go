@@ -0,0 +1,514 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterexec
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"emperror.dev/errors"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/common"
+)
+
+// Client message types.
+const (
+	// MessageStdin carries input for the remote process.
+	MessageStdin = "stdin"
+
+	// MessageResize carries the new size of the client terminal.
+	MessageResize = "resize"
+)
+
+// Server message types.
+const (
+	// MessageSession is the first message of a session carrying its ID.
+	MessageSession = "session"
+
+	// MessageStdout carries the standard output (or the terminal output) of the remote process.
+	MessageStdout = "stdout"
+
+	// MessageStderr carries the standard error of the remote process.
+	MessageStderr = "stderr"
+
+	// MessageExit is the last message of a session.
+	MessageExit = "exit"
+)
+
+// ClientMessage is a message sent by the client of a session.
+type ClientMessage struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+}
+
+// ServerMessage is a message sent to the client of a session.
+type ServerMessage struct {
+	Type      string `json:"type"`
+	Data      string `json:"data,omitempty"`
+	SessionID uint   `json:"sessionId,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	ExitCode  int    `json:"exitCode,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Conn is a message based connection to the client of a session.
+type Conn interface {
+	// Receive blocks until the next message of the client arrives.
+	Receive() (ClientMessage, error)
+
+	// Send sends a message to the client.
+	Send(message ServerMessage) error
+}
+
+// Executor streams the input and output of a process in a container.
+type Executor interface {
+	// Stream blocks until the remote process exits or the executor is closed.
+	Stream(options remotecommand.StreamOptions) error
+
+	// Close terminates the connection to the container.
+	Close() error
+}
+
+// ExecutorFactory creates executors for session requests.
+type ExecutorFactory interface {
+	// NewExecutor creates an executor for a session request.
+	NewExecutor(ctx context.Context, request Request) (Executor, error)
+}
+
+// Service runs and lists interactive sessions.
+type Service struct {
+	config    Config
+	executors ExecutorFactory
+	store     Store
+	logger    common.Logger
+}
+
+// NewService returns a new Service.
+func NewService(config Config, executors ExecutorFactory, store Store, logger common.Logger) Service {
+	return Service{
+		config:    config,
+		executors: executors,
+		store:     store,
+		logger:    logger,
+	}
+}
+
+// ListSessions returns the sessions of a cluster, most recent first.
+func (s Service) ListSessions(ctx context.Context, organizationID uint, clusterID uint) ([]Session, error) {
+	return s.store.List(ctx, organizationID, clusterID)
+}
+
+// GetSession returns a session of a cluster.
+func (s Service) GetSession(ctx context.Context, organizationID uint, clusterID uint, id uint) (Session, error) {
+	return s.store.Get(ctx, organizationID, clusterID, id)
+}
+
+// GetRecording returns the asciicast recording of a session.
+func (s Service) GetRecording(ctx context.Context, organizationID uint, clusterID uint, id uint) ([]byte, error) {
+	return s.store.GetRecording(ctx, organizationID, clusterID, id)
+}
+
+// Run runs an interactive session until the remote process exits, the client goes away or a time limit is reached.
+//
+// Errors are only returned if the session could not be started.
+func (s Service) Run(ctx context.Context, conn Conn, request Request) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	if request.Record && !s.config.RecordingEnabled {
+		return errors.WithStack(cluster.NewValidationError("invalid session request", []string{"session recording is disabled"}))
+	}
+
+	executor, err := s.executors.NewExecutor(ctx, request)
+	if err != nil {
+		return errors.WrapIf(err, "failed to connect to container")
+	}
+	defer executor.Close()
+
+	startedAt := time.Now()
+
+	session, err := s.store.Create(ctx, Session{
+		OrganizationID: request.Target.OrganizationID,
+		ClusterID:      request.Target.ClusterID,
+		UserID:         request.UserID,
+		UserLogin:      request.UserLogin,
+		Mode:           request.Mode,
+		Namespace:      request.Target.Namespace,
+		Pod:            request.Target.Pod,
+		Container:      request.Target.Container,
+		Command:        request.Command,
+		TTY:            request.TTY,
+		StartedAt:      startedAt,
+		Recorded:       request.Record,
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed to create session")
+	}
+
+	logger := s.logger.WithFields(map[string]interface{}{
+		"sessionId": session.ID,
+		"clusterId": session.ClusterID,
+		"userId":    session.UserID,
+		"user":      session.UserLogin,
+		"mode":      session.Mode,
+		"namespace": session.Namespace,
+		"pod":       session.Pod,
+		"container": session.Container,
+	})
+
+	logger.Info("interactive session started", map[string]interface{}{"command": strings.Join(session.Command, " ")})
+
+	var recorder *Recorder
+	if request.Record {
+		recorder = NewRecorder(startedAt, request.Command, s.config.MaxRecordingSize)
+	}
+
+	output := &sessionOutput{conn: conn, recorder: recorder}
+
+	if err := output.send(ServerMessage{Type: MessageSession, SessionID: session.ID}); err != nil {
+		logger.Debug("failed to send session message", map[string]interface{}{"error": err.Error()})
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	term := &terminator{cancel: cancel}
+
+	if s.config.MaxSessionDuration > 0 {
+		timer := time.AfterFunc(s.config.MaxSessionDuration, func() { term.terminate(EndReasonTimeout) })
+		defer timer.Stop()
+	}
+
+	if s.config.IdleTimeout > 0 {
+		idleTimer := time.AfterFunc(s.config.IdleTimeout, func() { term.terminate(EndReasonIdle) })
+		defer idleTimer.Stop()
+
+		output.activity = func() { idleTimer.Reset(s.config.IdleTimeout) }
+	}
+
+	stdinReader, stdinWriter := io.Pipe()
+	sizes := newSizeQueue()
+
+	go func() {
+		<-runCtx.Done()
+
+		_ = stdinWriter.Close()
+		sizes.close()
+		_ = executor.Close()
+	}()
+
+	go s.receive(conn, request, stdinWriter, sizes, output, term)
+
+	options := remotecommand.StreamOptions{
+		Stdout: output.stream(MessageStdout),
+		Tty:    request.TTY,
+	}
+
+	if request.Stdin {
+		options.Stdin = stdinReader
+	}
+
+	if request.TTY {
+		options.TerminalSizeQueue = sizes
+	} else {
+		options.Stderr = output.stream(MessageStderr)
+	}
+
+	streamErr := executor.Stream(options)
+
+	alreadyTerminated := term.terminate(EndReasonCompleted)
+
+	exitMessage := ServerMessage{Type: MessageExit, Reason: term.reason()}
+	var errorMessage string
+
+	var exitErr exec.ExitError
+	switch {
+	case streamErr == nil:
+
+	case errors.As(streamErr, &exitErr):
+		exitMessage.ExitCode = exitErr.ExitStatus()
+
+	case alreadyTerminated:
+		// errors caused by closing the executor are not reported
+
+	default:
+		exitMessage.Reason = EndReasonError
+		exitMessage.Error = streamErr.Error()
+		errorMessage = streamErr.Error()
+	}
+
+	output.flush()
+
+	if err := output.send(exitMessage); err != nil {
+		logger.Debug("failed to send exit message", map[string]interface{}{"error": err.Error()})
+	}
+
+	endedAt := time.Now()
+
+	var recording []byte
+	if recorder != nil {
+		recording = recorder.Bytes()
+	}
+
+	// the session has to be recorded even if the request context is already canceled
+	err = s.store.Finish(context.Background(), session.ID, endedAt, exitMessage.Reason, errorMessage, recording)
+	if err != nil {
+		logger.Error("failed to record session end", map[string]interface{}{"error": err.Error()})
+	}
+
+	logger.Info("interactive session ended", map[string]interface{}{
+		"reason":   exitMessage.Reason,
+		"duration": endedAt.Sub(startedAt).String(),
+	})
+
+	return nil
+}
+
+// receive forwards client messages to the remote process until the client goes away.
+func (s Service) receive(
+	conn Conn,
+	request Request,
+	stdin *io.PipeWriter,
+	sizes *sizeQueue,
+	output *sessionOutput,
+	term *terminator,
+) {
+	for {
+		message, err := conn.Receive()
+		if err != nil {
+			term.terminate(EndReasonCompleted)
+
+			return
+		}
+
+		output.touch()
+
+		switch message.Type {
+		case MessageStdin:
+			if !request.Stdin {
+				continue
+			}
+
+			if _, err := stdin.Write([]byte(message.Data)); err != nil {
+				return
+			}
+
+		case MessageResize:
+			if message.Cols == 0 || message.Rows == 0 {
+				continue
+			}
+
+			if output.recorder != nil {
+				output.recorder.Resize(time.Now(), message.Cols, message.Rows)
+			}
+
+			sizes.push(remotecommand.TerminalSize{Width: message.Cols, Height: message.Rows})
+		}
+	}
+}
+
+// terminator cancels a session with the first termination reason.
+type terminator struct {
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	endReason  string
+	terminated bool
+}
+
+// terminate ends the session unless it has already been ended.
+// It returns true if the session had already been ended for another reason.
+func (t *terminator) terminate(reason string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.terminated {
+		return true
+	}
+
+	t.terminated = true
+	t.endReason = reason
+	t.cancel()
+
+	return false
+}
+
+func (t *terminator) reason() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.endReason
+}
+
+// sessionOutput sends the output of the remote process to the client and to the recorder.
+type sessionOutput struct {
+	conn     Conn
+	recorder *Recorder
+	activity func()
+
+	mu      sync.Mutex
+	streams []*outputStream
+}
+
+func (o *sessionOutput) send(message ServerMessage) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.conn.Send(message)
+}
+
+func (o *sessionOutput) touch() {
+	if o.activity != nil {
+		o.activity()
+	}
+}
+
+func (o *sessionOutput) stream(messageType string) *outputStream {
+	stream := &outputStream{output: o, messageType: messageType}
+
+	o.mu.Lock()
+	o.streams = append(o.streams, stream)
+	o.mu.Unlock()
+
+	return stream
+}
+
+// flush sends the incomplete characters left in the output streams.
+func (o *sessionOutput) flush() {
+	o.mu.Lock()
+	streams := o.streams
+	o.mu.Unlock()
+
+	for _, stream := range streams {
+		stream.flush()
+	}
+}
+
+// outputStream converts the output of the remote process to messages.
+//
+// Messages carry text, so multi-byte characters split between writes are held back until they are complete.
+type outputStream struct {
+	output      *sessionOutput
+	messageType string
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+func (w *outputStream) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data := append(w.pending, p...)
+
+	complete, rest := splitIncompleteRune(data)
+	w.pending = append([]byte(nil), rest...)
+
+	if len(complete) > 0 {
+		if err := w.write(complete); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *outputStream) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		_ = w.write(w.pending)
+		w.pending = nil
+	}
+}
+
+func (w *outputStream) write(data []byte) error {
+	w.output.touch()
+
+	if w.output.recorder != nil {
+		w.output.recorder.Output(time.Now(), data)
+	}
+
+	return w.output.send(ServerMessage{Type: w.messageType, Data: string(data)})
+}
+
+// splitIncompleteRune splits an incomplete UTF-8 encoded character from the end of a byte slice.
+func splitIncompleteRune(data []byte) ([]byte, []byte) {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+
+		if !utf8.FullRune(data[i:]) {
+			return data[:i], data[i:]
+		}
+
+		break
+	}
+
+	return data, nil
+}
+
+// sizeQueue passes terminal size changes to the executor.
+type sizeQueue struct {
+	sizes chan remotecommand.TerminalSize
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newSizeQueue() *sizeQueue {
+	return &sizeQueue{
+		sizes: make(chan remotecommand.TerminalSize, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+// push queues a terminal size, replacing the pending one.
+func (q *sizeQueue) push(size remotecommand.TerminalSize) {
+	select {
+	case <-q.sizes:
+	default:
+	}
+
+	select {
+	case q.sizes <- size:
+	default:
+	}
+}
+
+// Next implements the remotecommand.TerminalSizeQueue interface.
+func (q *sizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-q.sizes:
+		return &size
+	case <-q.done:
+		return nil
+	}
+}
+
+func (q *sizeQueue) close() {
+	q.once.Do(func() { close(q.done) })
+}
@@ -0,0 +1,392 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterexec
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+)
+
+type fakeConn struct {
+	incoming chan ClientMessage
+
+	mu       sync.Mutex
+	messages []ServerMessage
+}
+
+func newFakeConn(messages ...ClientMessage) *fakeConn {
+	conn := &fakeConn{incoming: make(chan ClientMessage, len(messages)+1)}
+	for _, message := range messages {
+		conn.incoming <- message
+	}
+
+	return conn
+}
+
+func (c *fakeConn) Receive() (ClientMessage, error) {
+	message, ok := <-c.incoming
+	if !ok {
+		return message, io.EOF
+	}
+
+	return message, nil
+}
+
+func (c *fakeConn) Send(message ServerMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = append(c.messages, message)
+
+	return nil
+}
+
+func (c *fakeConn) sent() []ServerMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]ServerMessage(nil), c.messages...)
+}
+
+type fakeExecutor struct {
+	stream func(options remotecommand.StreamOptions, closed <-chan struct{}) error
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (e *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return e.stream(options, e.closed)
+}
+
+func (e *fakeExecutor) Close() error {
+	e.closeOnce.Do(func() { close(e.closed) })
+
+	return nil
+}
+
+type fakeExecutorFactory struct {
+	executor *fakeExecutor
+	requests []Request
+}
+
+func (f *fakeExecutorFactory) NewExecutor(_ context.Context, request Request) (Executor, error) {
+	f.requests = append(f.requests, request)
+
+	return f.executor, nil
+}
+
+func newFakeExecutorFactory(stream func(options remotecommand.StreamOptions, closed <-chan struct{}) error) *fakeExecutorFactory {
+	return &fakeExecutorFactory{
+		executor: &fakeExecutor{stream: stream, closed: make(chan struct{})},
+	}
+}
+
+type fakeStore struct {
+	sessions   []Session
+	recordings map[uint][]byte
+}
+
+func (s *fakeStore) Create(_ context.Context, session Session) (Session, error) {
+	session.ID = uint(len(s.sessions) + 1)
+	s.sessions = append(s.sessions, session)
+
+	return session, nil
+}
+
+func (s *fakeStore) Finish(_ context.Context, id uint, endedAt time.Time, endReason string, errorMessage string, recording []byte) error {
+	session := &s.sessions[id-1]
+	session.EndedAt = &endedAt
+	session.EndReason = endReason
+	session.Error = errorMessage
+
+	if recording != nil {
+		if s.recordings == nil {
+			s.recordings = make(map[uint][]byte)
+		}
+
+		s.recordings[id] = recording
+	}
+
+	return nil
+}
+
+func (s *fakeStore) List(_ context.Context, _ uint, _ uint) ([]Session, error) {
+	return s.sessions, nil
+}
+
+func (s *fakeStore) Get(_ context.Context, _ uint, clusterID uint, id uint) (Session, error) {
+	if id == 0 || int(id) > len(s.sessions) {
+		return Session{}, errors.WithStack(NotFoundError{ClusterID: clusterID, ID: id})
+	}
+
+	return s.sessions[id-1], nil
+}
+
+func (s *fakeStore) GetRecording(_ context.Context, _ uint, clusterID uint, id uint) ([]byte, error) {
+	recording, ok := s.recordings[id]
+	if !ok {
+		return nil, errors.WithStack(NotFoundError{ClusterID: clusterID, ID: id})
+	}
+
+	return recording, nil
+}
+
+func newExecRequest(command ...string) Request {
+	return Request{
+		Target: Target{
+			OrganizationID: 1,
+			ClusterID:      2,
+			Namespace:      "default",
+			Pod:            "nginx",
+		},
+		UserID:    3,
+		UserLogin: "john",
+		Mode:      ModeExec,
+		Command:   command,
+	}
+}
+
+func TestService_Run_Exec(t *testing.T) {
+	executors := newFakeExecutorFactory(func(options remotecommand.StreamOptions, _ <-chan struct{}) error {
+		_, _ = options.Stdout.Write([]byte("hello\n"))
+		_, _ = options.Stderr.Write([]byte("oops\n"))
+
+		return exec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2}
+	})
+	store := &fakeStore{}
+	service := NewService(Config{MaxSessionDuration: time.Minute}, executors, store, common.NoopLogger{})
+	conn := newFakeConn()
+
+	err := service.Run(context.Background(), conn, newExecRequest("ls", "-l"))
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]ServerMessage{
+			{Type: MessageSession, SessionID: 1},
+			{Type: MessageStdout, Data: "hello\n"},
+			{Type: MessageStderr, Data: "oops\n"},
+			{Type: MessageExit, Reason: EndReasonCompleted, ExitCode: 2},
+		},
+		conn.sent(),
+	)
+
+	require.Len(t, store.sessions, 1)
+
+	session := store.sessions[0]
+	assert.Equal(t, "john", session.UserLogin)
+	assert.Equal(t, []string{"ls", "-l"}, session.Command)
+	assert.Equal(t, EndReasonCompleted, session.EndReason)
+	assert.NotNil(t, session.EndedAt)
+	assert.Empty(t, session.Error)
+	assert.Empty(t, store.recordings)
+}
+
+func TestService_Run_TerminalRecording(t *testing.T) {
+	executors := newFakeExecutorFactory(func(options remotecommand.StreamOptions, _ <-chan struct{}) error {
+		size := options.TerminalSizeQueue.Next()
+		if size == nil {
+			return errors.New("no terminal size")
+		}
+
+		input := make([]byte, 2)
+		if _, err := io.ReadFull(options.Stdin, input); err != nil {
+			return err
+		}
+
+		// split a multi-byte character between writes
+		_, _ = options.Stdout.Write(append(input, "\xc3"...))
+		_, _ = options.Stdout.Write([]byte("\xa9"))
+
+		return nil
+	})
+	store := &fakeStore{}
+	service := NewService(
+		Config{MaxSessionDuration: time.Minute, RecordingEnabled: true, MaxRecordingSize: 1024},
+		executors,
+		store,
+		common.NoopLogger{},
+	)
+	conn := newFakeConn(
+		ClientMessage{Type: MessageResize, Cols: 100, Rows: 30},
+		ClientMessage{Type: MessageStdin, Data: "hi"},
+	)
+
+	request := newExecRequest("sh")
+	request.Stdin = true
+	request.TTY = true
+	request.Record = true
+
+	err := service.Run(context.Background(), conn, request)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]ServerMessage{
+			{Type: MessageSession, SessionID: 1},
+			{Type: MessageStdout, Data: "hi"},
+			{Type: MessageStdout, Data: "é"},
+			{Type: MessageExit, Reason: EndReasonCompleted},
+		},
+		conn.sent(),
+	)
+
+	recording, err := service.GetRecording(context.Background(), 1, 2, 1)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(recording)), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"width":100,"height":30`)
+	assert.Contains(t, lines[0], `"command":"sh"`)
+	assert.Contains(t, lines[1], `"o","hi"]`)
+	assert.Contains(t, lines[2], `"o","é"]`)
+}
+
+func TestService_Run_Terminated(t *testing.T) {
+	blockUntilClosed := func(_ remotecommand.StreamOptions, closed <-chan struct{}) error {
+		<-closed
+
+		return errors.New("connection closed")
+	}
+
+	tests := map[string]struct {
+		config    Config
+		conn      func() *fakeConn
+		endReason string
+	}{
+		"timeout": {
+			config:    Config{MaxSessionDuration: 20 * time.Millisecond},
+			conn:      func() *fakeConn { return newFakeConn() },
+			endReason: EndReasonTimeout,
+		},
+		"idle": {
+			config:    Config{MaxSessionDuration: time.Minute, IdleTimeout: 20 * time.Millisecond},
+			conn:      func() *fakeConn { return newFakeConn() },
+			endReason: EndReasonIdle,
+		},
+		"client gone": {
+			config: Config{MaxSessionDuration: time.Minute},
+			conn: func() *fakeConn {
+				conn := newFakeConn()
+				close(conn.incoming)
+
+				return conn
+			},
+			endReason: EndReasonCompleted,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			store := &fakeStore{}
+			service := NewService(test.config, newFakeExecutorFactory(blockUntilClosed), store, common.NoopLogger{})
+			conn := test.conn()
+
+			err := service.Run(context.Background(), conn, newExecRequest("sh"))
+			require.NoError(t, err)
+
+			messages := conn.sent()
+			require.NotEmpty(t, messages)
+			assert.Equal(t, ServerMessage{Type: MessageExit, Reason: test.endReason}, messages[len(messages)-1])
+
+			require.Len(t, store.sessions, 1)
+			assert.Equal(t, test.endReason, store.sessions[0].EndReason)
+			assert.Empty(t, store.sessions[0].Error)
+		})
+	}
+}
+
+func TestService_Run_StreamError(t *testing.T) {
+	executors := newFakeExecutorFactory(func(_ remotecommand.StreamOptions, _ <-chan struct{}) error {
+		return errors.New("container not found")
+	})
+	store := &fakeStore{}
+	service := NewService(Config{MaxSessionDuration: time.Minute}, executors, store, common.NoopLogger{})
+	conn := newFakeConn()
+
+	err := service.Run(context.Background(), conn, newExecRequest("sh"))
+	require.NoError(t, err)
+
+	messages := conn.sent()
+	assert.Equal(
+		t,
+		ServerMessage{Type: MessageExit, Reason: EndReasonError, Error: "container not found"},
+		messages[len(messages)-1],
+	)
+	assert.Equal(t, "container not found", store.sessions[0].Error)
+}
+
+func TestService_Run_InvalidRequest(t *testing.T) {
+	tests := map[string]struct {
+		config  Config
+		request func() Request
+	}{
+		"missing command": {
+			config:  Config{MaxSessionDuration: time.Minute},
+			request: func() Request { return newExecRequest() },
+		},
+		"command on attach": {
+			config: Config{MaxSessionDuration: time.Minute},
+			request: func() Request {
+				request := newExecRequest("sh")
+				request.Mode = ModeAttach
+
+				return request
+			},
+		},
+		"recording disabled": {
+			config: Config{MaxSessionDuration: time.Minute},
+			request: func() Request {
+				request := newExecRequest("sh")
+				request.Record = true
+
+				return request
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			executors := newFakeExecutorFactory(nil)
+			store := &fakeStore{}
+			service := NewService(test.config, executors, store, common.NoopLogger{})
+
+			err := service.Run(context.Background(), newFakeConn(), test.request())
+			require.Error(t, err)
+
+			var validationErr interface{ Validation() bool }
+			assert.True(t, errors.As(err, &validationErr) && validationErr.Validation())
+			assert.Empty(t, executors.requests)
+			assert.Empty(t, store.sessions)
+		})
+	}
+}
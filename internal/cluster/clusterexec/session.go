@@ -0,0 +1,200 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterexec
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// Session modes.
+const (
+	// ModeExec runs a new command in a container.
+	ModeExec = "exec"
+
+	// ModeAttach attaches to the main process of a running container.
+	ModeAttach = "attach"
+)
+
+// Session end reasons.
+const (
+	// EndReasonCompleted means the remote process exited or the client closed the session.
+	EndReasonCompleted = "completed"
+
+	// EndReasonTimeout means the session reached the maximum session duration.
+	EndReasonTimeout = "timeout"
+
+	// EndReasonIdle means there was no input or output for the idle timeout.
+	EndReasonIdle = "idle"
+
+	// EndReasonError means the session failed.
+	EndReasonError = "error"
+)
+
+// Config contains the limits of interactive sessions.
+type Config struct {
+	// MaxSessionDuration is the time after which sessions are terminated.
+	MaxSessionDuration time.Duration
+
+	// IdleTimeout is the time without input or output after which sessions are terminated.
+	IdleTimeout time.Duration
+
+	// RecordingEnabled allows clients to record sessions.
+	RecordingEnabled bool
+
+	// MaxRecordingSize is the maximum size of a recording in bytes. Recordings are truncated above this size.
+	MaxRecordingSize int
+}
+
+// Target identifies the container of a session.
+type Target struct {
+	OrganizationID uint
+	ClusterID      uint
+
+	// ConfigSecretID is the ID of the secret containing the cluster kubeconfig.
+	ConfigSecretID string
+
+	Namespace string
+	Pod       string
+	Container string
+}
+
+// Request describes a new interactive session.
+type Request struct {
+	Target Target
+
+	UserID    uint
+	UserLogin string
+
+	Mode    string
+	Command []string
+	Stdin   bool
+	TTY     bool
+	Record  bool
+}
+
+// Session is the audit record of an interactive session.
+type Session struct {
+	ID             uint
+	OrganizationID uint
+	ClusterID      uint
+	UserID         uint
+	UserLogin      string
+
+	Mode      string
+	Namespace string
+	Pod       string
+	Container string
+	Command   []string
+	TTY       bool
+
+	StartedAt time.Time
+	EndedAt   *time.Time
+	EndReason string
+	Error     string
+
+	Recorded bool
+}
+
+// Duration returns the length of a finished session or the time elapsed since the start of a running one.
+func (s Session) Duration(now time.Time) time.Duration {
+	if s.EndedAt != nil {
+		return s.EndedAt.Sub(s.StartedAt)
+	}
+
+	return now.Sub(s.StartedAt)
+}
+
+// Store persists interactive session records.
+type Store interface {
+	// Create persists a new session and returns it with its ID.
+	Create(ctx context.Context, session Session) (Session, error)
+
+	// Finish records the end of a session along with its recording (if any).
+	Finish(ctx context.Context, id uint, endedAt time.Time, endReason string, errorMessage string, recording []byte) error
+
+	// List returns the sessions of a cluster, most recent first.
+	List(ctx context.Context, organizationID uint, clusterID uint) ([]Session, error)
+
+	// Get returns a session of a cluster.
+	// Returns a NotFoundError when the session does not exist.
+	Get(ctx context.Context, organizationID uint, clusterID uint, id uint) (Session, error)
+
+	// GetRecording returns the asciicast recording of a session.
+	// Returns a NotFoundError when the session does not exist or was not recorded.
+	GetRecording(ctx context.Context, organizationID uint, clusterID uint, id uint) ([]byte, error)
+}
+
+// NotFoundError is returned when a session or its recording cannot be found.
+type NotFoundError struct {
+	ClusterID uint
+	ID        uint
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "session not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ClusterID, "sessionId", e.ID}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to status codes for example.
+func (NotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (NotFoundError) ServiceError() bool {
+	return true
+}
+
+// Validate validates a session request.
+func (r Request) Validate() error {
+	var violations []string
+
+	if r.Target.Namespace == "" || r.Target.Pod == "" {
+		violations = append(violations, "namespace and pod are required")
+	}
+
+	switch r.Mode {
+	case ModeExec:
+		if len(r.Command) == 0 {
+			violations = append(violations, "command is required")
+		}
+
+	case ModeAttach:
+		if len(r.Command) > 0 {
+			violations = append(violations, "command cannot be specified when attaching")
+		}
+
+	default:
+		violations = append(violations, "mode must be exec or attach")
+	}
+
+	if len(violations) > 0 {
+		return errors.WithStack(cluster.NewValidationError("invalid session request", violations))
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealth
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+)
+
+// Checker checks the health of running clusters.
+type Checker struct {
+	prober Prober
+	store  Store
+	events Events
+
+	logger common.Logger
+}
+
+// NewChecker returns a new Checker.
+func NewChecker(
+	prober Prober,
+	store Store,
+	events Events,
+	logger common.Logger,
+) Checker {
+	return Checker{
+		prober: prober,
+		store:  store,
+		events: events,
+		logger: logger,
+	}
+}
+
+// CheckCluster checks the health of a cluster and stores the result.
+// An event is emitted when the health status of the cluster changes.
+func (c Checker) CheckCluster(ctx context.Context, cluster Cluster) (Health, error) {
+	checks := c.prober.Probe(ctx, cluster)
+	now := time.Now().UTC()
+
+	health := Health{
+		ClusterID:      cluster.ID,
+		OrganizationID: cluster.OrganizationID,
+		ClusterName:    cluster.Name,
+		Status:         overallStatus(checks),
+		Checks:         checks,
+		CheckedAt:      now,
+		TransitionedAt: now,
+	}
+
+	var previousStatus string
+
+	previous, err := c.store.Get(ctx, cluster.ID)
+	if err == nil {
+		previousStatus = previous.Status
+
+		if previous.Status == health.Status {
+			health.TransitionedAt = previous.TransitionedAt
+		}
+	} else if notFoundErr := (NotFoundError{}); !errors.As(err, &notFoundErr) {
+		return health, err
+	}
+
+	if err := c.store.Save(ctx, health); err != nil {
+		return health, err
+	}
+
+	// a healthy cluster checked for the first time is not a transition worth reporting
+	if previousStatus != health.Status && (previousStatus != "" || health.Status != StatusHealthy) {
+		c.logger.Info("cluster health changed", map[string]interface{}{
+			"clusterId":      cluster.ID,
+			"previousStatus": previousStatus,
+			"status":         health.Status,
+		})
+
+		c.events.HealthChanged(HealthChangedEvent{
+			ClusterID:      cluster.ID,
+			OrganizationID: cluster.OrganizationID,
+			ClusterName:    cluster.Name,
+			PreviousStatus: previousStatus,
+			Status:         health.Status,
+		})
+	}
+
+	return health, nil
+}
+
+// RemoveStaleHealth deletes the stored health of the clusters that are no longer running.
+func (c Checker) RemoveStaleHealth(ctx context.Context, runningClusterIDs []uint) error {
+	running := make(map[uint]bool, len(runningClusterIDs))
+	for _, clusterID := range runningClusterIDs {
+		running[clusterID] = true
+	}
+
+	healths, err := c.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, health := range healths {
+		if running[health.ClusterID] {
+			continue
+		}
+
+		errs = errors.Append(errs, c.store.Delete(ctx, health.ClusterID))
+	}
+
+	return errs
+}
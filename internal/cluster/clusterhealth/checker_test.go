@@ -0,0 +1,131 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+)
+
+type fakeProber map[uint][]Check
+
+func (p fakeProber) Probe(_ context.Context, cluster Cluster) []Check {
+	return p[cluster.ID]
+}
+
+// fakeBackend implements the Store and Events interfaces.
+type fakeBackend struct {
+	health map[uint]Health
+	events []HealthChangedEvent
+}
+
+func (b *fakeBackend) Get(_ context.Context, clusterID uint) (Health, error) {
+	health, ok := b.health[clusterID]
+	if !ok {
+		return Health{}, NotFoundError{ClusterID: clusterID}
+	}
+
+	return health, nil
+}
+
+func (b *fakeBackend) List(_ context.Context) ([]Health, error) {
+	healths := make([]Health, 0, len(b.health))
+	for _, health := range b.health {
+		healths = append(healths, health)
+	}
+
+	return healths, nil
+}
+
+func (b *fakeBackend) Save(_ context.Context, health Health) error {
+	b.health[health.ClusterID] = health
+
+	return nil
+}
+
+func (b *fakeBackend) Delete(_ context.Context, clusterID uint) error {
+	delete(b.health, clusterID)
+
+	return nil
+}
+
+func (b *fakeBackend) HealthChanged(event HealthChangedEvent) {
+	b.events = append(b.events, event)
+}
+
+func TestChecker_CheckCluster(t *testing.T) {
+	healthy := Cluster{ID: 1, OrganizationID: 1, Name: "healthy"}
+	broken := Cluster{ID: 2, OrganizationID: 1, Name: "broken"}
+
+	prober := fakeProber{
+		1: {{Name: CheckAPIServer, Status: StatusHealthy}},
+		2: {{Name: CheckAPIServer, Status: StatusHealthy}, {Name: CheckNodes, Status: StatusDegraded}},
+	}
+
+	backend := &fakeBackend{
+		health: make(map[uint]Health),
+	}
+
+	checker := NewChecker(prober, backend, backend, common.NoopLogger{})
+
+	for _, cluster := range []Cluster{healthy, broken} {
+		_, err := checker.CheckCluster(context.Background(), cluster)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, StatusHealthy, backend.health[1].Status)
+	assert.Equal(t, StatusDegraded, backend.health[2].Status)
+
+	// only unhealthy clusters are reported when checked for the first time
+	assert.Equal(t, []HealthChangedEvent{
+		{ClusterID: 2, OrganizationID: 1, ClusterName: "broken", Status: StatusDegraded},
+	}, backend.events)
+
+	transitionedAt := backend.health[2].TransitionedAt
+
+	prober[1] = []Check{{Name: CheckAPIServer, Status: StatusUnhealthy}}
+
+	for _, cluster := range []Cluster{healthy, broken} {
+		_, err := checker.CheckCluster(context.Background(), cluster)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, StatusUnhealthy, backend.health[1].Status)
+	assert.Equal(t, transitionedAt, backend.health[2].TransitionedAt, "unchanged status keeps the transition time")
+	require.Len(t, backend.events, 2)
+	assert.Equal(t, HealthChangedEvent{
+		ClusterID: 1, OrganizationID: 1, ClusterName: "healthy", PreviousStatus: StatusHealthy, Status: StatusUnhealthy,
+	}, backend.events[1])
+}
+
+func TestChecker_RemoveStaleHealth(t *testing.T) {
+	backend := &fakeBackend{
+		health: map[uint]Health{
+			1: {ClusterID: 1, Status: StatusHealthy},
+			2: {ClusterID: 2, Status: StatusUnhealthy},
+		},
+	}
+
+	checker := NewChecker(fakeProber{}, backend, backend, common.NoopLogger{})
+
+	// the stored health of clusters no longer running is removed, even if they were checked by another worker
+	require.NoError(t, checker.RemoveStaleHealth(context.Background(), []uint{1, 3}))
+
+	assert.Equal(t, map[uint]Health{1: {ClusterID: 1, Status: StatusHealthy}}, backend.health)
+}
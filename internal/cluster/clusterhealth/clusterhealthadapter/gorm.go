@@ -0,0 +1,190 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealthadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const healthTableName = "cluster_health"
+
+type healthModel struct {
+	ID             uint `gorm:"primary_key"`
+	ClusterID      uint `gorm:"unique_index:idx_cluster_health_cluster_id"`
+	OrganizationID uint
+	ClusterName    string
+	Status         string
+	Checks         string `gorm:"type:text"`
+	CheckedAt      time.Time
+	TransitionedAt time.Time
+}
+
+func (healthModel) TableName() string {
+	return healthTableName
+}
+
+// Migrate executes the table migrations for the cluster health.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	tables := []interface{}{
+		&healthModel{},
+	}
+
+	var tableNames string
+	for _, table := range tables {
+		tableNames += fmt.Sprintf(" %s", db.NewScope(table).TableName())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.TrimSpace(tableNames),
+	}).Info("migrating cluster health tables")
+
+	return db.AutoMigrate(tables...).Error
+}
+
+// GormStore implements the clusterhealth.Store interface using gorm.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+// Get implements the clusterhealth.Store interface.
+func (s GormStore) Get(ctx context.Context, clusterID uint) (clusterhealth.Health, error) {
+	var model healthModel
+
+	err := s.db.Where(healthModel{ClusterID: clusterID}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return clusterhealth.Health{}, errors.WithStack(clusterhealth.NotFoundError{ClusterID: clusterID})
+	} else if err != nil {
+		return clusterhealth.Health{}, errors.WrapIfWithDetails(err, "failed to get cluster health", "clusterId", clusterID)
+	}
+
+	return toHealth(model)
+}
+
+// List implements the clusterhealth.Store interface.
+func (s GormStore) List(ctx context.Context) ([]clusterhealth.Health, error) {
+	var models []healthModel
+
+	if err := s.db.Order("cluster_id").Find(&models).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to list cluster health")
+	}
+
+	healths := make([]clusterhealth.Health, 0, len(models))
+	for _, model := range models {
+		health, err := toHealth(model)
+		if err != nil {
+			return nil, err
+		}
+
+		healths = append(healths, health)
+	}
+
+	return healths, nil
+}
+
+func toHealth(model healthModel) (clusterhealth.Health, error) {
+	var checks []clusterhealth.Check
+	if err := json.Unmarshal([]byte(model.Checks), &checks); err != nil {
+		return clusterhealth.Health{}, errors.WrapIfWithDetails(err, "failed to unmarshal health checks", "clusterId", model.ClusterID)
+	}
+
+	return clusterhealth.Health{
+		ClusterID:      model.ClusterID,
+		OrganizationID: model.OrganizationID,
+		ClusterName:    model.ClusterName,
+		Status:         model.Status,
+		Checks:         checks,
+		CheckedAt:      model.CheckedAt,
+		TransitionedAt: model.TransitionedAt,
+	}, nil
+}
+
+// Save implements the clusterhealth.Store interface.
+func (s GormStore) Save(ctx context.Context, health clusterhealth.Health) error {
+	checks, err := json.Marshal(health.Checks)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to marshal health checks", "clusterId", health.ClusterID)
+	}
+
+	var model healthModel
+
+	err = s.db.
+		Where(healthModel{ClusterID: health.ClusterID}).
+		Assign(map[string]interface{}{
+			"organization_id": health.OrganizationID,
+			"cluster_name":    health.ClusterName,
+			"status":          health.Status,
+			"checks":          string(checks),
+			"checked_at":      health.CheckedAt,
+			"transitioned_at": health.TransitionedAt,
+		}).
+		FirstOrCreate(&model).
+		Error
+
+	return errors.WrapIfWithDetails(err, "failed to save cluster health", "clusterId", health.ClusterID)
+}
+
+// Delete implements the clusterhealth.Store interface.
+func (s GormStore) Delete(ctx context.Context, clusterID uint) error {
+	err := s.db.Where(healthModel{ClusterID: clusterID}).Delete(&healthModel{}).Error
+
+	return errors.WrapIfWithDetails(err, "failed to delete cluster health", "clusterId", clusterID)
+}
+
+// ClusterFinder finds the running clusters.
+type ClusterFinder struct {
+	db *gorm.DB
+}
+
+// NewClusterFinder returns a new ClusterFinder.
+func NewClusterFinder(db *gorm.DB) ClusterFinder {
+	return ClusterFinder{
+		db: db,
+	}
+}
+
+// FindClusters implements the clusterhealth.ClusterFinder interface.
+func (f ClusterFinder) FindClusters(ctx context.Context) ([]clusterhealth.Cluster, error) {
+	var clusters []clusterhealth.Cluster
+
+	err := f.db.
+		Table("clusters").
+		Where("deleted_at IS NULL AND status IN (?)", []string{pkgCluster.Running, pkgCluster.Warning}).
+		Select("id, organization_id, name").
+		Order("id").
+		Scan(&clusters).
+		Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to query clusters")
+	}
+
+	return clusters, nil
+}
@@ -0,0 +1,64 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealthworkflow
+
+import (
+	"context"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth"
+)
+
+const ListClustersActivityName = "cluster-health-check-list-clusters"
+
+type ListClustersActivityInput struct{}
+
+const CheckClusterActivityName = "cluster-health-check-cluster"
+
+type CheckClusterActivityInput struct {
+	Cluster clusterhealth.Cluster
+}
+
+const RemoveStaleHealthActivityName = "cluster-health-check-remove-stale"
+
+type RemoveStaleHealthActivityInput struct {
+	RunningClusterIDs []uint
+}
+
+// CheckActivities implements the activities of the health check workflow.
+type CheckActivities struct {
+	clusterFinder clusterhealth.ClusterFinder
+	checker       clusterhealth.Checker
+}
+
+// NewCheckActivities returns a new CheckActivities.
+func NewCheckActivities(clusterFinder clusterhealth.ClusterFinder, checker clusterhealth.Checker) CheckActivities {
+	return CheckActivities{
+		clusterFinder: clusterFinder,
+		checker:       checker,
+	}
+}
+
+func (a CheckActivities) ListClusters(ctx context.Context, _ ListClustersActivityInput) ([]clusterhealth.Cluster, error) {
+	return a.clusterFinder.FindClusters(ctx)
+}
+
+func (a CheckActivities) CheckCluster(ctx context.Context, input CheckClusterActivityInput) error {
+	_, err := a.checker.CheckCluster(ctx, input.Cluster)
+
+	return err
+}
+
+func (a CheckActivities) RemoveStaleHealth(ctx context.Context, input RemoveStaleHealthActivityInput) error {
+	return a.checker.RemoveStaleHealth(ctx, input.RunningClusterIDs)
+}
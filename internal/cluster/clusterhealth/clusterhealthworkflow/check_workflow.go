@@ -0,0 +1,77 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealthworkflow
+
+import (
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth"
+)
+
+// CheckWorkflowName is the name of the workflow checking the health of every running cluster.
+const CheckWorkflowName = "cluster-health-check"
+
+// CheckWorkflowInput defines the inputs of the health check workflow.
+type CheckWorkflowInput struct{}
+
+// CheckWorkflow checks the health of every running cluster
+// and removes the stored health of the clusters that are no longer running.
+func CheckWorkflow(ctx workflow.Context, _ CheckWorkflowInput) error {
+	logger := workflow.GetLogger(ctx).Sugar()
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    5 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy: &cadence.RetryPolicy{
+			InitialInterval:    15 * time.Second,
+			BackoffCoefficient: 1.5,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    3,
+		},
+	})
+
+	var clusters []clusterhealth.Cluster
+	if err := workflow.ExecuteActivity(ctx, ListClustersActivityName, ListClustersActivityInput{}).Get(ctx, &clusters); err != nil {
+		return err
+	}
+
+	futures := make([]workflow.Future, 0, len(clusters))
+	for _, cluster := range clusters {
+		activityInput := CheckClusterActivityInput{
+			Cluster: cluster,
+		}
+
+		futures = append(futures, workflow.ExecuteActivity(ctx, CheckClusterActivityName, activityInput))
+	}
+
+	clusterIDs := make([]uint, 0, len(clusters))
+	for i, future := range futures {
+		clusterIDs = append(clusterIDs, clusters[i].ID)
+
+		if err := future.Get(ctx, nil); err != nil {
+			// a failing cluster should not prevent checking the others
+			logger.Errorw("failed to check cluster health", "clusterId", clusters[i].ID, "error", err.Error())
+		}
+	}
+
+	activityInput := RemoveStaleHealthActivityInput{
+		RunningClusterIDs: clusterIDs,
+	}
+
+	return workflow.ExecuteActivity(ctx, RemoveStaleHealthActivityName, activityInput).Get(ctx, nil)
+}
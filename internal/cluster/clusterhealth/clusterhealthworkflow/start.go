@@ -0,0 +1,47 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealthworkflow
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+)
+
+const checkWorkflowID = "cluster-health-check"
+
+// checkWorkflowTimeout bounds a single run of the workflow, a longer run delays the next scheduled one.
+const checkWorkflowTimeout = 30 * time.Minute
+
+// StartCheckWorkflow starts the health check workflow with a cron schedule unless it is already running.
+// Changing the schedule requires terminating the running workflow first.
+func StartCheckWorkflow(ctx context.Context, cadenceClient client.Client, schedule string) error {
+	options := client.StartWorkflowOptions{
+		ID:                           checkWorkflowID,
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: checkWorkflowTimeout,
+		CronSchedule:                 schedule,
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+	}
+
+	_, err := cadenceClient.StartWorkflow(ctx, options, CheckWorkflowName, CheckWorkflowInput{})
+	if _, ok := err.(*shared.WorkflowExecutionAlreadyStartedError); ok {
+		return nil
+	}
+
+	return errors.WrapIfWithDetails(err, "failed to start the health check workflow", "workflowId", options.ID)
+}
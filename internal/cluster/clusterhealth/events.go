@@ -0,0 +1,38 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealth
+
+type eventBus interface {
+	Publish(topic string, args ...interface{})
+}
+
+// HealthChangedTopic is the event bus topic of the cluster health transitions.
+const HealthChangedTopic = "cluster_health_changed"
+
+// EventBusEvents publishes health events to an event bus.
+type EventBusEvents struct {
+	eb eventBus
+}
+
+// NewEventBusEvents returns a new EventBusEvents.
+func NewEventBusEvents(eb eventBus) EventBusEvents {
+	return EventBusEvents{
+		eb: eb,
+	}
+}
+
+// HealthChanged implements the Events interface.
+func (e EventBusEvents) HealthChanged(event HealthChangedEvent) {
+	e.eb.Publish(HealthChangedTopic, event)
+}
@@ -0,0 +1,165 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealth
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// Health statuses in order of severity.
+const (
+	StatusHealthy   = "HEALTHY"
+	StatusDegraded  = "DEGRADED"
+	StatusUnknown   = "UNKNOWN"
+	StatusUnhealthy = "UNHEALTHY"
+)
+
+// Names of the health checks.
+const (
+	CheckAPIServer    = "apiServer"
+	CheckNodes        = "nodes"
+	CheckSystemPods   = "systemPods"
+	CheckPipelinePods = "pipelinePods"
+	CheckCertificates = "certificates"
+)
+
+// Check is the result of a single health check.
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Health is the result of the latest health check of a cluster.
+type Health struct {
+	ClusterID      uint
+	OrganizationID uint
+	ClusterName    string
+
+	Status string
+	Checks []Check
+
+	CheckedAt time.Time
+
+	// TransitionedAt is the time the cluster got into its current status.
+	TransitionedAt time.Time
+}
+
+// Cluster identifies a cluster to be checked.
+type Cluster struct {
+	ID             uint
+	OrganizationID uint
+	Name           string
+}
+
+// ClusterFinder finds the clusters that should be checked.
+type ClusterFinder interface {
+	// FindClusters returns the running clusters.
+	FindClusters(ctx context.Context) ([]Cluster, error)
+}
+
+// ConfigGetter returns the stored kubeconfig of a cluster.
+type ConfigGetter interface {
+	// GetKubeConfig gets a kube config for a specific cluster.
+	GetKubeConfig(ctx context.Context, clusterID uint) (*rest.Config, error)
+}
+
+// Store persists the health of clusters.
+type Store interface {
+	// Get returns the health of a cluster.
+	// Returns an error with the NotFound behavior when the cluster has not been checked yet.
+	Get(ctx context.Context, clusterID uint) (Health, error)
+
+	// List returns the health of every checked cluster.
+	List(ctx context.Context) ([]Health, error)
+
+	// Save saves the health of a cluster.
+	Save(ctx context.Context, health Health) error
+
+	// Delete deletes the health of a cluster.
+	Delete(ctx context.Context, clusterID uint) error
+}
+
+// HealthChangedEvent is emitted when the health status of a cluster changes.
+type HealthChangedEvent struct {
+	ClusterID      uint
+	OrganizationID uint
+	ClusterName    string
+
+	// PreviousStatus is empty when the cluster is checked for the first time.
+	PreviousStatus string
+	Status         string
+}
+
+// Events emits health events.
+type Events interface {
+	// HealthChanged is emitted when the health status of a cluster changes.
+	HealthChanged(event HealthChangedEvent)
+}
+
+// NotFoundError is returned when the health of a cluster cannot be found.
+type NotFoundError struct {
+	ClusterID uint
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "cluster health not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ClusterID}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to status codes for example.
+func (NotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (NotFoundError) ServiceError() bool {
+	return true
+}
+
+// overallStatus returns the most severe status of the checks.
+func overallStatus(checks []Check) string {
+	status := StatusHealthy
+
+	for _, check := range checks {
+		if severity(check.Status) > severity(status) {
+			status = check.Status
+		}
+	}
+
+	return status
+}
+
+func severity(status string) int {
+	switch status {
+	case StatusHealthy:
+		return 0
+	case StatusDegraded:
+		return 1
+	case StatusUnknown:
+		return 2
+	default:
+		return 3
+	}
+}
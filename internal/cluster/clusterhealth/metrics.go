@@ -0,0 +1,87 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealth
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusCollector exports the stored health of clusters as Prometheus metrics.
+//
+// The health is read from the store on every scrape, so every instance exports the latest results
+// regardless of which worker checked the clusters.
+type PrometheusCollector struct {
+	store   Store
+	timeout time.Duration
+
+	healthy *prometheus.Desc
+}
+
+// NewPrometheusCollector returns a new PrometheusCollector.
+func NewPrometheusCollector(store Store, timeout time.Duration) PrometheusCollector {
+	return PrometheusCollector{
+		store:   store,
+		timeout: timeout,
+		healthy: prometheus.NewDesc(
+			"pipeline_cluster_healthy",
+			"Whether a cluster health check passes (1) or not (0), the overall status is exported as the 'cluster' check",
+			[]string{"orgId", "clusterId", "clusterName", "check", "status"},
+			nil,
+		),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.healthy
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	healths, err := c.store.List(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.healthy, err)
+
+		return
+	}
+
+	for _, health := range healths {
+		checks := append([]Check{{Name: "cluster", Status: health.Status}}, health.Checks...)
+
+		for _, check := range checks {
+			var value float64
+			if check.Status == StatusHealthy {
+				value = 1
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				c.healthy,
+				prometheus.GaugeValue,
+				value,
+				strconv.FormatUint(uint64(health.OrganizationID), 10),
+				strconv.FormatUint(uint64(health.ClusterID), 10),
+				health.ClusterName,
+				check.Name,
+				check.Status,
+			)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/resourcesummary"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// pendingPodTimeout is the time after which a pending pod is considered unhealthy.
+const pendingPodTimeout = 10 * time.Minute
+
+// maxReportedNames is the maximum number of unhealthy resources listed in a check message.
+const maxReportedNames = 5
+
+// ProberConfig contains the configuration of the health checks.
+type ProberConfig struct {
+	// Timeout of the requests sent to the API server.
+	Timeout time.Duration
+
+	// PipelineNamespace is the namespace Pipeline components are installed to.
+	PipelineNamespace string
+
+	// CertificateExpiryWarning is the remaining validity under which certificates are reported.
+	CertificateExpiryWarning time.Duration
+}
+
+// Prober checks the health of a cluster.
+type Prober interface {
+	// Probe runs the health checks of a cluster.
+	Probe(ctx context.Context, cluster Cluster) []Check
+}
+
+// KubernetesProber checks the health of a cluster through its stored kubeconfig.
+type KubernetesProber struct {
+	configs ConfigGetter
+	config  ProberConfig
+}
+
+// NewKubernetesProber returns a new KubernetesProber.
+func NewKubernetesProber(configs ConfigGetter, config ProberConfig) KubernetesProber {
+	return KubernetesProber{
+		configs: configs,
+		config:  config,
+	}
+}
+
+// Probe implements the Prober interface.
+func (p KubernetesProber) Probe(ctx context.Context, cluster Cluster) []Check {
+	// kubeconfigs are stored as organization secrets
+	ctx = auth.SetCurrentOrganizationID(ctx, cluster.OrganizationID)
+
+	config, err := p.configs.GetKubeConfig(ctx, cluster.ID)
+	if err != nil {
+		return unknownChecks(fmt.Sprintf("failed to get kubeconfig: %s", err.Error()))
+	}
+
+	config = rest.CopyConfig(config)
+	config.Timeout = p.config.Timeout
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return unknownChecks(fmt.Sprintf("failed to create kubernetes client: %s", err.Error()))
+	}
+
+	return probeCluster(client, config, p.config, time.Now())
+}
+
+func unknownChecks(message string) []Check {
+	return []Check{
+		{Name: CheckAPIServer, Status: StatusUnknown, Message: message},
+		{Name: CheckNodes, Status: StatusUnknown},
+		{Name: CheckSystemPods, Status: StatusUnknown},
+		{Name: CheckPipelinePods, Status: StatusUnknown},
+		{Name: CheckCertificates, Status: StatusUnknown},
+	}
+}
+
+func probeCluster(client kubernetes.Interface, config *rest.Config, proberConfig ProberConfig, now time.Time) []Check {
+	certificates := checkCertificates(config, proberConfig.CertificateExpiryWarning, now)
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return []Check{
+			{Name: CheckAPIServer, Status: StatusUnhealthy, Message: fmt.Sprintf("API server is not reachable: %s", err.Error())},
+			{Name: CheckNodes, Status: StatusUnknown},
+			{Name: CheckSystemPods, Status: StatusUnknown},
+			{Name: CheckPipelinePods, Status: StatusUnknown},
+			certificates,
+		}
+	}
+
+	return []Check{
+		{Name: CheckAPIServer, Status: StatusHealthy, Message: fmt.Sprintf("API server version %s", version.GitVersion)},
+		checkNodes(client),
+		checkPods(client, CheckSystemPods, metav1.NamespaceSystem, now),
+		checkPods(client, CheckPipelinePods, proberConfig.PipelineNamespace, now),
+		certificates,
+	}
+}
+
+func checkNodes(client kubernetes.Interface) Check {
+	check := Check{Name: CheckNodes}
+
+	nodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		check.Status = StatusUnknown
+		check.Message = fmt.Sprintf("failed to list nodes: %s", err.Error())
+
+		return check
+	}
+
+	var notReady []string
+	for _, node := range nodes.Items {
+		if resourcesummary.GetNodeStatus(node) != resourcesummary.StatusReady {
+			notReady = append(notReady, node.Name)
+		}
+	}
+
+	switch {
+	case len(nodes.Items) == 0:
+		check.Status = StatusUnhealthy
+		check.Message = "no nodes found"
+
+	case len(notReady) == len(nodes.Items):
+		check.Status = StatusUnhealthy
+		check.Message = fmt.Sprintf("none of the %d nodes are ready", len(nodes.Items))
+
+	case len(notReady) > 0:
+		check.Status = StatusDegraded
+		check.Message = fmt.Sprintf("%d of %d nodes are not ready: %s", len(notReady), len(nodes.Items), joinNames(notReady))
+
+	default:
+		check.Status = StatusHealthy
+		check.Message = fmt.Sprintf("%d nodes are ready", len(nodes.Items))
+	}
+
+	return check
+}
+
+func checkPods(client kubernetes.Interface, name string, namespace string, now time.Time) Check {
+	check := Check{Name: name}
+
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		check.Status = StatusUnknown
+		check.Message = fmt.Sprintf("failed to list pods in namespace %s: %s", namespace, err.Error())
+
+		return check
+	}
+
+	var unhealthy []string
+	for _, pod := range pods.Items {
+		if reason := podProblem(pod, now); reason != "" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", pod.Name, reason))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		check.Status = StatusDegraded
+		check.Message = fmt.Sprintf("%d pods are unhealthy in namespace %s: %s", len(unhealthy), namespace, joinNames(unhealthy))
+
+		return check
+	}
+
+	check.Status = StatusHealthy
+
+	return check
+}
+
+// podProblem returns the reason a pod is considered unhealthy or an empty string.
+func podProblem(pod corev1.Pod, now time.Time) string {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return ""
+
+	case corev1.PodFailed:
+		// failed attempts of jobs are retried by the job controller
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == "Job" {
+				return ""
+			}
+		}
+
+		return "Failed"
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			switch waiting.Reason {
+			case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError":
+				return waiting.Reason
+			}
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodPending && now.Sub(pod.CreationTimestamp.Time) > pendingPodTimeout {
+		return "Pending"
+	}
+
+	return ""
+}
+
+func checkCertificates(config *rest.Config, expiryWarning time.Duration, now time.Time) Check {
+	check := Check{Name: CheckCertificates}
+
+	var certificates []*x509.Certificate
+	for _, data := range [][]byte{config.TLSClientConfig.CAData, config.TLSClientConfig.CertData} {
+		certs, err := parseCertificates(data)
+		if err != nil {
+			check.Status = StatusUnknown
+			check.Message = err.Error()
+
+			return check
+		}
+
+		certificates = append(certificates, certs...)
+	}
+
+	if len(certificates) == 0 {
+		check.Status = StatusHealthy
+		check.Message = "no certificates in kubeconfig"
+
+		return check
+	}
+
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].NotAfter.Before(certificates[j].NotAfter)
+	})
+
+	first := certificates[0]
+
+	switch {
+	case !now.Before(first.NotAfter):
+		check.Status = StatusUnhealthy
+		check.Message = fmt.Sprintf("certificate %q expired at %s", first.Subject.CommonName, first.NotAfter.UTC().Format(time.RFC3339))
+
+	case first.NotAfter.Sub(now) < expiryWarning:
+		check.Status = StatusDegraded
+		check.Message = fmt.Sprintf("certificate %q expires at %s", first.Subject.CommonName, first.NotAfter.UTC().Format(time.RFC3339))
+
+	default:
+		check.Status = StatusHealthy
+		check.Message = fmt.Sprintf("certificates are valid until %s", first.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	return check
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certificates, nil
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to parse certificate")
+		}
+
+		certificates = append(certificates, certificate)
+	}
+}
+
+func joinNames(names []string) string {
+	if len(names) > maxReportedNames {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:maxReportedNames], ", "), len(names)-maxReportedNames)
+	}
+
+	return strings.Join(names, ", ")
+}
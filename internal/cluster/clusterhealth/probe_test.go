@@ -0,0 +1,194 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterhealth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func newNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready},
+			},
+		},
+	}
+}
+
+func newPod(namespace string, name string, phase corev1.PodPhase, created time.Time, waitingReason string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+
+	if waitingReason != "" {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason}}},
+		}
+	}
+
+	return pod
+}
+
+func newCertificate(t *testing.T, commonName string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestProbeCluster(t *testing.T) {
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	config := ProberConfig{
+		PipelineNamespace:        "pipeline-system",
+		CertificateExpiryWarning: 30 * 24 * time.Hour,
+	}
+
+	t.Run("Healthy", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			newNode("node1", corev1.ConditionTrue),
+			newNode("node2", corev1.ConditionTrue),
+			newPod("kube-system", "coredns", corev1.PodRunning, now.Add(-time.Hour), ""),
+			newPod("pipeline-system", "job", corev1.PodSucceeded, now.Add(-time.Hour), ""),
+		)
+
+		restConfig := &rest.Config{
+			TLSClientConfig: rest.TLSClientConfig{
+				CAData: newCertificate(t, "ca", now.Add(365*24*time.Hour)),
+			},
+		}
+
+		checks := probeCluster(client, restConfig, config, now)
+
+		for _, check := range checks {
+			assert.Equal(t, StatusHealthy, check.Status, check.Name)
+		}
+
+		assert.Equal(t, StatusHealthy, overallStatus(checks))
+	})
+
+	t.Run("Degraded", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			newNode("node1", corev1.ConditionTrue),
+			newNode("node2", corev1.ConditionFalse),
+			newPod("kube-system", "coredns", corev1.PodRunning, now.Add(-time.Hour), "CrashLoopBackOff"),
+			newPod("kube-system", "proxy", corev1.PodPending, now.Add(-time.Minute), ""),
+			newPod("pipeline-system", "webhook", corev1.PodPending, now.Add(-time.Hour), ""),
+		)
+
+		restConfig := &rest.Config{
+			TLSClientConfig: rest.TLSClientConfig{
+				CertData: newCertificate(t, "admin", now.Add(24*time.Hour)),
+			},
+		}
+
+		checks := probeCluster(client, restConfig, config, now)
+
+		expected := []Check{
+			{Name: CheckAPIServer, Status: StatusHealthy, Message: checks[0].Message},
+			{Name: CheckNodes, Status: StatusDegraded, Message: "1 of 2 nodes are not ready: node2"},
+			{Name: CheckSystemPods, Status: StatusDegraded, Message: "1 pods are unhealthy in namespace kube-system: coredns (CrashLoopBackOff)"},
+			{Name: CheckPipelinePods, Status: StatusDegraded, Message: "1 pods are unhealthy in namespace pipeline-system: webhook (Pending)"},
+			{Name: CheckCertificates, Status: StatusDegraded, Message: `certificate "admin" expires at 2020-05-02T12:00:00Z`},
+		}
+
+		assert.Equal(t, expected, checks)
+		assert.Equal(t, StatusDegraded, overallStatus(checks))
+	})
+
+	t.Run("Unhealthy", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			newNode("node1", corev1.ConditionUnknown),
+		)
+
+		restConfig := &rest.Config{
+			TLSClientConfig: rest.TLSClientConfig{
+				CAData:   newCertificate(t, "ca", now.Add(365*24*time.Hour)),
+				CertData: newCertificate(t, "admin", now.Add(-time.Hour)),
+			},
+		}
+
+		checks := probeCluster(client, restConfig, config, now)
+
+		assert.Equal(t, Check{Name: CheckNodes, Status: StatusUnhealthy, Message: "none of the 1 nodes are ready"}, checks[1])
+		assert.Equal(t, Check{Name: CheckCertificates, Status: StatusUnhealthy, Message: `certificate "admin" expired at 2020-05-01T11:00:00Z`}, checks[4])
+		assert.Equal(t, StatusUnhealthy, overallStatus(checks))
+	})
+}
+
+func TestPodProblem(t *testing.T) {
+	now := time.Now()
+
+	jobPod := newPod("default", "job", corev1.PodFailed, now, "")
+	jobPod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "job"}}
+
+	tests := map[string]struct {
+		pod      *corev1.Pod
+		expected string
+	}{
+		"Running":        {newPod("default", "pod", corev1.PodRunning, now, ""), ""},
+		"Failed":         {newPod("default", "pod", corev1.PodFailed, now, ""), "Failed"},
+		"FailedJob":      {jobPod, ""},
+		"RecentPending":  {newPod("default", "pod", corev1.PodPending, now.Add(-time.Minute), ""), ""},
+		"StuckPending":   {newPod("default", "pod", corev1.PodPending, now.Add(-time.Hour), ""), "Pending"},
+		"ImagePullError": {newPod("default", "pod", corev1.PodPending, now, "ImagePullBackOff"), "ImagePullBackOff"},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, podProblem(*test.pod, now))
+		})
+	}
+}
+
+func TestJoinNames(t *testing.T) {
+	assert.Equal(t, "a, b", joinNames([]string{"a", "b"}))
+	assert.Equal(t, "a, b, c, d, e and 2 more", joinNames([]string{"a", "b", "c", "d", "e", "f", "g"}))
+}
@@ -0,0 +1,282 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusternamespaceadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const templateTableName = "namespace_templates"
+
+type templateModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_namespace_templates_organization_id_name"`
+	Name           string `gorm:"unique_index:idx_namespace_templates_organization_id_name"`
+	Description    string `gorm:"type:text"`
+	Spec           string `gorm:"type:text"`
+}
+
+func (templateModel) TableName() string {
+	return templateTableName
+}
+
+// specDocument is the stored representation of a namespace spec.
+type specDocument struct {
+	Labels        map[string]string     `json:"labels,omitempty"`
+	Annotations   map[string]string     `json:"annotations,omitempty"`
+	ResourceQuota map[string]string     `json:"resourceQuota,omitempty"`
+	LimitRange    limitRangeDocument    `json:"limitRange,omitempty"`
+	RoleBindings  []roleBindingDocument `json:"roleBindings,omitempty"`
+	Vault         vaultDocument         `json:"vault,omitempty"`
+	NetworkPolicy networkPolicyDocument `json:"networkPolicy,omitempty"`
+}
+
+type limitRangeDocument struct {
+	Default        map[string]string `json:"default,omitempty"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+}
+
+type roleBindingDocument struct {
+	OrganizationRole string `json:"organizationRole"`
+	NamespaceRole    string `json:"namespaceRole"`
+}
+
+type vaultDocument struct {
+	DisableSecretInjection bool `json:"disableSecretInjection,omitempty"`
+}
+
+type networkPolicyDocument struct {
+	Isolation string `json:"isolation,omitempty"`
+}
+
+func specToDocument(spec clusternamespace.Spec) specDocument {
+	document := specDocument{
+		Labels:        spec.Labels,
+		Annotations:   spec.Annotations,
+		ResourceQuota: spec.ResourceQuota,
+		LimitRange:    limitRangeDocument(spec.LimitRange),
+		Vault:         vaultDocument(spec.Vault),
+		NetworkPolicy: networkPolicyDocument(spec.NetworkPolicy),
+	}
+
+	for _, binding := range spec.RoleBindings {
+		document.RoleBindings = append(document.RoleBindings, roleBindingDocument(binding))
+	}
+
+	return document
+}
+
+func specFromDocument(document specDocument) clusternamespace.Spec {
+	spec := clusternamespace.Spec{
+		Labels:        document.Labels,
+		Annotations:   document.Annotations,
+		ResourceQuota: document.ResourceQuota,
+		LimitRange:    clusternamespace.LimitRange(document.LimitRange),
+		Vault:         clusternamespace.VaultSettings(document.Vault),
+		NetworkPolicy: clusternamespace.NetworkPolicySettings(document.NetworkPolicy),
+	}
+
+	for _, binding := range document.RoleBindings {
+		spec.RoleBindings = append(spec.RoleBindings, clusternamespace.RoleBinding(binding))
+	}
+
+	return spec
+}
+
+// Migrate executes the table migrations for the namespace templates.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	tables := []interface{}{
+		&templateModel{},
+	}
+
+	var tableNames string
+	for _, table := range tables {
+		tableNames += fmt.Sprintf(" %s", db.NewScope(table).TableName())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.TrimSpace(tableNames),
+	}).Info("migrating namespace template tables")
+
+	return db.AutoMigrate(tables...).Error
+}
+
+// GormTemplateStore implements the clusternamespace.TemplateStore interface using gorm.
+type GormTemplateStore struct {
+	db *gorm.DB
+}
+
+// NewGormTemplateStore returns a new GormTemplateStore.
+func NewGormTemplateStore(db *gorm.DB) GormTemplateStore {
+	return GormTemplateStore{
+		db: db,
+	}
+}
+
+// List implements the clusternamespace.TemplateStore interface.
+func (s GormTemplateStore) List(ctx context.Context, organizationID uint) ([]clusternamespace.Template, error) {
+	var models []templateModel
+
+	err := s.db.Where(templateModel{OrganizationID: organizationID}).Order("name").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list namespace templates", "orgId", organizationID)
+	}
+
+	templates := make([]clusternamespace.Template, 0, len(models))
+	for _, model := range models {
+		template, err := templateFromModel(model)
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// Get implements the clusternamespace.TemplateStore interface.
+func (s GormTemplateStore) Get(ctx context.Context, organizationID uint, name string) (clusternamespace.Template, error) {
+	var model templateModel
+
+	err := s.db.Where(templateModel{OrganizationID: organizationID, Name: name}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return clusternamespace.Template{}, errors.WithStack(clusternamespace.TemplateNotFoundError{
+			OrganizationID: organizationID,
+			Name:           name,
+		})
+	} else if err != nil {
+		return clusternamespace.Template{}, errors.WrapIfWithDetails(err, "failed to get namespace template", "orgId", organizationID, "template", name)
+	}
+
+	return templateFromModel(model)
+}
+
+// Save implements the clusternamespace.TemplateStore interface.
+func (s GormTemplateStore) Save(ctx context.Context, organizationID uint, template clusternamespace.Template) error {
+	document, err := json.Marshal(specToDocument(template.Spec))
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to marshal namespace template", "orgId", organizationID, "template", template.Name)
+	}
+
+	var model templateModel
+
+	err = s.db.
+		Where(templateModel{OrganizationID: organizationID, Name: template.Name}).
+		Assign(map[string]interface{}{
+			"description": template.Description,
+			"spec":        string(document),
+		}).
+		FirstOrCreate(&model).
+		Error
+
+	return errors.WrapIfWithDetails(err, "failed to save namespace template", "orgId", organizationID, "template", template.Name)
+}
+
+// Delete implements the clusternamespace.TemplateStore interface.
+func (s GormTemplateStore) Delete(ctx context.Context, organizationID uint, name string) error {
+	err := s.db.Where(templateModel{OrganizationID: organizationID, Name: name}).Delete(&templateModel{}).Error
+
+	return errors.WrapIfWithDetails(err, "failed to delete namespace template", "orgId", organizationID, "template", name)
+}
+
+func templateFromModel(model templateModel) (clusternamespace.Template, error) {
+	var document specDocument
+	if err := json.Unmarshal([]byte(model.Spec), &document); err != nil {
+		return clusternamespace.Template{}, errors.WrapIfWithDetails(
+			err, "failed to unmarshal namespace template",
+			"orgId", model.OrganizationID,
+			"template", model.Name,
+		)
+	}
+
+	return clusternamespace.Template{
+		Name:        model.Name,
+		Description: model.Description,
+		Spec:        specFromDocument(document),
+	}, nil
+}
+
+// MemberLister lists organization members from the user database.
+type MemberLister struct {
+	db *gorm.DB
+}
+
+// NewMemberLister returns a new MemberLister.
+func NewMemberLister(db *gorm.DB) MemberLister {
+	return MemberLister{
+		db: db,
+	}
+}
+
+// ListMembers implements the clusternamespace.MemberLister interface.
+func (l MemberLister) ListMembers(ctx context.Context, organizationID uint) ([]clusternamespace.Member, error) {
+	var members []clusternamespace.Member
+
+	err := l.db.
+		Table("users").
+		Select("users.login, users.email, user_organizations.role").
+		Joins("JOIN user_organizations ON user_organizations.user_id = users.id").
+		Where("user_organizations.organization_id = ?", organizationID).
+		Order("users.login").
+		Scan(&members).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list organization members", "orgId", organizationID)
+	}
+
+	return members, nil
+}
+
+// ClusterFinder finds the clusters of an organization in the cluster database.
+type ClusterFinder struct {
+	db *gorm.DB
+}
+
+// NewClusterFinder returns a new ClusterFinder.
+func NewClusterFinder(db *gorm.DB) ClusterFinder {
+	return ClusterFinder{
+		db: db,
+	}
+}
+
+// FindClusterIDs implements the clusternamespaceworkflow.ClusterFinder interface.
+func (f ClusterFinder) FindClusterIDs(ctx context.Context, organizationID uint) ([]uint, error) {
+	var clusterIDs []uint
+
+	err := f.db.
+		Table("clusters").
+		Where("organization_id = ? AND deleted_at IS NULL AND status IN (?)", organizationID, []string{pkgCluster.Running, pkgCluster.Warning}).
+		Order("id").
+		Pluck("id", &clusterIDs).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to query clusters", "orgId", organizationID)
+	}
+
+	return clusterIDs, nil
+}
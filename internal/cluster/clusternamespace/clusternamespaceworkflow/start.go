@@ -0,0 +1,54 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusternamespaceworkflow
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/client"
+)
+
+const syncRoleBindingsWorkflowTimeout = time.Hour
+
+// RoleBindingSyncStarter starts role binding synchronization workflows.
+type RoleBindingSyncStarter struct {
+	client client.Client
+}
+
+// NewRoleBindingSyncStarter returns a new RoleBindingSyncStarter.
+func NewRoleBindingSyncStarter(client client.Client) RoleBindingSyncStarter {
+	return RoleBindingSyncStarter{
+		client: client,
+	}
+}
+
+// StartRoleBindingSync starts a workflow updating the namespace role bindings of the clusters of an organization.
+//
+// Every change starts a new workflow, so that a sync already in progress cannot miss the latest members.
+func (s RoleBindingSyncStarter) StartRoleBindingSync(ctx context.Context, organizationID uint) error {
+	options := client.StartWorkflowOptions{
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: syncRoleBindingsWorkflowTimeout,
+	}
+
+	input := SyncRoleBindingsWorkflowInput{
+		OrganizationID: organizationID,
+	}
+
+	_, err := s.client.StartWorkflow(ctx, options, SyncRoleBindingsWorkflowName, input)
+
+	return errors.WrapIfWithDetails(err, "failed to start the role binding sync workflow", "orgId", organizationID)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusternamespaceworkflow
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+const ListClustersActivityName = "cluster-namespace-sync-role-bindings-list-clusters"
+
+type ListClustersActivityInput struct {
+	OrganizationID uint
+}
+
+const SyncClusterActivityName = "cluster-namespace-sync-role-bindings-cluster"
+
+type SyncClusterActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+}
+
+// ClusterFinder finds the running clusters of an organization.
+type ClusterFinder interface {
+	// FindClusterIDs returns the IDs of the running clusters of an organization.
+	FindClusterIDs(ctx context.Context, organizationID uint) ([]uint, error)
+}
+
+// ConfigGetter returns the stored kubeconfig of a cluster.
+type ConfigGetter interface {
+	// GetKubeConfig gets a kube config for a specific cluster.
+	GetKubeConfig(ctx context.Context, clusterID uint) (*rest.Config, error)
+}
+
+// RoleBindingSyncer updates the namespace role bindings of a cluster to match the organization members.
+type RoleBindingSyncer interface {
+	// SyncRoleBindings updates the role bindings managed by Pipeline in a cluster.
+	SyncRoleBindings(ctx context.Context, client kubernetes.Interface, organizationID uint) error
+}
+
+type SyncRoleBindingsActivities struct {
+	clusterFinder ClusterFinder
+	configs       ConfigGetter
+	syncer        RoleBindingSyncer
+}
+
+func NewSyncRoleBindingsActivities(clusterFinder ClusterFinder, configs ConfigGetter, syncer RoleBindingSyncer) SyncRoleBindingsActivities {
+	return SyncRoleBindingsActivities{
+		clusterFinder: clusterFinder,
+		configs:       configs,
+		syncer:        syncer,
+	}
+}
+
+func (a SyncRoleBindingsActivities) ListClusters(ctx context.Context, input ListClustersActivityInput) ([]uint, error) {
+	return a.clusterFinder.FindClusterIDs(ctx, input.OrganizationID)
+}
+
+func (a SyncRoleBindingsActivities) SyncCluster(ctx context.Context, input SyncClusterActivityInput) error {
+	// kubeconfigs are stored as organization secrets
+	ctx = auth.SetCurrentOrganizationID(ctx, input.OrganizationID)
+
+	config, err := a.configs.GetKubeConfig(ctx, input.ClusterID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get kubeconfig", "clusterId", input.ClusterID)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to create kubernetes client", "clusterId", input.ClusterID)
+	}
+
+	return a.syncer.SyncRoleBindings(ctx, client, input.OrganizationID)
+}
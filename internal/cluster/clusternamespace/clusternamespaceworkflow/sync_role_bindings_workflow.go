@@ -0,0 +1,81 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusternamespaceworkflow
+
+import (
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+)
+
+const SyncRoleBindingsWorkflowName = "cluster-namespace-sync-role-bindings"
+
+type SyncRoleBindingsWorkflowInput struct {
+	OrganizationID uint
+}
+
+// SyncRoleBindingsWorkflow updates the namespace role bindings on every running cluster of an organization
+// to match the current organization members.
+func SyncRoleBindingsWorkflow(ctx workflow.Context, input SyncRoleBindingsWorkflowInput) error {
+	logger := workflow.GetLogger(ctx).Sugar()
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    5 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy: &cadence.RetryPolicy{
+			InitialInterval:    15 * time.Second,
+			BackoffCoefficient: 1.5,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	var clusterIDs []uint
+	{
+		activityInput := ListClustersActivityInput{
+			OrganizationID: input.OrganizationID,
+		}
+
+		if err := workflow.ExecuteActivity(ctx, ListClustersActivityName, activityInput).Get(ctx, &clusterIDs); err != nil {
+			return err
+		}
+	}
+
+	futures := make([]workflow.Future, 0, len(clusterIDs))
+	for _, clusterID := range clusterIDs {
+		activityInput := SyncClusterActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      clusterID,
+		}
+
+		futures = append(futures, workflow.ExecuteActivity(ctx, SyncClusterActivityName, activityInput))
+	}
+
+	var failed bool
+	for i, future := range futures {
+		if err := future.Get(ctx, nil); err != nil {
+			// a failing cluster should not prevent syncing the others
+			logger.Errorw("failed to sync role bindings", "clusterId", clusterIDs[i], "error", err.Error())
+			failed = true
+		}
+	}
+
+	if failed {
+		return cadence.NewCustomError("SyncRoleBindingsFailed", "failed to sync role bindings on some clusters")
+	}
+
+	return nil
+}
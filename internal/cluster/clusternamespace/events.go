@@ -0,0 +1,62 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusternamespace
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// RoleBindingSyncStarter starts the synchronization of the namespace role bindings of an organization.
+type RoleBindingSyncStarter interface {
+	// StartRoleBindingSync starts updating the role bindings on the clusters of an organization.
+	StartRoleBindingSync(ctx context.Context, organizationID uint) error
+}
+
+// MembershipChangedHandler starts the synchronization of the namespace role bindings
+// when the members of an organization change.
+type MembershipChangedHandler struct {
+	starter RoleBindingSyncStarter
+}
+
+// NewMembershipChangedHandler returns a new MembershipChangedHandler.
+func NewMembershipChangedHandler(starter RoleBindingSyncStarter) MembershipChangedHandler {
+	return MembershipChangedHandler{
+		starter: starter,
+	}
+}
+
+// HandlerName returns the name of the event handler.
+func (MembershipChangedHandler) HandlerName() string {
+	return "cluster_namespace_sync_role_bindings"
+}
+
+// NewEvent returns a new empty event used for serialization.
+func (MembershipChangedHandler) NewEvent() interface{} {
+	return &auth.OrganizationMembershipChanged{}
+}
+
+// Handle handles an OrganizationMembershipChanged event.
+func (h MembershipChangedHandler) Handle(ctx context.Context, event interface{}) error {
+	e, ok := event.(*auth.OrganizationMembershipChanged)
+	if !ok {
+		return errors.NewWithDetails("unexpected event type", "type", fmt.Sprintf("%T", event))
+	}
+
+	return h.starter.StartRoleBindingSync(ctx, e.OrganizationID)
+}
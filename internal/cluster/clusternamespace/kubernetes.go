@@ -0,0 +1,407 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusternamespace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services/vault"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+const (
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "pipeline"
+
+	// Namespace annotations recording the label and annotation keys set by Pipeline,
+	// so that keys removed from the spec can be removed from the namespace as well.
+	managedLabelsAnnotation      = "pipeline.banzaicloud.io/managed-labels"
+	managedAnnotationsAnnotation = "pipeline.banzaicloud.io/managed-annotations"
+
+	// Role binding label recording the organization role whose members are the subjects of the binding.
+	organizationRoleLabel = "pipeline.banzaicloud.io/organization-role"
+
+	resourceQuotaName     = "pipeline-quota"
+	limitRangeName        = "pipeline-limits"
+	networkPolicyName     = "pipeline-isolation"
+	roleBindingNamePrefix = "pipeline-"
+)
+
+func createNamespace(client kubernetes.Interface, name string, spec Spec, members []Member) error {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	setNamespaceMetadata(namespace, spec)
+
+	_, err := client.CoreV1().Namespaces().Create(namespace)
+	if k8serrors.IsAlreadyExists(err) {
+		return errors.WithStack(AlreadyExistsError{Name: name})
+	} else if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to create namespace", "namespace", name)
+	}
+
+	return applyNamespaceResources(client, name, spec, members)
+}
+
+func updateNamespace(client kubernetes.Interface, name string, spec Spec, members []Member) error {
+	namespace, err := client.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return errors.WithStack(NotFoundError{Name: name})
+	} else if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get namespace", "namespace", name)
+	}
+
+	setNamespaceMetadata(namespace, spec)
+
+	_, err = client.CoreV1().Namespaces().Update(namespace)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to update namespace", "namespace", name)
+	}
+
+	return applyNamespaceResources(client, name, spec, members)
+}
+
+// setNamespaceMetadata replaces the labels and annotations previously set by Pipeline with the ones in the spec.
+func setNamespaceMetadata(namespace *corev1.Namespace, spec Spec) {
+	labels := make(map[string]string, len(spec.Labels)+1)
+	for k, v := range spec.Labels {
+		labels[k] = v
+	}
+
+	if spec.Vault.DisableSecretInjection {
+		labels[vault.SecretInjectionNamespaceLabel] = vault.SecretInjectionDisabled
+	}
+
+	namespace.Labels = replaceManaged(namespace.Labels, namespace.Annotations[managedLabelsAnnotation], labels)
+	namespace.Annotations = replaceManaged(namespace.Annotations, namespace.Annotations[managedAnnotationsAnnotation], spec.Annotations)
+
+	namespace.Annotations[managedLabelsAnnotation] = joinKeys(labels)
+	namespace.Annotations[managedAnnotationsAnnotation] = joinKeys(spec.Annotations)
+}
+
+func replaceManaged(current map[string]string, managedKeys string, desired map[string]string) map[string]string {
+	result := make(map[string]string, len(current)+len(desired))
+	for k, v := range current {
+		result[k] = v
+	}
+
+	if managedKeys != "" {
+		for _, k := range strings.Split(managedKeys, ",") {
+			delete(result, k)
+		}
+	}
+
+	for k, v := range desired {
+		result[k] = v
+	}
+
+	return result
+}
+
+func joinKeys(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
+
+func applyNamespaceResources(client kubernetes.Interface, namespace string, spec Spec, members []Member) error {
+	if err := applyResourceQuota(client, namespace, spec.ResourceQuota); err != nil {
+		return err
+	}
+
+	if err := applyLimitRange(client, namespace, spec.LimitRange); err != nil {
+		return err
+	}
+
+	if err := applyNetworkPolicy(client, namespace, spec.NetworkPolicy); err != nil {
+		return err
+	}
+
+	return applyRoleBindings(client, namespace, spec.RoleBindings, members)
+}
+
+func managedObjectMeta(namespace string, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels: map[string]string{
+			managedByLabel: managedByValue,
+		},
+	}
+}
+
+func resourceList(quantities map[string]string) corev1.ResourceList {
+	if len(quantities) == 0 {
+		return nil
+	}
+
+	list := make(corev1.ResourceList, len(quantities))
+	for name, value := range quantities {
+		// quantities are validated as part of the spec
+		list[corev1.ResourceName(name)] = resource.MustParse(value)
+	}
+
+	return list
+}
+
+func applyResourceQuota(client kubernetes.Interface, namespace string, hard map[string]string) error {
+	quotas := client.CoreV1().ResourceQuotas(namespace)
+
+	if len(hard) == 0 {
+		err := quotas.Delete(resourceQuotaName, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WrapIfWithDetails(err, "failed to delete resource quota", "namespace", namespace)
+		}
+
+		return nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: managedObjectMeta(namespace, resourceQuotaName),
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: resourceList(hard),
+		},
+	}
+
+	current, err := quotas.Get(resourceQuotaName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = quotas.Create(quota)
+	} else if err == nil {
+		quota.ResourceVersion = current.ResourceVersion
+		_, err = quotas.Update(quota)
+	}
+
+	return errors.WrapIfWithDetails(err, "failed to apply resource quota", "namespace", namespace)
+}
+
+func applyLimitRange(client kubernetes.Interface, namespace string, limits LimitRange) error {
+	limitRanges := client.CoreV1().LimitRanges(namespace)
+
+	if limits.IsEmpty() {
+		err := limitRanges.Delete(limitRangeName, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WrapIfWithDetails(err, "failed to delete limit range", "namespace", namespace)
+		}
+
+		return nil
+	}
+
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: managedObjectMeta(namespace, limitRangeName),
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type:           corev1.LimitTypeContainer,
+					Default:        resourceList(limits.Default),
+					DefaultRequest: resourceList(limits.DefaultRequest),
+					Max:            resourceList(limits.Max),
+					Min:            resourceList(limits.Min),
+				},
+			},
+		},
+	}
+
+	current, err := limitRanges.Get(limitRangeName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = limitRanges.Create(limitRange)
+	} else if err == nil {
+		limitRange.ResourceVersion = current.ResourceVersion
+		_, err = limitRanges.Update(limitRange)
+	}
+
+	return errors.WrapIfWithDetails(err, "failed to apply limit range", "namespace", namespace)
+}
+
+func applyNetworkPolicy(client kubernetes.Interface, namespace string, settings NetworkPolicySettings) error {
+	policies := client.NetworkingV1().NetworkPolicies(namespace)
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: managedObjectMeta(namespace, networkPolicyName),
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+
+	switch settings.Isolation {
+	case IsolationDenyIngress:
+		// an ingress policy without rules denies every incoming connection
+
+	case IsolationNamespace:
+		policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+			{
+				From: []networkingv1.NetworkPolicyPeer{
+					{PodSelector: &metav1.LabelSelector{}},
+				},
+			},
+		}
+
+	default:
+		err := policies.Delete(networkPolicyName, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WrapIfWithDetails(err, "failed to delete network policy", "namespace", namespace)
+		}
+
+		return nil
+	}
+
+	current, err := policies.Get(networkPolicyName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = policies.Create(policy)
+	} else if err == nil {
+		policy.ResourceVersion = current.ResourceVersion
+		_, err = policies.Update(policy)
+	}
+
+	return errors.WrapIfWithDetails(err, "failed to apply network policy", "namespace", namespace)
+}
+
+// roleBindingName returns the name of the role binding of an organization role and namespace role pair.
+func roleBindingName(binding RoleBinding) string {
+	return roleBindingNamePrefix + binding.OrganizationRole + "-" + strings.ReplaceAll(binding.NamespaceRole, ":", "-")
+}
+
+// roleBindingSubjects returns the members having an organization role.
+// Every organization member has the member role, admins have the admin role as well.
+func roleBindingSubjects(organizationRole string, members []Member) []rbacv1.Subject {
+	subjects := []rbacv1.Subject{}
+
+	for _, member := range members {
+		if organizationRole == auth.RoleAdmin && member.Role != auth.RoleAdmin {
+			continue
+		}
+
+		name := member.Email
+		if name == "" {
+			name = member.Login
+		}
+
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.UserKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     name,
+		})
+	}
+
+	return subjects
+}
+
+func applyRoleBindings(client kubernetes.Interface, namespace string, bindings []RoleBinding, members []Member) error {
+	roleBindings := client.RbacV1().RoleBindings(namespace)
+
+	desired := make(map[string]bool, len(bindings))
+
+	for _, binding := range bindings {
+		name := roleBindingName(binding)
+		desired[name] = true
+
+		roleBinding := &rbacv1.RoleBinding{
+			ObjectMeta: managedObjectMeta(namespace, name),
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     binding.NamespaceRole,
+			},
+			Subjects: roleBindingSubjects(binding.OrganizationRole, members),
+		}
+		roleBinding.Labels[organizationRoleLabel] = binding.OrganizationRole
+
+		current, err := roleBindings.Get(name, metav1.GetOptions{})
+		switch {
+		case k8serrors.IsNotFound(err):
+			_, err = roleBindings.Create(roleBinding)
+
+		case err == nil && current.RoleRef != roleBinding.RoleRef:
+			// the role reference of a role binding is immutable
+			err = roleBindings.Delete(name, &metav1.DeleteOptions{})
+			if err == nil {
+				_, err = roleBindings.Create(roleBinding)
+			}
+
+		case err == nil:
+			roleBinding.ResourceVersion = current.ResourceVersion
+			_, err = roleBindings.Update(roleBinding)
+		}
+
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to apply role binding", "namespace", namespace, "roleBinding", name)
+		}
+	}
+
+	current, err := roleBindings.List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", managedByLabel, managedByValue),
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list role bindings", "namespace", namespace)
+	}
+
+	for _, roleBinding := range current.Items {
+		if desired[roleBinding.Name] || !strings.HasPrefix(roleBinding.Name, roleBindingNamePrefix) {
+			continue
+		}
+
+		err := roleBindings.Delete(roleBinding.Name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WrapIfWithDetails(err, "failed to delete role binding", "namespace", namespace, "roleBinding", roleBinding.Name)
+		}
+	}
+
+	return nil
+}
+
+// syncRoleBindingSubjects updates the subjects of every role binding managed by Pipeline in the cluster
+// to match the current organization members.
+func syncRoleBindingSubjects(client kubernetes.Interface, members []Member) error {
+	current, err := client.RbacV1().RoleBindings(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s", managedByLabel, managedByValue, organizationRoleLabel),
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed to list role bindings")
+	}
+
+	for _, roleBinding := range current.Items {
+		subjects := roleBindingSubjects(roleBinding.Labels[organizationRoleLabel], members)
+		if equality.Semantic.DeepEqual(roleBinding.Subjects, subjects) {
+			continue
+		}
+
+		roleBinding.Subjects = subjects
+
+		_, err := client.RbacV1().RoleBindings(roleBinding.Namespace).Update(&roleBinding)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WrapIfWithDetails(err, "failed to update role binding", "namespace", roleBinding.Namespace, "roleBinding", roleBinding.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusternamespace
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// Member is a member of an organization.
+type Member struct {
+	Login string
+	Email string
+
+	// Role is the role of the member in the organization.
+	Role string
+}
+
+// MemberLister lists the members of an organization.
+type MemberLister interface {
+	// ListMembers returns the members of an organization.
+	ListMembers(ctx context.Context, organizationID uint) ([]Member, error)
+}
+
+// Request describes the desired state of a namespace.
+type Request struct {
+	Name string
+
+	// Template is the name of an organization namespace template the spec is based on.
+	Template string
+
+	Spec Spec
+}
+
+// AlreadyExistsError is returned when a namespace to be created already exists.
+type AlreadyExistsError struct {
+	Name string
+}
+
+// Error implements the error interface.
+func (AlreadyExistsError) Error() string {
+	return "namespace already exists"
+}
+
+// Details returns error details.
+func (e AlreadyExistsError) Details() []interface{} {
+	return []interface{}{"namespace", e.Name}
+}
+
+// Conflict tells a client that this error is related to a conflicting request.
+// Can be used to translate the error to status codes for example.
+func (AlreadyExistsError) Conflict() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (AlreadyExistsError) ServiceError() bool {
+	return true
+}
+
+// NotFoundError is returned when a namespace to be updated does not exist.
+type NotFoundError struct {
+	Name string
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "namespace not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"namespace", e.Name}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to status codes for example.
+func (NotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (NotFoundError) ServiceError() bool {
+	return true
+}
+
+// Service manages namespace templates and the namespaces of clusters.
+type Service struct {
+	templates TemplateStore
+	members   MemberLister
+}
+
+// NewService returns a new Service.
+func NewService(templates TemplateStore, members MemberLister) Service {
+	return Service{
+		templates: templates,
+		members:   members,
+	}
+}
+
+// ListTemplates returns the namespace templates of an organization.
+func (s Service) ListTemplates(ctx context.Context, organizationID uint) ([]Template, error) {
+	return s.templates.List(ctx, organizationID)
+}
+
+// GetTemplate returns a namespace template of an organization.
+func (s Service) GetTemplate(ctx context.Context, organizationID uint, name string) (Template, error) {
+	return s.templates.Get(ctx, organizationID, name)
+}
+
+// SaveTemplate validates and saves a namespace template of an organization.
+func (s Service) SaveTemplate(ctx context.Context, organizationID uint, template Template) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	return s.templates.Save(ctx, organizationID, template)
+}
+
+// DeleteTemplate removes a namespace template of an organization.
+func (s Service) DeleteTemplate(ctx context.Context, organizationID uint, name string) error {
+	return s.templates.Delete(ctx, organizationID, name)
+}
+
+// CreateNamespace creates a namespace along with the resources described by the request.
+func (s Service) CreateNamespace(ctx context.Context, client kubernetes.Interface, organizationID uint, request Request) error {
+	spec, err := s.resolveSpec(ctx, organizationID, request)
+	if err != nil {
+		return err
+	}
+
+	members, err := s.members.ListMembers(ctx, organizationID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list organization members", "orgId", organizationID)
+	}
+
+	return createNamespace(client, request.Name, spec, members)
+}
+
+// UpdateNamespace updates an existing namespace and its resources to match the request.
+//
+// Role bindings are recalculated from the current organization members.
+func (s Service) UpdateNamespace(ctx context.Context, client kubernetes.Interface, organizationID uint, request Request) error {
+	spec, err := s.resolveSpec(ctx, organizationID, request)
+	if err != nil {
+		return err
+	}
+
+	members, err := s.members.ListMembers(ctx, organizationID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list organization members", "orgId", organizationID)
+	}
+
+	return updateNamespace(client, request.Name, spec, members)
+}
+
+// SyncRoleBindings updates the subjects of the role bindings managed by Pipeline in a cluster
+// to match the current organization members, so that removed members lose their access.
+func (s Service) SyncRoleBindings(ctx context.Context, client kubernetes.Interface, organizationID uint) error {
+	members, err := s.members.ListMembers(ctx, organizationID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list organization members", "orgId", organizationID)
+	}
+
+	return syncRoleBindingSubjects(client, members)
+}
+
+// resolveSpec merges the request spec into its template and validates the result.
+func (s Service) resolveSpec(ctx context.Context, organizationID uint, request Request) (Spec, error) {
+	var violations []string
+
+	for _, msg := range validation.IsDNS1123Label(request.Name) {
+		violations = append(violations, fmt.Sprintf("invalid namespace name %q: %s", request.Name, msg))
+	}
+
+	spec := request.Spec
+
+	if request.Template != "" {
+		template, err := s.templates.Get(ctx, organizationID, request.Template)
+		if err != nil {
+			var notFoundErr TemplateNotFoundError
+			if !errors.As(err, &notFoundErr) {
+				return Spec{}, errors.WrapIfWithDetails(err, "failed to get namespace template", "template", request.Template)
+			}
+
+			violations = append(violations, fmt.Sprintf("namespace template %q does not exist", request.Template))
+		} else {
+			spec = MergeSpecs(template.Spec, request.Spec)
+		}
+	}
+
+	if err := spec.Validate(); err != nil {
+		violations = append(violations, unwrapViolations(err)...)
+	}
+
+	if len(violations) > 0 {
+		return Spec{}, errors.WithStack(cluster.NewValidationError("invalid namespace request", violations))
+	}
+
+	return spec, nil
+}
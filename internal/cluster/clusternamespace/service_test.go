@@ -0,0 +1,319 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusternamespace
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services/vault"
+)
+
+type fakeTemplateStore map[string]Template
+
+func (s fakeTemplateStore) List(_ context.Context, _ uint) ([]Template, error) {
+	var templates []Template
+	for _, template := range s {
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+func (s fakeTemplateStore) Get(_ context.Context, organizationID uint, name string) (Template, error) {
+	template, ok := s[name]
+	if !ok {
+		return Template{}, errors.WithStack(TemplateNotFoundError{OrganizationID: organizationID, Name: name})
+	}
+
+	return template, nil
+}
+
+func (s fakeTemplateStore) Save(_ context.Context, _ uint, template Template) error {
+	s[template.Name] = template
+
+	return nil
+}
+
+func (s fakeTemplateStore) Delete(_ context.Context, _ uint, name string) error {
+	delete(s, name)
+
+	return nil
+}
+
+type fakeMemberLister []Member
+
+func (l fakeMemberLister) ListMembers(_ context.Context, _ uint) ([]Member, error) {
+	return l, nil
+}
+
+func TestService_CreateNamespace(t *testing.T) {
+	templates := fakeTemplateStore{
+		"team": {
+			Name: "team",
+			Spec: Spec{
+				Labels:        map[string]string{"tier": "team"},
+				ResourceQuota: map[string]string{"requests.cpu": "4", "requests.memory": "8Gi"},
+				LimitRange: LimitRange{
+					Default: map[string]string{"cpu": "500m"},
+				},
+				RoleBindings: []RoleBinding{
+					{OrganizationRole: "admin", NamespaceRole: "admin"},
+				},
+				NetworkPolicy: NetworkPolicySettings{Isolation: IsolationNamespace},
+			},
+		},
+	}
+	members := fakeMemberLister{
+		{Login: "alice", Email: "alice@example.com", Role: "admin"},
+		{Login: "bob", Role: "member"},
+	}
+
+	service := NewService(templates, members)
+	client := fake.NewSimpleClientset()
+
+	err := service.CreateNamespace(context.Background(), client, 1, Request{
+		Name:     "team-a",
+		Template: "team",
+		Spec: Spec{
+			Labels:        map[string]string{"team": "a"},
+			ResourceQuota: map[string]string{"requests.cpu": "2"},
+			RoleBindings: []RoleBinding{
+				{OrganizationRole: "member", NamespaceRole: "edit"},
+			},
+			Vault: VaultSettings{DisableSecretInjection: true},
+		},
+	})
+	require.NoError(t, err)
+
+	namespace, err := client.CoreV1().Namespaces().Get("team-a", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		map[string]string{
+			"tier":                              "team",
+			"team":                              "a",
+			vault.SecretInjectionNamespaceLabel: vault.SecretInjectionDisabled,
+		},
+		namespace.Labels,
+	)
+
+	quota, err := client.CoreV1().ResourceQuotas("team-a").Get(resourceQuotaName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		corev1.ResourceList{
+			corev1.ResourceRequestsCPU:    resource.MustParse("2"),
+			corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+		},
+		quota.Spec.Hard,
+	)
+
+	limitRange, err := client.CoreV1().LimitRanges("team-a").Get(limitRangeName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, resource.MustParse("500m"), limitRange.Spec.Limits[0].Default[corev1.ResourceCPU])
+
+	policy, err := client.NetworkingV1().NetworkPolicies("team-a").Get(networkPolicyName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, policy.Spec.Ingress, 1)
+
+	adminBinding, err := client.RbacV1().RoleBindings("team-a").Get("pipeline-admin-admin", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []rbacv1.Subject{{Kind: "User", APIGroup: rbacv1.GroupName, Name: "alice@example.com"}}, adminBinding.Subjects)
+
+	memberBinding, err := client.RbacV1().RoleBindings("team-a").Get("pipeline-member-edit", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "edit", memberBinding.RoleRef.Name)
+	assert.Len(t, memberBinding.Subjects, 2)
+
+	err = service.CreateNamespace(context.Background(), client, 1, Request{Name: "team-a"})
+	require.Error(t, err)
+	assert.True(t, errors.As(err, &AlreadyExistsError{}))
+}
+
+func TestService_UpdateNamespace(t *testing.T) {
+	service := NewService(fakeTemplateStore{}, fakeMemberLister{{Login: "alice", Role: "admin"}})
+	client := fake.NewSimpleClientset()
+
+	ctx := context.Background()
+
+	err := service.CreateNamespace(ctx, client, 1, Request{
+		Name: "team-a",
+		Spec: Spec{
+			Labels:        map[string]string{"team": "a", "tier": "team"},
+			ResourceQuota: map[string]string{"pods": "10"},
+			RoleBindings: []RoleBinding{
+				{OrganizationRole: "member", NamespaceRole: "edit"},
+			},
+			NetworkPolicy: NetworkPolicySettings{Isolation: IsolationDenyIngress},
+		},
+	})
+	require.NoError(t, err)
+
+	// labels set outside of Pipeline are kept
+	namespace, err := client.CoreV1().Namespaces().Get("team-a", metav1.GetOptions{})
+	require.NoError(t, err)
+	namespace.Labels["istio-injection"] = "enabled"
+	_, err = client.CoreV1().Namespaces().Update(namespace)
+	require.NoError(t, err)
+
+	err = service.UpdateNamespace(ctx, client, 1, Request{
+		Name: "team-a",
+		Spec: Spec{
+			Labels: map[string]string{"team": "b"},
+			RoleBindings: []RoleBinding{
+				{OrganizationRole: "member", NamespaceRole: "view"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	namespace, err = client.CoreV1().Namespaces().Get("team-a", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "b", "istio-injection": "enabled"}, namespace.Labels)
+
+	quotas, err := client.CoreV1().ResourceQuotas("team-a").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, quotas.Items)
+
+	policies, err := client.NetworkingV1().NetworkPolicies("team-a").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, policies.Items)
+
+	bindings, err := client.RbacV1().RoleBindings("team-a").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, bindings.Items, 1)
+	assert.Equal(t, "pipeline-member-view", bindings.Items[0].Name)
+
+	err = service.UpdateNamespace(ctx, client, 1, Request{Name: "team-b"})
+	require.Error(t, err)
+	assert.True(t, errors.As(err, &NotFoundError{}))
+}
+
+func TestService_SyncRoleBindings(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	ctx := context.Background()
+
+	members := fakeMemberLister{
+		{Login: "alice", Email: "alice@example.com", Role: "admin"},
+		{Login: "bob", Email: "bob@example.com", Role: "member"},
+	}
+
+	err := NewService(fakeTemplateStore{}, members).CreateNamespace(ctx, client, 1, Request{
+		Name: "team-a",
+		Spec: Spec{
+			RoleBindings: []RoleBinding{
+				{OrganizationRole: "admin", NamespaceRole: "admin"},
+				{OrganizationRole: "member", NamespaceRole: "edit"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// role bindings created outside of Pipeline are left alone
+	_, err = client.RbacV1().RoleBindings("team-a").Create(&rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "team-a"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "bob@example.com"}},
+	})
+	require.NoError(t, err)
+
+	// bob is removed from the organization
+	err = NewService(fakeTemplateStore{}, members[:1]).SyncRoleBindings(ctx, client, 1)
+	require.NoError(t, err)
+
+	alice := []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "alice@example.com"}}
+
+	adminBinding, err := client.RbacV1().RoleBindings("team-a").Get("pipeline-admin-admin", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, alice, adminBinding.Subjects)
+
+	memberBinding, err := client.RbacV1().RoleBindings("team-a").Get("pipeline-member-edit", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, alice, memberBinding.Subjects)
+
+	customBinding, err := client.RbacV1().RoleBindings("team-a").Get("custom", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, customBinding.Subjects, 1)
+}
+
+func TestService_InvalidRequest(t *testing.T) {
+	service := NewService(fakeTemplateStore{}, fakeMemberLister{})
+	client := fake.NewSimpleClientset()
+
+	err := service.CreateNamespace(context.Background(), client, 1, Request{
+		Name:     "Team_A",
+		Template: "missing",
+		Spec: Spec{
+			ResourceQuota: map[string]string{"requests.cpu": "lots"},
+			RoleBindings: []RoleBinding{
+				{OrganizationRole: "owner", NamespaceRole: "admin"},
+			},
+			NetworkPolicy: NetworkPolicySettings{Isolation: "strict"},
+		},
+	})
+	require.Error(t, err)
+
+	var validationErr interface{ Violations() []string }
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Violations(), 5)
+
+	namespaces, err := client.CoreV1().Namespaces().List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, namespaces.Items)
+}
+
+func TestMergeSpecs(t *testing.T) {
+	base := Spec{
+		Labels:        map[string]string{"a": "1", "b": "2"},
+		RoleBindings:  []RoleBinding{{OrganizationRole: "admin", NamespaceRole: "admin"}},
+		Vault:         VaultSettings{DisableSecretInjection: true},
+		NetworkPolicy: NetworkPolicySettings{Isolation: IsolationNamespace},
+	}
+	override := Spec{
+		Labels: map[string]string{"b": "3"},
+		RoleBindings: []RoleBinding{
+			{OrganizationRole: "admin", NamespaceRole: "admin"},
+			{OrganizationRole: "member", NamespaceRole: "view"},
+		},
+		NetworkPolicy: NetworkPolicySettings{Isolation: IsolationNone},
+	}
+
+	assert.Equal(
+		t,
+		Spec{
+			Labels: map[string]string{"a": "1", "b": "3"},
+			RoleBindings: []RoleBinding{
+				{OrganizationRole: "admin", NamespaceRole: "admin"},
+				{OrganizationRole: "member", NamespaceRole: "view"},
+			},
+			Vault:         VaultSettings{DisableSecretInjection: true},
+			NetworkPolicy: NetworkPolicySettings{Isolation: IsolationNone},
+		},
+		MergeSpecs(base, override),
+	)
+}
@@ -0,0 +1,221 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusternamespace
+
+import (
+	"fmt"
+	"sort"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// Network isolation modes of a namespace.
+const (
+	// IsolationNone does not restrict the network traffic of a namespace.
+	IsolationNone = "none"
+
+	// IsolationDenyIngress denies every incoming connection to the pods of a namespace.
+	IsolationDenyIngress = "denyIngress"
+
+	// IsolationNamespace only allows incoming connections from the pods of the same namespace.
+	IsolationNamespace = "namespace"
+)
+
+// Spec describes the settings Pipeline manages on a namespace.
+type Spec struct {
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// ResourceQuota contains the hard limits of the namespace resource quota (eg. requests.cpu: 4).
+	ResourceQuota map[string]string
+
+	// LimitRange contains the default and allowed resources of containers in the namespace.
+	LimitRange LimitRange
+
+	// RoleBindings map organization members to namespace roles.
+	RoleBindings []RoleBinding
+
+	Vault         VaultSettings
+	NetworkPolicy NetworkPolicySettings
+}
+
+// LimitRange contains container resource constraints.
+type LimitRange struct {
+	Default        map[string]string
+	DefaultRequest map[string]string
+	Max            map[string]string
+	Min            map[string]string
+}
+
+// IsEmpty returns true if the limit range does not constrain anything.
+func (l LimitRange) IsEmpty() bool {
+	return len(l.Default) == 0 && len(l.DefaultRequest) == 0 && len(l.Max) == 0 && len(l.Min) == 0
+}
+
+// RoleBinding binds the members of an organization with the given role to a namespace role.
+type RoleBinding struct {
+	// OrganizationRole is the role of the members in the organization (admin or member).
+	OrganizationRole string
+
+	// NamespaceRole is the name of the cluster role granted in the namespace (eg. admin, edit or view).
+	NamespaceRole string
+}
+
+// VaultSettings contains the Vault related settings of a namespace.
+type VaultSettings struct {
+	// DisableSecretInjection excludes the namespace from the Vault secret injection webhook.
+	DisableSecretInjection bool
+}
+
+// NetworkPolicySettings contains the network policy settings of a namespace.
+type NetworkPolicySettings struct {
+	// Isolation is one of none, denyIngress or namespace. Empty means none.
+	Isolation string
+}
+
+// Validate validates the namespace spec.
+func (s Spec) Validate() error {
+	var violations []string
+
+	for key, value := range s.Labels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			violations = append(violations, fmt.Sprintf("invalid label key %q: %s", key, msg))
+		}
+
+		for _, msg := range validation.IsValidLabelValue(value) {
+			violations = append(violations, fmt.Sprintf("invalid value for label %q: %s", key, msg))
+		}
+	}
+
+	for key := range s.Annotations {
+		for _, msg := range validation.IsQualifiedName(key) {
+			violations = append(violations, fmt.Sprintf("invalid annotation key %q: %s", key, msg))
+		}
+	}
+
+	violations = validateQuantities(violations, "resourceQuota", s.ResourceQuota)
+	violations = validateQuantities(violations, "limitRange.default", s.LimitRange.Default)
+	violations = validateQuantities(violations, "limitRange.defaultRequest", s.LimitRange.DefaultRequest)
+	violations = validateQuantities(violations, "limitRange.max", s.LimitRange.Max)
+	violations = validateQuantities(violations, "limitRange.min", s.LimitRange.Min)
+
+	for _, binding := range s.RoleBindings {
+		if binding.OrganizationRole != auth.RoleAdmin && binding.OrganizationRole != auth.RoleMember {
+			violations = append(violations, fmt.Sprintf("invalid organization role %q: must be %s or %s", binding.OrganizationRole, auth.RoleAdmin, auth.RoleMember))
+		}
+
+		if binding.NamespaceRole == "" {
+			violations = append(violations, "namespace role must not be empty")
+		}
+	}
+
+	switch s.NetworkPolicy.Isolation {
+	case "", IsolationNone, IsolationDenyIngress, IsolationNamespace:
+	default:
+		violations = append(violations, fmt.Sprintf(
+			"invalid network isolation %q: must be %s, %s or %s",
+			s.NetworkPolicy.Isolation, IsolationNone, IsolationDenyIngress, IsolationNamespace,
+		))
+	}
+
+	if len(violations) > 0 {
+		sort.Strings(violations)
+
+		return errors.WithStack(cluster.NewValidationError("invalid namespace spec", violations))
+	}
+
+	return nil
+}
+
+func validateQuantities(violations []string, field string, quantities map[string]string) []string {
+	for name, value := range quantities {
+		if name == "" {
+			violations = append(violations, fmt.Sprintf("%s contains an empty resource name", field))
+			continue
+		}
+
+		if _, err := resource.ParseQuantity(value); err != nil {
+			violations = append(violations, fmt.Sprintf("invalid quantity %q for %s.%s", value, field, name))
+		}
+	}
+
+	return violations
+}
+
+// MergeSpecs returns a spec based on a template spec overridden by the non-empty fields of another spec.
+//
+// Maps are merged key by key, role bindings are combined.
+func MergeSpecs(base Spec, override Spec) Spec {
+	spec := Spec{
+		Labels:        mergeMaps(base.Labels, override.Labels),
+		Annotations:   mergeMaps(base.Annotations, override.Annotations),
+		ResourceQuota: mergeMaps(base.ResourceQuota, override.ResourceQuota),
+		LimitRange: LimitRange{
+			Default:        mergeMaps(base.LimitRange.Default, override.LimitRange.Default),
+			DefaultRequest: mergeMaps(base.LimitRange.DefaultRequest, override.LimitRange.DefaultRequest),
+			Max:            mergeMaps(base.LimitRange.Max, override.LimitRange.Max),
+			Min:            mergeMaps(base.LimitRange.Min, override.LimitRange.Min),
+		},
+		Vault: VaultSettings{
+			DisableSecretInjection: base.Vault.DisableSecretInjection || override.Vault.DisableSecretInjection,
+		},
+		NetworkPolicy: base.NetworkPolicy,
+	}
+
+	if override.NetworkPolicy.Isolation != "" {
+		spec.NetworkPolicy = override.NetworkPolicy
+	}
+
+	for _, binding := range append(append([]RoleBinding(nil), base.RoleBindings...), override.RoleBindings...) {
+		if !containsRoleBinding(spec.RoleBindings, binding) {
+			spec.RoleBindings = append(spec.RoleBindings, binding)
+		}
+	}
+
+	return spec
+}
+
+func mergeMaps(base map[string]string, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, len(base)+len(override))
+
+	for k, v := range base {
+		merged[k] = v
+	}
+
+	for k, v := range override {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+func containsRoleBinding(bindings []RoleBinding, binding RoleBinding) bool {
+	for _, b := range bindings {
+		if b == binding {
+			return true
+		}
+	}
+
+	return false
+}
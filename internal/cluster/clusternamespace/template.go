@@ -0,0 +1,107 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusternamespace
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// Template is a reusable namespace spec of an organization.
+type Template struct {
+	Name        string
+	Description string
+	Spec        Spec
+}
+
+// Validate validates the namespace template.
+func (t Template) Validate() error {
+	var violations []string
+
+	for _, msg := range validation.IsDNS1123Label(t.Name) {
+		violations = append(violations, fmt.Sprintf("invalid template name %q: %s", t.Name, msg))
+	}
+
+	if err := t.Spec.Validate(); err != nil {
+		violations = append(violations, unwrapViolations(err)...)
+	}
+
+	if len(violations) > 0 {
+		return errors.WithStack(cluster.NewValidationError("invalid namespace template", violations))
+	}
+
+	return nil
+}
+
+// TemplateStore persists the namespace templates of organizations.
+type TemplateStore interface {
+	// List returns the namespace templates of an organization.
+	List(ctx context.Context, organizationID uint) ([]Template, error)
+
+	// Get returns a namespace template of an organization.
+	// Returns a TemplateNotFoundError when the template does not exist.
+	Get(ctx context.Context, organizationID uint, name string) (Template, error)
+
+	// Save creates or replaces a namespace template of an organization.
+	Save(ctx context.Context, organizationID uint, template Template) error
+
+	// Delete removes a namespace template of an organization.
+	Delete(ctx context.Context, organizationID uint, name string) error
+}
+
+// TemplateNotFoundError is returned when a namespace template cannot be found.
+type TemplateNotFoundError struct {
+	OrganizationID uint
+	Name           string
+}
+
+// Error implements the error interface.
+func (TemplateNotFoundError) Error() string {
+	return "namespace template not found"
+}
+
+// Details returns error details.
+func (e TemplateNotFoundError) Details() []interface{} {
+	return []interface{}{"orgId", e.OrganizationID, "template", e.Name}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to status codes for example.
+func (TemplateNotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (TemplateNotFoundError) ServiceError() bool {
+	return true
+}
+
+func unwrapViolations(err error) []string {
+	var validationErr interface {
+		Violations() []string
+	}
+
+	if errors.As(err, &validationErr) {
+		return validationErr.Violations()
+	}
+
+	return []string{err.Error()}
+}
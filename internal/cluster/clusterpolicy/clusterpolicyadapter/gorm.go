@@ -0,0 +1,157 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpolicyadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+)
+
+const policyTableName = "cluster_policies"
+
+type policyModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_cluster_policies_organization_id"`
+	Policy         string `gorm:"type:text"`
+}
+
+func (policyModel) TableName() string {
+	return policyTableName
+}
+
+// policyDocument is the stored representation of a policy.
+type policyDocument struct {
+	AllowedClouds             []string `json:"allowedClouds,omitempty"`
+	AllowedDistributions      []string `json:"allowedDistributions,omitempty"`
+	AllowedLocations          []string `json:"allowedLocations,omitempty"`
+	AllowedInstanceTypes      []string `json:"allowedInstanceTypes,omitempty"`
+	AllowedKubernetesVersions []string `json:"allowedKubernetesVersions,omitempty"`
+	MaxNodesPerNodePool       int      `json:"maxNodesPerNodePool,omitempty"`
+	MaxNodesPerCluster        int      `json:"maxNodesPerCluster,omitempty"`
+	MaxClusters               int      `json:"maxClusters,omitempty"`
+	RequiredLabels            []string `json:"requiredLabels,omitempty"`
+}
+
+// Migrate executes the table migrations for the cluster policies.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	tables := []interface{}{
+		&policyModel{},
+	}
+
+	var tableNames string
+	for _, table := range tables {
+		tableNames += fmt.Sprintf(" %s", db.NewScope(table).TableName())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.TrimSpace(tableNames),
+	}).Info("migrating cluster policy tables")
+
+	return db.AutoMigrate(tables...).Error
+}
+
+// GormStore implements the clusterpolicy.Store interface using gorm.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+// Get implements the clusterpolicy.Store interface.
+func (s GormStore) Get(ctx context.Context, organizationID uint) (clusterpolicy.Policy, error) {
+	var model policyModel
+
+	err := s.db.Where(policyModel{OrganizationID: organizationID}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return clusterpolicy.Policy{}, errors.WithStack(clusterpolicy.NotFoundError{OrganizationID: organizationID})
+	} else if err != nil {
+		return clusterpolicy.Policy{}, errors.WrapIfWithDetails(err, "failed to get cluster policy", "orgId", organizationID)
+	}
+
+	var document policyDocument
+	if err := json.Unmarshal([]byte(model.Policy), &document); err != nil {
+		return clusterpolicy.Policy{}, errors.WrapIfWithDetails(err, "failed to unmarshal cluster policy", "orgId", organizationID)
+	}
+
+	return clusterpolicy.Policy(document), nil
+}
+
+// Save implements the clusterpolicy.Store interface.
+func (s GormStore) Save(ctx context.Context, organizationID uint, policy clusterpolicy.Policy) error {
+	document, err := json.Marshal(policyDocument(policy))
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to marshal cluster policy", "orgId", organizationID)
+	}
+
+	var model policyModel
+
+	err = s.db.
+		Where(policyModel{OrganizationID: organizationID}).
+		Assign(map[string]interface{}{
+			"policy": string(document),
+		}).
+		FirstOrCreate(&model).
+		Error
+
+	return errors.WrapIfWithDetails(err, "failed to save cluster policy", "orgId", organizationID)
+}
+
+// Delete implements the clusterpolicy.Store interface.
+func (s GormStore) Delete(ctx context.Context, organizationID uint) error {
+	err := s.db.Where(policyModel{OrganizationID: organizationID}).Delete(&policyModel{}).Error
+
+	return errors.WrapIfWithDetails(err, "failed to delete cluster policy", "orgId", organizationID)
+}
+
+// ClusterCounter counts the clusters of an organization.
+type ClusterCounter struct {
+	db *gorm.DB
+}
+
+// NewClusterCounter returns a new ClusterCounter.
+func NewClusterCounter(db *gorm.DB) ClusterCounter {
+	return ClusterCounter{
+		db: db,
+	}
+}
+
+// CountClusters implements the clusterpolicy.ClusterCounter interface.
+func (c ClusterCounter) CountClusters(ctx context.Context, organizationID uint) (int, error) {
+	var count int
+
+	err := c.db.
+		Table("clusters").
+		Where("deleted_at IS NULL AND organization_id = ?", organizationID).
+		Count(&count).
+		Error
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "failed to count clusters", "orgId", organizationID)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,49 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpolicyadapter
+
+import (
+	"context"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/src/cluster"
+)
+
+// NodePoolLister lists the node pools of existing clusters using the cluster manager.
+type NodePoolLister struct {
+	clusterManager *cluster.Manager
+}
+
+// NewNodePoolLister returns a new NodePoolLister.
+func NewNodePoolLister(clusterManager *cluster.Manager) NodePoolLister {
+	return NodePoolLister{
+		clusterManager: clusterManager,
+	}
+}
+
+// ListNodePools implements the clusterpolicy.NodePoolLister interface.
+func (l NodePoolLister) ListNodePools(ctx context.Context, clusterID uint) ([]clusterpolicy.NodePoolSpec, error) {
+	c, err := l.clusterManager.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := c.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterpolicy.SpecFromClusterStatus(status).NodePools, nil
+}
@@ -0,0 +1,170 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterpolicy
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// Policy restricts the clusters an organization can create.
+//
+// Empty lists allow every value and zero limits mean no limit.
+type Policy struct {
+	AllowedClouds             []string
+	AllowedDistributions      []string
+	AllowedLocations          []string
+	AllowedInstanceTypes      []string
+	AllowedKubernetesVersions []string
+
+	MaxNodesPerNodePool int
+	MaxNodesPerCluster  int
+	MaxClusters         int
+
+	RequiredLabels []string
+}
+
+// Validate validates the policy itself.
+func (p Policy) Validate() error {
+	var violations []string
+
+	if p.MaxNodesPerNodePool < 0 {
+		violations = append(violations, "maxNodesPerNodePool must not be negative")
+	}
+
+	if p.MaxNodesPerCluster < 0 {
+		violations = append(violations, "maxNodesPerCluster must not be negative")
+	}
+
+	if p.MaxClusters < 0 {
+		violations = append(violations, "maxClusters must not be negative")
+	}
+
+	for _, key := range p.RequiredLabels {
+		if key == "" {
+			violations = append(violations, "required label keys must be non-empty strings")
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.WithStack(cluster.NewValidationError("invalid cluster policy", violations))
+	}
+
+	return nil
+}
+
+// Store persists organization cluster policies.
+type Store interface {
+	// Get returns the cluster policy of an organization.
+	// Returns a NotFoundError when the organization has no policy.
+	Get(ctx context.Context, organizationID uint) (Policy, error)
+
+	// Save creates or replaces the cluster policy of an organization.
+	Save(ctx context.Context, organizationID uint, policy Policy) error
+
+	// Delete removes the cluster policy of an organization.
+	Delete(ctx context.Context, organizationID uint) error
+}
+
+// NotFoundError is returned when an organization has no cluster policy.
+type NotFoundError struct {
+	OrganizationID uint
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "cluster policy not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"orgId", e.OrganizationID}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to status codes for example.
+func (NotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (NotFoundError) ServiceError() bool {
+	return true
+}
+
+// Service manages organization cluster policies.
+type Service struct {
+	store Store
+}
+
+// NewService returns a new Service.
+func NewService(store Store) Service {
+	return Service{
+		store: store,
+	}
+}
+
+// GetPolicy returns the cluster policy of an organization.
+func (s Service) GetPolicy(ctx context.Context, organizationID uint) (Policy, error) {
+	return s.store.Get(ctx, organizationID)
+}
+
+// SetPolicy validates and saves the cluster policy of an organization.
+func (s Service) SetPolicy(ctx context.Context, organizationID uint, policy Policy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	return s.store.Save(ctx, organizationID, policy)
+}
+
+// DeletePolicy removes the cluster policy of an organization.
+func (s Service) DeletePolicy(ctx context.Context, organizationID uint) error {
+	return s.store.Delete(ctx, organizationID)
+}
+
+// getPolicy returns the policy of an organization or nil if there is none.
+func getPolicy(ctx context.Context, store Store, organizationID uint) (*Policy, error) {
+	policy, err := store.Get(ctx, organizationID)
+	if err != nil {
+		var notFoundErr NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIfWithDetails(err, "failed to get cluster policy", "orgId", organizationID)
+	}
+
+	return &policy, nil
+}
+
+func checkAllowed(violations []string, kind string, value string, allowed []string) []string {
+	if len(allowed) == 0 || value == "" {
+		return violations
+	}
+
+	for _, a := range allowed {
+		if a == value {
+			return violations
+		}
+	}
+
+	return append(violations, fmt.Sprintf("%s %q is not allowed by the organization policy", kind, value))
+}
@@ -0,0 +1,339 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpolicy
+
+import (
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/providers/pke"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// ClusterSpec contains the parts of a cluster restricted by policies.
+//
+// Empty fields are not checked.
+type ClusterSpec struct {
+	Cloud             string
+	Distribution      string
+	Location          string
+	KubernetesVersion string
+	NodePools         []NodePoolSpec
+	Labels            map[string]string
+}
+
+// NodePoolSpec contains the parts of a node pool restricted by policies.
+type NodePoolSpec struct {
+	Name         string
+	InstanceType string
+
+	// MaxNodes is the maximum number of nodes the node pool can scale to.
+	MaxNodes int
+
+	// Spot tells whether the nodes of the node pool are spot instances.
+	Spot bool
+}
+
+// SpecFromCreateRequest returns the restricted parts of a cluster described by a creation request.
+//
+// Node pools are only extracted for the supported cluster types.
+func SpecFromCreateRequest(request pkgCluster.CreateClusterRequest) (ClusterSpec, error) {
+	spec := ClusterSpec{
+		Cloud:    request.Cloud,
+		Location: request.Location,
+		Labels:   request.Labels,
+	}
+
+	if request.Properties == nil {
+		return spec, nil
+	}
+
+	switch {
+	case request.Properties.CreateClusterACK != nil:
+		spec.Distribution = pkgCluster.ACK
+
+	case request.Properties.CreateClusterOKE != nil:
+		spec.Distribution = pkgCluster.OKE
+
+	case request.Properties.CreateClusterEKS != nil:
+		spec.Distribution = pkgCluster.EKS
+		spec.KubernetesVersion = request.Properties.CreateClusterEKS.Version
+
+		for name, nodePool := range request.Properties.CreateClusterEKS.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			spec.NodePools = append(spec.NodePools, NodePoolSpec{
+				Name:         name,
+				InstanceType: nodePool.InstanceType,
+				MaxNodes:     maxNodes(nodePool.Count, nodePool.MaxCount, nodePool.Autoscaling),
+				Spot:         isSpot(nodePool.SpotPrice),
+			})
+		}
+
+	case request.Properties.CreateClusterAKS != nil:
+		spec.Distribution = pkgCluster.AKS
+		spec.KubernetesVersion = request.Properties.CreateClusterAKS.KubernetesVersion
+
+		for name, nodePool := range request.Properties.CreateClusterAKS.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			spec.NodePools = append(spec.NodePools, NodePoolSpec{
+				Name:         name,
+				InstanceType: nodePool.NodeInstanceType,
+				MaxNodes:     maxNodes(nodePool.Count, nodePool.MaxCount, nodePool.Autoscaling),
+			})
+		}
+
+	case request.Properties.CreateClusterGKE != nil:
+		spec.Distribution = pkgCluster.GKE
+
+		if master := request.Properties.CreateClusterGKE.Master; master != nil {
+			spec.KubernetesVersion = master.Version
+		}
+
+		for name, nodePool := range request.Properties.CreateClusterGKE.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			spec.NodePools = append(spec.NodePools, NodePoolSpec{
+				Name:         name,
+				InstanceType: nodePool.NodeInstanceType,
+				MaxNodes:     maxNodes(nodePool.Count, nodePool.MaxCount, nodePool.Autoscaling),
+			})
+		}
+
+	case request.Properties.CreateClusterPKE != nil:
+		spec.Distribution = pkgCluster.PKE
+		spec.KubernetesVersion = request.Properties.CreateClusterPKE.Kubernetes.Version
+
+		if request.Cloud != pkgCluster.Amazon {
+			break
+		}
+
+		for _, nodePool := range request.Properties.CreateClusterPKE.NodePools {
+			var providerConfig pke.NodePoolProviderConfigAmazon
+
+			err := mapstructure.Decode(nodePool.ProviderConfig, &providerConfig)
+			if err != nil {
+				return ClusterSpec{}, errors.WrapIfWithDetails(err, "failed to decode node pool provider config", "nodePool", nodePool.Name)
+			}
+
+			size := providerConfig.AutoScalingGroup.Size
+
+			spec.NodePools = append(spec.NodePools, NodePoolSpec{
+				Name:         nodePool.Name,
+				InstanceType: providerConfig.AutoScalingGroup.InstanceType,
+				MaxNodes:     maxNodes(size.Desired, size.Max, nodePool.Autoscaling),
+				Spot:         isSpot(providerConfig.AutoScalingGroup.SpotPrice),
+			})
+		}
+	}
+
+	return spec, nil
+}
+
+// SpecFromClusterStatus returns the restricted parts of an existing cluster.
+func SpecFromClusterStatus(status *pkgCluster.GetClusterStatusResponse) ClusterSpec {
+	spec := ClusterSpec{
+		Cloud:        status.Cloud,
+		Distribution: status.Distribution,
+		Location:     status.Location,
+		Labels:       status.Labels,
+	}
+
+	for name, nodePool := range status.NodePools {
+		if nodePool == nil {
+			continue
+		}
+
+		spec.NodePools = append(spec.NodePools, NodePoolSpec{
+			Name:         name,
+			InstanceType: nodePool.InstanceType,
+			MaxNodes:     maxNodes(nodePool.Count, nodePool.MaxCount, nodePool.Autoscaling),
+		})
+	}
+
+	return spec
+}
+
+// ApplyUpdateRequest returns the restricted parts of a cluster after applying an update request.
+//
+// EKS, GKE and PKE updates describe every node pool (missing ones are deleted),
+// AKS updates only change the listed node pools.
+// Instance types are inherited from the existing node pools when not specified.
+func ApplyUpdateRequest(spec ClusterSpec, request pkgCluster.UpdateClusterRequest) ClusterSpec {
+	current := make(map[string]NodePoolSpec, len(spec.NodePools))
+	for _, nodePool := range spec.NodePools {
+		current[nodePool.Name] = nodePool
+	}
+
+	var nodePools []NodePoolSpec
+
+	switch {
+	case request.EKS != nil:
+		for name, nodePool := range request.EKS.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			nodePools = append(nodePools, NodePoolSpec{
+				Name:         name,
+				InstanceType: inheritInstanceType(nodePool.InstanceType, current[name]),
+				MaxNodes:     maxNodes(nodePool.Count, nodePool.MaxCount, nodePool.Autoscaling),
+			})
+		}
+
+	case request.GKE != nil:
+		if master := request.GKE.Master; master != nil && master.Version != "" {
+			spec.KubernetesVersion = master.Version
+		}
+
+		for name, nodePool := range request.GKE.NodePools {
+			if nodePool == nil {
+				continue
+			}
+
+			nodePools = append(nodePools, NodePoolSpec{
+				Name:         name,
+				InstanceType: inheritInstanceType(nodePool.NodeInstanceType, current[name]),
+				MaxNodes:     maxNodes(nodePool.Count, nodePool.MaxCount, nodePool.Autoscaling),
+			})
+		}
+
+	case request.PKE != nil:
+		// master node pools cannot be changed by updates
+		if master, ok := current["master"]; ok {
+			nodePools = append(nodePools, master)
+		}
+
+		for name, nodePool := range request.PKE.NodePools {
+			nodePools = append(nodePools, NodePoolSpec{
+				Name:         name,
+				InstanceType: inheritInstanceType(nodePool.InstanceType, current[name]),
+				MaxNodes:     maxNodes(nodePool.Count, nodePool.MaxCount, nodePool.Autoscaling),
+			})
+		}
+
+	case request.AKS != nil:
+		for name, nodePool := range current {
+			if nodePoolUpdate, ok := request.AKS.NodePools[name]; ok && nodePoolUpdate != nil {
+				nodePool.MaxNodes = maxNodes(nodePoolUpdate.Count, nodePoolUpdate.MaxCount, nodePoolUpdate.Autoscaling)
+			}
+
+			nodePools = append(nodePools, nodePool)
+		}
+
+	default:
+		return spec
+	}
+
+	spec.NodePools = nodePools
+
+	return spec
+}
+
+// ApplyNodePoolsUpdateRequest returns the restricted parts of a cluster after resizing its node pools.
+func ApplyNodePoolsUpdateRequest(status *pkgCluster.GetClusterStatusResponse, request pkgCluster.UpdateNodePoolsRequest) ClusterSpec {
+	spec := SpecFromClusterStatus(status)
+
+	for i, nodePool := range spec.NodePools {
+		nodePoolUpdate, ok := request.NodePools[nodePool.Name]
+		if !ok || nodePoolUpdate == nil {
+			continue
+		}
+
+		nodePoolStatus := status.NodePools[nodePool.Name]
+
+		spec.NodePools[i].MaxNodes = maxNodes(nodePoolUpdate.Count, nodePoolStatus.MaxCount, nodePoolStatus.Autoscaling)
+	}
+
+	return spec
+}
+
+// NodePoolSpecFromNew returns the restricted parts of a new node pool descriptor.
+func NodePoolSpecFromNew(rawNodePool cluster.NewRawNodePool) (NodePoolSpec, error) {
+	var nodePool rawNodePoolSpec
+
+	err := mapstructure.Decode(rawNodePool, &nodePool)
+	if err != nil {
+		return NodePoolSpec{}, errors.WrapIf(err, "failed to decode node pool")
+	}
+
+	return NodePoolSpec{
+		Name:         nodePool.Name,
+		InstanceType: nodePool.InstanceType,
+		MaxNodes:     maxNodes(nodePool.Size, nodePool.Autoscaling.MaxSize, nodePool.Autoscaling.Enabled),
+	}, nil
+}
+
+// applyNodePoolUpdate returns the restricted parts of a node pool after applying an update descriptor.
+func applyNodePoolUpdate(nodePool NodePoolSpec, rawNodePoolUpdate cluster.RawNodePoolUpdate) (NodePoolSpec, error) {
+	var nodePoolUpdate rawNodePoolSpec
+
+	err := mapstructure.Decode(rawNodePoolUpdate, &nodePoolUpdate)
+	if err != nil {
+		return NodePoolSpec{}, errors.WrapIf(err, "failed to decode node pool update")
+	}
+
+	nodePool.InstanceType = inheritInstanceType(nodePoolUpdate.InstanceType, nodePool)
+
+	// updates without sizing information keep the current size
+	_, hasSize := rawNodePoolUpdate["size"]
+	_, hasAutoscaling := rawNodePoolUpdate["autoscaling"]
+	if hasSize || hasAutoscaling {
+		nodePool.MaxNodes = maxNodes(nodePoolUpdate.Size, nodePoolUpdate.Autoscaling.MaxSize, nodePoolUpdate.Autoscaling.Enabled)
+	}
+
+	return nodePool, nil
+}
+
+// rawNodePoolSpec decodes the common fields of raw node pool descriptors.
+type rawNodePoolSpec struct {
+	Name         string `mapstructure:"name"`
+	InstanceType string `mapstructure:"instanceType"`
+	Size         int    `mapstructure:"size"`
+	Autoscaling  struct {
+		Enabled bool `mapstructure:"enabled"`
+		MaxSize int  `mapstructure:"maxSize"`
+	} `mapstructure:"autoscaling"`
+}
+
+// maxNodes returns the maximum number of nodes a node pool can scale to.
+func maxNodes(count int, maxCount int, autoscaling bool) int {
+	if autoscaling && maxCount > count {
+		return maxCount
+	}
+
+	return count
+}
+
+// isSpot tells whether a node pool with the given spot price uses spot instances.
+func isSpot(spotPrice string) bool {
+	return spotPrice != "" && spotPrice != "0"
+}
+
+func inheritInstanceType(instanceType string, current NodePoolSpec) string {
+	if instanceType != "" {
+		return instanceType
+	}
+
+	return current.InstanceType
+}
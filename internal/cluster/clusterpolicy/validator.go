@@ -0,0 +1,262 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// ClusterCounter counts the clusters of an organization.
+type ClusterCounter interface {
+	// CountClusters returns the number of existing clusters in an organization.
+	CountClusters(ctx context.Context, organizationID uint) (int, error)
+}
+
+// NodePoolLister lists the node pools of existing clusters.
+type NodePoolLister interface {
+	// ListNodePools returns the node pools of a cluster.
+	ListNodePools(ctx context.Context, clusterID uint) ([]NodePoolSpec, error)
+}
+
+// Validator checks cluster and node pool requests against organization policies.
+type Validator struct {
+	policies  Store
+	clusters  ClusterCounter
+	nodePools NodePoolLister
+}
+
+// NewValidator returns a new Validator.
+func NewValidator(policies Store, clusters ClusterCounter, nodePools NodePoolLister) Validator {
+	return Validator{
+		policies:  policies,
+		clusters:  clusters,
+		nodePools: nodePools,
+	}
+}
+
+// ValidateClusterCreate checks a new cluster against the policy of an organization.
+func (v Validator) ValidateClusterCreate(ctx context.Context, organizationID uint, spec ClusterSpec) error {
+	policy, err := getPolicy(ctx, v.policies, organizationID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	var violations []string
+
+	violations = checkAllowed(violations, "cloud", spec.Cloud, policy.AllowedClouds)
+	violations = checkAllowed(violations, "distribution", spec.Distribution, policy.AllowedDistributions)
+	violations = checkAllowed(violations, "location", spec.Location, policy.AllowedLocations)
+	violations = policy.checkKubernetesVersion(violations, spec.KubernetesVersion)
+
+	violations = policy.checkRequiredLabels(violations, spec.Labels)
+
+	for _, nodePool := range spec.NodePools {
+		violations = policy.checkNodePool(violations, nodePool)
+	}
+
+	violations = policy.checkClusterNodes(violations, spec.NodePools)
+
+	if policy.MaxClusters > 0 {
+		count, err := v.clusters.CountClusters(ctx, organizationID)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to count clusters", "orgId", organizationID)
+		}
+
+		if count >= policy.MaxClusters {
+			violations = append(violations, fmt.Sprintf("the organization policy allows at most %d clusters", policy.MaxClusters))
+		}
+	}
+
+	return newPolicyViolationError(violations)
+}
+
+// ValidateClusterUpdate checks the desired state of an updated cluster against the policy of an organization.
+func (v Validator) ValidateClusterUpdate(ctx context.Context, organizationID uint, spec ClusterSpec) error {
+	policy, err := getPolicy(ctx, v.policies, organizationID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	var violations []string
+
+	violations = policy.checkKubernetesVersion(violations, spec.KubernetesVersion)
+
+	for _, nodePool := range spec.NodePools {
+		violations = policy.checkNodePool(violations, nodePool)
+	}
+
+	violations = policy.checkClusterNodes(violations, spec.NodePools)
+
+	return newPolicyViolationError(violations)
+}
+
+// ValidateClusterUpgrade checks the target Kubernetes version of a cluster upgrade against the policy of an organization.
+func (v Validator) ValidateClusterUpgrade(ctx context.Context, organizationID uint, kubernetesVersion string) error {
+	return v.ValidateClusterUpdate(ctx, organizationID, ClusterSpec{KubernetesVersion: kubernetesVersion})
+}
+
+// ValidateClusterLabels checks the new labels of an existing cluster against the policy of the cluster's organization.
+//
+// It implements the cluster.ClusterLabelValidator interface.
+func (v Validator) ValidateClusterLabels(ctx context.Context, c cluster.Cluster, labels map[string]string) error {
+	policy, err := getPolicy(ctx, v.policies, c.OrganizationID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	return newPolicyViolationError(policy.checkRequiredLabels(nil, labels))
+}
+
+// ValidateNew checks a new node pool against the policy of the cluster's organization.
+//
+// It implements the cluster.NodePoolValidator interface.
+func (v Validator) ValidateNew(ctx context.Context, c cluster.Cluster, rawNodePool cluster.NewRawNodePool) error {
+	policy, err := getPolicy(ctx, v.policies, c.OrganizationID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	nodePool, err := NodePoolSpecFromNew(rawNodePool)
+	if err != nil {
+		return err
+	}
+
+	nodePools, err := v.nodePools.ListNodePools(ctx, c.ID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list node pools", "clusterId", c.ID)
+	}
+
+	var violations []string
+
+	violations = policy.checkNodePool(violations, nodePool)
+	violations = policy.checkClusterNodes(violations, append(nodePools, nodePool))
+
+	return newPolicyViolationError(violations)
+}
+
+// ValidateUpdate checks a node pool update against the policy of the cluster's organization.
+//
+// It implements the cluster.NodePoolUpdateValidator interface.
+func (v Validator) ValidateUpdate(
+	ctx context.Context,
+	c cluster.Cluster,
+	nodePoolName string,
+	rawNodePoolUpdate cluster.RawNodePoolUpdate,
+) error {
+	policy, err := getPolicy(ctx, v.policies, c.OrganizationID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	nodePools, err := v.nodePools.ListNodePools(ctx, c.ID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list node pools", "clusterId", c.ID)
+	}
+
+	var violations []string
+
+	for i, nodePool := range nodePools {
+		if nodePool.Name != nodePoolName {
+			continue
+		}
+
+		nodePool, err := applyNodePoolUpdate(nodePool, rawNodePoolUpdate)
+		if err != nil {
+			return err
+		}
+
+		nodePools[i] = nodePool
+		violations = policy.checkNodePool(violations, nodePool)
+	}
+
+	violations = policy.checkClusterNodes(violations, nodePools)
+
+	return newPolicyViolationError(violations)
+}
+
+func (p Policy) checkKubernetesVersion(violations []string, version string) []string {
+	if len(p.AllowedKubernetesVersions) == 0 || version == "" {
+		return violations
+	}
+
+	// Allowed versions also match patch versions (eg. 1.15 allows 1.15.10)
+	for _, allowed := range p.AllowedKubernetesVersions {
+		if version == allowed || strings.HasPrefix(version, allowed+".") {
+			return violations
+		}
+	}
+
+	return append(violations, fmt.Sprintf("Kubernetes version %q is not allowed by the organization policy", version))
+}
+
+func (p Policy) checkRequiredLabels(violations []string, labels map[string]string) []string {
+	for _, key := range p.RequiredLabels {
+		if _, ok := labels[key]; !ok {
+			violations = append(violations, fmt.Sprintf("label %q is required by the organization policy", key))
+		}
+	}
+
+	return violations
+}
+
+func (p Policy) checkNodePool(violations []string, nodePool NodePoolSpec) []string {
+	violations = checkAllowed(violations, "instance type", nodePool.InstanceType, p.AllowedInstanceTypes)
+
+	if p.MaxNodesPerNodePool > 0 && nodePool.MaxNodes > p.MaxNodesPerNodePool {
+		violations = append(violations, fmt.Sprintf(
+			"node pool %q may have up to %d nodes, the organization policy allows at most %d",
+			nodePool.Name,
+			nodePool.MaxNodes,
+			p.MaxNodesPerNodePool,
+		))
+	}
+
+	return violations
+}
+
+func (p Policy) checkClusterNodes(violations []string, nodePools []NodePoolSpec) []string {
+	if p.MaxNodesPerCluster == 0 {
+		return violations
+	}
+
+	var nodes int
+	for _, nodePool := range nodePools {
+		nodes += nodePool.MaxNodes
+	}
+
+	if nodes > p.MaxNodesPerCluster {
+		violations = append(violations, fmt.Sprintf(
+			"cluster may have up to %d nodes, the organization policy allows at most %d",
+			nodes,
+			p.MaxNodesPerCluster,
+		))
+	}
+
+	return violations
+}
+
+func newPolicyViolationError(violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return errors.WithStack(cluster.NewValidationError("cluster violates organization policy", violations))
+}
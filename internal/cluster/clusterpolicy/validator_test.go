@@ -0,0 +1,294 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpolicy
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+type fakeStore map[uint]Policy
+
+func (s fakeStore) Get(_ context.Context, organizationID uint) (Policy, error) {
+	policy, ok := s[organizationID]
+	if !ok {
+		return Policy{}, errors.WithStack(NotFoundError{OrganizationID: organizationID})
+	}
+
+	return policy, nil
+}
+
+func (s fakeStore) Save(_ context.Context, organizationID uint, policy Policy) error {
+	s[organizationID] = policy
+
+	return nil
+}
+
+func (s fakeStore) Delete(_ context.Context, organizationID uint) error {
+	delete(s, organizationID)
+
+	return nil
+}
+
+type fakeClusterCounter map[uint]int
+
+func (c fakeClusterCounter) CountClusters(_ context.Context, organizationID uint) (int, error) {
+	return c[organizationID], nil
+}
+
+type fakeNodePoolLister map[uint][]NodePoolSpec
+
+func (l fakeNodePoolLister) ListNodePools(_ context.Context, clusterID uint) ([]NodePoolSpec, error) {
+	return append([]NodePoolSpec(nil), l[clusterID]...), nil
+}
+
+func requireViolations(t *testing.T, err error) []string {
+	t.Helper()
+
+	require.Error(t, err)
+
+	var verr cluster.ValidationError
+	require.True(t, errors.As(err, &verr))
+
+	return verr.Violations()
+}
+
+func TestValidator_ValidateClusterCreate(t *testing.T) {
+	store := fakeStore{
+		1: {
+			AllowedClouds:             []string{"amazon"},
+			AllowedDistributions:      []string{"eks", "pke"},
+			AllowedLocations:          []string{"eu-west-1"},
+			AllowedInstanceTypes:      []string{"t2.medium"},
+			AllowedKubernetesVersions: []string{"1.15"},
+			MaxNodesPerNodePool:       5,
+			MaxNodesPerCluster:        8,
+			MaxClusters:               2,
+			RequiredLabels:            []string{"team"},
+		},
+	}
+	clusters := fakeClusterCounter{1: 1}
+
+	validator := NewValidator(store, clusters, fakeNodePoolLister{})
+
+	t.Run("Valid", func(t *testing.T) {
+		err := validator.ValidateClusterCreate(context.Background(), 1, ClusterSpec{
+			Cloud:             "amazon",
+			Distribution:      "eks",
+			Location:          "eu-west-1",
+			KubernetesVersion: "1.15.10",
+			NodePools: []NodePoolSpec{
+				{Name: "pool0", InstanceType: "t2.medium", MaxNodes: 5},
+				{Name: "pool1", InstanceType: "t2.medium", MaxNodes: 3},
+			},
+			Labels: map[string]string{"team": "platform"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		err := validator.ValidateClusterCreate(context.Background(), 1, ClusterSpec{
+			Cloud:             "google",
+			Distribution:      "gke",
+			Location:          "us-central1",
+			KubernetesVersion: "1.16.8",
+			NodePools: []NodePoolSpec{
+				{Name: "pool0", InstanceType: "n1-standard-2", MaxNodes: 6},
+				{Name: "pool1", InstanceType: "t2.medium", MaxNodes: 3},
+			},
+		})
+
+		assert.Equal(
+			t,
+			[]string{
+				`cloud "google" is not allowed by the organization policy`,
+				`distribution "gke" is not allowed by the organization policy`,
+				`location "us-central1" is not allowed by the organization policy`,
+				`Kubernetes version "1.16.8" is not allowed by the organization policy`,
+				`label "team" is required by the organization policy`,
+				`instance type "n1-standard-2" is not allowed by the organization policy`,
+				`node pool "pool0" may have up to 6 nodes, the organization policy allows at most 5`,
+				`cluster may have up to 9 nodes, the organization policy allows at most 8`,
+			},
+			requireViolations(t, err),
+		)
+	})
+
+	t.Run("MaxClusters", func(t *testing.T) {
+		clusters := fakeClusterCounter{1: 2}
+
+		validator := NewValidator(store, clusters, fakeNodePoolLister{})
+
+		err := validator.ValidateClusterCreate(context.Background(), 1, ClusterSpec{
+			Cloud:  "amazon",
+			Labels: map[string]string{"team": "platform"},
+		})
+
+		assert.Equal(t, []string{"the organization policy allows at most 2 clusters"}, requireViolations(t, err))
+	})
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		err := validator.ValidateClusterCreate(context.Background(), 2, ClusterSpec{Cloud: "google"})
+		require.NoError(t, err)
+	})
+}
+
+func TestValidator_ValidateNew(t *testing.T) {
+	store := fakeStore{
+		1: {
+			AllowedInstanceTypes: []string{"t2.medium"},
+			MaxNodesPerNodePool:  5,
+			MaxNodesPerCluster:   8,
+		},
+	}
+	nodePools := fakeNodePoolLister{
+		1: {
+			{Name: "pool0", InstanceType: "t2.medium", MaxNodes: 5},
+		},
+	}
+
+	validator := NewValidator(store, fakeClusterCounter{}, nodePools)
+
+	c := cluster.Cluster{ID: 1, OrganizationID: 1}
+
+	t.Run("Valid", func(t *testing.T) {
+		err := validator.ValidateNew(context.Background(), c, cluster.NewRawNodePool{
+			"name":         "pool1",
+			"instanceType": "t2.medium",
+			"size":         1,
+			"autoscaling": map[string]interface{}{
+				"enabled": true,
+				"maxSize": 3,
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		err := validator.ValidateNew(context.Background(), c, cluster.NewRawNodePool{
+			"name":         "pool1",
+			"instanceType": "m5.xlarge",
+			"size":         6,
+		})
+
+		assert.Equal(
+			t,
+			[]string{
+				`instance type "m5.xlarge" is not allowed by the organization policy`,
+				`node pool "pool1" may have up to 6 nodes, the organization policy allows at most 5`,
+				`cluster may have up to 11 nodes, the organization policy allows at most 8`,
+			},
+			requireViolations(t, err),
+		)
+	})
+}
+
+func TestValidator_ValidateUpdate(t *testing.T) {
+	store := fakeStore{
+		1: {
+			MaxNodesPerCluster: 8,
+		},
+	}
+	nodePools := fakeNodePoolLister{
+		1: {
+			{Name: "pool0", InstanceType: "t2.medium", MaxNodes: 5},
+			{Name: "pool1", InstanceType: "t2.medium", MaxNodes: 2},
+		},
+	}
+
+	validator := NewValidator(store, fakeClusterCounter{}, nodePools)
+
+	c := cluster.Cluster{ID: 1, OrganizationID: 1}
+
+	err := validator.ValidateUpdate(context.Background(), c, "pool1", cluster.RawNodePoolUpdate{"size": 3})
+	require.NoError(t, err)
+
+	err = validator.ValidateUpdate(context.Background(), c, "pool1", cluster.RawNodePoolUpdate{"size": 4})
+	assert.Equal(
+		t,
+		[]string{"cluster may have up to 9 nodes, the organization policy allows at most 8"},
+		requireViolations(t, err),
+	)
+}
+
+func TestValidator_ValidateClusterUpgrade(t *testing.T) {
+	store := fakeStore{
+		1: {
+			AllowedKubernetesVersions: []string{"1.15"},
+		},
+	}
+
+	validator := NewValidator(store, fakeClusterCounter{}, fakeNodePoolLister{})
+
+	err := validator.ValidateClusterUpgrade(context.Background(), 1, "1.15.10")
+	require.NoError(t, err)
+
+	err = validator.ValidateClusterUpgrade(context.Background(), 1, "1.16")
+	assert.Equal(
+		t,
+		[]string{`Kubernetes version "1.16" is not allowed by the organization policy`},
+		requireViolations(t, err),
+	)
+}
+
+func TestValidator_ValidateClusterLabels(t *testing.T) {
+	store := fakeStore{
+		1: {
+			RequiredLabels: []string{"team"},
+		},
+	}
+
+	validator := NewValidator(store, fakeClusterCounter{}, fakeNodePoolLister{})
+
+	err := validator.ValidateClusterLabels(context.Background(), cluster.Cluster{ID: 1, OrganizationID: 1}, map[string]string{"team": "a"})
+	require.NoError(t, err)
+
+	err = validator.ValidateClusterLabels(context.Background(), cluster.Cluster{ID: 1, OrganizationID: 1}, map[string]string{"env": "prod"})
+	assert.Equal(
+		t,
+		[]string{`label "team" is required by the organization policy`},
+		requireViolations(t, err),
+	)
+
+	// organizations without a policy have no required labels
+	err = validator.ValidateClusterLabels(context.Background(), cluster.Cluster{ID: 2, OrganizationID: 2}, nil)
+	require.NoError(t, err)
+}
+
+func TestService_SetPolicy(t *testing.T) {
+	store := fakeStore{}
+	service := NewService(store)
+
+	err := service.SetPolicy(context.Background(), 1, Policy{MaxClusters: -1, RequiredLabels: []string{""}})
+	assert.Equal(
+		t,
+		[]string{"maxClusters must not be negative", "required label keys must be non-empty strings"},
+		requireViolations(t, err),
+	)
+	assert.Empty(t, store)
+
+	err = service.SetPolicy(context.Background(), 1, Policy{MaxClusters: 3})
+	require.NoError(t, err)
+
+	policy, err := service.GetPolicy(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, Policy{MaxClusters: 3}, policy)
+}
@@ -0,0 +1,133 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpreflightadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/network"
+	"github.com/banzaicloud/pipeline/internal/providers"
+	"github.com/banzaicloud/pipeline/src/secret"
+)
+
+// ClusterNameChecker checks cluster names in the database.
+type ClusterNameChecker struct {
+	db *gorm.DB
+}
+
+// NewClusterNameChecker returns a new ClusterNameChecker.
+func NewClusterNameChecker(db *gorm.DB) ClusterNameChecker {
+	return ClusterNameChecker{
+		db: db,
+	}
+}
+
+// ClusterExists implements the clusterpreflight.ClusterNameChecker interface.
+func (c ClusterNameChecker) ClusterExists(ctx context.Context, organizationID uint, name string) (bool, error) {
+	var count int
+
+	err := c.db.
+		Table("clusters").
+		Where("deleted_at IS NULL AND organization_id = ? AND name = ?", organizationID, name).
+		Count(&count).
+		Error
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to check cluster existence", "orgId", organizationID, "clusterName", name)
+	}
+
+	return count > 0, nil
+}
+
+// SecretStore provides access to secrets.
+type SecretStore interface {
+	// Get returns a secret.
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+
+	// Verify verifies the credentials of a secret (if the secret type supports it).
+	Verify(organizationID uint, secretID string) error
+}
+
+// SecretVerifier verifies secrets using the secret store.
+type SecretVerifier struct {
+	secrets SecretStore
+}
+
+// NewSecretVerifier returns a new SecretVerifier.
+func NewSecretVerifier(secrets SecretStore) SecretVerifier {
+	return SecretVerifier{
+		secrets: secrets,
+	}
+}
+
+// VerifySecret implements the clusterpreflight.SecretVerifier interface.
+func (v SecretVerifier) VerifySecret(ctx context.Context, organizationID uint, secretID string, cloud string) error {
+	s, err := v.secrets.Get(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	if err := secret.ValidateSecretType(s, cloud); err != nil {
+		return err
+	}
+
+	return v.secrets.Verify(organizationID, secretID)
+}
+
+// NetworkServiceFactory creates cloud provider network services.
+type NetworkServiceFactory struct {
+	secrets SecretStore
+	logger  logrus.FieldLogger
+}
+
+// NewNetworkServiceFactory returns a new NetworkServiceFactory.
+func NewNetworkServiceFactory(secrets SecretStore, logger logrus.FieldLogger) NetworkServiceFactory {
+	return NetworkServiceFactory{
+		secrets: secrets,
+		logger:  logger,
+	}
+}
+
+// NewNetworkService implements the clusterpreflight.NetworkServiceFactory interface.
+func (f NetworkServiceFactory) NewNetworkService(
+	ctx context.Context,
+	organizationID uint,
+	secretID string,
+	cloud string,
+	region string,
+	resourceGroup string,
+) (network.Service, error) {
+	s, err := f.secrets.Get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	return providers.NewNetworkService(providers.ServiceParams{
+		Logger: f.logger.WithFields(logrus.Fields{
+			"organization":  organizationID,
+			"provider":      cloud,
+			"region":        region,
+			"resourceGroup": resourceGroup,
+			"secretID":      secretID,
+		}),
+		Provider:          cloud,
+		Region:            region,
+		ResourceGroupName: resourceGroup,
+		Secret:            s,
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterpreflightadapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	"github.com/banzaicloud/pipeline/pkg/providers/amazon"
+)
+
+// Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances
+const onDemandStandardVCPUQuotaCode = "L-1216C47A"
+
+type AmazonAccountChecker struct {
+	secrets SecretStore
+}
+
+func NewAmazonAccountChecker(secrets SecretStore) AmazonAccountChecker {
+	return AmazonAccountChecker{
+		secrets: secrets,
+	}
+}
+
+func (c AmazonAccountChecker) newSession(organizationID uint, secretID string, region string) (*session.Session, error) {
+	s, err := c.secrets.Get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: amazon.CreateAWSCredentials(s.Values),
+		Region:      aws.String(region),
+	})
+
+	return sess, errors.WrapIf(err, "failed to create AWS session")
+}
+
+func (c AmazonAccountChecker) AvailableOnDemandVCPUs(ctx context.Context, organizationID uint, secretID string, region string) (int, error) {
+	sess, err := c.newSession(organizationID, secretID, region)
+	if err != nil {
+		return 0, err
+	}
+
+	quota, err := servicequotas.New(sess).GetServiceQuotaWithContext(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String("ec2"),
+		QuotaCode:   aws.String(onDemandStandardVCPUQuotaCode),
+	})
+	if err != nil {
+		return 0, errors.WrapIf(err, "failed to get on-demand vCPU quota")
+	}
+
+	used := 0
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+			},
+		},
+	}
+
+	err = ec2.New(sess).DescribeInstancesPagesWithContext(ctx, input, func(page *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				// spot and scheduled instances have separate quotas
+				if instance.InstanceLifecycle != nil || instance.CpuOptions == nil {
+					continue
+				}
+
+				if !isStandardInstanceType(aws.StringValue(instance.InstanceType)) {
+					continue
+				}
+
+				used += int(aws.Int64Value(instance.CpuOptions.CoreCount) * aws.Int64Value(instance.CpuOptions.ThreadsPerCore))
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return 0, errors.WrapIf(err, "failed to list instances")
+	}
+
+	available := int(aws.Float64Value(quota.Quota.Value)) - used
+	if available < 0 {
+		return 0, nil
+	}
+
+	return available, nil
+}
+
+// isStandardInstanceType tells whether an instance type belongs to the standard instance families.
+func isStandardInstanceType(instanceType string) bool {
+	return instanceType != "" && strings.ContainsAny(instanceType[:1], "acdhimrtz")
+}
+
+func (c AmazonAccountChecker) DeniedActions(ctx context.Context, organizationID uint, secretID string, region string, actions []string) ([]string, error) {
+	sess, err := c.newSession(organizationID, secretID, region)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := sts.New(sess).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get caller identity")
+	}
+
+	principal, err := arn.Parse(aws.StringValue(identity.Arn))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to parse caller identity")
+	}
+
+	switch {
+	case principal.Resource == "root":
+		// the root user of the account is allowed to perform every action
+		return nil, nil
+
+	case principal.Service == "sts" && strings.HasPrefix(principal.Resource, "assumed-role/"):
+		// policies of an assumed role session can only be simulated through the role
+		roleName := strings.Split(principal.Resource, "/")[1]
+		principal = arn.ARN{
+			Partition: principal.Partition,
+			Service:   "iam",
+			AccountID: principal.AccountID,
+			Resource:  fmt.Sprintf("role/%s", roleName),
+		}
+	}
+
+	var denied []string
+
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principal.String()),
+		ActionNames:     aws.StringSlice(actions),
+	}
+
+	err = iam.New(sess).SimulatePrincipalPolicyPagesWithContext(ctx, input, func(page *iam.SimulatePolicyResponse, _ bool) bool {
+		for _, result := range page.EvaluationResults {
+			if aws.StringValue(result.EvalDecision) != iam.PolicyEvaluationDecisionTypeAllowed {
+				denied = append(denied, aws.StringValue(result.EvalActionName))
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to simulate IAM policies")
+	}
+
+	return denied, nil
+}
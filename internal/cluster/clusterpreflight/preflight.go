@@ -0,0 +1,409 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpreflight
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/.gen/cloudinfo"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/internal/network"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Request describes a cluster creation request to be checked without creating the cluster.
+type Request struct {
+	OrganizationID uint
+	Name           string
+
+	// SecretIDs are the candidate secrets of the cluster, one of them has to belong to the cloud provider.
+	SecretIDs []string
+
+	Cluster clusterpolicy.ClusterSpec
+
+	// ResourceGroup is the Azure resource group of the cluster.
+	ResourceGroup string
+
+	// NetworkID references an existing VPC network (if any).
+	NetworkID string
+
+	// SubnetIDs reference existing subnetworks (if any) of the VPC network.
+	SubnetIDs []string
+}
+
+// ClusterNameChecker checks whether a cluster name is already taken.
+type ClusterNameChecker interface {
+	// ClusterExists checks whether a cluster with the given name exists in an organization.
+	ClusterExists(ctx context.Context, organizationID uint, name string) (bool, error)
+}
+
+// SecretVerifier verifies cloud provider credentials.
+type SecretVerifier interface {
+	// VerifySecret checks that a secret belongs to the cloud provider and that its credentials are valid.
+	VerifySecret(ctx context.Context, organizationID uint, secretID string, cloud string) error
+}
+
+// ProductCatalog provides information about the cloud provider services.
+type ProductCatalog interface {
+	// GetServiceRegions returns the cloud provider regions where the specified service is available.
+	GetServiceRegions(ctx context.Context, cloud string, service string) ([]string, error)
+
+	// GetProductDetails returns details for a single product.
+	GetProductDetails(ctx context.Context, cloud string, service string, region string, productType string) (cloudinfo.ProductDetails, error)
+}
+
+// NetworkServiceFactory creates network services for cloud providers.
+type NetworkServiceFactory interface {
+	// NewNetworkService returns a network service accessing the cloud provider with a secret.
+	NewNetworkService(
+		ctx context.Context,
+		organizationID uint,
+		secretID string,
+		cloud string,
+		region string,
+		resourceGroup string,
+	) (network.Service, error)
+}
+
+// AmazonAccountChecker checks the AWS account a cluster is created in.
+type AmazonAccountChecker interface {
+	// AvailableOnDemandVCPUs returns the number of vCPUs the on-demand standard instance quota
+	// of a region allows to be started in addition to the running instances.
+	AvailableOnDemandVCPUs(ctx context.Context, organizationID uint, secretID string, region string) (int, error)
+
+	// DeniedActions returns the IAM actions the credentials of a secret are not allowed to perform.
+	DeniedActions(ctx context.Context, organizationID uint, secretID string, region string, actions []string) ([]string, error)
+}
+
+// PolicyValidator checks new clusters against organization policies (including quotas).
+type PolicyValidator interface {
+	// ValidateClusterCreate checks a new cluster against the policy of an organization.
+	ValidateClusterCreate(ctx context.Context, organizationID uint, spec clusterpolicy.ClusterSpec) error
+}
+
+// Checker runs the synchronous checks of a cluster creation.
+type Checker struct {
+	clusters ClusterNameChecker
+	secrets  SecretVerifier
+	products ProductCatalog
+	networks NetworkServiceFactory
+	amazon   AmazonAccountChecker
+	policies PolicyValidator
+}
+
+// NewChecker returns a new Checker.
+func NewChecker(
+	clusters ClusterNameChecker,
+	secrets SecretVerifier,
+	products ProductCatalog,
+	networks NetworkServiceFactory,
+	amazon AmazonAccountChecker,
+	policies PolicyValidator,
+) Checker {
+	return Checker{
+		clusters: clusters,
+		secrets:  secrets,
+		products: products,
+		networks: networks,
+		amazon:   amazon,
+		policies: policies,
+	}
+}
+
+// Check runs every check on a cluster creation request and returns all the problems found.
+//
+// Checks are not stopped by failing ones, but checks depending on the cloud credentials
+// (networks, AWS quotas and IAM permissions) are skipped when no valid secret is found.
+func (c Checker) Check(ctx context.Context, request Request) []string {
+	var violations []string
+
+	if request.Name != "" {
+		exists, err := c.clusters.ClusterExists(ctx, request.OrganizationID, request.Name)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("failed to check cluster name: %s", err.Error()))
+		} else if exists {
+			violations = append(violations, fmt.Sprintf("cluster %q already exists", request.Name))
+		}
+	}
+
+	secretID, secretViolations := c.checkSecrets(ctx, request)
+	violations = append(violations, secretViolations...)
+
+	region := request.Cluster.Location
+	vcpus := 0
+	if hasCloudinfo(request.Cluster.Cloud) {
+		var productViolations []string
+
+		region, vcpus, productViolations = c.checkProducts(ctx, request.Cluster)
+		violations = append(violations, productViolations...)
+	}
+
+	if secretID != "" && region != "" {
+		violations = append(violations, c.checkNetwork(ctx, request, secretID, region)...)
+	}
+
+	if secretID != "" && region != "" && request.Cluster.Cloud == pkgCluster.Amazon {
+		violations = append(violations, c.checkAmazonAccount(ctx, request, secretID, region, vcpus)...)
+	}
+
+	if err := c.policies.ValidateClusterCreate(ctx, request.OrganizationID, request.Cluster); err != nil {
+		violations = append(violations, unwrapViolations(err)...)
+	}
+
+	return violations
+}
+
+// checkSecrets returns the first valid secret of the request.
+func (c Checker) checkSecrets(ctx context.Context, request Request) (string, []string) {
+	if len(request.SecretIDs) == 0 {
+		return "", []string{"a secret is required"}
+	}
+
+	var violations []string
+
+	for _, secretID := range request.SecretIDs {
+		err := c.secrets.VerifySecret(ctx, request.OrganizationID, secretID, request.Cluster.Cloud)
+		if err == nil {
+			return secretID, nil
+		}
+
+		violations = append(violations, fmt.Sprintf("secret %q is invalid: %s", secretID, err.Error()))
+	}
+
+	return "", violations
+}
+
+// checkProducts returns the region of the cluster, the number of on-demand vCPUs the node pools can scale to
+// and the problems with the requested cloud products.
+func (c Checker) checkProducts(ctx context.Context, spec clusterpolicy.ClusterSpec) (string, int, []string) {
+	var violations []string
+
+	regions, err := c.products.GetServiceRegions(ctx, spec.Cloud, spec.Distribution)
+	if err != nil {
+		return "", 0, []string{fmt.Sprintf("failed to check location: %s", err.Error())}
+	}
+
+	region, ok := findRegion(regions, spec.Location)
+	if !ok {
+		// instance types cannot be checked without a valid region
+		return "", 0, []string{fmt.Sprintf("%s is not available in location %q", spec.Distribution, spec.Location)}
+	}
+
+	vcpus := 0
+
+	for _, nodePool := range spec.NodePools {
+		if nodePool.InstanceType == "" {
+			continue
+		}
+
+		details, err := c.products.GetProductDetails(ctx, spec.Cloud, spec.Distribution, region, nodePool.InstanceType)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf(
+				"instance type %q of node pool %q is not available in region %q",
+				nodePool.InstanceType,
+				nodePool.Name,
+				region,
+			))
+
+			continue
+		}
+
+		if !nodePool.Spot {
+			vcpus += int(math.Ceil(details.CpusPerVm)) * nodePool.MaxNodes
+		}
+	}
+
+	return region, vcpus, violations
+}
+
+// amazonActions are the IAM actions required to create a cluster on AWS by distribution.
+var amazonActions = map[string][]string{
+	pkgCluster.EKS: {
+		"autoscaling:CreateAutoScalingGroup",
+		"autoscaling:CreateLaunchConfiguration",
+		"cloudformation:CreateStack",
+		"cloudformation:DescribeStacks",
+		"ec2:CreateSecurityGroup",
+		"ec2:CreateSubnet",
+		"ec2:CreateVpc",
+		"ec2:RunInstances",
+		"eks:CreateCluster",
+		"eks:DescribeCluster",
+		"iam:CreateInstanceProfile",
+		"iam:CreateRole",
+		"iam:PassRole",
+	},
+	pkgCluster.PKE: {
+		"autoscaling:CreateAutoScalingGroup",
+		"autoscaling:CreateLaunchConfiguration",
+		"cloudformation:CreateStack",
+		"cloudformation:DescribeStacks",
+		"ec2:AllocateAddress",
+		"ec2:CreateSecurityGroup",
+		"ec2:CreateSubnet",
+		"ec2:CreateVpc",
+		"ec2:RunInstances",
+		"elasticloadbalancing:CreateLoadBalancer",
+		"iam:CreateInstanceProfile",
+		"iam:CreateRole",
+		"iam:CreateUser",
+		"iam:PassRole",
+	},
+}
+
+// checkAmazonAccount checks the on-demand vCPU quota of the region and the IAM permissions of the secret.
+func (c Checker) checkAmazonAccount(ctx context.Context, request Request, secretID string, region string, vcpus int) []string {
+	actions, ok := amazonActions[request.Cluster.Distribution]
+	if !ok {
+		return nil
+	}
+
+	var violations []string
+
+	if vcpus > 0 {
+		available, err := c.amazon.AvailableOnDemandVCPUs(ctx, request.OrganizationID, secretID, region)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("failed to check vCPU quota: %s", err.Error()))
+		} else if vcpus > available {
+			violations = append(violations, fmt.Sprintf(
+				"the on-demand node pools can scale up to %d vCPUs, but the vCPU quota of region %q allows only %d more",
+				vcpus,
+				region,
+				available,
+			))
+		}
+	}
+
+	denied, err := c.amazon.DeniedActions(ctx, request.OrganizationID, secretID, region, actions)
+	if err != nil {
+		violations = append(violations, fmt.Sprintf("failed to check IAM permissions: %s", err.Error()))
+	} else if len(denied) > 0 {
+		violations = append(violations, fmt.Sprintf("secret %q is not allowed to perform %s", secretID, strings.Join(denied, ", ")))
+	}
+
+	return violations
+}
+
+func (c Checker) checkNetwork(ctx context.Context, request Request, secretID string, region string) []string {
+	if request.NetworkID == "" {
+		if len(request.SubnetIDs) > 0 {
+			return []string{"subnets can only be referenced together with an existing network"}
+		}
+
+		return nil
+	}
+
+	service, err := c.networks.NewNetworkService(
+		ctx,
+		request.OrganizationID,
+		secretID,
+		request.Cluster.Cloud,
+		region,
+		request.ResourceGroup,
+	)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to check network: %s", err.Error())}
+	}
+
+	networks, err := service.ListNetworks()
+	if err != nil {
+		return []string{fmt.Sprintf("failed to check network: %s", err.Error())}
+	}
+
+	networkID, ok := findNetwork(networks, request.NetworkID)
+	if !ok {
+		return []string{fmt.Sprintf("network %q does not exist", request.NetworkID)}
+	}
+
+	if len(request.SubnetIDs) == 0 {
+		return nil
+	}
+
+	subnets, err := service.ListSubnets(networkID)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to check subnets: %s", err.Error())}
+	}
+
+	var violations []string
+
+	for _, subnetID := range request.SubnetIDs {
+		if !hasSubnet(subnets, subnetID) {
+			violations = append(violations, fmt.Sprintf("subnet %q does not exist in network %q", subnetID, request.NetworkID))
+		}
+	}
+
+	return violations
+}
+
+// hasCloudinfo tells whether cloudinfo knows about the services of a cloud provider.
+func hasCloudinfo(cloud string) bool {
+	switch cloud {
+	case pkgCluster.Alibaba, pkgCluster.Amazon, pkgCluster.Azure, pkgCluster.Google, pkgCluster.Oracle:
+		return true
+	default:
+		return false
+	}
+}
+
+// findRegion returns the region of a location.
+// Locations can either be regions or zones (eg. us-central1-a is in us-central1).
+func findRegion(regions []string, location string) (string, bool) {
+	for _, region := range regions {
+		if location == region || strings.HasPrefix(location, region+"-") {
+			return region, true
+		}
+	}
+
+	return "", false
+}
+
+// findNetwork returns the ID of a network referenced either by ID or name.
+func findNetwork(networks []network.Network, reference string) (string, bool) {
+	for _, n := range networks {
+		if n.ID() == reference || n.Name() == reference {
+			return n.ID(), true
+		}
+	}
+
+	return "", false
+}
+
+func hasSubnet(subnets []network.Subnet, reference string) bool {
+	for _, subnet := range subnets {
+		if subnet.ID() == reference || subnet.Name() == reference {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unwrapViolations is a helper func to unwrap violations from a validation error
+func unwrapViolations(err error) []string {
+	var verr interface {
+		Violations() []string
+	}
+
+	if errors.As(err, &verr) {
+		return verr.Violations()
+	}
+
+	return []string{err.Error()}
+}
@@ -0,0 +1,305 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpreflight
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/.gen/cloudinfo"
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/internal/network"
+)
+
+type fakeClusterNameChecker map[string]bool
+
+func (c fakeClusterNameChecker) ClusterExists(_ context.Context, _ uint, name string) (bool, error) {
+	return c[name], nil
+}
+
+type fakeSecretVerifier map[string]error
+
+func (v fakeSecretVerifier) VerifySecret(_ context.Context, _ uint, secretID string, _ string) error {
+	err, ok := v[secretID]
+	if !ok {
+		return errors.New("secret not found")
+	}
+
+	return err
+}
+
+type fakeProductCatalog struct {
+	regions       []string
+	instanceTypes []string
+	cpus          float64
+}
+
+func (c fakeProductCatalog) GetServiceRegions(_ context.Context, _ string, _ string) ([]string, error) {
+	return c.regions, nil
+}
+
+func (c fakeProductCatalog) GetProductDetails(_ context.Context, _ string, _ string, _ string, productType string) (cloudinfo.ProductDetails, error) {
+	for _, instanceType := range c.instanceTypes {
+		if instanceType == productType {
+			return cloudinfo.ProductDetails{Type: productType, CpusPerVm: c.cpus}, nil
+		}
+	}
+
+	return cloudinfo.ProductDetails{}, errors.New("no product info found")
+}
+
+type fakeNetwork struct {
+	id      string
+	subnets []string
+}
+
+func (n fakeNetwork) CIDRs() []string { return nil }
+func (n fakeNetwork) ID() string      { return n.id }
+func (n fakeNetwork) Name() string    { return n.id }
+
+type fakeSubnet string
+
+func (s fakeSubnet) CIDRs() []string  { return nil }
+func (s fakeSubnet) ID() string       { return string(s) }
+func (s fakeSubnet) Location() string { return "" }
+func (s fakeSubnet) Name() string     { return string(s) }
+
+type fakeNetworkService []fakeNetwork
+
+func (s fakeNetworkService) ListNetworks() ([]network.Network, error) {
+	networks := make([]network.Network, 0, len(s))
+	for _, n := range s {
+		networks = append(networks, n)
+	}
+
+	return networks, nil
+}
+
+func (s fakeNetworkService) ListRouteTables(_ string) ([]network.RouteTable, error) {
+	return nil, nil
+}
+
+func (s fakeNetworkService) ListSubnets(networkID string) ([]network.Subnet, error) {
+	var subnets []network.Subnet
+
+	for _, n := range s {
+		if n.id != networkID {
+			continue
+		}
+
+		for _, subnet := range n.subnets {
+			subnets = append(subnets, fakeSubnet(subnet))
+		}
+	}
+
+	return subnets, nil
+}
+
+type fakeNetworkServiceFactory struct {
+	service fakeNetworkService
+	region  string
+}
+
+func (f *fakeNetworkServiceFactory) NewNetworkService(_ context.Context, _ uint, _ string, _ string, region string, _ string) (network.Service, error) {
+	f.region = region
+
+	return f.service, nil
+}
+
+type fakeAmazonAccountChecker struct {
+	availableVCPUs int
+	deniedActions  []string
+}
+
+func (c fakeAmazonAccountChecker) AvailableOnDemandVCPUs(_ context.Context, _ uint, _ string, _ string) (int, error) {
+	return c.availableVCPUs, nil
+}
+
+func (c fakeAmazonAccountChecker) DeniedActions(_ context.Context, _ uint, _ string, _ string, _ []string) ([]string, error) {
+	return c.deniedActions, nil
+}
+
+type fakePolicyValidator []string
+
+func (v fakePolicyValidator) ValidateClusterCreate(_ context.Context, _ uint, _ clusterpolicy.ClusterSpec) error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return cluster.NewValidationError("cluster violates organization policy", v)
+}
+
+func TestChecker_Check(t *testing.T) {
+	products := fakeProductCatalog{
+		regions:       []string{"us-central1", "europe-west1"},
+		instanceTypes: []string{"n1-standard-2"},
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		networks := &fakeNetworkServiceFactory{
+			service: fakeNetworkService{{id: "vpc-1", subnets: []string{"subnet-1"}}},
+		}
+
+		checker := NewChecker(
+			fakeClusterNameChecker{},
+			fakeSecretVerifier{"secret-1": errors.New("invalid credentials"), "secret-2": nil},
+			products,
+			networks,
+			fakeAmazonAccountChecker{},
+			fakePolicyValidator{},
+		)
+
+		violations := checker.Check(context.Background(), Request{
+			OrganizationID: 1,
+			Name:           "cluster",
+			SecretIDs:      []string{"secret-1", "secret-2"},
+			Cluster: clusterpolicy.ClusterSpec{
+				Cloud:        "google",
+				Distribution: "gke",
+				Location:     "us-central1-a",
+				NodePools: []clusterpolicy.NodePoolSpec{
+					{Name: "pool0", InstanceType: "n1-standard-2"},
+				},
+			},
+			NetworkID: "vpc-1",
+			SubnetIDs: []string{"subnet-1"},
+		})
+
+		assert.Empty(t, violations)
+		assert.Equal(t, "us-central1", networks.region)
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		networks := &fakeNetworkServiceFactory{
+			service: fakeNetworkService{{id: "vpc-1", subnets: []string{"subnet-1"}}},
+		}
+
+		checker := NewChecker(
+			fakeClusterNameChecker{"cluster": true},
+			fakeSecretVerifier{"secret-1": nil},
+			products,
+			networks,
+			fakeAmazonAccountChecker{},
+			fakePolicyValidator{"the organization policy allows at most 2 clusters"},
+		)
+
+		violations := checker.Check(context.Background(), Request{
+			OrganizationID: 1,
+			Name:           "cluster",
+			SecretIDs:      []string{"secret-1"},
+			Cluster: clusterpolicy.ClusterSpec{
+				Cloud:        "google",
+				Distribution: "gke",
+				Location:     "europe-west1",
+				NodePools: []clusterpolicy.NodePoolSpec{
+					{Name: "pool0", InstanceType: "n1-standard-2"},
+					{Name: "pool1", InstanceType: "n1-highmem-64"},
+				},
+			},
+			NetworkID: "vpc-1",
+			SubnetIDs: []string{"subnet-1", "subnet-2"},
+		})
+
+		assert.Equal(
+			t,
+			[]string{
+				`cluster "cluster" already exists`,
+				`instance type "n1-highmem-64" of node pool "pool1" is not available in region "europe-west1"`,
+				`subnet "subnet-2" does not exist in network "vpc-1"`,
+				"the organization policy allows at most 2 clusters",
+			},
+			violations,
+		)
+	})
+
+	t.Run("InvalidSecretAndLocation", func(t *testing.T) {
+		checker := NewChecker(
+			fakeClusterNameChecker{},
+			fakeSecretVerifier{},
+			products,
+			&fakeNetworkServiceFactory{},
+			fakeAmazonAccountChecker{},
+			fakePolicyValidator{},
+		)
+
+		violations := checker.Check(context.Background(), Request{
+			OrganizationID: 1,
+			Name:           "cluster",
+			SecretIDs:      []string{"secret-1"},
+			Cluster: clusterpolicy.ClusterSpec{
+				Cloud:        "google",
+				Distribution: "gke",
+				Location:     "asia-east1",
+			},
+			NetworkID: "vpc-1",
+		})
+
+		assert.Equal(
+			t,
+			[]string{
+				`secret "secret-1" is invalid: secret not found`,
+				`gke is not available in location "asia-east1"`,
+			},
+			violations,
+		)
+	})
+
+	t.Run("AmazonAccount", func(t *testing.T) {
+		checker := NewChecker(
+			fakeClusterNameChecker{},
+			fakeSecretVerifier{"secret-1": nil},
+			fakeProductCatalog{
+				regions:       []string{"eu-west-1"},
+				instanceTypes: []string{"m5.xlarge"},
+				cpus:          4,
+			},
+			&fakeNetworkServiceFactory{},
+			fakeAmazonAccountChecker{
+				availableVCPUs: 32,
+				deniedActions:  []string{"eks:CreateCluster"},
+			},
+			fakePolicyValidator{},
+		)
+
+		violations := checker.Check(context.Background(), Request{
+			OrganizationID: 1,
+			Name:           "cluster",
+			SecretIDs:      []string{"secret-1"},
+			Cluster: clusterpolicy.ClusterSpec{
+				Cloud:        "amazon",
+				Distribution: "eks",
+				Location:     "eu-west-1",
+				NodePools: []clusterpolicy.NodePoolSpec{
+					{Name: "pool0", InstanceType: "m5.xlarge", MaxNodes: 5},
+					{Name: "pool1", InstanceType: "m5.xlarge", MaxNodes: 5},
+					{Name: "pool2", InstanceType: "m5.xlarge", MaxNodes: 10, Spot: true},
+				},
+			},
+		})
+
+		assert.Equal(
+			t,
+			[]string{
+				`the on-demand node pools can scale up to 40 vCPUs, but the vCPU quota of region "eu-west-1" allows only 32 more`,
+				`secret "secret-1" is not allowed to perform eks:CreateCluster`,
+			},
+			violations,
+		)
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterpreflight
+
+import (
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// RequestFromCreateRequest returns the checked parts of a (legacy) cluster creation request.
+func RequestFromCreateRequest(organizationID uint, request pkgCluster.CreateClusterRequest) (Request, error) {
+	spec, err := clusterpolicy.SpecFromCreateRequest(request)
+	if err != nil {
+		return Request{}, err
+	}
+
+	r := Request{
+		OrganizationID: organizationID,
+		Name:           request.Name,
+		SecretIDs:      request.SecretIds,
+		Cluster:        spec,
+	}
+
+	if request.SecretId != "" {
+		r.SecretIDs = append([]string{request.SecretId}, r.SecretIDs...)
+	}
+
+	if request.Properties == nil {
+		return r, nil
+	}
+
+	switch {
+	case request.Properties.CreateClusterEKS != nil:
+		eks := request.Properties.CreateClusterEKS
+
+		if eks.Vpc != nil {
+			r.NetworkID = eks.Vpc.VpcId
+		}
+
+		// subnets without ID are created with the cluster
+		for _, subnet := range eks.Subnets {
+			if subnet != nil && subnet.SubnetId != "" {
+				r.SubnetIDs = appendUnique(r.SubnetIDs, subnet.SubnetId)
+			}
+		}
+
+		for _, nodePool := range eks.NodePools {
+			if nodePool != nil && nodePool.Subnet != nil && nodePool.Subnet.SubnetId != "" {
+				r.SubnetIDs = appendUnique(r.SubnetIDs, nodePool.Subnet.SubnetId)
+			}
+		}
+
+	case request.Properties.CreateClusterGKE != nil:
+		gke := request.Properties.CreateClusterGKE
+
+		r.NetworkID = gke.Vpc
+		if gke.Subnet != "" {
+			r.SubnetIDs = []string{gke.Subnet}
+		}
+
+	case request.Properties.CreateClusterAKS != nil:
+		r.ResourceGroup = request.Properties.CreateClusterAKS.ResourceGroup
+	}
+
+	return r, nil
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
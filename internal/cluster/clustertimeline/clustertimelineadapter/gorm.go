@@ -0,0 +1,62 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustertimelineadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustertimeline"
+)
+
+// GormStatusHistoryStore reads the cluster status history from a database using Gorm.
+type GormStatusHistoryStore struct {
+	db *gorm.DB
+}
+
+// NewGormStatusHistoryStore returns a new GormStatusHistoryStore.
+func NewGormStatusHistoryStore(db *gorm.DB) GormStatusHistoryStore {
+	return GormStatusHistoryStore{
+		db: db,
+	}
+}
+
+// ListStatusTransitions returns the status transitions of a cluster in chronological order.
+func (s GormStatusHistoryStore) ListStatusTransitions(ctx context.Context, clusterID uint) ([]clustertimeline.StatusTransition, error) {
+	var models []clustermodel.StatusHistoryModel
+
+	err := s.db.Where("cluster_id = ?", clusterID).Order("created_at, id").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to find status history", "clusterId", clusterID)
+	}
+
+	transitions := make([]clustertimeline.StatusTransition, 0, len(models))
+	for _, model := range models {
+		transitions = append(transitions, clustertimeline.StatusTransition{
+			ClusterID:         model.ClusterID,
+			Timestamp:         model.CreatedAt,
+			FromStatus:        model.FromStatus,
+			FromStatusMessage: model.FromStatusMessage,
+			ToStatus:          model.ToStatus,
+			ToStatusMessage:   model.ToStatusMessage,
+			UserID:            model.UserID,
+			ProcessID:         model.ProcessID,
+		})
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,151 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustertimeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/process"
+)
+
+// Timeline entry types.
+const (
+	EntryTypeStatusChange    = "statusChange"
+	EntryTypeProcessStarted  = "processStarted"
+	EntryTypeProcessEvent    = "processEvent"
+	EntryTypeProcessFinished = "processFinished"
+)
+
+// StatusTransition is a recorded change of the status of a cluster.
+type StatusTransition struct {
+	ClusterID         uint
+	Timestamp         time.Time
+	FromStatus        string
+	FromStatusMessage string
+	ToStatus          string
+	ToStatusMessage   string
+	UserID            uint
+	ProcessID         string
+}
+
+// Entry is a single item of a cluster timeline.
+type Entry struct {
+	Timestamp      time.Time
+	Type           string
+	Status         string
+	PreviousStatus string
+	Message        string
+	UserID         uint
+	ProcessID      string
+	ProcessType    string
+}
+
+// StatusHistoryStore lists the recorded status transitions of clusters.
+type StatusHistoryStore interface {
+	// ListStatusTransitions returns the status transitions of a cluster in chronological order.
+	ListStatusTransitions(ctx context.Context, clusterID uint) ([]StatusTransition, error)
+}
+
+// ProcessStore lists pipeline processes.
+type ProcessStore interface {
+	// ListProcesses lists the processes matching the query together with their events.
+	ListProcesses(ctx context.Context, query process.Process) ([]process.Process, error)
+}
+
+// Service assembles cluster timelines.
+type Service struct {
+	history   StatusHistoryStore
+	processes ProcessStore
+}
+
+// NewService returns a new Service.
+func NewService(history StatusHistoryStore, processes ProcessStore) Service {
+	return Service{
+		history:   history,
+		processes: processes,
+	}
+}
+
+// GetClusterTimeline returns the status transitions of a cluster merged with the processes
+// (and their events) that were running against the cluster, in chronological order.
+func (s Service) GetClusterTimeline(ctx context.Context, organizationID uint, clusterID uint) ([]Entry, error) {
+	transitions, err := s.history.ListStatusTransitions(ctx, clusterID)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list status transitions", "clusterId", clusterID)
+	}
+
+	processes, err := s.processes.ListProcesses(ctx, process.Process{
+		OrgId:      int32(organizationID),
+		ResourceId: fmt.Sprint(clusterID),
+	})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list processes", "clusterId", clusterID)
+	}
+
+	entries := make([]Entry, 0, len(transitions)+len(processes))
+
+	for _, transition := range transitions {
+		entries = append(entries, Entry{
+			Timestamp:      transition.Timestamp,
+			Type:           EntryTypeStatusChange,
+			Status:         transition.ToStatus,
+			PreviousStatus: transition.FromStatus,
+			Message:        transition.ToStatusMessage,
+			UserID:         transition.UserID,
+			ProcessID:      transition.ProcessID,
+		})
+	}
+
+	for _, p := range processes {
+		entries = append(entries, Entry{
+			Timestamp:   p.StartedAt,
+			Type:        EntryTypeProcessStarted,
+			ProcessID:   p.Id,
+			ProcessType: p.Type,
+		})
+
+		for _, event := range p.Events {
+			entries = append(entries, Entry{
+				Timestamp:   event.Timestamp,
+				Type:        EntryTypeProcessEvent,
+				Status:      string(event.Status),
+				Message:     event.Log,
+				ProcessID:   p.Id,
+				ProcessType: event.Type,
+			})
+		}
+
+		if p.FinishedAt != nil {
+			entries = append(entries, Entry{
+				Timestamp:   *p.FinishedAt,
+				Type:        EntryTypeProcessFinished,
+				Status:      string(p.Status),
+				Message:     p.Log,
+				ProcessID:   p.Id,
+				ProcessType: p.Type,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,109 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clustertimeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/process"
+)
+
+type fakeStatusHistoryStore map[uint][]StatusTransition
+
+func (s fakeStatusHistoryStore) ListStatusTransitions(_ context.Context, clusterID uint) ([]StatusTransition, error) {
+	return s[clusterID], nil
+}
+
+type fakeProcessStore struct {
+	processes []process.Process
+	query     process.Process
+}
+
+func (s *fakeProcessStore) ListProcesses(_ context.Context, query process.Process) ([]process.Process, error) {
+	s.query = query
+
+	return s.processes, nil
+}
+
+func TestService_GetClusterTimeline(t *testing.T) {
+	start := time.Date(2020, time.May, 19, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+	finishedAt := at(4)
+
+	history := fakeStatusHistoryStore{
+		1: {
+			{
+				ClusterID:       1,
+				Timestamp:       at(1),
+				FromStatus:      "RUNNING",
+				ToStatus:        "UPDATING",
+				ToStatusMessage: "updating cluster",
+				UserID:          7,
+				ProcessID:       "update-1",
+			},
+			{
+				ClusterID:       1,
+				Timestamp:       at(5),
+				FromStatus:      "UPDATING",
+				ToStatus:        "ERROR",
+				ToStatusMessage: "failed to update node pool",
+				ProcessID:       "update-1",
+			},
+		},
+	}
+	processes := &fakeProcessStore{
+		processes: []process.Process{
+			{
+				Id:         "update-1",
+				Type:       "eks-update-cluster",
+				Status:     process.ProcessStatus("failed"),
+				Log:        "node pool update failed",
+				StartedAt:  at(0),
+				FinishedAt: &finishedAt,
+				Events: []process.ProcessEvent{
+					{
+						ProcessId: "update-1",
+						Type:      "eks-update-node-group",
+						Status:    process.ProcessStatus("failed"),
+						Log:       "timeout",
+						Timestamp: at(3),
+					},
+				},
+			},
+		},
+	}
+
+	service := NewService(history, processes)
+
+	entries, err := service.GetClusterTimeline(context.Background(), 2, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, process.Process{OrgId: 2, ResourceId: "1"}, processes.query)
+
+	expected := []Entry{
+		{Timestamp: at(0), Type: EntryTypeProcessStarted, ProcessID: "update-1", ProcessType: "eks-update-cluster"},
+		{Timestamp: at(1), Type: EntryTypeStatusChange, Status: "UPDATING", PreviousStatus: "RUNNING", Message: "updating cluster", UserID: 7, ProcessID: "update-1"},
+		{Timestamp: at(3), Type: EntryTypeProcessEvent, Status: "failed", Message: "timeout", ProcessID: "update-1", ProcessType: "eks-update-node-group"},
+		{Timestamp: at(4), Type: EntryTypeProcessFinished, Status: "failed", Message: "node pool update failed", ProcessID: "update-1", ProcessType: "eks-update-cluster"},
+		{Timestamp: at(5), Type: EntryTypeStatusChange, Status: "ERROR", PreviousStatus: "UPDATING", Message: "failed to update node pool", ProcessID: "update-1"},
+	}
+	assert.Equal(t, expected, entries)
+}
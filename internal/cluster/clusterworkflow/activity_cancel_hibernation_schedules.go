@@ -0,0 +1,65 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterworkflow
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+const CancelHibernationSchedulesActivityName = "cancel-hibernation-schedules"
+
+// CancelHibernationSchedulesActivity terminates the hibernation cron workflows of a deleted cluster.
+type CancelHibernationSchedulesActivity struct {
+	workflowClient client.Client
+	workflowNames  []string
+}
+
+// MakeCancelHibernationSchedulesActivity returns a new CancelHibernationSchedulesActivity
+// terminating the schedules of the given hibernate and resume workflows.
+func MakeCancelHibernationSchedulesActivity(workflowClient client.Client, workflowNames ...string) CancelHibernationSchedulesActivity {
+	return CancelHibernationSchedulesActivity{
+		workflowClient: workflowClient,
+		workflowNames:  workflowNames,
+	}
+}
+
+type CancelHibernationSchedulesActivityInput struct {
+	ClusterID uint
+}
+
+func (a CancelHibernationSchedulesActivity) Execute(ctx context.Context, input CancelHibernationSchedulesActivityInput) error {
+	for _, workflowName := range a.workflowNames {
+		workflowID := cluster.HibernationScheduleWorkflowID(workflowName, input.ClusterID)
+
+		err := a.workflowClient.TerminateWorkflow(ctx, workflowID, "", "cluster deleted", nil)
+		if err != nil && !isEntityNotExistsError(err) {
+			return errors.WrapIfWithDetails(err, "failed to terminate hibernation schedule", "workflowId", workflowID)
+		}
+	}
+
+	return nil
+}
+
+func isEntityNotExistsError(err error) bool {
+	var ene *shared.EntityNotExistsError
+
+	return errors.As(err, &ene)
+}
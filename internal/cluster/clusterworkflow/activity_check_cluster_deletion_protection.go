@@ -0,0 +1,52 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package clusterworkflow
+
+import (
+	"context"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cadence"
+)
+
+const CheckClusterDeletionProtectionActivityName = "check-cluster-deletion-protection"
+
+// CheckClusterDeletionProtectionActivity fails if the deletion protection of a cluster is enabled.
+type CheckClusterDeletionProtectionActivity struct {
+	clusters cluster.Store
+}
+
+// MakeCheckClusterDeletionProtectionActivity returns a new CheckClusterDeletionProtectionActivity.
+func MakeCheckClusterDeletionProtectionActivity(clusters cluster.Store) CheckClusterDeletionProtectionActivity {
+	return CheckClusterDeletionProtectionActivity{
+		clusters: clusters,
+	}
+}
+
+type CheckClusterDeletionProtectionActivityInput struct {
+	ClusterID uint
+}
+
+func (a CheckClusterDeletionProtectionActivity) Execute(ctx context.Context, input CheckClusterDeletionProtectionActivityInput) error {
+	c, err := a.clusters.GetCluster(ctx, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	if err := cluster.CheckDeletionProtection(c); err != nil {
+		return cadence.NewClientError(err)
+	}
+
+	return nil
+}
@@ -17,6 +17,8 @@ package clusterworkflow
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/pkg/cadence"
 	"github.com/banzaicloud/pipeline/pkg/kubernetes/custom/npls"
@@ -53,9 +55,32 @@ func (a CreateNodePoolLabelSetActivity) Execute(ctx context.Context, input Creat
 
 	manager := npls.NewManager(client, a.namespace)
 
-	err = manager.SyncOne(input.RawNodePool.GetName(), input.RawNodePool.GetLabels())
-	if err != nil {
-		return err
+	// Labels are left untouched when only taints are present in the descriptor (and vice versa),
+	// so that node pool updates can change one without the other.
+	_, hasTaints := input.RawNodePool["taints"]
+	if _, ok := input.RawNodePool["labels"]; ok || !hasTaints {
+		err = manager.SyncOne(input.RawNodePool.GetName(), input.RawNodePool.GetLabels())
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasTaints {
+		taints := input.RawNodePool.GetTaints()
+
+		nodeTaints := make([]corev1.Taint, 0, len(taints))
+		for _, taint := range taints {
+			nodeTaints = append(nodeTaints, corev1.Taint{
+				Key:    taint.Key,
+				Value:  taint.Value,
+				Effect: corev1.TaintEffect(taint.Effect),
+			})
+		}
+
+		err = manager.SyncTaints(input.RawNodePool.GetName(), nodeTaints)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
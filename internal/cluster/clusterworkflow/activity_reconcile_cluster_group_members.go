@@ -0,0 +1,41 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterworkflow
+
+import "context"
+
+const ReconcileClusterGroupMembersActivityName = "reconcile-cluster-group-members"
+
+type ReconcileClusterGroupMembersActivity struct {
+	clusterGroupManager ClusterGroupMembershipReconciler
+}
+
+type ClusterGroupMembershipReconciler interface {
+	ReconcileClusterMembership(ctx context.Context, clusterID uint) error
+}
+
+func MakeReconcileClusterGroupMembersActivity(clusterGroupManager ClusterGroupMembershipReconciler) ReconcileClusterGroupMembersActivity {
+	return ReconcileClusterGroupMembersActivity{
+		clusterGroupManager: clusterGroupManager,
+	}
+}
+
+type ReconcileClusterGroupMembersActivityInput struct {
+	ClusterID uint
+}
+
+func (a ReconcileClusterGroupMembersActivity) Execute(ctx context.Context, input ReconcileClusterGroupMembersActivityInput) error {
+	return a.clusterGroupManager.ReconcileClusterMembership(ctx, input.ClusterID)
+}
@@ -18,10 +18,12 @@ import (
 	"context"
 	"time"
 
+	"go.uber.org/cadence/activity"
 	"go.uber.org/cadence/workflow"
 
 	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/pkg/cadence"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
 )
 
 const SetClusterStatusActivityName = "set-cluster-status"
@@ -43,6 +45,8 @@ type SetClusterStatusActivityInput struct {
 }
 
 func (a SetClusterStatusActivity) Execute(ctx context.Context, input SetClusterStatusActivityInput) error {
+	ctx = ctxutil.WithProcessID(ctx, activity.GetInfo(ctx).WorkflowExecution.ID)
+
 	err := a.store.SetStatus(ctx, input.ClusterID, input.Status, input.StatusMessage)
 	if err != nil {
 		return cadence.WrapClientError(err)
@@ -76,7 +76,8 @@ func CreateNodePoolWorkflow(ctx workflow.Context, input CreateNodePoolWorkflowIn
 		}
 	}
 
-	{
+	// The cluster status is managed by the parent workflow (eg. cluster apply)
+	if workflow.GetInfo(_ctx).ParentWorkflowExecution == nil {
 		input := SetClusterStatusActivityInput{
 			ClusterID:     input.ClusterID,
 			Status:        cluster.Running,
@@ -30,6 +30,22 @@ type DeleteClusterWorkflowInput struct {
 }
 
 func DeleteClusterWorkflow(ctx workflow.Context, input DeleteClusterWorkflowInput) error {
+	{
+		ctx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			ScheduleToStartTimeout: 5 * time.Minute,
+			StartToCloseTimeout:    time.Minute,
+		})
+
+		// protected clusters must be left untouched (including their cluster group membership)
+		activityInput := CheckClusterDeletionProtectionActivityInput{
+			ClusterID: input.ClusterID,
+		}
+		err := workflow.ExecuteActivity(ctx, CheckClusterDeletionProtectionActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+
 	{
 		ctx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
 			ScheduleToStartTimeout: 5 * time.Minute,
@@ -63,5 +79,21 @@ func DeleteClusterWorkflow(ctx workflow.Context, input DeleteClusterWorkflowInpu
 		}
 	}
 
+	{
+		ctx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			ScheduleToStartTimeout: 5 * time.Minute,
+			StartToCloseTimeout:    time.Minute,
+		})
+
+		// the cluster is gone, so its scheduled hibernations must not fire anymore
+		activityInput := CancelHibernationSchedulesActivityInput{
+			ClusterID: input.ClusterID,
+		}
+		err := workflow.ExecuteActivity(ctx, CancelHibernationSchedulesActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
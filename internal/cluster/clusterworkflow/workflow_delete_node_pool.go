@@ -74,7 +74,8 @@ func DeleteNodePoolWorkflow(ctx workflow.Context, input DeleteNodePoolWorkflowIn
 		}
 	}
 
-	{
+	// The cluster status is managed by the parent workflow (eg. cluster apply)
+	if workflow.GetInfo(_ctx).ParentWorkflowExecution == nil {
 		input := SetClusterStatusActivityInput{
 			ClusterID:     input.ClusterID,
 			Status:        cluster.Running,
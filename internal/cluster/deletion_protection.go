@@ -0,0 +1,72 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cluster
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+)
+
+// DeletionProtectedError is returned if a cluster cannot be deleted because its deletion protection is enabled.
+type DeletionProtectedError struct {
+	OrganizationID uint
+	ClusterID      uint
+	ClusterName    string
+}
+
+// Error implements the error interface.
+func (DeletionProtectedError) Error() string {
+	return "cluster is protected from deletion"
+}
+
+// Details returns error details.
+func (e DeletionProtectedError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ClusterID, "clusterName", e.ClusterName, "orgId", e.OrganizationID}
+}
+
+// Conflict tells a client that this error is related to a conflicting request.
+// Can be used to translate the error to status codes for example.
+func (DeletionProtectedError) Conflict() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (DeletionProtectedError) ServiceError() bool {
+	return true
+}
+
+// CheckDeletionProtection returns a DeletionProtectedError if the deletion protection of the cluster is enabled.
+func CheckDeletionProtection(c Cluster) error {
+	if c.DeletionProtection {
+		return errors.WithStack(DeletionProtectedError{
+			OrganizationID: c.OrganizationID,
+			ClusterID:      c.ID,
+			ClusterName:    c.Name,
+		})
+	}
+
+	return nil
+}
+
+// SetDeletionProtection enables or disables the deletion protection of a cluster.
+func (s service) SetDeletionProtection(ctx context.Context, clusterID uint, enabled bool) error {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	return s.clusters.SetDeletionProtection(ctx, cluster.ID, enabled)
+}
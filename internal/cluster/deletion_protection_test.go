@@ -0,0 +1,59 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_DeleteCluster_DeletionProtection(t *testing.T) {
+	ctx := context.Background()
+
+	clusterStore := new(MockStore)
+	clusterStore.On("GetCluster", ctx, uint(1)).Return(Cluster{ID: 1, Name: "prod", OrganizationID: 2, DeletionProtection: true}, nil)
+
+	service := NewService(clusterStore, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	deleted, err := service.DeleteCluster(ctx, Identifier{OrganizationID: 2, ClusterID: 1}, DeleteClusterOptions{Force: true})
+	require.Error(t, err)
+	assert.False(t, deleted)
+
+	var protectedErr DeletionProtectedError
+	require.True(t, errors.As(err, &protectedErr))
+	assert.Equal(t, DeletionProtectedError{OrganizationID: 2, ClusterID: 1, ClusterName: "prod"}, protectedErr)
+
+	// the cluster must not be touched at all
+	clusterStore.AssertExpectations(t)
+	clusterStore.AssertNotCalled(t, "SetStatus")
+}
+
+func TestService_SetDeletionProtection(t *testing.T) {
+	ctx := context.Background()
+
+	clusterStore := new(MockStore)
+	clusterStore.On("GetCluster", ctx, uint(1)).Return(Cluster{ID: 1, DeletionProtection: true}, nil)
+	clusterStore.On("SetDeletionProtection", ctx, uint(1), false).Return(nil)
+
+	service := NewService(clusterStore, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	err := service.SetDeletionProtection(ctx, 1, false)
+	require.NoError(t, err)
+
+	clusterStore.AssertExpectations(t)
+}
@@ -0,0 +1,119 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eks
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// ValidateVersionUpgrade validates that a cluster can be upgraded from the current to the target Kubernetes version.
+//
+// EKS only supports upgrading the control plane by one minor version at a time.
+func ValidateVersionUpgrade(currentVersion string, targetVersion string) error {
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "invalid current Kubernetes version", "kubernetesVersion", currentVersion)
+	}
+
+	target, err := semver.NewVersion(targetVersion)
+	if err != nil {
+		return cluster.NewValidationError(
+			"invalid cluster upgrade request",
+			[]string{fmt.Sprintf("invalid Kubernetes version %q", targetVersion)},
+		)
+	}
+
+	var violations []string
+
+	switch {
+	case target.Major() != current.Major() || target.Minor() < current.Minor():
+		violations = append(violations, fmt.Sprintf("cannot upgrade from %s to %s", currentVersion, targetVersion))
+
+	case target.Minor() == current.Minor():
+		violations = append(violations, fmt.Sprintf("cluster is already running Kubernetes version %s", currentVersion))
+
+	case target.Minor() > current.Minor()+1:
+		violations = append(violations, fmt.Sprintf(
+			"cannot skip minor versions: upgrade to %d.%d first",
+			current.Major(), current.Minor()+1,
+		))
+	}
+
+	if len(violations) > 0 {
+		return cluster.NewValidationError("invalid cluster upgrade request", violations)
+	}
+
+	return nil
+}
+
+// AddonVersions contains the versions of the add-ons deployed to every EKS cluster.
+type AddonVersions struct {
+	KubeProxy string
+	CoreDNS   string
+	CNI       string
+}
+
+// Add-on versions taken from https://docs.aws.amazon.com/eks/latest/userguide/update-cluster.html
+// nolint: gochecknoglobals
+var defaultAddonVersionMap = []struct {
+	constraint *semver.Constraints
+	versions   AddonVersions
+}{
+	{
+		constraintForVersion("1.13"),
+		AddonVersions{
+			KubeProxy: "v1.13.12",
+			CoreDNS:   "v1.2.6",
+			CNI:       "v1.6.1",
+		},
+	},
+	{
+		constraintForVersion("1.14"),
+		AddonVersions{
+			KubeProxy: "v1.14.9",
+			CoreDNS:   "v1.6.6",
+			CNI:       "v1.6.1",
+		},
+	},
+	{
+		constraintForVersion("1.15"),
+		AddonVersions{
+			KubeProxy: "v1.15.11",
+			CoreDNS:   "v1.6.6",
+			CNI:       "v1.6.1",
+		},
+	},
+}
+
+// GetAddonVersions returns the recommended add-on versions for a Kubernetes version.
+func GetAddonVersions(kubernetesVersion string) (AddonVersions, error) {
+	kubeVersion, err := semver.NewVersion(kubernetesVersion)
+	if err != nil {
+		return AddonVersions{}, errors.WrapIfWithDetails(err, "could not create semver from Kubernetes version", "kubernetesVersion", kubernetesVersion)
+	}
+
+	for _, m := range defaultAddonVersionMap {
+		if m.constraint.Check(kubeVersion) {
+			return m.versions, nil
+		}
+	}
+
+	return AddonVersions{}, errors.Errorf("unsupported Kubernetes version %q", kubeVersion)
+}
@@ -0,0 +1,219 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eks
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+func TestValidateVersionUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		valid   bool
+	}{
+		{
+			name:    "next minor",
+			current: "1.14",
+			target:  "1.15",
+			valid:   true,
+		},
+		{
+			name:    "same version",
+			current: "1.14",
+			target:  "1.14",
+		},
+		{
+			name:    "skip minor",
+			current: "1.13",
+			target:  "1.15",
+		},
+		{
+			name:    "downgrade",
+			current: "1.15",
+			target:  "1.14",
+		},
+		{
+			name:    "major",
+			current: "1.15",
+			target:  "2.0",
+		},
+		{
+			name:    "invalid target",
+			current: "1.15",
+			target:  "latest",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateVersionUpgrade(test.current, test.target)
+
+			if test.valid {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var validationErr cluster.ValidationError
+			assert.True(t, errors.As(err, &validationErr))
+		})
+	}
+}
+
+func TestGetAddonVersions(t *testing.T) {
+	versions, err := GetAddonVersions("1.15")
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1.15.11", versions.KubeProxy)
+	assert.Equal(t, "v1.6.6", versions.CoreDNS)
+
+	_, err = GetAddonVersions("1.10")
+	assert.Error(t, err)
+}
+
+type fakeUpgradeClusterStore struct {
+	cluster.Store
+
+	cluster  cluster.Cluster
+	statuses []string
+}
+
+func (s *fakeUpgradeClusterStore) GetCluster(context.Context, uint) (cluster.Cluster, error) {
+	return s.cluster, nil
+}
+
+func (s *fakeUpgradeClusterStore) SetStatus(_ context.Context, _ uint, status string, _ string) error {
+	s.statuses = append(s.statuses, status)
+
+	return nil
+}
+
+type fakeUpgradeVersionStore struct {
+	ClusterStore
+}
+
+func (fakeUpgradeVersionStore) GetKubernetesVersion(context.Context, uint) (string, error) {
+	return "1.14", nil
+}
+
+type fakeUpgradeNodePoolStore struct {
+	NodePoolStore
+
+	nodePools []NodePool
+}
+
+func (s fakeUpgradeNodePoolStore) ListNodePoolNames(context.Context, uint) ([]string, error) {
+	names := make([]string, 0, len(s.nodePools))
+	for _, nodePool := range s.nodePools {
+		names = append(names, nodePool.Name)
+	}
+
+	return names, nil
+}
+
+func (s fakeUpgradeNodePoolStore) GetNodePool(_ context.Context, _ uint, nodePoolName string) (NodePool, error) {
+	for _, nodePool := range s.nodePools {
+		if nodePool.Name == nodePoolName {
+			return nodePool, nil
+		}
+	}
+
+	return NodePool{}, errors.New("node pool not found")
+}
+
+type fakeUpgradeClusterManager struct {
+	ClusterManager
+
+	upgrades []ClusterUpgrade
+	err      error
+}
+
+func (m *fakeUpgradeClusterManager) UpgradeCluster(_ context.Context, _ cluster.Cluster, upgrade ClusterUpgrade) (string, error) {
+	m.upgrades = append(m.upgrades, upgrade)
+
+	return "process", m.err
+}
+
+type fakeUpgradePolicyValidator struct{}
+
+func (fakeUpgradePolicyValidator) ValidateClusterUpgrade(context.Context, uint, string) error {
+	return nil
+}
+
+func newUpgradeTestService(clusterManager *fakeUpgradeClusterManager) (Service, *fakeUpgradeClusterStore) {
+	clusters := &fakeUpgradeClusterStore{
+		cluster: cluster.Cluster{ID: 1, Location: "eu-west-1", Status: cluster.Running, StatusMessage: cluster.RunningMessage},
+	}
+
+	nodePools := fakeUpgradeNodePoolStore{
+		nodePools: []NodePool{
+			{Name: "default", Image: "ami-02dca57ad67c7bf57"},
+			{Name: "gpu", Image: "ami-0123456789abcdef0"},
+		},
+	}
+
+	service := NewService(
+		clusters,
+		fakeUpgradeVersionStore{},
+		clusterManager,
+		nodePools,
+		nil,
+		nil,
+		nil,
+		fakeUpgradePolicyValidator{},
+	)
+
+	return service, clusters
+}
+
+func TestService_UpgradeCluster(t *testing.T) {
+	clusterManager := &fakeUpgradeClusterManager{}
+	service, clusters := newUpgradeTestService(clusterManager)
+
+	processID, err := service.UpgradeCluster(context.Background(), 1, "1.15")
+	require.NoError(t, err)
+
+	assert.Equal(t, "process", processID)
+	assert.Equal(t, []string{cluster.Updating}, clusters.statuses)
+
+	// the node pool with a custom image is left intact
+	assert.Equal(
+		t,
+		[]ClusterUpgrade{{KubernetesVersion: "1.15", NodeImage: "ami-04bf3ca704bd6b643", NodePools: []string{"default"}}},
+		clusterManager.upgrades,
+	)
+}
+
+func TestService_UpgradeCluster_StartFailure(t *testing.T) {
+	clusterManager := &fakeUpgradeClusterManager{err: errors.New("failed to start workflow")}
+	service, clusters := newUpgradeTestService(clusterManager)
+
+	_, err := service.UpgradeCluster(context.Background(), 1, "1.15")
+	require.Error(t, err)
+
+	// the cluster returns to its previous status
+	assert.Equal(t, []string{cluster.Updating, cluster.Running}, clusters.statuses)
+}
@@ -0,0 +1,189 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksadapter
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksworkflow"
+)
+
+type clusterManager struct {
+	workflowClient client.Client
+	enterprise     bool
+}
+
+// NewClusterManager returns a new eks.ClusterManager
+// that manages clusters asynchronously via Cadence workflows.
+func NewClusterManager(workflowClient client.Client, enterprise bool) eks.ClusterManager {
+	return clusterManager{
+		workflowClient: workflowClient,
+		enterprise:     enterprise,
+	}
+}
+
+func (m clusterManager) taskList() string {
+	if m.enterprise {
+		return "pipeline-enterprise"
+	}
+
+	return "pipeline"
+}
+
+func (m clusterManager) UpgradeCluster(ctx context.Context, c cluster.Cluster, upgrade eks.ClusterUpgrade) (string, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     m.taskList(),
+		ExecutionStartToCloseTimeout: 30 * 24 * 60 * time.Minute,
+	}
+
+	nodePools := make([]eksworkflow.UpgradeClusterNodePool, 0, len(upgrade.NodePools))
+	for _, nodePoolName := range upgrade.NodePools {
+		nodePools = append(nodePools, eksworkflow.UpgradeClusterNodePool{
+			Name:      nodePoolName,
+			StackName: generateNodePoolStackName(c.Name, nodePoolName),
+		})
+	}
+
+	input := eksworkflow.UpgradeClusterWorkflowInput{
+		ProviderSecretID: c.SecretID.String(),
+		Region:           c.Location,
+
+		OrganizationID:  c.OrganizationID,
+		ClusterID:       c.ID,
+		ClusterSecretID: c.ConfigSecretID.String(),
+		ClusterName:     c.Name,
+
+		KubernetesVersion: upgrade.KubernetesVersion,
+		NodeImage:         upgrade.NodeImage,
+		NodePools:         nodePools,
+	}
+
+	e, err := m.workflowClient.StartWorkflow(ctx, workflowOptions, eksworkflow.UpgradeClusterWorkflowName, input)
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", eksworkflow.UpgradeClusterWorkflowName)
+	}
+
+	return e.ID, nil
+}
+
+func (m clusterManager) HibernateCluster(ctx context.Context, c cluster.Cluster, options cluster.HibernateClusterOptions) (string, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     m.taskList(),
+		ExecutionStartToCloseTimeout: 24 * time.Hour,
+	}
+
+	input := eksworkflow.HibernateClusterWorkflowInput{
+		OrganizationID: c.OrganizationID,
+		ClusterID:      c.ID,
+		KeepNodePool:   options.KeepNodePool,
+	}
+
+	e, err := m.workflowClient.StartWorkflow(ctx, workflowOptions, eksworkflow.HibernateClusterWorkflowName, input)
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", eksworkflow.HibernateClusterWorkflowName)
+	}
+
+	return e.ID, nil
+}
+
+func (m clusterManager) ResumeCluster(ctx context.Context, c cluster.Cluster) (string, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     m.taskList(),
+		ExecutionStartToCloseTimeout: 24 * time.Hour,
+	}
+
+	input := eksworkflow.ResumeClusterWorkflowInput{
+		OrganizationID: c.OrganizationID,
+		ClusterID:      c.ID,
+	}
+
+	e, err := m.workflowClient.StartWorkflow(ctx, workflowOptions, eksworkflow.ResumeClusterWorkflowName, input)
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", eksworkflow.ResumeClusterWorkflowName)
+	}
+
+	return e.ID, nil
+}
+
+// ScheduleHibernation replaces the cron workflows of a cluster with new ones following the schedule.
+// Empty cron expressions only cancel the corresponding workflow.
+func (m clusterManager) ScheduleHibernation(ctx context.Context, c cluster.Cluster, schedule cluster.HibernationSchedule) error {
+	crons := []struct {
+		workflowName string
+		cronSchedule string
+		input        interface{}
+	}{
+		{
+			workflowName: eksworkflow.HibernateClusterWorkflowName,
+			cronSchedule: schedule.Hibernate,
+			input: eksworkflow.HibernateClusterWorkflowInput{
+				OrganizationID: c.OrganizationID,
+				ClusterID:      c.ID,
+				KeepNodePool:   schedule.KeepNodePool,
+				Scheduled:      true,
+			},
+		},
+		{
+			workflowName: eksworkflow.ResumeClusterWorkflowName,
+			cronSchedule: schedule.Resume,
+			input: eksworkflow.ResumeClusterWorkflowInput{
+				OrganizationID: c.OrganizationID,
+				ClusterID:      c.ID,
+				Scheduled:      true,
+			},
+		},
+	}
+
+	for _, cron := range crons {
+		workflowID := cluster.HibernationScheduleWorkflowID(cron.workflowName, c.ID)
+
+		err := m.workflowClient.TerminateWorkflow(ctx, workflowID, "", "hibernation schedule changed", nil)
+		if err != nil && !isEntityNotExistsError(err) {
+			return errors.WrapIfWithDetails(err, "failed to cancel scheduled workflow", "workflowId", workflowID)
+		}
+
+		if cron.cronSchedule == "" {
+			continue
+		}
+
+		workflowOptions := client.StartWorkflowOptions{
+			ID:                           workflowID,
+			TaskList:                     m.taskList(),
+			ExecutionStartToCloseTimeout: 24 * time.Hour,
+			WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+			CronSchedule:                 cron.cronSchedule,
+		}
+
+		_, err = m.workflowClient.StartWorkflow(ctx, workflowOptions, cron.workflowName, cron.input)
+		if err != nil {
+			return errors.WrapWithDetails(err, "failed to start workflow", "workflow", cron.workflowName)
+		}
+	}
+
+	return nil
+}
+
+func isEntityNotExistsError(err error) bool {
+	var ene *shared.EntityNotExistsError
+
+	return errors.As(err, &ene)
+}
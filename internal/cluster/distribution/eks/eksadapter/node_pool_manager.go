@@ -45,6 +45,7 @@ func (n nodePoolManager) UpdateNodePool(
 	c cluster.Cluster,
 	nodePoolName string,
 	nodePoolUpdate eks.NodePoolUpdate,
+	replaceNodes bool,
 ) (string, error) {
 	taskList := "pipeline"
 	if n.enterprise {
@@ -56,7 +57,24 @@ func (n nodePoolManager) UpdateNodePool(
 		ExecutionStartToCloseTimeout: 30 * 24 * 60 * time.Minute,
 	}
 
-	input := eksworkflow.UpdateNodePoolWorkflowInput{
+	input := NewUpdateNodePoolWorkflowInput(c, nodePoolName, nodePoolUpdate, replaceNodes)
+
+	e, err := n.workflowClient.StartWorkflow(ctx, workflowOptions, eksworkflow.UpdateNodePoolWorkflowName, input)
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", eksworkflow.UpdateNodePoolWorkflowName)
+	}
+
+	return e.ID, nil
+}
+
+// NewUpdateNodePoolWorkflowInput returns the input of an EKS node pool update workflow.
+func NewUpdateNodePoolWorkflowInput(
+	c cluster.Cluster,
+	nodePoolName string,
+	nodePoolUpdate eks.NodePoolUpdate,
+	replaceNodes bool,
+) eksworkflow.UpdateNodePoolWorkflowInput {
+	return eksworkflow.UpdateNodePoolWorkflowInput{
 		ProviderSecretID: c.SecretID.String(),
 		Region:           c.Location,
 
@@ -68,15 +86,22 @@ func (n nodePoolManager) UpdateNodePool(
 		NodePoolName:    nodePoolName,
 		OrganizationID:  c.OrganizationID,
 
-		NodeImage: nodePoolUpdate.Image,
-	}
+		Size:           nodePoolUpdate.Size,
+		Autoscaling:    nodePoolUpdate.Autoscaling,
+		Labels:         nodePoolUpdate.Labels,
+		InstanceType:   nodePoolUpdate.InstanceType,
+		NodeImage:      nodePoolUpdate.Image,
+		SpotPrice:      nodePoolUpdate.SpotPrice,
+		VolumeSize:     nodePoolUpdate.VolumeSize,
+		VolumeType:     nodePoolUpdate.VolumeType,
+		SecurityGroups: nodePoolUpdate.SecurityGroups,
+		Taints:         nodePoolUpdate.Taints,
 
-	e, err := n.workflowClient.StartWorkflow(ctx, workflowOptions, eksworkflow.UpdateNodePoolWorkflowName, input)
-	if err != nil {
-		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", eksworkflow.UpdateNodePoolWorkflowName)
+		ReplaceNodes:   replaceNodes,
+		MaxSurge:       nodePoolUpdate.Options.MaxSurge,
+		MaxUnavailable: nodePoolUpdate.Options.MaxUnavailable,
+		DrainTimeout:   time.Duration(nodePoolUpdate.Options.DrainTimeout) * time.Second,
 	}
-
-	return e.ID, nil
 }
 
 // TODO: this is temporary
@@ -16,10 +16,12 @@ package eksadapter
 
 import (
 	"context"
+	"strings"
 
 	"emperror.dev/errors"
 	"github.com/jinzhu/gorm"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksmodel"
 )
@@ -53,6 +55,7 @@ func (s nodePoolStore) CreateNodePool(
 		NodeMinCount:     nodePool.Autoscaling.MinSize,
 		NodeMaxCount:     nodePool.Autoscaling.MaxSize,
 		Count:            nodePool.Size,
+		Taints:           nodePool.Taints,
 	}
 
 	err := s.db.Save(nodePoolModel).Error
@@ -62,3 +65,181 @@ func (s nodePoolStore) CreateNodePool(
 
 	return nil
 }
+
+func (s nodePoolStore) GetNodePool(_ context.Context, clusterID uint, nodePoolName string) (eks.NodePool, error) {
+	eksCluster, err := s.getEKSCluster(clusterID)
+	if err != nil {
+		return eks.NodePool{}, err
+	}
+
+	var nodePoolModel eksmodel.AmazonNodePoolsModel
+
+	err = s.db.
+		Where(eksmodel.AmazonNodePoolsModel{ClusterID: eksCluster.ID, Name: nodePoolName}).
+		First(&nodePoolModel).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return eks.NodePool{}, errors.WithStack(cluster.NodePoolNotFoundError{
+			ClusterID: clusterID,
+			NodePool:  nodePoolName,
+		})
+	}
+	if err != nil {
+		return eks.NodePool{}, errors.WrapWithDetails(
+			err, "failed to get node pool",
+			"clusterId", clusterID,
+			"nodePoolName", nodePoolName,
+		)
+	}
+
+	return eks.NodePool{
+		Name: nodePoolModel.Name,
+		Size: nodePoolModel.Count,
+		Autoscaling: eks.NodePoolAutoscaling{
+			Enabled: nodePoolModel.Autoscaling,
+			MinSize: nodePoolModel.NodeMinCount,
+			MaxSize: nodePoolModel.NodeMaxCount,
+		},
+		InstanceType:   nodePoolModel.NodeInstanceType,
+		Image:          nodePoolModel.NodeImage,
+		SpotPrice:      nodePoolModel.NodeSpotPrice,
+		Taints:         nodePoolModel.Taints,
+		VolumeSize:     nodePoolModel.NodeVolumeSize,
+		VolumeType:     nodePoolModel.NodeVolumeType,
+		SecurityGroups: splitSecurityGroups(nodePoolModel.NodeSecurityGroups),
+	}, nil
+}
+
+func (s nodePoolStore) ListNodePoolNames(_ context.Context, clusterID uint) ([]string, error) {
+	eksCluster, err := s.getEKSCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodePoolNames []string
+
+	err = s.db.
+		Model(&eksmodel.AmazonNodePoolsModel{}).
+		Where(eksmodel.AmazonNodePoolsModel{ClusterID: eksCluster.ID}).
+		Order("name").
+		Pluck("name", &nodePoolNames).Error
+	if err != nil {
+		return nil, errors.WrapWithDetails(err, "failed to list node pools", "clusterId", clusterID)
+	}
+
+	return nodePoolNames, nil
+}
+
+func (s nodePoolStore) UpdateNodePoolImage(_ context.Context, clusterID uint, nodePoolName string, image string) error {
+	eksCluster, err := s.getEKSCluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.
+		Model(&eksmodel.AmazonNodePoolsModel{}).
+		Where(eksmodel.AmazonNodePoolsModel{ClusterID: eksCluster.ID, Name: nodePoolName}).
+		Update("node_image", image).Error
+	if err != nil {
+		return errors.WrapWithDetails(
+			err, "failed to update node pool image",
+			"clusterId", clusterID,
+			"nodePoolName", nodePoolName,
+		)
+	}
+
+	return nil
+}
+
+func (s nodePoolStore) UpdateNodePool(
+	_ context.Context,
+	clusterID uint,
+	nodePoolName string,
+	nodePoolUpdate eks.NodePoolUpdate,
+) error {
+	eksCluster, err := s.getEKSCluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{})
+
+	if nodePoolUpdate.Autoscaling.Enabled {
+		fields["autoscaling"] = true
+		fields["node_min_count"] = nodePoolUpdate.Autoscaling.MinSize
+		fields["node_max_count"] = nodePoolUpdate.Autoscaling.MaxSize
+	} else if nodePoolUpdate.Size > 0 {
+		fields["autoscaling"] = false
+		fields["node_min_count"] = nodePoolUpdate.Size
+		fields["node_max_count"] = nodePoolUpdate.Size + 1
+		fields["count"] = nodePoolUpdate.Size
+	}
+
+	if nodePoolUpdate.InstanceType != "" {
+		fields["node_instance_type"] = nodePoolUpdate.InstanceType
+	}
+
+	if nodePoolUpdate.Image != "" {
+		fields["node_image"] = nodePoolUpdate.Image
+	}
+
+	if nodePoolUpdate.SpotPrice != "" {
+		fields["node_spot_price"] = nodePoolUpdate.SpotPrice
+	}
+
+	if nodePoolUpdate.VolumeSize > 0 {
+		fields["node_volume_size"] = nodePoolUpdate.VolumeSize
+	}
+
+	if nodePoolUpdate.VolumeType != "" {
+		fields["node_volume_type"] = nodePoolUpdate.VolumeType
+	}
+
+	if len(nodePoolUpdate.SecurityGroups) > 0 {
+		fields["node_security_groups"] = strings.Join(nodePoolUpdate.SecurityGroups, ",")
+	}
+
+	// nil taints are left intact, an empty list removes them
+	if nodePoolUpdate.Taints != nil {
+		fields["taints"] = cluster.Taints(nodePoolUpdate.Taints)
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	err = s.db.
+		Model(&eksmodel.AmazonNodePoolsModel{}).
+		Where(eksmodel.AmazonNodePoolsModel{ClusterID: eksCluster.ID, Name: nodePoolName}).
+		Updates(fields).Error
+	if err != nil {
+		return errors.WrapWithDetails(
+			err, "failed to update node pool",
+			"clusterId", clusterID,
+			"nodePoolName", nodePoolName,
+		)
+	}
+
+	return nil
+}
+
+func splitSecurityGroups(securityGroups string) []string {
+	if securityGroups == "" {
+		return nil
+	}
+
+	return strings.Split(securityGroups, ",")
+}
+
+func (s nodePoolStore) getEKSCluster(clusterID uint) (eksmodel.EKSClusterModel, error) {
+	var eksCluster eksmodel.EKSClusterModel
+
+	err := s.db.Where(eksmodel.EKSClusterModel{ClusterID: clusterID}).First(&eksCluster).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return eksCluster, errors.NewWithDetails("cluster model is inconsistent", "clusterId", clusterID)
+	}
+	if err != nil {
+		return eksCluster, errors.WrapWithDetails(err, "failed to get cluster info", "clusterId", clusterID)
+	}
+
+	return eksCluster, nil
+}
@@ -20,6 +20,7 @@ import (
 	"emperror.dev/errors"
 	"github.com/Masterminds/semver/v3"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	eks2 "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
@@ -58,6 +59,7 @@ type NodePool struct {
 	Count        int               `json:"count" yaml:"count"`
 	Image        string            `json:"image" yaml:"image"`
 	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints       []cluster.Taint   `json:"taints,omitempty" yaml:"taints,omitempty"`
 	// Subnet for worker nodes of this node pool. If not specified than worker nodes
 	// are launched in the same subnet in one of the subnets from the list of subnets of the EKS cluster
 	Subnet *Subnet `json:"subnet,omitempty" yaml:"subnet,omitempty"`
@@ -145,6 +147,11 @@ func (a *NodePool) Validate(npName string) error {
 		return err
 	}
 
+	// --- [Taint validation]--- //
+	if err := cluster.ValidateNodePoolTaints(npName, a.Taints); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -185,6 +192,11 @@ func (a *NodePool) ValidateForUpdate(npName string) error {
 		return err
 	}
 
+	// --- [Taint validation]--- //
+	if err := cluster.ValidateNodePoolTaints(npName, a.Taints); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"emperror.dev/errors"
 	"github.com/jinzhu/gorm"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/global"
 )
@@ -124,20 +125,24 @@ func (cm EKSClusterModel) String() string {
 
 // AmazonNodePoolsModel describes Amazon node groups model of a cluster
 type AmazonNodePoolsModel struct {
-	ID               uint `gorm:"primary_key"`
-	CreatedAt        time.Time
-	CreatedBy        uint
-	ClusterID        uint   `gorm:"unique_index:idx_amazon_node_pools_cluster_id_name"`
-	Name             string `gorm:"unique_index:idx_amazon_node_pools_cluster_id_name"`
-	NodeSpotPrice    string
-	Autoscaling      bool
-	NodeMinCount     int
-	NodeMaxCount     int
-	Count            int
-	NodeImage        string
-	NodeInstanceType string
-	Labels           map[string]string `gorm:"-"`
-	Delete           bool              `gorm:"-"`
+	ID                 uint `gorm:"primary_key"`
+	CreatedAt          time.Time
+	CreatedBy          uint
+	ClusterID          uint   `gorm:"unique_index:idx_amazon_node_pools_cluster_id_name"`
+	Name               string `gorm:"unique_index:idx_amazon_node_pools_cluster_id_name"`
+	NodeSpotPrice      string
+	Autoscaling        bool
+	NodeMinCount       int
+	NodeMaxCount       int
+	Count              int
+	NodeImage          string
+	NodeInstanceType   string
+	NodeVolumeSize     int
+	NodeVolumeType     string
+	NodeSecurityGroups string            `gorm:"type:text"`
+	Labels             map[string]string `gorm:"-"`
+	Taints             cluster.Taints    `gorm:"type:text"`
+	Delete             bool              `gorm:"-"`
 }
 
 // TableName sets AmazonNodePoolsModel's table name
@@ -198,6 +198,7 @@ func (c *EksClusterCreator) create(ctx context.Context, logger logrus.FieldLogge
 			NodeImage:        np.NodeImage,
 			NodeInstanceType: np.NodeInstanceType,
 			Labels:           np.Labels,
+			Taints:           np.Taints,
 		}
 		asgList = append(asgList, asg)
 
@@ -90,6 +90,7 @@ func createNodePoolsFromUpdateRequest(eksCluster *cluster.EKSCluster, requestedN
 				NodeMaxCount:     nodePool.MaxCount,
 				Count:            nodePool.Count,
 				Labels:           nodePool.Labels,
+				Taints:           nodePool.Taints,
 				Delete:           false,
 			})
 		} else {
@@ -124,6 +125,7 @@ func createNodePoolsFromUpdateRequest(eksCluster *cluster.EKSCluster, requestedN
 				Count:            nodePool.Count,
 				Delete:           false,
 				Labels:           nodePool.Labels,
+				Taints:           nodePool.Taints,
 			})
 		}
 	}
@@ -298,6 +300,7 @@ func (c *EksClusterUpdater) update(ctx context.Context, logger logrus.FieldLogge
 			NodeImage:        np.NodeImage,
 			NodeInstanceType: np.NodeInstanceType,
 			Labels:           np.Labels,
+			Taints:           np.Taints,
 			Delete:           np.Delete,
 			CreatedBy:        np.CreatedBy,
 		}
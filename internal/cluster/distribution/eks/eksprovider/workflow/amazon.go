@@ -32,6 +32,7 @@ import (
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api/v1"
 	zapadapter "logur.dev/adapter/zap"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksmodel"
 	internalAmazon "github.com/banzaicloud/pipeline/internal/providers/amazon"
 	"github.com/banzaicloud/pipeline/pkg/providers/amazon/autoscaling"
@@ -182,11 +183,34 @@ type AutoscaleGroup struct {
 	NodeImage        string
 	NodeInstanceType string
 	Labels           map[string]string
+	Taints           []cluster.Taint
 	Delete           bool
 	Create           bool
 	CreatedBy        uint
 }
 
+// formatNodeTaints returns the taints in the format accepted by the kubelet.
+func formatNodeTaints(taints []cluster.Taint) string {
+	formatted := make([]string, 0, len(taints))
+
+	for _, taint := range taints {
+		formatted = append(formatted, taint.String())
+	}
+
+	return strings.Join(formatted, ",")
+}
+
+// formatBootstrapArguments returns the node bootstrap arguments registering the nodes with the given labels and taints.
+func formatBootstrapArguments(nodeLabels []string, taints []cluster.Taint) string {
+	kubeletArgs := fmt.Sprintf("--node-labels %v", strings.Join(nodeLabels, ","))
+
+	if len(taints) > 0 {
+		kubeletArgs += fmt.Sprintf(" --register-with-taints %v", formatNodeTaints(taints))
+	}
+
+	return fmt.Sprintf("--kubelet-extra-args '%v'", kubeletArgs)
+}
+
 type SecretStore interface {
 	Get(orgnaizationID uint, secretID string) (*secret.SecretItemResponse, error)
 	GetByName(orgnaizationID uint, secretID string) (*secret.SecretItemResponse, error)
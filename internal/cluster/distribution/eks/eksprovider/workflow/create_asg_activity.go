@@ -57,6 +57,7 @@ type CreateAsgActivityInput struct {
 	NodeImage        string
 	NodeInstanceType string
 	Labels           map[string]string
+	Taints           []cluster.Taint
 
 	Subnets             []Subnet
 	VpcID               string
@@ -121,7 +122,7 @@ func (a *CreateAsgActivity) Execute(ctx context.Context, input CreateAsgActivity
 	// we only add node pool name here, all other labels will be added by NodePoolLabelSet operator
 	nodeLabels := []string{
 		fmt.Sprintf("%v=%v", cluster.NodePoolNameLabelKey, input.Name),
-		fmt.Sprintf("%v=%v", cluster.NodePoolVersionLabelKey, eks.CalculateNodePoolVersion(input.NodeImage)),
+		fmt.Sprintf("%v=%v", cluster.NodePoolVersionLabelKey, eks.CalculateNodePoolLaunchConfigVersion(input.NodeImage, input.NodeInstanceType, input.NodeSpotPrice, "", "", "")),
 	}
 
 	var subnetIDs []string
@@ -196,9 +197,13 @@ func (a *CreateAsgActivity) Execute(ctx context.Context, input CreateAsgActivity
 			ParameterKey:   aws.String("TerminationDetachEnabled"),
 			ParameterValue: aws.String(fmt.Sprint(terminationDetachEnabled)),
 		},
+		{
+			ParameterKey:   aws.String("NodeTaints"),
+			ParameterValue: aws.String(formatNodeTaints(input.Taints)),
+		},
 		{
 			ParameterKey:   aws.String("BootstrapArguments"),
-			ParameterValue: aws.String(fmt.Sprintf("--kubelet-extra-args '--node-labels %v'", strings.Join(nodeLabels, ","))),
+			ParameterValue: aws.String(formatBootstrapArguments(nodeLabels, input.Taints)),
 		},
 	}
 	clientRequestToken := generateRequestToken(input.AWSClientRequestTokenBase, CreateAsgActivityName)
@@ -331,6 +331,7 @@ func CreateInfrastructureWorkflow(ctx workflow.Context, input CreateInfrastructu
 			NodeImage:        asg.NodeImage,
 			NodeInstanceType: asg.NodeInstanceType,
 			Labels:           asg.Labels,
+			Taints:           asg.Taints,
 		}
 		if input.UseGeneratedSSHKey {
 			activityInput.SSHKeyName = sshKeyName
@@ -36,6 +36,12 @@ const (
 
 // getEksCloudFormationTemplate returns CloudFormation template with given name
 func getEksCloudFormationTemplate(name string) (string, error) {
+	return renderEksCloudFormationTemplate(name, !global.Config.Pipeline.Enterprise)
+}
+
+// renderEksCloudFormationTemplate returns CloudFormation template with given name
+// optionally including the auto scaling group update policy
+func renderEksCloudFormationTemplate(name string, updatePolicyEnabled bool) (string, error) {
 	// location to retrieve the Cloud Formation template from
 	templatePath := global.Config.Distribution.EKS.TemplateLocation + "/" + name
 
@@ -69,7 +75,7 @@ func getEksCloudFormationTemplate(name string) (string, error) {
 
 	buffer := bytes.NewBuffer(nil)
 	data := map[string]interface{}{
-		"UpdatePolicyEnabled": !global.Config.Pipeline.Enterprise,
+		"UpdatePolicyEnabled": updatePolicyEnabled,
 	}
 	err = t.Execute(buffer, data)
 	if err != nil {
@@ -89,6 +95,12 @@ func GetNodePoolTemplate() (string, error) {
 	return getEksCloudFormationTemplate(eksNodePoolTemplateName)
 }
 
+// GetNodePoolUpdateTemplate returns the CloudFormation template for updating node pools for EKS cluster.
+// The template does not contain an update policy, nodes are replaced by the node pool update workflow.
+func GetNodePoolUpdateTemplate() (string, error) {
+	return renderEksCloudFormationTemplate(eksNodePoolTemplateName, false)
+}
+
 // GetSubnetTemplate returns the CloudFormation template for creating a Subnet
 func GetSubnetTemplate() (string, error) {
 	return getEksCloudFormationTemplate(eksSubnetTemplateName)
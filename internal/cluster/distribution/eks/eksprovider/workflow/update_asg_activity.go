@@ -63,6 +63,7 @@ type UpdateAsgActivityInput struct {
 	NodeImage        string
 	NodeInstanceType string
 	Labels           map[string]string
+	Taints           []cluster.Taint
 }
 
 // UpdateAsgActivityOutput holds the output data of the UpdateAsgActivityOutput
@@ -173,7 +174,7 @@ func (a *UpdateAsgActivity) Execute(ctx context.Context, input UpdateAsgActivity
 
 	nodeLabels := []string{
 		fmt.Sprintf("%v=%v", cluster.NodePoolNameLabelKey, input.Name),
-		fmt.Sprintf("%v=%v", cluster.NodePoolVersionLabelKey, eks.CalculateNodePoolVersion(input.NodeImage)),
+		fmt.Sprintf("%v=%v", cluster.NodePoolVersionLabelKey, eks.CalculateNodePoolLaunchConfigVersion(input.NodeImage, input.NodeInstanceType, input.NodeSpotPrice, "", "", "")),
 	}
 
 	stackParams := []*cloudformation.Parameter{
@@ -241,9 +242,13 @@ func (a *UpdateAsgActivity) Execute(ctx context.Context, input UpdateAsgActivity
 			ParameterKey:   aws.String("TerminationDetachEnabled"),
 			ParameterValue: aws.String(fmt.Sprint(terminationDetachEnabled)),
 		},
+		{
+			ParameterKey:   aws.String("NodeTaints"),
+			ParameterValue: aws.String(formatNodeTaints(input.Taints)),
+		},
 		{
 			ParameterKey:   aws.String("BootstrapArguments"),
-			ParameterValue: aws.String(fmt.Sprintf("--kubelet-extra-args '--node-labels %v'", strings.Join(nodeLabels, ","))),
+			ParameterValue: aws.String(formatBootstrapArguments(nodeLabels, input.Taints)),
 		},
 	}
 
@@ -16,6 +16,7 @@ package eksworkflow
 
 import (
 	"context"
+	"strings"
 
 	"go.uber.org/cadence/activity"
 
@@ -28,7 +29,12 @@ const CalculateNodePoolVersionActivityName = "eks-calculate-node-pool-version"
 type CalculateNodePoolVersionActivity struct{}
 
 type CalculateNodePoolVersionActivityInput struct {
-	Image string
+	Image          string
+	InstanceType   string
+	SpotPrice      string
+	VolumeSize     int
+	VolumeType     string
+	SecurityGroups []string
 }
 
 type CalculateNodePoolVersionActivityOutput struct {
@@ -51,6 +57,13 @@ func (a CalculateNodePoolVersionActivity) Execute(
 	input CalculateNodePoolVersionActivityInput,
 ) (CalculateNodePoolVersionActivityOutput, error) {
 	return CalculateNodePoolVersionActivityOutput{
-		Version: eks.CalculateNodePoolVersion(input.Image),
+		Version: eks.CalculateNodePoolLaunchConfigVersion(
+			input.Image,
+			input.InstanceType,
+			input.SpotPrice,
+			formatPositiveInt(input.VolumeSize),
+			input.VolumeType,
+			strings.Join(input.SecurityGroups, ","),
+		),
 	}, nil
 }
@@ -45,25 +45,50 @@ func init() {
 func TestCalculateNodePoolVersionActivity(t *testing.T) {
 	testCalculateNodePoolVersionActivity = NewCalculateNodePoolVersionActivity()
 
-	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
-
-	input := CalculateNodePoolVersionActivityInput{
-		Image: "ami-xxxxxxxxxxxxx",
+	tests := []struct {
+		name  string
+		input CalculateNodePoolVersionActivityInput
+	}{
+		{
+			name: "defaults",
+			input: CalculateNodePoolVersionActivityInput{
+				Image:        "ami-xxxxxxxxxxxxx",
+				InstanceType: "t2.medium",
+			},
+		},
+		{
+			name: "explicit defaults",
+			input: CalculateNodePoolVersionActivityInput{
+				Image:        "ami-xxxxxxxxxxxxx",
+				InstanceType: "t2.medium",
+				SpotPrice:    "0",
+				VolumeSize:   20,
+				VolumeType:   "gp2",
+			},
+		},
 	}
 
-	v, err := env.ExecuteActivity(CalculateNodePoolVersionActivityName, input)
-	require.NoError(t, err)
+	for _, test := range tests {
+		test := test
 
-	var output CalculateNodePoolVersionActivityOutput
+		t.Run(test.name, func(t *testing.T) {
+			env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
 
-	err = v.Get(&output)
-	require.NoError(t, err)
+			v, err := env.ExecuteActivity(CalculateNodePoolVersionActivityName, test.input)
+			require.NoError(t, err)
 
-	assert.Equal(
-		t,
-		CalculateNodePoolVersionActivityOutput{
-			Version: "c1d358c1b8d475f98c04d12b674c6f770ae41d09",
-		},
-		output,
-	)
+			var output CalculateNodePoolVersionActivityOutput
+
+			err = v.Get(&output)
+			require.NoError(t, err)
+
+			assert.Equal(
+				t,
+				CalculateNodePoolVersionActivityOutput{
+					Version: "67537359ab821ddd97dca21676615bf93d6cd356",
+				},
+				output,
+			)
+		})
+	}
 }
@@ -0,0 +1,51 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package eksworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+const DeleteHibernatedNodePoolsActivityName = "eks-delete-hibernated-node-pools"
+
+// DeleteHibernatedNodePoolsActivity deletes the recorded scaling settings of the node pools of a resumed cluster.
+type DeleteHibernatedNodePoolsActivity struct {
+	hibernations cluster.HibernationStore
+}
+
+// DeleteHibernatedNodePoolsActivityInput holds the parameters for deleting the hibernation records.
+type DeleteHibernatedNodePoolsActivityInput struct {
+	ClusterID uint
+}
+
+// NewDeleteHibernatedNodePoolsActivity creates a new DeleteHibernatedNodePoolsActivity instance.
+func NewDeleteHibernatedNodePoolsActivity(hibernations cluster.HibernationStore) DeleteHibernatedNodePoolsActivity {
+	return DeleteHibernatedNodePoolsActivity{
+		hibernations: hibernations,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a DeleteHibernatedNodePoolsActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: DeleteHibernatedNodePoolsActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a DeleteHibernatedNodePoolsActivity) Execute(ctx context.Context, input DeleteHibernatedNodePoolsActivityInput) error {
+	return a.hibernations.DeleteHibernatedNodePools(ctx, input.ClusterID)
+}
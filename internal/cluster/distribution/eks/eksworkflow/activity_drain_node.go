@@ -0,0 +1,121 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+const DrainNodeActivityName = "eks-drain-node"
+
+// DrainNodeActivity cordons a node and evicts its pods respecting PodDisruptionBudgets.
+type DrainNodeActivity struct {
+	clientFactory ClientFactory
+}
+
+// DrainNodeActivityInput holds the parameters for draining a node.
+type DrainNodeActivityInput struct {
+	// Kubernetes cluster config secret ID.
+	ConfigSecretID string
+
+	NodeName string
+
+	// Maximum time to wait for the pods to be evicted.
+	Timeout time.Duration
+}
+
+// NewDrainNodeActivity creates a new DrainNodeActivity instance.
+func NewDrainNodeActivity(clientFactory ClientFactory) DrainNodeActivity {
+	return DrainNodeActivity{
+		clientFactory: clientFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a DrainNodeActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: DrainNodeActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a DrainNodeActivity) Execute(ctx context.Context, input DrainNodeActivityInput) error {
+	client, err := a.clientFactory.FromSecret(ctx, input.ConfigSecretID)
+	if err != nil {
+		return err
+	}
+
+	node, err := client.CoreV1().Nodes().Get(input.NodeName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		// The node is already gone
+		return nil
+	}
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get node", "node", input.NodeName)
+	}
+
+	var out bytes.Buffer
+
+	helper := &drain.Helper{
+		Client:              client,
+		Force:               true,
+		GracePeriodSeconds:  -1,
+		IgnoreAllDaemonSets: true,
+		Timeout:             input.Timeout,
+		DeleteLocalData:     true,
+		Out:                 &out,
+		ErrOut:              &out,
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, _ bool) {
+			activity.RecordHeartbeat(ctx, pod.Namespace+"/"+pod.Name)
+		},
+	}
+
+	err = drain.RunCordonOrUncordon(helper, node, true)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to cordon node", "node", input.NodeName)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- drain.RunNodeDrain(helper, input.NodeName)
+	}()
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return errors.WrapIfWithDetails(err, "failed to drain node", "node", input.NodeName, "output", out.String())
+			}
+
+			return nil
+
+		case <-ticker.C:
+			activity.RecordHeartbeat(ctx)
+
+		case <-ctx.Done():
+			return errors.WrapIfWithDetails(ctx.Err(), "draining node interrupted", "node", input.NodeName)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"go.uber.org/cadence/activity"
+)
+
+const DetachNodeInstancesActivityName = "eks-detach-node-instances"
+
+// DetachNodeInstancesActivity detaches instances from the auto scaling group of a node pool
+// without decrementing its desired capacity, so that the auto scaling group launches replacements.
+type DetachNodeInstancesActivity struct {
+	sessionFactory AWSSessionFactory
+}
+
+// DetachNodeInstancesActivityInput holds the parameters for detaching node instances.
+type DetachNodeInstancesActivityInput struct {
+	SecretID string
+	Region   string
+
+	StackName   string
+	InstanceIDs []string
+}
+
+// NewDetachNodeInstancesActivity creates a new DetachNodeInstancesActivity instance.
+func NewDetachNodeInstancesActivity(sessionFactory AWSSessionFactory) DetachNodeInstancesActivity {
+	return DetachNodeInstancesActivity{
+		sessionFactory: sessionFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a DetachNodeInstancesActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: DetachNodeInstancesActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a DetachNodeInstancesActivity) Execute(ctx context.Context, input DetachNodeInstancesActivityInput) error {
+	sess, err := a.sessionFactory.NewSession(input.SecretID, input.Region)
+	if err = errors.WrapIf(err, "failed to create AWS session"); err != nil { // internal error?
+		return err
+	}
+
+	cloudformationClient := cloudformation.New(sess)
+	autoscalingClient := autoscaling.New(sess)
+
+	resource, err := cloudformationClient.DescribeStackResourceWithContext(ctx, &cloudformation.DescribeStackResourceInput{
+		LogicalResourceId: aws.String("NodeGroup"),
+		StackName:         aws.String(input.StackName),
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get auto scaling group of node pool", "stackName", input.StackName)
+	}
+
+	groupName := aws.StringValue(resource.StackResourceDetail.PhysicalResourceId)
+
+	// Only detach instances that are still attached (the activity might be retried)
+	instances, err := autoscalingClient.DescribeAutoScalingInstancesWithContext(ctx, &autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: aws.StringSlice(input.InstanceIDs),
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to describe auto scaling instances", "autoScalingGroup", groupName)
+	}
+
+	var instanceIDs []*string
+	for _, instance := range instances.AutoScalingInstances {
+		if aws.StringValue(instance.AutoScalingGroupName) == groupName {
+			instanceIDs = append(instanceIDs, instance.InstanceId)
+		}
+	}
+
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	_, err = autoscalingClient.DetachInstancesWithContext(ctx, &autoscaling.DetachInstancesInput{
+		AutoScalingGroupName:           aws.String(groupName),
+		InstanceIds:                    instanceIDs,
+		ShouldDecrementDesiredCapacity: aws.Bool(false),
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to detach instances", "autoScalingGroup", groupName)
+	}
+
+	return nil
+}
+
+const AttachNodeInstancesActivityName = "eks-attach-node-instances"
+
+// AttachNodeInstancesActivity attaches detached instances to the auto scaling group of their node pool again
+// (incrementing its desired capacity), so that they do not leak when a node replacement fails.
+type AttachNodeInstancesActivity struct {
+	sessionFactory AWSSessionFactory
+}
+
+// AttachNodeInstancesActivityInput holds the parameters for attaching node instances.
+type AttachNodeInstancesActivityInput struct {
+	SecretID string
+	Region   string
+
+	StackName   string
+	InstanceIDs []string
+}
+
+// NewAttachNodeInstancesActivity creates a new AttachNodeInstancesActivity instance.
+func NewAttachNodeInstancesActivity(sessionFactory AWSSessionFactory) AttachNodeInstancesActivity {
+	return AttachNodeInstancesActivity{
+		sessionFactory: sessionFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a AttachNodeInstancesActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: AttachNodeInstancesActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a AttachNodeInstancesActivity) Execute(ctx context.Context, input AttachNodeInstancesActivityInput) error {
+	sess, err := a.sessionFactory.NewSession(input.SecretID, input.Region)
+	if err = errors.WrapIf(err, "failed to create AWS session"); err != nil { // internal error?
+		return err
+	}
+
+	cloudformationClient := cloudformation.New(sess)
+	autoscalingClient := autoscaling.New(sess)
+
+	resource, err := cloudformationClient.DescribeStackResourceWithContext(ctx, &cloudformation.DescribeStackResourceInput{
+		LogicalResourceId: aws.String("NodeGroup"),
+		StackName:         aws.String(input.StackName),
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get auto scaling group of node pool", "stackName", input.StackName)
+	}
+
+	groupName := aws.StringValue(resource.StackResourceDetail.PhysicalResourceId)
+
+	// Only attach running instances that are not attached yet (the activity might be retried)
+	instances, err := autoscalingClient.DescribeAutoScalingInstancesWithContext(ctx, &autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: aws.StringSlice(input.InstanceIDs),
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to describe auto scaling instances", "autoScalingGroup", groupName)
+	}
+
+	attached := make(map[string]bool, len(instances.AutoScalingInstances))
+	for _, instance := range instances.AutoScalingInstances {
+		attached[aws.StringValue(instance.InstanceId)] = true
+	}
+
+	var detachedIDs []string
+	for _, instanceID := range input.InstanceIDs {
+		if !attached[instanceID] {
+			detachedIDs = append(detachedIDs, instanceID)
+		}
+	}
+
+	if len(detachedIDs) == 0 {
+		return nil
+	}
+
+	reservations, err := ec2.New(sess).DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice(detachedIDs),
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+			},
+		},
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to describe instances", "instances", detachedIDs)
+	}
+
+	var instanceIDs []*string
+	for _, reservation := range reservations.Reservations {
+		for _, instance := range reservation.Instances {
+			instanceIDs = append(instanceIDs, instance.InstanceId)
+		}
+	}
+
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	_, err = autoscalingClient.AttachInstancesWithContext(ctx, &autoscaling.AttachInstancesInput{
+		AutoScalingGroupName: aws.String(groupName),
+		InstanceIds:          instanceIDs,
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to attach instances", "autoScalingGroup", groupName)
+	}
+
+	return nil
+}
+
+const TerminateNodeInstanceActivityName = "eks-terminate-node-instance"
+
+// TerminateNodeInstanceActivity terminates the instance of a drained node.
+type TerminateNodeInstanceActivity struct {
+	sessionFactory AWSSessionFactory
+}
+
+// TerminateNodeInstanceActivityInput holds the parameters for terminating a node instance.
+type TerminateNodeInstanceActivityInput struct {
+	SecretID string
+	Region   string
+
+	InstanceID string
+
+	// Detached instances are terminated directly,
+	// otherwise the auto scaling group is asked to terminate (and replace) the instance.
+	Detached bool
+}
+
+// NewTerminateNodeInstanceActivity creates a new TerminateNodeInstanceActivity instance.
+func NewTerminateNodeInstanceActivity(sessionFactory AWSSessionFactory) TerminateNodeInstanceActivity {
+	return TerminateNodeInstanceActivity{
+		sessionFactory: sessionFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a TerminateNodeInstanceActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: TerminateNodeInstanceActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a TerminateNodeInstanceActivity) Execute(ctx context.Context, input TerminateNodeInstanceActivityInput) error {
+	sess, err := a.sessionFactory.NewSession(input.SecretID, input.Region)
+	if err = errors.WrapIf(err, "failed to create AWS session"); err != nil { // internal error?
+		return err
+	}
+
+	if input.Detached {
+		_, err = ec2.New(sess).TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{
+			InstanceIds: aws.StringSlice([]string{input.InstanceID}),
+		})
+	} else {
+		_, err = autoscaling.New(sess).TerminateInstanceInAutoScalingGroupWithContext(ctx, &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+			InstanceId:                     aws.String(input.InstanceID),
+			ShouldDecrementDesiredCapacity: aws.Bool(false),
+		})
+	}
+	if err != nil {
+		// The instance is already gone (the activity might be retried)
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && strings.Contains(strings.ToLower(awsErr.Message()), "not found") {
+			return nil
+		}
+
+		return errors.WrapIfWithDetails(err, "failed to terminate instance", "instance", input.InstanceID)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+const ListNodePoolNodesActivityName = "eks-list-node-pool-nodes"
+
+// ListNodePoolNodesActivity lists the Kubernetes nodes of a node pool.
+type ListNodePoolNodesActivity struct {
+	clientFactory ClientFactory
+}
+
+// ListNodePoolNodesActivityInput holds the parameters for listing node pool nodes.
+type ListNodePoolNodesActivityInput struct {
+	// Kubernetes cluster config secret ID.
+	ConfigSecretID string
+
+	NodePoolName string
+}
+
+// ListNodePoolNodesActivityOutput holds the nodes of a node pool.
+type ListNodePoolNodesActivityOutput struct {
+	Nodes []NodePoolNode
+}
+
+// NodePoolNode describes a Kubernetes node in a node pool.
+type NodePoolNode struct {
+	Name       string
+	InstanceID string
+	Version    string
+	Ready      bool
+}
+
+// NewListNodePoolNodesActivity creates a new ListNodePoolNodesActivity instance.
+func NewListNodePoolNodesActivity(clientFactory ClientFactory) ListNodePoolNodesActivity {
+	return ListNodePoolNodesActivity{
+		clientFactory: clientFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a ListNodePoolNodesActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: ListNodePoolNodesActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a ListNodePoolNodesActivity) Execute(
+	ctx context.Context,
+	input ListNodePoolNodesActivityInput,
+) (ListNodePoolNodesActivityOutput, error) {
+	nodes, err := listNodePoolNodes(ctx, a.clientFactory, input.ConfigSecretID, input.NodePoolName)
+	if err != nil {
+		return ListNodePoolNodesActivityOutput{}, err
+	}
+
+	return ListNodePoolNodesActivityOutput{Nodes: nodes}, nil
+}
+
+const WaitNodePoolNodesReadyActivityName = "eks-wait-node-pool-nodes-ready"
+
+// WaitNodePoolNodesReadyActivity waits until a given number of nodes are ready in a node pool with a specific version.
+type WaitNodePoolNodesReadyActivity struct {
+	clientFactory ClientFactory
+}
+
+// WaitNodePoolNodesReadyActivityInput holds the parameters for waiting for node pool nodes.
+type WaitNodePoolNodesReadyActivityInput struct {
+	// Kubernetes cluster config secret ID.
+	ConfigSecretID string
+
+	NodePoolName    string
+	NodePoolVersion string
+
+	// Number of ready nodes with the node pool version to wait for.
+	Count int
+}
+
+// NewWaitNodePoolNodesReadyActivity creates a new WaitNodePoolNodesReadyActivity instance.
+func NewWaitNodePoolNodesReadyActivity(clientFactory ClientFactory) WaitNodePoolNodesReadyActivity {
+	return WaitNodePoolNodesReadyActivity{
+		clientFactory: clientFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a WaitNodePoolNodesReadyActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: WaitNodePoolNodesReadyActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a WaitNodePoolNodesReadyActivity) Execute(ctx context.Context, input WaitNodePoolNodesReadyActivityInput) error {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		nodes, err := listNodePoolNodes(ctx, a.clientFactory, input.ConfigSecretID, input.NodePoolName)
+		if err != nil {
+			return err
+		}
+
+		ready := countReadyNodes(nodes, input.NodePoolVersion)
+		if ready >= input.Count {
+			return nil
+		}
+
+		activity.RecordHeartbeat(ctx, ready)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.WrapIfWithDetails(
+				ctx.Err(), "waiting for node pool nodes to become ready failed",
+				"nodePool", input.NodePoolName,
+				"ready", ready,
+				"expected", input.Count,
+			)
+		}
+	}
+}
+
+func listNodePoolNodes(ctx context.Context, clientFactory ClientFactory, configSecretID string, nodePoolName string) ([]NodePoolNode, error) {
+	client, err := clientFactory.FromSecret(ctx, configSecretID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeList, err := client.CoreV1().Nodes().List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", cluster.NodePoolNameLabelKey, nodePoolName),
+	})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list nodes", "nodePool", nodePoolName)
+	}
+
+	nodes := make([]NodePoolNode, 0, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		nodes = append(nodes, NodePoolNode{
+			Name:       node.Name,
+			InstanceID: getInstanceID(node.Spec.ProviderID),
+			Version:    node.Labels[cluster.NodePoolVersionLabelKey],
+			Ready:      isNodeReady(node),
+		})
+	}
+
+	return nodes, nil
+}
+
+// getInstanceID extracts the EC2 instance ID from a provider ID (eg. aws:///eu-west-1a/i-0123456789abcdef0).
+func getInstanceID(providerID string) string {
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
+
+func isNodeReady(node corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func countReadyNodes(nodes []NodePoolNode, version string) int {
+	var count int
+
+	for _, node := range nodes {
+		if node.Ready && node.Version == version {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,125 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package eksworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+const PrepareHibernateClusterActivityName = "eks-prepare-hibernate-cluster"
+
+// PrepareHibernateClusterActivity records the scaling settings of the node pools of a cluster before hibernation.
+type PrepareHibernateClusterActivity struct {
+	clusters     cluster.Store
+	nodePools    eks.NodePoolStore
+	hibernations cluster.HibernationStore
+}
+
+// PrepareHibernateClusterActivityInput holds the parameters for preparing the hibernation.
+type PrepareHibernateClusterActivityInput struct {
+	ClusterID uint
+
+	// Scheduled hibernations are skipped when the cluster is not ready.
+	Scheduled bool
+}
+
+type PrepareHibernateClusterActivityOutput struct {
+	Skip bool
+
+	ProviderSecretID string
+	Region           string
+	ClusterName      string
+
+	NodePools []cluster.HibernatedNodePool
+}
+
+// NewPrepareHibernateClusterActivity creates a new PrepareHibernateClusterActivity instance.
+func NewPrepareHibernateClusterActivity(
+	clusters cluster.Store,
+	nodePools eks.NodePoolStore,
+	hibernations cluster.HibernationStore,
+) PrepareHibernateClusterActivity {
+	return PrepareHibernateClusterActivity{
+		clusters:     clusters,
+		nodePools:    nodePools,
+		hibernations: hibernations,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a PrepareHibernateClusterActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: PrepareHibernateClusterActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a PrepareHibernateClusterActivity) Execute(ctx context.Context, input PrepareHibernateClusterActivityInput) (PrepareHibernateClusterActivityOutput, error) {
+	c, err := a.clusters.GetCluster(ctx, input.ClusterID)
+	if input.Scheduled && cluster.IsNotFoundError(err) {
+		return PrepareHibernateClusterActivityOutput{Skip: true}, nil
+	} else if err != nil {
+		return PrepareHibernateClusterActivityOutput{}, err
+	}
+
+	if input.Scheduled {
+		if c.Status != cluster.Running && c.Status != cluster.Warning {
+			return PrepareHibernateClusterActivityOutput{Skip: true}, nil
+		}
+
+		ctx := ctxutil.WithProcessID(ctx, activity.GetInfo(ctx).WorkflowExecution.ID)
+
+		err := a.clusters.SetStatus(ctx, input.ClusterID, cluster.Updating, "hibernating cluster")
+		if err != nil {
+			return PrepareHibernateClusterActivityOutput{}, err
+		}
+	}
+
+	nodePoolNames, err := a.nodePools.ListNodePoolNames(ctx, input.ClusterID)
+	if err != nil {
+		return PrepareHibernateClusterActivityOutput{}, err
+	}
+
+	nodePools := make([]cluster.HibernatedNodePool, 0, len(nodePoolNames))
+	for _, nodePoolName := range nodePoolNames {
+		nodePool, err := a.nodePools.GetNodePool(ctx, input.ClusterID, nodePoolName)
+		if err != nil {
+			return PrepareHibernateClusterActivityOutput{}, err
+		}
+
+		nodePools = append(nodePools, cluster.HibernatedNodePool{
+			Name:        nodePool.Name,
+			Size:        nodePool.Size,
+			Autoscaling: nodePool.Autoscaling.Enabled,
+			MinSize:     nodePool.Autoscaling.MinSize,
+			MaxSize:     nodePool.Autoscaling.MaxSize,
+		})
+	}
+
+	err = a.hibernations.SaveHibernatedNodePools(ctx, input.ClusterID, nodePools)
+	if err != nil {
+		return PrepareHibernateClusterActivityOutput{}, err
+	}
+
+	return PrepareHibernateClusterActivityOutput{
+		ProviderSecretID: c.SecretID.String(),
+		Region:           c.Location,
+		ClusterName:      c.Name,
+		NodePools:        nodePools,
+	}, nil
+}
@@ -0,0 +1,53 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+)
+
+const SaveClusterVersionActivityName = "eks-save-cluster-version"
+
+// SaveClusterVersionActivity saves the Kubernetes version of a cluster.
+type SaveClusterVersionActivity struct {
+	clusters eks.ClusterStore
+}
+
+// SaveClusterVersionActivityInput holds the parameters for saving the cluster version.
+type SaveClusterVersionActivityInput struct {
+	ClusterID         uint
+	KubernetesVersion string
+}
+
+// NewSaveClusterVersionActivity creates a new SaveClusterVersionActivity instance.
+func NewSaveClusterVersionActivity(clusters eks.ClusterStore) SaveClusterVersionActivity {
+	return SaveClusterVersionActivity{
+		clusters: clusters,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a SaveClusterVersionActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: SaveClusterVersionActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a SaveClusterVersionActivity) Execute(ctx context.Context, input SaveClusterVersionActivityInput) error {
+	return a.clusters.SetKubernetesVersion(ctx, input.ClusterID, input.KubernetesVersion)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+)
+
+const SaveNodePoolActivityName = "eks-save-node-pool"
+
+// SaveNodePoolActivity saves the persisted properties of an updated node pool.
+type SaveNodePoolActivity struct {
+	nodePools eks.NodePoolStore
+}
+
+// SaveNodePoolActivityInput holds the parameters for saving the node pool.
+type SaveNodePoolActivityInput struct {
+	ClusterID      uint
+	NodePoolName   string
+	NodePoolUpdate eks.NodePoolUpdate
+}
+
+// NewSaveNodePoolActivity creates a new SaveNodePoolActivity instance.
+func NewSaveNodePoolActivity(nodePools eks.NodePoolStore) SaveNodePoolActivity {
+	return SaveNodePoolActivity{
+		nodePools: nodePools,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a SaveNodePoolActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: SaveNodePoolActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a SaveNodePoolActivity) Execute(ctx context.Context, input SaveNodePoolActivityInput) error {
+	return a.nodePools.UpdateNodePool(ctx, input.ClusterID, input.NodePoolName, input.NodePoolUpdate)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+)
+
+const SaveNodePoolImageActivityName = "eks-save-node-pool-image"
+
+// SaveNodePoolImageActivity saves the image of a node pool.
+type SaveNodePoolImageActivity struct {
+	nodePools eks.NodePoolStore
+}
+
+// SaveNodePoolImageActivityInput holds the parameters for saving the node pool image.
+type SaveNodePoolImageActivityInput struct {
+	ClusterID    uint
+	NodePoolName string
+	NodeImage    string
+}
+
+// NewSaveNodePoolImageActivity creates a new SaveNodePoolImageActivity instance.
+func NewSaveNodePoolImageActivity(nodePools eks.NodePoolStore) SaveNodePoolImageActivity {
+	return SaveNodePoolImageActivity{
+		nodePools: nodePools,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a SaveNodePoolImageActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: SaveNodePoolImageActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a SaveNodePoolImageActivity) Execute(ctx context.Context, input SaveNodePoolImageActivityInput) error {
+	return a.nodePools.UpdateNodePoolImage(ctx, input.ClusterID, input.NodePoolName, input.NodeImage)
+}
@@ -0,0 +1,143 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package eksworkflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"go.uber.org/cadence/activity"
+)
+
+const ScaleNodeGroupActivityName = "eks-scale-node-group"
+
+// ScaleNodeGroupActivity updates the scaling settings of an existing node group.
+//
+// Unlike UpdateNodeGroupActivity, it reuses the current template and every other stack parameter,
+// so it never replaces the nodes and allows scaling the node group to zero.
+type ScaleNodeGroupActivity struct {
+	sessionFactory AWSSessionFactory
+}
+
+// ScaleNodeGroupActivityInput holds the parameters for the node group scaling.
+type ScaleNodeGroupActivityInput struct {
+	SecretID string
+	Region   string
+
+	ClusterName string
+
+	StackName string
+
+	MinSize                  int
+	MaxSize                  int
+	DesiredCapacity          int
+	ClusterAutoscalerEnabled bool
+}
+
+type ScaleNodeGroupActivityOutput struct {
+	NodePoolChanged bool
+}
+
+// NewScaleNodeGroupActivity creates a new ScaleNodeGroupActivity instance.
+func NewScaleNodeGroupActivity(sessionFactory AWSSessionFactory) ScaleNodeGroupActivity {
+	return ScaleNodeGroupActivity{
+		sessionFactory: sessionFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a ScaleNodeGroupActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: ScaleNodeGroupActivityName})
+}
+
+// Execute is the main body of the activity, returns true if there was any update and that was successful.
+func (a ScaleNodeGroupActivity) Execute(ctx context.Context, input ScaleNodeGroupActivityInput) (ScaleNodeGroupActivityOutput, error) {
+	sess, err := a.sessionFactory.NewSession(input.SecretID, input.Region)
+	if err = errors.WrapIf(err, "failed to create AWS session"); err != nil { // internal error?
+		return ScaleNodeGroupActivityOutput{}, err
+	}
+
+	cloudformationClient := cloudformation.New(sess)
+
+	stacks, err := cloudformationClient.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(input.StackName),
+	})
+	if err != nil {
+		return ScaleNodeGroupActivityOutput{}, errors.WrapIfWithDetails(err, "failed to describe stack", "stackName", input.StackName)
+	}
+
+	if len(stacks.Stacks) == 0 {
+		return ScaleNodeGroupActivityOutput{}, errors.NewWithDetails("stack not found", "stackName", input.StackName)
+	}
+
+	clusterAutoscalerEnabled := input.ClusterAutoscalerEnabled
+
+	scalingParams := map[string]bool{
+		"NodeAutoScalingGroupMinSize": true,
+		"NodeAutoScalingGroupMaxSize": true,
+		"NodeAutoScalingInitSize":     true,
+		"ClusterAutoscalerEnabled":    true,
+	}
+
+	var stackParams []*cloudformation.Parameter
+	for _, param := range stacks.Stacks[0].Parameters {
+		key := aws.StringValue(param.ParameterKey)
+
+		// Cluster Autoscaler is disabled on all node pools if scale options are enabled on the cluster
+		if key == "TerminationDetachEnabled" && aws.StringValue(param.ParameterValue) == "true" {
+			clusterAutoscalerEnabled = false
+		}
+
+		if scalingParams[key] {
+			continue
+		}
+
+		stackParams = append(stackParams, previousStackParameter(key))
+	}
+
+	stackParams = append(
+		stackParams,
+		stackParameter("NodeAutoScalingGroupMinSize", fmt.Sprint(input.MinSize)),
+		stackParameter("NodeAutoScalingGroupMaxSize", fmt.Sprint(input.MaxSize)),
+		stackParameter("NodeAutoScalingInitSize", fmt.Sprint(input.DesiredCapacity)),
+		stackParameter("ClusterAutoscalerEnabled", fmt.Sprint(clusterAutoscalerEnabled)),
+	)
+
+	// scheduled workflows share the same workflow ID, so the run ID is used as a request token
+	updateStackInput := &cloudformation.UpdateStackInput{
+		ClientRequestToken:  aws.String(activity.GetInfo(ctx).WorkflowExecution.RunID),
+		StackName:           aws.String(input.StackName),
+		Capabilities:        []*string{aws.String(cloudformation.CapabilityCapabilityIam)},
+		Parameters:          stackParams,
+		Tags:                getNodePoolStackTags(input.ClusterName),
+		UsePreviousTemplate: aws.Bool(true),
+	}
+
+	_, err = cloudformationClient.UpdateStackWithContext(ctx, updateStackInput)
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == "ValidationError" && strings.HasPrefix(awsErr.Message(), awsNoUpdatesError) {
+			return ScaleNodeGroupActivityOutput{}, nil
+		}
+
+		return ScaleNodeGroupActivityOutput{}, errors.WrapIfWithDetails(err, "failed to update stack", "stackName", input.StackName)
+	}
+
+	return ScaleNodeGroupActivityOutput{NodePoolChanged: true}, nil
+}
@@ -0,0 +1,76 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+const UncordonNodeActivityName = "eks-uncordon-node"
+
+// UncordonNodeActivity marks a node schedulable again.
+type UncordonNodeActivity struct {
+	clientFactory ClientFactory
+}
+
+// UncordonNodeActivityInput holds the parameters for uncordoning a node.
+type UncordonNodeActivityInput struct {
+	// Kubernetes cluster config secret ID.
+	ConfigSecretID string
+
+	NodeName string
+}
+
+// NewUncordonNodeActivity creates a new UncordonNodeActivity instance.
+func NewUncordonNodeActivity(clientFactory ClientFactory) UncordonNodeActivity {
+	return UncordonNodeActivity{
+		clientFactory: clientFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a UncordonNodeActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: UncordonNodeActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a UncordonNodeActivity) Execute(ctx context.Context, input UncordonNodeActivityInput) error {
+	client, err := a.clientFactory.FromSecret(ctx, input.ConfigSecretID)
+	if err != nil {
+		return err
+	}
+
+	node, err := client.CoreV1().Nodes().Get(input.NodeName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		// The node is already gone
+		return nil
+	}
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get node", "node", input.NodeName)
+	}
+
+	err = drain.RunCordonOrUncordon(&drain.Helper{Client: client}, node, false)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to uncordon node", "node", input.NodeName)
+	}
+
+	return nil
+}
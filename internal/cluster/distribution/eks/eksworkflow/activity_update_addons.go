@@ -0,0 +1,183 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver/v3"
+	"go.uber.org/cadence/activity"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+)
+
+const UpdateAddonsActivityName = "eks-update-addons"
+
+const (
+	kubeSystemNamespace = "kube-system"
+
+	kubeProxyName = "kube-proxy"
+	coreDNSName   = "coredns"
+	cniName       = "aws-node"
+)
+
+// ClientFactory returns a Kubernetes client.
+type ClientFactory interface {
+	// FromSecret creates a Kubernetes client for a cluster from a secret.
+	FromSecret(ctx context.Context, secretID string) (kubernetes.Interface, error)
+}
+
+// UpdateAddonsActivity updates the add-ons (kube-proxy, CoreDNS, CNI) deployed to every EKS cluster
+// to the versions recommended for the cluster's Kubernetes version.
+type UpdateAddonsActivity struct {
+	clientFactory ClientFactory
+}
+
+// UpdateAddonsActivityInput holds the parameters for the add-on update.
+type UpdateAddonsActivityInput struct {
+	// Kubernetes cluster config secret ID.
+	ConfigSecretID string
+
+	KubernetesVersion string
+}
+
+// NewUpdateAddonsActivity creates a new UpdateAddonsActivity instance.
+func NewUpdateAddonsActivity(clientFactory ClientFactory) UpdateAddonsActivity {
+	return UpdateAddonsActivity{
+		clientFactory: clientFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a UpdateAddonsActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: UpdateAddonsActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a UpdateAddonsActivity) Execute(ctx context.Context, input UpdateAddonsActivityInput) error {
+	versions, err := eks.GetAddonVersions(input.KubernetesVersion)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.clientFactory.FromSecret(ctx, input.ConfigSecretID)
+	if err != nil {
+		return err
+	}
+
+	daemonSets := client.AppsV1().DaemonSets(kubeSystemNamespace)
+
+	for name, version := range map[string]string{kubeProxyName: versions.KubeProxy, cniName: versions.CNI} {
+		daemonSet, err := daemonSets.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to get daemon set", "daemonSet", name)
+		}
+
+		if !upgradeContainerImage(&daemonSet.Spec.Template.Spec, name, version) {
+			continue
+		}
+
+		_, err = daemonSets.Update(daemonSet)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to update daemon set", "daemonSet", name)
+		}
+	}
+
+	deployments := client.AppsV1().Deployments(kubeSystemNamespace)
+
+	deployment, err := deployments.Get(coreDNSName, metav1.GetOptions{})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get deployment", "deployment", coreDNSName)
+	}
+
+	if upgradeContainerImage(&deployment.Spec.Template.Spec, coreDNSName, versions.CoreDNS) {
+		// The proxy plugin has been removed from CoreDNS 1.5
+		configMaps := client.CoreV1().ConfigMaps(kubeSystemNamespace)
+
+		configMap, err := configMaps.Get(coreDNSName, metav1.GetOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to get config map", "configMap", coreDNSName)
+		}
+
+		if corefile, ok := configMap.Data["Corefile"]; ok && strings.Contains(corefile, "proxy . ") {
+			configMap.Data["Corefile"] = strings.ReplaceAll(corefile, "proxy . ", "forward . ")
+
+			_, err = configMaps.Update(configMap)
+			if err != nil {
+				return errors.WrapIfWithDetails(err, "failed to update config map", "configMap", coreDNSName)
+			}
+		}
+
+		_, err = deployments.Update(deployment)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to update deployment", "deployment", coreDNSName)
+		}
+	}
+
+	return nil
+}
+
+// upgradeContainerImage sets the image tag of a container if the current tag is older than the desired one.
+// It returns true if the image has been changed.
+func upgradeContainerImage(podSpec *corev1.PodSpec, containerName string, tag string) bool {
+	for i, container := range podSpec.Containers {
+		if container.Name != containerName {
+			continue
+		}
+
+		repository, currentTag := splitImage(container.Image)
+
+		if !isNewerTag(currentTag, tag) {
+			return false
+		}
+
+		podSpec.Containers[i].Image = repository + ":" + tag
+
+		return true
+	}
+
+	return false
+}
+
+// splitImage splits an image reference into repository and tag.
+func splitImage(image string) (string, string) {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return image, ""
+	}
+
+	return image[:i], image[i+1:]
+}
+
+// isNewerTag returns true if the desired tag represents a newer version than the current one.
+// Unknown current tags are always replaced.
+func isNewerTag(current string, desired string) bool {
+	desiredVersion, err := semver.NewVersion(strings.Split(desired, "-")[0])
+	if err != nil {
+		return false
+	}
+
+	currentVersion, err := semver.NewVersion(strings.Split(current, "-")[0])
+	if err != nil {
+		return true
+	}
+
+	return desiredVersion.GreaterThan(currentVersion)
+}
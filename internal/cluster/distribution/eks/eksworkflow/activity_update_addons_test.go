@@ -0,0 +1,81 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestUpgradeContainerImage(t *testing.T) {
+	const repository = "602401143452.dkr.ecr.us-west-2.amazonaws.com/eks/kube-proxy"
+
+	tests := []struct {
+		name          string
+		image         string
+		tag           string
+		expectedImage string
+		changed       bool
+	}{
+		{
+			name:          "newer",
+			image:         repository + ":v1.14.6",
+			tag:           "v1.15.11",
+			expectedImage: repository + ":v1.15.11",
+			changed:       true,
+		},
+		{
+			name:          "same",
+			image:         repository + ":v1.15.11",
+			tag:           "v1.15.11",
+			expectedImage: repository + ":v1.15.11",
+		},
+		{
+			name:          "older",
+			image:         repository + ":v1.6.3",
+			tag:           "v1.6.1",
+			expectedImage: repository + ":v1.6.3",
+		},
+		{
+			name:          "suffixed",
+			image:         repository + ":v1.14.6-eksbuild.1",
+			tag:           "v1.15.11",
+			expectedImage: repository + ":v1.15.11",
+			changed:       true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "kube-proxy",
+						Image: test.image,
+					},
+				},
+			}
+
+			changed := upgradeContainerImage(&podSpec, "kube-proxy", test.tag)
+
+			assert.Equal(t, test.changed, changed)
+			assert.Equal(t, test.expectedImage, podSpec.Containers[0].Image)
+		})
+	}
+}
@@ -17,6 +17,7 @@ package eksworkflow
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"emperror.dev/errors"
@@ -28,6 +29,7 @@ import (
 	"go.uber.org/cadence/activity"
 
 	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
 	pkgCloudformation "github.com/banzaicloud/pipeline/pkg/providers/amazon/cloudformation"
 )
 
@@ -44,6 +46,8 @@ type UpdateNodeGroupActivity struct {
 }
 
 // UpdateNodeGroupActivityInput holds the parameters for the node group update.
+//
+// Zero values leave the current node group settings intact.
 type UpdateNodeGroupActivityInput struct {
 	SecretID string
 	Region   string
@@ -52,15 +56,22 @@ type UpdateNodeGroupActivityInput struct {
 
 	StackName string
 
-	NodePoolName    string
-	NodePoolVersion string
+	NodePoolName string
 
-	NodeImage       string
-	DesiredCapacity int64
+	Size           int
+	Autoscaling    eks.NodePoolAutoscaling
+	InstanceType   string
+	NodeImage      string
+	SpotPrice      string
+	VolumeSize     int
+	VolumeType     string
+	SecurityGroups []string
+	Taints         []cluster.Taint
 }
 
 type UpdateNodeGroupActivityOutput struct {
 	NodePoolChanged bool
+	NodePoolVersion string
 }
 
 // NewUpdateNodeGroupActivity creates a new UpdateNodeGroupActivity instance.
@@ -85,105 +96,154 @@ func (a UpdateNodeGroupActivity) Execute(ctx context.Context, input UpdateNodeGr
 
 	cloudformationClient := cloudformation.New(sess)
 
-	nodeLabels := []string{
-		fmt.Sprintf("%v=%v", cluster.NodePoolNameLabelKey, input.NodePoolName),
+	stacks, err := cloudformationClient.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(input.StackName),
+	})
+	if err != nil {
+		return UpdateNodeGroupActivityOutput{}, errors.WrapIfWithDetails(err, "failed to describe stack", "stackName", input.StackName)
 	}
 
-	if input.NodePoolVersion != "" {
-		nodeLabels = append(nodeLabels, fmt.Sprintf("%v=%v", cluster.NodePoolVersionLabelKey, input.NodePoolVersion))
+	if len(stacks.Stacks) == 0 {
+		return UpdateNodeGroupActivityOutput{}, errors.NewWithDetails("stack not found", "stackName", input.StackName)
+	}
+
+	previousParams := make(map[string]string)
+	for _, param := range stacks.Stacks[0].Parameters {
+		previousParams[aws.StringValue(param.ParameterKey)] = aws.StringValue(param.ParameterValue)
 	}
 
 	stackParams := []*cloudformation.Parameter{
-		{
-			ParameterKey:     aws.String("KeyName"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("NodeInstanceType"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("NodeSpotPrice"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("NodeAutoScalingGroupMinSize"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("NodeAutoScalingGroupMaxSize"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("ClusterName"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("NodeGroupName"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("ClusterControlPlaneSecurityGroup"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("NodeSecurityGroup"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("VpcId"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("Subnets"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("NodeInstanceRoleId"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("ClusterAutoscalerEnabled"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:     aws.String("TerminationDetachEnabled"),
-			UsePreviousValue: aws.Bool(true),
-		},
-		{
-			ParameterKey:   aws.String("BootstrapArguments"),
-			ParameterValue: aws.String(fmt.Sprintf("--kubelet-extra-args '--node-labels %v'", strings.Join(nodeLabels, ","))),
-		},
-	}
-
-	{
-		param := &cloudformation.Parameter{
-			ParameterKey: aws.String("NodeImageId"),
+		previousStackParameter("KeyName"),
+		previousStackParameter("ClusterName"),
+		previousStackParameter("NodeGroupName"),
+		previousStackParameter("ClusterControlPlaneSecurityGroup"),
+		previousStackParameter("NodeSecurityGroup"),
+		previousStackParameter("VpcId"),
+		previousStackParameter("Subnets"),
+		previousStackParameter("NodeInstanceRoleId"),
+		previousStackParameter("TerminationDetachEnabled"),
+	}
+
+	// Launch configuration parameters (changing them, except for taints, requires replacing the nodes)
+	launchConfig := []struct {
+		key          string
+		value        string
+		defaultValue string
+
+		// explicit values are applied even when empty (eg. an empty list of taints removes them)
+		explicit bool
+	}{
+		{key: "NodeImageId", value: input.NodeImage},
+		{key: "NodeInstanceType", value: input.InstanceType},
+		{key: "NodeSpotPrice", value: input.SpotPrice},
+		{key: "NodeVolumeSize", value: formatPositiveInt(input.VolumeSize), defaultValue: strconv.Itoa(eks.DefaultNodePoolVolumeSize)},
+		{key: "NodeVolumeType", value: input.VolumeType, defaultValue: eks.VolumeTypeGP2},
+		{key: "NodeExtraSecurityGroups", value: strings.Join(input.SecurityGroups, ",")},
+		{key: "NodeTaints", value: formatTaints(input.Taints), explicit: input.Taints != nil},
+	}
+
+	launchConfigValues := make(map[string]string, len(launchConfig))
+
+	for _, p := range launchConfig {
+		value := p.value
+
+		// Spot price 0 means on-demand instances
+		if p.key == "NodeSpotPrice" && value != "" {
+			if price, err := strconv.ParseFloat(value, 64); err == nil && price <= 0 {
+				value = ""
+			}
+
+			stackParams = append(stackParams, stackParameter(p.key, value))
+			launchConfigValues[p.key] = value
+
+			continue
 		}
 
-		if input.NodeImage != "" {
-			param.ParameterValue = aws.String(input.NodeImage)
-		} else {
-			param.UsePreviousValue = aws.Bool(true)
+		if value != "" || p.explicit {
+			stackParams = append(stackParams, stackParameter(p.key, value))
+			launchConfigValues[p.key] = value
+
+			continue
 		}
 
-		stackParams = append(stackParams, param)
-	}
+		// Parameters introduced after the stack was created are left on their default values
+		previousValue, ok := previousParams[p.key]
+		if !ok {
+			launchConfigValues[p.key] = p.defaultValue
 
-	{
-		param := &cloudformation.Parameter{
-			ParameterKey: aws.String("NodeAutoScalingInitSize"),
+			continue
 		}
 
-		if input.DesiredCapacity > 0 {
-			param.ParameterValue = aws.String(fmt.Sprintf("%d", input.DesiredCapacity))
-		} else {
-			param.UsePreviousValue = aws.Bool(true)
+		stackParams = append(stackParams, previousStackParameter(p.key))
+		launchConfigValues[p.key] = previousValue
+	}
+
+	nodePoolVersion := eks.CalculateNodePoolLaunchConfigVersion(
+		launchConfigValues["NodeImageId"],
+		launchConfigValues["NodeInstanceType"],
+		launchConfigValues["NodeSpotPrice"],
+		launchConfigValues["NodeVolumeSize"],
+		launchConfigValues["NodeVolumeType"],
+		launchConfigValues["NodeExtraSecurityGroups"],
+	)
+
+	switch {
+	case input.Autoscaling.Enabled:
+		desiredCapacity := input.Autoscaling.MinSize
+
+		if previousDesiredCapacity, err := strconv.Atoi(previousParams["NodeAutoScalingInitSize"]); err == nil {
+			desiredCapacity = previousDesiredCapacity
+
+			if desiredCapacity < input.Autoscaling.MinSize {
+				desiredCapacity = input.Autoscaling.MinSize
+			} else if desiredCapacity > input.Autoscaling.MaxSize {
+				desiredCapacity = input.Autoscaling.MaxSize
+			}
 		}
 
-		stackParams = append(stackParams, param)
+		// Cluster Autoscaler is disabled on all node pools if scale options are enabled on the cluster
+		clusterAutoscalerEnabled := previousParams["TerminationDetachEnabled"] != "true"
+
+		stackParams = append(
+			stackParams,
+			stackParameter("NodeAutoScalingGroupMinSize", fmt.Sprint(input.Autoscaling.MinSize)),
+			stackParameter("NodeAutoScalingGroupMaxSize", fmt.Sprint(input.Autoscaling.MaxSize)),
+			stackParameter("NodeAutoScalingInitSize", fmt.Sprint(desiredCapacity)),
+			stackParameter("ClusterAutoscalerEnabled", fmt.Sprint(clusterAutoscalerEnabled)),
+		)
+
+	case input.Size > 0:
+		stackParams = append(
+			stackParams,
+			stackParameter("NodeAutoScalingGroupMinSize", fmt.Sprint(input.Size)),
+			stackParameter("NodeAutoScalingGroupMaxSize", fmt.Sprint(input.Size+1)),
+			stackParameter("NodeAutoScalingInitSize", fmt.Sprint(input.Size)),
+			stackParameter("ClusterAutoscalerEnabled", "false"),
+		)
+
+	default:
+		stackParams = append(
+			stackParams,
+			previousStackParameter("NodeAutoScalingGroupMinSize"),
+			previousStackParameter("NodeAutoScalingGroupMaxSize"),
+			previousStackParameter("NodeAutoScalingInitSize"),
+			previousStackParameter("ClusterAutoscalerEnabled"),
+		)
+	}
+
+	nodeLabels := []string{
+		fmt.Sprintf("%v=%v", cluster.NodePoolNameLabelKey, input.NodePoolName),
+		fmt.Sprintf("%v=%v", cluster.NodePoolVersionLabelKey, nodePoolVersion),
 	}
 
+	kubeletArgs := fmt.Sprintf("--node-labels %v", strings.Join(nodeLabels, ","))
+
+	if taints := launchConfigValues["NodeTaints"]; taints != "" {
+		kubeletArgs += fmt.Sprintf(" --register-with-taints %v", taints)
+	}
+
+	stackParams = append(stackParams, stackParameter("BootstrapArguments", fmt.Sprintf("--kubelet-extra-args '%v'", kubeletArgs)))
+
 	// we don't reuse the creation time template, since it may have changed
 	updateStackInput := &cloudformation.UpdateStackInput{
 		ClientRequestToken: aws.String(activity.GetInfo(ctx).WorkflowExecution.ID),
@@ -197,7 +257,7 @@ func (a UpdateNodeGroupActivity) Execute(ctx context.Context, input UpdateNodeGr
 	_, err = cloudformationClient.UpdateStack(updateStackInput)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "ValidationError" && strings.HasPrefix(awsErr.Message(), awsNoUpdatesError) {
-			return UpdateNodeGroupActivityOutput{}, nil
+			return UpdateNodeGroupActivityOutput{NodePoolVersion: nodePoolVersion}, nil
 		}
 
 		var awsErr awserr.Error
@@ -213,5 +273,37 @@ func (a UpdateNodeGroupActivity) Execute(ctx context.Context, input UpdateNodeGr
 		}
 	}
 
-	return UpdateNodeGroupActivityOutput{NodePoolChanged: true}, nil
+	return UpdateNodeGroupActivityOutput{NodePoolChanged: true, NodePoolVersion: nodePoolVersion}, nil
+}
+
+func stackParameter(key string, value string) *cloudformation.Parameter {
+	return &cloudformation.Parameter{
+		ParameterKey:   aws.String(key),
+		ParameterValue: aws.String(value),
+	}
+}
+
+func previousStackParameter(key string) *cloudformation.Parameter {
+	return &cloudformation.Parameter{
+		ParameterKey:     aws.String(key),
+		UsePreviousValue: aws.Bool(true),
+	}
+}
+
+func formatPositiveInt(i int) string {
+	if i <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(i)
+}
+
+func formatTaints(taints []cluster.Taint) string {
+	formatted := make([]string, 0, len(taints))
+
+	for _, taint := range taints {
+		formatted = append(formatted, taint.String())
+	}
+
+	return strings.Join(formatted, ",")
 }
@@ -0,0 +1,128 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"go.uber.org/cadence/activity"
+)
+
+const WaitClusterVersionUpdateActivityName = "eks-wait-cluster-version-update"
+
+// WaitClusterVersionUpdateActivity waits for an EKS control plane version update to complete.
+type WaitClusterVersionUpdateActivity struct {
+	sessionFactory AWSSessionFactory
+}
+
+// WaitClusterVersionUpdateActivityInput holds the parameters for waiting for the control plane update.
+type WaitClusterVersionUpdateActivityInput struct {
+	SecretID string
+	Region   string
+
+	ClusterName string
+	UpdateID    string
+}
+
+// NewWaitClusterVersionUpdateActivity creates a new WaitClusterVersionUpdateActivity instance.
+func NewWaitClusterVersionUpdateActivity(sessionFactory AWSSessionFactory) WaitClusterVersionUpdateActivity {
+	return WaitClusterVersionUpdateActivity{
+		sessionFactory: sessionFactory,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a WaitClusterVersionUpdateActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: WaitClusterVersionUpdateActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a WaitClusterVersionUpdateActivity) Execute(ctx context.Context, input WaitClusterVersionUpdateActivityInput) error {
+	sess, err := a.sessionFactory.NewSession(input.SecretID, input.Region)
+	if err = errors.WrapIf(err, "failed to create AWS session"); err != nil { // internal error?
+		return err
+	}
+
+	eksClient := eks.New(sess)
+
+	describeUpdateInput := &eks.DescribeUpdateInput{
+		Name:     aws.String(input.ClusterName),
+		UpdateId: aws.String(input.UpdateID),
+	}
+
+	count := 0
+	if activity.HasHeartbeatDetails(ctx) {
+		_ = activity.GetHeartbeatDetails(ctx, &count)
+	}
+
+	w := request.Waiter{
+		Name:        "WaitUntilClusterUpdateComplete",
+		MaxAttempts: 180 - count,
+		Delay:       request.ConstantWaiterDelay(30 * time.Second),
+		Acceptors: []request.WaiterAcceptor{
+			{
+				State:   request.SuccessWaiterState,
+				Matcher: request.PathWaiterMatch, Argument: "Update.Status",
+				Expected: eks.UpdateStatusSuccessful,
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathWaiterMatch, Argument: "Update.Status",
+				Expected: eks.UpdateStatusFailed,
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathWaiterMatch, Argument: "Update.Status",
+				Expected: eks.UpdateStatusCancelled,
+			},
+		},
+		Logger: eksClient.Config.Logger,
+		NewRequest: func(opts []request.Option) (*request.Request, error) {
+			count++
+			activity.RecordHeartbeat(ctx, count)
+
+			req, _ := eksClient.DescribeUpdateRequest(describeUpdateInput)
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+
+			return req, nil
+		},
+	}
+
+	err = w.WaitWithContext(ctx)
+	if err != nil {
+		details := []interface{}{"cluster", input.ClusterName, "updateId", input.UpdateID}
+
+		output, derr := eksClient.DescribeUpdateWithContext(ctx, describeUpdateInput)
+		if derr == nil && output.Update != nil {
+			var messages []string
+			for _, updateErr := range output.Update.Errors {
+				messages = append(messages, aws.StringValue(updateErr.ErrorMessage))
+			}
+
+			details = append(details, "status", aws.StringValue(output.Update.Status), "errors", strings.Join(messages, "; "))
+		}
+
+		return errors.WrapIfWithDetails(err, "waiting for EKS cluster version update to complete failed", details...)
+	}
+
+	return nil
+}
@@ -85,3 +85,8 @@ func packageCFError(err error, stackName string, clientRequestToken string, clou
 	}
 	return err
 }
+
+// TODO: this is temporary
+func generateNodePoolStackName(clusterName string, poolName string) string {
+	return "pipeline-eks-nodepool-" + clusterName + "-" + poolName
+}
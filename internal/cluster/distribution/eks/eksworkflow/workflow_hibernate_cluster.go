@@ -0,0 +1,197 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package eksworkflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/pkg/sdk/brn"
+	"github.com/banzaicloud/pipeline/pkg/sdk/cadence/lib/pipeline/processlog"
+)
+
+const HibernateClusterWorkflowName = "eks-hibernate-cluster"
+
+// HibernateClusterWorkflow scales every node pool of an EKS cluster to zero.
+//
+// The scaling settings of the node pools are recorded first, so that they can be restored on resume.
+type HibernateClusterWorkflow struct {
+	processLogger processlog.ProcessLogger
+}
+
+// NewHibernateClusterWorkflow returns a new HibernateClusterWorkflow.
+func NewHibernateClusterWorkflow(processLogger processlog.ProcessLogger) HibernateClusterWorkflow {
+	return HibernateClusterWorkflow{
+		processLogger: processLogger,
+	}
+}
+
+type HibernateClusterWorkflowInput struct {
+	OrganizationID uint
+	ClusterID      uint
+
+	// KeepNodePool is kept running with a single node.
+	KeepNodePool string
+
+	// Scheduled is true when the workflow is started by a cron schedule.
+	Scheduled bool
+}
+
+func (w HibernateClusterWorkflow) Register() {
+	workflow.RegisterWithOptions(w.Execute, workflow.RegisterOptions{Name: HibernateClusterWorkflowName})
+}
+
+func (w HibernateClusterWorkflow) Execute(ctx workflow.Context, input HibernateClusterWorkflowInput) (err error) {
+	activityOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: time.Duration(workflow.GetInfo(ctx).ExecutionStartToCloseTimeoutSeconds) * time.Second,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var output PrepareHibernateClusterActivityOutput
+	{
+		activityInput := PrepareHibernateClusterActivityInput{
+			ClusterID: input.ClusterID,
+			Scheduled: input.Scheduled,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 30 * time.Second
+
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			PrepareHibernateClusterActivityName,
+			activityInput,
+		).Get(ctx, &output)
+		if err != nil {
+			if !input.Scheduled {
+				_ = setClusterStatus(ctx, input.ClusterID, cluster.Warning, fmt.Sprintf("failed to hibernate cluster: %s", err.Error()))
+			}
+
+			return err
+		}
+
+		if output.Skip {
+			return nil
+		}
+	}
+
+	clusterID := brn.New(input.OrganizationID, brn.ClusterResourceType, fmt.Sprint(input.ClusterID))
+
+	process := w.processLogger.StartProcess(ctx, clusterID.String())
+	defer func() {
+		process.Finish(ctx, err)
+	}()
+	defer func() {
+		status := cluster.Hibernated
+		statusMessage := cluster.HibernatedMessage
+
+		if err != nil {
+			if cadence.IsCanceledError(err) {
+				ctx, _ = workflow.NewDisconnectedContext(ctx)
+			}
+
+			status = cluster.Warning
+			statusMessage = fmt.Sprintf("failed to hibernate cluster: %s", err.Error())
+		}
+
+		_ = setClusterStatus(ctx, input.ClusterID, status, statusMessage)
+	}()
+
+	for _, nodePool := range output.NodePools {
+		activityInput := ScaleNodeGroupActivityInput{
+			SecretID:    output.ProviderSecretID,
+			Region:      output.Region,
+			ClusterName: output.ClusterName,
+			StackName:   generateNodePoolStackName(output.ClusterName, nodePool.Name),
+		}
+
+		if nodePool.Name == input.KeepNodePool {
+			activityInput.MinSize = 1
+			activityInput.MaxSize = 1
+			activityInput.DesiredCapacity = 1
+		}
+
+		err = scaleNodeGroup(ctx, activityOptions, process, activityInput)
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+// scaleNodeGroup updates the scaling settings of a node group and waits for the update to complete.
+func scaleNodeGroup(
+	ctx workflow.Context,
+	activityOptions workflow.ActivityOptions,
+	process processlog.Process,
+	input ScaleNodeGroupActivityInput,
+) (err error) {
+	var output ScaleNodeGroupActivityOutput
+	{
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 5 * time.Minute
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:          20 * time.Second,
+			BackoffCoefficient:       1.1,
+			MaximumAttempts:          10,
+			NonRetriableErrorReasons: []string{"cadenceInternal:Panic", ErrReasonStackFailed},
+		}
+
+		processActivity := process.StartActivity(ctx, ScaleNodeGroupActivityName)
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			ScaleNodeGroupActivityName,
+			input,
+		).Get(ctx, &output)
+		processActivity.Finish(ctx, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !output.NodePoolChanged {
+		return nil
+	}
+
+	activityInput := WaitCloudFormationStackUpdateActivityInput{
+		SecretID:  input.SecretID,
+		Region:    input.Region,
+		StackName: input.StackName,
+	}
+
+	activityOptions.StartToCloseTimeout = 100 * time.Minute
+	activityOptions.HeartbeatTimeout = time.Minute
+	activityOptions.RetryPolicy = &cadence.RetryPolicy{
+		InitialInterval:          20 * time.Second,
+		BackoffCoefficient:       1.1,
+		MaximumAttempts:          20,
+		NonRetriableErrorReasons: []string{"cadenceInternal:Panic"},
+	}
+
+	processActivity := process.StartActivity(ctx, WaitCloudFormationStackUpdateActivityName)
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, activityOptions),
+		WaitCloudFormationStackUpdateActivityName,
+		activityInput,
+	).Get(ctx, nil)
+	processActivity.Finish(ctx, err)
+
+	return err
+}
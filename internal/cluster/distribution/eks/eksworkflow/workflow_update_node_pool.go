@@ -22,6 +22,9 @@ import (
 	"go.uber.org/cadence/workflow"
 
 	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterworkflow"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks"
+	_cadence "github.com/banzaicloud/pipeline/pkg/cadence"
 	"github.com/banzaicloud/pipeline/pkg/sdk/brn"
 	"github.com/banzaicloud/pipeline/pkg/sdk/cadence/lib/pipeline/processlog"
 )
@@ -51,7 +54,23 @@ type UpdateNodePoolWorkflowInput struct {
 	ClusterName     string
 	NodePoolName    string
 
-	NodeImage string
+	// Node pool changes (zero values leave the current settings intact)
+	Size           int
+	Autoscaling    eks.NodePoolAutoscaling
+	Labels         map[string]string
+	InstanceType   string
+	NodeImage      string
+	SpotPrice      string
+	VolumeSize     int
+	VolumeType     string
+	SecurityGroups []string
+	Taints         []cluster.Taint // nil leaves the taints intact, an empty list removes them
+
+	// Rolling node replacement options
+	ReplaceNodes   bool
+	MaxSurge       int
+	MaxUnavailable int
+	DrainTimeout   time.Duration
 }
 
 func (w UpdateNodePoolWorkflow) Register() {
@@ -72,6 +91,11 @@ func (w UpdateNodePoolWorkflow) Execute(ctx workflow.Context, input UpdateNodePo
 		process.Finish(ctx, err)
 	}()
 	defer func() {
+		// The cluster status is managed by the parent workflow (eg. cluster upgrade)
+		if workflow.GetInfo(ctx).ParentWorkflowExecution != nil {
+			return
+		}
+
 		status := cluster.Running
 		statusMessage := cluster.RunningMessage
 
@@ -87,43 +111,64 @@ func (w UpdateNodePoolWorkflow) Execute(ctx workflow.Context, input UpdateNodePo
 		_ = setClusterStatus(ctx, input.ClusterID, status, statusMessage)
 	}()
 
-	var nodePoolVersion string
-	{
-		activityInput := CalculateNodePoolVersionActivityInput{
-			Image: input.NodeImage,
+	// nil taints are left untouched, an empty list removes every taint of the node pool
+	if len(input.Labels) > 0 || input.Taints != nil {
+		rawNodePool := cluster.NewRawNodePool{
+			"name": input.NodePoolName,
+		}
+
+		if len(input.Labels) > 0 {
+			rawNodePool["labels"] = input.Labels
+		}
+
+		// Taints are reconciled on the existing nodes, new nodes register with them on bootstrap
+		if input.Taints != nil {
+			rawNodePool["taints"] = input.Taints
+		}
+
+		activityInput := clusterworkflow.CreateNodePoolLabelSetActivityInput{
+			ClusterID:   input.ClusterID,
+			RawNodePool: rawNodePool,
 		}
 
 		activityOptions := activityOptions
-		activityOptions.StartToCloseTimeout = 30 * time.Second
+		activityOptions.StartToCloseTimeout = time.Minute
 		activityOptions.RetryPolicy = &cadence.RetryPolicy{
-			InitialInterval:    10 * time.Second,
-			BackoffCoefficient: 1.01,
-			MaximumInterval:    10 * time.Minute,
+			InitialInterval:          10 * time.Second,
+			BackoffCoefficient:       1.1,
+			MaximumAttempts:          10,
+			NonRetriableErrorReasons: []string{_cadence.ClientErrorReason, "cadenceInternal:Panic"},
 		}
 
-		var output CalculateNodePoolVersionActivityOutput
-
+		processActivity := process.StartActivity(ctx, clusterworkflow.CreateNodePoolLabelSetActivityName)
 		err = workflow.ExecuteActivity(
 			workflow.WithActivityOptions(ctx, activityOptions),
-			CalculateNodePoolVersionActivityName,
+			clusterworkflow.CreateNodePoolLabelSetActivityName,
 			activityInput,
-		).Get(ctx, &output)
+		).Get(ctx, nil)
+		processActivity.Finish(ctx, err)
 		if err != nil {
-			return
+			return err
 		}
-
-		nodePoolVersion = output.Version
 	}
 
+	var nodePoolVersion string
 	{
 		activityInput := UpdateNodeGroupActivityInput{
-			SecretID:        input.ProviderSecretID,
-			Region:          input.Region,
-			ClusterName:     input.ClusterName,
-			StackName:       input.StackName,
-			NodePoolName:    input.NodePoolName,
-			NodePoolVersion: nodePoolVersion,
-			NodeImage:       input.NodeImage,
+			SecretID:       input.ProviderSecretID,
+			Region:         input.Region,
+			ClusterName:    input.ClusterName,
+			StackName:      input.StackName,
+			NodePoolName:   input.NodePoolName,
+			Size:           input.Size,
+			Autoscaling:    input.Autoscaling,
+			InstanceType:   input.InstanceType,
+			NodeImage:      input.NodeImage,
+			SpotPrice:      input.SpotPrice,
+			VolumeSize:     input.VolumeSize,
+			VolumeType:     input.VolumeType,
+			SecurityGroups: input.SecurityGroups,
+			Taints:         input.Taints,
 		}
 
 		activityOptions := activityOptions
@@ -144,9 +189,15 @@ func (w UpdateNodePoolWorkflow) Execute(ctx workflow.Context, input UpdateNodePo
 			activityInput,
 		).Get(ctx, &output)
 		processActivity.Finish(ctx, err)
-		if err != nil || !output.NodePoolChanged {
-			return
+		if err != nil {
+			return err
 		}
+
+		if !output.NodePoolChanged {
+			return w.saveNodePool(ctx, activityOptions, input)
+		}
+
+		nodePoolVersion = output.NodePoolVersion
 	}
 
 	{
@@ -178,5 +229,363 @@ func (w UpdateNodePoolWorkflow) Execute(ctx workflow.Context, input UpdateNodePo
 		}
 	}
 
+	if input.ReplaceNodes {
+		err = w.replaceNodes(ctx, process, activityOptions, input, nodePoolVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.saveNodePool(ctx, activityOptions, input)
+}
+
+func (w UpdateNodePoolWorkflow) saveNodePool(
+	ctx workflow.Context,
+	activityOptions workflow.ActivityOptions,
+	input UpdateNodePoolWorkflowInput,
+) error {
+	activityInput := SaveNodePoolActivityInput{
+		ClusterID:    input.ClusterID,
+		NodePoolName: input.NodePoolName,
+		NodePoolUpdate: eks.NodePoolUpdate{
+			Size:         input.Size,
+			Autoscaling:  input.Autoscaling,
+			InstanceType: input.InstanceType,
+			Image:        input.NodeImage,
+			SpotPrice:    input.SpotPrice,
+
+			VolumeSize:     input.VolumeSize,
+			VolumeType:     input.VolumeType,
+			SecurityGroups: input.SecurityGroups,
+			Taints:         input.Taints,
+		},
+	}
+
+	activityOptions.StartToCloseTimeout = time.Minute
+	activityOptions.RetryPolicy = &cadence.RetryPolicy{
+		InitialInterval:    10 * time.Second,
+		BackoffCoefficient: 1.1,
+		MaximumAttempts:    10,
+	}
+
+	return workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, activityOptions),
+		SaveNodePoolActivityName,
+		activityInput,
+	).Get(ctx, nil)
+}
+
+// replaceNodes replaces the nodes of a node pool that are not running the current node pool version
+// following a surge-and-drain strategy.
+//
+// Nodes are replaced in batches of MaxSurge + MaxUnavailable nodes:
+// MaxSurge instances are detached from the auto scaling group (which launches their replacements immediately)
+// and their replacements are waited for, then every node in the batch is cordoned, drained and terminated.
+// The rest of the batch is replaced by the auto scaling group after termination.
+// When a batch fails, its nodes that were not terminated yet are restored.
+func (w UpdateNodePoolWorkflow) replaceNodes(
+	ctx workflow.Context,
+	process processlog.Process,
+	activityOptions workflow.ActivityOptions,
+	input UpdateNodePoolWorkflowInput,
+	nodePoolVersion string,
+) error {
+	configSecretID := brn.New(input.OrganizationID, brn.SecretResourceType, input.ClusterSecretID).String()
+
+	maxSurge, maxUnavailable := input.MaxSurge, input.MaxUnavailable
+	if maxSurge <= 0 && maxUnavailable <= 0 {
+		maxSurge = eks.DefaultNodePoolUpdateMaxSurge
+	}
+
+	drainTimeout := input.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = eks.DefaultNodePoolUpdateDrainTimeout * time.Second
+	}
+
+	var nodes []NodePoolNode
+	{
+		activityInput := ListNodePoolNodesActivityInput{
+			ConfigSecretID: configSecretID,
+			NodePoolName:   input.NodePoolName,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = time.Minute
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:    10 * time.Second,
+			BackoffCoefficient: 1.1,
+			MaximumAttempts:    10,
+		}
+
+		var output ListNodePoolNodesActivityOutput
+
+		err := workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			ListNodePoolNodesActivityName,
+			activityInput,
+		).Get(ctx, &output)
+		if err != nil {
+			return err
+		}
+
+		nodes = output.Nodes
+	}
+
+	var oldNodes []NodePoolNode
+	for _, node := range nodes {
+		if node.Version != nodePoolVersion {
+			oldNodes = append(oldNodes, node)
+		}
+	}
+
+	readyCount := countReadyNodes(nodes, nodePoolVersion)
+
+	waitForReadyNodes := func(count int) error {
+		activityInput := WaitNodePoolNodesReadyActivityInput{
+			ConfigSecretID:  configSecretID,
+			NodePoolName:    input.NodePoolName,
+			NodePoolVersion: nodePoolVersion,
+			Count:           count,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 30 * time.Minute
+		activityOptions.HeartbeatTimeout = 2 * time.Minute
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:          20 * time.Second,
+			BackoffCoefficient:       1.1,
+			MaximumAttempts:          5,
+			NonRetriableErrorReasons: []string{"cadenceInternal:Panic"},
+		}
+
+		processActivity := process.StartActivityWithLog(
+			ctx,
+			WaitNodePoolNodesReadyActivityName,
+			fmt.Sprintf("waiting for %d ready nodes", count),
+		)
+		err := workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			WaitNodePoolNodesReadyActivityName,
+			activityInput,
+		).Get(ctx, nil)
+		processActivity.Finish(ctx, err)
+
+		return err
+	}
+
+	// replaceBatch returns the number of nodes in the batch that were terminated (the rest has to be restored on error)
+	replaceBatch := func(batch []NodePoolNode, surge int) (int, error) {
+		if surge > 0 {
+			activityInput := DetachNodeInstancesActivityInput{
+				SecretID:  input.ProviderSecretID,
+				Region:    input.Region,
+				StackName: input.StackName,
+			}
+
+			for _, node := range batch[:surge] {
+				activityInput.InstanceIDs = append(activityInput.InstanceIDs, node.InstanceID)
+			}
+
+			activityOptions := activityOptions
+			activityOptions.StartToCloseTimeout = 2 * time.Minute
+			activityOptions.RetryPolicy = &cadence.RetryPolicy{
+				InitialInterval:    20 * time.Second,
+				BackoffCoefficient: 1.1,
+				MaximumAttempts:    10,
+			}
+
+			processActivity := process.StartActivity(ctx, DetachNodeInstancesActivityName)
+			err := workflow.ExecuteActivity(
+				workflow.WithActivityOptions(ctx, activityOptions),
+				DetachNodeInstancesActivityName,
+				activityInput,
+			).Get(ctx, nil)
+			processActivity.Finish(ctx, err)
+			if err != nil {
+				return 0, err
+			}
+
+			readyCount += surge
+
+			err = waitForReadyNodes(readyCount)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		for i, node := range batch {
+			{
+				activityInput := DrainNodeActivityInput{
+					ConfigSecretID: configSecretID,
+					NodeName:       node.Name,
+					Timeout:        drainTimeout,
+				}
+
+				activityOptions := activityOptions
+				activityOptions.StartToCloseTimeout = drainTimeout + 5*time.Minute
+				activityOptions.HeartbeatTimeout = time.Minute
+				activityOptions.RetryPolicy = &cadence.RetryPolicy{
+					InitialInterval:    20 * time.Second,
+					BackoffCoefficient: 1.1,
+					MaximumAttempts:    3,
+				}
+
+				processActivity := process.StartActivityWithLog(ctx, DrainNodeActivityName, node.Name)
+				err := workflow.ExecuteActivity(
+					workflow.WithActivityOptions(ctx, activityOptions),
+					DrainNodeActivityName,
+					activityInput,
+				).Get(ctx, nil)
+				processActivity.Finish(ctx, err)
+				if err != nil {
+					return i, err
+				}
+			}
+
+			{
+				activityInput := TerminateNodeInstanceActivityInput{
+					SecretID:   input.ProviderSecretID,
+					Region:     input.Region,
+					InstanceID: node.InstanceID,
+					Detached:   i < surge,
+				}
+
+				activityOptions := activityOptions
+				activityOptions.StartToCloseTimeout = 2 * time.Minute
+				activityOptions.RetryPolicy = &cadence.RetryPolicy{
+					InitialInterval:    20 * time.Second,
+					BackoffCoefficient: 1.1,
+					MaximumAttempts:    10,
+				}
+
+				processActivity := process.StartActivityWithLog(ctx, TerminateNodeInstanceActivityName, node.Name)
+				err := workflow.ExecuteActivity(
+					workflow.WithActivityOptions(ctx, activityOptions),
+					TerminateNodeInstanceActivityName,
+					activityInput,
+				).Get(ctx, nil)
+				processActivity.Finish(ctx, err)
+				if err != nil {
+					return i, err
+				}
+			}
+		}
+
+		// Wait for the auto scaling group to replace the terminated instances that were not detached
+		if unavailable := len(batch) - surge; unavailable > 0 {
+			readyCount += unavailable
+
+			err := waitForReadyNodes(readyCount)
+			if err != nil {
+				return len(batch), err
+			}
+		}
+
+		return len(batch), nil
+	}
+
+	for len(oldNodes) > 0 {
+		batchSize := maxSurge + maxUnavailable
+		if batchSize > len(oldNodes) {
+			batchSize = len(oldNodes)
+		}
+
+		batch := oldNodes[:batchSize]
+		oldNodes = oldNodes[batchSize:]
+
+		surge := maxSurge
+		if surge > len(batch) {
+			surge = len(batch)
+		}
+
+		replaced, err := replaceBatch(batch, surge)
+		if err != nil {
+			detached := surge - replaced
+			if detached < 0 {
+				detached = 0
+			}
+
+			// The restore has to run even if the workflow is canceled
+			restoreCtx, _ := workflow.NewDisconnectedContext(ctx)
+
+			w.restoreNodes(restoreCtx, activityOptions, input, configSecretID, batch[replaced:], detached)
+
+			return err
+		}
+	}
+
 	return nil
 }
+
+// restoreNodes puts back the nodes of a batch that could not be replaced,
+// so that detached instances do not leak outside the auto scaling group and no node is left cordoned.
+//
+// The first detached nodes were detached from the auto scaling group: they are attached again
+// or, if that fails (eg. the group is at its maximum size), terminated.
+// Every other node is uncordoned. Failures are logged, the update fails anyway.
+func (w UpdateNodePoolWorkflow) restoreNodes(
+	ctx workflow.Context,
+	activityOptions workflow.ActivityOptions,
+	input UpdateNodePoolWorkflowInput,
+	configSecretID string,
+	nodes []NodePoolNode,
+	detached int,
+) {
+	logger := workflow.GetLogger(ctx).Sugar()
+
+	activityOptions.StartToCloseTimeout = 2 * time.Minute
+	activityOptions.RetryPolicy = &cadence.RetryPolicy{
+		InitialInterval:    20 * time.Second,
+		BackoffCoefficient: 1.1,
+		MaximumAttempts:    5,
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	attached := true
+	if detached > 0 {
+		activityInput := AttachNodeInstancesActivityInput{
+			SecretID:  input.ProviderSecretID,
+			Region:    input.Region,
+			StackName: input.StackName,
+		}
+
+		for _, node := range nodes[:detached] {
+			activityInput.InstanceIDs = append(activityInput.InstanceIDs, node.InstanceID)
+		}
+
+		err := workflow.ExecuteActivity(ctx, AttachNodeInstancesActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			logger.Warnw("failed to attach detached instances, terminating them", "instances", activityInput.InstanceIDs, "error", err)
+
+			attached = false
+		}
+	}
+
+	for i, node := range nodes {
+		if i < detached && !attached {
+			activityInput := TerminateNodeInstanceActivityInput{
+				SecretID:   input.ProviderSecretID,
+				Region:     input.Region,
+				InstanceID: node.InstanceID,
+				Detached:   true,
+			}
+
+			err := workflow.ExecuteActivity(ctx, TerminateNodeInstanceActivityName, activityInput).Get(ctx, nil)
+			if err != nil {
+				logger.Errorw("failed to terminate detached instance", "instance", node.InstanceID, "error", err)
+			}
+
+			continue
+		}
+
+		activityInput := UncordonNodeActivityInput{
+			ConfigSecretID: configSecretID,
+			NodeName:       node.Name,
+		}
+
+		err := workflow.ExecuteActivity(ctx, UncordonNodeActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			logger.Errorw("failed to uncordon node", "node", node.Name, "error", err)
+		}
+	}
+}
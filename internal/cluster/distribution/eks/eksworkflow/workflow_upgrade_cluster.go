@@ -0,0 +1,273 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eksworkflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/pkg/sdk/brn"
+	"github.com/banzaicloud/pipeline/pkg/sdk/cadence/lib/pipeline/processlog"
+)
+
+const UpgradeClusterWorkflowName = "eks-upgrade-cluster"
+
+// UpgradeClusterWorkflow upgrades the Kubernetes version of an EKS cluster.
+//
+// The control plane is upgraded first, then the add-ons, then every node pool is rolled to the new node image.
+type UpgradeClusterWorkflow struct {
+	processLogger processlog.ProcessLogger
+}
+
+// NewUpgradeClusterWorkflow returns a new UpgradeClusterWorkflow.
+func NewUpgradeClusterWorkflow(processLogger processlog.ProcessLogger) UpgradeClusterWorkflow {
+	return UpgradeClusterWorkflow{
+		processLogger: processLogger,
+	}
+}
+
+type UpgradeClusterWorkflowInput struct {
+	ProviderSecretID string
+	Region           string
+
+	OrganizationID  uint
+	ClusterID       uint
+	ClusterSecretID string
+	ClusterName     string
+
+	KubernetesVersion string
+	NodeImage         string
+	NodePools         []UpgradeClusterNodePool
+}
+
+// UpgradeClusterNodePool describes a node pool upgraded as part of the cluster upgrade.
+type UpgradeClusterNodePool struct {
+	Name      string
+	StackName string
+}
+
+func (w UpgradeClusterWorkflow) Register() {
+	workflow.RegisterWithOptions(w.Execute, workflow.RegisterOptions{Name: UpgradeClusterWorkflowName})
+}
+
+func (w UpgradeClusterWorkflow) Execute(ctx workflow.Context, input UpgradeClusterWorkflowInput) (err error) {
+	activityOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: time.Duration(workflow.GetInfo(ctx).ExecutionStartToCloseTimeoutSeconds) * time.Second,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	clusterID := brn.New(input.OrganizationID, brn.ClusterResourceType, fmt.Sprint(input.ClusterID))
+
+	process := w.processLogger.StartProcess(ctx, clusterID.String())
+	defer func() {
+		process.Finish(ctx, err)
+	}()
+	defer func() {
+		status := cluster.Running
+		statusMessage := cluster.RunningMessage
+
+		if err != nil {
+			if cadence.IsCanceledError(err) {
+				ctx, _ = workflow.NewDisconnectedContext(ctx)
+			}
+
+			status = cluster.Warning
+			statusMessage = fmt.Sprintf("failed to upgrade cluster: %s", err.Error())
+		}
+
+		_ = setClusterStatus(ctx, input.ClusterID, status, statusMessage)
+	}()
+
+	var updateID string
+	{
+		activityInput := UpdateClusterVersionActivityInput{
+			SecretID:          input.ProviderSecretID,
+			Region:            input.Region,
+			ClusterName:       input.ClusterName,
+			KubernetesVersion: input.KubernetesVersion,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 5 * time.Minute
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:          20 * time.Second,
+			BackoffCoefficient:       1.1,
+			MaximumAttempts:          10,
+			NonRetriableErrorReasons: []string{"cadenceInternal:Panic"},
+		}
+
+		var output UpdateClusterVersionActivityOutput
+
+		processActivity := process.StartActivity(ctx, UpdateClusterVersionActivityName)
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			UpdateClusterVersionActivityName,
+			activityInput,
+		).Get(ctx, &output)
+		processActivity.Finish(ctx, err)
+		if err != nil {
+			return
+		}
+
+		updateID = output.UpdateID
+	}
+
+	if updateID != "" {
+		activityInput := WaitClusterVersionUpdateActivityInput{
+			SecretID:    input.ProviderSecretID,
+			Region:      input.Region,
+			ClusterName: input.ClusterName,
+			UpdateID:    updateID,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 90 * time.Minute
+		activityOptions.HeartbeatTimeout = 2 * time.Minute
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:          20 * time.Second,
+			BackoffCoefficient:       1.1,
+			MaximumAttempts:          20,
+			NonRetriableErrorReasons: []string{"cadenceInternal:Panic"},
+		}
+
+		processActivity := process.StartActivity(ctx, WaitClusterVersionUpdateActivityName)
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			WaitClusterVersionUpdateActivityName,
+			activityInput,
+		).Get(ctx, nil)
+		processActivity.Finish(ctx, err)
+		if err != nil {
+			return
+		}
+	}
+
+	{
+		activityInput := SaveClusterVersionActivityInput{
+			ClusterID:         input.ClusterID,
+			KubernetesVersion: input.KubernetesVersion,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 30 * time.Second
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:    10 * time.Second,
+			BackoffCoefficient: 1.01,
+			MaximumInterval:    10 * time.Minute,
+			MaximumAttempts:    30,
+		}
+
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			SaveClusterVersionActivityName,
+			activityInput,
+		).Get(ctx, nil)
+		if err != nil {
+			return
+		}
+	}
+
+	{
+		activityInput := UpdateAddonsActivityInput{
+			ConfigSecretID:    brn.New(input.OrganizationID, brn.SecretResourceType, input.ClusterSecretID).String(),
+			KubernetesVersion: input.KubernetesVersion,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 5 * time.Minute
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:          20 * time.Second,
+			BackoffCoefficient:       1.1,
+			MaximumAttempts:          10,
+			NonRetriableErrorReasons: []string{"cadenceInternal:Panic"},
+		}
+
+		processActivity := process.StartActivity(ctx, UpdateAddonsActivityName)
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			UpdateAddonsActivityName,
+			activityInput,
+		).Get(ctx, nil)
+		processActivity.Finish(ctx, err)
+		if err != nil {
+			return
+		}
+	}
+
+	// Node pools are rolled one by one to keep enough capacity in the cluster
+	for _, nodePool := range input.NodePools {
+		childWorkflowOptions := workflow.ChildWorkflowOptions{
+			ExecutionStartToCloseTimeout: time.Duration(workflow.GetInfo(ctx).ExecutionStartToCloseTimeoutSeconds) * time.Second,
+			TaskStartToCloseTimeout:      time.Minute,
+		}
+
+		childWorkflowInput := UpdateNodePoolWorkflowInput{
+			ProviderSecretID: input.ProviderSecretID,
+			Region:           input.Region,
+
+			StackName: nodePool.StackName,
+
+			OrganizationID:  input.OrganizationID,
+			ClusterID:       input.ClusterID,
+			ClusterSecretID: input.ClusterSecretID,
+			ClusterName:     input.ClusterName,
+			NodePoolName:    nodePool.Name,
+
+			NodeImage: input.NodeImage,
+
+			ReplaceNodes: true,
+		}
+
+		err = workflow.ExecuteChildWorkflow(
+			workflow.WithChildOptions(ctx, childWorkflowOptions),
+			UpdateNodePoolWorkflowName,
+			childWorkflowInput,
+		).Get(ctx, nil)
+		if err != nil {
+			return
+		}
+
+		activityInput := SaveNodePoolImageActivityInput{
+			ClusterID:    input.ClusterID,
+			NodePoolName: nodePool.Name,
+			NodeImage:    input.NodeImage,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 30 * time.Second
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:    10 * time.Second,
+			BackoffCoefficient: 1.01,
+			MaximumInterval:    10 * time.Minute,
+			MaximumAttempts:    30,
+		}
+
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			SaveNodePoolImageActivityName,
+			activityInput,
+		).Get(ctx, nil)
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
@@ -18,24 +18,29 @@ import (
 	"context"
 	"crypto/sha1"
 	"fmt"
+	"strconv"
 
 	"github.com/banzaicloud/pipeline/internal/cluster"
 )
 
 // NewNodePool describes new a Kubernetes node pool in an Amazon EKS cluster.
 type NewNodePool struct {
-	Name        string            `mapstructure:"name"`
-	Labels      map[string]string `mapstructure:"labels"`
-	Size        int               `mapstructure:"size"`
-	Autoscaling struct {
-		Enabled bool `mapstructure:"enabled"`
-		MinSize int  `mapstructure:"minSize"`
-		MaxSize int  `mapstructure:"maxSize"`
-	} `mapstructure:"autoscaling"`
-	InstanceType string `mapstructure:"instanceType"`
-	Image        string `mapstructure:"image"`
-	SpotPrice    string `mapstructure:"spotPrice"`
-	SubnetID     string `mapstructure:"subnetId"`
+	Name         string              `mapstructure:"name"`
+	Labels       map[string]string   `mapstructure:"labels"`
+	Taints       []cluster.Taint     `mapstructure:"taints"`
+	Size         int                 `mapstructure:"size"`
+	Autoscaling  NodePoolAutoscaling `mapstructure:"autoscaling"`
+	InstanceType string              `mapstructure:"instanceType"`
+	Image        string              `mapstructure:"image"`
+	SpotPrice    string              `mapstructure:"spotPrice"`
+	SubnetID     string              `mapstructure:"subnetId"`
+}
+
+// NodePoolAutoscaling describes the autoscaling settings of a node pool.
+type NodePoolAutoscaling struct {
+	Enabled bool `mapstructure:"enabled"`
+	MinSize int  `mapstructure:"minSize"`
+	MaxSize int  `mapstructure:"maxSize"`
 }
 
 // Validate semantically validates the new node pool.
@@ -49,7 +54,7 @@ func (n NewNodePool) Validate() error {
 			violations = append(violations, "minimum autoscaling size cannot be lower than one")
 		}
 
-		if n.Autoscaling.MaxSize <= n.Autoscaling.MinSize {
+		if n.Autoscaling.MaxSize < n.Autoscaling.MinSize {
 			violations = append(violations, "maximum autoscaling size cannot be lower than the minimum")
 		}
 	} else if n.Size < 1 {
@@ -60,6 +65,8 @@ func (n NewNodePool) Validate() error {
 		violations = append(violations, "instance type cannot be empty")
 	}
 
+	violations = append(violations, cluster.ValidateTaints(n.Taints)...)
+
 	if len(violations) > 0 {
 		return cluster.NewValidationError("invalid node pool creation request", violations)
 	}
@@ -67,10 +74,90 @@ func (n NewNodePool) Validate() error {
 	return nil
 }
 
+// NodePool describes an existing Kubernetes node pool in an Amazon EKS cluster.
+type NodePool struct {
+	Name         string
+	Labels       map[string]string
+	Size         int
+	Autoscaling  NodePoolAutoscaling
+	InstanceType string
+	Image        string
+	SpotPrice    string
+	Taints       []cluster.Taint
+
+	// Zero values mean the defaults of the node pool stack template.
+	VolumeSize     int
+	VolumeType     string
+	SecurityGroups []string
+}
+
+// GetName returns the node pool name.
+func (n NodePool) GetName() string {
+	return n.Name
+}
+
+// GetInstanceType returns the node pool instance type.
+func (n NodePool) GetInstanceType() string {
+	return n.InstanceType
+}
+
+// IsOnDemand determines whether the machines in the node pool are on demand or spot instances.
+func (n NodePool) IsOnDemand() bool {
+	price, err := strconv.ParseFloat(n.SpotPrice, 64)
+
+	return err != nil || price <= 0.0
+}
+
+// GetLabels returns labels that are/should be applied to every node in the pool.
+func (n NodePool) GetLabels() map[string]string {
+	return n.Labels
+}
+
 // NodePoolStore provides an interface for EKS node pool persistence.
 type NodePoolStore interface {
 	// CreateNodePool saves a new node pool.
 	CreateNodePool(ctx context.Context, clusterID uint, createdBy uint, nodePool NewNodePool) error
+
+	// GetNodePool returns an existing node pool.
+	GetNodePool(ctx context.Context, clusterID uint, nodePoolName string) (NodePool, error)
+
+	// ListNodePoolNames returns the names of the node pools in a cluster.
+	ListNodePoolNames(ctx context.Context, clusterID uint) ([]string, error)
+
+	// UpdateNodePoolImage updates the image of an existing node pool.
+	UpdateNodePoolImage(ctx context.Context, clusterID uint, nodePoolName string, image string) error
+
+	// UpdateNodePool saves the persisted properties of a node pool update.
+	UpdateNodePool(ctx context.Context, clusterID uint, nodePoolName string, nodePoolUpdate NodePoolUpdate) error
+}
+
+// CalculateNodePoolLaunchConfigVersion calculates the version of a node pool
+// from the launch configuration values that require node replacement when changed.
+//
+// Empty values are replaced with the defaults of the node pool stack template,
+// so that node pools created and updated with the same settings get the same version.
+func CalculateNodePoolLaunchConfigVersion(
+	image string,
+	instanceType string,
+	spotPrice string,
+	volumeSize string,
+	volumeType string,
+	securityGroups string,
+) string {
+	// Spot price 0 means on-demand instances
+	if price, err := strconv.ParseFloat(spotPrice, 64); err != nil || price <= 0 {
+		spotPrice = ""
+	}
+
+	if volumeSize == "" || volumeSize == "0" {
+		volumeSize = strconv.Itoa(DefaultNodePoolVolumeSize)
+	}
+
+	if volumeType == "" {
+		volumeType = VolumeTypeGP2
+	}
+
+	return CalculateNodePoolVersion(image, instanceType, spotPrice, volumeSize, volumeType, securityGroups)
 }
 
 func CalculateNodePoolVersion(input ...string) string {
@@ -16,6 +16,11 @@ package eks
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
 
 	"github.com/banzaicloud/pipeline/internal/cluster"
 )
@@ -25,40 +30,369 @@ type Service interface {
 	// UpdateNodePool updates an existing node pool in a cluster.
 	//
 	// This method accepts a partial body representation.
-	UpdateNodePool(ctx context.Context, clusterID uint, nodePoolName string, nodePoolUpdate NodePoolUpdate) (string, error)
+	// It returns the changed node pool properties that require replacing the existing nodes.
+	UpdateNodePool(
+		ctx context.Context,
+		clusterID uint,
+		nodePoolName string,
+		nodePoolUpdate NodePoolUpdate,
+	) (processID string, nodeReplacementChanges []string, err error)
+
+	// UpgradeCluster upgrades the Kubernetes version of a cluster.
+	//
+	// The control plane is upgraded first, followed by the add-ons and the node pools.
+	// Only node pools running the default image of the current version are upgraded,
+	// node pools with custom (eg. GPU) images are left intact.
+	UpgradeCluster(ctx context.Context, clusterID uint, kubernetesVersion string) (string, error)
+
+	// HibernateCluster scales every node pool of a cluster to zero.
+	HibernateCluster(ctx context.Context, clusterID uint, options cluster.HibernateClusterOptions) (string, error)
+
+	// ResumeCluster restores the node pools of a hibernated cluster.
+	ResumeCluster(ctx context.Context, clusterID uint) (string, error)
+
+	// GetHibernationSchedule returns the hibernation schedule of a cluster.
+	GetHibernationSchedule(ctx context.Context, clusterID uint) (cluster.HibernationSchedule, error)
+
+	// SetHibernationSchedule sets the hibernation schedule of a cluster.
+	SetHibernationSchedule(ctx context.Context, clusterID uint, schedule cluster.HibernationSchedule) error
 }
 
 // NodePoolUpdate describes a node pool update request.
 //
 // A node pool update contains a partial representation of the node pool resource,
 // updating only the changed values.
+//
+// Scaling settings are updated when either autoscaling is enabled (updating the autoscaling bounds)
+// or a non-zero size is specified (disabling autoscaling).
+// Labels, security groups and taints replace the current ones when specified.
+// Spot price "0" switches the node pool to on-demand instances.
 type NodePoolUpdate struct {
-	Image string `mapstructure:"image"`
+	Size           int                 `mapstructure:"size"`
+	Labels         map[string]string   `mapstructure:"labels"`
+	Autoscaling    NodePoolAutoscaling `mapstructure:"autoscaling"`
+	InstanceType   string              `mapstructure:"instanceType"`
+	Image          string              `mapstructure:"image"`
+	SpotPrice      string              `mapstructure:"spotPrice"`
+	VolumeSize     int                 `mapstructure:"volumeSize"`
+	VolumeType     string              `mapstructure:"volumeType"`
+	SecurityGroups []string            `mapstructure:"securityGroups"`
+	Taints         []cluster.Taint     `mapstructure:"taints"`
+
+	Options NodePoolUpdateOptions `mapstructure:"options"`
+}
+
+// NodePoolUpdateOptions controls how nodes are replaced during a node pool update.
+//
+// Nodes are replaced in batches: for every batch MaxSurge replacement nodes are launched before draining old nodes,
+// and at most MaxUnavailable old nodes are drained and terminated before their replacement is ready.
+type NodePoolUpdateOptions struct {
+	// Maximum number of extra nodes that can be created during the update.
+	MaxSurge int `mapstructure:"maxSurge"`
+
+	// Maximum number of nodes that can be unavailable during the update.
+	MaxUnavailable int `mapstructure:"maxUnavailable"`
+
+	// Maximum number of seconds to wait for a node to be drained.
+	DrainTimeout int `mapstructure:"drainTimeout"`
+}
+
+// DefaultNodePoolVolumeSize is the default size of the node root volume in GiB.
+const DefaultNodePoolVolumeSize = 20
+
+// Supported node root volume types.
+const (
+	VolumeTypeGP2      = "gp2"
+	VolumeTypeStandard = "standard"
+)
+
+// Default node pool update option values.
+const (
+	DefaultNodePoolUpdateMaxSurge       = 1
+	DefaultNodePoolUpdateMaxUnavailable = 0
+	DefaultNodePoolUpdateDrainTimeout   = 600
+)
+
+// Validate semantically validates the node pool update.
+func (u NodePoolUpdate) Validate() error {
+	var violations []string
+
+	if u.Size < 0 {
+		violations = append(violations, "size cannot be negative")
+	}
+
+	if u.Autoscaling.Enabled {
+		if u.Autoscaling.MinSize < 1 {
+			violations = append(violations, "minimum autoscaling size cannot be lower than one")
+		}
+
+		if u.Autoscaling.MaxSize < u.Autoscaling.MinSize {
+			violations = append(violations, "maximum autoscaling size cannot be lower than the minimum")
+		}
+	}
+
+	if u.SpotPrice != "" {
+		if price, err := strconv.ParseFloat(u.SpotPrice, 64); err != nil || price < 0 {
+			violations = append(violations, "spot price must be a non-negative number")
+		}
+	}
+
+	if u.VolumeSize < 0 {
+		violations = append(violations, "volume size cannot be negative")
+	}
+
+	if u.VolumeType != "" {
+		switch u.VolumeType {
+		case VolumeTypeGP2, VolumeTypeStandard:
+		default:
+			violations = append(violations, fmt.Sprintf("volume type must be one of %s, %s", VolumeTypeGP2, VolumeTypeStandard))
+		}
+	}
+
+	for _, securityGroup := range u.SecurityGroups {
+		if !strings.HasPrefix(securityGroup, "sg-") {
+			violations = append(violations, fmt.Sprintf("invalid security group ID %q", securityGroup))
+		}
+	}
+
+	violations = append(violations, cluster.ValidateTaints(u.Taints)...)
+
+	if u.Options.MaxSurge < 0 {
+		violations = append(violations, "max surge cannot be negative")
+	}
+
+	if u.Options.MaxUnavailable < 0 {
+		violations = append(violations, "max unavailable cannot be negative")
+	}
+
+	if u.Options.DrainTimeout < 0 {
+		violations = append(violations, "drain timeout cannot be negative")
+	}
+
+	if len(violations) > 0 {
+		return cluster.NewValidationError("invalid node pool update request", violations)
+	}
+
+	return nil
+}
+
+// NodeReplacementChanges returns the changed node pool properties that require replacing the existing nodes.
+//
+// Unspecified volume settings of the current node pool are compared using the stack template defaults.
+// Taints are reconciled on the existing nodes, so they never require replacement.
+func (u NodePoolUpdate) NodeReplacementChanges(current NodePool) []string {
+	var changes []string
+
+	if u.Image != "" && u.Image != current.Image {
+		changes = append(changes, "image")
+	}
+
+	if u.InstanceType != "" && u.InstanceType != current.InstanceType {
+		changes = append(changes, "instanceType")
+	}
+
+	if u.SpotPrice != "" && !isSameSpotPrice(u.SpotPrice, current.SpotPrice) {
+		changes = append(changes, "spotPrice")
+	}
+
+	if u.VolumeSize > 0 && u.VolumeSize != current.volumeSize() {
+		changes = append(changes, "volumeSize")
+	}
+
+	if u.VolumeType != "" && u.VolumeType != current.volumeType() {
+		changes = append(changes, "volumeType")
+	}
+
+	if len(u.SecurityGroups) > 0 && !isSameSecurityGroups(u.SecurityGroups, current.SecurityGroups) {
+		changes = append(changes, "securityGroups")
+	}
+
+	return changes
+}
+
+func (n NodePool) volumeSize() int {
+	if n.VolumeSize <= 0 {
+		return DefaultNodePoolVolumeSize
+	}
+
+	return n.VolumeSize
+}
+
+func (n NodePool) volumeType() string {
+	if n.VolumeType == "" {
+		return VolumeTypeGP2
+	}
+
+	return n.VolumeType
+}
+
+func isSameSecurityGroups(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	securityGroups := make(map[string]bool, len(a))
+	for _, securityGroup := range a {
+		securityGroups[securityGroup] = true
+	}
+
+	for _, securityGroup := range b {
+		if !securityGroups[securityGroup] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isSameSpotPrice(a string, b string) bool {
+	priceA, _ := strconv.ParseFloat(a, 64)
+	priceB, _ := strconv.ParseFloat(b, 64)
+
+	return priceA == priceB
+}
+
+// Apply returns the node pool with the update applied.
+func (n NodePool) Apply(u NodePoolUpdate) NodePool {
+	if u.Autoscaling.Enabled {
+		n.Autoscaling = u.Autoscaling
+	} else if u.Size > 0 {
+		n.Autoscaling = NodePoolAutoscaling{}
+		n.Size = u.Size
+	}
+
+	if len(u.Labels) > 0 {
+		n.Labels = u.Labels
+	}
+
+	if u.InstanceType != "" {
+		n.InstanceType = u.InstanceType
+	}
+
+	if u.Image != "" {
+		n.Image = u.Image
+	}
+
+	if u.SpotPrice != "" {
+		n.SpotPrice = u.SpotPrice
+	}
+
+	if u.Taints != nil {
+		n.Taints = u.Taints
+	}
+
+	if u.VolumeSize > 0 {
+		n.VolumeSize = u.VolumeSize
+	}
+
+	if u.VolumeType != "" {
+		n.VolumeType = u.VolumeType
+	}
+
+	if len(u.SecurityGroups) > 0 {
+		n.SecurityGroups = u.SecurityGroups
+	}
+
+	return n
+}
+
+// WithDefaults returns the update options with default values for the unspecified fields.
+func (o NodePoolUpdateOptions) WithDefaults() NodePoolUpdateOptions {
+	if o.MaxSurge == 0 && o.MaxUnavailable == 0 {
+		o.MaxSurge = DefaultNodePoolUpdateMaxSurge
+		o.MaxUnavailable = DefaultNodePoolUpdateMaxUnavailable
+	}
+
+	if o.DrainTimeout == 0 {
+		o.DrainTimeout = DefaultNodePoolUpdateDrainTimeout
+	}
+
+	return o
 }
 
 // NewService returns a new Service instance.
 func NewService(
 	genericClusters cluster.Store,
+	clusters ClusterStore,
+	clusterManager ClusterManager,
 	nodePools NodePoolStore,
 	nodePoolManager NodePoolManager,
+	nodePoolLabelSource cluster.NodePoolLabelSource,
+	hibernations cluster.HibernationStore,
+	policies PolicyValidator,
 ) Service {
 	return service{
-		genericClusters: genericClusters,
-		nodePools:       nodePools,
-		nodePoolManager: nodePoolManager,
+		genericClusters:     genericClusters,
+		clusters:            clusters,
+		clusterManager:      clusterManager,
+		nodePools:           nodePools,
+		nodePoolManager:     nodePoolManager,
+		nodePoolLabelSource: nodePoolLabelSource,
+		hibernations:        hibernations,
+		policies:            policies,
 	}
 }
 
 type service struct {
-	genericClusters cluster.Store
-	nodePools       NodePoolStore
-	nodePoolManager NodePoolManager
+	genericClusters     cluster.Store
+	clusters            ClusterStore
+	clusterManager      ClusterManager
+	nodePools           NodePoolStore
+	nodePoolManager     NodePoolManager
+	nodePoolLabelSource cluster.NodePoolLabelSource
+	hibernations        cluster.HibernationStore
+	policies            PolicyValidator
+}
+
+// PolicyValidator checks cluster changes against organization policies.
+type PolicyValidator interface {
+	// ValidateClusterUpgrade checks the target Kubernetes version of a cluster upgrade against the policy of an organization.
+	ValidateClusterUpgrade(ctx context.Context, organizationID uint, kubernetesVersion string) error
+}
+
+// ClusterStore provides an interface for EKS cluster persistence.
+type ClusterStore interface {
+	// GetKubernetesVersion returns the Kubernetes version of the cluster control plane.
+	GetKubernetesVersion(ctx context.Context, clusterID uint) (string, error)
+
+	// SetKubernetesVersion updates the Kubernetes version of the cluster control plane.
+	SetKubernetesVersion(ctx context.Context, clusterID uint, kubernetesVersion string) error
+}
+
+// ClusterUpgrade describes the parameters of a cluster upgrade.
+type ClusterUpgrade struct {
+	KubernetesVersion string
+	NodeImage         string
+	NodePools         []string
+}
+
+// ClusterManager is responsible for managing clusters.
+type ClusterManager interface {
+	// UpgradeCluster upgrades the Kubernetes version of a cluster.
+	UpgradeCluster(ctx context.Context, c cluster.Cluster, upgrade ClusterUpgrade) (string, error)
+
+	// HibernateCluster scales every node pool of a cluster to zero.
+	HibernateCluster(ctx context.Context, c cluster.Cluster, options cluster.HibernateClusterOptions) (string, error)
+
+	// ResumeCluster restores the node pools of a hibernated cluster.
+	ResumeCluster(ctx context.Context, c cluster.Cluster) (string, error)
+
+	// ScheduleHibernation (re)schedules the periodic hibernation and resume of a cluster.
+	ScheduleHibernation(ctx context.Context, c cluster.Cluster, schedule cluster.HibernationSchedule) error
 }
 
 // NodePoolManager is responsible for managing node pools.
 type NodePoolManager interface {
 	// UpdateNodePool updates an existing node pool in a cluster.
-	UpdateNodePool(ctx context.Context, c cluster.Cluster, nodePoolName string, nodePoolUpdate NodePoolUpdate) (string, error)
+	//
+	// When replaceNodes is true, the existing nodes are replaced with new ones following the update options.
+	UpdateNodePool(
+		ctx context.Context,
+		c cluster.Cluster,
+		nodePoolName string,
+		nodePoolUpdate NodePoolUpdate,
+		replaceNodes bool,
+	) (string, error)
 }
 
 func (s service) UpdateNodePool(
@@ -66,18 +400,112 @@ func (s service) UpdateNodePool(
 	clusterID uint,
 	nodePoolName string,
 	nodePoolUpdate NodePoolUpdate,
-) (string, error) {
-	// TODO: check if node pool exists
+) (string, []string, error) {
+	err := nodePoolUpdate.Validate()
+	if err != nil {
+		return "", nil, err
+	}
+
+	nodePoolUpdate.Options = nodePoolUpdate.Options.WithDefaults()
 
 	c, err := s.genericClusters.GetCluster(ctx, clusterID)
 	if err != nil {
-		return "", err
+		return "", nil, err
+	}
+
+	nodePool, err := s.nodePools.GetNodePool(ctx, clusterID, nodePoolName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	nodeReplacementChanges := nodePoolUpdate.NodeReplacementChanges(nodePool)
+
+	// Labels are replaced as a whole, so common node pool labels have to be added again
+	if len(nodePoolUpdate.Labels) > 0 {
+		nodePoolUpdate.Labels, err = s.nodePoolLabelSource.GetLabels(ctx, c, nodePool.Apply(nodePoolUpdate))
+		if err != nil {
+			return "", nil, err
+		}
 	}
 
 	err = s.genericClusters.SetStatus(ctx, clusterID, cluster.Updating, "updating node pool")
+	if err != nil {
+		return "", nil, err
+	}
+
+	processID, err := s.nodePoolManager.UpdateNodePool(ctx, c, nodePoolName, nodePoolUpdate, len(nodeReplacementChanges) > 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return processID, nodeReplacementChanges, nil
+}
+
+func (s service) UpgradeCluster(ctx context.Context, clusterID uint, kubernetesVersion string) (string, error) {
+	c, err := s.genericClusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	currentVersion, err := s.clusters.GetKubernetesVersion(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	err = ValidateVersionUpgrade(currentVersion, kubernetesVersion)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.policies.ValidateClusterUpgrade(ctx, c.OrganizationID, kubernetesVersion)
 	if err != nil {
 		return "", err
 	}
 
-	return s.nodePoolManager.UpdateNodePool(ctx, c, nodePoolName, nodePoolUpdate)
+	nodeImage, err := GetDefaultImageID(c.Location, kubernetesVersion)
+	if err != nil {
+		return "", cluster.NewValidationError(
+			"invalid cluster upgrade request",
+			[]string{fmt.Sprintf("no node image available for Kubernetes version %s in %s", kubernetesVersion, c.Location)},
+		)
+	}
+
+	// Custom images cannot be replaced with the default one, they have to be updated separately
+	currentNodeImage, _ := GetDefaultImageID(c.Location, currentVersion)
+
+	nodePoolNames, err := s.nodePools.ListNodePoolNames(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	var nodePools []string
+	for _, nodePoolName := range nodePoolNames {
+		nodePool, err := s.nodePools.GetNodePool(ctx, clusterID, nodePoolName)
+		if err != nil {
+			return "", err
+		}
+
+		if nodePool.Image == "" || nodePool.Image == currentNodeImage {
+			nodePools = append(nodePools, nodePoolName)
+		}
+	}
+
+	err = s.genericClusters.SetStatus(ctx, clusterID, cluster.Updating, "upgrading cluster")
+	if err != nil {
+		return "", err
+	}
+
+	processID, err := s.clusterManager.UpgradeCluster(ctx, c, ClusterUpgrade{
+		KubernetesVersion: kubernetesVersion,
+		NodeImage:         nodeImage,
+		NodePools:         nodePools,
+	})
+	if err != nil {
+		// Nothing has been changed, so the cluster returns to its previous status
+		statusErr := s.genericClusters.SetStatus(ctx, clusterID, c.Status, c.StatusMessage)
+
+		return "", errors.Combine(err, statusErr)
+	}
+
+	return processID, nil
 }
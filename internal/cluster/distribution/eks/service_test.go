@@ -0,0 +1,174 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+func TestNodePoolUpdate_Validate(t *testing.T) {
+	assert.NoError(t, NodePoolUpdate{}.Validate())
+
+	update := NodePoolUpdate{
+		Options: NodePoolUpdateOptions{
+			MaxSurge:       -1,
+			MaxUnavailable: -1,
+			DrainTimeout:   -1,
+		},
+	}
+
+	err := update.Validate()
+	if assert.Error(t, err) {
+		assert.Len(t, err.(interface{ Violations() []string }).Violations(), 3)
+	}
+}
+
+func TestNodePoolUpdateOptions_WithDefaults(t *testing.T) {
+	assert.Equal(
+		t,
+		NodePoolUpdateOptions{
+			MaxSurge:       DefaultNodePoolUpdateMaxSurge,
+			MaxUnavailable: DefaultNodePoolUpdateMaxUnavailable,
+			DrainTimeout:   DefaultNodePoolUpdateDrainTimeout,
+		},
+		NodePoolUpdateOptions{}.WithDefaults(),
+	)
+
+	assert.Equal(
+		t,
+		NodePoolUpdateOptions{
+			MaxSurge:       0,
+			MaxUnavailable: 2,
+			DrainTimeout:   60,
+		},
+		NodePoolUpdateOptions{MaxUnavailable: 2, DrainTimeout: 60}.WithDefaults(),
+	)
+}
+
+func TestNodePoolUpdate_Validate_Properties(t *testing.T) {
+	update := NodePoolUpdate{
+		Size: -1,
+		Autoscaling: NodePoolAutoscaling{
+			Enabled: true,
+			MinSize: 0,
+			MaxSize: -1,
+		},
+		SpotPrice:      "cheap",
+		VolumeSize:     -1,
+		VolumeType:     "io1",
+		SecurityGroups: []string{"sg-123", "group"},
+		Taints: []cluster.Taint{
+			{Key: "dedicated", Effect: "Never"},
+		},
+	}
+
+	err := update.Validate()
+	if assert.Error(t, err) {
+		assert.Len(t, err.(interface{ Violations() []string }).Violations(), 8)
+	}
+}
+
+func TestNodePoolUpdate_Validate_EqualAutoscalingBounds(t *testing.T) {
+	update := NodePoolUpdate{
+		Autoscaling: NodePoolAutoscaling{
+			Enabled: true,
+			MinSize: 2,
+			MaxSize: 2,
+		},
+	}
+
+	assert.NoError(t, update.Validate())
+}
+
+func TestNodePoolUpdate_NodeReplacementChanges(t *testing.T) {
+	current := NodePool{
+		Name:           "pool0",
+		Size:           2,
+		InstanceType:   "t2.medium",
+		Image:          "ami-123",
+		SecurityGroups: []string{"sg-123", "sg-456"},
+	}
+
+	t.Run("InPlace", func(t *testing.T) {
+		update := NodePoolUpdate{
+			Size:           3,
+			Labels:         map[string]string{"key": "value"},
+			InstanceType:   "t2.medium",
+			Image:          "ami-123",
+			SpotPrice:      "0",
+			VolumeSize:     DefaultNodePoolVolumeSize,
+			VolumeType:     VolumeTypeGP2,
+			SecurityGroups: []string{"sg-456", "sg-123"},
+			Taints:         []cluster.Taint{{Key: "dedicated", Effect: cluster.TaintEffectNoSchedule}},
+		}
+
+		assert.Empty(t, update.NodeReplacementChanges(current))
+	})
+
+	t.Run("Replacement", func(t *testing.T) {
+		update := NodePoolUpdate{
+			InstanceType:   "t2.large",
+			Image:          "ami-456",
+			SpotPrice:      "0.2",
+			VolumeSize:     50,
+			VolumeType:     VolumeTypeStandard,
+			SecurityGroups: []string{"sg-123"},
+		}
+
+		assert.Equal(
+			t,
+			[]string{"image", "instanceType", "spotPrice", "volumeSize", "volumeType", "securityGroups"},
+			update.NodeReplacementChanges(current),
+		)
+	})
+}
+
+func TestNodePool_Apply(t *testing.T) {
+	current := NodePool{
+		Name: "pool0",
+		Size: 2,
+		Autoscaling: NodePoolAutoscaling{
+			Enabled: true,
+			MinSize: 1,
+			MaxSize: 3,
+		},
+		InstanceType: "t2.medium",
+		Image:        "ami-123",
+	}
+
+	assert.Equal(t, current, current.Apply(NodePoolUpdate{}))
+
+	assert.Equal(
+		t,
+		NodePool{
+			Name:         "pool0",
+			Labels:       map[string]string{"key": "value"},
+			Size:         4,
+			InstanceType: "t2.large",
+			Image:        "ami-123",
+			SpotPrice:    "0.2",
+		},
+		current.Apply(NodePoolUpdate{
+			Size:         4,
+			Labels:       map[string]string{"key": "value"},
+			InstanceType: "t2.large",
+			SpotPrice:    "0.2",
+		}),
+	)
+}
@@ -0,0 +1,111 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pke
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+func (s service) HibernateCluster(ctx context.Context, clusterID uint, options cluster.HibernateClusterOptions) (string, error) {
+	c, err := s.genericClusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.validateKeepNodePool(ctx, clusterID, options.KeepNodePool)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.genericClusters.SetStatus(ctx, clusterID, cluster.Updating, "hibernating cluster")
+	if err != nil {
+		return "", err
+	}
+
+	return s.clusterManager.HibernateCluster(ctx, c, options)
+}
+
+func (s service) ResumeCluster(ctx context.Context, clusterID uint) (string, error) {
+	c, err := s.genericClusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.genericClusters.SetStatus(ctx, clusterID, cluster.Updating, "resuming cluster")
+	if err != nil {
+		return "", err
+	}
+
+	return s.clusterManager.ResumeCluster(ctx, c)
+}
+
+func (s service) GetHibernationSchedule(ctx context.Context, clusterID uint) (cluster.HibernationSchedule, error) {
+	return s.hibernations.GetHibernationSchedule(ctx, clusterID)
+}
+
+func (s service) SetHibernationSchedule(ctx context.Context, clusterID uint, schedule cluster.HibernationSchedule) error {
+	c, err := s.genericClusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	err = s.validateKeepNodePool(ctx, clusterID, schedule.KeepNodePool)
+	if err != nil {
+		return err
+	}
+
+	err = s.clusterManager.ScheduleHibernation(ctx, c, schedule)
+	if err != nil {
+		return err
+	}
+
+	return s.hibernations.SetHibernationSchedule(ctx, clusterID, schedule)
+}
+
+// validateKeepNodePool makes sure that the kept node pool is a worker node pool of the cluster
+// (master node pools are kept running anyway).
+func (s service) validateKeepNodePool(ctx context.Context, clusterID uint, keepNodePool string) error {
+	if keepNodePool == "" {
+		return nil
+	}
+
+	nodePools, err := s.nodePools.ListNodePools(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	for _, nodePool := range nodePools {
+		if nodePool.Name != keepNodePool {
+			continue
+		}
+
+		if nodePool.Master {
+			return cluster.NewValidationError(
+				"invalid cluster hibernation request",
+				[]string{fmt.Sprintf("node pool %q is a master node pool, it is kept running anyway", keepNodePool)},
+			)
+		}
+
+		return nil
+	}
+
+	return cluster.NewValidationError(
+		"invalid cluster hibernation request",
+		[]string{fmt.Sprintf("node pool %q does not exist", keepNodePool)},
+	)
+}
@@ -0,0 +1,66 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pke
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+type nodePoolStoreStub []NodePool
+
+func (s nodePoolStoreStub) ListNodePools(ctx context.Context, clusterID uint) ([]NodePool, error) {
+	return s, nil
+}
+
+func TestService_validateKeepNodePool(t *testing.T) {
+	s := service{
+		nodePools: nodePoolStoreStub{
+			{Name: "master", Master: true, Size: 1},
+			{Name: "pool1", Size: 3},
+		},
+	}
+
+	tests := map[string]struct {
+		keepNodePool string
+		valid        bool
+	}{
+		"none":         {keepNodePool: "", valid: true},
+		"worker":       {keepNodePool: "pool1", valid: true},
+		"master":       {keepNodePool: "master", valid: false},
+		"non-existent": {keepNodePool: "pool2", valid: false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := s.validateKeepNodePool(context.Background(), 1, test.keepNodePool)
+			if test.valid {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.IsType(t, cluster.ValidationError{}, err)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeadapter
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke/pkeworkflow"
+)
+
+type clusterManager struct {
+	workflowClient client.Client
+}
+
+// NewClusterManager returns a new pke.ClusterManager
+// that manages clusters asynchronously via Cadence workflows.
+func NewClusterManager(workflowClient client.Client) pke.ClusterManager {
+	return clusterManager{
+		workflowClient: workflowClient,
+	}
+}
+
+// PKE workflows are registered by the open source worker only.
+const taskList = "pipeline"
+
+func (m clusterManager) HibernateCluster(ctx context.Context, c cluster.Cluster, options cluster.HibernateClusterOptions) (string, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     taskList,
+		ExecutionStartToCloseTimeout: 24 * time.Hour,
+	}
+
+	input := pkeworkflow.HibernateClusterWorkflowInput{
+		OrganizationID: c.OrganizationID,
+		ClusterID:      c.ID,
+		KeepNodePool:   options.KeepNodePool,
+	}
+
+	e, err := m.workflowClient.StartWorkflow(ctx, workflowOptions, pkeworkflow.HibernateClusterWorkflowName, input)
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", pkeworkflow.HibernateClusterWorkflowName)
+	}
+
+	return e.ID, nil
+}
+
+func (m clusterManager) ResumeCluster(ctx context.Context, c cluster.Cluster) (string, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     taskList,
+		ExecutionStartToCloseTimeout: 24 * time.Hour,
+	}
+
+	input := pkeworkflow.ResumeClusterWorkflowInput{
+		OrganizationID: c.OrganizationID,
+		ClusterID:      c.ID,
+	}
+
+	e, err := m.workflowClient.StartWorkflow(ctx, workflowOptions, pkeworkflow.ResumeClusterWorkflowName, input)
+	if err != nil {
+		return "", errors.WrapWithDetails(err, "failed to start workflow", "workflow", pkeworkflow.ResumeClusterWorkflowName)
+	}
+
+	return e.ID, nil
+}
+
+// ScheduleHibernation replaces the cron workflows of a cluster with new ones following the schedule.
+// Empty cron expressions only cancel the corresponding workflow.
+func (m clusterManager) ScheduleHibernation(ctx context.Context, c cluster.Cluster, schedule cluster.HibernationSchedule) error {
+	crons := []struct {
+		workflowName string
+		cronSchedule string
+		input        interface{}
+	}{
+		{
+			workflowName: pkeworkflow.HibernateClusterWorkflowName,
+			cronSchedule: schedule.Hibernate,
+			input: pkeworkflow.HibernateClusterWorkflowInput{
+				OrganizationID: c.OrganizationID,
+				ClusterID:      c.ID,
+				KeepNodePool:   schedule.KeepNodePool,
+				Scheduled:      true,
+			},
+		},
+		{
+			workflowName: pkeworkflow.ResumeClusterWorkflowName,
+			cronSchedule: schedule.Resume,
+			input: pkeworkflow.ResumeClusterWorkflowInput{
+				OrganizationID: c.OrganizationID,
+				ClusterID:      c.ID,
+				Scheduled:      true,
+			},
+		},
+	}
+
+	for _, cron := range crons {
+		workflowID := cluster.HibernationScheduleWorkflowID(cron.workflowName, c.ID)
+
+		err := m.workflowClient.TerminateWorkflow(ctx, workflowID, "", "hibernation schedule changed", nil)
+		if err != nil && !isEntityNotExistsError(err) {
+			return errors.WrapIfWithDetails(err, "failed to cancel scheduled workflow", "workflowId", workflowID)
+		}
+
+		if cron.cronSchedule == "" {
+			continue
+		}
+
+		workflowOptions := client.StartWorkflowOptions{
+			ID:                           workflowID,
+			TaskList:                     taskList,
+			ExecutionStartToCloseTimeout: 24 * time.Hour,
+			WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+			CronSchedule:                 cron.cronSchedule,
+		}
+
+		_, err = m.workflowClient.StartWorkflow(ctx, workflowOptions, cron.workflowName, cron.input)
+		if err != nil {
+			return errors.WrapWithDetails(err, "failed to start workflow", "workflow", cron.workflowName)
+		}
+	}
+
+	return nil
+}
+
+func isEntityNotExistsError(err error) bool {
+	var ene *shared.EntityNotExistsError
+
+	return errors.As(err, &ene)
+}
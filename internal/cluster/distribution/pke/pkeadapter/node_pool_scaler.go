@@ -0,0 +1,249 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeadapter
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+	azurePKE "github.com/banzaicloud/pipeline/internal/providers/azure/pke"
+	azureWorkflow "github.com/banzaicloud/pipeline/internal/providers/azure/pke/workflow"
+	"github.com/banzaicloud/pipeline/internal/providers/pke/pkeworkflow"
+	vspherePKE "github.com/banzaicloud/pipeline/internal/providers/vsphere/pke"
+	vsphereWorkflow "github.com/banzaicloud/pipeline/internal/providers/vsphere/pke/workflow"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type nodePoolScaler struct {
+	clusters cluster.Store
+
+	awsClientFactory *pkeworkflow.AWSClientFactory
+
+	azureClusters      azurePKE.ClusterStore
+	azureClientFactory *azureWorkflow.AzureClientFactory
+
+	vsphereClusters      vspherePKE.ClusterStore
+	vsphereClientFactory *vsphereWorkflow.VMOMIClientFactory
+}
+
+// NewNodePoolScaler returns a new pke.NodePoolScaler
+// that scales node pools using the API of the cloud provider of the cluster:
+// auto scaling groups on AWS, virtual machine scale sets on Azure and powering virtual machines on and off on vSphere.
+func NewNodePoolScaler(
+	clusters cluster.Store,
+	awsClientFactory *pkeworkflow.AWSClientFactory,
+	azureClusters azurePKE.ClusterStore,
+	azureClientFactory *azureWorkflow.AzureClientFactory,
+	vsphereClusters vspherePKE.ClusterStore,
+	vsphereClientFactory *vsphereWorkflow.VMOMIClientFactory,
+) pke.NodePoolScaler {
+	return nodePoolScaler{
+		clusters:             clusters,
+		awsClientFactory:     awsClientFactory,
+		azureClusters:        azureClusters,
+		azureClientFactory:   azureClientFactory,
+		vsphereClusters:      vsphereClusters,
+		vsphereClientFactory: vsphereClientFactory,
+	}
+}
+
+func (s nodePoolScaler) ScaleNodePool(ctx context.Context, clusterID uint, nodePoolName string, scaling pke.NodePoolScaling) error {
+	c, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	switch c.Cloud {
+	case pkgCluster.Amazon:
+		return s.scaleAmazonNodePool(ctx, c, nodePoolName, scaling)
+
+	case pkgCluster.Azure:
+		return s.scaleAzureNodePool(ctx, c, nodePoolName, scaling)
+
+	case pkgCluster.Vsphere:
+		return s.scaleVsphereNodePool(ctx, c, nodePoolName, scaling)
+
+	default:
+		return errors.WithStack(cluster.NotSupportedDistributionError{
+			ID:           c.ID,
+			Cloud:        c.Cloud,
+			Distribution: c.Distribution,
+
+			Message: "not supported cloud provider",
+		})
+	}
+}
+
+// scaleAmazonNodePool updates the auto scaling group of the node pool.
+// The cluster autoscaler is disabled for the auto scaling group unless autoscaling is enabled in the scaling settings.
+func (s nodePoolScaler) scaleAmazonNodePool(ctx context.Context, c cluster.Cluster, nodePoolName string, scaling pke.NodePoolScaling) error {
+	awsActivityInput := pkeworkflow.AWSActivityInput{
+		OrganizationID: c.OrganizationID,
+		SecretID:       c.SecretID.ResourceID,
+		Region:         c.Location,
+	}
+
+	client, err := s.awsClientFactory.New(awsActivityInput.OrganizationID, awsActivityInput.SecretID, awsActivityInput.Region)
+	if err != nil {
+		return err
+	}
+
+	stackName := fmt.Sprintf("pke-pool-%s-worker-%s", c.Name, nodePoolName)
+
+	output, err := cloudformation.New(client).DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to describe node pool stack", "stackName", stackName)
+	}
+
+	var autoScalingGroup string
+	for _, stack := range output.Stacks {
+		for _, output := range stack.Outputs {
+			if aws.StringValue(output.OutputKey) == "AutoScalingGroupId" {
+				autoScalingGroup = aws.StringValue(output.OutputValue)
+			}
+		}
+	}
+
+	if autoScalingGroup == "" {
+		return errors.NewWithDetails("can't find auto scaling group for node pool", "nodePool", nodePoolName, "stackName", stackName)
+	}
+
+	return pkeworkflow.NewUpdatePoolActivity(s.awsClientFactory).Execute(ctx, pkeworkflow.UpdatePoolActivityInput{
+		AWSActivityInput: awsActivityInput,
+		Pool: pkeworkflow.NodePool{
+			Name:        nodePoolName,
+			MinCount:    scaling.MinSize,
+			MaxCount:    scaling.MaxSize,
+			Count:       scaling.Size,
+			Autoscaling: scaling.Autoscaling,
+		},
+		AutoScalingGroup: autoScalingGroup,
+	})
+}
+
+// scaleAzureNodePool updates the capacity of the virtual machine scale set of the node pool.
+func (s nodePoolScaler) scaleAzureNodePool(ctx context.Context, c cluster.Cluster, nodePoolName string, scaling pke.NodePoolScaling) error {
+	azureCluster, err := s.azureClusters.GetByID(c.ID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get cluster", "clusterId", c.ID)
+	}
+
+	size := scaling.Size
+	if scaling.Autoscaling {
+		size = clamp(size, scaling.MinSize, scaling.MaxSize)
+	}
+
+	return azureWorkflow.MakeUpdateVMSSActivity(s.azureClientFactory).Execute(ctx, azureWorkflow.UpdateVMSSActivityInput{
+		OrganizationID:    c.OrganizationID,
+		SecretID:          c.SecretID.ResourceID,
+		ClusterName:       c.Name,
+		ResourceGroupName: azureCluster.ResourceGroup.Name,
+		Changes: azureWorkflow.VirtualMachineScaleSetChanges{
+			Name:          azurePKE.GetVMSSName(c.Name, nodePoolName),
+			InstanceCount: azureWorkflow.NewUint(uint(size)),
+		},
+	})
+}
+
+// scaleVsphereNodePool powers on the first virtual machines of the node pool up to the requested size
+// and powers off the rest of them.
+// Virtual machines are kept (powered off), so their disks are preserved during hibernation.
+func (s nodePoolScaler) scaleVsphereNodePool(ctx context.Context, c cluster.Cluster, nodePoolName string, scaling pke.NodePoolScaling) error {
+	vsphereCluster, err := s.vsphereClusters.GetByID(c.ID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get cluster", "clusterId", c.ID)
+	}
+
+	var nodePool *vspherePKE.NodePool
+	for i := range vsphereCluster.NodePools {
+		if vsphereCluster.NodePools[i].Name == nodePoolName {
+			nodePool = &vsphereCluster.NodePools[i]
+		}
+	}
+
+	if nodePool == nil {
+		return errors.NewWithDetails("node pool not found", "clusterId", c.ID, "nodePool", nodePoolName)
+	}
+
+	client, err := s.vsphereClientFactory.New(c.OrganizationID, c.SecretID.ResourceID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create cloud connection")
+	}
+
+	finder := find.NewFinder(client.Client)
+
+	for i := 1; i <= nodePool.Size; i++ {
+		vmName := vspherePKE.GetVMName(c.Name, nodePoolName, i)
+
+		vms, err := finder.VirtualMachineList(ctx, vmName)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to find virtual machine", "vm", vmName)
+		}
+		if len(vms) != 1 {
+			return errors.NewWithDetails("couldn't find a single virtual machine", "vm", vmName)
+		}
+
+		vm := vms[0]
+
+		powerState, err := vm.PowerState(ctx)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to get power state of virtual machine", "vm", vmName)
+		}
+
+		if i <= scaling.Size && powerState != types.VirtualMachinePowerStatePoweredOn {
+			if _, err := vm.PowerOn(ctx); err != nil {
+				return errors.WrapIfWithDetails(err, "failed to power on virtual machine", "vm", vmName)
+			}
+
+			err = vm.WaitForPowerState(ctx, types.VirtualMachinePowerStatePoweredOn)
+			if err != nil {
+				return errors.WrapIfWithDetails(err, "failed to power on virtual machine", "vm", vmName)
+			}
+		} else if i > scaling.Size && powerState != types.VirtualMachinePowerStatePoweredOff {
+			if _, err := vm.PowerOff(ctx); err != nil {
+				return errors.WrapIfWithDetails(err, "failed to power off virtual machine", "vm", vmName)
+			}
+
+			err = vm.WaitForPowerState(ctx, types.VirtualMachinePowerStatePoweredOff)
+			if err != nil {
+				return errors.WrapIfWithDetails(err, "failed to power off virtual machine", "vm", vmName)
+			}
+		}
+	}
+
+	return nil
+}
+
+func clamp(value int, min int, max int) int {
+	if value < min {
+		return min
+	}
+
+	if value > max {
+		return max
+	}
+
+	return value
+}
@@ -0,0 +1,160 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+	azurePKE "github.com/banzaicloud/pipeline/internal/providers/azure/pke"
+	internalPKE "github.com/banzaicloud/pipeline/internal/providers/pke"
+	vspherePKE "github.com/banzaicloud/pipeline/internal/providers/vsphere/pke"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgPKE "github.com/banzaicloud/pipeline/pkg/cluster/pke"
+)
+
+type nodePoolStore struct {
+	db              *gorm.DB
+	clusters        cluster.Store
+	azureClusters   azurePKE.ClusterStore
+	vsphereClusters vspherePKE.ClusterStore
+}
+
+// NewNodePoolStore returns a new pke.NodePoolStore
+// that reads the node pools from the cluster store of the cloud provider.
+func NewNodePoolStore(
+	db *gorm.DB,
+	clusters cluster.Store,
+	azureClusters azurePKE.ClusterStore,
+	vsphereClusters vspherePKE.ClusterStore,
+) pke.NodePoolStore {
+	return nodePoolStore{
+		db:              db,
+		clusters:        clusters,
+		azureClusters:   azureClusters,
+		vsphereClusters: vsphereClusters,
+	}
+}
+
+func (s nodePoolStore) ListNodePools(ctx context.Context, clusterID uint) ([]pke.NodePool, error) {
+	c, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Cloud {
+	case pkgCluster.Amazon:
+		return s.listAmazonNodePools(clusterID)
+
+	case pkgCluster.Azure:
+		azureCluster, err := s.azureClusters.GetByID(clusterID)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to get cluster", "clusterId", clusterID)
+		}
+
+		nodePools := make([]pke.NodePool, 0, len(azureCluster.NodePools))
+		for _, np := range azureCluster.NodePools {
+			nodePools = append(nodePools, pke.NodePool{
+				Name:        np.Name,
+				Master:      hasMasterRole(np.Roles),
+				Size:        int(np.DesiredCount),
+				Autoscaling: np.Autoscaling,
+				MinSize:     int(np.Min),
+				MaxSize:     int(np.Max),
+			})
+		}
+
+		return nodePools, nil
+
+	case pkgCluster.Vsphere:
+		vsphereCluster, err := s.vsphereClusters.GetByID(clusterID)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to get cluster", "clusterId", clusterID)
+		}
+
+		nodePools := make([]pke.NodePool, 0, len(vsphereCluster.NodePools))
+		for _, np := range vsphereCluster.NodePools {
+			nodePools = append(nodePools, pke.NodePool{
+				Name:    np.Name,
+				Master:  hasMasterRole(np.Roles),
+				Size:    np.Size,
+				MinSize: np.Size,
+				MaxSize: np.Size,
+			})
+		}
+
+		return nodePools, nil
+
+	default:
+		return nil, errors.WithStack(cluster.NotSupportedDistributionError{
+			ID:           c.ID,
+			Cloud:        c.Cloud,
+			Distribution: c.Distribution,
+
+			Message: "not supported cloud provider",
+		})
+	}
+}
+
+func (s nodePoolStore) listAmazonNodePools(clusterID uint) ([]pke.NodePool, error) {
+	var model internalPKE.EC2PKEClusterModel
+
+	err := s.db.
+		Where(internalPKE.EC2PKEClusterModel{ClusterID: clusterID}).
+		Preload("NodePools").
+		First(&model).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to get cluster", "clusterId", clusterID)
+	}
+
+	nodePools := make([]pke.NodePool, 0, len(model.NodePools))
+	for _, np := range model.NodePools {
+		var providerConfig internalPKE.NodePoolProviderConfigAmazon
+		if err := mapstructure.Decode(np.ProviderConfig, &providerConfig); err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to decode node pool provider config", "clusterId", clusterID, "nodePool", np.Name)
+		}
+
+		roles := make([]string, 0, len(np.Roles))
+		for _, role := range np.Roles {
+			roles = append(roles, string(role))
+		}
+
+		nodePools = append(nodePools, pke.NodePool{
+			Name:        np.Name,
+			Master:      hasMasterRole(roles),
+			Size:        providerConfig.AutoScalingGroup.Size.Desired,
+			Autoscaling: np.Autoscaling,
+			MinSize:     providerConfig.AutoScalingGroup.Size.Min,
+			MaxSize:     providerConfig.AutoScalingGroup.Size.Max,
+		})
+	}
+
+	return nodePools, nil
+}
+
+func hasMasterRole(roles []string) bool {
+	for _, role := range roles {
+		if role == string(pkgPKE.RoleMaster) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+const PrepareHibernateClusterActivityName = "pke-prepare-hibernate-cluster"
+
+// PrepareHibernateClusterActivity records the scaling settings of the worker node pools of a cluster before hibernation.
+type PrepareHibernateClusterActivity struct {
+	clusters     cluster.Store
+	nodePools    pke.NodePoolStore
+	hibernations cluster.HibernationStore
+}
+
+// PrepareHibernateClusterActivityInput holds the parameters for preparing the hibernation.
+type PrepareHibernateClusterActivityInput struct {
+	ClusterID uint
+
+	// Scheduled hibernations are skipped when the cluster is not ready.
+	Scheduled bool
+}
+
+type PrepareHibernateClusterActivityOutput struct {
+	Skip bool
+
+	NodePools []cluster.HibernatedNodePool
+}
+
+// NewPrepareHibernateClusterActivity creates a new PrepareHibernateClusterActivity instance.
+func NewPrepareHibernateClusterActivity(
+	clusters cluster.Store,
+	nodePools pke.NodePoolStore,
+	hibernations cluster.HibernationStore,
+) PrepareHibernateClusterActivity {
+	return PrepareHibernateClusterActivity{
+		clusters:     clusters,
+		nodePools:    nodePools,
+		hibernations: hibernations,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a PrepareHibernateClusterActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: PrepareHibernateClusterActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a PrepareHibernateClusterActivity) Execute(ctx context.Context, input PrepareHibernateClusterActivityInput) (PrepareHibernateClusterActivityOutput, error) {
+	c, err := a.clusters.GetCluster(ctx, input.ClusterID)
+	if input.Scheduled && cluster.IsNotFoundError(err) {
+		return PrepareHibernateClusterActivityOutput{Skip: true}, nil
+	} else if err != nil {
+		return PrepareHibernateClusterActivityOutput{}, err
+	}
+
+	if input.Scheduled {
+		if c.Status != cluster.Running && c.Status != cluster.Warning {
+			return PrepareHibernateClusterActivityOutput{Skip: true}, nil
+		}
+
+		ctx := ctxutil.WithProcessID(ctx, activity.GetInfo(ctx).WorkflowExecution.ID)
+
+		err := a.clusters.SetStatus(ctx, input.ClusterID, cluster.Updating, "hibernating cluster")
+		if err != nil {
+			return PrepareHibernateClusterActivityOutput{}, err
+		}
+	}
+
+	nodePools, err := a.nodePools.ListNodePools(ctx, input.ClusterID)
+	if err != nil {
+		return PrepareHibernateClusterActivityOutput{}, err
+	}
+
+	hibernatedNodePools := make([]cluster.HibernatedNodePool, 0, len(nodePools))
+	for _, nodePool := range nodePools {
+		if nodePool.Master {
+			continue
+		}
+
+		hibernatedNodePools = append(hibernatedNodePools, cluster.HibernatedNodePool{
+			Name:        nodePool.Name,
+			Size:        nodePool.Size,
+			Autoscaling: nodePool.Autoscaling,
+			MinSize:     nodePool.MinSize,
+			MaxSize:     nodePool.MaxSize,
+		})
+	}
+
+	err = a.hibernations.SaveHibernatedNodePools(ctx, input.ClusterID, hibernatedNodePools)
+	if err != nil {
+		return PrepareHibernateClusterActivityOutput{}, err
+	}
+
+	return PrepareHibernateClusterActivityOutput{
+		NodePools: hibernatedNodePools,
+	}, nil
+}
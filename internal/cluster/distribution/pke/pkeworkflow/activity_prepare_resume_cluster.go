@@ -0,0 +1,91 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+const PrepareResumeClusterActivityName = "pke-prepare-resume-cluster"
+
+// PrepareResumeClusterActivity loads the recorded scaling settings of the node pools of a hibernated cluster.
+type PrepareResumeClusterActivity struct {
+	clusters     cluster.Store
+	hibernations cluster.HibernationStore
+}
+
+// PrepareResumeClusterActivityInput holds the parameters for preparing the resume.
+type PrepareResumeClusterActivityInput struct {
+	ClusterID uint
+
+	// Scheduled resumes are skipped when the cluster is not hibernated.
+	Scheduled bool
+}
+
+type PrepareResumeClusterActivityOutput struct {
+	Skip bool
+
+	NodePools []cluster.HibernatedNodePool
+}
+
+// NewPrepareResumeClusterActivity creates a new PrepareResumeClusterActivity instance.
+func NewPrepareResumeClusterActivity(clusters cluster.Store, hibernations cluster.HibernationStore) PrepareResumeClusterActivity {
+	return PrepareResumeClusterActivity{
+		clusters:     clusters,
+		hibernations: hibernations,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a PrepareResumeClusterActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: PrepareResumeClusterActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a PrepareResumeClusterActivity) Execute(ctx context.Context, input PrepareResumeClusterActivityInput) (PrepareResumeClusterActivityOutput, error) {
+	c, err := a.clusters.GetCluster(ctx, input.ClusterID)
+	if input.Scheduled && cluster.IsNotFoundError(err) {
+		return PrepareResumeClusterActivityOutput{Skip: true}, nil
+	} else if err != nil {
+		return PrepareResumeClusterActivityOutput{}, err
+	}
+
+	if input.Scheduled {
+		if c.Status != cluster.Hibernated {
+			return PrepareResumeClusterActivityOutput{Skip: true}, nil
+		}
+
+		ctx := ctxutil.WithProcessID(ctx, activity.GetInfo(ctx).WorkflowExecution.ID)
+
+		err := a.clusters.SetStatus(ctx, input.ClusterID, cluster.Updating, "resuming cluster")
+		if err != nil {
+			return PrepareResumeClusterActivityOutput{}, err
+		}
+	}
+
+	nodePools, err := a.hibernations.GetHibernatedNodePools(ctx, input.ClusterID)
+	if err != nil {
+		return PrepareResumeClusterActivityOutput{}, err
+	}
+
+	return PrepareResumeClusterActivityOutput{
+		NodePools: nodePools,
+	}, nil
+}
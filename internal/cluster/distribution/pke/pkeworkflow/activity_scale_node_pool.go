@@ -0,0 +1,54 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+)
+
+const ScaleNodePoolActivityName = "pke-scale-node-pool"
+
+// ScaleNodePoolActivity changes the number of nodes of a node pool on the cloud provider of the cluster.
+type ScaleNodePoolActivity struct {
+	scaler pke.NodePoolScaler
+}
+
+// ScaleNodePoolActivityInput holds the parameters for scaling a node pool.
+type ScaleNodePoolActivityInput struct {
+	ClusterID    uint
+	NodePoolName string
+	Scaling      pke.NodePoolScaling
+}
+
+// NewScaleNodePoolActivity creates a new ScaleNodePoolActivity instance.
+func NewScaleNodePoolActivity(scaler pke.NodePoolScaler) ScaleNodePoolActivity {
+	return ScaleNodePoolActivity{
+		scaler: scaler,
+	}
+}
+
+// Register registers the activity in the worker.
+func (a ScaleNodePoolActivity) Register() {
+	activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: ScaleNodePoolActivityName})
+}
+
+// Execute is the main body of the activity.
+func (a ScaleNodePoolActivity) Execute(ctx context.Context, input ScaleNodePoolActivityInput) error {
+	return a.scaler.ScaleNodePool(ctx, input.ClusterID, input.NodePoolName, input.Scaling)
+}
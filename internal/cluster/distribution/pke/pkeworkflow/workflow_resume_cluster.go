@@ -0,0 +1,156 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/pke"
+	"github.com/banzaicloud/pipeline/pkg/sdk/brn"
+	"github.com/banzaicloud/pipeline/pkg/sdk/cadence/lib/pipeline/processlog"
+)
+
+const ResumeClusterWorkflowName = "pke-resume-cluster"
+
+// ResumeClusterWorkflow restores the worker node pools of a hibernated PKE cluster to their recorded scaling settings.
+type ResumeClusterWorkflow struct {
+	processLogger processlog.ProcessLogger
+}
+
+// NewResumeClusterWorkflow returns a new ResumeClusterWorkflow.
+func NewResumeClusterWorkflow(processLogger processlog.ProcessLogger) ResumeClusterWorkflow {
+	return ResumeClusterWorkflow{
+		processLogger: processLogger,
+	}
+}
+
+type ResumeClusterWorkflowInput struct {
+	OrganizationID uint
+	ClusterID      uint
+
+	// Scheduled is true when the workflow is started by a cron schedule.
+	Scheduled bool
+}
+
+func (w ResumeClusterWorkflow) Register() {
+	workflow.RegisterWithOptions(w.Execute, workflow.RegisterOptions{Name: ResumeClusterWorkflowName})
+}
+
+func (w ResumeClusterWorkflow) Execute(ctx workflow.Context, input ResumeClusterWorkflowInput) (err error) {
+	activityOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: time.Duration(workflow.GetInfo(ctx).ExecutionStartToCloseTimeoutSeconds) * time.Second,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var output PrepareResumeClusterActivityOutput
+	{
+		activityInput := PrepareResumeClusterActivityInput{
+			ClusterID: input.ClusterID,
+			Scheduled: input.Scheduled,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 30 * time.Second
+
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			PrepareResumeClusterActivityName,
+			activityInput,
+		).Get(ctx, &output)
+		if err != nil {
+			if !input.Scheduled {
+				_ = setClusterStatus(ctx, input.ClusterID, cluster.Hibernated, fmt.Sprintf("failed to resume cluster: %s", err.Error()))
+			}
+
+			return err
+		}
+
+		if output.Skip {
+			return nil
+		}
+	}
+
+	clusterID := brn.New(input.OrganizationID, brn.ClusterResourceType, fmt.Sprint(input.ClusterID))
+
+	process := w.processLogger.StartProcess(ctx, clusterID.String())
+	defer func() {
+		process.Finish(ctx, err)
+	}()
+	defer func() {
+		status := cluster.Running
+		statusMessage := cluster.RunningMessage
+
+		if err != nil {
+			if cadence.IsCanceledError(err) {
+				ctx, _ = workflow.NewDisconnectedContext(ctx)
+			}
+
+			status = cluster.Warning
+			statusMessage = fmt.Sprintf("failed to resume cluster: %s", err.Error())
+		}
+
+		_ = setClusterStatus(ctx, input.ClusterID, status, statusMessage)
+	}()
+
+	for _, nodePool := range output.NodePools {
+		activityInput := ScaleNodePoolActivityInput{
+			ClusterID:    input.ClusterID,
+			NodePoolName: nodePool.Name,
+			Scaling: pke.NodePoolScaling{
+				Size:        nodePool.Size,
+				Autoscaling: nodePool.Autoscaling,
+				MinSize:     nodePool.MinSize,
+				MaxSize:     nodePool.MaxSize,
+			},
+		}
+
+		err = scaleNodePool(ctx, activityOptions, process, activityInput)
+		if err != nil {
+			return
+		}
+	}
+
+	{
+		activityInput := DeleteHibernatedNodePoolsActivityInput{
+			ClusterID: input.ClusterID,
+		}
+
+		activityOptions := activityOptions
+		activityOptions.StartToCloseTimeout = 30 * time.Second
+		activityOptions.RetryPolicy = &cadence.RetryPolicy{
+			InitialInterval:    10 * time.Second,
+			BackoffCoefficient: 1.01,
+			MaximumInterval:    10 * time.Minute,
+			MaximumAttempts:    30,
+		}
+
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			DeleteHibernatedNodePoolsActivityName,
+			activityInput,
+		).Get(ctx, nil)
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
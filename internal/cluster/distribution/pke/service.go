@@ -0,0 +1,105 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pke
+
+import (
+	"context"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// Service provides an interface to PKE clusters.
+//
+// PKE clusters are managed by the legacy cluster APIs, this service only covers hibernation.
+type Service interface {
+	// HibernateCluster scales every worker node pool of a cluster to zero.
+	HibernateCluster(ctx context.Context, clusterID uint, options cluster.HibernateClusterOptions) (string, error)
+
+	// ResumeCluster restores the worker node pools of a hibernated cluster.
+	ResumeCluster(ctx context.Context, clusterID uint) (string, error)
+
+	// GetHibernationSchedule returns the hibernation schedule of a cluster.
+	GetHibernationSchedule(ctx context.Context, clusterID uint) (cluster.HibernationSchedule, error)
+
+	// SetHibernationSchedule sets the hibernation schedule of a cluster.
+	SetHibernationSchedule(ctx context.Context, clusterID uint, schedule cluster.HibernationSchedule) error
+}
+
+// NodePool describes the scaling settings of a PKE node pool.
+type NodePool struct {
+	Name string
+
+	// Master node pools run the control plane, they are never scaled down.
+	Master bool
+
+	Size        int
+	Autoscaling bool
+	MinSize     int
+	MaxSize     int
+}
+
+// NodePoolScaling describes the desired scaling settings of a node pool.
+type NodePoolScaling struct {
+	Size        int
+	Autoscaling bool
+	MinSize     int
+	MaxSize     int
+}
+
+// NodePoolStore provides an interface to PKE node pools regardless of the cloud provider.
+type NodePoolStore interface {
+	// ListNodePools returns the node pools of a cluster.
+	ListNodePools(ctx context.Context, clusterID uint) ([]NodePool, error)
+}
+
+// NodePoolScaler changes the number of nodes in a node pool on the cloud provider of the cluster.
+type NodePoolScaler interface {
+	// ScaleNodePool applies the scaling settings to the nodes of a node pool.
+	ScaleNodePool(ctx context.Context, clusterID uint, nodePoolName string, scaling NodePoolScaling) error
+}
+
+// ClusterManager provides an interface to asynchronous PKE cluster operations.
+type ClusterManager interface {
+	// HibernateCluster starts the hibernation of a cluster.
+	HibernateCluster(ctx context.Context, c cluster.Cluster, options cluster.HibernateClusterOptions) (string, error)
+
+	// ResumeCluster starts resuming a hibernated cluster.
+	ResumeCluster(ctx context.Context, c cluster.Cluster) (string, error)
+
+	// ScheduleHibernation replaces the scheduled hibernation and resume operations of a cluster.
+	ScheduleHibernation(ctx context.Context, c cluster.Cluster, schedule cluster.HibernationSchedule) error
+}
+
+// NewService returns a new Service instance.
+func NewService(
+	genericClusters cluster.Store,
+	clusterManager ClusterManager,
+	nodePools NodePoolStore,
+	hibernations cluster.HibernationStore,
+) Service {
+	return service{
+		genericClusters: genericClusters,
+		clusterManager:  clusterManager,
+		nodePools:       nodePools,
+		hibernations:    hibernations,
+	}
+}
+
+type service struct {
+	genericClusters cluster.Store
+	clusterManager  ClusterManager
+	nodePools       NodePoolStore
+	hibernations    cluster.HibernationStore
+}
@@ -0,0 +1,197 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/robfig/cron"
+)
+
+// HibernateClusterOptions represents cluster hibernation options.
+type HibernateClusterOptions struct {
+	// KeepNodePool is the name of a node pool that is kept running with a single node during hibernation
+	// (eg. for system workloads).
+	KeepNodePool string
+}
+
+// HibernationSchedule describes when a cluster is hibernated and resumed automatically.
+//
+// Schedules are standard cron expressions (evaluated in UTC).
+// An empty expression disables the corresponding schedule.
+type HibernationSchedule struct {
+	Hibernate string
+	Resume    string
+
+	// KeepNodePool is the node pool kept running during scheduled hibernations.
+	KeepNodePool string
+}
+
+// Validate validates the cron expressions of the schedule.
+func (s HibernationSchedule) Validate() error {
+	var violations []string
+
+	if s.Hibernate != "" {
+		if _, err := cron.ParseStandard(s.Hibernate); err != nil {
+			violations = append(violations, fmt.Sprintf("invalid hibernate schedule %q: %s", s.Hibernate, err.Error()))
+		}
+	}
+
+	if s.Resume != "" {
+		if _, err := cron.ParseStandard(s.Resume); err != nil {
+			violations = append(violations, fmt.Sprintf("invalid resume schedule %q: %s", s.Resume, err.Error()))
+		}
+	}
+
+	if len(violations) > 0 {
+		return NewValidationError("invalid hibernation schedule", violations)
+	}
+
+	return nil
+}
+
+// HibernationScheduleWorkflowID returns the ID of the cron workflow that starts a hibernation or resume workflow of a cluster.
+//
+// Cluster IDs are unique in the system, so a cluster has at most one scheduled workflow of each kind.
+func HibernationScheduleWorkflowID(workflowName string, clusterID uint) string {
+	return fmt.Sprintf("%s-schedule-%d", workflowName, clusterID)
+}
+
+// HibernatedNodePool records the scaling settings of a node pool before hibernation.
+type HibernatedNodePool struct {
+	Name        string
+	Size        int
+	Autoscaling bool
+	MinSize     int
+	MaxSize     int
+}
+
+// HibernationStore provides an interface for cluster hibernation state persistence.
+type HibernationStore interface {
+	// SaveHibernatedNodePools records the scaling settings of node pools of a hibernated cluster.
+	SaveHibernatedNodePools(ctx context.Context, clusterID uint, nodePools []HibernatedNodePool) error
+
+	// GetHibernatedNodePools returns the recorded scaling settings of node pools of a hibernated cluster.
+	GetHibernatedNodePools(ctx context.Context, clusterID uint) ([]HibernatedNodePool, error)
+
+	// DeleteHibernatedNodePools deletes the recorded scaling settings of node pools of a cluster.
+	DeleteHibernatedNodePools(ctx context.Context, clusterID uint) error
+
+	// GetHibernationSchedule returns the hibernation schedule of a cluster.
+	GetHibernationSchedule(ctx context.Context, clusterID uint) (HibernationSchedule, error)
+
+	// SetHibernationSchedule saves the hibernation schedule of a cluster.
+	SetHibernationSchedule(ctx context.Context, clusterID uint, schedule HibernationSchedule) error
+}
+
+// NotHibernatedError is returned when a cluster is expected to be hibernated, but it is not.
+type NotHibernatedError struct {
+	ID uint
+}
+
+// Error implements the error interface.
+func (NotHibernatedError) Error() string {
+	return "cluster is not hibernated"
+}
+
+// Details returns error details.
+func (e NotHibernatedError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ID}
+}
+
+// Conflict tells a client that this error is related to a conflicting request.
+// Can be used to translate the error to status codes for example.
+func (NotHibernatedError) Conflict() bool {
+	return true
+}
+
+// ServiceError tells the consumer whether this error is caused by invalid input supplied by the client.
+// Client errors are usually returned to the consumer without retrying the operation.
+func (NotHibernatedError) ServiceError() bool {
+	return true
+}
+
+// HibernateCluster scales every node pool of a cluster to zero.
+func (s service) HibernateCluster(ctx context.Context, clusterID uint, options HibernateClusterOptions) (string, error) {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.checkCluster(cluster); err != nil {
+		return "", err
+	}
+
+	service, err := s.getDistributionService(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	return service.HibernateCluster(ctx, clusterID, options)
+}
+
+// ResumeCluster restores the node pools of a hibernated cluster.
+func (s service) ResumeCluster(ctx context.Context, clusterID uint) (string, error) {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	if cluster.Status != Hibernated {
+		return "", errors.WithStack(NotHibernatedError{ID: cluster.ID})
+	}
+
+	service, err := s.getDistributionService(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	return service.ResumeCluster(ctx, clusterID)
+}
+
+// GetHibernationSchedule returns the hibernation schedule of a cluster.
+func (s service) GetHibernationSchedule(ctx context.Context, clusterID uint) (HibernationSchedule, error) {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return HibernationSchedule{}, err
+	}
+
+	service, err := s.getDistributionService(cluster)
+	if err != nil {
+		return HibernationSchedule{}, err
+	}
+
+	return service.GetHibernationSchedule(ctx, clusterID)
+}
+
+// SetHibernationSchedule sets the hibernation schedule of a cluster.
+func (s service) SetHibernationSchedule(ctx context.Context, clusterID uint, schedule HibernationSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	service, err := s.getDistributionService(cluster)
+	if err != nil {
+		return err
+	}
+
+	return service.SetHibernationSchedule(ctx, clusterID, schedule)
+}
@@ -0,0 +1,142 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHibernationSchedule_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, HibernationSchedule{}.Validate())
+		assert.NoError(t, HibernationSchedule{Hibernate: "0 20 * * 1-5", Resume: "0 7 * * 1-5"}.Validate())
+		assert.NoError(t, HibernationSchedule{Hibernate: "@daily"}.Validate())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		err := HibernationSchedule{Hibernate: "every evening", Resume: "0 25 * * *"}.Validate()
+		require.Error(t, err)
+
+		var validationErr ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Len(t, validationErr.Violations(), 2)
+	})
+}
+
+func TestService_HibernateCluster(t *testing.T) {
+	t.Run("ClusterNotReady", func(t *testing.T) {
+		ctx := context.Background()
+
+		clusterStore := new(MockStore)
+		clusterStore.On("GetCluster", ctx, uint(1)).Return(Cluster{ID: 1, Status: Updating, Distribution: "eks"}, nil)
+
+		distribution := new(MockService)
+
+		service := NewService(clusterStore, nil, nil, map[string]Service{"eks": distribution}, nil, nil, nil, nil, nil)
+
+		_, err := service.HibernateCluster(ctx, 1, HibernateClusterOptions{})
+		require.Error(t, err)
+
+		assert.True(t, errors.As(err, &NotReadyError{}))
+
+		clusterStore.AssertExpectations(t)
+		distribution.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		ctx := context.Background()
+
+		clusterStore := new(MockStore)
+		clusterStore.On("GetCluster", ctx, uint(1)).Return(Cluster{ID: 1, Status: Running, Distribution: "eks"}, nil)
+
+		options := HibernateClusterOptions{KeepNodePool: "system"}
+
+		distribution := new(MockService)
+		distribution.On("HibernateCluster", ctx, uint(1), options).Return("process", nil)
+
+		service := NewService(clusterStore, nil, nil, map[string]Service{"eks": distribution}, nil, nil, nil, nil, nil)
+
+		processID, err := service.HibernateCluster(ctx, 1, options)
+		require.NoError(t, err)
+
+		assert.Equal(t, "process", processID)
+
+		clusterStore.AssertExpectations(t)
+		distribution.AssertExpectations(t)
+	})
+}
+
+func TestService_ResumeCluster(t *testing.T) {
+	t.Run("ClusterNotHibernated", func(t *testing.T) {
+		ctx := context.Background()
+
+		clusterStore := new(MockStore)
+		clusterStore.On("GetCluster", ctx, uint(1)).Return(Cluster{ID: 1, Status: Running, Distribution: "eks"}, nil)
+
+		distribution := new(MockService)
+
+		service := NewService(clusterStore, nil, nil, map[string]Service{"eks": distribution}, nil, nil, nil, nil, nil)
+
+		_, err := service.ResumeCluster(ctx, 1)
+		require.Error(t, err)
+
+		assert.True(t, errors.Is(err, NotHibernatedError{ID: 1}))
+
+		clusterStore.AssertExpectations(t)
+		distribution.AssertExpectations(t)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		ctx := context.Background()
+
+		clusterStore := new(MockStore)
+		clusterStore.On("GetCluster", ctx, uint(1)).Return(Cluster{ID: 1, Status: Hibernated, Distribution: "eks"}, nil)
+
+		distribution := new(MockService)
+		distribution.On("ResumeCluster", ctx, uint(1)).Return("process", nil)
+
+		service := NewService(clusterStore, nil, nil, map[string]Service{"eks": distribution}, nil, nil, nil, nil, nil)
+
+		processID, err := service.ResumeCluster(ctx, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, "process", processID)
+
+		clusterStore.AssertExpectations(t)
+		distribution.AssertExpectations(t)
+	})
+}
+
+func TestService_SetHibernationSchedule(t *testing.T) {
+	ctx := context.Background()
+
+	clusterStore := new(MockStore)
+	distribution := new(MockService)
+
+	service := NewService(clusterStore, nil, nil, map[string]Service{"eks": distribution}, nil, nil, nil, nil, nil)
+
+	err := service.SetHibernationSchedule(ctx, 1, HibernationSchedule{Hibernate: "invalid"})
+	require.Error(t, err)
+
+	var validationErr ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	clusterStore.AssertExpectations(t)
+	distribution.AssertExpectations(t)
+}
@@ -0,0 +1,124 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/banzaicloud/pipeline/internal/database/sql/json"
+)
+
+// Node taint effects.
+const (
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+	TaintEffectNoExecute        = "NoExecute"
+)
+
+// Taint describes a taint that should be applied to every node in a node pool.
+type Taint struct {
+	Key    string `json:"key" yaml:"key" mapstructure:"key"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty" mapstructure:"value"`
+	Effect string `json:"effect" yaml:"effect" mapstructure:"effect"`
+}
+
+// String returns the taint in the "key=value:effect" format accepted by the kubelet.
+func (t Taint) String() string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+// Taints is a list of node taints that can be persisted in a single column.
+type Taints []Taint
+
+// Value implements the driver.Valuer interface.
+func (t Taints) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	return json.Value(t)
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *Taints) Scan(src interface{}) error {
+	if src == nil {
+		*t = nil
+
+		return nil
+	}
+
+	return json.Scan(src, t)
+}
+
+// ValidateTaints validates a list of node taints and returns the violations.
+func ValidateTaints(taints []Taint) []string {
+	var violations []string
+
+	seen := make(map[string]bool, len(taints))
+
+	for _, taint := range taints {
+		if taint.Key == "" {
+			violations = append(violations, "taint key cannot be empty")
+
+			continue
+		}
+
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			violations = append(violations, fmt.Sprintf("invalid key for taint %q: %s", taint.Key, msg))
+		}
+
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			violations = append(violations, fmt.Sprintf("invalid value for taint %q: %s", taint.Key, msg))
+		}
+
+		switch taint.Effect {
+		case TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute:
+		default:
+			violations = append(violations, fmt.Sprintf("invalid effect %q for taint %q", taint.Effect, taint.Key))
+		}
+
+		id := taint.Key + ":" + taint.Effect
+		if seen[id] {
+			violations = append(violations, fmt.Sprintf("duplicate taint %q with effect %q", taint.Key, taint.Effect))
+		}
+
+		seen[id] = true
+	}
+
+	return violations
+}
+
+// ValidateNodePoolTaints validates the taints of a node pool.
+// The returned error lists the violations in its message in a readable format for legacy APIs.
+func ValidateNodePoolTaints(nodePoolName string, taints []Taint) error {
+	violations := ValidateTaints(taints)
+	if len(violations) > 0 {
+		return errors.NewWithDetails(
+			fmt.Sprintf("invalid taints on %s node pool: %s", nodePoolName, strings.Join(violations, ", ")),
+			"nodePool", nodePoolName,
+		)
+	}
+
+	return nil
+}
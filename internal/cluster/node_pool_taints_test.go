@@ -0,0 +1,90 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaint_String(t *testing.T) {
+	assert.Equal(t, "dedicated=gpu:NoSchedule", Taint{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}.String())
+	assert.Equal(t, "batch:NoExecute", Taint{Key: "batch", Effect: TaintEffectNoExecute}.String())
+}
+
+func TestValidateTaints(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		violations := ValidateTaints([]Taint{
+			{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+			{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoExecute},
+			{Key: "example.com/dedicated", Effect: TaintEffectPreferNoSchedule},
+		})
+
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		violations := ValidateTaints([]Taint{
+			{Value: "gpu", Effect: TaintEffectNoSchedule},
+			{Key: "dedicated", Value: "gpu", Effect: "Never"},
+			{Key: "batch", Effect: TaintEffectNoSchedule},
+			{Key: "batch", Value: "true", Effect: TaintEffectNoSchedule},
+		})
+
+		assert.Equal(
+			t,
+			[]string{
+				"taint key cannot be empty",
+				"invalid effect \"Never\" for taint \"dedicated\"",
+				"duplicate taint \"batch\" with effect \"NoSchedule\"",
+			},
+			violations,
+		)
+	})
+
+	t.Run("InvalidKeyAndValue", func(t *testing.T) {
+		violations := ValidateTaints([]Taint{
+			{Key: "dedicated?", Value: "gpu", Effect: TaintEffectNoSchedule},
+			{Key: "gpu", Value: "nvidia tesla", Effect: TaintEffectNoSchedule},
+		})
+
+		require.Len(t, violations, 2)
+		assert.Contains(t, violations[0], "invalid key for taint \"dedicated?\"")
+		assert.Contains(t, violations[1], "invalid value for taint \"gpu\"")
+	})
+}
+
+func TestTaints_ValueScan(t *testing.T) {
+	taints := Taints{{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}}
+
+	value, err := taints.Value()
+	require.NoError(t, err)
+
+	var scanned Taints
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, taints, scanned)
+
+	value, err = Taints{}.Value()
+	require.NoError(t, err)
+
+	require.NoError(t, scanned.Scan(value))
+	assert.NotNil(t, scanned)
+	assert.Empty(t, scanned)
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+}
@@ -44,6 +44,38 @@ func (v NodePoolValidators) ValidateNew(ctx context.Context, cluster Cluster, ra
 	return nil
 }
 
+// ValidateUpdate validates a node pool update descriptor
+// with the validators implementing NodePoolUpdateValidator.
+func (v NodePoolValidators) ValidateUpdate(
+	ctx context.Context,
+	cluster Cluster,
+	nodePoolName string,
+	rawNodePoolUpdate RawNodePoolUpdate,
+) error {
+	var violations []string
+
+	for _, validator := range v {
+		updateValidator, ok := validator.(NodePoolUpdateValidator)
+		if !ok {
+			continue
+		}
+
+		err := updateValidator.ValidateUpdate(ctx, cluster, nodePoolName, rawNodePoolUpdate)
+		if err != nil {
+			violations = append(violations, unwrapViolations(err)...)
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.WithStack(ValidationError{
+			message:    "invalid node pool update",
+			violations: violations,
+		})
+	}
+
+	return nil
+}
+
 type commonNodePoolValidator struct {
 	labelValidator LabelValidator
 }
@@ -84,6 +116,8 @@ func (v commonNodePoolValidator) ValidateNew(_ context.Context, _ Cluster, rawNo
 		}
 	}
 
+	violations = append(violations, ValidateTaints(rawNodePool.GetTaints())...)
+
 	if len(violations) > 0 {
 		return errors.WithStack(ValidationError{
 			message:    "invalid node pool",
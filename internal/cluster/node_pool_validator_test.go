@@ -56,6 +56,47 @@ func TestNodePoolValidators_Validate(t *testing.T) {
 	validator3.AssertExpectations(t)
 }
 
+type testNodePoolUpdateValidator struct {
+	err error
+}
+
+func (v testNodePoolUpdateValidator) ValidateNew(_ context.Context, _ Cluster, _ NewRawNodePool) error {
+	return nil
+}
+
+func (v testNodePoolUpdateValidator) ValidateUpdate(_ context.Context, _ Cluster, _ string, _ RawNodePoolUpdate) error {
+	return v.err
+}
+
+func TestNodePoolValidators_ValidateUpdate(t *testing.T) {
+	ctx := context.Background()
+	cluster := Cluster{}
+	nodePoolUpdate := RawNodePoolUpdate{}
+
+	// Validators not implementing NodePoolUpdateValidator are skipped
+	validator1 := new(MockNodePoolValidator)
+
+	validator2 := testNodePoolUpdateValidator{err: NewValidationError("invalid node pool", []string{"invalid something"})}
+	validator3 := testNodePoolUpdateValidator{err: errors.New("invalid node pool something")}
+	validator4 := testNodePoolUpdateValidator{}
+
+	validator := NodePoolValidators{validator1, validator2, validator3, validator4}
+
+	err := validator.ValidateUpdate(ctx, cluster, "pool0", nodePoolUpdate)
+	require.Error(t, err)
+
+	var verr ValidationError
+
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(
+		t,
+		[]string{"invalid something", "invalid node pool something"},
+		verr.Violations(),
+	)
+
+	validator1.AssertExpectations(t)
+}
+
 func TestNewCommonNodePoolValidator_ValidateNew(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
 		const labelKey = "key"
@@ -114,6 +155,32 @@ func TestNewCommonNodePoolValidator_ValidateNew(t *testing.T) {
 		labelValidator.AssertExpectations(t)
 	})
 
+	t.Run("InvalidTaints", func(t *testing.T) {
+		nodePool := NewRawNodePool{
+			"name": "pool0",
+			"taints": []interface{}{
+				map[string]interface{}{
+					"key":    "dedicated",
+					"effect": "NoWay",
+				},
+			},
+		}
+
+		validator := NewCommonNodePoolValidator(new(MockLabelValidator))
+
+		err := validator.ValidateNew(context.Background(), Cluster{}, nodePool)
+		require.Error(t, err)
+
+		var verr ValidationError
+
+		assert.True(t, errors.As(err, &verr))
+		assert.Equal(
+			t,
+			[]string{`invalid effect "NoWay" for taint "dedicated"`},
+			verr.Violations(),
+		)
+	})
+
 	t.Run("InvalidSingleLabelError", func(t *testing.T) {
 		const labelKey = "key"
 		const labelValue = "value"
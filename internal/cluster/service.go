@@ -24,17 +24,19 @@ import (
 
 // Cluster status constants
 const (
-	Creating = "CREATING"
-	Running  = "RUNNING"
-	Updating = "UPDATING"
-	Deleting = "DELETING"
-	Warning  = "WARNING"
-	Error    = "ERROR"
-
-	CreatingMessage = "Cluster creation is in progress"
-	RunningMessage  = "Cluster is running"
-	UpdatingMessage = "Update is in progress"
-	DeletingMessage = "Termination is in progress"
+	Creating   = "CREATING"
+	Running    = "RUNNING"
+	Updating   = "UPDATING"
+	Deleting   = "DELETING"
+	Warning    = "WARNING"
+	Error      = "ERROR"
+	Hibernated = "HIBERNATED"
+
+	CreatingMessage   = "Cluster creation is in progress"
+	RunningMessage    = "Cluster is running"
+	UpdatingMessage   = "Update is in progress"
+	DeletingMessage   = "Termination is in progress"
+	HibernatedMessage = "Cluster is hibernated"
 )
 
 // Cluster represents a generic, provider agnostic Kubernetes cluster structure.
@@ -54,6 +56,11 @@ type Cluster struct {
 
 	SecretID       brn.ResourceName
 	ConfigSecretID brn.ResourceName
+
+	Labels map[string]string
+
+	// DeletionProtection prevents the cluster from being deleted until it is disabled.
+	DeletionProtection bool
 }
 
 type Identifier struct {
@@ -76,11 +83,18 @@ type Store interface {
 
 	// SetStatus sets the cluster status.
 	SetStatus(ctx context.Context, id uint, status string, statusMessage string) error
+
+	// SetLabels replaces the labels of a cluster.
+	SetLabels(ctx context.Context, id uint, labels map[string]string) error
+
+	// SetDeletionProtection enables or disables the deletion protection of a cluster.
+	SetDeletionProtection(ctx context.Context, id uint, enabled bool) error
 }
 
 // +testify:mock:testOnly=true
 type ClusterGroupManager interface {
 	ValidateClusterRemoval(ctx context.Context, clusterID uint) error
+	ReconcileClusterMembership(ctx context.Context, clusterID uint) error
 }
 
 // ClusterDeleteNotPermittedError is returned if a cluster cannot be deleted.
@@ -197,10 +211,38 @@ type Service interface {
 	CreateNodePool(ctx context.Context, clusterID uint, rawNodePool NewRawNodePool) error
 
 	// UpdateNodePool updates an existing node pool in a cluster.
-	UpdateNodePool(ctx context.Context, clusterID uint, nodePoolName string, rawNodePoolUpdate RawNodePoolUpdate) (processID string, err error)
+	//
+	// It returns the changed node pool properties that require replacing the existing nodes.
+	UpdateNodePool(
+		ctx context.Context,
+		clusterID uint,
+		nodePoolName string,
+		rawNodePoolUpdate RawNodePoolUpdate,
+	) (processID string, nodeReplacementChanges []string, err error)
 
 	// DeleteNodePool deletes a node pool from a cluster.
 	DeleteNodePool(ctx context.Context, clusterID uint, name string) (deleted bool, err error)
+
+	// UpgradeCluster upgrades the Kubernetes version of a cluster.
+	UpgradeCluster(ctx context.Context, clusterID uint, kubernetesVersion string) (processID string, err error)
+
+	// UpdateClusterLabels replaces the labels of a cluster.
+	UpdateClusterLabels(ctx context.Context, clusterID uint, labels map[string]string) error
+
+	// HibernateCluster records the scaling settings of every node pool in a cluster and scales them to zero.
+	HibernateCluster(ctx context.Context, clusterID uint, options HibernateClusterOptions) (processID string, err error)
+
+	// ResumeCluster restores the recorded scaling settings of every node pool in a hibernated cluster.
+	ResumeCluster(ctx context.Context, clusterID uint) (processID string, err error)
+
+	// GetHibernationSchedule returns the hibernation schedule of a cluster.
+	GetHibernationSchedule(ctx context.Context, clusterID uint) (schedule HibernationSchedule, err error)
+
+	// SetHibernationSchedule sets the hibernation schedule of a cluster.
+	SetHibernationSchedule(ctx context.Context, clusterID uint, schedule HibernationSchedule) error
+
+	// SetDeletionProtection enables or disables the deletion protection of a cluster.
+	SetDeletionProtection(ctx context.Context, clusterID uint, enabled bool) error
 }
 
 // DeleteClusterOptions represents cluster deletion options.
@@ -219,6 +261,14 @@ type service struct {
 	nodePoolValidator NodePoolValidator
 	nodePoolProcessor NodePoolProcessor
 	nodePoolManager   NodePoolManager
+
+	labelValidator ClusterLabelValidator
+}
+
+// ClusterLabelValidator validates the labels of an existing cluster.
+type ClusterLabelValidator interface {
+	// ValidateClusterLabels validates the new labels of a cluster.
+	ValidateClusterLabels(ctx context.Context, cluster Cluster, labels map[string]string) error
 }
 
 // Manager provides lower level cluster operations for Service.
@@ -242,6 +292,7 @@ func NewService(
 	nodePoolValidator NodePoolValidator,
 	nodePoolProcessor NodePoolProcessor,
 	nodePoolManager NodePoolManager,
+	labelValidator ClusterLabelValidator,
 ) Service {
 	return service{
 		clusters:            clusters,
@@ -254,6 +305,8 @@ func NewService(
 		nodePoolValidator: nodePoolValidator,
 		nodePoolProcessor: nodePoolProcessor,
 		nodePoolManager:   nodePoolManager,
+
+		labelValidator: labelValidator,
 	}
 }
 
@@ -277,6 +330,10 @@ func (s service) DeleteCluster(ctx context.Context, clusterIdentifier Identifier
 		return false, err
 	}
 
+	if err := CheckDeletionProtection(c); err != nil {
+		return false, err
+	}
+
 	err = s.clusterGroupManager.ValidateClusterRemoval(ctx, clusterIdentifier.ClusterID)
 	if err != nil {
 		return false, ClusterDeleteNotPermittedError{
@@ -298,6 +355,52 @@ func (s service) DeleteCluster(ctx context.Context, clusterIdentifier Identifier
 	return false, nil
 }
 
+// UpgradeCluster upgrades the Kubernetes version of a cluster.
+func (s service) UpgradeCluster(ctx context.Context, clusterID uint, kubernetesVersion string) (string, error) {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.checkCluster(cluster); err != nil {
+		return "", err
+	}
+
+	service, err := s.getDistributionService(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	return service.UpgradeCluster(ctx, clusterID, kubernetesVersion)
+}
+
+// UpdateClusterLabels replaces the labels of a cluster.
+func (s service) UpdateClusterLabels(ctx context.Context, clusterID uint, labels map[string]string) error {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateClusterLabels(labels); err != nil {
+		return err
+	}
+
+	if err := s.labelValidator.ValidateClusterLabels(ctx, cluster, labels); err != nil {
+		return err
+	}
+
+	if err := s.clusters.SetLabels(ctx, cluster.ID, labels); err != nil {
+		return err
+	}
+
+	err = s.clusterGroupManager.ReconcileClusterMembership(ctx, cluster.ID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to reconcile cluster group membership")
+	}
+
+	return nil
+}
+
 // NotSupportedDistributionError is returned if an API does not support a certain distribution.
 type NotSupportedDistributionError struct {
 	ID           uint
@@ -94,6 +94,23 @@ func (n NewRawNodePool) GetLabels() map[string]string {
 	return labels
 }
 
+// GetTaints returns taints that are/should be applied to every node in the pool.
+func (n NewRawNodePool) GetTaints() []Taint {
+	var taints []Taint
+
+	t, ok := n["taints"]
+	if !ok {
+		return nil
+	}
+
+	err := mapstructure.Decode(t, &taints)
+	if err != nil {
+		return nil
+	}
+
+	return taints
+}
+
 // RawNodePoolUpdate is an unstructured, distribution specific descriptor for a node pool update.
 type RawNodePoolUpdate map[string]interface{}
 
@@ -172,6 +189,14 @@ type NodePoolValidator interface {
 	ValidateNew(ctx context.Context, cluster Cluster, rawNodePool NewRawNodePool) error
 }
 
+// NodePoolUpdateValidator validates a node pool update descriptor.
+//
+// Node pool validators may optionally implement this interface.
+type NodePoolUpdateValidator interface {
+	// ValidateUpdate validates a node pool update descriptor.
+	ValidateUpdate(ctx context.Context, cluster Cluster, nodePoolName string, rawNodePoolUpdate RawNodePoolUpdate) error
+}
+
 // +testify:mock:testOnly=true
 
 // NodePoolProcessor processes a node pool descriptor.
@@ -244,34 +269,40 @@ func (s service) UpdateNodePool(
 	clusterID uint,
 	nodePoolName string,
 	rawNodePoolUpdate RawNodePoolUpdate,
-) (string, error) {
+) (string, []string, error) {
 	cluster, err := s.clusters.GetCluster(ctx, clusterID)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	if err := s.checkCluster(cluster); err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	service, err := s.getDistributionService(cluster)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	// TODO: move this to distribution level
 	exists, err := s.nodePools.NodePoolExists(ctx, clusterID, nodePoolName)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	if !exists {
-		return "", errors.WithStack(NodePoolNotFoundError{
+		return "", nil, errors.WithStack(NodePoolNotFoundError{
 			ClusterID: clusterID,
 			NodePool:  nodePoolName,
 		})
 	}
 
+	if validator, ok := s.nodePoolValidator.(NodePoolUpdateValidator); ok {
+		if err := validator.ValidateUpdate(ctx, cluster, nodePoolName, rawNodePoolUpdate); err != nil {
+			return "", nil, err
+		}
+	}
+
 	return service.UpdateNodePool(ctx, clusterID, nodePoolName, rawNodePoolUpdate)
 }
 
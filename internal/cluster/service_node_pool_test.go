@@ -103,6 +103,37 @@ func TestNewRawNodePool(t *testing.T) {
 			assert.Equal(t, map[string]string{}, np.GetLabels())
 		})
 	})
+
+	t.Run("GetTaints", func(t *testing.T) {
+		t.Run("Struct", func(t *testing.T) {
+			np := NewRawNodePool{
+				"taints": []Taint{
+					{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+				},
+			}
+
+			assert.Equal(t, []Taint{{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}}, np.GetTaints())
+		})
+
+		t.Run("Interface", func(t *testing.T) {
+			np := NewRawNodePool{
+				"taints": []interface{}{
+					map[string]interface{}{
+						"key":    "dedicated",
+						"effect": "NoExecute",
+					},
+				},
+			}
+
+			assert.Equal(t, []Taint{{Key: "dedicated", Effect: TaintEffectNoExecute}}, np.GetTaints())
+		})
+
+		t.Run("Empty", func(t *testing.T) {
+			np := NewRawNodePool{}
+
+			assert.Empty(t, np.GetTaints())
+		})
+	})
 }
 
 type nodePoolStub struct {
@@ -144,7 +175,7 @@ func TestNodePoolService_CreateNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		rawNewNodePool := NewRawNodePool{
 			"name": "pool0",
@@ -184,7 +215,7 @@ func TestNodePoolService_CreateNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		rawNewNodePool := NewRawNodePool{
 			"name": "pool0",
@@ -235,7 +266,7 @@ func TestNodePoolService_CreateNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		err := service.CreateNodePool(ctx, 1, rawNewNodePool)
 		require.Error(t, err)
@@ -281,7 +312,7 @@ func TestNodePoolService_CreateNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		err := service.CreateNodePool(ctx, 1, rawNewNodePool)
 		require.Error(t, err)
@@ -332,7 +363,7 @@ func TestNodePoolService_CreateNodePool(t *testing.T) {
 
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		err := service.CreateNodePool(ctx, 1, rawNewNodePool)
 		require.NoError(t, err)
@@ -361,11 +392,11 @@ func TestNodePoolService_UpdateNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		rawNodePoolUpdate := RawNodePoolUpdate{}
 
-		_, err := service.UpdateNodePool(ctx, 1, "pool0", rawNodePoolUpdate)
+		_, _, err := service.UpdateNodePool(ctx, 1, "pool0", rawNodePoolUpdate)
 		require.Error(t, err)
 
 		assert.True(t, errors.Is(err, NotFoundError{ClusterID: 1}))
@@ -399,11 +430,11 @@ func TestNodePoolService_UpdateNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, map[string]Service{}, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, map[string]Service{}, nodePoolStore, validator, processor, manager, nil)
 
 		rawNodePoolUpdate := RawNodePoolUpdate{}
 
-		_, err := service.UpdateNodePool(ctx, 1, "pool0", rawNodePoolUpdate)
+		_, _, err := service.UpdateNodePool(ctx, 1, "pool0", rawNodePoolUpdate)
 		require.Error(t, err)
 
 		assert.True(t, errors.As(err, &NotSupportedDistributionError{}))
@@ -446,11 +477,11 @@ func TestNodePoolService_UpdateNodePool(t *testing.T) {
 			cluster.Distribution: nil,
 		}
 
-		service := NewService(clusterStore, nil, clusterGroupManager, distributions, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, distributions, nodePoolStore, validator, processor, manager, nil)
 
 		rawNodePoolUpdate := RawNodePoolUpdate{}
 
-		_, err := service.UpdateNodePool(ctx, 1, nodePoolName, rawNodePoolUpdate)
+		_, _, err := service.UpdateNodePool(ctx, 1, nodePoolName, rawNodePoolUpdate)
 		require.Error(t, err)
 
 		assert.True(t, errors.Is(err, NodePoolNotFoundError{ClusterID: 1, NodePool: nodePoolName}))
@@ -495,15 +526,15 @@ func TestNodePoolService_UpdateNodePool(t *testing.T) {
 
 		distErr := errors.NewPlain("distribution error")
 
-		distribution.On("UpdateNodePool", ctx, cluster.ID, nodePoolName, rawNodePoolUpdate).Return("", distErr)
+		distribution.On("UpdateNodePool", ctx, cluster.ID, nodePoolName, rawNodePoolUpdate).Return("", nil, distErr)
 
 		distributions := map[string]Service{
 			cluster.Distribution: distribution,
 		}
 
-		service := NewService(clusterStore, nil, clusterGroupManager, distributions, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, distributions, nodePoolStore, validator, processor, manager, nil)
 
-		_, err := service.UpdateNodePool(ctx, cluster.ID, nodePoolName, rawNodePoolUpdate)
+		_, _, err := service.UpdateNodePool(ctx, cluster.ID, nodePoolName, rawNodePoolUpdate)
 		require.Error(t, err)
 
 		assert.Equal(t, distErr, err)
@@ -547,17 +578,20 @@ func TestNodePoolService_UpdateNodePool(t *testing.T) {
 
 		rawNodePoolUpdate := RawNodePoolUpdate{}
 
-		distribution.On("UpdateNodePool", ctx, cluster.ID, nodePoolName, rawNodePoolUpdate).Return("pid", nil)
+		distribution.On("UpdateNodePool", ctx, cluster.ID, nodePoolName, rawNodePoolUpdate).Return("pid", []string{"instanceType"}, nil)
 
 		distributions := map[string]Service{
 			cluster.Distribution: distribution,
 		}
 
-		service := NewService(clusterStore, nil, clusterGroupManager, distributions, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, distributions, nodePoolStore, validator, processor, manager, nil)
 
-		_, err := service.UpdateNodePool(ctx, cluster.ID, nodePoolName, rawNodePoolUpdate)
+		processID, nodeReplacementChanges, err := service.UpdateNodePool(ctx, cluster.ID, nodePoolName, rawNodePoolUpdate)
 		require.NoError(t, err)
 
+		assert.Equal(t, "pid", processID)
+		assert.Equal(t, []string{"instanceType"}, nodeReplacementChanges)
+
 		clusterStore.AssertExpectations(t)
 		nodePoolStore.AssertExpectations(t)
 		validator.AssertExpectations(t)
@@ -583,7 +617,7 @@ func TestNodePoolService_DeleteNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		_, err := service.DeleteNodePool(ctx, 1, "pool0")
 		require.Error(t, err)
@@ -619,7 +653,7 @@ func TestNodePoolService_DeleteNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		_, err := service.DeleteNodePool(ctx, 1, "pool0")
 		require.Error(t, err)
@@ -659,7 +693,7 @@ func TestNodePoolService_DeleteNodePool(t *testing.T) {
 		manager := new(MockNodePoolManager)
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		deleted, err := service.DeleteNodePool(ctx, 1, nodePoolName)
 		require.NoError(t, err)
@@ -703,7 +737,7 @@ func TestNodePoolService_DeleteNodePool(t *testing.T) {
 
 		clusterGroupManager := new(MockClusterGroupManager)
 
-		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager)
+		service := NewService(clusterStore, nil, clusterGroupManager, nil, nodePoolStore, validator, processor, manager, nil)
 
 		deleted, err := service.DeleteNodePool(ctx, 1, nodePoolName)
 		require.NoError(t, err)
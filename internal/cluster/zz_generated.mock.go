@@ -70,8 +70,113 @@ func (_m *MockService) DeleteNodePool(ctx context.Context, clusterID uint, name
 	return r0, r1
 }
 
+// GetHibernationSchedule provides a mock function.
+func (_m *MockService) GetHibernationSchedule(ctx context.Context, clusterID uint) (schedule HibernationSchedule, err error) {
+	ret := _m.Called(ctx, clusterID)
+
+	var r0 HibernationSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, uint) HibernationSchedule); ok {
+		r0 = rf(ctx, clusterID)
+	} else {
+		r0 = ret.Get(0).(HibernationSchedule)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, clusterID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HibernateCluster provides a mock function.
+func (_m *MockService) HibernateCluster(ctx context.Context, clusterID uint, options HibernateClusterOptions) (processID string, err error) {
+	ret := _m.Called(ctx, clusterID, options)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, uint, HibernateClusterOptions) string); ok {
+		r0 = rf(ctx, clusterID, options)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, HibernateClusterOptions) error); ok {
+		r1 = rf(ctx, clusterID, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResumeCluster provides a mock function.
+func (_m *MockService) ResumeCluster(ctx context.Context, clusterID uint) (processID string, err error) {
+	ret := _m.Called(ctx, clusterID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, uint) string); ok {
+		r0 = rf(ctx, clusterID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, clusterID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetDeletionProtection provides a mock function.
+func (_m *MockService) SetDeletionProtection(ctx context.Context, clusterID uint, enabled bool) error {
+	ret := _m.Called(ctx, clusterID, enabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, bool) error); ok {
+		r0 = rf(ctx, clusterID, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetHibernationSchedule provides a mock function.
+func (_m *MockService) SetHibernationSchedule(ctx context.Context, clusterID uint, schedule HibernationSchedule) error {
+	ret := _m.Called(ctx, clusterID, schedule)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, HibernationSchedule) error); ok {
+		r0 = rf(ctx, clusterID, schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateClusterLabels provides a mock function.
+func (_m *MockService) UpdateClusterLabels(ctx context.Context, clusterID uint, labels map[string]string) error {
+	ret := _m.Called(ctx, clusterID, labels)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, map[string]string) error); ok {
+		r0 = rf(ctx, clusterID, labels)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateNodePool provides a mock function.
-func (_m *MockService) UpdateNodePool(ctx context.Context, clusterID uint, nodePoolName string, rawNodePoolUpdate RawNodePoolUpdate) (processID string, err error) {
+func (_m *MockService) UpdateNodePool(ctx context.Context, clusterID uint, nodePoolName string, rawNodePoolUpdate RawNodePoolUpdate) (processID string, nodeReplacementChanges []string, err error) {
 	ret := _m.Called(ctx, clusterID, nodePoolName, rawNodePoolUpdate)
 
 	var r0 string
@@ -81,9 +186,39 @@ func (_m *MockService) UpdateNodePool(ctx context.Context, clusterID uint, nodeP
 		r0 = ret.Get(0).(string)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uint, string, RawNodePoolUpdate) error); ok {
+	var r1 []string
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, RawNodePoolUpdate) []string); ok {
 		r1 = rf(ctx, clusterID, nodePoolName, rawNodePoolUpdate)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uint, string, RawNodePoolUpdate) error); ok {
+		r2 = rf(ctx, clusterID, nodePoolName, rawNodePoolUpdate)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpgradeCluster provides a mock function.
+func (_m *MockService) UpgradeCluster(ctx context.Context, clusterID uint, kubernetesVersion string) (processID string, err error) {
+	ret := _m.Called(ctx, clusterID, kubernetesVersion)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) string); ok {
+		r0 = rf(ctx, clusterID, kubernetesVersion)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, clusterID, kubernetesVersion)
 	} else {
 		r1 = ret.Error(1)
 	}
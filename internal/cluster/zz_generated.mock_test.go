@@ -117,6 +117,34 @@ func (_m *MockStore) GetClusterByName(ctx context.Context, orgID uint, clusterNa
 	return r0, r1
 }
 
+// SetDeletionProtection provides a mock function.
+func (_m *MockStore) SetDeletionProtection(ctx context.Context, id uint, enabled bool) error {
+	ret := _m.Called(ctx, id, enabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, bool) error); ok {
+		r0 = rf(ctx, id, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLabels provides a mock function.
+func (_m *MockStore) SetLabels(ctx context.Context, id uint, labels map[string]string) error {
+	ret := _m.Called(ctx, id, labels)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, map[string]string) error); ok {
+		r0 = rf(ctx, id, labels)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetStatus provides a mock function.
 func (_m *MockStore) SetStatus(ctx context.Context, id uint, status string, statusMessage string) error {
 	ret := _m.Called(ctx, id, status, statusMessage)
@@ -136,6 +164,20 @@ type MockClusterGroupManager struct {
 	mock.Mock
 }
 
+// ReconcileClusterMembership provides a mock function.
+func (_m *MockClusterGroupManager) ReconcileClusterMembership(ctx context.Context, clusterID uint) error {
+	ret := _m.Called(ctx, clusterID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, clusterID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateClusterRemoval provides a mock function.
 func (_m *MockClusterGroupManager) ValidateClusterRemoval(ctx context.Context, clusterID uint) error {
 	ret := _m.Called(ctx, clusterID)
@@ -21,9 +21,11 @@ import (
 // Cluster
 type Cluster interface {
 	GetID() uint
+	GetOrganizationId() uint
 	GetCloud() string
 	GetDistribution() string
 	GetName() string
+	GetLocation() string
 	GetK8sConfig() ([]byte, error)
 	GetStatus() (*cluster.GetClusterStatusResponse, error)
 	IsReady() (bool, error)
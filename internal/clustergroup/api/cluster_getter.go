@@ -23,4 +23,5 @@ type ClusterGetter interface {
 	GetClusterByIDOnly(ctx context.Context, clusterID uint) (Cluster, error)
 	GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (Cluster, error)
 	GetClusterByName(ctx context.Context, organizationID uint, clusterName string) (Cluster, error)
+	GetClusters(ctx context.Context, organizationID uint) ([]Cluster, error)
 }
@@ -20,8 +20,9 @@ import (
 
 // CreateRequest describes fields of a create cluster group request
 type CreateRequest struct {
-	Name    string `json:"name" yaml:"name" example:"cluster_group_name"`
-	Members []uint `json:"members" yaml:"members"`
+	Name           string          `json:"name" yaml:"name" example:"cluster_group_name"`
+	Members        []uint          `json:"members" yaml:"members"`
+	MemberSelector *MemberSelector `json:"memberSelector,omitempty" yaml:"memberSelector,omitempty"`
 }
 
 // Validate validates CreateRequest
@@ -30,10 +31,7 @@ func (g *CreateRequest) Validate() error {
 		return errors.New("cluster group name is empty")
 	}
 
-	if len(g.Members) == 0 {
-		return errors.New("there should be at least one cluster member")
-	}
-	return nil
+	return validateMembers(g.Members, g.MemberSelector)
 }
 
 // CreateResponse describes fields of a create cluster group response
@@ -44,8 +42,9 @@ type CreateResponse struct {
 
 // UpdateRequest describes fields of a update cluster group request
 type UpdateRequest struct {
-	Name    string `json:"name" yaml:"name" example:"cluster_group_name"`
-	Members []uint `json:"members,omitempty" yaml:"members"`
+	Name           string          `json:"name" yaml:"name" example:"cluster_group_name"`
+	Members        []uint          `json:"members,omitempty" yaml:"members"`
+	MemberSelector *MemberSelector `json:"memberSelector,omitempty" yaml:"memberSelector,omitempty"`
 }
 
 // Validate validates UpdateRequest
@@ -54,7 +53,19 @@ func (g *UpdateRequest) Validate() error {
 		return errors.New("cluster group name is empty")
 	}
 
-	if len(g.Members) == 0 {
+	return validateMembers(g.Members, g.MemberSelector)
+}
+
+func validateMembers(members []uint, memberSelector *MemberSelector) error {
+	if memberSelector != nil {
+		if len(members) > 0 {
+			return errors.New("cluster members cannot be listed when a member selector is specified")
+		}
+
+		return memberSelector.Validate()
+	}
+
+	if len(members) == 0 {
 		return errors.New("there should be at least one cluster member")
 	}
 	return nil
@@ -83,6 +94,7 @@ type ClusterGroup struct {
 	OrganizationID  uint             `json:"organizationId" yaml:"organizationId"`
 	Members         []Member         `json:"members,omitempty" yaml:"members"`
 	EnabledFeatures []string         `json:"enabledFeatures,omitempty" yaml:"enabledFeatures"`
+	MemberSelector  *MemberSelector  `json:"memberSelector,omitempty" yaml:"memberSelector,omitempty"`
 	Clusters        map[uint]Cluster `json:"-" yaml:"-"`
 }
 
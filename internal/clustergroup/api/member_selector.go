@@ -0,0 +1,70 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+// MemberSelector selects the member clusters of a cluster group dynamically.
+// A cluster is selected when it matches every non-empty field of the selector.
+type MemberSelector struct {
+	Cloud         string `json:"cloud,omitempty" yaml:"cloud,omitempty" example:"amazon"`
+	Distribution  string `json:"distribution,omitempty" yaml:"distribution,omitempty" example:"eks"`
+	Location      string `json:"location,omitempty" yaml:"location,omitempty" example:"eu-west-1"`
+	LabelSelector string `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty" example:"env=prod"`
+}
+
+// Validate validates the member selector.
+func (s MemberSelector) Validate() error {
+	if s == (MemberSelector{}) {
+		return errors.New("member selector must specify at least one criterion")
+	}
+
+	_, err := cluster.ParseLabelSelector(s.LabelSelector)
+
+	return err
+}
+
+// Matches returns true if the cluster (with the given labels) matches the selector.
+func (s MemberSelector) Matches(c Cluster, labels map[string]string) (bool, error) {
+	if s.Cloud != "" && s.Cloud != c.GetCloud() {
+		return false, nil
+	}
+
+	if s.Distribution != "" && s.Distribution != c.GetDistribution() {
+		return false, nil
+	}
+
+	if s.Location != "" && s.Location != c.GetLocation() {
+		return false, nil
+	}
+
+	selector, err := cluster.ParseLabelSelector(s.LabelSelector)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to parse member label selector")
+	}
+
+	return cluster.Cluster{Labels: labels}.MatchesLabelSelector(selector), nil
+}
+
+// ClusterLabelGetter returns cluster labels.
+type ClusterLabelGetter interface {
+	// GetLabelsByOrganization returns the labels of every cluster in an organization indexed by cluster ID.
+	GetLabelsByOrganization(ctx context.Context, orgID uint) (map[uint]map[string]string, error)
+}
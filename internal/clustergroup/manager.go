@@ -16,6 +16,7 @@ package clustergroup
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"emperror.dev/emperror"
@@ -30,6 +31,7 @@ import (
 // Manager
 type Manager struct {
 	clusterGetter     api.ClusterGetter
+	clusterLabels     api.ClusterLabelGetter
 	cgRepo            *ClusterGroupRepository
 	logger            logrus.FieldLogger
 	errorHandler      emperror.Handler
@@ -39,6 +41,7 @@ type Manager struct {
 // NewManager returns a new Manager instance.
 func NewManager(
 	clusterGetter api.ClusterGetter,
+	clusterLabels api.ClusterLabelGetter,
 	repository *ClusterGroupRepository,
 	logger logrus.FieldLogger,
 	errorHandler emperror.Handler,
@@ -46,6 +49,7 @@ func NewManager(
 	featureHandlerMap := make(map[string]api.FeatureHandler, 0)
 	return &Manager{
 		clusterGetter:     clusterGetter,
+		clusterLabels:     clusterLabels,
 		cgRepo:            repository,
 		logger:            logger,
 		errorHandler:      errorHandler,
@@ -63,7 +67,7 @@ func isValidClusterStatus(clusterStatus *cluster.GetClusterStatusResponse) bool
 }
 
 // CreateClusterGroup creates a cluster group
-func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uint, members []uint) (*uint, error) {
+func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uint, members []uint, memberSelector *api.MemberSelector) (*uint, error) {
 	cgModel, err := g.cgRepo.FindOne(ClusterGroupModel{
 		OrganizationID: orgID,
 		Name:           name,
@@ -79,6 +83,105 @@ func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uin
 		})
 	}
 
+	memberClusterModels := make([]MemberClusterModel, 0)
+	if memberSelector != nil {
+		if err := validateMemberSelector(members, *memberSelector); err != nil {
+			return nil, err
+		}
+
+		selectedMembers, err := g.selectMembers(ctx, orgID, 0, *memberSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for clusterID := range selectedMembers {
+			memberClusterModels = append(memberClusterModels, MemberClusterModel{
+				ClusterID: clusterID,
+			})
+		}
+	} else {
+		var err error
+
+		memberClusterModels, err = g.getMemberModels(ctx, name, orgID, members)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cgId, err := g.cgRepo.Create(name, orgID, memberClusterModels, memberSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	// enable DeploymentFeature by default on every cluster group
+	deploymentFeature := &ClusterGroupFeatureModel{
+		Enabled:        true,
+		Name:           deployment.FeatureName,
+		ClusterGroupID: *cgId,
+	}
+	err = g.cgRepo.SaveFeature(deploymentFeature)
+	if err != nil {
+		return nil, err
+	}
+	return cgId, nil
+}
+
+// UpdateClusterGroup updates a cluster group
+func (g *Manager) UpdateClusterGroup(ctx context.Context, clusterGroupID uint, orgID uint, name string, members []uint, memberSelector *api.MemberSelector) error {
+	cgModel, err := g.cgRepo.FindOne(ClusterGroupModel{
+		ID:             clusterGroupID,
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		return err
+	}
+
+	existingClusterGroup := g.GetClusterGroupFromModel(ctx, cgModel, false)
+	var newMembers map[uint]api.Cluster
+	if memberSelector != nil {
+		if err := validateMemberSelector(members, *memberSelector); err != nil {
+			return err
+		}
+
+		newMembers, err = g.selectMembers(ctx, orgID, existingClusterGroup.Id, *memberSelector)
+	} else {
+		newMembers, err = g.getMembers(ctx, orgID, existingClusterGroup, members)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = g.validateBeforeClusterGroupUpdate(*existingClusterGroup, newMembers)
+	if err != nil {
+		return errors.WrapIf(err, "updating cluster group is not allowed")
+	}
+
+	err = g.cgRepo.UpdateMembers(existingClusterGroup, newMembers)
+	if err != nil {
+		return err
+	}
+
+	err = g.cgRepo.UpdateMemberSelector(existingClusterGroup.Id, memberSelector)
+	if err != nil {
+		return err
+	}
+
+	clusterGroup, err := g.GetClusterGroupByID(ctx, existingClusterGroup.Id, orgID)
+	if err != nil {
+		return err
+	}
+
+	// call feature handlers on members update
+	err = g.ReconcileFeatures(*clusterGroup, true)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getMemberModels validates the listed member clusters of a new cluster group.
+func (g *Manager) getMemberModels(ctx context.Context, name string, orgID uint, members []uint) ([]MemberClusterModel, error) {
 	memberClusterModels := make([]MemberClusterModel, 0)
 	for _, clusterID := range members {
 		var cluster api.Cluster
@@ -119,53 +222,29 @@ func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uin
 		}
 	}
 
-	cgId, err := g.cgRepo.Create(name, orgID, memberClusterModels)
-	if err != nil {
-		return nil, err
-	}
-
-	// enable DeploymentFeature by default on every cluster group
-	deploymentFeature := &ClusterGroupFeatureModel{
-		Enabled:        true,
-		Name:           deployment.FeatureName,
-		ClusterGroupID: *cgId,
-	}
-	err = g.cgRepo.SaveFeature(deploymentFeature)
-	if err != nil {
-		return nil, err
-	}
-	return cgId, nil
+	return memberClusterModels, nil
 }
 
-// UpdateClusterGroup updates a cluster group
-func (g *Manager) UpdateClusterGroup(ctx context.Context, clusterGroupID uint, orgID uint, name string, members []uint) error {
-	cgModel, err := g.cgRepo.FindOne(ClusterGroupModel{
-		ID:             clusterGroupID,
-		OrganizationID: orgID,
-	})
-	if err != nil {
-		return err
-	}
-
-	existingClusterGroup := g.GetClusterGroupFromModel(ctx, cgModel, false)
+// getMembers validates the listed member clusters of an existing cluster group.
+func (g *Manager) getMembers(ctx context.Context, orgID uint, existingClusterGroup *api.ClusterGroup, members []uint) (map[uint]api.Cluster, error) {
 	newMembers := make(map[uint]api.Cluster, 0)
 
 	for _, clusterID := range members {
 		var cluster api.Cluster
-		cluster, err = g.clusterGetter.GetClusterByID(ctx, orgID, clusterID)
+		cluster, err := g.clusterGetter.GetClusterByID(ctx, orgID, clusterID)
 		if err != nil {
-			return errors.WithStack(&memberClusterNotFoundError{
+			return nil, errors.WithStack(&memberClusterNotFoundError{
 				orgID:     orgID,
 				clusterID: clusterID,
 			})
 		}
 		if ok, err := g.isClusterMemberOfAClusterGroup(cluster.GetID(), existingClusterGroup.Id); ok {
-			return errors.WithStack(&memberClusterPartOfAClusterGroupError{
+			return nil, errors.WithStack(&memberClusterPartOfAClusterGroupError{
 				orgID:     orgID,
 				clusterID: clusterID,
 			})
 		} else if err != nil {
-			return errors.WithStack(err)
+			return nil, errors.WithStack(err)
 		}
 
 		clusterStatus, err := cluster.GetStatus()
@@ -177,39 +256,18 @@ func (g *Manager) UpdateClusterGroup(ctx context.Context, clusterGroupID uint, o
 				}).Info("Join cluster to group")
 				newMembers[cluster.GetID()] = cluster
 			} else {
-				return errors.WithStack(&unableToJoinMemberClusterError{
+				return nil, errors.WithStack(&unableToJoinMemberClusterError{
 					clusterID:     clusterID,
 					clusterName:   cluster.GetName(),
 					clusterStatus: clusterStatus.Status,
 				})
 			}
 		} else {
-			return errors.WrapIfWithDetails(err, "could not check cluster state", "clusterID", cluster.GetID())
+			return nil, errors.WrapIfWithDetails(err, "could not check cluster state", "clusterID", cluster.GetID())
 		}
 	}
 
-	err = g.validateBeforeClusterGroupUpdate(*existingClusterGroup, newMembers)
-	if err != nil {
-		return errors.WrapIf(err, "updating cluster group is not allowed")
-	}
-
-	err = g.cgRepo.UpdateMembers(existingClusterGroup, newMembers)
-	if err != nil {
-		return err
-	}
-
-	clusterGroup, err := g.GetClusterGroupByID(ctx, existingClusterGroup.Id, orgID)
-	if err != nil {
-		return err
-	}
-
-	// call feature handlers on members update
-	err = g.ReconcileFeatures(*clusterGroup, true)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return newMembers, nil
 }
 
 // DeleteClusterGroup deletes a cluster group by id
@@ -290,7 +348,8 @@ func (g *Manager) RemoveClusterFromGroup(ctx context.Context, clusterID uint) er
 		}
 	}
 
-	if len(newMembers) == 0 {
+	// cluster groups with a member selector are kept without members as well
+	if len(newMembers) == 0 && len(cgModel.MemberSelector) == 0 {
 		g.logger.Debug("delete cluster group before deleting it's last member")
 		err := g.DeleteClusterGroupByID(ctx, existingClusterGroup.OrganizationID, existingClusterGroup.Id)
 		if err != nil {
@@ -343,6 +402,15 @@ func (g *Manager) GetClusterGroupFromModel(ctx context.Context, cg *ClusterGroup
 	}
 	clusterGroup.EnabledFeatures = enabledFeatures
 
+	if len(cg.MemberSelector) > 0 {
+		var memberSelector api.MemberSelector
+		if err := json.Unmarshal(cg.MemberSelector, &memberSelector); err != nil {
+			g.errorHandler.Handle(errors.WrapIfWithDetails(err, "could not unmarshal member selector", "clusterGroupID", cg.ID))
+		} else {
+			clusterGroup.MemberSelector = &memberSelector
+		}
+	}
+
 	for _, m := range cg.Members {
 		cluster, err := g.clusterGetter.GetClusterByIDOnly(ctx, m.ClusterID)
 		if err != nil {
@@ -406,6 +474,163 @@ func (g *Manager) GetAllClusterGroups(ctx context.Context, orgID uint) ([]api.Cl
 	return groups, nil
 }
 
+// ReconcileClusterMembership reconciles the members of the cluster groups with a member selector
+// in the organization of a cluster. It should be called whenever a cluster is created, updated or relabeled.
+func (g *Manager) ReconcileClusterMembership(ctx context.Context, clusterID uint) error {
+	cluster, err := g.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not get cluster", "clusterID", clusterID)
+	}
+
+	return g.ReconcileMembers(ctx, cluster.GetOrganizationId())
+}
+
+// ReconcileMembers updates the members of every cluster group with a member selector in an organization
+// and reconciles the features of the cluster groups whose membership changed.
+func (g *Manager) ReconcileMembers(ctx context.Context, orgID uint) error {
+	cgModels, err := g.cgRepo.FindAll(orgID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, cgModel := range cgModels {
+		if len(cgModel.MemberSelector) == 0 {
+			continue
+		}
+
+		err := g.reconcileMembers(ctx, cgModel)
+		if err != nil {
+			errs = append(errs, errors.WrapIfWithDetails(err, "could not reconcile cluster group members", "clusterGroupID", cgModel.ID))
+		}
+	}
+
+	return errors.Combine(errs...)
+}
+
+func (g *Manager) reconcileMembers(ctx context.Context, cgModel *ClusterGroupModel) error {
+	existingClusterGroup := g.GetClusterGroupFromModel(ctx, cgModel, false)
+	if existingClusterGroup.MemberSelector == nil {
+		return nil
+	}
+
+	newMembers, err := g.selectMembers(ctx, cgModel.OrganizationID, cgModel.ID, *existingClusterGroup.MemberSelector)
+	if err != nil {
+		return err
+	}
+
+	changed := len(newMembers) != len(cgModel.Members)
+	for _, member := range cgModel.Members {
+		if _, ok := newMembers[member.ClusterID]; !ok {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	logger := g.logger.WithFields(logrus.Fields{
+		"clusterGroupName": existingClusterGroup.Name,
+		"members":          len(newMembers),
+	})
+	logger.Info("updating cluster group members")
+
+	err = g.validateBeforeClusterGroupUpdate(*existingClusterGroup, newMembers)
+	if err != nil {
+		return errors.WrapIf(err, "updating cluster group is not allowed")
+	}
+
+	err = g.cgRepo.UpdateMembers(existingClusterGroup, newMembers)
+	if err != nil {
+		return err
+	}
+
+	clusterGroup, err := g.GetClusterGroupByID(ctx, existingClusterGroup.Id, existingClusterGroup.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	// call feature handlers on members update
+	return g.ReconcileFeatures(*clusterGroup, true)
+}
+
+// selectMembers returns the clusters of an organization matching a member selector
+// which are not members of another cluster group.
+// Current members are kept whatever their status, only joining clusters have to be running.
+func (g *Manager) selectMembers(ctx context.Context, orgID uint, clusterGroupID uint, memberSelector api.MemberSelector) (map[uint]api.Cluster, error) {
+	clusters, err := g.clusterGetter.GetClusters(ctx, orgID)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not list clusters", "organizationID", orgID)
+	}
+
+	clusterLabels, err := g.clusterLabels.GetLabelsByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[uint]api.Cluster)
+	for _, cluster := range clusters {
+		ok, err := memberSelector.Matches(cluster, clusterLabels[cluster.GetID()])
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		logger := g.logger.WithField("clusterName", cluster.GetName())
+
+		memberCluster, err := g.cgRepo.FindMemberClusterByID(cluster.GetID())
+		if err != nil && !IsRecordNotFoundError(err) {
+			return nil, errors.WithStack(err)
+		} else if err == nil {
+			if clusterGroupID == 0 || memberCluster.ClusterGroupID != clusterGroupID {
+				logger.Debug("cluster is already a member of another cluster group")
+
+				continue
+			}
+
+			// members being updated or hibernated must not leave the group (and lose its features)
+			members[cluster.GetID()] = cluster
+
+			continue
+		}
+
+		clusterStatus, err := cluster.GetStatus()
+		if err != nil {
+			logger.Warnf("could not check cluster state: %s", err.Error())
+
+			continue
+		}
+
+		if !isValidClusterStatus(clusterStatus) {
+			continue
+		}
+
+		members[cluster.GetID()] = cluster
+	}
+
+	return members, nil
+}
+
+func validateMemberSelector(members []uint, memberSelector api.MemberSelector) error {
+	if len(members) > 0 {
+		return errors.WithStack(&invalidClusterGroupCreateRequestError{
+			message: "cluster members cannot be listed when a member selector is specified",
+		})
+	}
+
+	if err := memberSelector.Validate(); err != nil {
+		return errors.WithStack(&invalidClusterGroupCreateRequestError{
+			message: err.Error(),
+		})
+	}
+
+	return nil
+}
+
 func (g *Manager) isClusterMemberOfAClusterGroup(clusterID uint, clusterGroupId uint) (bool, error) {
 	result, err := g.cgRepo.FindMemberClusterByID(clusterID)
 	if IsRecordNotFoundError(err) {
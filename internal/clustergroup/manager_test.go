@@ -0,0 +1,302 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustergroup
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/clustergroup/api"
+	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
+	"github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type testCluster struct {
+	api.Cluster
+
+	id           uint
+	cloud        string
+	distribution string
+	location     string
+	status       string
+}
+
+func (c testCluster) GetID() uint {
+	return c.id
+}
+
+func (c testCluster) GetOrganizationId() uint {
+	return 1
+}
+
+func (c testCluster) GetCloud() string {
+	return c.cloud
+}
+
+func (c testCluster) GetDistribution() string {
+	return c.distribution
+}
+
+func (c testCluster) GetName() string {
+	return c.cloud + "-" + c.distribution
+}
+
+func (c testCluster) GetLocation() string {
+	return c.location
+}
+
+func (c testCluster) GetStatus() (*cluster.GetClusterStatusResponse, error) {
+	return &cluster.GetClusterStatusResponse{Status: c.status}, nil
+}
+
+type testClusterGetter struct {
+	api.ClusterGetter
+
+	clusters []api.Cluster
+}
+
+func (g testClusterGetter) GetClusterByIDOnly(ctx context.Context, clusterID uint) (api.Cluster, error) {
+	for _, c := range g.clusters {
+		if c.GetID() == clusterID {
+			return c, nil
+		}
+	}
+
+	return nil, errors.New("cluster not found")
+}
+
+func (g testClusterGetter) GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (api.Cluster, error) {
+	return g.GetClusterByIDOnly(ctx, clusterID)
+}
+
+func (g testClusterGetter) GetClusters(ctx context.Context, organizationID uint) ([]api.Cluster, error) {
+	return g.clusters, nil
+}
+
+type testClusterLabelGetter map[uint]map[string]string
+
+func (g testClusterLabelGetter) GetLabelsByOrganization(ctx context.Context, orgID uint) (map[uint]map[string]string, error) {
+	return g, nil
+}
+
+type testFeatureHandler struct {
+	api.FeatureHandler
+
+	reconciledMembers [][]uint
+}
+
+func (h *testFeatureHandler) ValidateState(featureState api.Feature) error {
+	return nil
+}
+
+func (h *testFeatureHandler) ReconcileState(featureState api.Feature) error {
+	members := make([]uint, 0, len(featureState.ClusterGroup.Members))
+	for _, member := range featureState.ClusterGroup.Members {
+		members = append(members, member.ID)
+	}
+
+	h.reconciledMembers = append(h.reconciledMembers, members)
+
+	return nil
+}
+
+func setUpManager(t *testing.T, labels testClusterLabelGetter) (*Manager, *testFeatureHandler) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	logger := logrus.New()
+
+	err = Migrate(db, logger)
+	require.NoError(t, err)
+
+	clusters := testClusterGetter{
+		clusters: []api.Cluster{
+			testCluster{id: 1, cloud: "amazon", distribution: "eks", location: "eu-west-1", status: cluster.Running},
+			testCluster{id: 2, cloud: "amazon", distribution: "eks", location: "eu-west-1", status: cluster.Running},
+			testCluster{id: 3, cloud: "google", distribution: "gke", location: "europe-west1", status: cluster.Running},
+			testCluster{id: 4, cloud: "amazon", distribution: "eks", location: "eu-west-1", status: cluster.Deleting},
+			testCluster{id: 5, cloud: "amazon", distribution: "pke", location: "eu-west-1", status: cluster.Warning},
+		},
+	}
+
+	manager := NewManager(clusters, labels, NewClusterGroupRepository(db, logger), logger, emperror.NewNoopHandler())
+
+	featureHandler := &testFeatureHandler{}
+	manager.RegisterFeatureHandler(deployment.FeatureName, featureHandler)
+
+	return manager, featureHandler
+}
+
+func getMemberIDs(t *testing.T, manager *Manager, clusterGroupID uint) []uint {
+	clusterGroup, err := manager.GetClusterGroupByID(context.Background(), clusterGroupID, 1)
+	require.NoError(t, err)
+
+	memberIDs := make([]uint, 0, len(clusterGroup.Members))
+	for _, member := range clusterGroup.Members {
+		memberIDs = append(memberIDs, member.ID)
+	}
+
+	return memberIDs
+}
+
+func TestManager_CreateClusterGroup_MemberSelector(t *testing.T) {
+	manager, _ := setUpManager(t, testClusterLabelGetter{
+		1: {"env": "prod"},
+		2: {"env": "dev"},
+		3: {"env": "prod"},
+		4: {"env": "prod"},
+		5: {"env": "prod"},
+	})
+
+	clusterGroupID, err := manager.CreateClusterGroup(context.Background(), "prod", 1, nil, &api.MemberSelector{
+		Cloud:         "amazon",
+		LabelSelector: "env=prod",
+	})
+	require.NoError(t, err)
+
+	// cluster 2 does not match the labels, 3 the cloud and 4 is being deleted
+	assert.ElementsMatch(t, []uint{1, 5}, getMemberIDs(t, manager, *clusterGroupID))
+
+	clusterGroup, err := manager.GetClusterGroupByID(context.Background(), *clusterGroupID, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, &api.MemberSelector{Cloud: "amazon", LabelSelector: "env=prod"}, clusterGroup.MemberSelector)
+}
+
+func TestManager_CreateClusterGroup_MembersWithMemberSelector(t *testing.T) {
+	manager, _ := setUpManager(t, testClusterLabelGetter{})
+
+	_, err := manager.CreateClusterGroup(context.Background(), "prod", 1, []uint{1}, &api.MemberSelector{
+		Cloud: "amazon",
+	})
+	require.Error(t, err)
+
+	assert.IsType(t, &invalidClusterGroupCreateRequestError{}, errors.Cause(err))
+
+	_, err = manager.CreateClusterGroup(context.Background(), "prod", 1, nil, &api.MemberSelector{})
+	require.Error(t, err)
+
+	assert.IsType(t, &invalidClusterGroupCreateRequestError{}, errors.Cause(err))
+}
+
+func TestManager_UpdateClusterGroup_MembersWithMemberSelector(t *testing.T) {
+	manager, _ := setUpManager(t, testClusterLabelGetter{})
+
+	clusterGroupID, err := manager.CreateClusterGroup(context.Background(), "static", 1, []uint{1}, nil)
+	require.NoError(t, err)
+
+	err = manager.UpdateClusterGroup(context.Background(), *clusterGroupID, 1, "static", []uint{1, 2}, &api.MemberSelector{
+		Cloud: "amazon",
+	})
+	require.Error(t, err)
+
+	assert.IsType(t, &invalidClusterGroupCreateRequestError{}, errors.Cause(err))
+	assert.Equal(t, []uint{1}, getMemberIDs(t, manager, *clusterGroupID))
+}
+
+func TestManager_CreateClusterGroup_MemberSelectorExcludesOtherGroupMembers(t *testing.T) {
+	manager, _ := setUpManager(t, testClusterLabelGetter{
+		1: {"env": "prod"},
+		2: {"env": "prod"},
+	})
+
+	staticClusterGroupID, err := manager.CreateClusterGroup(context.Background(), "static", 1, []uint{1}, nil)
+	require.NoError(t, err)
+
+	clusterGroupID, err := manager.CreateClusterGroup(context.Background(), "prod", 1, nil, &api.MemberSelector{
+		LabelSelector: "env=prod",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{1}, getMemberIDs(t, manager, *staticClusterGroupID))
+	assert.Equal(t, []uint{2}, getMemberIDs(t, manager, *clusterGroupID))
+}
+
+func TestManager_ReconcileMembers(t *testing.T) {
+	labels := testClusterLabelGetter{
+		1: {"env": "prod"},
+		2: {"env": "dev"},
+	}
+	manager, featureHandler := setUpManager(t, labels)
+
+	clusterGroupID, err := manager.CreateClusterGroup(context.Background(), "prod", 1, nil, &api.MemberSelector{
+		LabelSelector: "env=prod",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []uint{1}, getMemberIDs(t, manager, *clusterGroupID))
+
+	// nothing changed: the features are not reconciled
+	err = manager.ReconcileMembers(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Empty(t, featureHandler.reconciledMembers)
+
+	// cluster 1 leaves, cluster 2 joins the group
+	labels[1] = map[string]string{"env": "dev"}
+	labels[2] = map[string]string{"env": "prod"}
+
+	err = manager.ReconcileClusterMembership(context.Background(), 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{2}, getMemberIDs(t, manager, *clusterGroupID))
+	assert.Equal(t, [][]uint{{2}}, featureHandler.reconciledMembers)
+
+	// every member leaves, the group is kept with its member selector
+	labels[2] = map[string]string{"env": "dev"}
+
+	err = manager.ReconcileMembers(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Empty(t, getMemberIDs(t, manager, *clusterGroupID))
+	assert.Equal(t, [][]uint{{2}, {}}, featureHandler.reconciledMembers)
+}
+
+func TestManager_ReconcileMembers_UpdatingMember(t *testing.T) {
+	labels := testClusterLabelGetter{
+		1: {"env": "prod"},
+		2: {"env": "dev"},
+	}
+	manager, featureHandler := setUpManager(t, labels)
+
+	clusterGroupID, err := manager.CreateClusterGroup(context.Background(), "prod", 1, nil, &api.MemberSelector{
+		LabelSelector: "env=prod",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []uint{1}, getMemberIDs(t, manager, *clusterGroupID))
+
+	clusters := manager.clusterGetter.(testClusterGetter).clusters
+	clusters[0] = testCluster{id: 1, cloud: "amazon", distribution: "eks", location: "eu-west-1", status: cluster.Updating}
+	clusters[1] = testCluster{id: 2, cloud: "amazon", distribution: "eks", location: "eu-west-1", status: cluster.Updating}
+
+	// cluster 1 stays a member while it is being updated, cluster 2 cannot join until it is running
+	labels[2] = map[string]string{"env": "prod"}
+
+	err = manager.ReconcileMembers(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{1}, getMemberIDs(t, manager, *clusterGroupID))
+	assert.Empty(t, featureHandler.reconciledMembers)
+}
@@ -15,6 +15,8 @@
 package clustergroup
 
 import (
+	"encoding/json"
+
 	"emperror.dev/errors"
 	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
@@ -75,13 +77,22 @@ func (g *ClusterGroupRepository) FindAll(orgID uint) ([]*ClusterGroupModel, erro
 }
 
 // Create persists a cluster group
-func (g *ClusterGroupRepository) Create(name string, orgID uint, memberClusterModels []MemberClusterModel) (*uint, error) {
+func (g *ClusterGroupRepository) Create(name string, orgID uint, memberClusterModels []MemberClusterModel, memberSelector *api.MemberSelector) (*uint, error) {
 	clusterGroupModel := &ClusterGroupModel{
 		Name:           name,
 		OrganizationID: orgID,
 		Members:        memberClusterModels,
 	}
 
+	if memberSelector != nil {
+		rawMemberSelector, err := json.Marshal(memberSelector)
+		if err != nil {
+			return nil, errors.WrapIf(err, "could not marshal member selector")
+		}
+
+		clusterGroupModel.MemberSelector = rawMemberSelector
+	}
+
 	err := g.db.Save(clusterGroupModel).Error
 	if err != nil {
 		return nil, errors.WrapIfWithDetails(err, "error creating cluster group", "name", name)
@@ -127,6 +138,27 @@ func (g *ClusterGroupRepository) UpdateMembers(cgroup *api.ClusterGroup, newMemb
 	return nil
 }
 
+// UpdateMemberSelector updates the member selector of a cluster group.
+// A nil selector turns the cluster group into a static one.
+func (g *ClusterGroupRepository) UpdateMemberSelector(clusterGroupID uint, memberSelector *api.MemberSelector) error {
+	var rawMemberSelector interface{}
+	if memberSelector != nil {
+		raw, err := json.Marshal(memberSelector)
+		if err != nil {
+			return errors.WrapIf(err, "could not marshal member selector")
+		}
+
+		rawMemberSelector = raw
+	}
+
+	err := g.db.Model(&ClusterGroupModel{ID: clusterGroupID}).Update("member_selector", rawMemberSelector).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not update member selector", "clusterGroupID", clusterGroupID)
+	}
+
+	return nil
+}
+
 // Delete deletes a cluster group
 func (g *ClusterGroupRepository) Delete(cgroup *ClusterGroupModel) error {
 	for _, fp := range cgroup.FeatureParams {
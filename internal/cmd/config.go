@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"emperror.dev/errors"
+	"github.com/robfig/cron"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
@@ -164,14 +165,20 @@ type ClusterConfig struct {
 
 	Backyards istiofeature.StaticConfig
 
+	Cost ClusterCostConfig
+
 	DisasterRecovery ClusterDisasterRecoveryConfig
 
 	DNS ClusterDNSConfig
 
+	Exec ClusterExecConfig
+
 	Expiry ClusterExpiryConfig
 
 	Federation federation.StaticConfig
 
+	Health ClusterHealthConfig
+
 	Ingress ClusterIngressConfig
 
 	Labels clusterconfig.LabelConfig
@@ -189,6 +196,8 @@ type ClusterConfig struct {
 	// Posthook configs
 	PostHook cluster.PostHookConfig
 
+	SecretSync ClusterSecretSyncConfig
+
 	SecurityScan ClusterSecurityScanConfig
 
 	Vault ClusterVaultConfig
@@ -198,8 +207,14 @@ type ClusterConfig struct {
 func (c ClusterConfig) Validate() error {
 	var errs error
 
+	errs = errors.Append(errs, c.Cost.Validate())
+
 	errs = errors.Append(errs, c.DNS.Validate())
 
+	errs = errors.Append(errs, c.Exec.Validate())
+
+	errs = errors.Append(errs, c.Health.Validate())
+
 	errs = errors.Append(errs, c.Ingress.Validate())
 
 	errs = errors.Append(errs, c.Labels.Validate())
@@ -325,6 +340,32 @@ type ClusterDisasterRecoveryConfig struct {
 }
 
 // ClusterDNSConfig contains cluster DNS configuration.
+// ClusterCostConfig contains cluster cost configuration.
+type ClusterCostConfig struct {
+	// Ledger periodically records the running nodes of every cluster for usage reports
+	Ledger struct {
+		Enabled  bool
+		Schedule string
+		Timeout  time.Duration
+	}
+}
+
+func (c ClusterCostConfig) Validate() error {
+	var errs error
+
+	if c.Ledger.Enabled {
+		if _, err := cron.ParseStandard(c.Ledger.Schedule); err != nil {
+			errs = errors.Append(errs, errors.WrapIf(err, "cluster cost ledger schedule is invalid"))
+		}
+
+		if c.Ledger.Timeout <= 0 {
+			errs = errors.Append(errs, errors.New("cluster cost ledger timeout must be positive"))
+		}
+	}
+
+	return errs
+}
+
 type ClusterDNSConfig struct {
 	Enabled bool
 
@@ -345,6 +386,70 @@ type ClusterExpiryConfig struct {
 	Enabled bool
 }
 
+// ClusterExecConfig contains configuration for interactive exec and attach sessions.
+type ClusterExecConfig struct {
+	MaxSessionDuration time.Duration
+	IdleTimeout        time.Duration
+
+	Recording ClusterExecRecordingConfig
+}
+
+// ClusterExecRecordingConfig contains session recording configuration.
+type ClusterExecRecordingConfig struct {
+	Enabled bool
+
+	// MaxSize is the maximum size of a recording in bytes
+	MaxSize int
+}
+
+func (c ClusterExecConfig) Validate() error {
+	var errs error
+
+	if c.MaxSessionDuration <= 0 {
+		errs = errors.Append(errs, errors.New("cluster exec max session duration must be positive"))
+	}
+
+	if c.IdleTimeout < 0 {
+		errs = errors.Append(errs, errors.New("cluster exec idle timeout cannot be negative"))
+	}
+
+	if c.Recording.Enabled && c.Recording.MaxSize <= 0 {
+		errs = errors.Append(errs, errors.New("cluster exec recording max size must be positive"))
+	}
+
+	return errs
+}
+
+// ClusterHealthConfig contains cluster health check configuration.
+type ClusterHealthConfig struct {
+	Enabled bool
+
+	// Schedule is the cron schedule of the health checks
+	Schedule string
+
+	// Timeout is the timeout of the requests sent to the API server of a cluster
+	Timeout time.Duration
+
+	// CertificateExpiryWarning is the remaining validity under which certificates are reported
+	CertificateExpiryWarning time.Duration
+}
+
+func (c ClusterHealthConfig) Validate() error {
+	var errs error
+
+	if c.Enabled {
+		if _, err := cron.ParseStandard(c.Schedule); err != nil {
+			errs = errors.Append(errs, errors.WrapIf(err, "cluster health check schedule is invalid"))
+		}
+
+		if c.Timeout <= 0 {
+			errs = errors.Append(errs, errors.New("cluster health check timeout must be positive"))
+		}
+	}
+
+	return errs
+}
+
 type ClusterIngressConfig struct {
 	Enabled bool
 
@@ -400,11 +505,23 @@ func (c ClusterMonitoringConfig) Validate() error {
 	return errs
 }
 
+type ClusterSecretSyncConfig struct {
+	Enabled bool
+}
+
 // ClusterSecurityScanConfig contains cluster security scan configuration.
 type ClusterSecurityScanConfig struct {
 	Enabled bool
 
 	securityscan.Config `mapstructure:",squash"`
+
+	// Rescan periodically re-evaluates every running image
+	Rescan struct {
+		Enabled       bool
+		Schedule      string
+		Timeout       time.Duration
+		KeepSnapshots int
+	}
 }
 
 func (c ClusterSecurityScanConfig) Validate() error {
@@ -412,6 +529,16 @@ func (c ClusterSecurityScanConfig) Validate() error {
 
 	if c.Enabled {
 		err = errors.Append(err, c.Config.Validate())
+
+		if c.Rescan.Enabled {
+			if c.Rescan.Schedule == "" {
+				err = errors.Append(err, errors.New("cluster security scan rescan schedule is required"))
+			}
+
+			if c.Rescan.Timeout <= 0 {
+				err = errors.Append(err, errors.New("cluster security scan rescan timeout must be positive"))
+			}
+		}
 	}
 
 	return err
@@ -605,6 +732,10 @@ func Configure(v *viper.Viper, p *pflag.FlagSet) {
 	v.SetDefault("cluster::logging::images::fluentd::repository", "banzaicloud/fluentd")
 	v.SetDefault("cluster::logging::images::fluentd::tag", "v1.7.4-alpine-13")
 
+	v.SetDefault("cluster::cost::ledger::enabled", true)
+	v.SetDefault("cluster::cost::ledger::schedule", "0 * * * *")
+	v.SetDefault("cluster::cost::ledger::timeout", "50m")
+
 	v.SetDefault("cluster::dns::enabled", true)
 	v.SetDefault("cluster::dns::namespace", "")
 	v.SetDefault("cluster::dns::baseDomain", "")
@@ -682,6 +813,22 @@ ssl:
 	v.SetDefault("cluster::securityScan::anchore::user", "")
 	v.SetDefault("cluster::securityScan::anchore::password", "")
 	v.SetDefault("cluster::securityScan::anchore::insecure", false)
+	v.SetDefault("cluster::securityScan::trivy::enabled", false)
+	v.SetDefault("cluster::securityScan::trivy::chart", "aquasecurity/trivy")
+	v.SetDefault("cluster::securityScan::trivy::version", "0.1.6")
+	v.SetDefault("cluster::securityScan::trivy::release", "trivy")
+	v.SetDefault("cluster::securityScan::trivy::port", 4954)
+	v.SetDefault("cluster::securityScan::trivy::image", "aquasec/trivy:0.9.1")
+	v.SetDefault("cluster::securityScan::trivy::scanTimeout", "5m")
+	v.SetDefault("cluster::securityScan::trivy::values", map[string]interface{}{
+		"trivy": map[string]interface{}{
+			"serverMode": true,
+		},
+	})
+	v.SetDefault("cluster::securityScan::rescan::enabled", true)
+	v.SetDefault("cluster::securityScan::rescan::schedule", "0 3 * * *")
+	v.SetDefault("cluster::securityScan::rescan::timeout", "6h")
+	v.SetDefault("cluster::securityScan::rescan::keepSnapshots", 30)
 	v.SetDefault("cluster::securityScan::webhook::chart", "banzaicloud-stable/anchore-policy-validator")
 	v.SetDefault("cluster::securityScan::webhook::version", "0.5.8")
 	v.SetDefault("cluster::securityScan::webhook::release", "anchore")
@@ -696,6 +843,18 @@ ssl:
 
 	v.SetDefault("cluster::expiry::enabled", true)
 
+	v.SetDefault("cluster::exec::maxSessionDuration", "1h")
+	v.SetDefault("cluster::exec::idleTimeout", "15m")
+	v.SetDefault("cluster::exec::recording::enabled", true)
+	v.SetDefault("cluster::exec::recording::maxSize", 10485760)
+
+	v.SetDefault("cluster::health::enabled", true)
+	v.SetDefault("cluster::health::schedule", "*/5 * * * *")
+	v.SetDefault("cluster::health::timeout", "30s")
+	v.SetDefault("cluster::health::certificateExpiryWarning", "720h")
+
+	v.SetDefault("cluster::secretSync::enabled", true)
+
 	// ingress controller config
 	v.SetDefault("cluster::posthook::ingress::enabled", true)
 	v.SetDefault("cluster::posthook::ingress::chart", "banzaicloud-stable/pipeline-cluster-ingress")
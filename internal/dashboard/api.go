@@ -51,6 +51,12 @@ type DashboardAPI struct {
 
 	authConfig         auth.Config
 	clientSecretGetter clusterAuth.ClusterClientSecretGetter
+	clusterLabels      ClusterLabelGetter
+}
+
+// ClusterLabelGetter returns the labels of a cluster.
+type ClusterLabelGetter interface {
+	GetLabels(ctx context.Context, clusterID uint) (map[string]string, error)
 }
 
 func NewDashboardAPI(
@@ -60,6 +66,7 @@ func NewDashboardAPI(
 	errorHandler emperror.Handler,
 	authConfig auth.Config,
 	clientSecretGetter clusterAuth.ClusterClientSecretGetter,
+	clusterLabels ClusterLabelGetter,
 ) *DashboardAPI {
 	return &DashboardAPI{
 		clusterManager:      clusterManager,
@@ -68,6 +75,7 @@ func NewDashboardAPI(
 		errorHandler:        errorHandler,
 		authConfig:          authConfig,
 		clientSecretGetter:  clientSecretGetter,
+		clusterLabels:       clusterLabels,
 	}
 }
 
@@ -190,6 +198,13 @@ func (d *DashboardAPI) getClusterDashboardInfo(logger *logrus.Entry, commonClust
 		Nodes:        nodeStates,
 	}
 
+	clusterLabels, err := d.clusterLabels.GetLabels(context.Background(), commonCluster.GetID())
+	if err != nil {
+		d.logger.Warn(err.Error())
+	} else {
+		clusterInfo.Labels = clusterLabels
+	}
+
 	clusterStatus, err := commonCluster.GetStatus()
 	if err != nil {
 		clusterInfo.Status = "ERROR"
@@ -91,6 +91,7 @@ type ClusterInfo struct {
 	Project             string              `json:"project,omitempty"`
 	ResourceGroup       string              `json:"resourceGroup,omitempty"`
 	ClusterGroup        string              `json:"clusterGroup,omitempty"`
+	Labels              map[string]string   `json:"labels,omitempty"`
 	SecretName          string              `json:"secretName,omitempty"`
 	Nodes               []Node              `json:"nodes"`
 	NodePools           map[string]NodePool `json:"nodePools,omitempty"`
@@ -0,0 +1,33 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+const (
+	integratedServiceName = "secretsync"
+
+	// managedByLabel marks Kubernetes secrets that are managed by the secret sync integrated service.
+	managedByLabel      = "app.kubernetes.io/managed-by"
+	managedByLabelValue = "pipeline-secretsync"
+
+	sourceSecretAnnotation = "secretsync.integratedservices.banzaicloud.io/source"
+	checksumAnnotation     = "secretsync.integratedservices.banzaicloud.io/checksum"
+)
+
+// Sync status constants
+const (
+	syncStatusSynced    = "SYNCED"
+	syncStatusOutOfSync = "OUT_OF_SYNC"
+	syncStatusMissing   = "MISSING"
+)
@@ -0,0 +1,156 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+)
+
+type obj = map[string]interface{}
+
+type dummyClusterGetter struct {
+	Clusters map[uint]dummyCluster
+}
+
+func (d dummyClusterGetter) GetClusterByIDOnly(ctx context.Context, clusterID uint) (integratedserviceadapter.Cluster, error) {
+	return d.Clusters[clusterID], nil
+}
+
+func (d dummyClusterGetter) GetClusterStatus(ctx context.Context, clusterID uint) (string, error) {
+	if c, ok := d.Clusters[clusterID]; ok {
+		return c.Status, nil
+	}
+	return "", errors.New("cluster not found")
+}
+
+type dummyCluster struct {
+	integratedserviceadapter.Cluster
+
+	OrgID  uint
+	ID     uint
+	Status string
+}
+
+func (d dummyCluster) GetOrganizationId() uint {
+	return d.OrgID
+}
+
+func (d dummyCluster) GetID() uint {
+	return d.ID
+}
+
+type dummySecretStore struct {
+	Secrets map[string]Secret
+	Tags    map[string][]string
+}
+
+func (d dummySecretStore) GetSecretByName(ctx context.Context, name string) (Secret, error) {
+	s, ok := d.Secrets[name]
+	if !ok {
+		return Secret{}, errors.WithStack(NotFoundError{SecretName: name})
+	}
+
+	return s, nil
+}
+
+func (d dummySecretStore) ListSecretsByTags(ctx context.Context, tags []string) ([]Secret, error) {
+	var secrets []Secret
+
+	for name, s := range d.Secrets {
+		if hasTags(d.Tags[name], tags) {
+			secrets = append(secrets, s)
+		}
+	}
+
+	return secrets, nil
+}
+
+func hasTags(tags []string, selector []string) bool {
+	for _, s := range selector {
+		found := false
+		for _, t := range tags {
+			if s == t {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dummyKubernetesService keeps secrets and namespaces in memory.
+type dummyKubernetesService struct {
+	Secrets    map[string]corev1.Secret
+	Namespaces map[string]bool
+}
+
+func (s *dummyKubernetesService) EnsureObject(ctx context.Context, clusterID uint, o runtime.Object) error {
+	switch v := o.(type) {
+	case *corev1.Namespace:
+		s.Namespaces[v.Name] = true
+	case *corev1.Secret:
+		if current, ok := s.Secrets[v.Namespace+"/"+v.Name]; ok {
+			current.DeepCopyInto(v)
+		} else {
+			s.Secrets[v.Namespace+"/"+v.Name] = *v.DeepCopy()
+		}
+	}
+
+	return nil
+}
+
+func (s *dummyKubernetesService) Update(ctx context.Context, clusterID uint, o runtime.Object) error {
+	if v, ok := o.(*corev1.Secret); ok {
+		s.Secrets[v.Namespace+"/"+v.Name] = *v.DeepCopy()
+	}
+
+	return nil
+}
+
+func (s *dummyKubernetesService) DeleteObject(ctx context.Context, clusterID uint, o runtime.Object) error {
+	if v, ok := o.(*corev1.Secret); ok {
+		delete(s.Secrets, v.Namespace+"/"+v.Name)
+	}
+
+	return nil
+}
+
+func (s *dummyKubernetesService) List(ctx context.Context, clusterID uint, labels map[string]string, o runtime.Object) error {
+	if v, ok := o.(*corev1.SecretList); ok {
+	secrets:
+		for _, secret := range s.Secrets {
+			for key, value := range labels {
+				if secret.Labels[key] != value {
+					continue secrets
+				}
+			}
+
+			v.Items = append(v.Items, secret)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+)
+
+// IntegratedServiceManager implements the secret sync integrated service manager
+type IntegratedServiceManager struct {
+	integratedservices.PassthroughIntegratedServiceSpecPreparer
+
+	clusterGetter     integratedserviceadapter.ClusterGetter
+	secretStore       SecretStore
+	kubernetesService KubernetesService
+	logger            services.Logger
+}
+
+// MakeIntegratedServiceManager builds a new integrated service manager component
+func MakeIntegratedServiceManager(
+	clusterGetter integratedserviceadapter.ClusterGetter,
+	secretStore SecretStore,
+	kubernetesService KubernetesService,
+	logger services.Logger,
+) IntegratedServiceManager {
+	return IntegratedServiceManager{
+		clusterGetter:     clusterGetter,
+		secretStore:       secretStore,
+		kubernetesService: kubernetesService,
+		logger:            logger,
+	}
+}
+
+// Name returns the integrated service' name
+func (m IntegratedServiceManager) Name() string {
+	return integratedServiceName
+}
+
+// GetOutput returns the sync status of every secret in the spec
+func (m IntegratedServiceManager) GetOutput(ctx context.Context, clusterID uint, spec integratedservices.IntegratedServiceSpec) (integratedservices.IntegratedServiceOutput, error) {
+	boundSpec, err := bindIntegratedServiceSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = ensureOrgIDInContext(ctx, m.clusterGetter, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	desired, err := resolveSecrets(ctx, m.secretStore, boundSpec)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to resolve secrets")
+	}
+
+	var actual corev1.SecretList
+	if err := m.kubernetesService.List(ctx, clusterID, map[string]string{managedByLabel: managedByLabelValue}, &actual); err != nil {
+		return nil, errors.WrapIf(err, "failed to list synced secrets")
+	}
+
+	return integratedservices.IntegratedServiceOutput{
+		"secrets": syncStatus(desired, actual.Items),
+	}, nil
+}
+
+// ValidateSpec validates a secret sync integrated service specification
+func (m IntegratedServiceManager) ValidateSpec(ctx context.Context, spec integratedservices.IntegratedServiceSpec) error {
+	boundSpec, err := bindIntegratedServiceSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := boundSpec.Validate(); err != nil {
+		return integratedservices.InvalidIntegratedServiceSpecError{
+			IntegratedServiceName: integratedServiceName,
+			Problem:               err.Error(),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+)
+
+// IntegratedServiceOperator implements the secret sync integrated service operator
+type IntegratedServiceOperator struct {
+	clusterGetter     integratedserviceadapter.ClusterGetter
+	clusterService    integratedservices.ClusterService
+	secretStore       SecretStore
+	kubernetesService KubernetesService
+	logger            services.Logger
+}
+
+// MakeIntegratedServiceOperator returns a secret sync integrated service operator
+func MakeIntegratedServiceOperator(
+	clusterGetter integratedserviceadapter.ClusterGetter,
+	clusterService integratedservices.ClusterService,
+	secretStore SecretStore,
+	kubernetesService KubernetesService,
+	logger services.Logger,
+) IntegratedServiceOperator {
+	return IntegratedServiceOperator{
+		clusterGetter:     clusterGetter,
+		clusterService:    clusterService,
+		secretStore:       secretStore,
+		kubernetesService: kubernetesService,
+		logger:            logger,
+	}
+}
+
+// Name returns the name of the secret sync integrated service
+func (op IntegratedServiceOperator) Name() string {
+	return integratedServiceName
+}
+
+// Apply syncs the secrets in the spec to the cluster and deletes the ones that are no longer in the spec
+func (op IntegratedServiceOperator) Apply(ctx context.Context, clusterID uint, spec integratedservices.IntegratedServiceSpec) error {
+	ctx, err := ensureOrgIDInContext(ctx, op.clusterGetter, clusterID)
+	if err != nil {
+		return err
+	}
+
+	if err := op.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	logger := op.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "integrated service": integratedServiceName})
+
+	boundSpec, err := bindIntegratedServiceSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	desired, err := resolveSecrets(ctx, op.secretStore, boundSpec)
+	if err != nil {
+		return errors.WrapIf(err, "failed to resolve secrets")
+	}
+
+	namespaces := make(map[string]bool)
+	for _, s := range desired {
+		if namespaces[s.Namespace] {
+			continue
+		}
+
+		if err := op.kubernetesService.EnsureObject(ctx, clusterID, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: s.Namespace}}); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to ensure namespace", "namespace", s.Namespace)
+		}
+
+		namespaces[s.Namespace] = true
+	}
+
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, s := range desired {
+		desiredKeys[s.key()] = true
+
+		if err := op.syncSecret(ctx, clusterID, s); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to sync secret", "secret", s.Source, "target", s.key())
+		}
+
+		logger.Debug("secret synced", map[string]interface{}{"secret": s.Source, "target": s.key()})
+	}
+
+	var actual corev1.SecretList
+	if err := op.kubernetesService.List(ctx, clusterID, map[string]string{managedByLabel: managedByLabelValue}, &actual); err != nil {
+		return errors.WrapIf(err, "failed to list synced secrets")
+	}
+
+	for i := range actual.Items {
+		s := &actual.Items[i]
+		if desiredKeys[s.Namespace+"/"+s.Name] {
+			continue
+		}
+
+		if err := op.kubernetesService.DeleteObject(ctx, clusterID, s); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to delete synced secret", "target", s.Namespace+"/"+s.Name)
+		}
+
+		logger.Debug("stale secret deleted", map[string]interface{}{"target": s.Namespace + "/" + s.Name})
+	}
+
+	logger.Info("secrets synced successfully")
+
+	return nil
+}
+
+func (op IntegratedServiceOperator) syncSecret(ctx context.Context, clusterID uint, s syncedSecret) error {
+	desired := s.toKubernetesSecret()
+
+	current := desired.DeepCopy()
+	if err := op.kubernetesService.EnsureObject(ctx, clusterID, current); err != nil {
+		return err
+	}
+
+	if current.Labels[managedByLabel] != managedByLabelValue {
+		return errors.NewWithDetails("a secret not managed by Pipeline already exists with the same name", "target", s.key())
+	}
+
+	if current.Annotations[checksumAnnotation] == s.Checksum {
+		return nil
+	}
+
+	if current.Annotations == nil {
+		current.Annotations = make(map[string]string)
+	}
+	for key, value := range desired.Annotations {
+		current.Annotations[key] = value
+	}
+
+	current.Data = desired.Data
+
+	return op.kubernetesService.Update(ctx, clusterID, current)
+}
+
+// Deactivate deletes every synced secret from the cluster
+func (op IntegratedServiceOperator) Deactivate(ctx context.Context, clusterID uint, _ integratedservices.IntegratedServiceSpec) error {
+	if err := op.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	logger := op.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "integrated service": integratedServiceName})
+
+	var actual corev1.SecretList
+	if err := op.kubernetesService.List(ctx, clusterID, map[string]string{managedByLabel: managedByLabelValue}, &actual); err != nil {
+		return errors.WrapIf(err, "failed to list synced secrets")
+	}
+
+	for i := range actual.Items {
+		s := &actual.Items[i]
+		if err := op.kubernetesService.DeleteObject(ctx, clusterID, s); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to delete synced secret", "target", s.Namespace+"/"+s.Name)
+		}
+	}
+
+	logger.Info("synced secrets deleted successfully")
+
+	return nil
+}
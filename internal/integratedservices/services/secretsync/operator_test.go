@@ -0,0 +1,253 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+func TestIntegratedServiceOperator_Name(t *testing.T) {
+	op := MakeIntegratedServiceOperator(nil, nil, nil, nil, nil)
+
+	assert.Equal(t, "secretsync", op.Name())
+}
+
+func TestIntegratedServiceOperator_Apply(t *testing.T) {
+	clusterID := uint(42)
+	orgID := uint(13)
+
+	clusterGetter := dummyClusterGetter{
+		Clusters: map[uint]dummyCluster{
+			clusterID: {
+				OrgID:  orgID,
+				ID:     clusterID,
+				Status: pkgCluster.Running,
+			},
+		},
+	}
+	clusterService := integratedserviceadapter.NewClusterService(clusterGetter)
+	secretStore := dummySecretStore{
+		Secrets: map[string]Secret{
+			"db":    {Name: "db", Values: map[string]string{"username": "admin", "password": "secret"}},
+			"api-a": {Name: "api-a", Values: map[string]string{"token": "a"}},
+			"api-b": {Name: "api-b", Values: map[string]string{"token": "b"}},
+		},
+		Tags: map[string][]string{
+			"api-a": {"team:a"},
+			"api-b": {"team:b"},
+		},
+	}
+	kubernetesService := &dummyKubernetesService{
+		Secrets: map[string]corev1.Secret{
+			"default/stale": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stale",
+					Namespace: "default",
+					Labels:    map[string]string{managedByLabel: managedByLabelValue},
+				},
+			},
+			"default/unmanaged": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unmanaged",
+					Namespace: "default",
+				},
+			},
+		},
+		Namespaces: map[string]bool{},
+	}
+
+	op := MakeIntegratedServiceOperator(clusterGetter, clusterService, secretStore, kubernetesService, services.NoopLogger{})
+
+	ctx := auth.SetCurrentOrganizationID(context.Background(), orgID)
+
+	spec := integratedservices.IntegratedServiceSpec{
+		"secrets": []obj{
+			{
+				"name":        "db",
+				"targetName":  "db-credentials",
+				"namespaces":  []string{"default", "app"},
+				"keyMappings": obj{"password": "DB_PASSWORD"},
+			},
+			{
+				"selector":   []string{"team:a"},
+				"namespaces": []string{"team-a"},
+			},
+		},
+	}
+
+	err := op.Apply(ctx, clusterID, spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"default": true, "app": true, "team-a": true}, kubernetesService.Namespaces)
+
+	assert.Contains(t, kubernetesService.Secrets, "default/db-credentials")
+	assert.Contains(t, kubernetesService.Secrets, "app/db-credentials")
+	assert.Contains(t, kubernetesService.Secrets, "team-a/api-a")
+	assert.Contains(t, kubernetesService.Secrets, "default/unmanaged")
+	assert.NotContains(t, kubernetesService.Secrets, "default/stale")
+	assert.NotContains(t, kubernetesService.Secrets, "team-a/api-b")
+
+	assert.Equal(t, map[string][]byte{"DB_PASSWORD": []byte("secret")}, kubernetesService.Secrets["default/db-credentials"].Data)
+
+	// the source secret changes
+	secretStore.Secrets["db"] = Secret{Name: "db", Values: map[string]string{"password": "new-secret"}}
+
+	manager := MakeIntegratedServiceManager(clusterGetter, secretStore, kubernetesService, services.NoopLogger{})
+
+	output, err := manager.GetOutput(ctx, clusterID, spec)
+	require.NoError(t, err)
+
+	assert.Contains(t, output["secrets"], map[string]interface{}{
+		"source":    "db",
+		"namespace": "default",
+		"name":      "db-credentials",
+		"status":    syncStatusOutOfSync,
+	})
+
+	err = op.Apply(ctx, clusterID, spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]byte{"DB_PASSWORD": []byte("new-secret")}, kubernetesService.Secrets["default/db-credentials"].Data)
+
+	output, err = manager.GetOutput(ctx, clusterID, spec)
+	require.NoError(t, err)
+
+	for _, status := range output["secrets"].([]map[string]interface{}) {
+		assert.Equal(t, syncStatusSynced, status["status"])
+	}
+}
+
+func TestIntegratedServiceOperator_Apply_UnmanagedConflict(t *testing.T) {
+	clusterID := uint(42)
+
+	clusterGetter := dummyClusterGetter{
+		Clusters: map[uint]dummyCluster{
+			clusterID: {ID: clusterID, Status: pkgCluster.Running},
+		},
+	}
+	secretStore := dummySecretStore{
+		Secrets: map[string]Secret{
+			"db": {Name: "db", Values: map[string]string{"password": "secret"}},
+		},
+	}
+	kubernetesService := &dummyKubernetesService{
+		Secrets: map[string]corev1.Secret{
+			"default/db": {ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}},
+		},
+		Namespaces: map[string]bool{},
+	}
+
+	op := MakeIntegratedServiceOperator(clusterGetter, integratedserviceadapter.NewClusterService(clusterGetter), secretStore, kubernetesService, services.NoopLogger{})
+
+	err := op.Apply(auth.SetCurrentOrganizationID(context.Background(), 1), clusterID, integratedservices.IntegratedServiceSpec{
+		"secrets": []obj{{"name": "db", "namespaces": []string{"default"}}},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, kubernetesService.Secrets["default/db"].Data)
+}
+
+func TestIntegratedServiceOperator_Apply_DeletedSecret(t *testing.T) {
+	clusterID := uint(42)
+
+	clusterGetter := dummyClusterGetter{
+		Clusters: map[uint]dummyCluster{
+			clusterID: {ID: clusterID, Status: pkgCluster.Running},
+		},
+	}
+	secretStore := dummySecretStore{
+		Secrets: map[string]Secret{
+			"db":  {Name: "db", Values: map[string]string{"password": "secret"}},
+			"api": {Name: "api", Values: map[string]string{"token": "token"}},
+		},
+	}
+	kubernetesService := &dummyKubernetesService{
+		Secrets:    map[string]corev1.Secret{},
+		Namespaces: map[string]bool{},
+	}
+
+	op := MakeIntegratedServiceOperator(clusterGetter, integratedserviceadapter.NewClusterService(clusterGetter), secretStore, kubernetesService, services.NoopLogger{})
+
+	ctx := auth.SetCurrentOrganizationID(context.Background(), 1)
+
+	spec := integratedservices.IntegratedServiceSpec{
+		"secrets": []obj{
+			{"name": "db", "namespaces": []string{"default", "app"}},
+			{"name": "api", "namespaces": []string{"default"}},
+		},
+	}
+
+	err := op.Apply(ctx, clusterID, spec)
+	require.NoError(t, err)
+
+	assert.Contains(t, kubernetesService.Secrets, "default/db")
+	assert.Contains(t, kubernetesService.Secrets, "app/db")
+
+	// the source secret is deleted
+	delete(secretStore.Secrets, "db")
+
+	err = op.Apply(ctx, clusterID, spec)
+	require.NoError(t, err)
+
+	assert.NotContains(t, kubernetesService.Secrets, "default/db")
+	assert.NotContains(t, kubernetesService.Secrets, "app/db")
+	assert.Contains(t, kubernetesService.Secrets, "default/api")
+}
+
+func TestIntegratedServiceOperator_Deactivate(t *testing.T) {
+	clusterID := uint(42)
+
+	clusterGetter := dummyClusterGetter{
+		Clusters: map[uint]dummyCluster{
+			clusterID: {ID: clusterID, Status: pkgCluster.Running},
+		},
+	}
+	kubernetesService := &dummyKubernetesService{
+		Secrets: map[string]corev1.Secret{
+			"default/managed": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "managed",
+					Namespace: "default",
+					Labels:    map[string]string{managedByLabel: managedByLabelValue},
+				},
+			},
+			"default/unmanaged": {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unmanaged",
+					Namespace: "default",
+				},
+			},
+		},
+	}
+
+	op := MakeIntegratedServiceOperator(clusterGetter, integratedserviceadapter.NewClusterService(clusterGetter), nil, kubernetesService, services.NoopLogger{})
+
+	err := op.Deactivate(context.Background(), clusterID, nil)
+	require.NoError(t, err)
+
+	assert.NotContains(t, kubernetesService.Secrets, "default/managed")
+	assert.Contains(t, kubernetesService.Secrets, "default/unmanaged")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+)
+
+// Secret is a Pipeline secret that can be synced to a cluster.
+type Secret struct {
+	ID     string
+	Name   string
+	Values map[string]string
+}
+
+// SecretStore returns Pipeline secrets of the organization found in the context.
+type SecretStore interface {
+	// GetSecretByName returns a secret by its name.
+	// Returns a NotFoundError when the secret cannot be found.
+	GetSecretByName(ctx context.Context, name string) (Secret, error)
+
+	// ListSecretsByTags returns every secret that has all the specified tags.
+	ListSecretsByTags(ctx context.Context, tags []string) ([]Secret, error)
+}
+
+// NotFoundError is returned when a secret cannot be found.
+type NotFoundError struct {
+	SecretName string
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "secret not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"secretName", e.SecretName}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+func (NotFoundError) NotFound() bool {
+	return true
+}
+
+func isSecretNotFoundError(err error) bool {
+	var notFoundErr NotFoundError
+
+	return errors.As(err, &notFoundErr)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// ClusterFinder finds the clusters of an organization that have a specific integrated service.
+type ClusterFinder interface {
+	// FindClustersWithIntegratedService returns the IDs of the organization's clusters that have the integrated service.
+	FindClustersWithIntegratedService(ctx context.Context, organizationID uint, integratedServiceName string) ([]uint, error)
+}
+
+// SecretChangeHandler keeps the synced secrets up-to-date by re-applying the secret sync integrated service
+// on every cluster that may reference a changed Pipeline secret.
+type SecretChangeHandler struct {
+	clusterFinder                        ClusterFinder
+	integratedServiceRepository          integratedservices.IntegratedServiceRepository
+	integratedServiceOperationDispatcher integratedservices.IntegratedServiceOperationDispatcher
+	logger                               services.Logger
+}
+
+// NewSecretChangeHandler returns a new SecretChangeHandler.
+func NewSecretChangeHandler(
+	clusterFinder ClusterFinder,
+	integratedServiceRepository integratedservices.IntegratedServiceRepository,
+	integratedServiceOperationDispatcher integratedservices.IntegratedServiceOperationDispatcher,
+	logger services.Logger,
+) SecretChangeHandler {
+	return SecretChangeHandler{
+		clusterFinder:                        clusterFinder,
+		integratedServiceRepository:          integratedServiceRepository,
+		integratedServiceOperationDispatcher: integratedServiceOperationDispatcher,
+		logger:                               logger,
+	}
+}
+
+// SecretChanged starts syncing a created, updated or deleted secret to every cluster that references it.
+func (h SecretChangeHandler) SecretChanged(ctx context.Context, organizationID uint, secretID string) error {
+	ctx = auth.SetCurrentOrganizationID(ctx, organizationID)
+
+	logger := h.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"organization":       organizationID,
+		"secret":             secretID,
+		"integrated service": integratedServiceName,
+	})
+
+	clusterIDs, err := h.clusterFinder.FindClustersWithIntegratedService(ctx, organizationID, integratedServiceName)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to find clusters", "organizationId", organizationID)
+	}
+
+	var errs error
+	for _, clusterID := range clusterIDs {
+		integratedService, err := h.integratedServiceRepository.GetIntegratedService(ctx, clusterID, integratedServiceName)
+		if integratedservices.IsIntegratedServiceNotFoundError(err) {
+			continue
+		} else if err != nil {
+			errs = errors.Append(errs, errors.WrapIfWithDetails(err, "failed to get integrated service", "clusterId", clusterID))
+			continue
+		}
+
+		// the apply is dispatched even if an earlier operation is still pending:
+		// the operation dispatcher serializes the operations of a cluster, so the change is never lost
+		boundSpec, err := bindIntegratedServiceSpec(integratedService.Spec)
+		if err != nil {
+			errs = errors.Append(errs, errors.WithDetails(err, "clusterId", clusterID))
+			continue
+		}
+
+		if !boundSpec.references(secretID) {
+			continue
+		}
+
+		if err := h.integratedServiceOperationDispatcher.DispatchApply(ctx, clusterID, integratedServiceName, integratedService.Spec); err != nil {
+			errs = errors.Append(errs, errors.WrapIfWithDetails(err, "failed to start secret sync", "clusterId", clusterID))
+			continue
+		}
+
+		if err := h.integratedServiceRepository.UpdateIntegratedServiceStatus(ctx, clusterID, integratedServiceName, integratedservices.IntegratedServiceStatusPending); err != nil {
+			errs = errors.Append(errs, errors.WrapIfWithDetails(err, "failed to update integrated service status", "clusterId", clusterID))
+			continue
+		}
+
+		logger.Info("secret sync started", map[string]interface{}{"cluster": clusterID})
+	}
+
+	return errs
+}
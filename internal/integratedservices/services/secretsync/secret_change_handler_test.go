@@ -0,0 +1,127 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+	"github.com/banzaicloud/pipeline/src/secret"
+)
+
+type dummyClusterFinder struct {
+	ClusterIDs []uint
+}
+
+func (d dummyClusterFinder) FindClustersWithIntegratedService(ctx context.Context, organizationID uint, integratedServiceName string) ([]uint, error) {
+	return d.ClusterIDs, nil
+}
+
+type dummyOperationDispatcher struct {
+	Applied []uint
+}
+
+func (d *dummyOperationDispatcher) DispatchApply(ctx context.Context, clusterID uint, integratedServiceName string, spec integratedservices.IntegratedServiceSpec) error {
+	d.Applied = append(d.Applied, clusterID)
+
+	return nil
+}
+
+func (d *dummyOperationDispatcher) DispatchDeactivate(ctx context.Context, clusterID uint, integratedServiceName string, spec integratedservices.IntegratedServiceSpec) error {
+	return nil
+}
+
+func TestSecretChangeHandler_SecretChanged(t *testing.T) {
+	byName := integratedservices.IntegratedServiceSpec{
+		"secrets": []obj{{"name": "db", "namespaces": []string{"default"}}},
+	}
+
+	repository := integratedservices.NewInMemoryIntegratedServiceRepository(map[uint][]integratedservices.IntegratedService{
+		1: {{Name: integratedServiceName, Spec: byName, Status: integratedservices.IntegratedServiceStatusActive}},
+		2: {{Name: integratedServiceName, Spec: byName, Status: integratedservices.IntegratedServiceStatusPending}},
+		3: {{
+			Name: integratedServiceName,
+			Spec: integratedservices.IntegratedServiceSpec{
+				"secrets": []obj{{"name": "other", "namespaces": []string{"default"}}},
+			},
+			Status: integratedservices.IntegratedServiceStatusActive,
+		}},
+		4: {{
+			Name: integratedServiceName,
+			Spec: integratedservices.IntegratedServiceSpec{
+				"secrets": []obj{{"selector": []string{"team:a"}, "namespaces": []string{"default"}}},
+			},
+			Status: integratedservices.IntegratedServiceStatusError,
+		}},
+	})
+	dispatcher := &dummyOperationDispatcher{}
+
+	handler := NewSecretChangeHandler(
+		dummyClusterFinder{ClusterIDs: []uint{1, 2, 3, 4, 5}},
+		repository,
+		dispatcher,
+		services.NoopLogger{},
+	)
+
+	err := handler.SecretChanged(context.Background(), 13, secret.GenerateSecretIDFromName("db"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{1, 2, 4}, dispatcher.Applied)
+
+	for clusterID, status := range map[uint]string{
+		1: integratedservices.IntegratedServiceStatusPending,
+		2: integratedservices.IntegratedServiceStatusPending,
+		3: integratedservices.IntegratedServiceStatusActive,
+		4: integratedservices.IntegratedServiceStatusPending,
+	} {
+		integratedService, err := repository.GetIntegratedService(context.Background(), clusterID, integratedServiceName)
+		require.NoError(t, err)
+
+		assert.Equal(t, status, integratedService.Status)
+	}
+}
+
+func TestSecretChangeHandler_SecretChanged_Pending(t *testing.T) {
+	spec := integratedservices.IntegratedServiceSpec{
+		"secrets": []obj{{"name": "db", "namespaces": []string{"default"}}},
+	}
+
+	repository := integratedservices.NewInMemoryIntegratedServiceRepository(map[uint][]integratedservices.IntegratedService{
+		1: {{Name: integratedServiceName, Spec: spec, Status: integratedservices.IntegratedServiceStatusActive}},
+	})
+	dispatcher := &dummyOperationDispatcher{}
+
+	handler := NewSecretChangeHandler(dummyClusterFinder{ClusterIDs: []uint{1}}, repository, dispatcher, services.NoopLogger{})
+
+	// the first change starts a sync
+	err := handler.SecretChanged(context.Background(), 13, secret.GenerateSecretIDFromName("db"))
+	require.NoError(t, err)
+
+	integratedService, err := repository.GetIntegratedService(context.Background(), 1, integratedServiceName)
+	require.NoError(t, err)
+
+	require.Equal(t, integratedservices.IntegratedServiceStatusPending, integratedService.Status)
+
+	// an update arriving while the first sync is in flight is synced as well
+	err = handler.SecretChanged(context.Background(), 13, secret.GenerateSecretIDFromName("db"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{1, 1}, dispatcher.Applied)
+}
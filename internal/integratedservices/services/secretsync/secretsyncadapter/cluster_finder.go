@@ -0,0 +1,56 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsyncadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// ClusterFinder finds clusters with an integrated service in the database.
+type ClusterFinder struct {
+	db *gorm.DB
+}
+
+// NewClusterFinder returns a new ClusterFinder.
+func NewClusterFinder(db *gorm.DB) ClusterFinder {
+	return ClusterFinder{
+		db: db,
+	}
+}
+
+// FindClustersWithIntegratedService implements the secretsync.ClusterFinder interface.
+func (f ClusterFinder) FindClustersWithIntegratedService(ctx context.Context, organizationID uint, integratedServiceName string) ([]uint, error) {
+	var clusterIDs []uint
+
+	err := f.db.
+		Table("cluster_features").
+		Joins("JOIN clusters ON clusters.id = cluster_features.cluster_id").
+		Where("clusters.organization_id = ? AND clusters.deleted_at IS NULL", organizationID).
+		Where("cluster_features.name = ?", integratedServiceName).
+		Pluck("cluster_features.cluster_id", &clusterIDs).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to query clusters",
+			"organizationId", organizationID,
+			"integratedService", integratedServiceName,
+		)
+	}
+
+	return clusterIDs, nil
+}
@@ -0,0 +1,43 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsyncadapter
+
+type eventBus interface {
+	SubscribeAsync(topic string, fn interface{}, transactional bool) error
+}
+
+type secretEventBus struct {
+	eb eventBus
+}
+
+const (
+	secretCreatedTopic = "secret_created"
+	secretUpdatedTopic = "secret_updated"
+	secretDeletedTopic = "secret_deleted"
+)
+
+// NewSecretEvents gives back a new secretEventBus
+func NewSecretEvents(eb eventBus) *secretEventBus {
+	return &secretEventBus{
+		eb: eb,
+	}
+}
+
+// NotifySecretChanged subscribes to every secret change topic
+func (s *secretEventBus) NotifySecretChanged(fn func(organizationID uint, secretID string)) {
+	s.eb.SubscribeAsync(secretCreatedTopic, fn, false) // nolint: errcheck
+	s.eb.SubscribeAsync(secretUpdatedTopic, fn, false) // nolint: errcheck
+	s.eb.SubscribeAsync(secretDeletedTopic, fn, false) // nolint: errcheck
+}
@@ -0,0 +1,105 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsyncadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services/secretsync"
+	"github.com/banzaicloud/pipeline/internal/secret/restricted"
+	"github.com/banzaicloud/pipeline/src/auth"
+	"github.com/banzaicloud/pipeline/src/secret"
+)
+
+// OrganizationalSecretStore is the global secret store that stores values under a compound key:
+// the organization ID and a secret ID.
+type OrganizationalSecretStore interface {
+	// GetByName returns a secret in the internal format of the secret store based on secret name.
+	GetByName(organizationID uint, name string) (*secret.SecretItemResponse, error)
+
+	// List returns secrets in the internal format of the secret store.
+	List(organizationID uint, query *secret.ListSecretsQuery) ([]*secret.SecretItemResponse, error)
+}
+
+// SecretStore returns Pipeline secrets that can be synced to clusters.
+// Secrets with forbidden tags (eg. cluster kubeconfigs) are never returned.
+type SecretStore struct {
+	store OrganizationalSecretStore
+}
+
+// NewSecretStore returns a new SecretStore.
+func NewSecretStore(store OrganizationalSecretStore) SecretStore {
+	return SecretStore{
+		store: store,
+	}
+}
+
+// GetSecretByName implements the secretsync.SecretStore interface.
+func (s SecretStore) GetSecretByName(ctx context.Context, name string) (secretsync.Secret, error) {
+	organizationID, ok := auth.GetCurrentOrganizationID(ctx)
+	if !ok {
+		return secretsync.Secret{}, errors.NewWithDetails("organization ID cannot be found in the context", "secretName", name)
+	}
+
+	item, err := s.store.GetByName(organizationID, name)
+	if errors.Is(err, secret.ErrSecretNotExists) {
+		return secretsync.Secret{}, errors.WithStack(secretsync.NotFoundError{SecretName: name})
+	} else if err != nil {
+		return secretsync.Secret{}, errors.WrapIfWithDetails(err, "failed to get secret", "organizationId", organizationID, "secretName", name)
+	}
+
+	if err := restricted.HasForbiddenTag(item.Tags); err != nil {
+		return secretsync.Secret{}, errors.WithDetails(err, "organizationId", organizationID, "secretName", name)
+	}
+
+	return secretsync.Secret{
+		ID:     item.ID,
+		Name:   item.Name,
+		Values: item.Values,
+	}, nil
+}
+
+// ListSecretsByTags implements the secretsync.SecretStore interface.
+func (s SecretStore) ListSecretsByTags(ctx context.Context, tags []string) ([]secretsync.Secret, error) {
+	organizationID, ok := auth.GetCurrentOrganizationID(ctx)
+	if !ok {
+		return nil, errors.NewWithDetails("organization ID cannot be found in the context", "tags", tags)
+	}
+
+	items, err := s.store.List(organizationID, &secret.ListSecretsQuery{
+		Tags:   tags,
+		Values: true,
+	})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list secrets", "organizationId", organizationID, "tags", tags)
+	}
+
+	secrets := make([]secretsync.Secret, 0, len(items))
+	for _, item := range items {
+		if restricted.HasForbiddenTag(item.Tags) != nil {
+			continue
+		}
+
+		secrets = append(secrets, secretsync.Secret{
+			ID:     item.ID,
+			Name:   item.Name,
+			Values: item.Values,
+		})
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,121 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/src/secret"
+)
+
+type secretSyncSpec struct {
+	Secrets []secretSpec `json:"secrets" mapstructure:"secrets"`
+}
+
+// secretSpec describes a set of Pipeline secrets and where they should be synced to.
+// Either the name or the selector of the source secrets must be set.
+type secretSpec struct {
+	Name        string            `json:"name" mapstructure:"name"`
+	Selector    []string          `json:"selector" mapstructure:"selector"`
+	TargetName  string            `json:"targetName" mapstructure:"targetName"`
+	Namespaces  []string          `json:"namespaces" mapstructure:"namespaces"`
+	KeyMappings map[string]string `json:"keyMappings" mapstructure:"keyMappings"`
+}
+
+func bindIntegratedServiceSpec(spec integratedservices.IntegratedServiceSpec) (secretSyncSpec, error) {
+	var boundSpec secretSyncSpec
+	if err := mapstructure.Decode(spec, &boundSpec); err != nil {
+		return boundSpec, integratedservices.InvalidIntegratedServiceSpecError{
+			IntegratedServiceName: integratedServiceName,
+			Problem:               "failed to bind integrated service spec",
+		}
+	}
+
+	return boundSpec, nil
+}
+
+func (s secretSyncSpec) Validate() error {
+	if len(s.Secrets) == 0 {
+		return errors.New("at least one secret must be specified")
+	}
+
+	var errs error
+	for i, secretSpec := range s.Secrets {
+		if err := secretSpec.Validate(); err != nil {
+			errs = errors.Append(errs, errors.WithMessage(err, fmt.Sprintf("secrets[%d]", i)))
+		}
+	}
+
+	return errs
+}
+
+func (s secretSpec) Validate() error {
+	if s.Name == "" && len(s.Selector) == 0 {
+		return errors.New("either name or selector must be specified")
+	}
+
+	if s.Name != "" && len(s.Selector) != 0 {
+		return errors.New("name and selector are mutually exclusive")
+	}
+
+	if s.TargetName != "" {
+		if s.Name == "" {
+			return errors.New("target name can only be specified for a secret selected by name")
+		}
+
+		if errs := validation.IsDNS1123Subdomain(s.TargetName); len(errs) != 0 {
+			return errors.Errorf("invalid target name %q: %s", s.TargetName, errs[0])
+		}
+	}
+
+	if len(s.Namespaces) == 0 {
+		return errors.New("at least one target namespace must be specified")
+	}
+
+	for _, namespace := range s.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) != 0 {
+			return errors.Errorf("invalid namespace %q: %s", namespace, errs[0])
+		}
+	}
+
+	for from, to := range s.KeyMappings {
+		if errs := validation.IsConfigMapKey(to); len(errs) != 0 {
+			return errors.Errorf("invalid target key %q for source key %q: %s", to, from, errs[0])
+		}
+	}
+
+	return nil
+}
+
+// references tells whether the spec may refer to the secret identified by the specified ID.
+// Secrets selected by tags may change with every secret change, so a spec with a selector references every secret.
+func (s secretSyncSpec) references(secretID string) bool {
+	for _, secretSpec := range s.Secrets {
+		if len(secretSpec.Selector) != 0 {
+			return true
+		}
+
+		if secret.GenerateSecretIDFromName(secretSpec.Name) == secretID {
+			return true
+		}
+	}
+
+	return false
+}
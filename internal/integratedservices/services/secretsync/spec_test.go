@@ -0,0 +1,127 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/src/secret"
+)
+
+func TestSecretSyncSpec_Validate(t *testing.T) {
+	tests := map[string]struct {
+		Spec    secretSyncSpec
+		WantErr bool
+	}{
+		"valid name": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Name: "db", TargetName: "db-credentials", Namespaces: []string{"default"}},
+			}},
+		},
+		"valid selector with key mappings": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Selector: []string{"team:a"}, Namespaces: []string{"team-a"}, KeyMappings: map[string]string{"password": "DB_PASSWORD"}},
+			}},
+		},
+		"no secrets": {
+			Spec:    secretSyncSpec{},
+			WantErr: true,
+		},
+		"no source": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Namespaces: []string{"default"}},
+			}},
+			WantErr: true,
+		},
+		"both name and selector": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Name: "db", Selector: []string{"team:a"}, Namespaces: []string{"default"}},
+			}},
+			WantErr: true,
+		},
+		"target name with selector": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Selector: []string{"team:a"}, TargetName: "db", Namespaces: []string{"default"}},
+			}},
+			WantErr: true,
+		},
+		"no namespaces": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Name: "db"},
+			}},
+			WantErr: true,
+		},
+		"invalid namespace": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Name: "db", Namespaces: []string{"Not_Valid"}},
+			}},
+			WantErr: true,
+		},
+		"invalid target key": {
+			Spec: secretSyncSpec{Secrets: []secretSpec{
+				{Name: "db", Namespaces: []string{"default"}, KeyMappings: map[string]string{"password": "not valid"}},
+			}},
+			WantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.Spec.Validate()
+			if test.WantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBindIntegratedServiceSpec(t *testing.T) {
+	spec, err := bindIntegratedServiceSpec(obj{
+		"secrets": []obj{
+			{
+				"name":        "db",
+				"namespaces":  []string{"default"},
+				"keyMappings": obj{"password": "DB_PASSWORD"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, secretSyncSpec{Secrets: []secretSpec{
+		{
+			Name:        "db",
+			Namespaces:  []string{"default"},
+			KeyMappings: map[string]string{"password": "DB_PASSWORD"},
+		},
+	}}, spec)
+}
+
+func TestSecretSyncSpec_References(t *testing.T) {
+	byName := secretSyncSpec{Secrets: []secretSpec{{Name: "db", Namespaces: []string{"default"}}}}
+
+	assert.True(t, byName.references(secret.GenerateSecretIDFromName("db")))
+	assert.False(t, byName.references(secret.GenerateSecretIDFromName("other")))
+
+	bySelector := secretSyncSpec{Secrets: []secretSpec{{Selector: []string{"team:a"}, Namespaces: []string{"default"}}}}
+
+	assert.True(t, bySelector.references(secret.GenerateSecretIDFromName("other")))
+}
@@ -0,0 +1,206 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// syncedSecret is the desired state of a Kubernetes secret rendered from a Pipeline secret.
+type syncedSecret struct {
+	Source    string
+	Namespace string
+	Name      string
+	Data      map[string][]byte
+	Checksum  string
+}
+
+func (s syncedSecret) key() string {
+	return s.Namespace + "/" + s.Name
+}
+
+func (s syncedSecret) toKubernetesSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.Name,
+			Namespace: s.Namespace,
+			Labels: map[string]string{
+				managedByLabel: managedByLabelValue,
+			},
+			Annotations: map[string]string{
+				sourceSecretAnnotation: s.Source,
+				checksumAnnotation:     s.Checksum,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: s.Data,
+	}
+}
+
+// resolveSecrets renders the desired state of the synced Kubernetes secrets from the Pipeline secrets referenced in the spec.
+// Secrets referenced by name that do not exist (anymore) are skipped.
+func resolveSecrets(ctx context.Context, secretStore SecretStore, spec secretSyncSpec) ([]syncedSecret, error) {
+	var result []syncedSecret
+
+	targets := make(map[string]string)
+
+	for _, secretSpec := range spec.Secrets {
+		var sources []Secret
+
+		if secretSpec.Name != "" {
+			source, err := secretStore.GetSecretByName(ctx, secretSpec.Name)
+			if isSecretNotFoundError(err) {
+				// the copies of a deleted secret are not desired anymore
+				continue
+			} else if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "failed to get secret", "secret", secretSpec.Name)
+			}
+
+			sources = append(sources, source)
+		} else {
+			var err error
+
+			sources, err = secretStore.ListSecretsByTags(ctx, secretSpec.Selector)
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "failed to list secrets", "selector", secretSpec.Selector)
+			}
+		}
+
+		for _, source := range sources {
+			data, err := mapSecretValues(source, secretSpec.KeyMappings)
+			if err != nil {
+				return nil, err
+			}
+
+			name := source.Name
+			if secretSpec.TargetName != "" {
+				name = secretSpec.TargetName
+			}
+
+			for _, namespace := range secretSpec.Namespaces {
+				s := syncedSecret{
+					Source:    source.Name,
+					Namespace: namespace,
+					Name:      name,
+					Data:      data,
+					Checksum:  checksum(data),
+				}
+
+				if other, ok := targets[s.key()]; ok && other != source.Name {
+					return nil, errors.NewWithDetails(
+						"multiple secrets are synced to the same target",
+						"target", s.key(),
+						"secrets", []string{other, source.Name},
+					)
+				} else if ok {
+					continue
+				}
+
+				targets[s.key()] = source.Name
+				result = append(result, s)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func mapSecretValues(source Secret, keyMappings map[string]string) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+
+	if len(keyMappings) == 0 {
+		for key, value := range source.Values {
+			data[key] = []byte(value)
+		}
+
+		return data, nil
+	}
+
+	for from, to := range keyMappings {
+		value, ok := source.Values[from]
+		if !ok {
+			return nil, errors.NewWithDetails("secret has no such key", "secret", source.Name, "key", from)
+		}
+
+		data[to] = []byte(value)
+	}
+
+	return data, nil
+}
+
+func checksum(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(hash, "%d:%s%d:", len(key), key, len(data[key]))
+		_, _ = hash.Write(data[key])
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// syncStatus compares the desired and the actual state of the synced secrets.
+func syncStatus(desired []syncedSecret, actual []corev1.Secret) []map[string]interface{} {
+	checksums := make(map[string]string, len(actual))
+	for _, s := range actual {
+		checksums[s.Namespace+"/"+s.Name] = s.Annotations[checksumAnnotation]
+	}
+
+	result := make([]map[string]interface{}, 0, len(desired))
+	for _, s := range desired {
+		status := syncStatusSynced
+		if checksum, ok := checksums[s.key()]; !ok {
+			status = syncStatusMissing
+		} else if checksum != s.Checksum {
+			status = syncStatusOutOfSync
+		}
+
+		result = append(result, map[string]interface{}{
+			"source":    s.Source,
+			"namespace": s.Namespace,
+			"name":      s.Name,
+			"status":    status,
+		})
+	}
+
+	return result
+}
+
+func ensureOrgIDInContext(ctx context.Context, clusterGetter integratedserviceadapter.ClusterGetter, clusterID uint) (context.Context, error) {
+	if _, ok := auth.GetCurrentOrganizationID(ctx); !ok {
+		cluster, err := clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get cluster by ID")
+		}
+		ctx = auth.SetCurrentOrganizationID(ctx, cluster.GetOrganizationId())
+	}
+	return ctx, nil
+}
@@ -16,7 +16,9 @@ package securityscan
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"time"
 
 	"emperror.dev/errors"
 
@@ -29,12 +31,13 @@ import (
 
 type Config struct {
 	Anchore           AnchoreConfig
+	Trivy             TrivyConfig
 	PipelineNamespace string
 	Webhook           WebhookConfig
 }
 
 func (c Config) Validate() error {
-	return errors.Combine(c.Anchore.Validate())
+	return errors.Combine(c.Anchore.Validate(), c.Trivy.Validate())
 }
 
 type AnchoreConfig struct {
@@ -61,6 +64,44 @@ func (c AnchoreConfig) Validate() error {
 	return err
 }
 
+// TrivyConfig holds the configuration of the Trivy scanner backend.
+// Trivy runs in server mode inside the cluster.
+type TrivyConfig struct {
+	Enabled     bool
+	Chart       string
+	Version     string
+	Release     string
+	Port        int
+	Image       string
+	ScanTimeout time.Duration
+	Values      map[string]interface{}
+}
+
+func (c TrivyConfig) Validate() error {
+	var err error
+
+	if c.Enabled {
+		if c.Chart == "" || c.Release == "" {
+			err = errors.Append(err, errors.New("trivy chart and release are required"))
+		}
+
+		if c.Image == "" {
+			err = errors.Append(err, errors.New("trivy image is required"))
+		}
+
+		if c.Port <= 0 {
+			err = errors.Append(err, errors.New("trivy port must be positive"))
+		}
+	}
+
+	return err
+}
+
+// ServerURL returns the in-cluster address of the Trivy server.
+func (c TrivyConfig) ServerURL(namespace string) string {
+	return fmt.Sprintf("http://%s.%s:%d", c.Release, namespace, c.Port)
+}
+
 // UserNameGenerator generates an Anchore username for a cluster.
 type UserNameGenerator interface {
 	// GenerateUsername generates an Anchore username for a cluster.
@@ -175,6 +216,33 @@ func (p CustomAnchoreConfigProvider) GetConfiguration(ctx context.Context, clust
 	}, nil
 }
 
+// ScannerProvider returns the scanner backend configured for a cluster.
+type ScannerProvider struct {
+	integratedServicesRepository integratedservices.IntegratedServiceRepository
+}
+
+// NewScannerProvider returns a new ScannerProvider.
+func NewScannerProvider(integratedServiceRepository integratedservices.IntegratedServiceRepository) ScannerProvider {
+	return ScannerProvider{
+		integratedServicesRepository: integratedServiceRepository,
+	}
+}
+
+// GetScanner returns the name of the scanner backend configured for a cluster.
+func (p ScannerProvider) GetScanner(ctx context.Context, clusterID uint) (string, error) {
+	integratedService, err := p.integratedServicesRepository.GetIntegratedService(ctx, clusterID, IntegratedServiceName)
+	if err != nil {
+		return "", err
+	}
+
+	spec, err := bindIntegratedServiceSpec(integratedService.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	return spec.scanner(), nil
+}
+
 // WebhookConfig encapsulates configuration of the image validator webhook
 // sensitive defaults provided through env vars
 type WebhookConfig struct {
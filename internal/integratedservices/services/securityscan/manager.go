@@ -19,6 +19,7 @@ import (
 
 	"github.com/banzaicloud/pipeline/internal/common"
 	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	anchore "github.com/banzaicloud/pipeline/internal/security"
 )
 
 type IntegratedServiceManager struct {
@@ -57,12 +58,25 @@ func (f IntegratedServiceManager) ValidateSpec(ctx context.Context, spec integra
 		}
 	}
 
+	if securityScanSpec.scanner() == anchore.ScannerTrivy && !f.config.Trivy.Enabled {
+		return integratedservices.InvalidIntegratedServiceSpecError{
+			IntegratedServiceName: IntegratedServiceName,
+			Problem:               "trivy scanner is not available",
+		}
+	}
+
 	return nil
 }
 
 func (f IntegratedServiceManager) GetOutput(ctx context.Context, clusterID uint, spec integratedservices.IntegratedServiceSpec) (integratedservices.IntegratedServiceOutput, error) {
+	boundSpec, err := bindIntegratedServiceSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
 	// todo read these through the helm service?
 	out := map[string]interface{}{
+		"scanner": boundSpec.scanner(),
 		"anchore": map[string]interface{}{
 			// leave this for backwards compatibility
 			// to be populated (externally) via direct call to the configured anchore service
@@ -73,5 +87,11 @@ func (f IntegratedServiceManager) GetOutput(ctx context.Context, clusterID uint,
 		},
 	}
 
+	if boundSpec.scanner() == anchore.ScannerTrivy {
+		out["trivy"] = map[string]interface{}{
+			"version": f.config.Trivy.Version,
+		}
+	}
+
 	return out, nil
 }
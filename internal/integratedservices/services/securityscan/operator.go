@@ -19,14 +19,15 @@ import (
 	"encoding/json"
 
 	"emperror.dev/errors"
-	"github.com/mitchellh/mapstructure"
 
 	"github.com/banzaicloud/pipeline/internal/common"
 	"github.com/banzaicloud/pipeline/internal/integratedservices"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/pkg/security"
 	"github.com/banzaicloud/pipeline/src/auth"
-	"github.com/banzaicloud/pipeline/src/secret"
+	legacyHelm "github.com/banzaicloud/pipeline/src/helm"
 )
 
 const (
@@ -47,6 +48,7 @@ type IntegratedServiceOperator struct {
 	anchoreService   IntegratedServiceAnchoreService
 	whiteListService IntegratedServiceWhiteListService
 	namespaceService NamespaceService
+	scanners         map[string]ScannerBackend
 	errorHandler     common.ErrorHandler
 	logger           common.Logger
 }
@@ -72,8 +74,20 @@ func MakeIntegratedServiceOperator(
 		anchoreService:   anchoreService,
 		whiteListService: integratedServiceWhitelistService,
 		namespaceService: NewNamespacesService(clusterGetter, logger), // wired service
-		errorHandler:     errorHandler,
-		logger:           logger,
+		scanners: map[string]ScannerBackend{
+			anchore.ScannerAnchore: anchoreScannerBackend{
+				config:         config,
+				clusterGetter:  clusterGetter,
+				secretStore:    secretStore,
+				anchoreService: anchoreService,
+			},
+			anchore.ScannerTrivy: trivyScannerBackend{
+				config:      config,
+				helmService: helmService,
+			},
+		},
+		errorHandler: errorHandler,
+		logger:       logger,
 	}
 }
 
@@ -100,20 +114,22 @@ func (op IntegratedServiceOperator) Apply(ctx context.Context, clusterID uint, s
 		return errors.WrapIf(err, "failed to apply integrated service")
 	}
 
-	var anchoreValues AnchoreValues
-	if boundSpec.CustomAnchore.Enabled {
-		anchoreValues, err = op.getCustomAnchoreValues(ctx, boundSpec.CustomAnchore)
-		if err != nil {
-			return errors.WrapIf(err, "failed to get default anchore values")
-		}
-	} else {
-		anchoreValues, err = op.getDefaultAnchoreValues(ctx, clusterID)
-		if err != nil {
-			return errors.WrapIf(err, "failed to get default anchore values")
-		}
+	scanner, err := op.getScanner(boundSpec)
+	if err != nil {
+		return errors.WrapIf(err, "failed to apply integrated service")
+	}
+
+	scannerValues, err := scanner.Apply(ctx, clusterID, boundSpec)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to set up scanner backend", "scanner", boundSpec.scanner())
+	}
+
+	if boundSpec.scanner() != anchore.ScannerTrivy && op.config.Trivy.Enabled {
+		// the cluster may have been switched over from trivy, the server is not needed anymore
+		op.removeTrivyServer(ctx, clusterID, boundSpec)
 	}
 
-	values, err := assembleChartValues(anchoreValues, boundSpec.WebhookConfig)
+	values, err := assembleChartValues(scannerValues, boundSpec.WebhookConfig)
 	if err != nil {
 		return errors.WrapIf(err, "failed to assemble chart values")
 	}
@@ -134,10 +150,32 @@ func (op IntegratedServiceOperator) Apply(ctx context.Context, clusterID uint, s
 			//  as agreed, we let the integrated service activation to succeed and log the errors
 			op.errorHandler.HandleContext(ctx, err)
 		}
+
+		if err = op.applyAdmissionModeLabels(ctx, clusterID, boundSpec.WebhookConfig); err != nil {
+			op.errorHandler.HandleContext(ctx, err)
+		}
 	}
 	return nil
 }
 
+// removeTrivyServer uninstalls the trivy server when it is deployed to the cluster
+func (op IntegratedServiceOperator) removeTrivyServer(ctx context.Context, clusterID uint, spec integratedServiceSpec) {
+	_, err := op.helmService.GetDeployment(ctx, clusterID, op.config.Trivy.Release, op.config.Webhook.Namespace)
+	if err != nil {
+		var notFoundErr *legacyHelm.DeploymentNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return
+		}
+
+		op.errorHandler.HandleContext(ctx, errors.WrapIfWithDetails(err, "failed to check trivy server", "clusterId", clusterID))
+		return
+	}
+
+	if err := op.scanners[anchore.ScannerTrivy].Deactivate(ctx, clusterID, spec); err != nil {
+		op.errorHandler.HandleContext(ctx, errors.WithDetails(err, "clusterId", clusterID))
+	}
+}
+
 func (op IntegratedServiceOperator) Deactivate(ctx context.Context, clusterID uint, spec integratedservices.IntegratedServiceSpec) error {
 	ctx, err := op.ensureOrgIDInContext(ctx, clusterID)
 	if err != nil {
@@ -148,11 +186,6 @@ func (op IntegratedServiceOperator) Deactivate(ctx context.Context, clusterID ui
 		return errors.WrapIf(err, "failed to deactivate integrated service")
 	}
 
-	cl, err := op.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
-	if err != nil {
-		return errors.WrapIf(err, "failed to get cluster by ID")
-	}
-
 	boundSpec, err := bindIntegratedServiceSpec(spec)
 	if err != nil {
 		op.logger.Debug("failed to bind the spec")
@@ -165,7 +198,7 @@ func (op IntegratedServiceOperator) Deactivate(ctx context.Context, clusterID ui
 			"clusterID", clusterID)
 	}
 
-	if err := op.namespaceService.CleanupLabels(ctx, clusterID, []string{labelKey}); err != nil {
+	if err := op.namespaceService.CleanupLabels(ctx, clusterID, []string{labelKey, security.AdmissionModeLabel}); err != nil {
 		// if the operation fails for some reason (eg. non-existent namespaces) we notice that and let the deactivation succeed
 		op.logger.Warn("failed to delete namespace labels", map[string]interface{}{"clusterID": clusterID})
 		op.errorHandler.HandleContext(ctx, err)
@@ -173,17 +206,29 @@ func (op IntegratedServiceOperator) Deactivate(ctx context.Context, clusterID ui
 		return nil
 	}
 
-	if !boundSpec.CustomAnchore.Enabled {
-		if err = op.anchoreService.DeleteUser(ctx, cl.GetOrganizationId(), clusterID); err != nil {
-			// deactivation succeeds even in case the generated anchore user is not deleted!
-			op.logger.Warn("failed to delete the anchore user generated for the cluster", map[string]interface{}{"clusterID": clusterID})
-			return nil
-		}
+	scanner, err := op.getScanner(boundSpec)
+	if err != nil {
+		return errors.WrapIf(err, "failed to deactivate integrated service")
+	}
+
+	if err = scanner.Deactivate(ctx, clusterID, boundSpec); err != nil {
+		// deactivation succeeds even in case the scanner backend resources (eg. the generated anchore user) are not deleted!
+		op.logger.Warn("failed to clean up the scanner backend of the cluster", map[string]interface{}{"clusterID": clusterID, "scanner": boundSpec.scanner()})
+		return nil
 	}
 
 	return nil
 }
 
+func (op IntegratedServiceOperator) getScanner(spec integratedServiceSpec) (ScannerBackend, error) {
+	scanner, ok := op.scanners[spec.scanner()]
+	if !ok {
+		return nil, errors.NewWithDetails("unsupported scanner", "scanner", spec.scanner())
+	}
+
+	return scanner, nil
+}
+
 func (op IntegratedServiceOperator) ensureOrgIDInContext(ctx context.Context, clusterID uint) (context.Context, error) {
 	if _, ok := auth.GetCurrentOrganizationID(ctx); !ok {
 		cl, err := op.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
@@ -195,24 +240,13 @@ func (op IntegratedServiceOperator) ensureOrgIDInContext(ctx context.Context, cl
 	return ctx, nil
 }
 
-func (op IntegratedServiceOperator) createAnchoreUserForCluster(ctx context.Context, clusterID uint) (string, error) {
-	cl, err := op.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
-	if err != nil {
-		return "", errors.WrapIf(err, "error retrieving cluster")
-	}
-
-	userName, err := op.anchoreService.GenerateUser(ctx, cl.GetOrganizationId(), clusterID)
-	if err != nil {
-		return "", errors.WrapIf(err, "error creating anchore user")
-	}
-
-	return userName, nil
-}
-
 // assembleChartValues is in charge to assemble the values json for the chart based on the input and configuration
-func assembleChartValues(anchoreValues AnchoreValues, webhookConfigSpec webHookConfigSpec) ([]byte, error) {
+func assembleChartValues(scannerValues ImageValidatorChartValues, webhookConfigSpec webHookConfigSpec) ([]byte, error) {
 	chartValues := webhookConfigSpec.GetValues()
-	chartValues.ExternalAnchore = &anchoreValues
+	chartValues.Scanner = scannerValues.Scanner
+	chartValues.ExternalAnchore = scannerValues.ExternalAnchore
+	chartValues.ExternalTrivy = scannerValues.ExternalTrivy
+	chartValues.PolicyID = scannerValues.PolicyID
 
 	valuesBytes, err := json.Marshal(chartValues)
 	if err != nil {
@@ -222,55 +256,6 @@ func assembleChartValues(anchoreValues AnchoreValues, webhookConfigSpec webHookC
 	return valuesBytes, nil
 }
 
-func (op IntegratedServiceOperator) getCustomAnchoreValues(ctx context.Context, customAnchore anchoreSpec) (AnchoreValues, error) {
-	if !customAnchore.Enabled { // this is already checked
-		return AnchoreValues{}, errors.NewWithDetails("custom anchore disabled")
-	}
-
-	anchoreUserSecret, err := op.secretStore.GetSecretValues(ctx, customAnchore.SecretID)
-	if err != nil {
-		return AnchoreValues{}, errors.WrapWithDetails(err, "failed to get anchore secret", "secretId", customAnchore.SecretID)
-	}
-
-	var anchoreValues AnchoreValues
-	if err := mapstructure.Decode(anchoreUserSecret, &anchoreValues); err != nil {
-		return AnchoreValues{}, errors.WrapIf(err, "failed to extract anchore secret values")
-	}
-
-	anchoreValues.Host = customAnchore.Url
-	anchoreValues.Insecure = customAnchore.Insecure
-
-	return anchoreValues, nil
-}
-
-func (op IntegratedServiceOperator) getDefaultAnchoreValues(ctx context.Context, clusterID uint) (AnchoreValues, error) {
-	// default (pipeline hosted) anchore
-	if !op.config.Anchore.Enabled {
-		return AnchoreValues{}, errors.NewWithDetails("default anchore is not enabled")
-	}
-
-	secretName, err := op.createAnchoreUserForCluster(ctx, clusterID)
-	if err != nil {
-		return AnchoreValues{}, errors.WrapIf(err, "failed to create anchore user")
-	}
-
-	anchoreSecretID := secret.GenerateSecretIDFromName(secretName)
-	anchoreUserSecret, err := op.secretStore.GetSecretValues(ctx, anchoreSecretID)
-	if err != nil {
-		return AnchoreValues{}, errors.WrapWithDetails(err, "failed to get anchore secret", "secretId", anchoreSecretID)
-	}
-
-	var anchoreValues AnchoreValues
-	if err := mapstructure.Decode(anchoreUserSecret, &anchoreValues); err != nil {
-		return AnchoreValues{}, errors.WrapIf(err, "failed to extract anchore secret values")
-	}
-
-	anchoreValues.Host = op.config.Anchore.Endpoint
-	anchoreValues.Insecure = op.config.Anchore.Insecure
-
-	return anchoreValues, nil
-}
-
 // performs namespace labeling based on the provided input
 func (op *IntegratedServiceOperator) applyLabelsForSecurityScan(ctx context.Context, clusterID uint, whConfig webHookConfigSpec) error {
 	// possible label values that are used to make decisions by the webhook
@@ -309,3 +294,22 @@ func (op *IntegratedServiceOperator) applyLabelsForSecurityScan(ctx context.Cont
 
 	return nil
 }
+
+// applyAdmissionModeLabels labels the namespaces with their admission mode overrides
+func (op *IntegratedServiceOperator) applyAdmissionModeLabels(ctx context.Context, clusterID uint, whConfig webHookConfigSpec) error {
+	if err := op.namespaceService.CleanupLabels(ctx, clusterID, []string{security.AdmissionModeLabel}); err != nil {
+		// log the error and continue!
+		op.errorHandler.HandleContext(ctx, err)
+	}
+
+	var combinedErr error
+	for namespace, mode := range whConfig.NamespaceModes {
+		labels := map[string]string{security.AdmissionModeLabel: mode}
+
+		if err := op.namespaceService.LabelNamespaces(ctx, clusterID, []string{namespace}, labels); err != nil {
+			combinedErr = errors.Append(combinedErr, errors.WrapIff(err, "failed to label namespace %s with admission mode", namespace))
+		}
+	}
+
+	return combinedErr
+}
@@ -0,0 +1,59 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityscan
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+	legacyHelm "github.com/banzaicloud/pipeline/src/helm"
+)
+
+func TestIntegratedServiceOperator_removeTrivyServer(t *testing.T) {
+	config := Config{
+		Webhook: WebhookConfig{Namespace: "pipeline-system"},
+		Trivy:   TrivyConfig{Enabled: true, Release: "trivy"},
+	}
+
+	t.Run("NotDeployed", func(t *testing.T) {
+		helmService := &services.MockHelmService{}
+		helmService.On("GetDeployment", mock.Anything, uint(1), "trivy", "pipeline-system").
+			Return(nil, &legacyHelm.DeploymentNotFoundError{HelmError: errors.New("release: not found")})
+
+		op := MakeIntegratedServiceOperator(config, nil, nil, helmService, nil, nil, nil, common.NoopErrorHandler{}, common.NoopLogger{})
+		op.removeTrivyServer(context.Background(), 1, integratedServiceSpec{})
+
+		helmService.AssertExpectations(t)
+		helmService.AssertNotCalled(t, "DeleteDeployment", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deployed", func(t *testing.T) {
+		helmService := &services.MockHelmService{}
+		helmService.On("GetDeployment", mock.Anything, uint(1), "trivy", "pipeline-system").
+			Return(&pkgHelm.GetDeploymentResponse{ReleaseName: "trivy"}, nil)
+		helmService.On("DeleteDeployment", mock.Anything, uint(1), "trivy", "pipeline-system").Return(nil)
+
+		op := MakeIntegratedServiceOperator(config, nil, nil, helmService, nil, nil, nil, common.NoopErrorHandler{}, common.NoopLogger{})
+		op.removeTrivyServer(context.Background(), 1, integratedServiceSpec{})
+
+		helmService.AssertExpectations(t)
+	})
+}
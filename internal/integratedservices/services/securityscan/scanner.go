@@ -0,0 +1,183 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityscan
+
+import (
+	"context"
+	"encoding/json"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/src/secret"
+)
+
+// ScannerBackend sets up a scanner backend for a cluster.
+type ScannerBackend interface {
+	// Apply prepares the backend for the cluster and returns the image validator values pointing to it.
+	Apply(ctx context.Context, clusterID uint, spec integratedServiceSpec) (ImageValidatorChartValues, error)
+
+	// Deactivate cleans up the resources of the backend created for the cluster.
+	Deactivate(ctx context.Context, clusterID uint, spec integratedServiceSpec) error
+}
+
+// anchoreScannerBackend uses either the Pipeline hosted or a custom Anchore instance.
+type anchoreScannerBackend struct {
+	config         Config
+	clusterGetter  integratedserviceadapter.ClusterGetter
+	secretStore    services.SecretStore
+	anchoreService IntegratedServiceAnchoreService
+}
+
+func (b anchoreScannerBackend) Apply(ctx context.Context, clusterID uint, spec integratedServiceSpec) (ImageValidatorChartValues, error) {
+	var (
+		anchoreValues AnchoreValues
+		err           error
+	)
+
+	if spec.CustomAnchore.Enabled {
+		anchoreValues, err = b.getCustomAnchoreValues(ctx, spec.CustomAnchore)
+		if err != nil {
+			return ImageValidatorChartValues{}, errors.WrapIf(err, "failed to get custom anchore values")
+		}
+	} else {
+		anchoreValues, err = b.getDefaultAnchoreValues(ctx, clusterID)
+		if err != nil {
+			return ImageValidatorChartValues{}, errors.WrapIf(err, "failed to get default anchore values")
+		}
+	}
+
+	return ImageValidatorChartValues{
+		ExternalAnchore: &anchoreValues,
+	}, nil
+}
+
+func (b anchoreScannerBackend) Deactivate(ctx context.Context, clusterID uint, spec integratedServiceSpec) error {
+	if spec.CustomAnchore.Enabled {
+		return nil
+	}
+
+	cl, err := b.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster by ID")
+	}
+
+	return b.anchoreService.DeleteUser(ctx, cl.GetOrganizationId(), clusterID)
+}
+
+func (b anchoreScannerBackend) createAnchoreUserForCluster(ctx context.Context, clusterID uint) (string, error) {
+	cl, err := b.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return "", errors.WrapIf(err, "error retrieving cluster")
+	}
+
+	userName, err := b.anchoreService.GenerateUser(ctx, cl.GetOrganizationId(), clusterID)
+	if err != nil {
+		return "", errors.WrapIf(err, "error creating anchore user")
+	}
+
+	return userName, nil
+}
+
+func (b anchoreScannerBackend) getCustomAnchoreValues(ctx context.Context, customAnchore anchoreSpec) (AnchoreValues, error) {
+	if !customAnchore.Enabled { // this is already checked
+		return AnchoreValues{}, errors.NewWithDetails("custom anchore disabled")
+	}
+
+	anchoreUserSecret, err := b.secretStore.GetSecretValues(ctx, customAnchore.SecretID)
+	if err != nil {
+		return AnchoreValues{}, errors.WrapWithDetails(err, "failed to get anchore secret", "secretId", customAnchore.SecretID)
+	}
+
+	var anchoreValues AnchoreValues
+	if err := mapstructure.Decode(anchoreUserSecret, &anchoreValues); err != nil {
+		return AnchoreValues{}, errors.WrapIf(err, "failed to extract anchore secret values")
+	}
+
+	anchoreValues.Host = customAnchore.Url
+	anchoreValues.Insecure = customAnchore.Insecure
+
+	return anchoreValues, nil
+}
+
+func (b anchoreScannerBackend) getDefaultAnchoreValues(ctx context.Context, clusterID uint) (AnchoreValues, error) {
+	// default (pipeline hosted) anchore
+	if !b.config.Anchore.Enabled {
+		return AnchoreValues{}, errors.NewWithDetails("default anchore is not enabled")
+	}
+
+	secretName, err := b.createAnchoreUserForCluster(ctx, clusterID)
+	if err != nil {
+		return AnchoreValues{}, errors.WrapIf(err, "failed to create anchore user")
+	}
+
+	anchoreSecretID := secret.GenerateSecretIDFromName(secretName)
+	anchoreUserSecret, err := b.secretStore.GetSecretValues(ctx, anchoreSecretID)
+	if err != nil {
+		return AnchoreValues{}, errors.WrapWithDetails(err, "failed to get anchore secret", "secretId", anchoreSecretID)
+	}
+
+	var anchoreValues AnchoreValues
+	if err := mapstructure.Decode(anchoreUserSecret, &anchoreValues); err != nil {
+		return AnchoreValues{}, errors.WrapIf(err, "failed to extract anchore secret values")
+	}
+
+	anchoreValues.Host = b.config.Anchore.Endpoint
+	anchoreValues.Insecure = b.config.Anchore.Insecure
+
+	return anchoreValues, nil
+}
+
+// trivyScannerBackend deploys a Trivy server into the cluster.
+type trivyScannerBackend struct {
+	config      Config
+	helmService services.HelmService
+}
+
+func (b trivyScannerBackend) Apply(ctx context.Context, clusterID uint, spec integratedServiceSpec) (ImageValidatorChartValues, error) {
+	if !b.config.Trivy.Enabled {
+		return ImageValidatorChartValues{}, errors.NewWithDetails("trivy scanner is not enabled")
+	}
+
+	values, err := json.Marshal(b.config.Trivy.Values)
+	if err != nil {
+		return ImageValidatorChartValues{}, errors.WrapIf(err, "failed to marshal trivy chart values")
+	}
+
+	err = b.helmService.ApplyDeployment(ctx, clusterID, b.config.Webhook.Namespace, b.config.Trivy.Chart, b.config.Trivy.Release,
+		values, b.config.Trivy.Version)
+	if err != nil {
+		return ImageValidatorChartValues{}, errors.WrapIf(err, "failed to deploy trivy server")
+	}
+
+	return ImageValidatorChartValues{
+		Scanner: anchore.ScannerTrivy,
+		ExternalTrivy: &TrivyValues{
+			Host: b.config.Trivy.ServerURL(b.config.Webhook.Namespace),
+		},
+		PolicyID: spec.Policy.PolicyID,
+	}, nil
+}
+
+func (b trivyScannerBackend) Deactivate(ctx context.Context, clusterID uint, spec integratedServiceSpec) error {
+	if err := b.helmService.DeleteDeployment(ctx, clusterID, b.config.Trivy.Release, b.config.Webhook.Namespace); err != nil {
+		return errors.WrapIf(err, "failed to delete trivy server")
+	}
+
+	return nil
+}
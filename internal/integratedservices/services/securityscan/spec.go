@@ -19,10 +19,13 @@ import (
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/pkg/security"
 )
 
 //integratedServiceSpec security scan cluster integrated service specific specification
 type integratedServiceSpec struct {
+	Scanner          string            `json:"scanner,omitempty" mapstructure:"scanner"`
 	CustomAnchore    anchoreSpec       `json:"customAnchore" mapstructure:"customAnchore"`
 	Policy           policySpec        `json:"policy" mapstructure:"policy"`
 	ReleaseWhiteList []releaseSpec     `json:"releaseWhiteList,omitempty" mapstructure:"releaseWhiteList"`
@@ -33,8 +36,22 @@ type integratedServiceSpec struct {
 func (s integratedServiceSpec) Validate(pipelineNamespace string) error {
 	var validationErrors error
 
+	switch s.scanner() {
+	case anchore.ScannerAnchore:
+	case anchore.ScannerTrivy:
+		if s.CustomAnchore.Enabled {
+			validationErrors = errors.Combine(validationErrors, errors.New("custom anchore cannot be used with the trivy scanner"))
+		}
+
+		if s.Policy.CustomPolicy.Enabled {
+			validationErrors = errors.Combine(validationErrors, errors.New("custom policies are only supported by the anchore scanner"))
+		}
+	default:
+		validationErrors = errors.Combine(validationErrors, errors.Errorf("unsupported scanner: %q", s.Scanner))
+	}
+
 	if s.CustomAnchore.Enabled {
-		validationErrors = s.CustomAnchore.Validate()
+		validationErrors = errors.Combine(validationErrors, s.CustomAnchore.Validate())
 	}
 
 	if !s.Policy.CustomPolicy.Enabled && s.Policy.PolicyID == "" {
@@ -50,6 +67,15 @@ func (s integratedServiceSpec) Validate(pipelineNamespace string) error {
 	return validationErrors
 }
 
+// scanner returns the scanner backend of the spec, defaulting to Anchore.
+func (s integratedServiceSpec) scanner() string {
+	if s.Scanner == "" {
+		return anchore.ScannerAnchore
+	}
+
+	return s.Scanner
+}
+
 type anchoreSpec struct {
 	Enabled  bool   `json:"enabled" mapstructure:"enabled"`
 	Url      string `json:"url" mapstructure:"url"`
@@ -95,6 +121,15 @@ type webHookConfigSpec struct {
 	Enabled    bool     `json:"enabled" mapstructure:"enabled"`
 	Selector   string   `json:"selector" mapstructure:"selector"`
 	Namespaces []string `json:"namespaces" mapstructure:"namespaces"`
+
+	// Mode is the default admission mode: enforce, warn or audit
+	Mode string `json:"mode,omitempty" mapstructure:"mode"`
+
+	// NamespaceModes overrides the admission mode for individual namespaces
+	NamespaceModes map[string]string `json:"namespaceModes,omitempty" mapstructure:"namespaceModes"`
+
+	// AllowBreakGlass lets workloads annotated with the break-glass annotation bypass the policy check
+	AllowBreakGlass bool `json:"allowBreakGlass,omitempty" mapstructure:"allowBreakGlass"`
 }
 
 func (w webHookConfigSpec) Validate(pipelineNamespace string) error {
@@ -108,11 +143,34 @@ func (w webHookConfigSpec) Validate(pipelineNamespace string) error {
 				return errors.Errorf("the following namespaces may not be modified: %v", []string{pipelineNamespace, "kube-system"})
 			}
 		}
+
+		if w.Mode != "" && !security.IsValidAdmissionMode(w.Mode) {
+			return errors.Errorf("invalid admission mode: %q", w.Mode)
+		}
+
+		for ns, mode := range w.NamespaceModes {
+			if ns == pipelineNamespace || ns == "kube-system" {
+				return errors.Errorf("the following namespaces may not be modified: %v", []string{pipelineNamespace, "kube-system"})
+			}
+
+			if !security.IsValidAdmissionMode(mode) {
+				return errors.Errorf("invalid admission mode for namespace %s: %q", ns, mode)
+			}
+		}
 	}
 
 	return nil
 }
 
+// mode returns the default admission mode, defaulting to enforce.
+func (w webHookConfigSpec) mode() string {
+	if w.Mode == "" {
+		return security.AdmissionModeEnforce
+	}
+
+	return w.Mode
+}
+
 func (w webHookConfigSpec) allNamespaces() bool {
 	return (len(w.Namespaces) == 1) && w.Namespaces[0] == selectedAllStar
 }
@@ -151,8 +209,21 @@ func (w webHookConfigSpec) GetValues() ImageValidatorChartValues {
 		}
 	}
 
-	return ImageValidatorChartValues{
+	values := ImageValidatorChartValues{
 		NamespaceSelector: namespaceSelector,
 		ObjectSelector:    objectSelector,
 	}
+
+	if w.Enabled && (w.mode() != security.AdmissionModeEnforce || len(w.NamespaceModes) > 0 || w.AllowBreakGlass) {
+		values.Admission = &AdmissionValues{
+			Mode:      w.mode(),
+			ModeLabel: security.AdmissionModeLabel,
+		}
+
+		if w.AllowBreakGlass {
+			values.Admission.BreakGlassAnnotation = security.BreakGlassAnnotation
+		}
+	}
+
+	return values
 }
@@ -120,3 +120,153 @@ func TestMarshalValues(t *testing.T) {
 		})
 	}
 }
+
+func Test_integratedServiceSpec_ValidateScanner(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    integratedServiceSpec
+		wantErr bool
+	}{
+		{
+			name: "default scanner",
+			spec: integratedServiceSpec{
+				Policy: policySpec{PolicyID: "policy"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "trivy scanner",
+			spec: integratedServiceSpec{
+				Scanner: "trivy",
+				Policy:  policySpec{PolicyID: "policy"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "trivy scanner with custom anchore",
+			spec: integratedServiceSpec{
+				Scanner: "trivy",
+				CustomAnchore: anchoreSpec{
+					Enabled:  true,
+					Url:      "anchore.example.com",
+					SecretID: "secret",
+				},
+				Policy: policySpec{PolicyID: "policy"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "trivy scanner with custom policy",
+			spec: integratedServiceSpec{
+				Scanner: "trivy",
+				Policy: policySpec{
+					CustomPolicy: customPolicySpec{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown scanner",
+			spec: integratedServiceSpec{
+				Scanner: "clair",
+				Policy:  policySpec{PolicyID: "policy"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.Validate("pipeline-system"); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_webHookConfigSpec_ValidateAdmissionModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    webHookConfigSpec
+		wantErr bool
+	}{
+		{
+			name: "default mode",
+			spec: webHookConfigSpec{
+				Enabled:    true,
+				Selector:   selectorInclude,
+				Namespaces: []string{selectedAllStar},
+			},
+			wantErr: false,
+		},
+		{
+			name: "audit mode with namespace overrides",
+			spec: webHookConfigSpec{
+				Enabled:        true,
+				Selector:       selectorInclude,
+				Namespaces:     []string{selectedAllStar},
+				Mode:           "audit",
+				NamespaceModes: map[string]string{"prod": "enforce", "staging": "warn"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid mode",
+			spec: webHookConfigSpec{
+				Enabled:    true,
+				Selector:   selectorInclude,
+				Namespaces: []string{selectedAllStar},
+				Mode:       "block",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid namespace mode",
+			spec: webHookConfigSpec{
+				Enabled:        true,
+				Selector:       selectorInclude,
+				Namespaces:     []string{selectedAllStar},
+				NamespaceModes: map[string]string{"prod": "block"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "protected namespace mode",
+			spec: webHookConfigSpec{
+				Enabled:        true,
+				Selector:       selectorInclude,
+				Namespaces:     []string{selectedAllStar},
+				NamespaceModes: map[string]string{"kube-system": "audit"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.Validate("pipeline-system"); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_webHookConfigSpec_GetAdmissionValues(t *testing.T) {
+	spec := webHookConfigSpec{
+		Enabled:         true,
+		Selector:        selectorInclude,
+		Namespaces:      []string{selectedAllStar},
+		Mode:            "warn",
+		AllowBreakGlass: true,
+	}
+
+	want := &AdmissionValues{
+		Mode:                 "warn",
+		ModeLabel:            "scan-mode",
+		BreakGlassAnnotation: "security.banzaicloud.io/break-glass",
+	}
+
+	if got := spec.GetValues().Admission; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetValues().Admission = %v, want %v", got, want)
+	}
+}
@@ -16,9 +16,25 @@ package securityscan
 
 // represents a values yaml to be passed to the anchore image validator webhook chart
 type ImageValidatorChartValues struct {
+	Scanner           string            `json:"scanner,omitempty" mapstructure:"scanner"`
 	ExternalAnchore   *AnchoreValues    `json:"externalAnchore,omitempty" mapstructure:"externalAnchore"`
+	ExternalTrivy     *TrivyValues      `json:"externalTrivy,omitempty" mapstructure:"externalTrivy"`
+	PolicyID          string            `json:"policyId,omitempty" mapstructure:"policyId"`
 	NamespaceSelector *SetBasedSelector `json:"namespaceSelector,omitempty" mapstructure:"namespaceSelector"`
 	ObjectSelector    *SetBasedSelector `json:"objectSelector,omitempty" mapstructure:"objectSelector"`
+	Admission         *AdmissionValues  `json:"admission,omitempty" mapstructure:"admission"`
+}
+
+// AdmissionValues configures how the webhook acts on policy failures
+type AdmissionValues struct {
+	// Mode is the default admission mode
+	Mode string `json:"mode" mapstructure:"mode"`
+
+	// ModeLabel is the namespace label overriding the default mode, also set on the recorded scan logs
+	ModeLabel string `json:"modeLabel" mapstructure:"modeLabel"`
+
+	// BreakGlassAnnotation is the workload annotation bypassing the policy check, disabled if empty
+	BreakGlassAnnotation string `json:"breakGlassAnnotation,omitempty" mapstructure:"breakGlassAnnotation"`
 }
 
 // AnchoreValues struct used to build chart values and to extract anchore data from secret values
@@ -29,6 +45,11 @@ type AnchoreValues struct {
 	Insecure bool   `json:"insecureSkipVerify" mapstructure:"insecure"`
 }
 
+// TrivyValues struct used to point the image validator webhook to the Trivy server
+type TrivyValues struct {
+	Host string `json:"trivyHost" mapstructure:"host"`
+}
+
 type MatchExpression struct {
 	Key      string   `json:"key" mapstructure:"key"`
 	Operator string   `json:"operator" mapstructure:"operator"`
@@ -114,22 +114,58 @@ func (m vaultManager) configureAuth(tokenReviewerJWT, kubernetesHost string, caC
 	return m.vaultClient.RawClient().Logical().Write(getAuthMethodConfigPath(m.orgID, m.clusterID), configData)
 }
 
-func (m vaultManager) createRole(serviceAccounts, namespaces []string) (*vaultapi.Secret, error) {
+func (m vaultManager) createRole(roleName string, serviceAccounts, namespaces, policies []string, ttl, maxTTL string) (*vaultapi.Secret, error) {
+	if ttl == "" {
+		ttl = defaultRoleTTL
+	}
+
 	roleData := map[string]interface{}{
 		"bound_service_account_names":      serviceAccounts,
 		"bound_service_account_namespaces": namespaces,
-		"policies":                         []string{getPolicyName(m.orgID, m.clusterID)},
-		"ttl":                              "1h",
+		"policies":                         policies,
+		"ttl":                              ttl,
+	}
+	if maxTTL != "" {
+		roleData["max_ttl"] = maxTTL
 	}
-	return m.vaultClient.RawClient().Logical().Write(getRolePath(m.orgID, m.clusterID, getRoleName(m.customVault)), roleData)
+
+	return m.vaultClient.RawClient().Logical().Write(getRolePath(m.orgID, m.clusterID, roleName), roleData)
+}
+
+func (m vaultManager) listRoles() ([]string, error) {
+	secret, err := m.vaultClient.RawClient().Logical().List(fmt.Sprintf("auth/%s/role", getAuthMethodPath(m.orgID, m.clusterID)))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list roles")
+	}
+
+	if secret == nil || secret.Data == nil {
+		return nil, nil
+	}
+
+	keys, _ := secret.Data["keys"].([]interface{})
+
+	roles := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if role, ok := key.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles, nil
+}
+
+func (m vaultManager) deleteRole(roleName string) error {
+	_, err := m.vaultClient.RawClient().Logical().Delete(getRolePath(m.orgID, m.clusterID, roleName))
+
+	return err
 }
 
-func (m vaultManager) createPolicy(policy string) error {
-	return m.vaultClient.RawClient().Sys().PutPolicy(getPolicyName(m.orgID, m.clusterID), policy)
+func (m vaultManager) createPolicy(policyName, policy string) error {
+	return m.vaultClient.RawClient().Sys().PutPolicy(policyName, policy)
 }
 
-func (m vaultManager) deletePolicy() error {
-	return m.vaultClient.RawClient().Sys().DeletePolicy(getPolicyName(m.orgID, m.clusterID))
+func (m vaultManager) deletePolicy(policyName string) error {
+	return m.vaultClient.RawClient().Sys().DeletePolicy(policyName)
 }
 
 func (m vaultManager) close() {
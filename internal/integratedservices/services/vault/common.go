@@ -28,4 +28,14 @@ const (
 	policyNamePrefix        = "allow_cluster_secrets"
 	vaultTokenReviewer      = "vault-token-reviewer"
 	vaultTokenKey           = "token"
+	defaultRoleTTL          = "1h"
+)
+
+const (
+	// SecretInjectionNamespaceLabel is the namespace label that controls the secret injection webhook.
+	// Namespaces labeled with SecretInjectionDisabled are skipped by the webhook.
+	SecretInjectionNamespaceLabel = "vault.banzaicloud.io/secret-injection"
+
+	// SecretInjectionDisabled is the value of SecretInjectionNamespaceLabel that disables secret injection.
+	SecretInjectionDisabled = "disabled"
 )
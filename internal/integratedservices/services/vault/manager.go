@@ -16,12 +16,14 @@ package vault
 
 import (
 	"context"
+	"fmt"
 
 	"emperror.dev/errors"
 
 	"github.com/banzaicloud/pipeline/internal/integratedservices"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
 	"github.com/banzaicloud/pipeline/internal/integratedservices/services"
+	"github.com/banzaicloud/pipeline/src/auth"
 )
 
 // IntegratedServiceManager implements the Vault integrated service manager
@@ -95,6 +97,10 @@ func (m IntegratedServicesManager) GetOutput(ctx context.Context, clusterID uint
 		return nil, errors.WrapIf(err, "failed to get Vault output")
 	}
 
+	if len(boundSpec.Roles) > 0 {
+		vaultOutput["roles"] = getRolesOutput(boundSpec.Roles, orgID, clusterID, !boundSpec.CustomVault.Enabled)
+	}
+
 	out := map[string]interface{}{
 		"vault": vaultOutput,
 		"webhook": map[string]interface{}{
@@ -124,6 +130,23 @@ func getVaultOutput(m vaultManager, orgID, clusterID uint) (map[string]interface
 	return out, nil
 }
 
+func getRolesOutput(roles []Role, orgID, clusterID uint, managedVault bool) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(roles))
+	for _, role := range roles {
+		roleOutput := map[string]interface{}{
+			"name":   role.Name,
+			"policy": getRolePolicyName(orgID, clusterID, role.Name),
+		}
+		if policy, err := renderRolePolicy(role, orgID, clusterID, managedVault); err == nil {
+			roleOutput["policyRules"] = policy
+		}
+
+		out = append(out, roleOutput)
+	}
+
+	return out
+}
+
 // ValidateSpec validates a Vault integrated service specification
 func (m IntegratedServicesManager) ValidateSpec(ctx context.Context, spec integratedservices.IntegratedServiceSpec) error {
 	vaultSpec, err := bindIntegratedServiceSpec(spec)
@@ -145,5 +168,17 @@ func (m IntegratedServicesManager) ValidateSpec(ctx context.Context, spec integr
 		}
 	}
 
+	// policies in Pipeline's managed Vault are checked against the organization's secrets once they can be rendered
+	if orgID, ok := auth.GetCurrentOrganizationID(ctx); ok && !vaultSpec.CustomVault.Enabled {
+		for _, role := range vaultSpec.Roles {
+			if _, err := renderRolePolicy(role, orgID, 0, true); err != nil {
+				return integratedservices.InvalidIntegratedServiceSpecError{
+					IntegratedServiceName: integratedServiceName,
+					Problem:               fmt.Sprintf("invalid role %q: %s", role.Name, err.Error()),
+				}
+			}
+		}
+	}
+
 	return nil
 }
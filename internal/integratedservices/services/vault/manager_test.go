@@ -175,6 +175,24 @@ func TestIntegratedServiceManager_ValidateSpec(t *testing.T) {
 			IsManagedEnabled: true,
 			Error:            true,
 		},
+		"role policy escaping the organization": {
+			Spec: obj{
+				"settings": obj{
+					"namespaces":      []string{"default"},
+					"serviceAccounts": []string{"default"},
+				},
+				"roles": []obj{
+					{
+						"name":            "team-a",
+						"namespaces":      []string{"team-a"},
+						"serviceAccounts": []string{"default"},
+						"policyPaths":     []obj{{"path": "secret/data/orgs/14/*"}},
+					},
+				},
+			},
+			IsManagedEnabled: true,
+			Error:            true,
+		},
 		"disable CP Vault": {
 			Spec: obj{
 				"customVault": obj{
@@ -192,7 +210,7 @@ func TestIntegratedServiceManager_ValidateSpec(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			ctx := context.Background()
+			ctx := auth.SetCurrentOrganizationID(context.Background(), 13)
 
 			mng := MakeIntegratedServiceManager(nil, nil, Config{Managed: ManagedConfig{Enabled: tc.IsManagedEnabled}}, nil)
 			err := mng.ValidateSpec(ctx, tc.Spec)
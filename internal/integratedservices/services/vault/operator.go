@@ -251,7 +251,7 @@ func (op IntegratedServicesOperator) configureVault(
 		} else {
 			policy = getDefaultPolicy(orgID)
 		}
-		if err := vaultManager.createPolicy(policy); err != nil {
+		if err := vaultManager.createPolicy(getPolicyName(orgID, clusterID), policy); err != nil {
 			return errors.WrapIf(err, "failed to create policy")
 		}
 		logger.Info("policy created successfully")
@@ -269,10 +269,83 @@ func (op IntegratedServicesOperator) configureVault(
 		logger.Info(fmt.Sprintf("auth method %q configured for vault", authMethodType))
 
 		// create role
-		if _, err := vaultManager.createRole(boundSpec.Settings.ServiceAccounts, boundSpec.Settings.Namespaces); err != nil {
+		defaultRoleName := getRoleName(boundSpec.CustomVault.Enabled)
+		_, err = vaultManager.createRole(
+			defaultRoleName,
+			boundSpec.Settings.ServiceAccounts,
+			boundSpec.Settings.Namespaces,
+			[]string{getPolicyName(orgID, clusterID)},
+			defaultRoleTTL,
+			"",
+		)
+		if err != nil {
 			return errors.WrapIf(err, fmt.Sprintf("failed to create role in the auth method %q", authMethodType))
 		}
 		logger.Info(fmt.Sprintf("role created in auth method %q for vault", authMethodType))
+
+		if err := op.reconcileRoles(logger, vaultManager, orgID, clusterID, defaultRoleName, boundSpec.Roles, !boundSpec.CustomVault.Enabled); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileRoles creates or updates the roles (and their policies) in the spec
+// and removes the ones that are no longer present.
+func (op IntegratedServicesOperator) reconcileRoles(
+	logger common.Logger,
+	vaultManager *vaultManager,
+	orgID,
+	clusterID uint,
+	defaultRoleName string,
+	roles []Role,
+	managedVault bool,
+) error {
+	desiredRoles := map[string]bool{
+		defaultRoleName: true,
+	}
+
+	for _, role := range roles {
+		desiredRoles[role.Name] = true
+
+		policy, err := renderRolePolicy(role, orgID, clusterID, managedVault)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to render role policy", "role", role.Name)
+		}
+
+		policyName := getRolePolicyName(orgID, clusterID, role.Name)
+		if err := vaultManager.createPolicy(policyName, policy); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to create role policy", "role", role.Name)
+		}
+
+		_, err = vaultManager.createRole(role.Name, role.ServiceAccounts, role.Namespaces, []string{policyName}, role.TTL, role.MaxTTL)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to create role", "role", role.Name)
+		}
+
+		logger.Info("role created", map[string]interface{}{"role": role.Name})
+	}
+
+	existingRoles, err := vaultManager.listRoles()
+	if err != nil {
+		return err
+	}
+
+	for _, roleName := range existingRoles {
+		if desiredRoles[roleName] {
+			continue
+		}
+
+		if err := vaultManager.deleteRole(roleName); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to delete role", "role", roleName)
+		}
+
+		if err := vaultManager.deletePolicy(getRolePolicyName(orgID, clusterID, roleName)); err != nil {
+			logger.Warn(fmt.Sprintf("failed to delete policy of role %q in vault: %v", roleName, err))
+		}
+
+		logger.Info("role deleted", map[string]interface{}{"role": roleName})
 	}
 
 	return nil
@@ -311,6 +384,11 @@ func (op IntegratedServicesOperator) installOrUpdateWebhook(
 						pipelineSystemNamespace,
 					},
 				},
+				{
+					Key:      SecretInjectionNamespaceLabel,
+					Operator: "NotIn",
+					Values:   []string{SecretInjectionDisabled},
+				},
 			},
 		},
 	}
@@ -397,12 +475,19 @@ func (op IntegratedServicesOperator) Deactivate(ctx context.Context, clusterID u
 		}
 
 		// delete policy
-		if err := vaultManager.deletePolicy(); err != nil {
+		if err := vaultManager.deletePolicy(getPolicyName(orgID, clusterID)); err != nil {
 			logger.Warn(fmt.Sprintf("failed to delete policy in vault: %v", err))
 		} else {
 			logger.Info("vault policy deleted successfully")
 		}
 
+		// delete role policies, the roles themselves are gone with the auth method
+		for _, role := range boundSpec.Roles {
+			if err := vaultManager.deletePolicy(getRolePolicyName(orgID, clusterID, role.Name)); err != nil {
+				logger.Warn(fmt.Sprintf("failed to delete policy of role %q in vault: %v", role.Name, err))
+			}
+		}
+
 		// delete kubernetes service account
 		pipelineSystemNamespace := op.config.Namespace
 		if err := op.kubernetesService.DeleteObject(ctx, clusterID, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: vaultTokenReviewer, Namespace: pipelineSystemNamespace}}); err != nil {
@@ -0,0 +1,136 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"emperror.dev/errors"
+)
+
+var policyCapabilities = map[string]bool{
+	"create": true,
+	"read":   true,
+	"update": true,
+	"delete": true,
+	"list":   true,
+	"sudo":   true,
+	"deny":   true,
+}
+
+// policyPathData is the data available in policy path templates.
+type policyPathData struct {
+	OrganizationID uint
+	ClusterID      uint
+	Role           string
+	Namespace      string
+}
+
+func isNamespaceDependent(path string) bool {
+	return strings.Contains(path, ".Namespace")
+}
+
+// renderRolePolicy returns the HCL policy of a role either as given or generated from its policy paths.
+// In Pipeline's managed Vault only generated policies are allowed and every path must be within the secrets of the organization.
+func renderRolePolicy(role Role, orgID, clusterID uint, managedVault bool) (string, error) {
+	if role.Policy != "" {
+		if managedVault {
+			return "", errors.New("custom policies are only allowed in a custom Vault, use policy paths instead")
+		}
+
+		return role.Policy, nil
+	}
+
+	var buf bytes.Buffer
+	rendered := make(map[string]bool)
+
+	for _, policyPath := range role.PolicyPaths {
+		tmpl, err := template.New(role.Name).Option("missingkey=error").Parse(policyPath.Path)
+		if err != nil {
+			return "", errors.WrapIfWithDetails(err, "failed to parse policy path template", "path", policyPath.Path)
+		}
+
+		namespaces := []string{""}
+		if isNamespaceDependent(policyPath.Path) {
+			namespaces = role.Namespaces
+		}
+
+		capabilities := policyPath.Capabilities
+		if len(capabilities) == 0 {
+			capabilities = []string{"read"}
+		}
+
+		for _, namespace := range namespaces {
+			var path bytes.Buffer
+
+			err := tmpl.Execute(&path, policyPathData{
+				OrganizationID: orgID,
+				ClusterID:      clusterID,
+				Role:           role.Name,
+				Namespace:      namespace,
+			})
+			if err != nil {
+				return "", errors.WrapIfWithDetails(err, "failed to render policy path template", "path", policyPath.Path)
+			}
+
+			if managedVault {
+				if err := checkManagedPolicyPath(path.String(), orgID); err != nil {
+					return "", err
+				}
+			}
+
+			if rendered[path.String()] {
+				continue
+			}
+			rendered[path.String()] = true
+
+			quoted := make([]string, 0, len(capabilities))
+			for _, capability := range capabilities {
+				quoted = append(quoted, fmt.Sprintf("%q", capability))
+			}
+
+			fmt.Fprintf(&buf, "path %q {\n\tcapabilities = [ %s ]\n}\n", path.String(), strings.Join(quoted, ", "))
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// checkManagedPolicyPath returns an error if a rendered policy path is outside the secrets of the organization.
+func checkManagedPolicyPath(path string, orgID uint) error {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return errors.NewWithDetails("policy path cannot contain relative segments", "path", path)
+		}
+	}
+
+	for _, prefix := range []string{"secret/data/orgs/%d/", "secret/metadata/orgs/%d/"} {
+		if strings.HasPrefix(path, fmt.Sprintf(prefix, orgID)) {
+			return nil
+		}
+	}
+
+	return errors.NewWithDetails(
+		fmt.Sprintf("policy path must be within secret/data/orgs/%d/ or secret/metadata/orgs/%d/", orgID, orgID),
+		"path", path,
+	)
+}
+
+func getRolePolicyName(orgID, clusterID uint, roleName string) string {
+	return fmt.Sprintf("%s_%s", getPolicyName(orgID, clusterID), roleName)
+}
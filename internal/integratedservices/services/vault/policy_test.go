@@ -0,0 +1,135 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderRolePolicy(t *testing.T) {
+	t.Run("policy", func(t *testing.T) {
+		role := Role{
+			Name:   "custom",
+			Policy: `path "secret/*" { capabilities = [ "read" ] }`,
+		}
+
+		policy, err := renderRolePolicy(role, 13, 42, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, role.Policy, policy)
+	})
+
+	t.Run("policy paths", func(t *testing.T) {
+		role := Role{
+			Name:       "team-a",
+			Namespaces: []string{"team-a", "team-a-staging"},
+			PolicyPaths: []PolicyPath{
+				{
+					Path:         "secret/data/orgs/{{ .OrganizationID }}/{{ .Namespace }}/*",
+					Capabilities: []string{"read", "list"},
+				},
+				{
+					Path: "secret/data/clusters/{{ .ClusterID }}/{{ .Role }}",
+				},
+			},
+		}
+
+		policy, err := renderRolePolicy(role, 13, 42, false)
+		require.NoError(t, err)
+
+		expected := `path "secret/data/orgs/13/team-a/*" {
+	capabilities = [ "read", "list" ]
+}
+path "secret/data/orgs/13/team-a-staging/*" {
+	capabilities = [ "read", "list" ]
+}
+path "secret/data/clusters/42/team-a" {
+	capabilities = [ "read" ]
+}
+`
+		assert.Equal(t, expected, policy)
+	})
+	t.Run("policy in managed Vault", func(t *testing.T) {
+		role := Role{
+			Name:   "custom",
+			Policy: `path "secret/data/orgs/13/*" { capabilities = [ "read" ] }`,
+		}
+
+		_, err := renderRolePolicy(role, 13, 42, true)
+		assert.Error(t, err)
+	})
+
+	t.Run("policy paths in managed Vault", func(t *testing.T) {
+		role := Role{
+			Name:       "team-a",
+			Namespaces: []string{"team-a"},
+			PolicyPaths: []PolicyPath{
+				{
+					Path:         "secret/data/orgs/{{ .OrganizationID }}/{{ .Namespace }}/*",
+					Capabilities: []string{"read", "list"},
+				},
+				{
+					Path: "secret/metadata/orgs/{{ .OrganizationID }}/{{ .Namespace }}/*",
+				},
+			},
+		}
+
+		policy, err := renderRolePolicy(role, 13, 42, true)
+		require.NoError(t, err)
+
+		expected := `path "secret/data/orgs/13/team-a/*" {
+	capabilities = [ "read", "list" ]
+}
+path "secret/metadata/orgs/13/team-a/*" {
+	capabilities = [ "read" ]
+}
+`
+		assert.Equal(t, expected, policy)
+	})
+
+	t.Run("policy paths escaping the organization in managed Vault", func(t *testing.T) {
+		paths := map[string]struct {
+			path       string
+			namespaces []string
+		}{
+			"all secrets":              {path: "secret/*"},
+			"all organizations":        {path: "secret/data/orgs/*"},
+			"organization id prefix":   {path: "secret/data/orgs/{{ .OrganizationID }}*"},
+			"other organization":       {path: "secret/data/orgs/14/*"},
+			"cluster secrets":          {path: "secret/data/clusters/{{ .ClusterID }}/*"},
+			"other secret engine":      {path: "sys/policy/*"},
+			"leading slash":            {path: "/secret/data/orgs/{{ .OrganizationID }}/*"},
+			"parent segment":           {path: "secret/data/orgs/{{ .OrganizationID }}/../14/*"},
+			"parent segment namespace": {path: "secret/data/orgs/{{ .OrganizationID }}/{{ .Namespace }}/*", namespaces: []string{".."}},
+		}
+
+		for name, p := range paths {
+			p := p
+			t.Run(name, func(t *testing.T) {
+				role := Role{
+					Name:        "escape",
+					Namespaces:  p.namespaces,
+					PolicyPaths: []PolicyPath{{Path: p.path}},
+				}
+
+				_, err := renderRolePolicy(role, 13, 42, true)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -16,9 +16,13 @@ package vault
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"text/template"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/banzaicloud/pipeline/internal/integratedservices"
 )
@@ -26,6 +30,7 @@ import (
 type vaultIntegratedServiceSpec struct {
 	CustomVault CustomVault `json:"customVault" mapstructure:"customVault"`
 	Settings    Settings    `json:"settings" mapstructure:"settings"`
+	Roles       []Role      `json:"roles" mapstructure:"roles"`
 }
 
 type CustomVault struct {
@@ -40,6 +45,23 @@ type Settings struct {
 	ServiceAccounts []string `json:"serviceAccounts" mapstructure:"serviceAccounts"`
 }
 
+// Role is a Kubernetes auth role with its own policy in addition to the default role described by Settings.
+type Role struct {
+	Name            string       `json:"name" mapstructure:"name"`
+	Namespaces      []string     `json:"namespaces" mapstructure:"namespaces"`
+	ServiceAccounts []string     `json:"serviceAccounts" mapstructure:"serviceAccounts"`
+	Policy          string       `json:"policy" mapstructure:"policy"`
+	PolicyPaths     []PolicyPath `json:"policyPaths" mapstructure:"policyPaths"`
+	TTL             string       `json:"ttl" mapstructure:"ttl"`
+	MaxTTL          string       `json:"maxTTL" mapstructure:"maxTTL"`
+}
+
+// PolicyPath is a path template and the capabilities granted on the rendered paths.
+type PolicyPath struct {
+	Path         string   `json:"path" mapstructure:"path"`
+	Capabilities []string `json:"capabilities" mapstructure:"capabilities"`
+}
+
 func bindIntegratedServiceSpec(spec integratedservices.IntegratedServiceSpec) (vaultIntegratedServiceSpec, error) {
 	var integratedServiceSpec vaultIntegratedServiceSpec
 	if err := mapstructure.Decode(spec, &integratedServiceSpec); err != nil {
@@ -70,9 +92,101 @@ func (s *vaultIntegratedServiceSpec) Validate() error {
 		return errors.New(`both namespaces and service accounts cannot be "*"`)
 	}
 
+	roleNames := map[string]bool{
+		pipelineRoleName: true,
+		customRoleName:   true,
+	}
+	for _, role := range s.Roles {
+		if roleNames[role.Name] {
+			return errors.New(fmt.Sprintf("role name %q is reserved or used multiple times", role.Name))
+		}
+		roleNames[role.Name] = true
+
+		if err := role.Validate(); err != nil {
+			return errors.New(fmt.Sprintf("invalid role %q: %s", role.Name, err.Error()))
+		}
+
+		// arbitrary policies could grant access to the secrets of other organizations in Pipeline's managed Vault
+		if !s.CustomVault.Enabled && role.Policy != "" {
+			return errors.New(fmt.Sprintf("invalid role %q: custom policies are only allowed in a custom Vault, use policy paths instead", role.Name))
+		}
+	}
+
 	return nil
 }
 
+func (r Role) Validate() error {
+	if errs := validation.IsDNS1123Label(r.Name); len(errs) != 0 {
+		return errors.New(errs[0])
+	}
+
+	if len(r.Namespaces) == 0 || len(r.ServiceAccounts) == 0 {
+		return errors.New("both namespaces and service accounts are required")
+	}
+
+	if isWildcard(r.Namespaces) && isWildcard(r.ServiceAccounts) {
+		return errors.New(`both namespaces and service accounts cannot be "*"`)
+	}
+
+	if (r.Policy == "") == (len(r.PolicyPaths) == 0) {
+		return errors.New("exactly one of policy and policy paths is required")
+	}
+
+	for _, path := range r.PolicyPaths {
+		if err := path.Validate(); err != nil {
+			return err
+		}
+
+		if isNamespaceDependent(path.Path) && containsWildcard(r.Namespaces) {
+			return errors.New(fmt.Sprintf(`path %q depends on the namespace, so namespaces cannot contain "*"`, path.Path))
+		}
+	}
+
+	for _, ttl := range []string{r.TTL, r.MaxTTL} {
+		if ttl == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(ttl); err != nil {
+			return errors.New(fmt.Sprintf("invalid TTL %q", ttl))
+		}
+	}
+
+	return nil
+}
+
+func (p PolicyPath) Validate() error {
+	if p.Path == "" {
+		return errors.New("policy path is required")
+	}
+
+	if _, err := template.New("").Parse(p.Path); err != nil {
+		return errors.New(fmt.Sprintf("invalid policy path template %q: %s", p.Path, err.Error()))
+	}
+
+	for _, capability := range p.Capabilities {
+		if !policyCapabilities[capability] {
+			return errors.New(fmt.Sprintf("invalid capability %q", capability))
+		}
+	}
+
+	return nil
+}
+
+func isWildcard(values []string) bool {
+	return len(values) == 1 && values[0] == "*"
+}
+
+func containsWildcard(values []string) bool {
+	for _, value := range values {
+		if value == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *vaultIntegratedServiceSpec) getVaultAddress() (vaultAddress string) {
 	if s.CustomVault.Enabled {
 		vaultAddress = s.CustomVault.Address
@@ -0,0 +1,158 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"testing"
+)
+
+func TestVaultIntegratedServiceSpec_Validate(t *testing.T) {
+	validRole := Role{
+		Name:            "team-a",
+		Namespaces:      []string{"team-a"},
+		ServiceAccounts: []string{"*"},
+		PolicyPaths: []PolicyPath{
+			{
+				Path:         "secret/data/orgs/{{ .OrganizationID }}/{{ .Namespace }}/*",
+				Capabilities: []string{"read", "list"},
+			},
+		},
+		TTL: "30m",
+	}
+
+	tests := []struct {
+		name        string
+		customVault CustomVault
+		roles       func(role Role) []Role
+		wantErr     bool
+	}{
+		{
+			name:    "valid role",
+			roles:   func(role Role) []Role { return []Role{role} },
+			wantErr: false,
+		},
+		{
+			name: "duplicate role names",
+			roles: func(role Role) []Role {
+				return []Role{role, role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "reserved role name",
+			roles: func(role Role) []Role {
+				role.Name = pipelineRoleName
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid role name",
+			roles: func(role Role) []Role {
+				role.Name = "Team_A"
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing namespaces",
+			roles: func(role Role) []Role {
+				role.Namespaces = nil
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "both policy and policy paths",
+			roles: func(role Role) []Role {
+				role.Policy = `path "secret/*" { capabilities = [ "read" ] }`
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "neither policy nor policy paths",
+			roles: func(role Role) []Role {
+				role.PolicyPaths = nil
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "namespace dependent path with wildcard namespace",
+			roles: func(role Role) []Role {
+				role.Namespaces = []string{"*"}
+				role.ServiceAccounts = []string{"default"}
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid capability",
+			roles: func(role Role) []Role {
+				role.PolicyPaths = []PolicyPath{{Path: "secret/*", Capabilities: []string{"write"}}}
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid path template",
+			roles: func(role Role) []Role {
+				role.PolicyPaths = []PolicyPath{{Path: "secret/{{ .Namespace"}}
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom policy in managed Vault",
+			roles: func(role Role) []Role {
+				role.Policy = `path "secret/*" { capabilities = [ "read" ] }`
+				role.PolicyPaths = nil
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+		{
+			name:        "custom policy in custom Vault",
+			customVault: CustomVault{Enabled: true, Address: "http://localhost:8200/"},
+			roles: func(role Role) []Role {
+				role.Policy = `path "secret/*" { capabilities = [ "read" ] }`
+				role.PolicyPaths = nil
+				return []Role{role}
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid TTL",
+			roles: func(role Role) []Role {
+				role.MaxTTL = "one day"
+				return []Role{role}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := vaultIntegratedServiceSpec{
+				CustomVault: tt.customVault,
+				Roles:       tt.roles(validRole),
+			}
+			if err := s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
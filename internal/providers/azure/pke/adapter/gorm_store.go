@@ -22,6 +22,7 @@ import (
 	"emperror.dev/errors"
 	"github.com/jinzhu/gorm"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/common"
 	"github.com/banzaicloud/pipeline/internal/database/sql/json"
@@ -83,7 +84,8 @@ type nodePoolModel struct {
 	Name         string     `gorm:"unique_index:idx_azure_pke_np_cluster_id_name"`
 	Roles        rolesModel `gorm:"type:json"`
 	SubnetName   string
-	Zones        zonesModel `gorm:"type:json"`
+	Taints       cluster.Taints `gorm:"type:text"`
+	Zones        zonesModel     `gorm:"type:json"`
 }
 
 func (nodePoolModel) TableName() string {
@@ -298,6 +300,7 @@ func (m *nodePoolModel) fromEntity(e pke.NodePool) {
 	m.Name = e.Name
 	m.Roles = rolesModel(e.Roles)
 	m.SubnetName = e.Subnet.Name
+	m.Taints = cluster.Taints(e.Taints)
 	m.Zones = zonesModel(e.Zones)
 }
 
@@ -311,6 +314,7 @@ func (m nodePoolModel) intoEntity(e *pke.NodePool) {
 	e.Name = m.Name
 	e.Roles = []string(m.Roles)
 	e.Subnet.Name = m.SubnetName
+	e.Taints = []cluster.Taint(m.Taints)
 	e.Zones = []string(m.Zones)
 }
 
@@ -352,18 +356,20 @@ func (s ClusterStore) Create(params pke.CreateParams) (pke.Cluster, error) {
 
 	model := clusterModel{
 		Cluster: clustermodel.ClusterModel{
-			CreatedBy:      params.CreatedBy,
-			Name:           params.Name,
-			Location:       params.Location,
-			Cloud:          pkgCluster.Azure,
-			Distribution:   pkgCluster.PKE,
-			OrganizationID: params.OrganizationID,
-			SecretID:       params.SecretID,
-			SSHSecretID:    params.SSHSecretID,
-			Status:         pkgCluster.Creating,
-			StatusMessage:  pkgCluster.CreatingMessage,
-			RbacEnabled:    params.RBAC,
-			OidcEnabled:    params.OIDC,
+			CreatedBy:          params.CreatedBy,
+			Name:               params.Name,
+			Location:           params.Location,
+			Cloud:              pkgCluster.Azure,
+			Distribution:       pkgCluster.PKE,
+			OrganizationID:     params.OrganizationID,
+			SecretID:           params.SecretID,
+			SSHSecretID:        params.SSHSecretID,
+			Status:             pkgCluster.Creating,
+			StatusMessage:      pkgCluster.CreatingMessage,
+			RbacEnabled:        params.RBAC,
+			OidcEnabled:        params.OIDC,
+			DeletionProtection: params.DeletionProtection,
+			Labels:             clustermodel.NewLabelModels(params.Labels),
 			ScaleOptions: clustermodel.ScaleOptions{
 				Enabled:             params.ScaleOptions.Enabled,
 				DesiredCpu:          params.ScaleOptions.DesiredCpu,
@@ -15,6 +15,7 @@
 package pke
 
 import (
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusterbase"
 	intPKE "github.com/banzaicloud/pipeline/internal/pke"
 )
@@ -44,6 +45,7 @@ type NodePool struct {
 	Name         string
 	Roles        []string
 	Subnet       Subnetwork
+	Taints       []cluster.Taint
 	Zones        []string
 }
 
@@ -29,6 +29,7 @@ import (
 	"go.uber.org/cadence/client"
 	corev1 "k8s.io/api/core/v1"
 
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intPKE "github.com/banzaicloud/pipeline/internal/pke"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke/driver/commoncluster"
@@ -103,6 +104,7 @@ type NodePool struct {
 	Zones        []string
 	Roles        []string
 	Labels       map[string]string
+	Taints       []intCluster.Taint
 	Autoscaling  bool
 	Count        int
 	Min          int
@@ -128,6 +130,7 @@ func (np NodePool) toPke() (pnp pke.NodePool) {
 	pnp.Name = np.Name
 	pnp.Roles = np.Roles
 	pnp.Subnet = pke.Subnetwork{Name: np.Subnet.Name}
+	pnp.Taints = np.Taints
 	pnp.Zones = np.Zones
 	return
 }
@@ -152,6 +155,8 @@ type ClusterCreationParams struct {
 	HTTPProxy             intPKE.HTTPProxy
 	AccessPoints          pke.AccessPoints
 	APIServerAccessPoints pke.APIServerAccessPoints
+	Labels                map[string]string
+	DeletionProtection    bool
 }
 
 // Create
@@ -203,7 +208,8 @@ func (cc ClusterCreator) Create(ctx context.Context, params ClusterCreationParam
 			Subnet: pke.Subnetwork{
 				Name: np.Subnet.Name,
 			},
-			Zones: np.Zones,
+			Taints: np.Taints,
+			Zones:  np.Zones,
 		}
 	}
 	createParams := pke.CreateParams{
@@ -223,6 +229,8 @@ func (cc ClusterCreator) Create(ctx context.Context, params ClusterCreationParam
 		HTTPProxy:             params.HTTPProxy,
 		AccessPoints:          params.AccessPoints,
 		APIServerAccessPoints: params.APIServerAccessPoints,
+		Labels:                params.Labels,
+		DeletionProtection:    params.DeletionProtection,
 	}
 	cl, err = cc.store.Create(createParams)
 	if err != nil {
@@ -105,6 +105,14 @@ func (f nodePoolTemplateFactory) getTemplates(np NodePool) (workflow.VirtualMach
 		}
 	}
 
+	for _, taint := range np.Taints {
+		if taints == "" || taints == "," {
+			taints = taint.String()
+		} else {
+			taints += "," + taint.String()
+		}
+	}
+
 	vmssName := pke.GetVMSSName(f.ClusterName, np.Name)
 
 	cnsgn := nsgn
@@ -25,6 +25,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/sirupsen/logrus"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke"
 	"github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/pkg/providers/azure"
@@ -189,6 +190,11 @@ func (p NodePoolPreparer) Prepare(ctx context.Context, nodePool *NodePool) error
 		return validationErrorf(err.Error())
 	}
 
+	err = cluster.ValidateNodePoolTaints(nodePool.Name, nodePool.Taints)
+	if err != nil {
+		return validationErrorf(err.Error())
+	}
+
 	np, err := p.dataProvider.getExistingNodePoolByName(ctx, nodePool.Name)
 	if pke.IsNotFound(err) {
 		return p.prepareNewNodePool(ctx, nodePool)
@@ -300,6 +306,12 @@ func (p NodePoolPreparer) prepareExistingNodePool(ctx context.Context, nodePool
 		}
 		nodePool.Zones = existing.Zones
 	}
+	if !taintSliceEqual(nodePool.Taints, existing.Taints) {
+		if nodePool.Taints != nil {
+			logMismatchOn(p, "Taints", existing.Taints, nodePool.Taints)
+		}
+		nodePool.Taints = existing.Taints
+	}
 
 	return nil
 }
@@ -418,3 +430,15 @@ func (e validationError) Error() string {
 func (e validationError) InputValidationError() bool {
 	return true
 }
+
+func taintSliceEqual(lhs, rhs []cluster.Taint) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	for i := range lhs {
+		if lhs[i] != rhs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -38,6 +38,8 @@ type CreateParams struct {
 	HTTPProxy             intPKE.HTTPProxy
 	AccessPoints          AccessPoints
 	APIServerAccessPoints APIServerAccessPoints
+	Labels                map[string]string
+	DeletionProtection    bool
 }
 
 // ClusterStore defines behaviors of Cluster persistent storage
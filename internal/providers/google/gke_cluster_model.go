@@ -20,6 +20,7 @@ import (
 
 	"github.com/jinzhu/gorm"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 )
 
@@ -112,6 +113,7 @@ type GKENodePoolModel struct {
 	NodeCount        int
 	NodeInstanceType string
 	Labels           map[string]string `gorm:"-"`
+	Taints           cluster.Taints    `gorm:"type:text"`
 	Delete           bool              `gorm:"-"`
 }
 
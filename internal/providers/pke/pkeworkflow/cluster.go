@@ -18,6 +18,8 @@ import (
 	"context"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
 )
 
 type Clusters interface {
@@ -58,4 +60,5 @@ type NodePool struct {
 	ImageID           string
 	SpotPrice         string
 	Subnets           []string
+	Taints            []cluster.Taint // nil leaves the taints of an existing node pool intact
 }
@@ -20,6 +20,9 @@ import (
 
 	"emperror.dev/errors"
 	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterworkflow"
 )
 
 const UpdateClusterWorkflowName = "pke-update-cluster"
@@ -185,6 +188,38 @@ func UpdateClusterWorkflow(ctx workflow.Context, input UpdateClusterWorkflowInpu
 		}
 	}
 
+	// reconcile the taints of the existing nodes, new nodes register with them on bootstrap
+	{
+		futures := make([]workflow.Future, 0, len(input.NodePoolsToUpdate))
+		names := make([]string, 0, len(input.NodePoolsToUpdate))
+
+		for _, np := range input.NodePoolsToUpdate {
+			if np.Taints == nil {
+				continue
+			}
+
+			activityInput := clusterworkflow.CreateNodePoolLabelSetActivityInput{
+				ClusterID: input.ClusterID,
+				RawNodePool: cluster.NewRawNodePool{
+					"name":   np.Name,
+					"taints": np.Taints,
+				},
+			}
+
+			futures = append(futures, workflow.ExecuteActivity(ctx, clusterworkflow.CreateNodePoolLabelSetActivityName, activityInput))
+			names = append(names, np.Name)
+		}
+
+		errs := make([]error, len(futures))
+		for i, future := range futures {
+			errs[i] = errors.Wrapf(future.Get(ctx, nil), "couldn't update taints of node pool %q", names[i])
+		}
+
+		if err := errors.Combine(errs...); err != nil {
+			return err
+		}
+	}
+
 	{
 		futures := make([]workflow.Future, len(input.NodePoolsToAdd))
 
@@ -16,6 +16,10 @@ package pkeworkflow
 
 import (
 	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
 )
 
 const UpdateClusterStatusActivityName = "pke-update-cluster-status-activity"
@@ -42,5 +46,13 @@ func (a *UpdateClusterStatusActivity) Execute(ctx context.Context, input UpdateC
 		return err
 	}
 
+	if c, ok := c.(interface {
+		SetStatusWithContext(ctx context.Context, status, statusMessage string) error
+	}); ok {
+		ctx = ctxutil.WithProcessID(ctx, activity.GetInfo(ctx).WorkflowExecution.ID)
+
+		return c.SetStatusWithContext(ctx, input.Status, input.StatusMessage)
+	}
+
 	return c.SetStatus(input.Status, input.StatusMessage)
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
 
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	sqlJson "github.com/banzaicloud/pipeline/internal/database/sql/json"
 	intPKE "github.com/banzaicloud/pipeline/internal/pke"
@@ -68,7 +69,8 @@ type nodePoolModel struct {
 	VCPU          int `gorm:"column:vcpu"`
 	RAM           int
 	Name          string     `gorm:"unique_index:idx_vsphere_pke_np_cluster_id_name"`
-	Roles         rolesModel `gorm:"type:json"`
+	Roles         rolesModel        `gorm:"type:json"`
+	Taints        intCluster.Taints `gorm:"type:text"`
 	AdminUsername string
 	TemplateName  string
 }
@@ -173,6 +175,7 @@ func fillNodePoolFromModel(nodePool *pke.NodePool, model nodePoolModel) {
 	nodePool.RAM = model.RAM
 	nodePool.Name = model.Name
 	nodePool.Roles = model.Roles
+	nodePool.Taints = []intCluster.Taint(model.Taints)
 	nodePool.TemplateName = model.TemplateName
 	nodePool.AdminUsername = model.AdminUsername
 }
@@ -184,6 +187,7 @@ func fillModelFromNodePool(model *nodePoolModel, nodePool pke.NodePool) {
 	model.RAM = nodePool.RAM
 	model.Name = nodePool.Name
 	model.Roles = nodePool.Roles
+	model.Taints = intCluster.Taints(nodePool.Taints)
 	model.TemplateName = nodePool.TemplateName
 	model.AdminUsername = nodePool.AdminUsername
 }
@@ -203,17 +207,19 @@ func (s gormVspherePKEClusterStore) Create(params pke.CreateParams) (c pke.PKEOn
 
 	model := vspherePkeCluster{
 		Cluster: clustermodel.ClusterModel{
-			CreatedBy:      params.CreatedBy,
-			Name:           params.Name,
-			Cloud:          pkgCluster.Vsphere,
-			Distribution:   pkgCluster.PKE,
-			OrganizationID: params.OrganizationID,
-			SecretID:       params.SecretID,
-			SSHSecretID:    params.SSHSecretID,
-			Status:         pkgCluster.Creating,
-			StatusMessage:  pkgCluster.CreatingMessage,
-			RbacEnabled:    params.RBAC,
-			OidcEnabled:    params.OIDC,
+			CreatedBy:          params.CreatedBy,
+			Name:               params.Name,
+			Cloud:              pkgCluster.Vsphere,
+			Distribution:       pkgCluster.PKE,
+			OrganizationID:     params.OrganizationID,
+			SecretID:           params.SecretID,
+			SSHSecretID:        params.SSHSecretID,
+			Status:             pkgCluster.Creating,
+			StatusMessage:      pkgCluster.CreatingMessage,
+			RbacEnabled:        params.RBAC,
+			OidcEnabled:        params.OIDC,
+			DeletionProtection: params.DeletionProtection,
+			Labels:             clustermodel.NewLabelModels(params.Labels),
 			ScaleOptions: clustermodel.ScaleOptions{
 				Enabled:             params.ScaleOptions.Enabled,
 				DesiredCpu:          params.ScaleOptions.DesiredCpu,
@@ -17,6 +17,7 @@ package pke
 import (
 	"fmt"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusterbase"
 	intPKE "github.com/banzaicloud/pipeline/internal/pke"
 	pkgPKE "github.com/banzaicloud/pipeline/pkg/cluster/pke"
@@ -33,6 +34,7 @@ type NodePool struct {
 	Roles         []string
 	AdminUsername string
 	TemplateName  string
+	Taints        []cluster.Taint
 }
 
 func (np NodePool) InstanceType() string {
@@ -24,6 +24,7 @@ import (
 	"go.uber.org/cadence/client"
 	corev1 "k8s.io/api/core/v1"
 
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intPKE "github.com/banzaicloud/pipeline/internal/pke"
 	"github.com/banzaicloud/pipeline/internal/providers/vsphere/pke"
 	vspherePKE "github.com/banzaicloud/pipeline/internal/providers/vsphere/pke"
@@ -92,6 +93,7 @@ type NodePool struct {
 	Name          string
 	Roles         []string
 	Labels        map[string]string
+	Taints        []intCluster.Taint
 	Size          int
 	AdminUsername string
 	VCPU          int
@@ -145,6 +147,8 @@ type VspherePKEClusterCreationParams struct {
 	Kubernetes          intPKE.Kubernetes
 	ActiveWorkflowID    string
 	LoadBalancerIPRange string
+	Labels              map[string]string
+	DeletionProtection  bool
 }
 
 // Create
@@ -173,6 +177,7 @@ func (cc VspherePKEClusterCreator) Create(ctx context.Context, params VspherePKE
 			VCPU:         np.VCPU,
 			RAM:          np.RAM,
 			TemplateName: np.TemplateName,
+			Taints:       np.Taints,
 		}
 		if nodePools[i].TemplateName == "" {
 			nodePools[i].TemplateName = defaultNodeTemplate
@@ -195,6 +200,8 @@ func (cc VspherePKEClusterCreator) Create(ctx context.Context, params VspherePKE
 		DatastoreName:       params.DatastoreName,
 		Kubernetes:          params.Kubernetes,
 		LoadBalancerIPRange: params.LoadBalancerIPRange,
+		Labels:              params.Labels,
+		DeletionProtection:  params.DeletionProtection,
 	}
 	cl, err = cc.store.Create(createParams)
 	if err != nil {
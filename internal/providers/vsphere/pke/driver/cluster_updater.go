@@ -137,6 +137,7 @@ func (cu ClusterUpdater) Update(ctx context.Context, params VspherePKEClusterUpd
 				VCPU:         np.VCPU,
 				RAM:          np.RAM,
 				TemplateName: np.TemplateName,
+				Taints:       np.Taints,
 			}
 			if nodePool.TemplateName == "" {
 				nodePool.TemplateName = defaultNodeTemplate
@@ -192,6 +193,7 @@ func (cu ClusterUpdater) Update(ctx context.Context, params VspherePKEClusterUpd
 						VCPU:         np.VCPU,
 						RAM:          np.RAM,
 						TemplateName: templateName,
+						Taints:       np.Taints,
 					}, i))
 				}
 
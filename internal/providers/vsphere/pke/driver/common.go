@@ -90,6 +90,14 @@ func (f nodeTemplateFactory) getNode(np pke.NodePool, number int) workflow.Node
 		}
 	}
 
+	for _, taint := range np.Taints {
+		if taints == "" || taints == "," {
+			taints = taint.String()
+		} else {
+			taints += "," + taint.String()
+		}
+	}
+
 	// HttpProxy settings will be set in workflow
 	node.UserDataScriptParams = map[string]string{
 		"ClusterID":            strconv.FormatUint(uint64(f.ClusterID), 10),
@@ -20,6 +20,7 @@ import (
 
 	"emperror.dev/errors"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/providers/vsphere/pke"
 	pkgPKE "github.com/banzaicloud/pipeline/pkg/cluster/pke"
 )
@@ -87,6 +88,10 @@ func (p NodePoolPreparer) Prepare(ctx context.Context, nodePool *NodePool) error
 		return validationErrorf("%s.Name must be specified", p.namespace)
 	}
 
+	if err := cluster.ValidateNodePoolTaints(nodePool.Name, nodePool.Taints); err != nil {
+		return validationErrorf(err.Error())
+	}
+
 	if nodePool.hasRole(pkgPKE.RoleMaster) && nodePool.Size == 0 {
 		p.logger.Debug("Master node pool size should be >= 0, defaulting to 1")
 		nodePool.Size = 1
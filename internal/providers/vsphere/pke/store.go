@@ -40,6 +40,8 @@ type CreateParams struct {
 	DatastoreName       string
 	Kubernetes          intPKE.Kubernetes
 	LoadBalancerIPRange string
+	Labels              map[string]string
+	DeletionProtection  bool
 }
 
 // ClusterStore defines behaviors of PKEOnVsphereCluster persistent storage
@@ -37,9 +37,14 @@ type UserManagementClient interface {
 	GetUserCredentials(ctx context.Context, userName string) (string, error)
 }
 
+type ImageClient interface {
+	GetImageVulnerabilities(ctx context.Context, imageDigest string) ([]anchore.Vulnerability, error)
+}
+
 // AnchoreClient "facade" for supported Anchore operations, decouples anchore specifics from the application
 type AnchoreClient interface {
 	UserManagementClient
+	ImageClient
 }
 
 type anchoreClient struct {
@@ -200,6 +205,20 @@ func (a anchoreClient) DeleteUser(ctx context.Context, accountName string, userN
 	return nil
 }
 
+func (a anchoreClient) GetImageVulnerabilities(ctx context.Context, imageDigest string) ([]anchore.Vulnerability, error) {
+	fnCtx := map[string]interface{}{"imageDigest": imageDigest}
+	a.logger.Info("retrieving image vulnerabilities", fnCtx)
+
+	vulnerabilities, resp, err := a.getRestClient().ImagesApi.GetImageVulnerabilitiesByType(a.authorizedContext(ctx), imageDigest, "all", nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		a.logger.Debug("failed to retrieve image vulnerabilities", fnCtx)
+
+		return nil, errors.WrapIfWithDetails(err, "failed to retrieve image vulnerabilities", fnCtx)
+	}
+
+	return vulnerabilities.Vulnerabilities, nil
+}
+
 func (a anchoreClient) authorizedContext(ctx context.Context) context.Context {
 	basicAuth := anchore.BasicAuth{
 		UserName: a.userName,
@@ -0,0 +1,81 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchore
+
+import (
+	"context"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/anchore"
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+// anchoreImageScanner retrieves image vulnerabilities from the Anchore instance configured for the cluster.
+type anchoreImageScanner struct {
+	configProvider anchore.ConfigProvider
+	logger         common.Logger
+}
+
+// NewAnchoreImageScanner returns an ImageScanner backed by Anchore.
+func NewAnchoreImageScanner(configProvider anchore.ConfigProvider, logger common.Logger) ImageScanner {
+	return anchoreImageScanner{
+		configProvider: configProvider,
+		logger:         logger,
+	}
+}
+
+func (s anchoreImageScanner) GetImageVulnerabilities(ctx context.Context, cluster Cluster, image security.Image) (security.ImageVulnerabilities, error) {
+	if image.Digest == "" {
+		return security.ImageVulnerabilities{}, errors.NewWithDetails("image digest is required", "image", image.Name)
+	}
+
+	config, err := s.configProvider.GetConfiguration(ctx, cluster.GetID())
+	if err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIf(err, "failed to get anchore configuration")
+	}
+
+	client := NewAnchoreClient(config.User, config.Password, config.Endpoint, config.Insecure, s.logger)
+
+	anchoreVulnerabilities, err := client.GetImageVulnerabilities(ctx, image.Digest)
+	if err != nil {
+		return security.ImageVulnerabilities{}, err
+	}
+
+	vulnerabilities := make([]security.Vulnerability, 0, len(anchoreVulnerabilities))
+	for _, vulnerability := range anchoreVulnerabilities {
+		// anchore reports missing fixes as "None"
+		if vulnerability.Fix == "None" {
+			vulnerability.Fix = ""
+		}
+
+		vulnerabilities = append(vulnerabilities, security.Vulnerability{
+			ID:             vulnerability.Vuln,
+			Severity:       strings.ToUpper(vulnerability.Severity),
+			Package:        vulnerability.PackageName,
+			PackageVersion: vulnerability.PackageVersion,
+			FixedVersion:   vulnerability.Fix,
+			URL:            vulnerability.Url,
+		})
+	}
+
+	return security.ImageVulnerabilities{
+		Scanner:         ScannerAnchore,
+		Image:           image,
+		Vulnerabilities: vulnerabilities,
+	}, nil
+}
@@ -44,10 +44,71 @@ type WhitelistService interface {
 }
 
 type ScanlogService interface {
-	ListScanLogs(ctx context.Context, cluster Cluster) (interface{}, error)
+	ListScanLogs(ctx context.Context, cluster Cluster, filter ScanLogFilter) (interface{}, error)
 	GetScanLogs(ctx context.Context, cluster Cluster, releaseName string) (interface{}, error)
 }
 
+// ScanLogItem is an admission decision recorded by the image validator webhook
+type ScanLogItem struct {
+	securityV1Alpha.AuditSpec
+
+	// Mode is the admission mode the decision was made in
+	Mode string `json:"mode"`
+
+	// BreakGlass is true if the workload was admitted using the break-glass annotation
+	BreakGlass bool `json:"breakGlass"`
+
+	// WouldBlock is true if the workload failed the policy check
+	WouldBlock bool `json:"wouldBlock"`
+
+	// Blocked is true if the workload was actually rejected
+	Blocked bool `json:"blocked"`
+}
+
+// ScanLogFilter filters the listed scan logs
+type ScanLogFilter struct {
+	Mode       string
+	WouldBlock *bool
+}
+
+func (f ScanLogFilter) matches(item ScanLogItem) bool {
+	if f.Mode != "" && f.Mode != item.Mode {
+		return false
+	}
+
+	if f.WouldBlock != nil && *f.WouldBlock != item.WouldBlock {
+		return false
+	}
+
+	return true
+}
+
+// NewScanLogItem assembles a scan log item from an audit record.
+// Decisions recorded without a mode were made in enforce mode.
+func NewScanLogItem(audit securityV1Alpha.Audit) ScanLogItem {
+	mode := audit.Labels[security.AdmissionModeLabel]
+	if mode == "" {
+		mode = security.AdmissionModeEnforce
+	}
+
+	breakGlass := audit.Annotations[security.BreakGlassAnnotation] == "true"
+	wouldBlock := audit.Spec.Action == "reject"
+
+	return ScanLogItem{
+		AuditSpec: securityV1Alpha.AuditSpec{
+			ReleaseName: audit.Spec.ReleaseName,
+			Resource:    audit.Spec.Resource,
+			Action:      audit.Spec.Action,
+			Images:      audit.Spec.Images,
+			Result:      audit.Spec.Result,
+		},
+		Mode:       mode,
+		BreakGlass: breakGlass,
+		WouldBlock: wouldBlock,
+		Blocked:    wouldBlock && mode == security.AdmissionModeEnforce && !breakGlass,
+	}
+}
+
 type securityResourceService struct {
 	logger common.Logger
 }
@@ -98,7 +159,7 @@ func (s securityResourceService) CreateWhitelist(ctx context.Context, cluster Cl
 	return wlItem, nil
 }
 
-func (s securityResourceService) ListScanLogs(ctx context.Context, cluster Cluster) (interface{}, error) {
+func (s securityResourceService) ListScanLogs(ctx context.Context, cluster Cluster, filter ScanLogFilter) (interface{}, error) {
 	logCtx := map[string]interface{}{"clusterID": cluster.GetID()}
 	s.logger.Info("listing scan logs ...", logCtx)
 
@@ -113,15 +174,13 @@ func (s securityResourceService) ListScanLogs(ctx context.Context, cluster Clust
 		return nil, errors.WrapIf(err, "failed to list scan logs")
 	}
 
-	scanLogList := make([]securityV1Alpha.AuditSpec, 0)
+	scanLogList := make([]ScanLogItem, 0)
 	for _, audit := range audits.Items {
-		scanLog := securityV1Alpha.AuditSpec{
-			ReleaseName: audit.Spec.ReleaseName,
-			Resource:    audit.Spec.Resource,
-			Action:      audit.Spec.Action,
-			Images:      audit.Spec.Images,
-			Result:      audit.Spec.Result,
+		scanLog := NewScanLogItem(audit)
+		if !filter.matches(scanLog) {
+			continue
 		}
+
 		scanLogList = append(scanLogList, scanLog)
 	}
 
@@ -146,13 +205,9 @@ func (s securityResourceService) GetScanLogs(ctx context.Context, cluster Cluste
 		return nil, errors.WrapIf(err, "failed to get audit")
 	}
 
-	return &securityV1Alpha.AuditSpec{
-		ReleaseName: audit.Spec.ReleaseName,
-		Resource:    audit.Spec.Resource,
-		Action:      audit.Spec.Action,
-		Images:      audit.Spec.Images,
-		Result:      audit.Spec.Result,
-	}, nil
+	scanLog := NewScanLogItem(*audit)
+
+	return &scanLog, nil
 }
 
 func (s securityResourceService) DeleteWhitelist(ctx context.Context, cluster Cluster, whitelistItemName string) error {
@@ -0,0 +1,90 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchore
+
+import (
+	"testing"
+
+	securityV1Alpha "github.com/banzaicloud/anchore-image-validator/pkg/apis/security/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewScanLogItem(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		annotation string
+		action     string
+		mode       string
+		wouldBlock bool
+		blocked    bool
+	}{
+		{
+			name:       "rejected without mode",
+			action:     "reject",
+			mode:       "enforce",
+			wouldBlock: true,
+			blocked:    true,
+		},
+		{
+			name:       "allowed in enforce mode",
+			labels:     map[string]string{"scan-mode": "enforce"},
+			action:     "allow",
+			mode:       "enforce",
+			wouldBlock: false,
+			blocked:    false,
+		},
+		{
+			name:       "failed in audit mode",
+			labels:     map[string]string{"scan-mode": "audit"},
+			action:     "reject",
+			mode:       "audit",
+			wouldBlock: true,
+			blocked:    false,
+		},
+		{
+			name:       "break-glass in enforce mode",
+			labels:     map[string]string{"scan-mode": "enforce"},
+			annotation: "true",
+			action:     "reject",
+			mode:       "enforce",
+			wouldBlock: true,
+			blocked:    false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			audit := securityV1Alpha.Audit{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      tt.labels,
+					Annotations: map[string]string{"security.banzaicloud.io/break-glass": tt.annotation},
+				},
+				Spec: securityV1Alpha.AuditSpec{
+					ReleaseName: "release",
+					Action:      tt.action,
+				},
+			}
+
+			item := NewScanLogItem(audit)
+
+			assert.Equal(t, tt.mode, item.Mode)
+			assert.Equal(t, tt.wouldBlock, item.WouldBlock)
+			assert.Equal(t, tt.blocked, item.Blocked)
+			assert.Equal(t, "release", item.ReleaseName)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchore
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+// Supported scanner backends.
+const (
+	ScannerAnchore = "anchore"
+	ScannerTrivy   = "trivy"
+)
+
+// ImageScanner retrieves vulnerability information of container images from a scanner backend.
+type ImageScanner interface {
+	// GetImageVulnerabilities returns the vulnerabilities found in the image.
+	GetImageVulnerabilities(ctx context.Context, cluster Cluster, image security.Image) (security.ImageVulnerabilities, error)
+}
+
+// ScannerProvider returns the name of the scanner backend configured for a cluster.
+type ScannerProvider interface {
+	// GetScanner returns the name of the scanner backend configured for a cluster.
+	GetScanner(ctx context.Context, clusterID uint) (string, error)
+}
+
+// ImageScannerSelector delegates image scanning to the backend configured for the cluster.
+type ImageScannerSelector struct {
+	scannerProvider ScannerProvider
+	scanners        map[string]ImageScanner
+}
+
+// NewImageScannerSelector returns a new ImageScannerSelector.
+func NewImageScannerSelector(scannerProvider ScannerProvider, scanners map[string]ImageScanner) ImageScannerSelector {
+	return ImageScannerSelector{
+		scannerProvider: scannerProvider,
+		scanners:        scanners,
+	}
+}
+
+func (s ImageScannerSelector) GetImageVulnerabilities(ctx context.Context, cluster Cluster, image security.Image) (security.ImageVulnerabilities, error) {
+	scannerName, err := s.scannerProvider.GetScanner(ctx, cluster.GetID())
+	if err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIf(err, "failed to get scanner backend for cluster")
+	}
+
+	scanner, ok := s.scanners[scannerName]
+	if !ok {
+		return security.ImageVulnerabilities{}, errors.NewWithDetails("scanner backend is not available", "scanner", scannerName)
+	}
+
+	return scanner.GetImageVulnerabilities(ctx, cluster, image)
+}
@@ -0,0 +1,125 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package securityadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+const trivyImageScanTableName = "security_trivy_image_scans"
+
+type trivyImageScanModel struct {
+	ID              uint   `gorm:"primary_key"`
+	ClusterID       uint   `gorm:"unique_index:idx_security_trivy_image_scans_cluster_id_digest"`
+	ImageDigest     string `gorm:"unique_index:idx_security_trivy_image_scans_cluster_id_digest"`
+	Image           string
+	Tag             string
+	Vulnerabilities string `gorm:"type:json"`
+	ScannedAt       time.Time
+}
+
+func (trivyImageScanModel) TableName() string {
+	return trivyImageScanTableName
+}
+
+// Migrate executes the table migrations for the security scan results.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	tables := []interface{}{
+		&trivyImageScanModel{},
+	}
+
+	var tableNames string
+	for _, table := range tables {
+		tableNames += fmt.Sprintf(" %s", db.NewScope(table).TableName())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.TrimSpace(tableNames),
+	}).Info("migrating security scan tables")
+
+	return db.AutoMigrate(tables...).Error
+}
+
+// TrivyScanStore implements the anchore.TrivyScanStore interface using gorm.
+type TrivyScanStore struct {
+	db *gorm.DB
+}
+
+// NewTrivyScanStore returns a new TrivyScanStore.
+func NewTrivyScanStore(db *gorm.DB) TrivyScanStore {
+	return TrivyScanStore{
+		db: db,
+	}
+}
+
+// GetImageScan implements the anchore.TrivyScanStore interface.
+func (s TrivyScanStore) GetImageScan(ctx context.Context, clusterID uint, imageDigest string) (security.ImageVulnerabilities, error) {
+	var model trivyImageScanModel
+
+	err := s.db.Where(trivyImageScanModel{ClusterID: clusterID, ImageDigest: imageDigest}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return security.ImageVulnerabilities{}, errors.WithStack(anchore.ImageScanNotFoundError{ClusterID: clusterID, ImageDigest: imageDigest})
+	} else if err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIfWithDetails(err, "failed to get image scan", "clusterId", clusterID, "imageDigest", imageDigest)
+	}
+
+	var vulnerabilities []security.Vulnerability
+	if err := json.Unmarshal([]byte(model.Vulnerabilities), &vulnerabilities); err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIfWithDetails(err, "failed to unmarshal vulnerabilities", "clusterId", clusterID, "imageDigest", imageDigest)
+	}
+
+	return security.ImageVulnerabilities{
+		Scanner: anchore.ScannerTrivy,
+		Image: security.Image{
+			Name:   model.Image,
+			Tag:    model.Tag,
+			Digest: model.ImageDigest,
+		},
+		Vulnerabilities: vulnerabilities,
+	}, nil
+}
+
+// SaveImageScan implements the anchore.TrivyScanStore interface.
+func (s TrivyScanStore) SaveImageScan(ctx context.Context, clusterID uint, result security.ImageVulnerabilities) error {
+	vulnerabilities, err := json.Marshal(result.Vulnerabilities)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to marshal vulnerabilities", "clusterId", clusterID, "imageDigest", result.Image.Digest)
+	}
+
+	var model trivyImageScanModel
+
+	err = s.db.
+		Where(trivyImageScanModel{ClusterID: clusterID, ImageDigest: result.Image.Digest}).
+		Assign(map[string]interface{}{
+			"image":           result.Image.Name,
+			"tag":             result.Image.Tag,
+			"vulnerabilities": string(vulnerabilities),
+			"scanned_at":      time.Now(),
+		}).
+		FirstOrCreate(&model).
+		Error
+
+	return errors.WrapIfWithDetails(err, "failed to save image scan", "clusterId", clusterID, "imageDigest", result.Image.Digest)
+}
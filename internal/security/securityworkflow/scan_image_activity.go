@@ -0,0 +1,62 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package securityworkflow
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/pkg/security"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+const ScanImageActivityName = "security-image-scan-run"
+
+type ScanImageActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	Image          security.Image
+}
+
+// ScanImageActivity runs an image scan and caches its results.
+type ScanImageActivity struct {
+	clusterGetter integratedserviceadapter.ClusterGetter
+	scanRunner    anchore.ImageScanner
+}
+
+// NewScanImageActivity returns a new ScanImageActivity.
+func NewScanImageActivity(clusterGetter integratedserviceadapter.ClusterGetter, scanRunner anchore.ImageScanner) ScanImageActivity {
+	return ScanImageActivity{
+		clusterGetter: clusterGetter,
+		scanRunner:    scanRunner,
+	}
+}
+
+func (a ScanImageActivity) Execute(ctx context.Context, input ScanImageActivityInput) error {
+	// secrets of the clusters are stored per organization
+	ctx = auth.SetCurrentOrganizationID(ctx, input.OrganizationID)
+
+	cluster, err := a.clusterGetter.GetClusterByIDOnly(ctx, input.ClusterID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	// the runner caches the results
+	_, err = a.scanRunner.GetImageVulnerabilities(ctx, cluster, input.Image)
+
+	return err
+}
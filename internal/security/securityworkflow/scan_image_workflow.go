@@ -0,0 +1,60 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package securityworkflow
+
+import (
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+// ScanImageWorkflowName is the name of the workflow scanning an image on demand.
+const ScanImageWorkflowName = "security-image-scan"
+
+// ScanImageWorkflowInput defines the inputs of the image scan workflow.
+type ScanImageWorkflowInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	Image          security.Image
+
+	// ScanTimeout is the maximum duration of the scan.
+	ScanTimeout time.Duration
+}
+
+// ScanImageWorkflow scans an image with the scanner backend of the cluster and caches the results.
+func ScanImageWorkflow(ctx workflow.Context, input ScanImageWorkflowInput) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		// leave some time for starting the scan job and reading its results
+		StartToCloseTimeout: input.ScanTimeout + 5*time.Minute,
+		WaitForCancellation: true,
+		RetryPolicy: &cadence.RetryPolicy{
+			InitialInterval:    15 * time.Second,
+			BackoffCoefficient: 1.5,
+			MaximumInterval:    3 * time.Minute,
+			MaximumAttempts:    3,
+		},
+	})
+
+	activityInput := ScanImageActivityInput{
+		OrganizationID: input.OrganizationID,
+		ClusterID:      input.ClusterID,
+		Image:          input.Image,
+	}
+
+	return workflow.ExecuteActivity(ctx, ScanImageActivityName, activityInput).Get(ctx, nil)
+}
@@ -0,0 +1,67 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package securityworkflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+// ImageScanStarter starts image scans in the background.
+type ImageScanStarter struct {
+	cadenceClient client.Client
+	scanTimeout   time.Duration
+}
+
+// NewImageScanStarter returns a new ImageScanStarter.
+func NewImageScanStarter(cadenceClient client.Client, scanTimeout time.Duration) ImageScanStarter {
+	return ImageScanStarter{
+		cadenceClient: cadenceClient,
+		scanTimeout:   scanTimeout,
+	}
+}
+
+// StartImageScan starts the scan of an image unless the same image is already being scanned in the cluster.
+func (s ImageScanStarter) StartImageScan(ctx context.Context, organizationID uint, clusterID uint, image security.Image) error {
+	// leave room for the retries of the scan
+	timeout := 3*(s.scanTimeout+5*time.Minute) + 15*time.Minute
+
+	options := client.StartWorkflowOptions{
+		ID:                           fmt.Sprintf("%s-%d-%s", ScanImageWorkflowName, clusterID, image.Digest),
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: timeout,
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+	}
+
+	input := ScanImageWorkflowInput{
+		OrganizationID: organizationID,
+		ClusterID:      clusterID,
+		Image:          image,
+		ScanTimeout:    s.scanTimeout,
+	}
+
+	_, err := s.cadenceClient.StartWorkflow(ctx, options, ScanImageWorkflowName, input)
+	if _, ok := err.(*shared.WorkflowExecutionAlreadyStartedError); ok {
+		return nil
+	}
+
+	return errors.WrapIfWithDetails(err, "failed to start the image scan workflow", "workflowId", options.ID)
+}
@@ -0,0 +1,303 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+// TrivyScannerConfig holds the configuration of Trivy scans.
+type TrivyScannerConfig struct {
+	// Namespace is where the Trivy server runs and the scan jobs are started.
+	Namespace string
+
+	// ServerURL is the in-cluster address of the Trivy server.
+	ServerURL string
+
+	// Image is the Trivy image used for running scans in client mode.
+	Image string
+
+	// Timeout is the maximum duration of a single scan.
+	Timeout time.Duration
+}
+
+// TrivyScanStore caches the results of Trivy scans.
+type TrivyScanStore interface {
+	// GetImageScan returns the latest scan results of an image in a cluster.
+	GetImageScan(ctx context.Context, clusterID uint, imageDigest string) (security.ImageVulnerabilities, error)
+
+	// SaveImageScan stores the scan results of an image in a cluster, replacing the previous results.
+	SaveImageScan(ctx context.Context, clusterID uint, result security.ImageVulnerabilities) error
+}
+
+// ImageScanNotFoundError is returned when an image has not been scanned yet.
+type ImageScanNotFoundError struct {
+	ClusterID   uint
+	ImageDigest string
+}
+
+// Error implements the error interface.
+func (ImageScanNotFoundError) Error() string {
+	return "image has not been scanned yet"
+}
+
+// Details returns error details.
+func (e ImageScanNotFoundError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ClusterID, "imageDigest", e.ImageDigest}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to eg. status code.
+func (ImageScanNotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (ImageScanNotFoundError) ServiceError() bool {
+	return true
+}
+
+// trivyImageScanner serves the cached results of Trivy scans.
+//
+// Running a scan takes a while, so scans are executed asynchronously by the workers (see NewTrivyScanRunner)
+// and only their results are read when vulnerabilities are requested.
+type trivyImageScanner struct {
+	store TrivyScanStore
+}
+
+// NewTrivyImageScanner returns an ImageScanner serving the cached results of Trivy scans.
+func NewTrivyImageScanner(store TrivyScanStore) ImageScanner {
+	return trivyImageScanner{
+		store: store,
+	}
+}
+
+func (s trivyImageScanner) GetImageVulnerabilities(ctx context.Context, cluster Cluster, image security.Image) (security.ImageVulnerabilities, error) {
+	if image.Digest == "" {
+		return security.ImageVulnerabilities{}, errors.NewWithDetails("image digest is required", "image", image.Name)
+	}
+
+	result, err := s.store.GetImageScan(ctx, cluster.GetID(), image.Digest)
+	if err != nil {
+		return security.ImageVulnerabilities{}, err
+	}
+
+	// the same digest may be referenced by several names
+	result.Image = image
+
+	return result, nil
+}
+
+// trivyScanRunner scans images with the Trivy server running in the cluster.
+//
+// Scans are executed by a short-lived job running Trivy in client mode against the server,
+// the report is read from the logs of the job and cached by the digest of the image.
+type trivyScanRunner struct {
+	config TrivyScannerConfig
+	store  TrivyScanStore
+	logger common.Logger
+}
+
+// NewTrivyScanRunner returns an ImageScanner that runs a Trivy scan of the image and caches its results.
+// It blocks until the scan is finished, so it should only be used by asynchronous jobs.
+func NewTrivyScanRunner(config TrivyScannerConfig, store TrivyScanStore, logger common.Logger) ImageScanner {
+	return trivyScanRunner{
+		config: config,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// trivyReport is the JSON output of Trivy.
+type trivyReport []struct {
+	Target          string `json:"Target"`
+	Vulnerabilities []struct {
+		VulnerabilityID  string   `json:"VulnerabilityID"`
+		PkgName          string   `json:"PkgName"`
+		InstalledVersion string   `json:"InstalledVersion"`
+		FixedVersion     string   `json:"FixedVersion"`
+		Severity         string   `json:"Severity"`
+		PrimaryURL       string   `json:"PrimaryURL"`
+		References       []string `json:"References"`
+	} `json:"Vulnerabilities"`
+}
+
+func (s trivyScanRunner) GetImageVulnerabilities(ctx context.Context, cluster Cluster, image security.Image) (security.ImageVulnerabilities, error) {
+	if image.Digest == "" {
+		return security.ImageVulnerabilities{}, errors.NewWithDetails("image digest is required", "image", image.Name)
+	}
+
+	logCtx := map[string]interface{}{"clusterID": cluster.GetID(), "image": image.Reference()}
+	s.logger.Info("scanning image with trivy", logCtx)
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIf(err, "failed to get k8s config for the cluster")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIf(err, "failed to create k8s client")
+	}
+
+	output, err := s.runScan(ctx, client, image)
+	if err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIfWithDetails(err, "failed to scan image", "image", image.Reference())
+	}
+
+	vulnerabilities, err := parseTrivyReport(output)
+	if err != nil {
+		return security.ImageVulnerabilities{}, err
+	}
+
+	s.logger.Info("image scanned with trivy", logCtx)
+
+	result := security.ImageVulnerabilities{
+		Scanner:         ScannerTrivy,
+		Image:           image,
+		Vulnerabilities: vulnerabilities,
+	}
+
+	if err := s.store.SaveImageScan(ctx, cluster.GetID(), result); err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIfWithDetails(err, "failed to save scan results", "image", image.Reference())
+	}
+
+	return result, nil
+}
+
+func (s trivyScanRunner) runScan(ctx context.Context, client kubernetes.Interface, image security.Image) ([]byte, error) {
+	backoffLimit := int32(0)
+	ttl := int32(300)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "trivy-scan-",
+			Namespace:    s.config.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:  "trivy",
+							Image: s.config.Image,
+							Args: []string{
+								"client",
+								"--remote", s.config.ServerURL,
+								"--format", "json",
+								"--quiet",
+								image.Reference(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	job, err := client.BatchV1().Jobs(s.config.Namespace).Create(job)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create scan job")
+	}
+
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		err := client.BatchV1().Jobs(job.Namespace).Delete(job.Name, &metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil {
+			s.logger.Warn("failed to delete scan job", map[string]interface{}{"job": job.Name})
+		}
+	}()
+
+	var pod corev1.Pod
+	err = wait.PollImmediate(2*time.Second, s.config.Timeout, func() (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		pods, err := client.CoreV1().Pods(job.Namespace).List(metav1.ListOptions{LabelSelector: "job-name=" + job.Name})
+		if err != nil {
+			return false, err
+		}
+
+		for _, p := range pods.Items {
+			switch p.Status.Phase {
+			case corev1.PodSucceeded:
+				pod = p
+
+				return true, nil
+			case corev1.PodFailed:
+				return false, errors.NewWithDetails("scan job failed", "pod", p.Name)
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to wait for scan job")
+	}
+
+	output, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: "trivy"}).DoRaw()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to read scan results")
+	}
+
+	return output, nil
+}
+
+func parseTrivyReport(output []byte) ([]security.Vulnerability, error) {
+	var report trivyReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, errors.WrapIf(err, "failed to parse trivy report")
+	}
+
+	vulnerabilities := make([]security.Vulnerability, 0)
+	for _, result := range report {
+		for _, vulnerability := range result.Vulnerabilities {
+			url := vulnerability.PrimaryURL
+			if url == "" && len(vulnerability.References) > 0 {
+				url = vulnerability.References[0]
+			}
+
+			vulnerabilities = append(vulnerabilities, security.Vulnerability{
+				ID:             vulnerability.VulnerabilityID,
+				Severity:       strings.ToUpper(vulnerability.Severity),
+				Package:        vulnerability.PkgName,
+				PackageVersion: vulnerability.InstalledVersion,
+				FixedVersion:   vulnerability.FixedVersion,
+				URL:            url,
+			})
+		}
+	}
+
+	return vulnerabilities, nil
+}
@@ -0,0 +1,148 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anchore
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+func TestParseTrivyReport(t *testing.T) {
+	output := []byte(`[
+  {
+    "Target": "alpine:3.10 (alpine 3.10.2)",
+    "Vulnerabilities": [
+      {
+        "VulnerabilityID": "CVE-2019-1549",
+        "PkgName": "openssl",
+        "InstalledVersion": "1.1.1c-r0",
+        "FixedVersion": "1.1.1d-r0",
+        "Severity": "MEDIUM",
+        "References": ["https://access.redhat.com/security/cve/CVE-2019-1549"]
+      },
+      {
+        "VulnerabilityID": "CVE-2019-1563",
+        "PkgName": "openssl",
+        "InstalledVersion": "1.1.1c-r0",
+        "Severity": "low",
+        "PrimaryURL": "https://avd.aquasec.com/nvd/cve-2019-1563"
+      }
+    ]
+  },
+  {
+    "Target": "app/package-lock.json",
+    "Vulnerabilities": null
+  }
+]`)
+
+	vulnerabilities, err := parseTrivyReport(output)
+	require.NoError(t, err)
+
+	expected := []security.Vulnerability{
+		{
+			ID:             "CVE-2019-1549",
+			Severity:       "MEDIUM",
+			Package:        "openssl",
+			PackageVersion: "1.1.1c-r0",
+			FixedVersion:   "1.1.1d-r0",
+			URL:            "https://access.redhat.com/security/cve/CVE-2019-1549",
+		},
+		{
+			ID:             "CVE-2019-1563",
+			Severity:       "LOW",
+			Package:        "openssl",
+			PackageVersion: "1.1.1c-r0",
+			URL:            "https://avd.aquasec.com/nvd/cve-2019-1563",
+		},
+	}
+
+	assert.Equal(t, expected, vulnerabilities)
+}
+
+func TestParseTrivyReport_Invalid(t *testing.T) {
+	_, err := parseTrivyReport([]byte("FATAL: unable to connect to the server"))
+
+	assert.Error(t, err)
+}
+
+type trivyTestCluster struct {
+	id uint
+}
+
+func (c trivyTestCluster) GetK8sConfig() ([]byte, error) {
+	return nil, errors.New("the scan results should be read from the store")
+}
+
+func (c trivyTestCluster) GetID() uint {
+	return c.id
+}
+
+type inmemoryTrivyScanStore map[uint]map[string]security.ImageVulnerabilities
+
+func (s inmemoryTrivyScanStore) GetImageScan(_ context.Context, clusterID uint, imageDigest string) (security.ImageVulnerabilities, error) {
+	result, ok := s[clusterID][imageDigest]
+	if !ok {
+		return security.ImageVulnerabilities{}, errors.WithStack(ImageScanNotFoundError{ClusterID: clusterID, ImageDigest: imageDigest})
+	}
+
+	return result, nil
+}
+
+func (s inmemoryTrivyScanStore) SaveImageScan(_ context.Context, clusterID uint, result security.ImageVulnerabilities) error {
+	if s[clusterID] == nil {
+		s[clusterID] = make(map[string]security.ImageVulnerabilities)
+	}
+
+	s[clusterID][result.Image.Digest] = result
+
+	return nil
+}
+
+func TestTrivyImageScanner_GetImageVulnerabilities(t *testing.T) {
+	const digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+	store := inmemoryTrivyScanStore{
+		1: {
+			digest: {
+				Scanner:         ScannerTrivy,
+				Image:           security.Image{Name: "alpine", Tag: "3.10", Digest: digest},
+				Vulnerabilities: []security.Vulnerability{{ID: "CVE-2019-1549", Severity: "MEDIUM"}},
+			},
+		},
+	}
+
+	scanner := NewTrivyImageScanner(store)
+
+	image := security.Image{Name: "docker.io/library/alpine", Tag: "latest", Digest: digest}
+
+	result, err := scanner.GetImageVulnerabilities(context.Background(), trivyTestCluster{id: 1}, image)
+	require.NoError(t, err)
+
+	assert.Equal(t, ScannerTrivy, result.Scanner)
+	assert.Equal(t, image, result.Image)
+	assert.Equal(t, []security.Vulnerability{{ID: "CVE-2019-1549", Severity: "MEDIUM"}}, result.Vulnerabilities)
+
+	// results are cached per cluster
+	_, err = scanner.GetImageVulnerabilities(context.Background(), trivyTestCluster{id: 2}, image)
+
+	var notFoundErr ImageScanNotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
@@ -0,0 +1,333 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreport
+
+import (
+	"context"
+	"sort"
+
+	"emperror.dev/errors"
+)
+
+// Report is an organization level vulnerability report built from the latest snapshot.
+type Report struct {
+	Snapshot         Snapshot           `json:"snapshot"`
+	PreviousSnapshot *Snapshot          `json:"previousSnapshot,omitempty"`
+	Summary          Summary            `json:"summary"`
+	Clusters         []ClusterSummary   `json:"clusters"`
+	Namespaces       []NamespaceSummary `json:"namespaces"`
+	Releases         []ReleaseSummary   `json:"releases"`
+
+	// Unscanned lists the clusters and images that could not be evaluated for the snapshot.
+	// Their vulnerabilities are neither reported as new nor as fixed.
+	Unscanned []Unscanned `json:"unscanned"`
+}
+
+// Summary aggregates the vulnerabilities of a group of findings.
+// Vulnerabilities of an image are counted once per cluster, regardless of the number of pods running the image.
+type Summary struct {
+	Total      int            `json:"total"`
+	Severities map[string]int `json:"severities"`
+	Delta      Delta          `json:"delta"`
+}
+
+// Delta describes the changes of a group of findings since the previous snapshot.
+type Delta struct {
+	Total      int            `json:"total"`
+	Severities map[string]int `json:"severities"`
+	New        int            `json:"new"`
+	Fixed      int            `json:"fixed"`
+}
+
+// ClusterSummary aggregates the vulnerabilities of a cluster.
+type ClusterSummary struct {
+	ClusterID   uint   `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+	Summary
+}
+
+// NamespaceSummary aggregates the vulnerabilities of a namespace.
+type NamespaceSummary struct {
+	ClusterID   uint   `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+	Namespace   string `json:"namespace"`
+	Summary
+}
+
+// ReleaseSummary aggregates the vulnerabilities of a Helm release.
+// Images not deployed by Helm are grouped under an empty release name.
+type ReleaseSummary struct {
+	ClusterID   uint   `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+	Namespace   string `json:"namespace"`
+	Release     string `json:"release"`
+	Summary
+}
+
+// Service provides vulnerability reports.
+type Service interface {
+	// GetReport returns the vulnerability report of an organization.
+	GetReport(ctx context.Context, organizationID uint) (report Report, err error)
+}
+
+// NewService returns a new Service.
+func NewService(store Store) Service {
+	return service{store: store}
+}
+
+type service struct {
+	store Store
+}
+
+func (s service) GetReport(ctx context.Context, organizationID uint) (Report, error) {
+	snapshots, err := s.store.GetLatestSnapshots(ctx, organizationID, 2)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if len(snapshots) == 0 {
+		return Report{}, errors.WithStack(NotFoundError{OrganizationID: organizationID})
+	}
+
+	findings, err := s.store.ListFindings(ctx, snapshots[0].ID)
+	if err != nil {
+		return Report{}, err
+	}
+
+	unscanned, err := s.store.ListUnscanned(ctx, snapshots[0].ID)
+	if err != nil {
+		return Report{}, err
+	}
+
+	var previous *Snapshot
+	var previousFindings []Finding
+
+	if len(snapshots) > 1 {
+		previous = &snapshots[1]
+
+		previousFindings, err = s.store.ListFindings(ctx, previous.ID)
+		if err != nil {
+			return Report{}, err
+		}
+	}
+
+	return BuildReport(snapshots[0], previous, findings, previousFindings, unscanned), nil
+}
+
+type clusterKey struct {
+	ClusterID uint
+}
+
+type namespaceKey struct {
+	ClusterID uint
+	Namespace string
+}
+
+type releaseKey struct {
+	ClusterID uint
+	Namespace string
+	Release   string
+}
+
+type findingGroup struct {
+	current  []Finding
+	previous []Finding
+}
+
+// BuildReport aggregates the findings of a snapshot and computes the deltas since the previous snapshot.
+// Previous findings of unscanned clusters and images are left out, since their current state is unknown.
+func BuildReport(
+	snapshot Snapshot,
+	previous *Snapshot,
+	findings []Finding,
+	previousFindings []Finding,
+	unscanned []Unscanned,
+) Report {
+	previousFindings = excludeUnscanned(previousFindings, unscanned)
+
+	if unscanned == nil {
+		unscanned = []Unscanned{}
+	}
+
+	report := Report{
+		Snapshot:         snapshot,
+		PreviousSnapshot: previous,
+		Summary:          summarize(findings, previousFindings),
+		Clusters:         []ClusterSummary{},
+		Namespaces:       []NamespaceSummary{},
+		Releases:         []ReleaseSummary{},
+		Unscanned:        unscanned,
+	}
+
+	clusterNames := make(map[uint]string)
+	clusters := make(map[clusterKey]*findingGroup)
+	namespaces := make(map[namespaceKey]*findingGroup)
+	releases := make(map[releaseKey]*findingGroup)
+
+	group := func(findings []Finding, add func(group *findingGroup, finding Finding)) {
+		for _, finding := range findings {
+			if _, ok := clusterNames[finding.ClusterID]; !ok || finding.ClusterName != "" {
+				clusterNames[finding.ClusterID] = finding.ClusterName
+			}
+
+			ck := clusterKey{ClusterID: finding.ClusterID}
+			if clusters[ck] == nil {
+				clusters[ck] = &findingGroup{}
+			}
+			add(clusters[ck], finding)
+
+			nk := namespaceKey{ClusterID: finding.ClusterID, Namespace: finding.Namespace}
+			if namespaces[nk] == nil {
+				namespaces[nk] = &findingGroup{}
+			}
+			add(namespaces[nk], finding)
+
+			rk := releaseKey{ClusterID: finding.ClusterID, Namespace: finding.Namespace, Release: finding.Release}
+			if releases[rk] == nil {
+				releases[rk] = &findingGroup{}
+			}
+			add(releases[rk], finding)
+		}
+	}
+
+	// previous findings first, so that current cluster names take precedence
+	group(previousFindings, func(group *findingGroup, finding Finding) { group.previous = append(group.previous, finding) })
+	group(findings, func(group *findingGroup, finding Finding) { group.current = append(group.current, finding) })
+
+	for key, group := range clusters {
+		report.Clusters = append(report.Clusters, ClusterSummary{
+			ClusterID:   key.ClusterID,
+			ClusterName: clusterNames[key.ClusterID],
+			Summary:     summarize(group.current, group.previous),
+		})
+	}
+
+	for key, group := range namespaces {
+		report.Namespaces = append(report.Namespaces, NamespaceSummary{
+			ClusterID:   key.ClusterID,
+			ClusterName: clusterNames[key.ClusterID],
+			Namespace:   key.Namespace,
+			Summary:     summarize(group.current, group.previous),
+		})
+	}
+
+	for key, group := range releases {
+		report.Releases = append(report.Releases, ReleaseSummary{
+			ClusterID:   key.ClusterID,
+			ClusterName: clusterNames[key.ClusterID],
+			Namespace:   key.Namespace,
+			Release:     key.Release,
+			Summary:     summarize(group.current, group.previous),
+		})
+	}
+
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		return report.Clusters[i].ClusterID < report.Clusters[j].ClusterID
+	})
+
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		a, b := report.Namespaces[i], report.Namespaces[j]
+		if a.ClusterID != b.ClusterID {
+			return a.ClusterID < b.ClusterID
+		}
+
+		return a.Namespace < b.Namespace
+	})
+
+	sort.Slice(report.Releases, func(i, j int) bool {
+		a, b := report.Releases[i], report.Releases[j]
+		if a.ClusterID != b.ClusterID {
+			return a.ClusterID < b.ClusterID
+		}
+
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+
+		return a.Release < b.Release
+	})
+
+	return report
+}
+
+func excludeUnscanned(findings []Finding, unscanned []Unscanned) []Finding {
+	if len(unscanned) == 0 {
+		return findings
+	}
+
+	filtered := make([]Finding, 0, len(findings))
+
+findings:
+	for _, finding := range findings {
+		for _, u := range unscanned {
+			if u.covers(finding) {
+				continue findings
+			}
+		}
+
+		filtered = append(filtered, finding)
+	}
+
+	return filtered
+}
+
+// severitiesByKey deduplicates findings by their keys.
+func severitiesByKey(findings []Finding) map[string]string {
+	severities := make(map[string]string, len(findings))
+	for _, finding := range findings {
+		severities[finding.key()] = finding.Severity
+	}
+
+	return severities
+}
+
+func summarize(current []Finding, previous []Finding) Summary {
+	currentSeverities := severitiesByKey(current)
+	previousSeverities := severitiesByKey(previous)
+
+	summary := Summary{
+		Total:      len(currentSeverities),
+		Severities: make(map[string]int),
+		Delta: Delta{
+			Total:      len(currentSeverities) - len(previousSeverities),
+			Severities: make(map[string]int),
+		},
+	}
+
+	for key, severity := range currentSeverities {
+		summary.Severities[severity]++
+		summary.Delta.Severities[severity]++
+
+		if _, ok := previousSeverities[key]; !ok {
+			summary.Delta.New++
+		}
+	}
+
+	for key, severity := range previousSeverities {
+		summary.Delta.Severities[severity]--
+
+		if _, ok := currentSeverities[key]; !ok {
+			summary.Delta.Fixed++
+		}
+	}
+
+	for severity, delta := range summary.Delta.Severities {
+		if delta == 0 {
+			delete(summary.Delta.Severities, severity)
+		}
+	}
+
+	return summary
+}
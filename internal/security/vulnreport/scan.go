@@ -0,0 +1,167 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreport
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+// Cluster is a cluster with security scan enabled.
+type Cluster struct {
+	ID             uint
+	Name           string
+	OrganizationID uint
+}
+
+// RunningImage is an image of a container running in a cluster.
+type RunningImage struct {
+	Namespace string
+	Release   string
+	Image     security.Image
+}
+
+// ClusterFinder finds the clusters that should be rescanned.
+type ClusterFinder interface {
+	// FindOrganizations returns the organizations having clusters with security scan enabled.
+	FindOrganizations(ctx context.Context) ([]uint, error)
+
+	// FindClusters returns the clusters of an organization with security scan enabled.
+	FindClusters(ctx context.Context, organizationID uint) ([]Cluster, error)
+}
+
+// ImageLister lists the images running in a cluster.
+type ImageLister interface {
+	// ListRunningImages lists the images of the running containers of a cluster.
+	ListRunningImages(ctx context.Context, clusterID uint) ([]RunningImage, error)
+}
+
+// ImageScanner evaluates images with the scanner backend configured for a cluster.
+type ImageScanner interface {
+	// GetImageVulnerabilities returns the vulnerabilities found in an image running in a cluster.
+	GetImageVulnerabilities(ctx context.Context, clusterID uint, image security.Image) (security.ImageVulnerabilities, error)
+}
+
+// ClusterScanner re-evaluates every running image of a cluster and records the findings in a snapshot.
+type ClusterScanner struct {
+	imageLister  ImageLister
+	imageScanner ImageScanner
+	store        Store
+	logger       common.Logger
+}
+
+// NewClusterScanner returns a new ClusterScanner.
+func NewClusterScanner(imageLister ImageLister, imageScanner ImageScanner, store Store, logger common.Logger) ClusterScanner {
+	return ClusterScanner{
+		imageLister:  imageLister,
+		imageScanner: imageScanner,
+		store:        store,
+		logger:       logger,
+	}
+}
+
+// ScanCluster scans the running images of a cluster and adds the findings to a snapshot.
+// Images that cannot be evaluated are skipped.
+//
+// The optional onImageScanned callback is called after every image evaluation, so that callers can report progress.
+func (s ClusterScanner) ScanCluster(ctx context.Context, snapshotID uint, cluster Cluster, onImageScanned func(image security.Image)) error {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"snapshotId": snapshotID,
+		"clusterId":  cluster.ID,
+	})
+
+	images, err := s.imageLister.ListRunningImages(ctx, cluster.ID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list running images", "clusterId", cluster.ID)
+	}
+
+	// the same image usually runs in several pods, evaluate it only once
+	results := make(map[string][]security.Vulnerability)
+	failed := make(map[string]bool)
+	findings := make([]Finding, 0)
+	unscanned := make([]Unscanned, 0)
+
+	for _, image := range images {
+		if failed[image.Image.Digest] {
+			continue
+		}
+
+		vulnerabilities, ok := results[image.Image.Digest]
+		if !ok {
+			imageVulnerabilities, err := s.imageScanner.GetImageVulnerabilities(ctx, cluster.ID, image.Image)
+			if onImageScanned != nil {
+				onImageScanned(image.Image)
+			}
+
+			if err != nil {
+				logger.Warn("failed to evaluate image", map[string]interface{}{
+					"image":  image.Image.Reference(),
+					"digest": image.Image.Digest,
+					"error":  err.Error(),
+				})
+
+				// an image without results is not an image without vulnerabilities
+				failed[image.Image.Digest] = true
+				unscanned = append(unscanned, Unscanned{
+					ClusterID:   cluster.ID,
+					ClusterName: cluster.Name,
+					Image:       security.Image{Name: image.Image.Name, Tag: image.Image.Tag}.Reference(),
+					ImageDigest: image.Image.Digest,
+					Reason:      err.Error(),
+				})
+
+				continue
+			}
+
+			vulnerabilities = imageVulnerabilities.Vulnerabilities
+			results[image.Image.Digest] = vulnerabilities
+		}
+
+		for _, vulnerability := range vulnerabilities {
+			findings = append(findings, Finding{
+				ClusterID:       cluster.ID,
+				ClusterName:     cluster.Name,
+				Namespace:       image.Namespace,
+				Release:         image.Release,
+				Image:           security.Image{Name: image.Image.Name, Tag: image.Image.Tag}.Reference(),
+				ImageDigest:     image.Image.Digest,
+				VulnerabilityID: vulnerability.ID,
+				Severity:        vulnerability.Severity,
+				Package:         vulnerability.Package,
+				FixedVersion:    vulnerability.FixedVersion,
+			})
+		}
+	}
+
+	logger.Info("cluster images evaluated", map[string]interface{}{
+		"images":    len(results),
+		"unscanned": len(unscanned),
+		"findings":  len(findings),
+	})
+
+	if err := s.store.AddFindings(ctx, snapshotID, findings); err != nil {
+		return err
+	}
+
+	if len(unscanned) > 0 {
+		return s.store.AddUnscanned(ctx, snapshotID, unscanned)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreport
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Snapshot is the result of a scheduled rescan of every running image in an organization.
+type Snapshot struct {
+	ID             uint      `json:"id"`
+	OrganizationID uint      `json:"organizationId"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Finding is a vulnerability found in an image running in a cluster.
+type Finding struct {
+	ClusterID       uint
+	ClusterName     string
+	Namespace       string
+	Release         string
+	Image           string
+	ImageDigest     string
+	VulnerabilityID string
+	Severity        string
+	Package         string
+	FixedVersion    string
+}
+
+// key identifies a finding across snapshots.
+// key identifies a vulnerability of an image in a cluster.
+// The same image running in several pods is counted once.
+func (f Finding) key() string {
+	return fmt.Sprintf("%d/%s/%s", f.ClusterID, f.ImageDigest, f.VulnerabilityID)
+}
+
+// Unscanned is a cluster or an image of a cluster that could not be evaluated for a snapshot.
+// Findings of unscanned targets are unknown, so they are left out of the deltas of a report.
+type Unscanned struct {
+	ClusterID   uint   `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+
+	// Image and ImageDigest are empty if the whole cluster could not be scanned.
+	Image       string `json:"image,omitempty"`
+	ImageDigest string `json:"imageDigest,omitempty"`
+
+	Reason string `json:"reason"`
+}
+
+// covers returns true if the finding belongs to the unscanned target.
+func (u Unscanned) covers(finding Finding) bool {
+	return u.ClusterID == finding.ClusterID && (u.ImageDigest == "" || u.ImageDigest == finding.ImageDigest)
+}
+
+// Store persists vulnerability snapshots.
+type Store interface {
+	// CreateSnapshot creates a new, incomplete snapshot for an organization.
+	CreateSnapshot(ctx context.Context, organizationID uint) (Snapshot, error)
+
+	// AddFindings adds findings to an incomplete snapshot.
+	AddFindings(ctx context.Context, snapshotID uint, findings []Finding) error
+
+	// AddUnscanned records clusters and images that could not be evaluated for an incomplete snapshot.
+	AddUnscanned(ctx context.Context, snapshotID uint, unscanned []Unscanned) error
+
+	// CompleteSnapshot marks a snapshot complete, making it visible for reports.
+	CompleteSnapshot(ctx context.Context, snapshotID uint) error
+
+	// GetLatestSnapshots returns the latest complete snapshots of an organization (newest first).
+	GetLatestSnapshots(ctx context.Context, organizationID uint, limit int) ([]Snapshot, error)
+
+	// ListFindings returns the findings of a snapshot.
+	ListFindings(ctx context.Context, snapshotID uint) ([]Finding, error)
+
+	// ListUnscanned returns the clusters and images that could not be evaluated for a snapshot.
+	ListUnscanned(ctx context.Context, snapshotID uint) ([]Unscanned, error)
+
+	// DeleteSnapshots deletes the snapshots of an organization except the latest ones.
+	DeleteSnapshots(ctx context.Context, organizationID uint, keep int) error
+}
+
+// NotFoundError is returned if an organization has no vulnerability snapshots yet.
+type NotFoundError struct {
+	OrganizationID uint
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "vulnerability report not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"organizationId", e.OrganizationID}
+}
+
+// NotFound tells a client that this error is related to a resource being not found.
+// Can be used to translate the error to eg. status code.
+func (NotFoundError) NotFound() bool {
+	return true
+}
+
+// ServiceError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (NotFoundError) ServiceError() bool {
+	return true
+}
@@ -0,0 +1,330 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+func TestBuildReport(t *testing.T) {
+	previous := Snapshot{ID: 1, OrganizationID: 1, CreatedAt: time.Date(2020, 5, 1, 3, 0, 0, 0, time.UTC)}
+	current := Snapshot{ID: 2, OrganizationID: 1, CreatedAt: time.Date(2020, 5, 2, 3, 0, 0, 0, time.UTC)}
+
+	finding := func(clusterID uint, namespace string, release string, id string, severity string) Finding {
+		return Finding{
+			ClusterID:       clusterID,
+			ClusterName:     map[uint]string{1: "prod", 2: "staging"}[clusterID],
+			Namespace:       namespace,
+			Release:         release,
+			Image:           "nginx:1.17",
+			ImageDigest:     "sha256:abc",
+			VulnerabilityID: id,
+			Severity:        severity,
+			Package:         "openssl",
+		}
+	}
+
+	previousFindings := []Finding{
+		finding(1, "default", "web", "CVE-1", "HIGH"),
+		finding(1, "default", "web", "CVE-2", "LOW"),
+		finding(2, "default", "web", "CVE-1", "HIGH"),
+	}
+
+	findings := []Finding{
+		finding(1, "default", "web", "CVE-1", "HIGH"),
+		finding(1, "default", "web", "CVE-3", "CRITICAL"),
+		finding(1, "monitoring", "", "CVE-3", "CRITICAL"),
+	}
+
+	report := BuildReport(current, &previous, findings, previousFindings, nil)
+
+	assert.Equal(t, current, report.Snapshot)
+	assert.Equal(t, &previous, report.PreviousSnapshot)
+	assert.Equal(t, []Unscanned{}, report.Unscanned)
+
+	// the image running in two namespaces of the same cluster is counted once
+	assert.Equal(t, Summary{
+		Total:      2,
+		Severities: map[string]int{"HIGH": 1, "CRITICAL": 1},
+		Delta: Delta{
+			Total:      -1,
+			Severities: map[string]int{"HIGH": -1, "LOW": -1, "CRITICAL": 1},
+			New:        1,
+			Fixed:      2,
+		},
+	}, report.Summary)
+
+	require.Len(t, report.Clusters, 2)
+	assert.Equal(t, ClusterSummary{
+		ClusterID:   1,
+		ClusterName: "prod",
+		Summary: Summary{
+			Total:      2,
+			Severities: map[string]int{"HIGH": 1, "CRITICAL": 1},
+			Delta: Delta{
+				Total:      0,
+				Severities: map[string]int{"LOW": -1, "CRITICAL": 1},
+				New:        1,
+				Fixed:      1,
+			},
+		},
+	}, report.Clusters[0])
+	assert.Equal(t, ClusterSummary{
+		ClusterID:   2,
+		ClusterName: "staging",
+		Summary: Summary{
+			Total:      0,
+			Severities: map[string]int{},
+			Delta: Delta{
+				Total:      -1,
+				Severities: map[string]int{"HIGH": -1},
+				Fixed:      1,
+			},
+		},
+	}, report.Clusters[1])
+
+	require.Len(t, report.Namespaces, 3)
+	assert.Equal(t, "default", report.Namespaces[0].Namespace)
+	assert.Equal(t, "monitoring", report.Namespaces[1].Namespace)
+	assert.Equal(t, 1, report.Namespaces[1].Delta.New)
+	assert.Equal(t, uint(2), report.Namespaces[2].ClusterID)
+
+	require.Len(t, report.Releases, 3)
+	assert.Equal(t, "web", report.Releases[0].Release)
+	assert.Equal(t, 2, report.Releases[0].Total)
+	assert.Equal(t, "", report.Releases[1].Release)
+	assert.Equal(t, "monitoring", report.Releases[1].Namespace)
+}
+
+func TestBuildReport_Unscanned(t *testing.T) {
+	previous := Snapshot{ID: 1, OrganizationID: 1}
+	current := Snapshot{ID: 2, OrganizationID: 1}
+
+	previousFindings := []Finding{
+		{ClusterID: 1, Namespace: "default", ImageDigest: "sha256:abc", VulnerabilityID: "CVE-1", Severity: "HIGH"},
+		{ClusterID: 1, Namespace: "default", ImageDigest: "sha256:def", VulnerabilityID: "CVE-2", Severity: "LOW"},
+		{ClusterID: 1, Namespace: "default", ImageDigest: "sha256:ghi", VulnerabilityID: "CVE-3", Severity: "LOW"},
+		{ClusterID: 2, Namespace: "default", ImageDigest: "sha256:abc", VulnerabilityID: "CVE-1", Severity: "HIGH"},
+	}
+
+	findings := []Finding{
+		{ClusterID: 1, Namespace: "default", ImageDigest: "sha256:abc", VulnerabilityID: "CVE-1", Severity: "HIGH"},
+	}
+
+	unscanned := []Unscanned{
+		{ClusterID: 1, ClusterName: "prod", Image: "redis:5", ImageDigest: "sha256:def", Reason: "scanner unavailable"},
+		{ClusterID: 2, ClusterName: "staging", Reason: "cluster unreachable"},
+	}
+
+	report := BuildReport(current, &previous, findings, previousFindings, unscanned)
+
+	// only the vulnerability of the image that is no longer running counts as fixed
+	assert.Equal(t, Summary{
+		Total:      1,
+		Severities: map[string]int{"HIGH": 1},
+		Delta: Delta{
+			Total:      -1,
+			Severities: map[string]int{"LOW": -1},
+			Fixed:      1,
+		},
+	}, report.Summary)
+	assert.Equal(t, unscanned, report.Unscanned)
+
+	require.Len(t, report.Clusters, 1)
+	assert.Equal(t, uint(1), report.Clusters[0].ClusterID)
+}
+
+func TestBuildReport_NoPreviousSnapshot(t *testing.T) {
+	snapshot := Snapshot{ID: 1, OrganizationID: 1}
+
+	report := BuildReport(snapshot, nil, []Finding{{ClusterID: 1, Namespace: "default", VulnerabilityID: "CVE-1", Severity: "HIGH"}}, nil, nil)
+
+	assert.Nil(t, report.PreviousSnapshot)
+	assert.Equal(t, 1, report.Summary.Total)
+	assert.Equal(t, 1, report.Summary.Delta.New)
+	assert.Equal(t, 0, report.Summary.Delta.Fixed)
+}
+
+type inmemoryStore struct {
+	snapshots []Snapshot
+	findings  map[uint][]Finding
+	unscanned map[uint][]Unscanned
+}
+
+func (s *inmemoryStore) CreateSnapshot(_ context.Context, organizationID uint) (Snapshot, error) {
+	snapshot := Snapshot{ID: uint(len(s.snapshots) + 1), OrganizationID: organizationID}
+	s.snapshots = append(s.snapshots, snapshot)
+
+	return snapshot, nil
+}
+
+func (s *inmemoryStore) AddFindings(_ context.Context, snapshotID uint, findings []Finding) error {
+	s.findings[snapshotID] = append(s.findings[snapshotID], findings...)
+
+	return nil
+}
+
+func (s *inmemoryStore) AddUnscanned(_ context.Context, snapshotID uint, unscanned []Unscanned) error {
+	s.unscanned[snapshotID] = append(s.unscanned[snapshotID], unscanned...)
+
+	return nil
+}
+
+func (s *inmemoryStore) CompleteSnapshot(_ context.Context, _ uint) error {
+	return nil
+}
+
+func (s *inmemoryStore) GetLatestSnapshots(_ context.Context, _ uint, limit int) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	for i := len(s.snapshots) - 1; i >= 0 && len(snapshots) < limit; i-- {
+		snapshots = append(snapshots, s.snapshots[i])
+	}
+
+	return snapshots, nil
+}
+
+func (s *inmemoryStore) ListFindings(_ context.Context, snapshotID uint) ([]Finding, error) {
+	return s.findings[snapshotID], nil
+}
+
+func (s *inmemoryStore) ListUnscanned(_ context.Context, snapshotID uint) ([]Unscanned, error) {
+	return s.unscanned[snapshotID], nil
+}
+
+func (s *inmemoryStore) DeleteSnapshots(_ context.Context, _ uint, _ int) error {
+	return nil
+}
+
+func TestService_GetReport_NotFound(t *testing.T) {
+	service := NewService(&inmemoryStore{findings: map[uint][]Finding{}, unscanned: map[uint][]Unscanned{}})
+
+	_, err := service.GetReport(context.Background(), 1)
+
+	var notFoundErr NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+type imageListerStub []RunningImage
+
+func (l imageListerStub) ListRunningImages(_ context.Context, _ uint) ([]RunningImage, error) {
+	return l, nil
+}
+
+type imageScannerStub struct {
+	calls  int
+	failed map[string]bool
+}
+
+func (s *imageScannerStub) GetImageVulnerabilities(_ context.Context, _ uint, image security.Image) (security.ImageVulnerabilities, error) {
+	s.calls++
+
+	if s.failed[image.Digest] {
+		return security.ImageVulnerabilities{}, errors.New("scanner unavailable")
+	}
+
+	return security.ImageVulnerabilities{
+		Image: image,
+		Vulnerabilities: []security.Vulnerability{
+			{ID: "CVE-1", Severity: "HIGH", Package: "openssl", FixedVersion: "1.1.1g"},
+		},
+	}, nil
+}
+
+func TestClusterScanner_ScanCluster(t *testing.T) {
+	image := security.Image{Name: "nginx", Tag: "1.17", Digest: "sha256:abc"}
+	imageLister := imageListerStub{
+		{Namespace: "default", Release: "web", Image: image},
+		{Namespace: "staging", Release: "web", Image: image},
+	}
+	imageScanner := &imageScannerStub{}
+	store := &inmemoryStore{findings: map[uint][]Finding{}, unscanned: map[uint][]Unscanned{}}
+
+	scanner := NewClusterScanner(imageLister, imageScanner, store, common.NoopLogger{})
+
+	var scanned []security.Image
+
+	err := scanner.ScanCluster(context.Background(), 1, Cluster{ID: 3, Name: "prod", OrganizationID: 1}, func(image security.Image) {
+		scanned = append(scanned, image)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, imageScanner.calls)
+	assert.Equal(t, []security.Image{image}, scanned)
+	assert.Equal(t, []Finding{
+		{
+			ClusterID:       3,
+			ClusterName:     "prod",
+			Namespace:       "default",
+			Release:         "web",
+			Image:           "nginx:1.17",
+			ImageDigest:     "sha256:abc",
+			VulnerabilityID: "CVE-1",
+			Severity:        "HIGH",
+			Package:         "openssl",
+			FixedVersion:    "1.1.1g",
+		},
+		{
+			ClusterID:       3,
+			ClusterName:     "prod",
+			Namespace:       "staging",
+			Release:         "web",
+			Image:           "nginx:1.17",
+			ImageDigest:     "sha256:abc",
+			VulnerabilityID: "CVE-1",
+			Severity:        "HIGH",
+			Package:         "openssl",
+			FixedVersion:    "1.1.1g",
+		},
+	}, store.findings[1])
+}
+
+func TestClusterScanner_ScanCluster_FailedImage(t *testing.T) {
+	image := security.Image{Name: "nginx", Tag: "1.17", Digest: "sha256:abc"}
+	failedImage := security.Image{Name: "redis", Tag: "5", Digest: "sha256:def"}
+	imageLister := imageListerStub{
+		{Namespace: "default", Release: "web", Image: image},
+		{Namespace: "default", Release: "cache", Image: failedImage},
+		{Namespace: "staging", Release: "cache", Image: failedImage},
+	}
+	imageScanner := &imageScannerStub{failed: map[string]bool{"sha256:def": true}}
+	store := &inmemoryStore{findings: map[uint][]Finding{}, unscanned: map[uint][]Unscanned{}}
+
+	scanner := NewClusterScanner(imageLister, imageScanner, store, common.NoopLogger{})
+
+	err := scanner.ScanCluster(context.Background(), 1, Cluster{ID: 3, Name: "prod", OrganizationID: 1}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, imageScanner.calls)
+	require.Len(t, store.findings[1], 1)
+	assert.Equal(t, "sha256:abc", store.findings[1][0].ImageDigest)
+	assert.Equal(t, []Unscanned{
+		{
+			ClusterID:   3,
+			ClusterName: "prod",
+			Image:       "redis:5",
+			ImageDigest: "sha256:def",
+			Reason:      "scanner unavailable",
+		},
+	}, store.unscanned[1])
+}
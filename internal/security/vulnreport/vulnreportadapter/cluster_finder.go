@@ -0,0 +1,80 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreportadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// ClusterFinder finds running clusters with an active integrated service in the database.
+type ClusterFinder struct {
+	db                    *gorm.DB
+	integratedServiceName string
+}
+
+// NewClusterFinder returns a new ClusterFinder.
+func NewClusterFinder(db *gorm.DB, integratedServiceName string) ClusterFinder {
+	return ClusterFinder{
+		db:                    db,
+		integratedServiceName: integratedServiceName,
+	}
+}
+
+func (f ClusterFinder) query() *gorm.DB {
+	return f.db.
+		Table("clusters").
+		Joins("JOIN cluster_features ON cluster_features.cluster_id = clusters.id").
+		Where("clusters.deleted_at IS NULL AND clusters.status = ?", pkgCluster.Running).
+		Where("cluster_features.name = ? AND cluster_features.status = ?", f.integratedServiceName, integratedservices.IntegratedServiceStatusActive)
+}
+
+// FindOrganizations implements the vulnreport.ClusterFinder interface.
+func (f ClusterFinder) FindOrganizations(ctx context.Context) ([]uint, error) {
+	var organizationIDs []uint
+
+	err := f.query().Pluck("DISTINCT clusters.organization_id", &organizationIDs).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to query organizations", "integratedService", f.integratedServiceName)
+	}
+
+	return organizationIDs, nil
+}
+
+// FindClusters implements the vulnreport.ClusterFinder interface.
+func (f ClusterFinder) FindClusters(ctx context.Context, organizationID uint) ([]vulnreport.Cluster, error) {
+	var clusters []vulnreport.Cluster
+
+	err := f.query().
+		Where("clusters.organization_id = ?", organizationID).
+		Select("clusters.id, clusters.name, clusters.organization_id").
+		Scan(&clusters).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to query clusters",
+			"organizationId", organizationID,
+			"integratedService", f.integratedServiceName,
+		)
+	}
+
+	return clusters, nil
+}
@@ -0,0 +1,322 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreportadapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
+	"github.com/banzaicloud/pipeline/pkg/gormhelper"
+)
+
+const (
+	snapshotTableName  = "security_vulnerability_snapshots"
+	findingTableName   = "security_vulnerability_findings"
+	unscannedTableName = "security_vulnerability_unscanned"
+)
+
+type snapshotModel struct {
+	ID             uint `gorm:"primary_key"`
+	OrganizationID uint `gorm:"index:idx_security_vulnerability_snapshots_org_id"`
+	CreatedAt      time.Time
+	Completed      bool
+}
+
+func (snapshotModel) TableName() string {
+	return snapshotTableName
+}
+
+type findingModel struct {
+	ID              uint `gorm:"primary_key"`
+	SnapshotID      uint `gorm:"index:idx_security_vulnerability_findings_snapshot_id"`
+	ClusterID       uint
+	ClusterName     string
+	Namespace       string
+	Release         string
+	Image           string
+	ImageDigest     string
+	VulnerabilityID string
+	Severity        string
+	Package         string
+	FixedVersion    string
+}
+
+func (findingModel) TableName() string {
+	return findingTableName
+}
+
+type unscannedModel struct {
+	ID          uint `gorm:"primary_key"`
+	SnapshotID  uint `gorm:"index:idx_security_vulnerability_unscanned_snapshot_id"`
+	ClusterID   uint
+	ClusterName string
+	Image       string
+	ImageDigest string
+	Reason      string `gorm:"type:text"`
+}
+
+func (unscannedModel) TableName() string {
+	return unscannedTableName
+}
+
+// Migrate executes the table migrations for the vulnerability report module.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	tables := []interface{}{
+		&snapshotModel{},
+		&findingModel{},
+		&unscannedModel{},
+	}
+
+	var tableNames string
+	for _, table := range tables {
+		tableNames += fmt.Sprintf(" %s", db.NewScope(table).TableName())
+	}
+
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.TrimSpace(tableNames),
+	}).Info("migrating vulnerability report tables")
+
+	err := db.AutoMigrate(tables...).Error
+	if err != nil {
+		return err
+	}
+
+	err = gormhelper.AddForeignKey(db, logger, &snapshotModel{}, &findingModel{}, "SnapshotID")
+	if err != nil {
+		return err
+	}
+
+	return gormhelper.AddForeignKey(db, logger, &snapshotModel{}, &unscannedModel{}, "SnapshotID")
+}
+
+// GormStore is a vulnreport.Store backed by a relational database.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+func (s GormStore) CreateSnapshot(ctx context.Context, organizationID uint) (vulnreport.Snapshot, error) {
+	model := snapshotModel{
+		OrganizationID: organizationID,
+	}
+
+	if err := s.db.Create(&model).Error; err != nil {
+		return vulnreport.Snapshot{}, errors.WrapIfWithDetails(err, "failed to create snapshot", "organizationId", organizationID)
+	}
+
+	return toSnapshot(model), nil
+}
+
+func (s GormStore) AddFindings(ctx context.Context, snapshotID uint, findings []vulnreport.Finding) error {
+	tx := s.db.Begin()
+	if err := tx.Error; err != nil {
+		return errors.WrapIf(err, "failed to begin transaction")
+	}
+
+	for _, finding := range findings {
+		model := findingModel{
+			SnapshotID:      snapshotID,
+			ClusterID:       finding.ClusterID,
+			ClusterName:     finding.ClusterName,
+			Namespace:       finding.Namespace,
+			Release:         finding.Release,
+			Image:           finding.Image,
+			ImageDigest:     finding.ImageDigest,
+			VulnerabilityID: finding.VulnerabilityID,
+			Severity:        finding.Severity,
+			Package:         finding.Package,
+			FixedVersion:    finding.FixedVersion,
+		}
+
+		if err := tx.Create(&model).Error; err != nil {
+			tx.Rollback()
+
+			return errors.WrapIfWithDetails(err, "failed to add finding", "snapshotId", snapshotID)
+		}
+	}
+
+	return errors.WrapIf(tx.Commit().Error, "failed to commit transaction")
+}
+
+func (s GormStore) AddUnscanned(ctx context.Context, snapshotID uint, unscanned []vulnreport.Unscanned) error {
+	tx := s.db.Begin()
+	if err := tx.Error; err != nil {
+		return errors.WrapIf(err, "failed to begin transaction")
+	}
+
+	for _, u := range unscanned {
+		model := unscannedModel{
+			SnapshotID:  snapshotID,
+			ClusterID:   u.ClusterID,
+			ClusterName: u.ClusterName,
+			Image:       u.Image,
+			ImageDigest: u.ImageDigest,
+			Reason:      u.Reason,
+		}
+
+		if err := tx.Create(&model).Error; err != nil {
+			tx.Rollback()
+
+			return errors.WrapIfWithDetails(err, "failed to add unscanned target", "snapshotId", snapshotID)
+		}
+	}
+
+	return errors.WrapIf(tx.Commit().Error, "failed to commit transaction")
+}
+
+func (s GormStore) CompleteSnapshot(ctx context.Context, snapshotID uint) error {
+	err := s.db.Model(&snapshotModel{ID: snapshotID}).Update("completed", true).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to complete snapshot", "snapshotId", snapshotID)
+	}
+
+	return nil
+}
+
+func (s GormStore) GetLatestSnapshots(ctx context.Context, organizationID uint, limit int) ([]vulnreport.Snapshot, error) {
+	var models []snapshotModel
+
+	err := s.db.
+		Where(snapshotModel{OrganizationID: organizationID, Completed: true}).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&models).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list snapshots", "organizationId", organizationID)
+	}
+
+	snapshots := make([]vulnreport.Snapshot, 0, len(models))
+	for _, model := range models {
+		snapshots = append(snapshots, toSnapshot(model))
+	}
+
+	return snapshots, nil
+}
+
+func (s GormStore) ListFindings(ctx context.Context, snapshotID uint) ([]vulnreport.Finding, error) {
+	var models []findingModel
+
+	err := s.db.Where(findingModel{SnapshotID: snapshotID}).Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list findings", "snapshotId", snapshotID)
+	}
+
+	findings := make([]vulnreport.Finding, 0, len(models))
+	for _, model := range models {
+		findings = append(findings, vulnreport.Finding{
+			ClusterID:       model.ClusterID,
+			ClusterName:     model.ClusterName,
+			Namespace:       model.Namespace,
+			Release:         model.Release,
+			Image:           model.Image,
+			ImageDigest:     model.ImageDigest,
+			VulnerabilityID: model.VulnerabilityID,
+			Severity:        model.Severity,
+			Package:         model.Package,
+			FixedVersion:    model.FixedVersion,
+		})
+	}
+
+	return findings, nil
+}
+
+func (s GormStore) ListUnscanned(ctx context.Context, snapshotID uint) ([]vulnreport.Unscanned, error) {
+	var models []unscannedModel
+
+	err := s.db.Where(unscannedModel{SnapshotID: snapshotID}).Order("id").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list unscanned targets", "snapshotId", snapshotID)
+	}
+
+	unscanned := make([]vulnreport.Unscanned, 0, len(models))
+	for _, model := range models {
+		unscanned = append(unscanned, vulnreport.Unscanned{
+			ClusterID:   model.ClusterID,
+			ClusterName: model.ClusterName,
+			Image:       model.Image,
+			ImageDigest: model.ImageDigest,
+			Reason:      model.Reason,
+		})
+	}
+
+	return unscanned, nil
+}
+
+func (s GormStore) DeleteSnapshots(ctx context.Context, organizationID uint, keep int) error {
+	var ids []uint
+
+	err := s.db.
+		Model(&snapshotModel{}).
+		Where("organization_id = ?", organizationID).
+		Order("created_at DESC, id DESC").
+		Pluck("id", &ids).
+		Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list snapshots", "organizationId", organizationID)
+	}
+
+	if len(ids) <= keep {
+		return nil
+	}
+
+	ids = ids[keep:]
+
+	tx := s.db.Begin()
+	if err := tx.Error; err != nil {
+		return errors.WrapIf(err, "failed to begin transaction")
+	}
+
+	if err := tx.Where("snapshot_id IN (?)", ids).Delete(&findingModel{}).Error; err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete findings", "organizationId", organizationID)
+	}
+
+	if err := tx.Where("snapshot_id IN (?)", ids).Delete(&unscannedModel{}).Error; err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete unscanned targets", "organizationId", organizationID)
+	}
+
+	if err := tx.Where("id IN (?)", ids).Delete(&snapshotModel{}).Error; err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete snapshots", "organizationId", organizationID)
+	}
+
+	return errors.WrapIf(tx.Commit().Error, "failed to commit transaction")
+}
+
+func toSnapshot(model snapshotModel) vulnreport.Snapshot {
+	return vulnreport.Snapshot{
+		ID:             model.ID,
+		OrganizationID: model.OrganizationID,
+		CreatedAt:      model.CreatedAt,
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreportadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/banzaicloud/pipeline/internal/integratedservices/integratedserviceadapter"
+	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/banzaicloud/pipeline/pkg/k8sutil"
+	"github.com/banzaicloud/pipeline/pkg/security"
+)
+
+// ImageLister lists the images running in a cluster through its stored kubeconfig.
+type ImageLister struct {
+	clusterGetter integratedserviceadapter.ClusterGetter
+}
+
+// NewImageLister returns a new ImageLister.
+func NewImageLister(clusterGetter integratedserviceadapter.ClusterGetter) ImageLister {
+	return ImageLister{
+		clusterGetter: clusterGetter,
+	}
+}
+
+// ListRunningImages implements the vulnreport.ImageLister interface.
+func (l ImageLister) ListRunningImages(ctx context.Context, clusterID uint) ([]vulnreport.RunningImage, error) {
+	cluster, err := l.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get cluster")
+	}
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get kubeconfig")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(v1.PodRunning)).String(),
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list pods")
+	}
+
+	// the same image in the same release is reported only once
+	type imageKey struct {
+		namespace string
+		release   string
+		digest    string
+	}
+
+	found := make(map[imageKey]bool)
+	images := make([]vulnreport.RunningImage, 0)
+
+	for _, pod := range pods.Items {
+		release := pkgHelm.GetHelmReleaseName(pod.Labels)
+
+		for _, image := range k8sutil.GetPodImages(pod) {
+			key := imageKey{namespace: pod.Namespace, release: release, digest: image.Digest}
+			if found[key] {
+				continue
+			}
+			found[key] = true
+
+			images = append(images, vulnreport.RunningImage{
+				Namespace: pod.Namespace,
+				Release:   release,
+				Image: security.Image{
+					Name:   image.Name,
+					Tag:    image.Tag,
+					Digest: image.Digest,
+				},
+			})
+		}
+	}
+
+	return images, nil
+}
+
+// ImageScanner evaluates images with the scanner backend of a cluster.
+type ImageScanner struct {
+	clusterGetter integratedserviceadapter.ClusterGetter
+	imageScanner  anchore.ImageScanner
+}
+
+// NewImageScanner returns a new ImageScanner.
+func NewImageScanner(clusterGetter integratedserviceadapter.ClusterGetter, imageScanner anchore.ImageScanner) ImageScanner {
+	return ImageScanner{
+		clusterGetter: clusterGetter,
+		imageScanner:  imageScanner,
+	}
+}
+
+// GetImageVulnerabilities implements the vulnreport.ImageScanner interface.
+func (s ImageScanner) GetImageVulnerabilities(ctx context.Context, clusterID uint, image security.Image) (security.ImageVulnerabilities, error) {
+	cluster, err := s.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return security.ImageVulnerabilities{}, errors.WrapIf(err, "failed to get cluster")
+	}
+
+	return s.imageScanner.GetImageVulnerabilities(ctx, cluster, image)
+}
@@ -0,0 +1,131 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreportworkflow
+
+import (
+	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
+	"github.com/banzaicloud/pipeline/pkg/security"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+const ListOrganizationsActivityName = "security-image-rescan-list-organizations"
+
+type ListOrganizationsActivityInput struct{}
+
+const ListClustersActivityName = "security-image-rescan-list-clusters"
+
+type ListClustersActivityInput struct {
+	OrganizationID uint
+}
+
+const CreateSnapshotActivityName = "security-image-rescan-create-snapshot"
+
+type CreateSnapshotActivityInput struct {
+	OrganizationID uint
+}
+
+const ScanClusterActivityName = "security-image-rescan-scan-cluster"
+
+type ScanClusterActivityInput struct {
+	OrganizationID uint
+	SnapshotID     uint
+	Cluster        vulnreport.Cluster
+}
+
+const MarkClusterUnscannedActivityName = "security-image-rescan-mark-cluster-unscanned"
+
+type MarkClusterUnscannedActivityInput struct {
+	SnapshotID uint
+	Cluster    vulnreport.Cluster
+	Reason     string
+}
+
+const CompleteSnapshotActivityName = "security-image-rescan-complete-snapshot"
+
+type CompleteSnapshotActivityInput struct {
+	OrganizationID uint
+	SnapshotID     uint
+	KeepSnapshots  int
+}
+
+// RescanActivities implements the activities of the rescan workflow.
+type RescanActivities struct {
+	clusterFinder  vulnreport.ClusterFinder
+	clusterScanner vulnreport.ClusterScanner
+	store          vulnreport.Store
+}
+
+// NewRescanActivities returns a new RescanActivities.
+func NewRescanActivities(clusterFinder vulnreport.ClusterFinder, clusterScanner vulnreport.ClusterScanner, store vulnreport.Store) RescanActivities {
+	return RescanActivities{
+		clusterFinder:  clusterFinder,
+		clusterScanner: clusterScanner,
+		store:          store,
+	}
+}
+
+func (a RescanActivities) ListOrganizations(ctx context.Context, _ ListOrganizationsActivityInput) ([]uint, error) {
+	return a.clusterFinder.FindOrganizations(ctx)
+}
+
+func (a RescanActivities) ListClusters(ctx context.Context, input ListClustersActivityInput) ([]vulnreport.Cluster, error) {
+	return a.clusterFinder.FindClusters(ctx, input.OrganizationID)
+}
+
+func (a RescanActivities) CreateSnapshot(ctx context.Context, input CreateSnapshotActivityInput) (uint, error) {
+	snapshot, err := a.store.CreateSnapshot(ctx, input.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+
+	return snapshot.ID, nil
+}
+
+func (a RescanActivities) ScanCluster(ctx context.Context, input ScanClusterActivityInput) error {
+	// secrets of the scanner backends are stored per organization
+	ctx = auth.SetCurrentOrganizationID(ctx, input.OrganizationID)
+
+	// image scans can take a while, the workflow detects stuck scans from the missing heartbeats
+	return a.clusterScanner.ScanCluster(ctx, input.SnapshotID, input.Cluster, func(image security.Image) {
+		activity.RecordHeartbeat(ctx, image.Reference())
+	})
+}
+
+func (a RescanActivities) MarkClusterUnscanned(ctx context.Context, input MarkClusterUnscannedActivityInput) error {
+	// findings of a partially scanned cluster are unreliable as well
+	return a.store.AddUnscanned(ctx, input.SnapshotID, []vulnreport.Unscanned{
+		{
+			ClusterID:   input.Cluster.ID,
+			ClusterName: input.Cluster.Name,
+			Reason:      input.Reason,
+		},
+	})
+}
+
+func (a RescanActivities) CompleteSnapshot(ctx context.Context, input CompleteSnapshotActivityInput) error {
+	if err := a.store.CompleteSnapshot(ctx, input.SnapshotID); err != nil {
+		return err
+	}
+
+	if input.KeepSnapshots > 0 {
+		return a.store.DeleteSnapshots(ctx, input.OrganizationID, input.KeepSnapshots)
+	}
+
+	return nil
+}
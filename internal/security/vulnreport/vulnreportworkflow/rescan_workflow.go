@@ -0,0 +1,147 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vulnreportworkflow
+
+import (
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
+)
+
+// RescanWorkflowName is the name of the workflow re-evaluating the running images of every organization.
+const RescanWorkflowName = "security-image-rescan"
+
+// RescanWorkflowInput defines the inputs of the rescan workflow.
+type RescanWorkflowInput struct {
+	// KeepSnapshots is the number of snapshots kept per organization.
+	KeepSnapshots int
+
+	// ImageScanTimeout is the maximum duration of a single image scan.
+	ImageScanTimeout time.Duration
+}
+
+// RescanWorkflow re-evaluates every running image of the clusters with security scan enabled
+// and stores the results as a new snapshot per organization.
+func RescanWorkflow(ctx workflow.Context, input RescanWorkflowInput) error {
+	logger := workflow.GetLogger(ctx)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    5 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy: &cadence.RetryPolicy{
+			InitialInterval:    15 * time.Second,
+			BackoffCoefficient: 1.5,
+			MaximumInterval:    3 * time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	var organizationIDs []uint
+	if err := workflow.ExecuteActivity(ctx, ListOrganizationsActivityName, ListOrganizationsActivityInput{}).Get(ctx, &organizationIDs); err != nil {
+		return err
+	}
+
+	for _, organizationID := range organizationIDs {
+		if err := rescanOrganization(ctx, organizationID, input); err != nil {
+			// a failing organization should not prevent rescanning the others
+			logger.Sugar().Errorw("failed to rescan organization", "organizationId", organizationID, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func rescanOrganization(ctx workflow.Context, organizationID uint, input RescanWorkflowInput) error {
+	logger := workflow.GetLogger(ctx).Sugar().With("organizationId", organizationID)
+
+	var clusters []vulnreport.Cluster
+	{
+		activityInput := ListClustersActivityInput{
+			OrganizationID: organizationID,
+		}
+
+		if err := workflow.ExecuteActivity(ctx, ListClustersActivityName, activityInput).Get(ctx, &clusters); err != nil {
+			return err
+		}
+	}
+
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	var snapshotID uint
+	{
+		activityInput := CreateSnapshotActivityInput{
+			OrganizationID: organizationID,
+		}
+
+		if err := workflow.ExecuteActivity(ctx, CreateSnapshotActivityName, activityInput).Get(ctx, &snapshotID); err != nil {
+			return err
+		}
+	}
+
+	{
+		scanCtx := workflow.WithStartToCloseTimeout(ctx, 30*time.Minute)
+
+		// the activity heartbeats after every image
+		scanCtx = workflow.WithHeartbeatTimeout(scanCtx, input.ImageScanTimeout+5*time.Minute)
+
+		futures := make([]workflow.Future, 0, len(clusters))
+		for _, cluster := range clusters {
+			activityInput := ScanClusterActivityInput{
+				OrganizationID: organizationID,
+				SnapshotID:     snapshotID,
+				Cluster:        cluster,
+			}
+
+			futures = append(futures, workflow.ExecuteActivity(scanCtx, ScanClusterActivityName, activityInput))
+		}
+
+		for i, future := range futures {
+			if err := future.Get(ctx, nil); err != nil {
+				logger.Errorw("failed to scan cluster", "clusterId", clusters[i].ID, "error", err.Error())
+
+				// unreachable clusters are marked in the snapshot, so that their vulnerabilities are not reported as fixed
+				activityInput := MarkClusterUnscannedActivityInput{
+					SnapshotID: snapshotID,
+					Cluster:    clusters[i],
+					Reason:     err.Error(),
+				}
+
+				if err := workflow.ExecuteActivity(ctx, MarkClusterUnscannedActivityName, activityInput).Get(ctx, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	{
+		activityInput := CompleteSnapshotActivityInput{
+			OrganizationID: organizationID,
+			SnapshotID:     snapshotID,
+			KeepSnapshots:  input.KeepSnapshots,
+		}
+
+		if err := workflow.ExecuteActivity(ctx, CompleteSnapshotActivityName, activityInput).Get(ctx, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
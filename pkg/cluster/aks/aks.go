@@ -19,6 +19,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 )
@@ -46,6 +47,7 @@ type NodePoolCreate struct {
 	NodeInstanceType string            `json:"instanceType" yaml:"instanceType"`
 	VNetSubnetID     string            `json:"vnetSubnetID,omitempty" yaml:"vnetSubnetID,omitempty"`
 	Labels           map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints           []cluster.Taint   `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 // NodePoolUpdate describes Azure's node count of a UpdateCluster request
@@ -106,6 +108,10 @@ func (azure *CreateClusterAKS) Validate() error {
 		if err := pkgCommon.ValidateNodePoolLabels(npName, np.Labels); err != nil {
 			return err
 		}
+
+		if err := cluster.ValidateNodePoolTaints(npName, np.Taints); err != nil {
+			return err
+		}
 	}
 
 	if len(azure.KubernetesVersion) == 0 {
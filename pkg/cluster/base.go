@@ -21,6 +21,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/ekscluster"
 	"github.com/banzaicloud/pipeline/pkg/cluster/ack"
 	"github.com/banzaicloud/pipeline/pkg/cluster/aks"
@@ -34,17 +35,19 @@ import (
 
 // ### [ Cluster statuses ] ### //
 const (
-	Creating = "CREATING"
-	Running  = "RUNNING"
-	Updating = "UPDATING"
-	Deleting = "DELETING"
-	Warning  = "WARNING"
-	Error    = "ERROR"
-
-	CreatingMessage = "Cluster creation is in progress"
-	RunningMessage  = "Cluster is running"
-	UpdatingMessage = "Update is in progress"
-	DeletingMessage = "Termination is in progress"
+	Creating   = "CREATING"
+	Running    = "RUNNING"
+	Updating   = "UPDATING"
+	Deleting   = "DELETING"
+	Warning    = "WARNING"
+	Error      = "ERROR"
+	Hibernated = "HIBERNATED"
+
+	CreatingMessage   = "Cluster creation is in progress"
+	RunningMessage    = "Cluster is running"
+	UpdatingMessage   = "Update is in progress"
+	DeletingMessage   = "Termination is in progress"
+	HibernatedMessage = "Cluster is hibernated"
 )
 
 // Cloud constants
@@ -91,6 +94,9 @@ type CreateClusterRequest struct {
 	PostHooks    PostHooks                `json:"postHooks" yaml:"postHooks"`
 	Properties   *CreateClusterProperties `json:"properties" yaml:"properties" binding:"required"`
 	ScaleOptions *ScaleOptions            `json:"scaleOptions,omitempty" yaml:"scaleOptions,omitempty"`
+	Labels       map[string]string        `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	DeletionProtection bool `json:"deletionProtection,omitempty" yaml:"deletionProtection,omitempty"`
 }
 
 // CreateClusterProperties contains the cluster flavor specific properties.
@@ -158,6 +164,7 @@ type GetClusterStatusResponse struct {
 	Version       string                     `json:"version,omitempty"`
 	ResourceID    uint                       `json:"id"`
 	NodePools     map[string]*NodePoolStatus `json:"nodePools"`
+	Labels        map[string]string          `json:"labels,omitempty"`
 	pkgCommon.CreatorBaseFields
 
 	// If region not available fall back to Location
@@ -347,6 +354,9 @@ func (r *CreateClusterRequest) validateMainFields() error {
 			return pkgErrors.ErrorLocationEmpty
 		}
 	}
+	if err := cluster.ValidateClusterLabels(r.Labels); err != nil {
+		return err
+	}
 	return nil
 }
 
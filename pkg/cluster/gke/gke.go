@@ -19,6 +19,7 @@ import (
 
 	"emperror.dev/errors"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 )
@@ -52,6 +53,7 @@ type NodePool struct {
 	NodeInstanceType string            `json:"instanceType,omitempty" yaml:"instanceType,omitempty"`
 	Preemptible      bool              `json:"preemptible,omitempty" yaml:"preemptible,omitempty"`
 	Labels           map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints           []cluster.Taint   `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 // UpdateClusterGoogle describes Google's node fields of an UpdateCluster request
@@ -80,6 +82,10 @@ func validateNodePool(npName string, nodePool *NodePool) error {
 		return err
 	}
 
+	if err := cluster.ValidateNodePoolTaints(npName, nodePool.Taints); err != nil {
+		return err
+	}
+
 	return nil
 }
 
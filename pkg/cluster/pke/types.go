@@ -17,6 +17,7 @@ package pke
 import (
 	"github.com/pkg/errors"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/pkg/common"
 )
 
@@ -43,6 +44,10 @@ func (a *UpdateClusterPKE) Validate() error {
 		if err := common.ValidateNodePoolLabels(npName, np.Labels); err != nil {
 			return err
 		}
+
+		if err := cluster.ValidateNodePoolTaints(npName, np.Taints); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -58,6 +63,7 @@ type UpdateNodePool struct {
 	Count        int               `json:"count" yaml:"count"`
 	Subnets      Subnets           `json:"subnets,omitempty" yaml:"subnets,omitempty"`
 	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints       []cluster.Taint   `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 type Network struct {
@@ -84,6 +90,7 @@ type NodePool struct {
 	Provider       NodePoolProvider       `json:"provider" yaml:"provider" binding:"required"`
 	ProviderConfig map[string]interface{} `json:"providerConfig" yaml:"providerConfig" binding:"required"`
 	Labels         map[string]string      `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints         []cluster.Taint        `json:"taints,omitempty" yaml:"taints,omitempty"`
 	Autoscaling    bool                   `json:"autoscaling" yaml:"autoscaling"`
 }
 
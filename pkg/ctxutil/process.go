@@ -0,0 +1,32 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package ctxutil
+
+import (
+	"context"
+)
+
+// nolint: gochecknoglobals
+var contextProcessID = contextKey("process-id")
+
+// ProcessID fetches the ID of the process acting on a resource from a context (if any).
+func ProcessID(ctx context.Context) (string, bool) {
+	processID, ok := ctx.Value(contextProcessID).(string)
+	return processID, ok
+}
+
+// WithProcessID appends a process ID to a context.
+func WithProcessID(ctx context.Context, processID string) context.Context {
+	return context.WithValue(ctx, contextProcessID, processID)
+}
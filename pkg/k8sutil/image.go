@@ -0,0 +1,86 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ContainerImage describes an image of a running container.
+type ContainerImage struct {
+	Name   string
+	Tag    string
+	Digest string
+}
+
+// GetPodImages returns the images of the running containers of a pod.
+// Containers without a resolved image digest are skipped.
+func GetPodImages(pod v1.Pod) []ContainerImage {
+	images := make([]ContainerImage, 0)
+	for _, container := range pod.Status.ContainerStatuses {
+		fullDigest := strings.Split(container.ImageID, "@")
+		var digest string
+		if len(fullDigest) > 1 {
+			digest = fullDigest[1]
+		} else {
+			continue
+		}
+
+		named, ok := parseContainerImage(pod, container)
+		if !ok {
+			continue
+		}
+
+		image := ContainerImage{
+			Name:   reference.FamiliarName(named),
+			Digest: digest,
+		}
+
+		if tagged, ok := named.(reference.Tagged); ok {
+			image.Tag = tagged.Tag()
+		} else if _, ok := named.(reference.Digested); !ok {
+			image.Tag = "latest"
+		}
+
+		images = append(images, image)
+	}
+	return images
+}
+
+// parseContainerImage parses the image reference of a container.
+// Runtimes may report the image ID instead of the reference, so the image in the pod spec is used as a fallback.
+func parseContainerImage(pod v1.Pod, status v1.ContainerStatus) (reference.Named, bool) {
+	// image IDs would be parsed as a repository called sha256
+	if reference.DigestRegexp.FindString(status.Image) != status.Image {
+		if named, err := reference.ParseNormalizedNamed(status.Image); err == nil {
+			return named, true
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Name != status.Name {
+			continue
+		}
+
+		named, err := reference.ParseNormalizedNamed(container.Image)
+
+		return named, err == nil
+	}
+
+	return nil, false
+}
@@ -0,0 +1,55 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package k8sutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/pipeline/pkg/k8sutil"
+)
+
+func TestGetPodImages(t *testing.T) {
+	const digest = "sha256:4b1ad6b1b2a9e1a3b2b5a9d3d1c6c8f7e1e2d3c4b5a6978877665544332211aa"
+
+	pod := v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "by-id", Image: "quay.io/team/app:2.1"},
+			},
+		},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "untagged", Image: "nginx", ImageID: "docker-pullable://nginx@" + digest},
+				{Name: "registry-port", Image: "registry:5000/app:1.0", ImageID: "docker-pullable://registry:5000/app@" + digest},
+				{Name: "digest", Image: "nginx@" + digest, ImageID: "docker-pullable://nginx@" + digest},
+				{Name: "by-id", Image: digest, ImageID: "docker-pullable://quay.io/team/app@" + digest},
+				{Name: "unresolved", Image: "nginx:1.19", ImageID: "docker://" + digest},
+			},
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]k8sutil.ContainerImage{
+			{Name: "nginx", Tag: "latest", Digest: digest},
+			{Name: "registry:5000/app", Tag: "1.0", Digest: digest},
+			{Name: "nginx", Digest: digest},
+			{Name: "quay.io/team/app", Tag: "2.1", Digest: digest},
+		},
+		k8sutil.GetPodImages(pod),
+	)
+}
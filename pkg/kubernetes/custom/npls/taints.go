@@ -0,0 +1,169 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// nodePoolNameLabelKey selects the nodes of a node pool.
+	nodePoolNameLabelKey = "nodepool.banzaicloud.io/name"
+
+	// ManagedTaintsAnnotationKey records the taints on a node that are managed through the node pool.
+	ManagedTaintsAnnotationKey = "nodepool.banzaicloud.io/managed-taints"
+)
+
+// nolint: gochecknoglobals
+var nodeGVR = schema.GroupVersionResource{
+	Version:  "v1",
+	Resource: "nodes",
+}
+
+// SyncTaints reconciles the taints on every node of a node pool.
+// Taints previously applied through the node pool but missing from the list are removed,
+// taints added manually to the nodes are left untouched.
+func (m Manager) SyncTaints(poolName string, taints []corev1.Taint) error {
+	client := m.client.Resource(nodeGVR)
+
+	list, err := client.List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", nodePoolNameLabelKey, poolName),
+	})
+	if err != nil {
+		return errors.WrapWithDetails(err, "failed to list node pool nodes", "poolName", poolName)
+	}
+
+	errs := make([]error, 0, len(list.Items))
+
+	for _, item := range list.Items {
+		name := item.GetName()
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			obj, err := client.Get(name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			var node corev1.Node
+
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &node)
+			if err != nil {
+				return err
+			}
+
+			if !reconcileNodeTaints(&node, taints) {
+				return nil
+			}
+
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&node)
+			if err != nil {
+				return err
+			}
+
+			_, err = client.Update(&unstructured.Unstructured{Object: content}, metav1.UpdateOptions{})
+
+			return err
+		})
+		if err != nil {
+			errs = append(errs, errors.WrapWithDetails(err, "failed to update node taints", "node", name))
+		}
+	}
+
+	return errors.Combine(errs...)
+}
+
+// reconcileNodeTaints replaces the managed taints of a node with the desired ones
+// and reports whether the node has changed.
+func reconcileNodeTaints(node *corev1.Node, desired []corev1.Taint) bool {
+	managed := make(map[string]bool)
+	for _, id := range strings.Split(node.Annotations[ManagedTaintsAnnotationKey], ",") {
+		if id != "" {
+			managed[id] = true
+		}
+	}
+
+	desiredIDs := make([]string, 0, len(desired))
+	for _, taint := range desired {
+		id := taintID(taint)
+
+		managed[id] = true
+		desiredIDs = append(desiredIDs, id)
+	}
+
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints)+len(desired))
+	for _, taint := range node.Spec.Taints {
+		if !managed[taintID(taint)] {
+			taints = append(taints, taint)
+		}
+	}
+
+	for _, taint := range desired {
+		for _, current := range node.Spec.Taints {
+			if taintID(current) == taintID(taint) && current.Value == taint.Value {
+				taint.TimeAdded = current.TimeAdded
+			}
+		}
+
+		taints = append(taints, taint)
+	}
+
+	sort.Strings(desiredIDs)
+	annotation := strings.Join(desiredIDs, ",")
+
+	changed := annotation != node.Annotations[ManagedTaintsAnnotationKey] || !sameTaints(node.Spec.Taints, taints)
+
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string, 1)
+	}
+
+	node.Annotations[ManagedTaintsAnnotationKey] = annotation
+	node.Spec.Taints = taints
+
+	return changed
+}
+
+func taintID(taint corev1.Taint) string {
+	return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+}
+
+func sameTaints(a []corev1.Taint, b []corev1.Taint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	values := make(map[string]string, len(a))
+	for _, taint := range a {
+		values[taintID(taint)] = taint.Value
+	}
+
+	for _, taint := range b {
+		value, ok := values[taintID(taint)]
+		if !ok || value != taint.Value {
+			return false
+		}
+	}
+
+	return true
+}
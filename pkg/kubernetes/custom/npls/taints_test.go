@@ -0,0 +1,78 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileNodeTaints(t *testing.T) {
+	gpu := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	batch := corev1.Taint{Key: "batch", Effect: corev1.TaintEffectNoExecute}
+	manual := corev1.Taint{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}
+
+	t.Run("AddTaints", func(t *testing.T) {
+		node := corev1.Node{
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{manual}},
+		}
+
+		assert.True(t, reconcileNodeTaints(&node, []corev1.Taint{gpu, batch}))
+		assert.Equal(t, []corev1.Taint{manual, gpu, batch}, node.Spec.Taints)
+		assert.Equal(t, "batch:NoExecute,dedicated:NoSchedule", node.Annotations[ManagedTaintsAnnotationKey])
+	})
+
+	t.Run("RemoveManagedTaints", func(t *testing.T) {
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{ManagedTaintsAnnotationKey: "batch:NoExecute,dedicated:NoSchedule"},
+			},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{manual, gpu, batch}},
+		}
+
+		assert.True(t, reconcileNodeTaints(&node, []corev1.Taint{gpu}))
+		assert.Equal(t, []corev1.Taint{manual, gpu}, node.Spec.Taints)
+		assert.Equal(t, "dedicated:NoSchedule", node.Annotations[ManagedTaintsAnnotationKey])
+	})
+
+	t.Run("ReplaceValue", func(t *testing.T) {
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{ManagedTaintsAnnotationKey: "dedicated:NoSchedule"},
+			},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{gpu}},
+		}
+
+		cpu := corev1.Taint{Key: "dedicated", Value: "cpu", Effect: corev1.TaintEffectNoSchedule}
+
+		assert.True(t, reconcileNodeTaints(&node, []corev1.Taint{cpu}))
+		assert.Equal(t, []corev1.Taint{cpu}, node.Spec.Taints)
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{ManagedTaintsAnnotationKey: "dedicated:NoSchedule"},
+			},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{manual, gpu}},
+		}
+
+		assert.False(t, reconcileNodeTaints(&node, []corev1.Taint{gpu}))
+		assert.Equal(t, []corev1.Taint{manual, gpu}, node.Spec.Taints)
+	})
+}
@@ -36,6 +36,9 @@ type Process interface {
 
 	// StartActivity records a new activity of a process.
 	StartActivity(ctx workflow.Context, typ string) Activity
+
+	// StartActivityWithLog records a new activity of a process with a log message (eg. the affected resource).
+	StartActivityWithLog(ctx workflow.Context, typ string, log string) Activity
 }
 
 // Activity is a short lived part of a Process.
@@ -143,6 +146,10 @@ func (p process) Finish(ctx workflow.Context, err error) {
 }
 
 func (p process) StartActivity(ctx workflow.Context, typ string) Activity {
+	return p.StartActivityWithLog(ctx, typ, "")
+}
+
+func (p process) StartActivityWithLog(ctx workflow.Context, typ string, log string) Activity {
 	ctx = withContext(ctx)
 
 	winfo := workflow.GetInfo(ctx)
@@ -150,6 +157,7 @@ func (p process) StartActivity(ctx workflow.Context, typ string) Activity {
 	activityInput := processActivityActivityInput{
 		ProcessID: winfo.WorkflowExecution.ID,
 		Type:      typ,
+		Log:       log,
 		Timestamp: workflow.Now(ctx),
 		Status:    running,
 	}
@@ -191,7 +199,11 @@ func (a processActivity) Finish(ctx workflow.Context, err error) {
 			activityInput.Status = failed
 		}
 
-		activityInput.Log = err.Error()
+		if activityInput.Log != "" {
+			activityInput.Log = activityInput.Log + ": " + err.Error()
+		} else {
+			activityInput.Log = err.Error()
+		}
 	} else {
 		activityInput.Status = finished
 	}
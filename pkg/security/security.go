@@ -126,3 +126,69 @@ type ReleaseWhiteListItem struct {
 	Reason string `json:"reason"`
 	Regexp string `json:"regexp,omitempty"`
 }
+
+// Image identifies a container image to be scanned.
+type Image struct {
+	Name   string `json:"name"`
+	Tag    string `json:"tag,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// Reference returns the reference of the image, preferring the digest over the tag.
+func (i Image) Reference() string {
+	switch {
+	case i.Digest != "":
+		return i.Name + "@" + i.Digest
+	case i.Tag != "":
+		return i.Name + ":" + i.Tag
+	default:
+		return i.Name
+	}
+}
+
+// ImageVulnerabilities is the result of an image scan.
+type ImageVulnerabilities struct {
+	Scanner         string          `json:"scanner"`
+	Image           Image           `json:"image"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// Vulnerability is a single vulnerability found in an image, independently of the scanner backend.
+type Vulnerability struct {
+	ID             string `json:"id"`
+	Severity       string `json:"severity"`
+	Package        string `json:"package"`
+	PackageVersion string `json:"packageVersion"`
+	FixedVersion   string `json:"fixedVersion,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+// Admission modes of the image validator webhook.
+const (
+	// AdmissionModeEnforce rejects workloads failing the policy check.
+	AdmissionModeEnforce = "enforce"
+
+	// AdmissionModeWarn admits workloads failing the policy check with a warning.
+	AdmissionModeWarn = "warn"
+
+	// AdmissionModeAudit admits workloads failing the policy check and only records the decision.
+	AdmissionModeAudit = "audit"
+)
+
+const (
+	// AdmissionModeLabel is the namespace (and scan log) label holding the admission mode.
+	AdmissionModeLabel = "scan-mode"
+
+	// BreakGlassAnnotation admits a workload regardless of the policy check when set to "true".
+	BreakGlassAnnotation = "security.banzaicloud.io/break-glass"
+)
+
+// IsValidAdmissionMode checks whether the admission mode is supported.
+func IsValidAdmissionMode(mode string) bool {
+	switch mode {
+	case AdmissionModeEnforce, AdmissionModeWarn, AdmissionModeAudit:
+		return true
+	default:
+		return false
+	}
+}
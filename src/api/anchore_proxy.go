@@ -17,6 +17,7 @@ package api
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -28,13 +29,17 @@ import (
 
 	"github.com/banzaicloud/pipeline/internal/anchore"
 	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	security "github.com/banzaicloud/pipeline/internal/security"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 )
 
 const pipelineUserAgent = "Pipeline/go"
 
 type AnchoreProxy struct {
-	basePath       string
-	configProvider anchore.ConfigProvider
+	basePath        string
+	configProvider  anchore.ConfigProvider
+	scannerProvider security.ScannerProvider
 
 	errorHandler common.ErrorHandler
 	logger       common.Logger
@@ -43,13 +48,15 @@ type AnchoreProxy struct {
 func NewAnchoreProxy(
 	basePath string,
 	configProvider anchore.ConfigProvider,
+	scannerProvider security.ScannerProvider,
 
 	errorHandler common.ErrorHandler,
 	logger common.Logger,
 ) AnchoreProxy {
 	return AnchoreProxy{
-		basePath:       basePath,
-		configProvider: configProvider,
+		basePath:        basePath,
+		configProvider:  configProvider,
+		scannerProvider: scannerProvider,
 
 		errorHandler: errorHandler,
 		logger:       logger,
@@ -76,6 +83,31 @@ func (ap AnchoreProxy) Proxy() gin.HandlerFunc {
 			return
 		}
 
+		// policies and the rest of the Anchore API are only available for clusters scanned by Anchore
+		scanner, err := ap.scannerProvider.GetScanner(c.Request.Context(), clusterID)
+		if integratedservices.IsIntegratedServiceNotFoundError(err) {
+			c.AbortWithStatusJSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "security scan is not enabled for the cluster",
+				Error:   errors.Cause(err).Error(),
+			})
+			return
+		}
+		if err != nil {
+			ap.errorHandler.HandleContext(c.Request.Context(), err)
+
+			c.JSON(http.StatusInternalServerError, c.AbortWithError(http.StatusInternalServerError, err))
+			return
+		}
+		if scanner != security.ScannerAnchore {
+			c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "the Anchore API is only available for clusters using the anchore scanner",
+				Error:   fmt.Sprintf("cluster is scanned by %s", scanner),
+			})
+			return
+		}
+
 		proxyPath := c.Param("proxyPath")
 
 		proxy, err := ap.buildReverseProxy(c.Request.Context(), proxyPath, orgID, clusterID)
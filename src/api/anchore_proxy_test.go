@@ -0,0 +1,80 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	security "github.com/banzaicloud/pipeline/internal/security"
+)
+
+type fakeScannerProvider struct {
+	scanner string
+	err     error
+}
+
+func (p fakeScannerProvider) GetScanner(_ context.Context, _ uint) (string, error) {
+	return p.scanner, p.err
+}
+
+type fakeIntegratedServiceNotFoundError struct{}
+
+func (fakeIntegratedServiceNotFoundError) Error() string {
+	return "integrated service not found"
+}
+
+func (fakeIntegratedServiceNotFoundError) IntegratedServiceNotFound() bool {
+	return true
+}
+
+func TestAnchoreProxy_Proxy_ScannerBackend(t *testing.T) {
+	tests := map[string]struct {
+		scannerProvider fakeScannerProvider
+		expectedStatus  int
+	}{
+		"trivy": {
+			scannerProvider: fakeScannerProvider{scanner: security.ScannerTrivy},
+			expectedStatus:  http.StatusBadRequest,
+		},
+		"not enabled": {
+			scannerProvider: fakeScannerProvider{err: fakeIntegratedServiceNotFoundError{}},
+			expectedStatus:  http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			proxy := NewAnchoreProxy("/api", nil, test.scannerProvider, common.NoopErrorHandler{}, common.NoopLogger{})
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Any("/api/v1/orgs/:orgid/clusters/:id/anchore/*proxyPath", proxy.Proxy())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/orgs/1/clusters/2/anchore/policies", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
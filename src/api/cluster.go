@@ -28,9 +28,14 @@ import (
 	"go.uber.org/cadence/client"
 	v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	clusterAuth "github.com/banzaicloud/pipeline/internal/cluster/auth"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpreflight"
 	eksdriver "github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksprovider/driver"
 	"github.com/banzaicloud/pipeline/internal/cluster/resourcesummary"
 	"github.com/banzaicloud/pipeline/internal/global"
@@ -66,6 +71,44 @@ type ClusterAPI struct {
 	helmService        cluster.HelmService
 	authConfig         auth.Config
 	clientSecretGetter clusterAuth.ClusterClientSecretGetter
+	clusterLabels      ClusterLabelStore
+	clusterTemplates   ClusterTemplateRenderer
+	clusterPolicies    ClusterPolicyValidator
+	clusterPreflight   ClusterPreflightChecker
+}
+
+// ClusterLabelStore reads cluster labels.
+type ClusterLabelStore interface {
+	// GetLabels returns the labels of a cluster.
+	GetLabels(ctx context.Context, clusterID uint) (map[string]string, error)
+
+	// GetLabelsByOrganization returns the labels of every cluster in an organization indexed by cluster ID.
+	GetLabelsByOrganization(ctx context.Context, orgID uint) (map[uint]map[string]string, error)
+}
+
+// ClusterPolicyValidator checks cluster requests against organization policies.
+type ClusterPolicyValidator interface {
+	// ValidateClusterCreate checks a new cluster against the policy of an organization.
+	ValidateClusterCreate(ctx context.Context, organizationID uint, spec clusterpolicy.ClusterSpec) error
+
+	// ValidateClusterUpdate checks the desired state of an updated cluster against the policy of an organization.
+	ValidateClusterUpdate(ctx context.Context, organizationID uint, spec clusterpolicy.ClusterSpec) error
+}
+
+// ClusterPreflightChecker runs the synchronous checks of a cluster creation without creating the cluster.
+type ClusterPreflightChecker interface {
+	// Check runs every check on a cluster creation request and returns all the problems found.
+	Check(ctx context.Context, request clusterpreflight.Request) []string
+}
+
+// ClusterTemplateRenderer renders cluster creation requests from cluster templates.
+type ClusterTemplateRenderer interface {
+	// RenderClusterTemplate renders a cluster creation request from a cluster template version.
+	// Version 0 renders the latest version.
+	RenderClusterTemplate(ctx context.Context, organizationID uint, id uint, version int, params map[string]interface{}) (clustertemplate.RenderedClusterTemplate, error)
+
+	// RecordClusterTemplateUsage records that a cluster was created from a cluster template version.
+	RecordClusterTemplateUsage(ctx context.Context, usage clustertemplate.ClusterTemplateUsage) error
 }
 
 type ClusterCreators struct {
@@ -100,6 +143,10 @@ func NewClusterAPI(
 	helmService cluster.HelmService,
 	authConfig auth.Config,
 	clientSecretGetter clusterAuth.ClusterClientSecretGetter,
+	clusterLabels ClusterLabelStore,
+	clusterTemplates ClusterTemplateRenderer,
+	clusterPolicies ClusterPolicyValidator,
+	clusterPreflight ClusterPreflightChecker,
 ) *ClusterAPI {
 	return &ClusterAPI{
 		clusterManager:          clusterManager,
@@ -115,6 +162,10 @@ func NewClusterAPI(
 		helmService:             helmService,
 		authConfig:              authConfig,
 		clientSecretGetter:      clientSecretGetter,
+		clusterLabels:           clusterLabels,
+		clusterTemplates:        clusterTemplates,
+		clusterPolicies:         clusterPolicies,
+		clusterPreflight:        clusterPreflight,
 	}
 }
 
@@ -183,6 +234,17 @@ func (a *ClusterAPI) GetClusters(c *gin.Context) {
 
 	logger.Info("fetching clusters")
 
+	selector, err := intCluster.ParseLabelSelector(c.Query("labelSelector"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid label selector",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
 	clusters, err := a.clusterManager.GetClusters(context.Background(), organizationID)
 	if err != nil {
 		logger.Errorf("error listing clusters: %s", err.Error())
@@ -196,16 +258,34 @@ func (a *ClusterAPI) GetClusters(c *gin.Context) {
 		return
 	}
 
+	clusterLabels, err := a.clusterLabels.GetLabelsByOrganization(c.Request.Context(), organizationID)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing cluster labels",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
 	response := make([]pkgCluster.GetClusterStatusResponse, 0)
 
 	for _, c := range clusters {
 		logger := logger.WithField("cluster", c.GetName())
 
+		if !selector.Matches(labels.Set(clusterLabels[c.GetID()])) {
+			continue
+		}
+
 		status, err := c.GetStatus()
 		if err != nil {
 			// TODO we want skip or return error?
 			logger.Errorf("get cluster status failed: %s", err.Error())
 		} else {
+			status.Labels = clusterLabels[c.GetID()]
 			response = append(response, *status)
 		}
 	}
@@ -0,0 +1,230 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/src/api/common"
+)
+
+type eventsQuery struct {
+	Namespace          string `form:"namespace"`
+	InvolvedObjectKind string `form:"involvedObjectKind"`
+	InvolvedObjectName string `form:"involvedObjectName"`
+	Type               string `form:"type"`
+	Watch              bool   `form:"watch"`
+}
+
+// fieldSelector returns the field selector matching the events of the query.
+func (q eventsQuery) fieldSelector() string {
+	// selectors are kept in a fixed order (fields.Set does not guarantee one)
+	var selectors []fields.Selector
+
+	if q.InvolvedObjectKind != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("involvedObject.kind", q.InvolvedObjectKind))
+	}
+
+	if q.InvolvedObjectName != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("involvedObject.name", q.InvolvedObjectName))
+	}
+
+	if q.Type != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("type", q.Type))
+	}
+
+	return fields.AndSelectors(selectors...).String()
+}
+
+// List lists kubernetes events, or watches them when the watch query parameter is set.
+func (a *API) List(c *gin.Context) {
+	var query eventsQuery
+	if err := c.BindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to parse query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if query.Type != "" && query.Type != corev1.EventTypeNormal && query.Type != corev1.EventTypeWarning {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to parse query",
+			Error:   "type must be Normal or Warning",
+		})
+		return
+	}
+
+	_, client, ok := common.KubernetesClientFromRequest(c, a.clusterGetter, a.clientFactory, a.errorHandler)
+	if !ok {
+		return
+	}
+
+	events := client.CoreV1().Events(query.Namespace)
+
+	eventList, err := events.List(metav1.ListOptions{FieldSelector: query.fieldSelector()})
+	if err != nil {
+		a.errorHandler.Handle(errors.Wrap(err, "failed to list events"))
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error listing events",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	sort.SliceStable(eventList.Items, func(i, j int) bool {
+		return eventTime(eventList.Items[i]).Before(eventTime(eventList.Items[j]))
+	})
+
+	if !query.Watch {
+		response := pipeline.KubernetesEventList{
+			Events: make([]pipeline.KubernetesEvent, 0, len(eventList.Items)),
+		}
+
+		for _, event := range eventList.Items {
+			response.Events = append(response.Events, eventToModel(event))
+		}
+
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	watcher, err := events.Watch(metav1.ListOptions{
+		FieldSelector:   query.fieldSelector(),
+		ResourceVersion: eventList.ResourceVersion,
+	})
+	if err != nil {
+		a.errorHandler.Handle(errors.Wrap(err, "failed to watch events"))
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error watching events",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer watcher.Stop()
+
+	writer := common.NewStreamWriter(c, "application/x-ndjson")
+
+	// existing events are sent first so that clients do not have to list them separately
+	for _, event := range eventList.Items {
+		if err := writeWatchMessage(writer, watch.Added, event); err != nil {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case watchEvent, ok := <-watcher.ResultChan():
+			if !ok {
+				// the API server closed the watch, clients are expected to reconnect
+				return
+			}
+
+			switch watchEvent.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+				event, ok := watchEvent.Object.(*corev1.Event)
+				if !ok {
+					continue
+				}
+
+				if err := writeWatchMessage(writer, watchEvent.Type, *event); err != nil {
+					return
+				}
+
+			case watch.Error:
+				err := errors.New("watching events failed")
+				if status, ok := watchEvent.Object.(*metav1.Status); ok {
+					err = errors.Errorf("watching events failed: %s", status.Message)
+				}
+
+				writer.WriteError(err)
+				return
+			}
+		}
+	}
+}
+
+func writeWatchMessage(writer *common.StreamWriter, eventType watch.EventType, event corev1.Event) error {
+	message, err := json.Marshal(pipeline.KubernetesEventWatchMessage{
+		Type:  string(eventType),
+		Event: eventToModel(event),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+
+	return writer.Write("event", message)
+}
+
+// eventTime returns the time an event was last observed.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func eventToModel(event corev1.Event) pipeline.KubernetesEvent {
+	model := pipeline.KubernetesEvent{
+		Namespace: event.Namespace,
+		Name:      event.Name,
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Message:   event.Message,
+		InvolvedObject: pipeline.KubernetesEventObjectReference{
+			Kind:      event.InvolvedObject.Kind,
+			Namespace: event.InvolvedObject.Namespace,
+			Name:      event.InvolvedObject.Name,
+			FieldPath: event.InvolvedObject.FieldPath,
+		},
+		Source: event.Source.Component,
+		Count:  event.Count,
+	}
+
+	if !event.FirstTimestamp.IsZero() {
+		model.FirstTimestamp = event.FirstTimestamp.Time
+	}
+
+	model.LastTimestamp = eventTime(event)
+
+	return model
+}
@@ -0,0 +1,94 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+)
+
+func TestEventsQuery_FieldSelector(t *testing.T) {
+	assert.Equal(t, "", eventsQuery{Namespace: "default"}.fieldSelector())
+
+	assert.Equal(
+		t,
+		"involvedObject.kind=Pod,involvedObject.name=nginx,type=Warning",
+		eventsQuery{
+			InvolvedObjectKind: "Pod",
+			InvolvedObjectName: "nginx",
+			Type:               "Warning",
+		}.fieldSelector(),
+	)
+}
+
+func TestEventToModel(t *testing.T) {
+	first := time.Date(2020, 5, 20, 10, 0, 0, 0, time.UTC)
+	last := first.Add(5 * time.Minute)
+
+	event := corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "nginx.1610f1e0bdbd8a2c",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "default",
+			Name:      "nginx",
+			FieldPath: "spec.containers{nginx}",
+		},
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Source:         corev1.EventSource{Component: "kubelet"},
+		FirstTimestamp: metav1.Time{Time: first},
+		LastTimestamp:  metav1.Time{Time: last},
+		Count:          3,
+		Type:           corev1.EventTypeWarning,
+	}
+
+	assert.Equal(
+		t,
+		pipeline.KubernetesEvent{
+			Namespace: "default",
+			Name:      "nginx.1610f1e0bdbd8a2c",
+			Type:      "Warning",
+			Reason:    "BackOff",
+			Message:   "Back-off restarting failed container",
+			InvolvedObject: pipeline.KubernetesEventObjectReference{
+				Kind:      "Pod",
+				Namespace: "default",
+				Name:      "nginx",
+				FieldPath: "spec.containers{nginx}",
+			},
+			Source:         "kubelet",
+			Count:          3,
+			FirstTimestamp: first,
+			LastTimestamp:  last,
+		},
+		eventToModel(event),
+	)
+
+	// events reported through the events.k8s.io API only have an event time
+	event.FirstTimestamp = metav1.Time{}
+	event.LastTimestamp = metav1.Time{}
+	event.EventTime = metav1.MicroTime{Time: last}
+
+	assert.Equal(t, last, eventToModel(event).LastTimestamp)
+}
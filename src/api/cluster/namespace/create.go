@@ -0,0 +1,180 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// Create creates a kubernetes namespace with the resources managed by Pipeline.
+func (a *API) Create(c *gin.Context) {
+	var request pipeline.CreateNamespaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	client, orgID, ok := a.getClient(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.service.CreateNamespace(ctx, client, orgID, clusternamespace.Request{
+		Name:     request.Name,
+		Template: request.Template,
+		Spec:     SpecFromModel(request.Spec),
+	})
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusCreated)
+}
+
+// Update replaces the settings Pipeline manages on a kubernetes namespace.
+func (a *API) Update(c *gin.Context) {
+	var request pipeline.UpdateNamespaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	client, orgID, ok := a.getClient(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.service.UpdateNamespace(ctx, client, orgID, clusternamespace.Request{
+		Name:     c.Param("namespace"),
+		Template: request.Template,
+		Spec:     SpecFromModel(request.Spec),
+	})
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (a *API) handleError(c *gin.Context, err error) {
+	var validationErr interface {
+		Validation() bool
+		Violations() []string
+	}
+	if errors.As(err, &validationErr) && validationErr.Validation() {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   strings.Join(validationErr.Violations(), "; "),
+		})
+		return
+	}
+
+	var notFoundErr interface{ NotFound() bool }
+	if errors.As(err, &notFoundErr) && notFoundErr.NotFound() {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusNotFound, err)
+		return
+	}
+
+	var conflictErr interface{ Conflict() bool }
+	if errors.As(err, &conflictErr) && conflictErr.Conflict() {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusConflict, err)
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error applying namespace",
+		Error:   err.Error(),
+	})
+}
+
+// SpecFromModel converts a namespace spec API model to a namespace spec.
+func SpecFromModel(model pipeline.NamespaceSpec) clusternamespace.Spec {
+	spec := clusternamespace.Spec{
+		Labels:        model.Labels,
+		Annotations:   model.Annotations,
+		ResourceQuota: model.ResourceQuota,
+		LimitRange: clusternamespace.LimitRange{
+			Default:        model.LimitRange.Default,
+			DefaultRequest: model.LimitRange.DefaultRequest,
+			Max:            model.LimitRange.Max,
+			Min:            model.LimitRange.Min,
+		},
+		Vault: clusternamespace.VaultSettings{
+			DisableSecretInjection: model.Vault.DisableSecretInjection,
+		},
+		NetworkPolicy: clusternamespace.NetworkPolicySettings{
+			Isolation: model.NetworkPolicy.Isolation,
+		},
+	}
+
+	for _, binding := range model.RoleBindings {
+		spec.RoleBindings = append(spec.RoleBindings, clusternamespace.RoleBinding{
+			OrganizationRole: binding.OrganizationRole,
+			NamespaceRole:    binding.NamespaceRole,
+		})
+	}
+
+	return spec
+}
+
+// SpecToModel converts a namespace spec to a namespace spec API model.
+func SpecToModel(spec clusternamespace.Spec) pipeline.NamespaceSpec {
+	model := pipeline.NamespaceSpec{
+		Labels:        spec.Labels,
+		Annotations:   spec.Annotations,
+		ResourceQuota: spec.ResourceQuota,
+		LimitRange: pipeline.NamespaceLimitRange{
+			Default:        spec.LimitRange.Default,
+			DefaultRequest: spec.LimitRange.DefaultRequest,
+			Max:            spec.LimitRange.Max,
+			Min:            spec.LimitRange.Min,
+		},
+		Vault: pipeline.NamespaceVaultSettings{
+			DisableSecretInjection: spec.Vault.DisableSecretInjection,
+		},
+		NetworkPolicy: pipeline.NamespaceNetworkPolicy{
+			Isolation: spec.NetworkPolicy.Isolation,
+		},
+	}
+
+	for _, binding := range spec.RoleBindings {
+		model.RoleBindings = append(model.RoleBindings, pipeline.NamespaceRoleBinding{
+			OrganizationRole: binding.OrganizationRole,
+			NamespaceRole:    binding.NamespaceRole,
+		})
+	}
+
+	return model
+}
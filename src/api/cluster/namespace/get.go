@@ -0,0 +1,61 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// Get returns a kubernetes namespace with its labels and annotations.
+func (a *API) Get(c *gin.Context) {
+	client, _, ok := a.getClient(c)
+	if !ok {
+		return
+	}
+
+	namespace, err := client.CoreV1().Namespaces().Get(c.Param("namespace"), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "namespace not found",
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(errors.Wrap(err, "failed to get namespace"))
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error getting namespace",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline.NamespaceDetails{
+		Name:        namespace.Name,
+		Status:      string(namespace.Status.Phase),
+		Labels:      namespace.Labels,
+		Annotations: namespace.Annotations,
+	})
+}
@@ -15,31 +15,61 @@
 package namespace
 
 import (
+	"context"
+
 	"emperror.dev/emperror"
 	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/kubernetes"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace"
 	"github.com/banzaicloud/pipeline/src/api/common"
 )
 
+// Service creates and updates namespaces along with the resources managed by Pipeline.
+type Service interface {
+	// CreateNamespace creates a namespace along with the resources described by the request.
+	CreateNamespace(ctx context.Context, client kubernetes.Interface, organizationID uint, request clusternamespace.Request) error
+
+	// UpdateNamespace updates an existing namespace and its resources to match the request.
+	UpdateNamespace(ctx context.Context, client kubernetes.Interface, organizationID uint, request clusternamespace.Request) error
+}
+
 type API struct {
 	clusterGetter common.ClusterGetter
 	clientFactory common.ClientFactory
+	service       Service
 	errorHandler  emperror.Handler
 }
 
 func NewAPI(
 	clusterGetter common.ClusterGetter,
 	clientFactory common.ClientFactory,
+	service Service,
 	errorHandler emperror.Handler,
 ) *API {
 	return &API{
 		clusterGetter: clusterGetter,
 		clientFactory: clientFactory,
+		service:       service,
 		errorHandler:  errorHandler,
 	}
 }
 
 func (a *API) RegisterRoutes(r gin.IRouter) {
 	r.DELETE(":namespace", a.Delete)
+	r.GET(":namespace", a.Get)
+	r.PUT(":namespace", a.Update)
 	r.GET("", a.List)
+	r.POST("", a.Create)
+}
+
+// getClient returns a Kubernetes client for the cluster of the request along with the organization ID.
+// It replies to the client on failure and returns false.
+func (a *API) getClient(c *gin.Context) (kubernetes.Interface, uint, bool) {
+	cluster, client, ok := common.KubernetesClientFromRequest(c, a.clusterGetter, a.clientFactory, a.errorHandler)
+	if !ok {
+		return nil, 0, false
+	}
+
+	return client, cluster.GetOrganizationId(), true
 }
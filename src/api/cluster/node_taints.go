@@ -0,0 +1,37 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+func convertNodeTaints(taints []pipeline.NodeTaint) []intCluster.Taint {
+	if len(taints) == 0 {
+		return nil
+	}
+
+	result := make([]intCluster.Taint, 0, len(taints))
+	for _, taint := range taints {
+		result = append(result, intCluster.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: taint.Effect,
+		})
+	}
+
+	return result
+}
@@ -95,6 +95,113 @@ func clientPKEClusterHTTPProxyOptionsToPKEHTTPProxyOptions(o pipeline.PkeCluster
 	}
 }
 
+// NewCreatePKEOnAzureClusterRequest reconstructs a cluster creation request from a stored PKE on Azure cluster.
+func NewCreatePKEOnAzureClusterRequest(c pke.Cluster) CreatePKEOnAzureClusterRequest {
+	var accessPoints []string
+	for _, ap := range c.AccessPoints {
+		accessPoints = append(accessPoints, ap.Name)
+	}
+
+	var apiServerAccessPoints []string
+	for _, ap := range c.APIServerAccessPoints {
+		apiServerAccessPoints = append(apiServerAccessPoints, string(ap))
+	}
+
+	nodepools := make([]pipeline.PkeOnAzureNodePool, len(c.NodePools))
+	for i, np := range c.NodePools {
+		var taints []pipeline.NodeTaint
+		for _, taint := range np.Taints {
+			taints = append(taints, pipeline.NodeTaint{
+				Key:    taint.Key,
+				Value:  taint.Value,
+				Effect: taint.Effect,
+			})
+		}
+
+		nodepools[i] = pipeline.PkeOnAzureNodePool{
+			Name:   np.Name,
+			Roles:  np.Roles,
+			Taints: taints,
+			Subnet: pipeline.PkeOnAzureNodePoolSubnet{
+				Name: np.Subnet.Name,
+			},
+			Zones:        np.Zones,
+			Autoscaling:  np.Autoscaling,
+			MinCount:     int32(np.Min),
+			MaxCount:     int32(np.Max),
+			Count:        int32(np.DesiredCount),
+			InstanceType: np.InstanceType,
+		}
+	}
+
+	return CreatePKEOnAzureClusterRequest{
+		Name:          c.Name,
+		SecretId:      c.SecretID,
+		SshSecretId:   c.SSHSecretID,
+		ScaleOptions:  scaleOptionsToClientScaleOptions(c.ScaleOptions),
+		Type:          PKEOnAzure,
+		Kubernetes:    pkeKubernetesToClientCreatePKEClusterKubernetes(c.Kubernetes),
+		Proxy:         pkeHTTPProxyToClientPKEClusterHTTPProxy(c.HTTPProxy),
+		Location:      c.Location,
+		ResourceGroup: c.ResourceGroup.Name,
+		Network: pipeline.PkeOnAzureClusterNetwork{
+			Name: c.VirtualNetwork.Name,
+		},
+		AccessPoints:          accessPoints,
+		ApiServerAccessPoints: apiServerAccessPoints,
+		Nodepools:             nodepools,
+	}
+}
+
+func scaleOptionsToClientScaleOptions(o cluster.ScaleOptions) pipeline.ScaleOptions {
+	return pipeline.ScaleOptions{
+		Enabled:             o.Enabled,
+		DesiredCpu:          o.DesiredCpu,
+		DesiredMem:          o.DesiredMem,
+		DesiredGpu:          int32(o.DesiredGpu),
+		OnDemandPct:         int32(o.OnDemandPct),
+		Excludes:            o.Excludes,
+		KeepDesiredCapacity: o.KeepDesiredCapacity,
+	}
+}
+
+func pkeKubernetesToClientCreatePKEClusterKubernetes(k intPKE.Kubernetes) pipeline.CreatePkeClusterKubernetes {
+	return pipeline.CreatePkeClusterKubernetes{
+		Version: k.Version,
+		Rbac:    k.RBAC,
+		Oidc: pipeline.CreatePkeClusterKubernetesOidc{
+			Enabled: k.OIDC.Enabled,
+		},
+		Cri: pipeline.CreatePkeClusterKubernetesCri{
+			Runtime:       k.CRI.Runtime,
+			RuntimeConfig: k.CRI.RuntimeConfig,
+		},
+		Network: pipeline.CreatePkeClusterKubernetesNetwork{
+			ServiceCIDR:    k.Network.ServiceCIDR,
+			PodCIDR:        k.Network.PodCIDR,
+			Provider:       k.Network.Provider,
+			ProviderConfig: k.Network.ProviderConfig,
+		},
+	}
+}
+
+func pkeHTTPProxyToClientPKEClusterHTTPProxy(p intPKE.HTTPProxy) pipeline.PkeClusterHttpProxy {
+	return pipeline.PkeClusterHttpProxy{
+		Http:       pkeHTTPProxyOptionsToClientPKEClusterHTTPProxyOptions(p.HTTP),
+		Https:      pkeHTTPProxyOptionsToClientPKEClusterHTTPProxyOptions(p.HTTPS),
+		Exceptions: p.Exceptions,
+	}
+}
+
+func pkeHTTPProxyOptionsToClientPKEClusterHTTPProxyOptions(o intPKE.HTTPProxyOptions) pipeline.PkeClusterHttpProxyOptions {
+	return pipeline.PkeClusterHttpProxyOptions{
+		Host:     o.Host,
+		Port:     int32(o.Port),
+		SecretId: o.SecretID,
+		Scheme:   o.Scheme,
+	}
+}
+
 type UpdatePKEOnAzureClusterRequest pipeline.UpdatePkeOnAzureClusterRequest
 
 func (req UpdatePKEOnAzureClusterRequest) ToAzurePKEClusterUpdateParams(clusterID, userID uint) driver.ClusterUpdateParams {
@@ -118,6 +225,7 @@ func azureRequestToClusterNodepools(request []pipeline.PkeOnAzureNodePool, userI
 			Zones:       node.Zones,
 			Roles:       node.Roles,
 			Labels:      node.Labels,
+			Taints:      convertNodeTaints(node.Taints),
 			Autoscaling: node.Autoscaling,
 			Count:       int(node.Count),
 			Min:         int(node.MinCount),
@@ -185,3 +185,89 @@ func TestToAzurePKEClusterCreationParams(t *testing.T) {
 		})
 	}
 }
+
+func TestNewCreatePKEOnAzureClusterRequest(t *testing.T) {
+	in := azurePke.Cluster{
+		Location: Location,
+		NodePools: []azurePke.NodePool{
+			{
+				Autoscaling:  true,
+				DesiredCount: 2,
+				InstanceType: Instancetype,
+				Max:          3,
+				Min:          1,
+				Name:         "nodepool1",
+				Roles:        []string{"worker"},
+				Subnet:       azurePke.Subnetwork{Name: "test-subnet"},
+				Zones:        []string{"1"},
+			},
+		},
+		ResourceGroup:  azurePke.ResourceGroup{Name: ResourceGroup},
+		VirtualNetwork: azurePke.VirtualNetwork{Location: Location, Name: "test-net"},
+		Kubernetes: pke.Kubernetes{
+			Version: Version,
+			RBAC:    true,
+			Network: pke.Network{
+				ServiceCIDR: "10.10.0.0/16",
+				PodCIDR:     "10.20.0.0/16",
+				Provider:    "weave",
+			},
+			CRI: pke.CRI{Runtime: "containerd"},
+		},
+		HTTPProxy: pke.HTTPProxy{
+			HTTP: pke.HTTPProxyOptions{Host: "proxy", Port: 3128},
+		},
+		AccessPoints:          azurePke.AccessPoints{{Name: "public", Address: "1.2.3.4"}},
+		APIServerAccessPoints: azurePke.APIServerAccessPoints{"public"},
+	}
+	in.Name = Name
+	in.SecretID = SecretID
+	in.SSHSecretID = SSHSecretID
+	in.ScaleOptions = cluster.ScaleOptions{Enabled: true, DesiredCpu: 2, DesiredMem: 2048, OnDemandPct: 55}
+
+	expected := CreatePKEOnAzureClusterRequest{
+		Name:        Name,
+		SecretId:    SecretID,
+		SshSecretId: SSHSecretID,
+		ScaleOptions: pipeline.ScaleOptions{
+			Enabled:     true,
+			DesiredCpu:  2,
+			DesiredMem:  2048,
+			OnDemandPct: 55,
+		},
+		Type: PKEOnAzure,
+		Kubernetes: pipeline.CreatePkeClusterKubernetes{
+			Version: Version,
+			Rbac:    true,
+			Cri:     pipeline.CreatePkeClusterKubernetesCri{Runtime: "containerd"},
+			Network: pipeline.CreatePkeClusterKubernetesNetwork{
+				ServiceCIDR: "10.10.0.0/16",
+				PodCIDR:     "10.20.0.0/16",
+				Provider:    "weave",
+			},
+		},
+		Proxy: pipeline.PkeClusterHttpProxy{
+			Http: pipeline.PkeClusterHttpProxyOptions{Host: "proxy", Port: 3128},
+		},
+		Location:              Location,
+		ResourceGroup:         ResourceGroup,
+		Network:               pipeline.PkeOnAzureClusterNetwork{Name: "test-net"},
+		AccessPoints:          []string{"public"},
+		ApiServerAccessPoints: []string{"public"},
+		Nodepools: []pipeline.PkeOnAzureNodePool{
+			{
+				Name:         "nodepool1",
+				Roles:        []string{"worker"},
+				Subnet:       pipeline.PkeOnAzureNodePoolSubnet{Name: "test-subnet"},
+				Zones:        []string{"1"},
+				Autoscaling:  true,
+				MinCount:     1,
+				MaxCount:     3,
+				Count:        2,
+				InstanceType: Instancetype,
+			},
+		},
+	}
+
+	assert.Equal(t, expected, NewCreatePKEOnAzureClusterRequest(in))
+}
@@ -79,6 +79,48 @@ func (req CreatePKEOnVsphereClusterRequest) ToVspherePKEClusterCreationParams(or
 	}
 }
 
+// NewCreatePKEOnVsphereClusterRequest reconstructs a cluster creation request from a stored PKE on vSphere cluster.
+func NewCreatePKEOnVsphereClusterRequest(c pke.PKEOnVsphereCluster) CreatePKEOnVsphereClusterRequest {
+	nodepools := make([]pipeline.PkeOnVsphereNodePool, len(c.NodePools))
+	for i, np := range c.NodePools {
+		var taints []pipeline.NodeTaint
+		for _, taint := range np.Taints {
+			taints = append(taints, pipeline.NodeTaint{
+				Key:    taint.Key,
+				Value:  taint.Value,
+				Effect: taint.Effect,
+			})
+		}
+
+		nodepools[i] = pipeline.PkeOnVsphereNodePool{
+			Name:          np.Name,
+			Roles:         np.Roles,
+			Taints:        taints,
+			Size:          int32(np.Size),
+			Vcpu:          int32(np.VCPU),
+			Ram:           int32(np.RAM),
+			Template:      np.TemplateName,
+			AdminUsername: np.AdminUsername,
+		}
+	}
+
+	return CreatePKEOnVsphereClusterRequest{
+		Name:                c.Name,
+		SecretId:            c.SecretID,
+		SshSecretId:         c.SSHSecretID,
+		ScaleOptions:        scaleOptionsToClientScaleOptions(c.ScaleOptions),
+		Type:                PKEOnVsphere,
+		Kubernetes:          pkeKubernetesToClientCreatePKEClusterKubernetes(c.Kubernetes),
+		Proxy:               pkeHTTPProxyToClientPKEClusterHTTPProxy(c.HTTPProxy),
+		StorageSecretId:     c.StorageSecretID,
+		Folder:              c.Folder,
+		Datastore:           c.Datastore,
+		ResourcePool:        c.ResourcePool,
+		Nodepools:           nodepools,
+		LoadBalancerIPRange: c.LoadBalancerIPRange,
+	}
+}
+
 type UpdatePKEOnVsphereClusterRequest pipeline.UpdatePkeOnVsphereClusterRequest
 
 func (req UpdatePKEOnVsphereClusterRequest) ToVspherePKEClusterUpdateParams(clusterID, userID uint) driver.VspherePKEClusterUpdateParams {
@@ -96,6 +138,7 @@ func vsphereRequestToClusterNodepools(request []pipeline.PkeOnVsphereNodePool, u
 			Name:          node.Name,
 			Roles:         node.Roles,
 			Labels:        node.Labels,
+			Taints:        convertNodeTaints(node.Taints),
 			Size:          int(node.Size),
 			AdminUsername: node.AdminUsername,
 			VCPU:          int(node.Vcpu),
@@ -0,0 +1,148 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+type execQuery struct {
+	Container string   `form:"container"`
+	Command   []string `form:"command"`
+	Stdin     bool     `form:"stdin"`
+	TTY       bool     `form:"tty"`
+	Record    bool     `form:"record"`
+}
+
+// Exec runs a command in a pod container over a WebSocket connection.
+func (a *API) Exec(c *gin.Context) {
+	a.runSession(c, clusterexec.ModeExec)
+}
+
+// Attach attaches to the main process of a pod container over a WebSocket connection.
+func (a *API) Attach(c *gin.Context) {
+	a.runSession(c, clusterexec.ModeAttach)
+}
+
+func (a *API) runSession(c *gin.Context, mode string) {
+	var query execQuery
+	if err := c.BindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to parse query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	request := clusterexec.Request{
+		Target: clusterexec.Target{
+			OrganizationID: commonCluster.GetOrganizationId(),
+			ClusterID:      commonCluster.GetID(),
+			ConfigSecretID: brn.New(commonCluster.GetOrganizationId(), brn.SecretResourceType, commonCluster.GetConfigSecretId()).String(),
+			Namespace:      c.Param("namespace"),
+			Pod:            c.Param("pod"),
+			Container:      query.Container,
+		},
+		Mode:    mode,
+		Command: query.Command,
+		Stdin:   query.Stdin,
+		TTY:     query.TTY,
+		Record:  query.Record,
+	}
+
+	users := auth.UserExtractor{}
+	request.UserID, _ = users.GetUserID(c.Request.Context())
+	request.UserLogin, _ = users.GetUserLogin(c.Request.Context())
+
+	if err := request.Validate(); err != nil {
+		a.handleSessionError(c, err)
+		return
+	}
+
+	server := websocket.Server{
+		// browsers do not apply CORS to WebSocket connections, so foreign origins have to be rejected here
+		Handshake: func(_ *websocket.Config, req *http.Request) error {
+			if !a.origins.Allowed(req) {
+				return errors.NewWithDetails("origin not allowed", "origin", req.Header.Get("Origin"))
+			}
+
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			err := a.sessions.Run(c.Request.Context(), websocketConn{ws: ws}, request)
+			if err != nil {
+				var validationErr interface{ Validation() bool }
+				if !errors.As(err, &validationErr) || !validationErr.Validation() {
+					a.errorHandler.Handle(err)
+				}
+
+				_ = websocket.JSON.Send(ws, clusterexec.ServerMessage{
+					Type:   clusterexec.MessageExit,
+					Reason: clusterexec.EndReasonError,
+					Error:  sessionErrorMessage(err),
+				})
+			}
+		},
+	}
+
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// websocketConn implements the clusterexec.Conn interface with JSON messages sent over a WebSocket connection.
+type websocketConn struct {
+	ws *websocket.Conn
+}
+
+func (c websocketConn) Receive() (clusterexec.ClientMessage, error) {
+	var message clusterexec.ClientMessage
+
+	err := websocket.JSON.Receive(c.ws, &message)
+
+	return message, err
+}
+
+func (c websocketConn) Send(message clusterexec.ServerMessage) error {
+	return websocket.JSON.Send(c.ws, message)
+}
+
+func sessionErrorMessage(err error) string {
+	var validationErr interface {
+		Validation() bool
+		Violations() []string
+	}
+	if errors.As(err, &validationErr) && validationErr.Validation() {
+		return strings.Join(validationErr.Violations(), "; ")
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,169 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+	"github.com/banzaicloud/pipeline/src/api/common"
+	"github.com/banzaicloud/pipeline/src/cluster"
+)
+
+type fakeCluster struct {
+	cluster.CommonCluster
+}
+
+func (fakeCluster) GetOrganizationId() uint   { return 1 }
+func (fakeCluster) GetID() uint               { return 2 }
+func (fakeCluster) GetConfigSecretId() string { return "secret" }
+
+type fakeClusterGetter struct{}
+
+func (fakeClusterGetter) GetClusterFromRequest(*gin.Context) (cluster.CommonCluster, bool) {
+	return fakeCluster{}, true
+}
+
+type fakeSessionService struct {
+	SessionService
+
+	requests chan clusterexec.Request
+}
+
+func (s fakeSessionService) Run(_ context.Context, conn clusterexec.Conn, request clusterexec.Request) error {
+	s.requests <- request
+
+	return conn.Send(clusterexec.ServerMessage{Type: clusterexec.MessageExit, Reason: clusterexec.EndReasonCompleted})
+}
+
+func newExecTestServer(t *testing.T) (*httptest.Server, fakeSessionService) {
+	gin.SetMode(gin.TestMode)
+
+	origins, err := common.NewOriginChecker([]string{"https://ui.example.com"}, "", "")
+	require.NoError(t, err)
+
+	sessions := fakeSessionService{requests: make(chan clusterexec.Request, 1)}
+
+	router := gin.New()
+	NewAPI(fakeClusterGetter{}, nil, sessions, nil, origins, nil).RegisterRoutes(router.Group("/pods"))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server, sessions
+}
+
+func TestAPI_Exec_AllowedOrigin(t *testing.T) {
+	server, sessions := newExecTestServer(t)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/pods/default/nginx/exec?command=ls&command=-l&tty=true"
+
+	ws, err := websocket.Dial(url, "", "https://ui.example.com")
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var message clusterexec.ServerMessage
+	require.NoError(t, websocket.JSON.Receive(ws, &message))
+	assert.Equal(t, clusterexec.MessageExit, message.Type)
+
+	request := <-sessions.requests
+	assert.Equal(t, []string{"ls", "-l"}, request.Command)
+	assert.Equal(t, "brn:1:secret:secret", request.Target.ConfigSecretID)
+	assert.True(t, request.TTY)
+}
+
+func TestAPI_Exec_ForeignOrigin(t *testing.T) {
+	server, sessions := newExecTestServer(t)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/pods/default/nginx/exec?command=sh"
+
+	_, err := websocket.Dial(url, "", "https://evil.example.org")
+	require.Error(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/pods/default/nginx/exec?command=sh", nil)
+	require.NoError(t, err)
+
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "https://evil.example.org")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Empty(t, sessions.requests)
+}
+
+func TestAPI_Exec_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := map[string]string{
+		"invalid stdin": "/pods/default/nginx/exec?command=sh&stdin=maybe",
+		"invalid tty":   "/pods/default/nginx/attach?tty=yes",
+	}
+
+	for name, target := range tests {
+		target := target
+
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			NewAPI(nil, nil, nil, nil, common.OriginChecker{}, nil).RegisterRoutes(router.Group("/pods"))
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestSessionToModel(t *testing.T) {
+	startedAt := time.Date(2020, 5, 23, 10, 0, 0, 0, time.UTC)
+	endedAt := startedAt.Add(90 * time.Second)
+
+	model := sessionToModel(clusterexec.Session{
+		ID:        1,
+		UserID:    2,
+		UserLogin: "john",
+		Mode:      clusterexec.ModeExec,
+		Namespace: "default",
+		Pod:       "nginx",
+		Command:   []string{"sh"},
+		TTY:       true,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		EndReason: clusterexec.EndReasonIdle,
+		Recorded:  true,
+	}, startedAt.Add(time.Hour))
+
+	assert.Equal(t, int32(1), model.Id)
+	assert.Equal(t, "1m30s", model.Duration)
+	assert.Equal(t, endedAt, model.EndedAt)
+	assert.Equal(t, clusterexec.EndReasonIdle, model.EndReason)
+	assert.True(t, model.Recorded)
+}
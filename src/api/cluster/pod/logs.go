@@ -0,0 +1,139 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"bufio"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/src/api/common"
+)
+
+// maxLogLineSize is the longest log line streamed to the client.
+const maxLogLineSize = 1024 * 1024
+
+type logsQuery struct {
+	Container  string    `form:"container"`
+	Follow     bool      `form:"follow"`
+	Previous   bool      `form:"previous"`
+	Timestamps bool      `form:"timestamps"`
+	Since      string    `form:"since"`
+	SinceTime  time.Time `form:"sinceTime" time_format:"2006-01-02T15:04:05Z07:00"`
+	Tail       *int64    `form:"tail"`
+}
+
+// Logs streams the logs of a pod container.
+func (a *API) Logs(c *gin.Context) {
+	var query logsQuery
+	if err := c.BindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to parse query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	options := corev1.PodLogOptions{
+		Container:  query.Container,
+		Follow:     query.Follow,
+		Previous:   query.Previous,
+		Timestamps: query.Timestamps,
+		TailLines:  query.Tail,
+	}
+
+	if query.Since != "" {
+		since, err := time.ParseDuration(query.Since)
+		if err != nil || since <= 0 {
+			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Failed to parse query",
+				Error:   "since must be a positive duration (eg. 10m)",
+			})
+			return
+		}
+
+		seconds := int64(since.Seconds())
+		if seconds < 1 {
+			seconds = 1
+		}
+		options.SinceSeconds = &seconds
+	} else if !query.SinceTime.IsZero() {
+		options.SinceTime = &metav1.Time{Time: query.SinceTime}
+	}
+
+	_, client, ok := common.KubernetesClientFromRequest(c, a.clusterGetter, a.clientFactory, a.errorHandler)
+	if !ok {
+		return
+	}
+
+	stream, err := client.CoreV1().
+		Pods(c.Param("namespace")).
+		GetLogs(c.Param("pod"), &options).
+		Context(c.Request.Context()).
+		Stream()
+	if k8serrors.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "pod not found",
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		if !k8serrors.IsBadRequest(err) {
+			a.errorHandler.Handle(errors.Wrap(err, "failed to get pod logs"))
+		}
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error getting pod logs",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer stream.Close()
+
+	writer := common.NewStreamWriter(c, "text/plain; charset=utf-8")
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineSize)
+
+	for scanner.Scan() {
+		if err := writer.Write("log", scanner.Bytes()); err != nil {
+			return
+		}
+	}
+
+	// the stream is closed when the client goes away
+	if err := scanner.Err(); err != nil && c.Request.Context().Err() == nil {
+		a.errorHandler.Handle(errors.Wrap(err, "failed to stream pod logs"))
+
+		writer.WriteError(err)
+		return
+	}
+
+	if !writer.Started() {
+		// the log is empty
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/src/api/common"
+)
+
+func TestAPI_Logs_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := map[string]string{
+		"invalid since":     "since=yesterday",
+		"negative since":    "since=-5m",
+		"invalid tail":      "tail=all",
+		"invalid sinceTime": "sinceTime=yesterday",
+	}
+
+	for name, query := range tests {
+		query := query
+
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			NewAPI(nil, nil, nil, nil, common.OriginChecker{}, nil).RegisterRoutes(router.Group("/pods"))
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/pods/default/nginx/logs?"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+	"github.com/banzaicloud/pipeline/src/api/common"
+)
+
+// SessionService runs and lists interactive exec and attach sessions.
+type SessionService interface {
+	Run(ctx context.Context, conn clusterexec.Conn, request clusterexec.Request) error
+	ListSessions(ctx context.Context, organizationID uint, clusterID uint) ([]clusterexec.Session, error)
+	GetSession(ctx context.Context, organizationID uint, clusterID uint, id uint) (clusterexec.Session, error)
+	GetRecording(ctx context.Context, organizationID uint, clusterID uint, id uint) ([]byte, error)
+}
+
+// RoleSource returns the user's role in a given organization.
+type RoleSource interface {
+	// FindUserRole returns the user's role in a given organization.
+	// Returns false as the second parameter if the user is not a member of the organization.
+	FindUserRole(ctx context.Context, organizationID uint, userID uint) (string, bool, error)
+}
+
+type API struct {
+	clusterGetter common.ClusterGetter
+	clientFactory common.ClientFactory
+	sessions      SessionService
+	roles         RoleSource
+	origins       common.OriginChecker
+	errorHandler  emperror.Handler
+}
+
+func NewAPI(
+	clusterGetter common.ClusterGetter,
+	clientFactory common.ClientFactory,
+	sessions SessionService,
+	roles RoleSource,
+	origins common.OriginChecker,
+	errorHandler emperror.Handler,
+) *API {
+	return &API{
+		clusterGetter: clusterGetter,
+		clientFactory: clientFactory,
+		sessions:      sessions,
+		roles:         roles,
+		origins:       origins,
+		errorHandler:  errorHandler,
+	}
+}
+
+func (a *API) RegisterRoutes(r gin.IRouter) {
+	r.GET(":namespace/:pod/logs", a.Logs)
+	r.GET(":namespace/:pod/exec", a.Exec)
+	r.GET(":namespace/:pod/attach", a.Attach)
+}
+
+// RegisterSessionRoutes registers the routes of the interactive session records.
+func (a *API) RegisterSessionRoutes(r gin.IRouter) {
+	r.GET("", a.ListSessions)
+	r.GET(":sessionId", a.GetSession)
+	r.GET(":sessionId/recording", a.GetRecording)
+}
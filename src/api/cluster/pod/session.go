@@ -0,0 +1,220 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/src/auth"
+	"github.com/banzaicloud/pipeline/src/cluster"
+)
+
+// ListSessions lists the interactive sessions of a cluster, most recent first.
+// Organization admins see every session, other users only their own ones.
+func (a *API) ListSessions(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := a.sessions.ListSessions(c.Request.Context(), commonCluster.GetOrganizationId(), commonCluster.GetID())
+	if err != nil {
+		a.handleSessionError(c, err)
+		return
+	}
+
+	admin, err := a.isAdmin(c.Request.Context(), commonCluster.GetOrganizationId())
+	if err != nil {
+		a.handleSessionError(c, err)
+		return
+	}
+
+	userID, _ := auth.UserExtractor{}.GetUserID(c.Request.Context())
+
+	now := time.Now()
+
+	response := pipeline.ExecSessionList{
+		Sessions: make([]pipeline.ExecSession, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		if !admin && (userID == 0 || session.UserID != userID) {
+			continue
+		}
+
+		response.Sessions = append(response.Sessions, sessionToModel(session, now))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSession returns an interactive session of a cluster.
+func (a *API) GetSession(c *gin.Context) {
+	session, ok := a.accessibleSessionFromRequest(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionToModel(session, time.Now()))
+}
+
+// GetRecording returns the asciicast recording of an interactive session.
+func (a *API) GetRecording(c *gin.Context) {
+	session, ok := a.accessibleSessionFromRequest(c)
+	if !ok {
+		return
+	}
+
+	recording, err := a.sessions.GetRecording(c.Request.Context(), session.OrganizationID, session.ClusterID, session.ID)
+	if err != nil {
+		a.handleSessionError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/x-asciicast", recording)
+}
+
+func (a *API) sessionFromRequest(c *gin.Context) (cluster.CommonCluster, uint, bool) {
+	sessionID, err := strconv.ParseUint(c.Param("sessionId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid session ID",
+			Error:   err.Error(),
+		})
+		return nil, 0, false
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return nil, 0, false
+	}
+
+	return commonCluster, uint(sessionID), true
+}
+
+// accessibleSessionFromRequest returns the requested session if the current user can access it:
+// organization admins can access every session, other users only their own ones.
+func (a *API) accessibleSessionFromRequest(c *gin.Context) (clusterexec.Session, bool) {
+	commonCluster, sessionID, ok := a.sessionFromRequest(c)
+	if !ok {
+		return clusterexec.Session{}, false
+	}
+
+	session, err := a.sessions.GetSession(c.Request.Context(), commonCluster.GetOrganizationId(), commonCluster.GetID(), sessionID)
+	if err != nil {
+		a.handleSessionError(c, err)
+		return clusterexec.Session{}, false
+	}
+
+	if userID, _ := (auth.UserExtractor{}).GetUserID(c.Request.Context()); userID != 0 && userID == session.UserID {
+		return session, true
+	}
+
+	admin, err := a.isAdmin(c.Request.Context(), session.OrganizationID)
+	if err != nil {
+		a.handleSessionError(c, err)
+		return clusterexec.Session{}, false
+	}
+
+	if !admin {
+		c.AbortWithStatusJSON(http.StatusForbidden, pkgCommon.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "only organization admins can access the sessions of other users",
+		})
+		return clusterexec.Session{}, false
+	}
+
+	return session, true
+}
+
+func (a *API) isAdmin(ctx context.Context, organizationID uint) (bool, error) {
+	userID, ok := auth.UserExtractor{}.GetUserID(ctx)
+	if !ok || userID == 0 {
+		return false, nil
+	}
+
+	role, member, err := a.roles.FindUserRole(ctx, organizationID, userID)
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to find user role", "organizationId", organizationID, "userId", userID)
+	}
+
+	return member && role == auth.RoleAdmin, nil
+}
+
+func (a *API) handleSessionError(c *gin.Context, err error) {
+	var validationErr interface {
+		Validation() bool
+		Violations() []string
+	}
+	if errors.As(err, &validationErr) && validationErr.Validation() {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   strings.Join(validationErr.Violations(), "; "),
+		})
+		return
+	}
+
+	var notFoundErr interface{ NotFound() bool }
+	if errors.As(err, &notFoundErr) && notFoundErr.NotFound() {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusNotFound, err)
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error getting sessions",
+		Error:   err.Error(),
+	})
+}
+
+func sessionToModel(session clusterexec.Session, now time.Time) pipeline.ExecSession {
+	model := pipeline.ExecSession{
+		Id:        int32(session.ID),
+		UserId:    int32(session.UserID),
+		UserLogin: session.UserLogin,
+		Mode:      session.Mode,
+		Namespace: session.Namespace,
+		Pod:       session.Pod,
+		Container: session.Container,
+		Command:   session.Command,
+		Tty:       session.TTY,
+		StartedAt: session.StartedAt,
+		Duration:  session.Duration(now).Round(time.Second).String(),
+		EndReason: session.EndReason,
+		Error:     session.Error,
+		Recorded:  session.Recorded,
+	}
+
+	if session.EndedAt != nil {
+		model.EndedAt = *session.EndedAt
+	}
+
+	return model
+}
@@ -0,0 +1,146 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	qorauth "github.com/qor/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterexec"
+	"github.com/banzaicloud/pipeline/src/api/common"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+type fakeRecordingSessionService struct {
+	SessionService
+
+	sessions []clusterexec.Session
+}
+
+func (s fakeRecordingSessionService) ListSessions(context.Context, uint, uint) ([]clusterexec.Session, error) {
+	return s.sessions, nil
+}
+
+func (s fakeRecordingSessionService) GetSession(_ context.Context, _ uint, _ uint, id uint) (clusterexec.Session, error) {
+	for _, session := range s.sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+
+	return clusterexec.Session{}, clusterexec.NotFoundError{ClusterID: 2, ID: id}
+}
+
+func (s fakeRecordingSessionService) GetRecording(context.Context, uint, uint, uint) ([]byte, error) {
+	return []byte("recording"), nil
+}
+
+type fakeRoleSource map[uint]string
+
+func (s fakeRoleSource) FindUserRole(_ context.Context, _ uint, userID uint) (string, bool, error) {
+	role, ok := s[userID]
+
+	return role, ok, nil
+}
+
+func newSessionTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	sessions := fakeRecordingSessionService{
+		sessions: []clusterexec.Session{
+			{ID: 1, OrganizationID: 1, ClusterID: 2, UserID: 10, Recorded: true},
+			{ID: 2, OrganizationID: 1, ClusterID: 2, UserID: 11, Recorded: true},
+		},
+	}
+	roles := fakeRoleSource{10: auth.RoleMember, 11: auth.RoleMember, 12: auth.RoleAdmin}
+
+	router := gin.New()
+	NewAPI(fakeClusterGetter{}, nil, sessions, roles, common.OriginChecker{}, nil).RegisterSessionRoutes(router.Group("/exec-sessions"))
+
+	return router
+}
+
+func sessionTestRequest(router *gin.Engine, userID uint, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req = req.WithContext(context.WithValue(req.Context(), qorauth.CurrentUser, &auth.User{ID: userID}))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestAPI_ListSessions(t *testing.T) {
+	router := newSessionTestRouter()
+
+	tests := map[string]struct {
+		userID   uint
+		expected []int32
+	}{
+		"admin":  {userID: 12, expected: []int32{1, 2}},
+		"member": {userID: 10, expected: []int32{1}},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			recorder := sessionTestRequest(router, test.userID, "/exec-sessions")
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var response pipeline.ExecSessionList
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+			ids := make([]int32, 0, len(response.Sessions))
+			for _, session := range response.Sessions {
+				ids = append(ids, session.Id)
+			}
+
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
+
+func TestAPI_GetRecording(t *testing.T) {
+	router := newSessionTestRouter()
+
+	tests := map[string]struct {
+		userID   uint
+		expected int
+	}{
+		"admin":        {userID: 12, expected: http.StatusOK},
+		"owner member": {userID: 11, expected: http.StatusOK},
+		"other member": {userID: 10, expected: http.StatusForbidden},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			recorder := sessionTestRequest(router, test.userID, "/exec-sessions/2/recording")
+
+			assert.Equal(t, test.expected, recorder.Code)
+		})
+	}
+}
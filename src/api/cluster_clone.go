@@ -0,0 +1,261 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	azurePKE "github.com/banzaicloud/pipeline/internal/providers/azure/pke"
+	vspherePKE "github.com/banzaicloud/pipeline/internal/providers/vsphere/pke"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	clusterAPI "github.com/banzaicloud/pipeline/src/api/cluster"
+	"github.com/banzaicloud/pipeline/src/auth"
+	"github.com/banzaicloud/pipeline/src/cluster"
+)
+
+// ClusterCloneAPI implements the cluster clone action.
+type ClusterCloneAPI struct {
+	clusterAPI         *ClusterAPI
+	integratedServices integratedservices.Service
+	errorHandler       emperror.Handler
+}
+
+// NewClusterCloneAPI returns a new ClusterCloneAPI instance.
+func NewClusterCloneAPI(
+	clusterAPI *ClusterAPI,
+	integratedServices integratedservices.Service,
+	errorHandler emperror.Handler,
+) ClusterCloneAPI {
+	return ClusterCloneAPI{
+		clusterAPI:         clusterAPI,
+		integratedServices: integratedServices,
+		errorHandler:       errorHandler,
+	}
+}
+
+// CloneCluster creates a new K8S cluster from the stored specification of an existing one.
+func (a ClusterCloneAPI) CloneCluster(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	commonCluster, ok := a.clusterAPI.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	orgID := auth.GetCurrentOrganization(c.Request).ID
+	userID := auth.GetCurrentUser(c.Request).ID
+
+	var request pipeline.CloneClusterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if request.Name == "" {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "name of the new cluster is required",
+			Error:   "no cluster name specified",
+		})
+		return
+	}
+
+	requestBody, err := buildCloneClusterRequestBody(commonCluster, request)
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	labels := request.Labels
+	if labels == nil {
+		labels, err = a.clusterAPI.clusterLabels.GetLabels(ctx, commonCluster.GetID())
+		if err != nil {
+			a.errorHandler.Handle(err)
+			pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if len(labels) > 0 {
+		requestBody["labels"] = labels
+	}
+
+	a.clusterAPI.logger.WithFields(logrus.Fields{
+		"sourceClusterId": commonCluster.GetID(),
+		"cluster":         request.Name,
+	}).Info("Cluster cloning started")
+
+	response, ok := a.clusterAPI.createClusterFromRequestBody(c, ctx, orgID, userID, requestBody)
+	if !ok {
+		return
+	}
+
+	if request.CopyIntegratedServices {
+		a.copyIntegratedServices(ctx, commonCluster.GetID(), response.ResourceID)
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// copyIntegratedServices activates the active integrated services of the source cluster on the target cluster.
+// The cluster creation is already in progress at this point, so failures are only logged.
+func (a ClusterCloneAPI) copyIntegratedServices(ctx context.Context, sourceClusterID uint, targetClusterID uint) {
+	services, err := a.integratedServices.List(ctx, sourceClusterID)
+	if err != nil {
+		a.errorHandler.Handle(errors.WrapIfWithDetails(err, "failed to list integrated services", "clusterId", sourceClusterID))
+		return
+	}
+
+	for _, service := range services {
+		if service.Status != integratedservices.IntegratedServiceStatusActive {
+			continue
+		}
+
+		details, err := a.integratedServices.Details(ctx, sourceClusterID, service.Name)
+		if err != nil {
+			a.errorHandler.Handle(errors.WrapIfWithDetails(err, "failed to get integrated service details", "clusterId", sourceClusterID, "integratedService", service.Name))
+			continue
+		}
+
+		if err := a.integratedServices.Activate(ctx, targetClusterID, service.Name, details.Spec); err != nil {
+			a.errorHandler.Handle(errors.WrapIfWithDetails(err, "failed to activate integrated service", "clusterId", targetClusterID, "integratedService", service.Name))
+		}
+	}
+}
+
+// buildCloneClusterRequestBody reconstructs the creation request of a cluster and applies the overrides of a clone request.
+func buildCloneClusterRequestBody(commonCluster cluster.CommonCluster, request pipeline.CloneClusterRequest) (map[string]interface{}, error) {
+	var createRequest interface{}
+
+	switch c := commonCluster.(type) {
+	case interface{ GetPKEOnAzureCluster() azurePKE.Cluster }:
+		req := clusterAPI.NewCreatePKEOnAzureClusterRequest(c.GetPKEOnAzureCluster())
+		req.Name = request.Name
+		if request.Location != "" && request.Location != req.Location {
+			// the virtual network of the source cluster cannot be reused in another location
+			req.Location = request.Location
+			req.Network = pipeline.PkeOnAzureClusterNetwork{}
+		}
+		if request.SecretId != "" || request.SecretName != "" {
+			req.SecretId = request.SecretId
+			req.SecretName = request.SecretName
+		}
+		createRequest = req
+
+	case interface {
+		GetPKEOnVsphereCluster() vspherePKE.PKEOnVsphereCluster
+	}:
+		if request.Location != "" {
+			return nil, errors.Errorf("location cannot be overridden for %s clusters", clusterAPI.PKEOnVsphere)
+		}
+
+		req := clusterAPI.NewCreatePKEOnVsphereClusterRequest(c.GetPKEOnVsphereCluster())
+		req.Name = request.Name
+		if request.SecretId != "" || request.SecretName != "" {
+			req.SecretId = request.SecretId
+			req.SecretName = request.SecretName
+		}
+		createRequest = req
+
+	case interface {
+		GetCreateClusterRequest() (*pkgCluster.CreateClusterRequest, error)
+	}:
+		req, err := c.GetCreateClusterRequest()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to reconstruct cluster creation request")
+		}
+
+		req.Name = request.Name
+		if request.Location != "" && request.Location != req.Location {
+			req.Location = request.Location
+			dropLocationBoundProperties(req.Properties)
+		}
+		if request.SecretId != "" || request.SecretName != "" {
+			req.SecretId = request.SecretId
+			req.SecretIds = nil
+			req.SecretName = request.SecretName
+		}
+		createRequest = req
+
+	default:
+		return nil, errors.Errorf("cloning %s clusters is not supported", commonCluster.GetDistribution())
+	}
+
+	data, err := json.Marshal(createRequest)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to marshal cluster creation request")
+	}
+
+	var requestBody map[string]interface{}
+	if err := json.Unmarshal(data, &requestBody); err != nil {
+		return nil, errors.WrapIf(err, "failed to unmarshal cluster creation request")
+	}
+
+	return requestBody, nil
+}
+
+// dropLocationBoundProperties removes the settings of a reconstructed cluster creation request
+// that refer to resources of the source cluster's location, so that defaults apply in the new location.
+func dropLocationBoundProperties(properties *pkgCluster.CreateClusterProperties) {
+	if properties == nil {
+		return
+	}
+
+	if eks := properties.CreateClusterEKS; eks != nil {
+		eks.Vpc = nil
+		eks.RouteTableId = ""
+		eks.Subnets = nil
+		for _, np := range eks.NodePools {
+			np.Image = ""
+			np.Subnet = nil
+		}
+	}
+
+	if aks := properties.CreateClusterAKS; aks != nil {
+		for _, np := range aks.NodePools {
+			np.VNetSubnetID = ""
+		}
+	}
+
+	if gke := properties.CreateClusterGKE; gke != nil {
+		gke.Vpc = ""
+		gke.Subnet = ""
+	}
+
+	if pke := properties.CreateClusterPKE; pke != nil {
+		for _, np := range pke.NodePools {
+			asg, ok := np.ProviderConfig["autoScalingGroup"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, key := range []string{"image", "zones", "subnets", "vpcID", "securityGroupID"} {
+				delete(asg, key)
+			}
+		}
+	}
+}
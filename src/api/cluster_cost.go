@@ -0,0 +1,347 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/src/auth"
+	"github.com/banzaicloud/pipeline/src/cluster"
+)
+
+// ClusterCostAPI implements the cluster cost estimation actions.
+type ClusterCostAPI struct {
+	clusterAPI    *ClusterAPI
+	estimator     clustercost.Estimator
+	usageReporter clustercost.UsageReporter
+	errorHandler  emperror.Handler
+}
+
+// NewClusterCostAPI returns a new ClusterCostAPI instance.
+func NewClusterCostAPI(
+	clusterAPI *ClusterAPI,
+	estimator clustercost.Estimator,
+	usageReporter clustercost.UsageReporter,
+	errorHandler emperror.Handler,
+) ClusterCostAPI {
+	return ClusterCostAPI{
+		clusterAPI:    clusterAPI,
+		estimator:     estimator,
+		usageReporter: usageReporter,
+		errorHandler:  errorHandler,
+	}
+}
+
+// GetClusterCost returns the estimated cost of a cluster based on its running nodes.
+func (a ClusterCostAPI) GetClusterCost(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	commonCluster, ok := a.clusterAPI.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	estimate, err := a.estimateCluster(ctx, commonCluster)
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// EstimateClusterUpdateCost returns the estimated cost of a cluster after applying an update request.
+func (a ClusterCostAPI) EstimateClusterUpdateCost(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	commonCluster, ok := a.clusterAPI.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var request pkgCluster.UpdateClusterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	spec, err := clustercost.ApplyUpdateRequest(clustercost.SpecFromClusterStatus(status, nil), request)
+	if err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	estimate, err := a.estimator.EstimateCluster(ctx, spec)
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterCostEstimate(estimate))
+}
+
+// EstimateClusterCreateCost returns the estimated cost of a cluster described by a creation request.
+func (a ClusterCostAPI) EstimateClusterCreateCost(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	var request pkgCluster.CreateClusterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	spec, err := clustercost.SpecFromCreateRequest(request)
+	if err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	estimate, err := a.estimator.EstimateCluster(ctx, spec)
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	response := newClusterCostEstimate(estimate)
+	response.ClusterName = request.Name
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOrganizationCost returns the estimated cost of every cluster in an organization.
+//
+// Clusters that cannot be estimated are reported separately and excluded from the totals.
+func (a ClusterCostAPI) GetOrganizationCost(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	orgID := auth.GetCurrentOrganization(c.Request).ID
+
+	clusters, err := a.clusterAPI.clusterManager.GetClusters(ctx, orgID)
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := pipeline.OrganizationCostEstimate{
+		Currency:       clustercost.Currency,
+		Clusters:       []pipeline.ClusterCostEstimate{},
+		FailedClusters: []pipeline.ClusterCostEstimateFailure{},
+	}
+
+	for _, commonCluster := range clusters {
+		estimate, err := a.estimateCluster(ctx, commonCluster)
+		if err != nil {
+			a.errorHandler.Handle(err)
+
+			response.FailedClusters = append(response.FailedClusters, pipeline.ClusterCostEstimateFailure{
+				ClusterId:   int32(commonCluster.GetID()),
+				ClusterName: commonCluster.GetName(),
+				Error:       err.Error(),
+			})
+
+			continue
+		}
+
+		response.Clusters = append(response.Clusters, estimate)
+		response.HourlyCost += estimate.HourlyCost
+		response.MonthlyCost += estimate.MonthlyCost
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUsageReport returns the recorded cost and usage of an organization aggregated by the requested dimension.
+//
+// The report covers the current month unless a time range is given, and is returned as CSV when requested.
+func (a ClusterCostAPI) GetUsageReport(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	orgID := auth.GetCurrentOrganization(c.Request).ID
+
+	options, err := parseUsageReportOptions(c, time.Now().UTC())
+	if err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := options.Validate(); err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := a.usageReporter.GetUsageReport(ctx, orgID, options)
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		var buf bytes.Buffer
+		if err := clustercost.WriteUsageReportCSV(&buf, report); err != nil {
+			a.errorHandler.Handle(err)
+			pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"cost-usage-%d.csv\"", orgID))
+		c.Data(http.StatusOK, "text/csv", buf.Bytes())
+		return
+	}
+
+	c.JSON(http.StatusOK, newUsageReport(report))
+}
+
+func parseUsageReportOptions(c *gin.Context, now time.Time) (clustercost.UsageReportOptions, error) {
+	options := clustercost.UsageReportOptions{
+		From:     time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		To:       now,
+		GroupBy:  c.DefaultQuery("groupBy", clustercost.GroupByCluster),
+		LabelKey: c.Query("label"),
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return options, errors.WrapIf(err, "invalid report start")
+		}
+
+		options.From = t
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return options, errors.WrapIf(err, "invalid report end")
+		}
+
+		options.To = t
+	}
+
+	if clusterID := c.Query("clusterId"); clusterID != "" {
+		id, err := strconv.ParseUint(clusterID, 10, 32)
+		if err != nil {
+			return options, errors.WrapIf(err, "invalid cluster ID")
+		}
+
+		options.ClusterID = uint(id)
+	}
+
+	return options, nil
+}
+
+func newUsageReport(report clustercost.UsageReport) pipeline.CostUsageReport {
+	items := make([]pipeline.CostUsageReportItem, 0, len(report.Items))
+	for _, item := range report.Items {
+		items = append(items, pipeline.CostUsageReportItem{
+			ClusterId:   int32(item.ClusterID),
+			ClusterName: item.ClusterName,
+			NodePool:    item.NodePool,
+			LabelValue:  item.LabelValue,
+			NodeHours:   item.NodeHours,
+			Cost:        item.Cost,
+		})
+	}
+
+	return pipeline.CostUsageReport{
+		From:      report.From,
+		To:        report.To,
+		GroupBy:   report.GroupBy,
+		LabelKey:  report.LabelKey,
+		Currency:  report.Currency,
+		NodeHours: report.NodeHours,
+		Cost:      report.Cost,
+		Items:     items,
+	}
+}
+
+// estimateCluster estimates the cost of a cluster using the node counts observed in the cluster.
+// Falls back to the stored node pool sizes when the cluster is not reachable.
+func (a ClusterCostAPI) estimateCluster(ctx context.Context, commonCluster cluster.CommonCluster) (pipeline.ClusterCostEstimate, error) {
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		return pipeline.ClusterCostEstimate{}, errors.WrapIf(err, "could not get cluster status")
+	}
+
+	nodeCounts, err := getActualNodeCounts(commonCluster)
+	if err != nil {
+		a.errorHandler.Handle(errors.WrapIfWithDetails(err, "could not get actual node counts", "clusterId", commonCluster.GetID()))
+
+		nodeCounts = nil
+	}
+
+	estimate, err := a.estimator.EstimateCluster(ctx, clustercost.SpecFromClusterStatus(status, nodeCounts))
+	if err != nil {
+		return pipeline.ClusterCostEstimate{}, err
+	}
+
+	response := newClusterCostEstimate(estimate)
+	response.ClusterId = int32(commonCluster.GetID())
+	response.ClusterName = commonCluster.GetName()
+
+	return response, nil
+}
+
+func newClusterCostEstimate(estimate clustercost.ClusterEstimate) pipeline.ClusterCostEstimate {
+	nodePools := make([]pipeline.NodePoolCostEstimate, 0, len(estimate.NodePools))
+	for _, nodePool := range estimate.NodePools {
+		nodePools = append(nodePools, pipeline.NodePoolCostEstimate{
+			Name:            nodePool.Name,
+			InstanceType:    nodePool.InstanceType,
+			Count:           int32(nodePool.Count),
+			Spot:            nodePool.Spot,
+			NodeHourlyPrice: nodePool.NodeHourlyPrice,
+			HourlyCost:      nodePool.HourlyCost,
+			MonthlyCost:     nodePool.MonthlyCost,
+		})
+	}
+
+	return pipeline.ClusterCostEstimate{
+		Cloud:                  estimate.Cloud,
+		Distribution:           estimate.Distribution,
+		Region:                 estimate.Region,
+		Currency:               estimate.Currency,
+		NodePools:              nodePools,
+		ControlPlaneHourlyCost: estimate.ControlPlaneHourlyCost,
+		HourlyCost:             estimate.HourlyCost,
+		MonthlyCost:            estimate.MonthlyCost,
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -26,6 +28,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/clustertemplate"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -93,11 +97,113 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
 		return
 	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, errors.WrapIf(err, "invalid dryRun parameter"))
+		return
+	}
+
+	if dryRun {
+		a.dryRunCreateClusterFromRequestBody(c, ctx, orgID, userID, requestBody)
+		return
+	}
+
+	response, ok := a.createClusterFromRequestBody(c, ctx, orgID, userID, requestBody)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// CreateClusterFromTemplate renders a cluster template with the supplied parameters
+// and creates a K8S cluster from the resulting request.
+func (a *ClusterAPI) CreateClusterFromTemplate(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	orgID := auth.GetCurrentOrganization(c.Request).ID
+	userID := auth.GetCurrentUser(c.Request).ID
+
+	templateID, ok := ginutils.UintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var request pipeline.ClusterTemplateParametersRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	rendered, err := a.clusterTemplates.RenderClusterTemplate(ctx, orgID, templateID, int(request.Version), request.Parameters)
+	if err != nil {
+		a.handleClusterTemplateError(c, err)
+		return
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"templateId":      rendered.TemplateID,
+		"templateVersion": rendered.TemplateVersion,
+	}).Info("Cluster creation from template started")
+
+	response, ok := a.createClusterFromRequestBody(c, ctx, orgID, userID, rendered.Request)
+	if !ok {
+		return
+	}
+
+	err = a.clusterTemplates.RecordClusterTemplateUsage(ctx, clustertemplate.ClusterTemplateUsage{
+		ClusterID:       response.ResourceID,
+		TemplateID:      rendered.TemplateID,
+		TemplateVersion: rendered.TemplateVersion,
+	})
+	if err != nil {
+		// the cluster creation is already in progress at this point, so the failure is only logged
+		a.errorHandler.Handle(errors.WrapIfWithDetails(err, "failed to record cluster template usage", "clusterId", response.ResourceID))
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+func (a *ClusterAPI) handleClusterTemplateError(c *gin.Context, err error) {
+	var notFoundErr interface{ NotFound() bool }
+	var validationErr interface {
+		Validation() bool
+		Violations() []string
+	}
+
+	switch {
+	case errors.As(err, &notFoundErr) && notFoundErr.NotFound():
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusNotFound, err)
+
+	case errors.As(err, &validationErr) && validationErr.Validation():
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   strings.Join(validationErr.Violations(), "; "),
+		})
+
+	default:
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+	}
+}
+
+// createClusterFromRequestBody creates a K8S cluster from a (legacy or v2) cluster creation request.
+// It replies to the client on failure and returns false.
+func (a *ClusterAPI) createClusterFromRequestBody(
+	c *gin.Context,
+	ctx context.Context,
+	orgID uint,
+	userID uint,
+	requestBody map[string]interface{},
+) (pkgCluster.CreateClusterResponse, bool) {
 	if _, ok := requestBody["type"]; !ok {
 		a.logger.Info("request body did not match v2 structure, trying legacy path")
 		var createClusterRequest pkgCluster.CreateClusterRequest
 		if !a.parseRequest(c, requestBody, &createClusterRequest) {
-			return
+			return pkgCluster.CreateClusterResponse{}, false
 		}
 
 		if createClusterRequest.SecretId == "" && len(createClusterRequest.SecretIds) == 0 {
@@ -106,28 +212,31 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 					Code:    http.StatusBadRequest,
 					Message: "either secretId or secretName has to be set",
 				})
-				return
+				return pkgCluster.CreateClusterResponse{}, false
 			}
 
 			createClusterRequest.SecretId = secret.GenerateSecretIDFromName(createClusterRequest.SecretName)
 		}
 
+		if !a.validateCreateClusterRequestPolicy(c, ctx, orgID, createClusterRequest) {
+			return pkgCluster.CreateClusterResponse{}, false
+		}
+
 		commonCluster, err := a.createCluster(ctx, &createClusterRequest, orgID, userID, createClusterRequest.PostHooks)
 		if err != nil {
 			c.JSON(err.Code, err)
-			return
+			return pkgCluster.CreateClusterResponse{}, false
 		}
 
-		c.JSON(http.StatusAccepted, pkgCluster.CreateClusterResponse{
+		return pkgCluster.CreateClusterResponse{
 			Name:       commonCluster.GetName(),
 			ResourceID: commonCluster.GetID(),
-		})
-		return
+		}, true
 	}
 
 	var createClusterRequestBase pipeline.CreateClusterRequestBase
 	if !a.parseRequest(c, requestBody, &createClusterRequestBase) {
-		return
+		return pkgCluster.CreateClusterResponse{}, false
 	}
 
 	secretID := createClusterRequestBase.SecretId
@@ -140,10 +249,19 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 				Message: "either secret ID or name is required",
 				Error:   "no secret specified",
 			})
-			return
+			return pkgCluster.CreateClusterResponse{}, false
 		}
 	}
 
+	if err := intCluster.ValidateClusterLabels(createClusterRequestBase.Labels); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		})
+		return pkgCluster.CreateClusterResponse{}, false
+	}
+
 	var cluster interface {
 		GetID() uint
 		GetName() string
@@ -153,29 +271,45 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 	case clusterAPI.PKEOnVsphere:
 		var req clusterAPI.CreatePKEOnVsphereClusterRequest
 		if ok := a.parseRequest(c, requestBody, &req); !ok {
-			return
+			return pkgCluster.CreateClusterResponse{}, false
 		}
 		req.SecretId = secretID
+
+		spec := pkeOnVsphereClusterSpec(req, createClusterRequestBase.Labels)
+		if !a.validateClusterCreatePolicy(c, ctx, orgID, spec) {
+			return pkgCluster.CreateClusterResponse{}, false
+		}
+
 		// TODO legacy posthook support if needed
 		params := req.ToVspherePKEClusterCreationParams(orgID, userID)
+		params.Labels = createClusterRequestBase.Labels
+		params.DeletionProtection = createClusterRequestBase.DeletionProtection
 		a.logger.Infof("request: %+v\n\n\nparams: %+v\n\n", req, params)
 		vsphereCluster, err := a.clusterCreators.PKEOnVsphere.Create(ctx, params)
 		if err = errors.WrapIf(err, "failed to create cluster from request"); err != nil {
 			a.handleCreationError(c, err)
-			return
+			return pkgCluster.CreateClusterResponse{}, false
 		}
 		cluster = vsphereCluster
 	case clusterAPI.PKEOnAzure:
 		var req clusterAPI.CreatePKEOnAzureClusterRequest
 		if ok := a.parseRequest(c, requestBody, &req); !ok {
-			return
+			return pkgCluster.CreateClusterResponse{}, false
 		}
 		req.SecretId = secretID
+
+		spec := pkeOnAzureClusterSpec(req, createClusterRequestBase.Labels)
+		if !a.validateClusterCreatePolicy(c, ctx, orgID, spec) {
+			return pkgCluster.CreateClusterResponse{}, false
+		}
+
 		params := req.ToAzurePKEClusterCreationParams(orgID, userID)
+		params.Labels = createClusterRequestBase.Labels
+		params.DeletionProtection = createClusterRequestBase.DeletionProtection
 		azurePKECluster, err := a.clusterCreators.PKEOnAzure.Create(ctx, params)
 		if err = errors.WrapIf(err, "failed to create cluster from request"); err != nil {
 			a.handleCreationError(c, err)
-			return
+			return pkgCluster.CreateClusterResponse{}, false
 		}
 		cluster = azurePKECluster
 	default:
@@ -183,13 +317,13 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 			Code:    http.StatusBadRequest,
 			Message: fmt.Sprintf("unknown cluster type: %s", createClusterRequestBase.Type),
 		})
-		return
+		return pkgCluster.CreateClusterResponse{}, false
 	}
 
-	c.JSON(http.StatusAccepted, pkgCluster.CreateClusterResponse{
+	return pkgCluster.CreateClusterResponse{
 		Name:       cluster.GetName(),
 		ResourceID: cluster.GetID(),
-	})
+	}, true
 }
 
 // createCluster creates a K8S cluster in the cloud.
@@ -0,0 +1,179 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpreflight"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	clusterAPI "github.com/banzaicloud/pipeline/src/api/cluster"
+	"github.com/banzaicloud/pipeline/src/cluster"
+	"github.com/banzaicloud/pipeline/src/secret"
+)
+
+// dryRunCreateClusterFromRequestBody runs every synchronous validation of a (legacy or v2) cluster creation request
+// without persisting the cluster or starting any workflows.
+// It replies with every problem found at once.
+func (a *ClusterAPI) dryRunCreateClusterFromRequestBody(
+	c *gin.Context,
+	ctx context.Context,
+	orgID uint,
+	userID uint,
+	requestBody map[string]interface{},
+) {
+	var request clusterpreflight.Request
+	var violations []string
+
+	if _, ok := requestBody["type"]; !ok {
+		var createClusterRequest pkgCluster.CreateClusterRequest
+		if !a.parseRequest(c, requestBody, &createClusterRequest) {
+			return
+		}
+
+		if createClusterRequest.SecretId == "" && createClusterRequest.SecretName != "" {
+			createClusterRequest.SecretId = secret.GenerateSecretIDFromName(createClusterRequest.SecretName)
+		}
+
+		preflightRequest, err := clusterpreflight.RequestFromCreateRequest(orgID, createClusterRequest)
+		if err != nil {
+			violations = append(violations, err.Error())
+		}
+
+		violations = append(violations, validateCreationFields(orgID, userID, createClusterRequest)...)
+
+		request = preflightRequest
+	} else {
+		v2Request, v2Violations, ok := a.preflightRequestFromV2RequestBody(c, orgID, requestBody)
+		if !ok {
+			return
+		}
+
+		request = v2Request
+		violations = append(violations, v2Violations...)
+	}
+
+	violations = append(violations, a.clusterPreflight.Check(ctx, request)...)
+
+	if len(violations) > 0 {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "cluster creation request is invalid",
+			Error:   strings.Join(violations, "; "),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pkgCluster.CreateClusterResponse{
+		Name: request.Name,
+	})
+}
+
+// validateCreationFields runs the provider specific validation of a legacy cluster creation request.
+func validateCreationFields(orgID uint, userID uint, createClusterRequest pkgCluster.CreateClusterRequest) []string {
+	// the request is copied, because it is completed with defaults
+	commonCluster, err := cluster.CreateCommonClusterFromRequest(&createClusterRequest, orgID, userID)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	// EKS creation fields are covered by the preflight checks
+	if _, ok := commonCluster.(*cluster.EKSCluster); ok {
+		return nil
+	}
+
+	if err := commonCluster.ValidateCreationFields(&createClusterRequest); err != nil {
+		return []string{err.Error()}
+	}
+
+	return nil
+}
+
+// preflightRequestFromV2RequestBody returns the checked parts of a v2 cluster creation request.
+// It replies to the client if the request cannot be parsed and returns false.
+func (a *ClusterAPI) preflightRequestFromV2RequestBody(
+	c *gin.Context,
+	orgID uint,
+	requestBody map[string]interface{},
+) (clusterpreflight.Request, []string, bool) {
+	var createClusterRequestBase pipeline.CreateClusterRequestBase
+	if !a.parseRequest(c, requestBody, &createClusterRequestBase) {
+		return clusterpreflight.Request{}, nil, false
+	}
+
+	var violations []string
+
+	request := clusterpreflight.Request{
+		OrganizationID: orgID,
+		Name:           createClusterRequestBase.Name,
+	}
+
+	secretID := createClusterRequestBase.SecretId
+	if secretID == "" && createClusterRequestBase.SecretName != "" {
+		secretID = secret.GenerateSecretIDFromName(createClusterRequestBase.SecretName)
+	}
+
+	if secretID != "" {
+		request.SecretIDs = []string{secretID}
+	}
+
+	if err := intCluster.ValidateClusterLabels(createClusterRequestBase.Labels); err != nil {
+		violations = append(violations, err.Error())
+	}
+
+	switch createClusterRequestBase.Type {
+	case clusterAPI.PKEOnVsphere:
+		var req clusterAPI.CreatePKEOnVsphereClusterRequest
+		if ok := a.parseRequest(c, requestBody, &req); !ok {
+			return clusterpreflight.Request{}, nil, false
+		}
+
+		request.Cluster = pkeOnVsphereClusterSpec(req, createClusterRequestBase.Labels)
+
+	case clusterAPI.PKEOnAzure:
+		var req clusterAPI.CreatePKEOnAzureClusterRequest
+		if ok := a.parseRequest(c, requestBody, &req); !ok {
+			return clusterpreflight.Request{}, nil, false
+		}
+
+		request.Cluster = pkeOnAzureClusterSpec(req, createClusterRequestBase.Labels)
+		request.ResourceGroup = req.ResourceGroup
+
+		// networks and subnets with CIDR are created with the cluster
+		if req.Network.Name != "" && req.Network.Cidr == "" {
+			request.NetworkID = req.Network.Name
+
+			for _, nodePool := range req.Nodepools {
+				if nodePool.Subnet.Name != "" && nodePool.Subnet.Cidr == "" {
+					request.SubnetIDs = append(request.SubnetIDs, nodePool.Subnet.Name)
+				}
+			}
+		}
+
+	default:
+		violations = append(violations, fmt.Sprintf("unknown cluster type: %s", createClusterRequestBase.Type))
+	}
+
+	return request, violations, true
+}
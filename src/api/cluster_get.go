@@ -98,6 +98,13 @@ func (a *ClusterAPI) GetCluster(c *gin.Context) {
 		CreatorID:   clusterStatus.CreatorId,
 	}
 
+	clusterLabels, err := a.clusterLabels.GetLabels(c.Request.Context(), commonCluster.GetID())
+	if err != nil {
+		errorHandler.Handle(err)
+	} else {
+		response.Labels = clusterLabels
+	}
+
 	// set oidc field on response
 	var oidcCreator = oidc.NewCreator(a.authConfig.OIDC, a.clientSecretGetter)
 	oidcResponse, err := oidcCreator.CreateNewOIDCResponse(c.Request.Context(), clusterStatus.OIDCEnabled, commonCluster.GetID())
@@ -265,6 +272,7 @@ type GetClusterResponse struct {
 	Monitoring   bool                     `json:"monitoring"`
 	SecurityScan bool                     `json:"securityscan"`
 	ScaleOptions *pkgCluster.ScaleOptions `json:"scaleOptions,omitempty" yaml:"scaleOptions,omitempty"`
+	Labels       map[string]string        `json:"labels,omitempty"`
 
 	// TODO: keep one of the following?
 	Version       string `json:"version,omitempty"`
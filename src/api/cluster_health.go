@@ -0,0 +1,83 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterhealth"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterHealthAPI implements the cluster health actions.
+type ClusterHealthAPI struct {
+	clusterAPI   *ClusterAPI
+	store        clusterhealth.Store
+	errorHandler emperror.Handler
+}
+
+// NewClusterHealthAPI returns a new ClusterHealthAPI instance.
+func NewClusterHealthAPI(clusterAPI *ClusterAPI, store clusterhealth.Store, errorHandler emperror.Handler) ClusterHealthAPI {
+	return ClusterHealthAPI{
+		clusterAPI:   clusterAPI,
+		store:        store,
+		errorHandler: errorHandler,
+	}
+}
+
+// GetClusterHealth returns the result of the latest health check of a cluster.
+func (a ClusterHealthAPI) GetClusterHealth(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	commonCluster, ok := a.clusterAPI.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	health, err := a.store.Get(ctx, commonCluster.GetID())
+	if err != nil {
+		var notFoundErr clusterhealth.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			pkgCommon.ErrorResponseWithStatus(c, http.StatusNotFound, err)
+			return
+		}
+
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	checks := make([]pipeline.ClusterHealthCheck, 0, len(health.Checks))
+	for _, check := range health.Checks {
+		checks = append(checks, pipeline.ClusterHealthCheck{
+			Name:    check.Name,
+			Status:  check.Status,
+			Message: check.Message,
+		})
+	}
+
+	c.JSON(http.StatusOK, pipeline.ClusterHealth{
+		Status:         health.Status,
+		Checks:         checks,
+		CheckedAt:      health.CheckedAt,
+		TransitionedAt: health.TransitionedAt,
+	})
+}
@@ -0,0 +1,252 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	clusterAPI "github.com/banzaicloud/pipeline/src/api/cluster"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// ClusterPolicyAPI implements the organization cluster policy actions.
+type ClusterPolicyAPI struct {
+	service      clusterpolicy.Service
+	errorHandler emperror.Handler
+}
+
+// NewClusterPolicyAPI returns a new ClusterPolicyAPI instance.
+func NewClusterPolicyAPI(service clusterpolicy.Service, errorHandler emperror.Handler) ClusterPolicyAPI {
+	return ClusterPolicyAPI{
+		service:      service,
+		errorHandler: errorHandler,
+	}
+}
+
+// GetClusterPolicy returns the cluster policy of an organization.
+func (a ClusterPolicyAPI) GetClusterPolicy(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	policy, err := a.service.GetPolicy(ctx, auth.GetCurrentOrganization(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline.ClusterPolicy{
+		AllowedClouds:             policy.AllowedClouds,
+		AllowedDistributions:      policy.AllowedDistributions,
+		AllowedLocations:          policy.AllowedLocations,
+		AllowedInstanceTypes:      policy.AllowedInstanceTypes,
+		AllowedKubernetesVersions: policy.AllowedKubernetesVersions,
+		MaxNodesPerNodePool:       int32(policy.MaxNodesPerNodePool),
+		MaxNodesPerCluster:        int32(policy.MaxNodesPerCluster),
+		MaxClusters:               int32(policy.MaxClusters),
+		RequiredLabels:            policy.RequiredLabels,
+	})
+}
+
+// SetClusterPolicy creates or replaces the cluster policy of an organization.
+func (a ClusterPolicyAPI) SetClusterPolicy(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	var request pipeline.ClusterPolicy
+	if err := c.ShouldBindJSON(&request); err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err := a.service.SetPolicy(ctx, auth.GetCurrentOrganization(c.Request).ID, clusterpolicy.Policy{
+		AllowedClouds:             request.AllowedClouds,
+		AllowedDistributions:      request.AllowedDistributions,
+		AllowedLocations:          request.AllowedLocations,
+		AllowedInstanceTypes:      request.AllowedInstanceTypes,
+		AllowedKubernetesVersions: request.AllowedKubernetesVersions,
+		MaxNodesPerNodePool:       int(request.MaxNodesPerNodePool),
+		MaxNodesPerCluster:        int(request.MaxNodesPerCluster),
+		MaxClusters:               int(request.MaxClusters),
+		RequiredLabels:            request.RequiredLabels,
+	})
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteClusterPolicy removes the cluster policy of an organization.
+func (a ClusterPolicyAPI) DeleteClusterPolicy(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.service.DeletePolicy(ctx, auth.GetCurrentOrganization(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (a ClusterPolicyAPI) handleError(c *gin.Context, err error) {
+	var notFoundErr interface{ NotFound() bool }
+
+	if errors.As(err, &notFoundErr) && notFoundErr.NotFound() {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusNotFound, err)
+		return
+	}
+
+	if replyWithPolicyViolations(c, err) {
+		return
+	}
+
+	a.errorHandler.Handle(err)
+	pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+}
+
+// validateCreateClusterRequestPolicy checks a legacy cluster creation request against the organization policy.
+// It replies to the client on failure and returns false.
+func (a *ClusterAPI) validateCreateClusterRequestPolicy(
+	c *gin.Context,
+	ctx context.Context,
+	orgID uint,
+	request pkgCluster.CreateClusterRequest,
+) bool {
+	spec, err := clusterpolicy.SpecFromCreateRequest(request)
+	if err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return false
+	}
+
+	return a.validateClusterCreatePolicy(c, ctx, orgID, spec)
+}
+
+// validateClusterCreatePolicy checks a new cluster against the organization policy.
+// It replies to the client on failure and returns false.
+func (a *ClusterAPI) validateClusterCreatePolicy(c *gin.Context, ctx context.Context, orgID uint, spec clusterpolicy.ClusterSpec) bool {
+	err := a.clusterPolicies.ValidateClusterCreate(ctx, orgID, spec)
+	if err != nil {
+		a.handleClusterPolicyError(c, err)
+		return false
+	}
+
+	return true
+}
+
+// validateClusterUpdatePolicy checks the desired state of an updated cluster against the organization policy.
+// It replies to the client on failure and returns false.
+func (a *ClusterAPI) validateClusterUpdatePolicy(c *gin.Context, ctx context.Context, orgID uint, spec clusterpolicy.ClusterSpec) bool {
+	err := a.clusterPolicies.ValidateClusterUpdate(ctx, orgID, spec)
+	if err != nil {
+		a.handleClusterPolicyError(c, err)
+		return false
+	}
+
+	return true
+}
+
+func (a *ClusterAPI) handleClusterPolicyError(c *gin.Context, err error) {
+	if replyWithPolicyViolations(c, err) {
+		return
+	}
+
+	a.errorHandler.Handle(err)
+	pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+}
+
+// replyWithPolicyViolations replies with the violations of a validation error.
+// It returns false if the error is not a validation error.
+func replyWithPolicyViolations(c *gin.Context, err error) bool {
+	var validationErr interface {
+		Validation() bool
+		Violations() []string
+	}
+
+	if !errors.As(err, &validationErr) || !validationErr.Validation() {
+		return false
+	}
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: err.Error(),
+		Error:   strings.Join(validationErr.Violations(), "; "),
+	})
+
+	return true
+}
+
+func pkeOnAzureClusterSpec(req clusterAPI.CreatePKEOnAzureClusterRequest, labels map[string]string) clusterpolicy.ClusterSpec {
+	return clusterpolicy.ClusterSpec{
+		Cloud:             pkgCluster.Azure,
+		Distribution:      pkgCluster.PKE,
+		Location:          req.Location,
+		KubernetesVersion: req.Kubernetes.Version,
+		NodePools:         pkeOnAzureNodePoolSpecs(req.Nodepools),
+		Labels:            labels,
+	}
+}
+
+func pkeOnVsphereClusterSpec(req clusterAPI.CreatePKEOnVsphereClusterRequest, labels map[string]string) clusterpolicy.ClusterSpec {
+	return clusterpolicy.ClusterSpec{
+		Cloud:             pkgCluster.Vsphere,
+		Distribution:      pkgCluster.PKE,
+		KubernetesVersion: req.Kubernetes.Version,
+		NodePools:         pkeOnVsphereNodePoolSpecs(req.Nodepools),
+		Labels:            labels,
+	}
+}
+
+func pkeOnAzureNodePoolSpecs(nodePools []pipeline.PkeOnAzureNodePool) []clusterpolicy.NodePoolSpec {
+	specs := make([]clusterpolicy.NodePoolSpec, 0, len(nodePools))
+
+	for _, nodePool := range nodePools {
+		maxNodes := int(nodePool.Count)
+		if nodePool.Autoscaling && nodePool.MaxCount > nodePool.Count {
+			maxNodes = int(nodePool.MaxCount)
+		}
+
+		specs = append(specs, clusterpolicy.NodePoolSpec{
+			Name:         nodePool.Name,
+			InstanceType: nodePool.InstanceType,
+			MaxNodes:     maxNodes,
+		})
+	}
+
+	return specs
+}
+
+func pkeOnVsphereNodePoolSpecs(nodePools []pipeline.PkeOnVsphereNodePool) []clusterpolicy.NodePoolSpec {
+	specs := make([]clusterpolicy.NodePoolSpec, 0, len(nodePools))
+
+	for _, nodePool := range nodePools {
+		specs = append(specs, clusterpolicy.NodePoolSpec{
+			Name:     nodePool.Name,
+			MaxNodes: int(nodePool.Size),
+		})
+	}
+
+	return specs
+}
@@ -0,0 +1,76 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustertimeline"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterTimelineAPI implements the cluster timeline actions.
+type ClusterTimelineAPI struct {
+	clusterAPI   *ClusterAPI
+	service      clustertimeline.Service
+	errorHandler emperror.Handler
+}
+
+// NewClusterTimelineAPI returns a new ClusterTimelineAPI instance.
+func NewClusterTimelineAPI(clusterAPI *ClusterAPI, service clustertimeline.Service, errorHandler emperror.Handler) ClusterTimelineAPI {
+	return ClusterTimelineAPI{
+		clusterAPI:   clusterAPI,
+		service:      service,
+		errorHandler: errorHandler,
+	}
+}
+
+// GetClusterTimeline returns the status transitions and related process events of a cluster.
+func (a ClusterTimelineAPI) GetClusterTimeline(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	commonCluster, ok := a.clusterAPI.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	entries, err := a.service.GetClusterTimeline(ctx, commonCluster.GetOrganizationId(), commonCluster.GetID())
+	if err != nil {
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := make([]pipeline.ClusterTimelineEntry, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, pipeline.ClusterTimelineEntry{
+			Timestamp:      entry.Timestamp,
+			Type:           entry.Type,
+			Status:         entry.Status,
+			PreviousStatus: entry.PreviousStatus,
+			Message:        entry.Message,
+			UserId:         int32(entry.UserID),
+			ProcessId:      entry.ProcessID,
+			ProcessType:    entry.ProcessType,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
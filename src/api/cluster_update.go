@@ -21,6 +21,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterpolicy"
 	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -41,6 +42,9 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 		return
 	}
 
+	ctx := ginutils.Context(context.Background(), c)
+	orgID := commonCluster.GetOrganizationId()
+
 	var err error
 	if commonCluster.GetDistribution() == pkgCluster.PKE {
 		switch commonCluster.GetCloud() {
@@ -55,6 +59,12 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 				})
 				return
 			}
+
+			spec := clusterpolicy.ClusterSpec{NodePools: pkeOnAzureNodePoolSpecs(updateRequest.Nodepools)}
+			if !a.validateClusterUpdatePolicy(c, ctx, orgID, spec) {
+				return
+			}
+
 			params := updateRequest.ToAzurePKEClusterUpdateParams(commonCluster.GetID(), auth.GetCurrentUser(c.Request).ID)
 			err = a.clusterUpdaters.PKEOnAzure.Update(c, params)
 		case pkgCluster.Vsphere:
@@ -68,6 +78,12 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 				})
 				return
 			}
+
+			spec := clusterpolicy.ClusterSpec{NodePools: pkeOnVsphereNodePoolSpecs(updateRequest.Nodepools)}
+			if !a.validateClusterUpdatePolicy(c, ctx, orgID, spec) {
+				return
+			}
+
 			params := updateRequest.ToVspherePKEClusterUpdateParams(commonCluster.GetID(), auth.GetCurrentUser(c.Request).ID)
 			err = a.clusterUpdaters.PKEOnVsphere.Update(c, params)
 		}
@@ -84,7 +100,17 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 			return
 		}
 
-		ctx := ginutils.Context(context.Background(), c)
+		status, statusErr := commonCluster.GetStatus()
+		if statusErr != nil {
+			errorHandler.Handle(statusErr)
+			pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, statusErr)
+			return
+		}
+
+		spec := clusterpolicy.ApplyUpdateRequest(clusterpolicy.SpecFromClusterStatus(status), *updateRequest)
+		if !a.validateClusterUpdatePolicy(c, ctx, orgID, spec) {
+			return
+		}
 
 		if _, ok := commonCluster.(*cluster.EKSCluster); ok {
 			err = a.clusterUpdaters.EKSAmazon.UpdateCluster(ctx, updateRequest, commonCluster, auth.GetCurrentUser(c.Request).ID)
@@ -157,6 +183,20 @@ func (a *ClusterAPI) UpdateNodePools(c *gin.Context) {
 		return
 	}
 
+	ctx := ginutils.Context(context.Background(), c)
+
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	spec := clusterpolicy.ApplyNodePoolsUpdateRequest(status, *updateRequest)
+	if !a.validateClusterUpdatePolicy(c, ctx, commonCluster.GetOrganizationId(), spec) {
+		return
+	}
+
 	updateCtx := cluster.UpdateContext{
 		OrganizationID: auth.GetCurrentOrganization(c.Request).ID,
 		UserID:         auth.GetCurrentUser(c.Request).ID,
@@ -164,8 +204,7 @@ func (a *ClusterAPI) UpdateNodePools(c *gin.Context) {
 	}
 
 	updater := cluster.NewCommonNodepoolUpdater(updateRequest, commonCluster, updateCtx.UserID)
-	ctx := ginutils.Context(context.Background(), c)
-	err := a.clusterManager.UpdateCluster(ctx, updateCtx, updater)
+	err = a.clusterManager.UpdateCluster(ctx, updateCtx, updater)
 	if err != nil {
 		if isInvalid(err) {
 			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
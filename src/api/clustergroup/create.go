@@ -47,7 +47,7 @@ func (n *API) Create(c *gin.Context) {
 	}
 
 	orgID := auth.GetCurrentOrganization(c.Request).ID
-	id, err := n.clusterGroupManager.CreateClusterGroup(ctx, req.Name, orgID, req.Members)
+	id, err := n.clusterGroupManager.CreateClusterGroup(ctx, req.Name, orgID, req.Members, req.MemberSelector)
 	if err != nil {
 		n.errorHandler.Handle(c, err)
 		return
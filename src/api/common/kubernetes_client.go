@@ -0,0 +1,56 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/pkg/brn"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/src/cluster"
+)
+
+// KubernetesClientFromRequest returns the cluster of the request along with a Kubernetes client created from its config secret.
+// It replies to the client on failure and returns false.
+func KubernetesClientFromRequest(
+	c *gin.Context,
+	clusterGetter ClusterGetter,
+	clientFactory ClientFactory,
+	errorHandler emperror.Handler,
+) (cluster.CommonCluster, kubernetes.Interface, bool) {
+	commonCluster, ok := clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	secretID := brn.New(commonCluster.GetOrganizationId(), brn.SecretResourceType, commonCluster.GetConfigSecretId()).String()
+	client, err := clientFactory.FromSecret(c.Request.Context(), secretID)
+	if err != nil {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error getting kube client",
+			Error:   err.Error(),
+		})
+		return nil, nil, false
+	}
+
+	return commonCluster, client, true
+}
@@ -0,0 +1,93 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// OriginChecker decides whether browser requests from an origin may use endpoints
+// that cannot rely on CORS, such as WebSocket handshakes.
+//
+// Unlike the CORS middleware, it rejects every foreign origin unless it is explicitly allowed.
+type OriginChecker struct {
+	allowedOrigins map[string]bool
+	originsRegexp  *regexp.Regexp
+}
+
+// NewOriginChecker returns a new OriginChecker.
+// Besides the listed and matching origins, the origin of the external Pipeline URL (if any)
+// and the origin of the requested host are allowed.
+func NewOriginChecker(allowedOrigins []string, allowedOriginsRegexp string, externalURL string) (OriginChecker, error) {
+	checker := OriginChecker{
+		allowedOrigins: make(map[string]bool),
+	}
+
+	for _, origin := range allowedOrigins {
+		checker.allowedOrigins[strings.ToLower(origin)] = true
+	}
+
+	if allowedOriginsRegexp != "" {
+		originsRegexp, err := regexp.Compile(fmt.Sprintf("^(%s)$", allowedOriginsRegexp))
+		if err != nil {
+			return checker, errors.WrapIf(err, "invalid allowed origins regexp")
+		}
+
+		checker.originsRegexp = originsRegexp
+	}
+
+	if externalURL != "" {
+		u, err := url.Parse(externalURL)
+		if err != nil {
+			return checker, errors.WrapIf(err, "invalid external URL")
+		}
+
+		if u.Scheme != "" && u.Host != "" {
+			checker.allowedOrigins[strings.ToLower(u.Scheme+"://"+u.Host)] = true
+		}
+	}
+
+	return checker, nil
+}
+
+// Allowed returns true if the origin of the request is allowed.
+// Requests without an Origin header are not sent by browsers on behalf of other sites, so they are allowed.
+func (c OriginChecker) Allowed(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	if strings.EqualFold(u.Host, req.Host) {
+		return true
+	}
+
+	if c.allowedOrigins[strings.ToLower(origin)] {
+		return true
+	}
+
+	return c.originsRegexp != nil && c.originsRegexp.MatchString(origin)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOriginChecker_Allowed(t *testing.T) {
+	checker, err := NewOriginChecker(
+		[]string{"https://ui.example.com"},
+		`https://.*\.banzaicloud\.io`,
+		"https://pipeline.example.com/pipeline",
+	)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		origin  string
+		allowed bool
+	}{
+		"no origin":       {origin: "", allowed: true},
+		"same host":       {origin: "http://localhost:9090", allowed: true},
+		"listed origin":   {origin: "https://ui.example.com", allowed: true},
+		"matching origin": {origin: "https://beta.banzaicloud.io", allowed: true},
+		"external URL":    {origin: "https://pipeline.example.com", allowed: true},
+		"foreign origin":  {origin: "https://evil.example.org", allowed: false},
+		"partial match":   {origin: "https://beta.banzaicloud.io.evil.org", allowed: false},
+		"opaque origin":   {origin: "null", allowed: false},
+		"other scheme":    {origin: "http://ui.example.com", allowed: false},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://localhost:9090/api/v1/orgs/1/clusters/1/pods/default/nginx/exec", nil)
+			if test.origin != "" {
+				req.Header.Set("Origin", test.origin)
+			}
+
+			assert.Equal(t, test.allowed, checker.Allowed(req))
+		})
+	}
+}
+
+func TestNewOriginChecker_InvalidRegexp(t *testing.T) {
+	_, err := NewOriginChecker(nil, "(", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+// StreamWriter writes a stream of messages to the client either as Server-Sent Events
+// or as a chunked HTTP response with one message per line.
+//
+// Messages are flushed to the client as soon as they are written.
+type StreamWriter struct {
+	c           *gin.Context
+	contentType string
+	sse         bool
+	started     bool
+}
+
+// NewStreamWriter returns a new StreamWriter.
+//
+// Server-Sent Events are used when the client accepts text/event-stream,
+// otherwise messages are written as lines of the given content type.
+func NewStreamWriter(c *gin.Context, contentType string) *StreamWriter {
+	return &StreamWriter{
+		c:           c,
+		contentType: contentType,
+		sse:         strings.Contains(c.GetHeader("Accept"), eventStreamContentType),
+	}
+}
+
+// Started returns true if the response headers have already been sent.
+func (w *StreamWriter) Started() bool {
+	return w.started
+}
+
+// Write writes a single message to the stream. The event name is only used by Server-Sent Events.
+func (w *StreamWriter) Write(event string, data []byte) error {
+	if w.sse {
+		w.started = true
+		w.c.SSEvent(event, string(data))
+	} else {
+		if !w.started {
+			w.started = true
+
+			w.c.Header("Content-Type", w.contentType)
+			w.c.Header("X-Content-Type-Options", "nosniff")
+			w.c.Status(http.StatusOK)
+		}
+
+		line := make([]byte, 0, len(data)+1)
+		line = append(append(line, data...), '\n')
+
+		if _, err := w.c.Writer.Write(line); err != nil {
+			return err
+		}
+	}
+
+	w.c.Writer.Flush()
+
+	return w.c.Request.Context().Err()
+}
+
+// WriteError reports an error that happened after the stream has been started.
+// Errors are sent as error events to Server-Sent Events clients and are not reported otherwise.
+func (w *StreamWriter) WriteError(err error) {
+	if !w.sse {
+		return
+	}
+
+	w.c.SSEvent("error", err.Error())
+	w.c.Writer.Flush()
+}
@@ -0,0 +1,67 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Chunked", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+		writer := NewStreamWriter(c, "text/plain; charset=utf-8")
+		assert.False(t, writer.Started())
+
+		require.NoError(t, writer.Write("log", []byte("first line")))
+		require.NoError(t, writer.Write("log", []byte("second line")))
+		writer.WriteError(assert.AnError)
+
+		assert.True(t, writer.Started())
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "first line\nsecond line\n", recorder.Body.String())
+		assert.True(t, recorder.Flushed)
+	})
+
+	t.Run("ServerSentEvents", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept", "text/event-stream")
+
+		writer := NewStreamWriter(c, "text/plain; charset=utf-8")
+
+		require.NoError(t, writer.Write("log", []byte("first line")))
+		writer.WriteError(assert.AnError)
+
+		assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+		assert.Equal(
+			t,
+			"event:log\ndata:first line\n\nevent:error\ndata:"+assert.AnError.Error()+"\n\n",
+			recorder.Body.String(),
+		)
+	})
+}
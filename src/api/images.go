@@ -17,7 +17,6 @@ package api
 import (
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
@@ -28,6 +27,7 @@ import (
 	pkgCommmon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/banzaicloud/pipeline/pkg/k8sutil"
 )
 
 // ListImages list all used images in cluster
@@ -143,28 +143,12 @@ func listAllImages(client *kubernetes.Clientset, labelSelector string) ([]*pipel
 
 func getPodImages(pod v1.Pod) []*pipeline.ClusterImage {
 	images := make([]*pipeline.ClusterImage, 0)
-	for _, container := range pod.Status.ContainerStatuses {
-		fullName := strings.Split(container.Image, ":")
-		var name string
-		tag := "latest"
-		if len(fullName) > 1 {
-			name = fullName[0]
-			tag = fullName[1]
-		}
-		fullDigest := strings.Split(container.ImageID, "@")
-		var digest string
-		if len(fullDigest) > 1 {
-			digest = fullDigest[1]
-		} else {
-			continue
-		}
-
-		image := pipeline.ClusterImage{
-			ImageName:   name,
-			ImageTag:    tag,
-			ImageDigest: digest,
-		}
-		images = append(images, &image)
+	for _, image := range k8sutil.GetPodImages(pod) {
+		images = append(images, &pipeline.ClusterImage{
+			ImageName:   image.Name,
+			ImageTag:    image.Tag,
+			ImageDigest: image.Digest,
+		})
 	}
 	return images
 }
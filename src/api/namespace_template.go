@@ -0,0 +1,136 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusternamespace"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/src/api/cluster/namespace"
+	"github.com/banzaicloud/pipeline/src/auth"
+)
+
+// NamespaceTemplateAPI implements the organization namespace template actions.
+type NamespaceTemplateAPI struct {
+	service      clusternamespace.Service
+	errorHandler emperror.Handler
+}
+
+// NewNamespaceTemplateAPI returns a new NamespaceTemplateAPI instance.
+func NewNamespaceTemplateAPI(service clusternamespace.Service, errorHandler emperror.Handler) NamespaceTemplateAPI {
+	return NamespaceTemplateAPI{
+		service:      service,
+		errorHandler: errorHandler,
+	}
+}
+
+// ListNamespaceTemplates returns the namespace templates of an organization.
+func (a NamespaceTemplateAPI) ListNamespaceTemplates(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	templates, err := a.service.ListTemplates(ctx, auth.GetCurrentOrganization(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	response := make([]pipeline.NamespaceTemplate, 0, len(templates))
+	for _, template := range templates {
+		response = append(response, namespaceTemplateToModel(template))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetNamespaceTemplate returns a namespace template of an organization.
+func (a NamespaceTemplateAPI) GetNamespaceTemplate(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	template, err := a.service.GetTemplate(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("name"))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, namespaceTemplateToModel(template))
+}
+
+// SaveNamespaceTemplate creates or replaces a namespace template of an organization.
+func (a NamespaceTemplateAPI) SaveNamespaceTemplate(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	var request pipeline.SaveNamespaceTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err := a.service.SaveTemplate(ctx, auth.GetCurrentOrganization(c.Request).ID, clusternamespace.Template{
+		Name:        c.Param("name"),
+		Description: request.Description,
+		Spec:        namespace.SpecFromModel(request.Spec),
+	})
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteNamespaceTemplate removes a namespace template of an organization.
+func (a NamespaceTemplateAPI) DeleteNamespaceTemplate(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.service.DeleteTemplate(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("name"))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (a NamespaceTemplateAPI) handleError(c *gin.Context, err error) {
+	var notFoundErr interface{ NotFound() bool }
+
+	if errors.As(err, &notFoundErr) && notFoundErr.NotFound() {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusNotFound, err)
+		return
+	}
+
+	if replyWithPolicyViolations(c, err) {
+		return
+	}
+
+	a.errorHandler.Handle(err)
+	pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+}
+
+func namespaceTemplateToModel(template clusternamespace.Template) pipeline.NamespaceTemplate {
+	return pipeline.NamespaceTemplate{
+		Name:        template.Name,
+		Description: template.Description,
+		Spec:        namespace.SpecToModel(template.Spec),
+	}
+}
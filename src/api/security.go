@@ -30,12 +30,15 @@ import (
 
 	internalCommon "github.com/banzaicloud/pipeline/internal/common"
 	"github.com/banzaicloud/pipeline/internal/helm"
+	"github.com/banzaicloud/pipeline/internal/integratedservices"
 	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/internal/security/vulnreport"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/banzaicloud/pipeline/pkg/security"
 	apiCommon "github.com/banzaicloud/pipeline/src/api/common"
+	"github.com/banzaicloud/pipeline/src/auth"
 	legacyHelm "github.com/banzaicloud/pipeline/src/helm"
 )
 
@@ -258,7 +261,8 @@ func GetReleaseScanLog(c *gin.Context) (map[string]bool, bool) {
 	}
 
 	for _, audit := range audits.Items {
-		if audit.Spec.Action == "reject" {
+		// releases admitted in warn or audit mode or by break-glass are not considered rejected
+		if anchore.NewScanLogItem(audit).Blocked {
 			releaseScanLogReject[audit.Spec.ReleaseName] = true
 		}
 	}
@@ -410,7 +414,25 @@ func (s securityHandlers) ListScanLogs(c *gin.Context) {
 		return
 	}
 
-	scanlogs, err := s.resourceService.ListScanLogs(c.Request.Context(), cluster)
+	filter := anchore.ScanLogFilter{
+		Mode: c.Query("mode"),
+	}
+
+	if wouldBlock := c.Query("wouldBlock"); wouldBlock != "" {
+		value, err := strconv.ParseBool(wouldBlock)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid wouldBlock parameter",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		filter.WouldBlock = &value
+	}
+
+	scanlogs, err := s.resourceService.ListScanLogs(c.Request.Context(), cluster, filter)
 	if err != nil {
 		s.errorHandler.HandleContext(c.Request.Context(), err)
 
@@ -454,3 +476,215 @@ func (s securityHandlers) successResponse(ginCtx *gin.Context, payload interface
 	ginCtx.JSON(http.StatusOK, payload)
 	return
 }
+
+// ImageScanStarter starts image scans in the background.
+type ImageScanStarter interface {
+	// StartImageScan starts the scan of an image running in a cluster.
+	StartImageScan(ctx context.Context, organizationID uint, clusterID uint, image security.Image) error
+}
+
+// ImageScanHandler serves image vulnerabilities regardless of the scanner backend of the cluster
+type ImageScanHandler struct {
+	clusterGetter   apiCommon.ClusterGetter
+	imageScanner    anchore.ImageScanner
+	scannerProvider anchore.ScannerProvider
+	scanStarter     ImageScanStarter
+	errorHandler    internalCommon.ErrorHandler
+	logger          internalCommon.Logger
+}
+
+func NewImageScanHandler(
+	clusterGetter apiCommon.ClusterGetter,
+	imageScanner anchore.ImageScanner,
+	scannerProvider anchore.ScannerProvider,
+	scanStarter ImageScanStarter,
+	errorHandler internalCommon.ErrorHandler,
+	logger internalCommon.Logger,
+) ImageScanHandler {
+	return ImageScanHandler{
+		clusterGetter:   clusterGetter,
+		imageScanner:    imageScanner,
+		scannerProvider: scannerProvider,
+		scanStarter:     scanStarter,
+		errorHandler:    errorHandler,
+		logger:          logger,
+	}
+}
+
+// GetImageVulnerabilities returns the vulnerabilities of the image identified by its digest and the image query parameter
+func (h ImageScanHandler) GetImageVulnerabilities(c *gin.Context) {
+	image, ok := imageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	cluster, ok := h.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		h.logger.Warn("failed to retrieve cluster based on the request")
+
+		return
+	}
+
+	vulnerabilities, err := h.imageScanner.GetImageVulnerabilities(c.Request.Context(), cluster, image)
+	if integratedservices.IsIntegratedServiceNotFoundError(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "security scan is not enabled for the cluster",
+			Error:   errors.Cause(err).Error(),
+		})
+		return
+	}
+	var notFoundErr anchore.ImageScanNotFoundError
+	if errors.As(err, &notFoundErr) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "the image has not been scanned yet",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.errorHandler.HandleContext(c.Request.Context(), err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "failed to retrieve image vulnerabilities",
+			Error:   errors.Cause(err).Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, vulnerabilities)
+}
+
+// ScanImage starts the scan of the image identified by its digest and the image query parameter in the background.
+// Only the Trivy backend scans on demand, Anchore evaluates the images when they are deployed.
+func (h ImageScanHandler) ScanImage(c *gin.Context) {
+	image, ok := imageFromRequest(c)
+	if !ok {
+		return
+	}
+
+	cluster, ok := h.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		h.logger.Warn("failed to retrieve cluster based on the request")
+
+		return
+	}
+
+	scanner, err := h.scannerProvider.GetScanner(c.Request.Context(), cluster.GetID())
+	if integratedservices.IsIntegratedServiceNotFoundError(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "security scan is not enabled for the cluster",
+			Error:   errors.Cause(err).Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.errorHandler.HandleContext(c.Request.Context(), err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "failed to get the scanner backend of the cluster",
+			Error:   errors.Cause(err).Error(),
+		})
+		return
+	}
+
+	if scanner != anchore.ScannerTrivy {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "on-demand scans are not supported by the scanner backend of the cluster",
+			Error:   fmt.Sprintf("on-demand scans are not supported by %s", scanner),
+		})
+		return
+	}
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	if err := h.scanStarter.StartImageScan(c.Request.Context(), organizationID, cluster.GetID(), image); err != nil {
+		h.errorHandler.HandleContext(c.Request.Context(), err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "failed to start image scan",
+			Error:   errors.Cause(err).Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// imageFromRequest parses the image identified by the request and writes an error response if it is invalid.
+func imageFromRequest(c *gin.Context) (security.Image, bool) {
+	imageDigest := c.Param("imageDigest")
+	re := regexp.MustCompile("^sha256:[a-f0-9]{64}$")
+	if !re.MatchString(imageDigest) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid image digest format",
+			Error:   fmt.Sprintf("invalid imageID format: %s", imageDigest),
+		})
+		return security.Image{}, false
+	}
+
+	imageName := c.Query("image")
+	if imageName == "" {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "image name is required",
+			Error:   "image name is required",
+		})
+		return security.Image{}, false
+	}
+
+	return security.Image{
+		Name:   imageName,
+		Tag:    c.Query("tag"),
+		Digest: imageDigest,
+	}, true
+}
+
+// VulnerabilityReportHandler serves the organization level vulnerability reports of the scheduled image rescans
+type VulnerabilityReportHandler struct {
+	service      vulnreport.Service
+	errorHandler internalCommon.ErrorHandler
+}
+
+func NewVulnerabilityReportHandler(service vulnreport.Service, errorHandler internalCommon.ErrorHandler) VulnerabilityReportHandler {
+	return VulnerabilityReportHandler{
+		service:      service,
+		errorHandler: errorHandler,
+	}
+}
+
+// GetReport returns the vulnerabilities of the running images of the organization aggregated by severity, cluster, namespace and release
+func (h VulnerabilityReportHandler) GetReport(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	report, err := h.service.GetReport(c.Request.Context(), organizationID)
+	if err != nil {
+		var notFoundErr vulnreport.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "no vulnerability report is available yet",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		h.errorHandler.HandleContext(c.Request.Context(), err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "failed to retrieve vulnerability report",
+			Error:   errors.Cause(err).Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
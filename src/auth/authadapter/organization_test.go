@@ -16,7 +16,6 @@ package authadapter
 
 import (
 	"context"
-	"encoding/json"
 	"testing"
 	"time"
 
@@ -36,7 +35,8 @@ func TestOrganizationSyncer_SyncOrganizations(t *testing.T) {
 	store := NewGormOrganizationStore(db)
 	publisher := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
 	const topic = "auth"
-	eventBus, _ := cqrs.NewEventBus(publisher, func(_ string) string { return topic }, &cqrs.JSONMarshaler{})
+	marshaler := &cqrs.JSONMarshaler{}
+	eventBus, _ := cqrs.NewEventBus(publisher, func(_ string) string { return topic }, marshaler)
 
 	messages, err := publisher.Subscribe(context.Background(), topic)
 	require.NoError(t, err)
@@ -145,6 +145,49 @@ func TestOrganizationSyncer_SyncOrganizations(t *testing.T) {
 	err = syncer.SyncOrganizations(context.Background(), user, upstreamMemberships)
 	require.NoError(t, err)
 
+	// one organization is created and the memberships of five organizations change
+	received, all := subscriber.BulkRead(messages, 6, time.Second)
+	if !all {
+		t.Fatal("not all messages received")
+	}
+
+	var createdEvents []auth.OrganizationCreated
+	changedOrganizationIDs := make(map[uint]bool)
+
+	for _, msg := range received {
+		switch marshaler.NameFromMessage(msg) {
+		case marshaler.Name(auth.OrganizationCreated{}):
+			var event auth.OrganizationCreated
+
+			err := marshaler.Unmarshal(msg, &event)
+			require.NoError(t, err)
+
+			createdEvents = append(createdEvents, event)
+
+		case marshaler.Name(auth.OrganizationMembershipChanged{}):
+			var event auth.OrganizationMembershipChanged
+
+			err := marshaler.Unmarshal(msg, &event)
+			require.NoError(t, err)
+
+			assert.Equal(t, user.ID, event.UserID)
+
+			changedOrganizationIDs[event.OrganizationID] = true
+
+		default:
+			t.Fatalf("unexpected event: %s", marshaler.NameFromMessage(msg))
+		}
+	}
+
+	for _, name := range []string{"lose-access", "change-role-to-member", "change-role-to-admin", "new-org", "add-to-existing-org"} {
+		var organization auth.Organization
+
+		err := db.Where(auth.Organization{Name: name}).First(&organization).Error
+		require.NoError(t, err)
+
+		assert.True(t, changedOrganizationIDs[organization.ID], "membership change of %s", name)
+	}
+
 	for _, m := range upstreamMemberships {
 		var organization auth.Organization
 
@@ -165,23 +208,15 @@ func TestOrganizationSyncer_SyncOrganizations(t *testing.T) {
 		assert.Equal(t, m.Role, membership.Role)
 
 		if m.Organization.Name == "new-org" {
-			received, all := subscriber.BulkRead(messages, 1, time.Second)
-			if !all {
-				t.Fatal("no message received")
-			}
-
-			var event auth.OrganizationCreated
-
-			err := json.Unmarshal(received[0].Payload, &event)
-			require.NoError(t, err)
-
 			assert.Equal(
 				t,
-				auth.OrganizationCreated{
-					ID:     organization.ID,
-					UserID: user.ID,
+				[]auth.OrganizationCreated{
+					{
+						ID:     organization.ID,
+						UserID: user.ID,
+					},
 				},
-				event,
+				createdEvents,
 			)
 		}
 	}
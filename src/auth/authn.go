@@ -119,7 +119,7 @@ func (r redirector) Redirect(w http.ResponseWriter, req *http.Request, action st
 }
 
 // Init initializes the auth
-func Init(db *gorm.DB, config Config, tokenStore bauth.TokenStore, tokenManager TokenManager, orgSyncer OIDCOrganizationSyncer, serviceAccountService ServiceAccountService) {
+func Init(db *gorm.DB, config Config, tokenStore bauth.TokenStore, tokenManager TokenManager, orgSyncer OIDCOrganizationSyncer, orgEvents OrganizationEvents, serviceAccountService ServiceAccountService) {
 	CookieDomain = config.Cookie.Domain
 
 	signingKey := config.Token.SigningKey
@@ -166,7 +166,7 @@ func Init(db *gorm.DB, config Config, tokenStore bauth.TokenStore, tokenManager
 		LoginHandler:      banzaiLoginHandler,
 		LogoutHandler:     banzaiLogoutHandler,
 		RegisterHandler:   banzaiRegisterHandler,
-		DeregisterHandler: NewBanzaiDeregisterHandler(tokenStore),
+		DeregisterHandler: NewBanzaiDeregisterHandler(tokenStore, orgEvents),
 	})
 
 	oidcProvider = newOIDCProvider(&OIDCProviderConfig{
@@ -365,12 +365,14 @@ func banzaiRegisterHandler(context *auth.Context, register func(*auth.Context) (
 
 type banzaiDeregisterHandler struct {
 	tokenStore bauth.TokenStore
+	events     OrganizationEvents
 }
 
 // NewBanzaiDeregisterHandler returns a handler that deletes the user and all his/her tokens from the database
-func NewBanzaiDeregisterHandler(tokenStore bauth.TokenStore) func(*auth.Context) {
+func NewBanzaiDeregisterHandler(tokenStore bauth.TokenStore, events OrganizationEvents) func(*auth.Context) {
 	handler := &banzaiDeregisterHandler{
 		tokenStore: tokenStore,
+		events:     events,
 	}
 
 	return handler.handler
@@ -382,6 +384,13 @@ func (h *banzaiDeregisterHandler) handler(context *auth.Context) {
 
 	db := context.GetDB(context.Request)
 
+	var organizations []Organization
+	if err := db.Model(user).Association("Organizations").Find(&organizations).Error; err != nil {
+		errorHandler.Handle(errors.Wrap(err, "failed to list user's organizations"))
+		http.Error(context.Writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// Remove organization memberships
 	if err := db.Model(user).Association("Organizations").Clear().Error; err != nil {
 		errorHandler.Handle(errors.Wrap(err, "failed delete user's organization associations"))
@@ -389,6 +398,18 @@ func (h *banzaiDeregisterHandler) handler(context *auth.Context) {
 		return
 	}
 
+	for _, organization := range organizations {
+		event := OrganizationMembershipChanged{
+			OrganizationID: organization.ID,
+			UserID:         user.ID,
+		}
+
+		// the memberships are already removed, so a failing dispatch should not fail the deregistration
+		if err := h.events.OrganizationMembershipChanged(context.Request.Context(), event); err != nil {
+			errorHandler.Handle(err)
+		}
+	}
+
 	if err := db.Delete(user).Error; err != nil {
 		errorHandler.Handle(errors.Wrap(err, "failed delete user from DB"))
 		http.Error(context.Writer, err.Error(), http.StatusInternalServerError)
@@ -127,6 +127,11 @@ func (e RbacEnforcer) Enforce(org *Organization, user *User, path, method string
 			return false, nil
 		}
 
+		// Members cannot open interactive sessions in pods (those run with the admin kube config)
+		if ok, err := regexp.MatchString(`^/api/v1/orgs/\d+/clusters/[^/]+/pods/[^/]+/[^/]+/(?:exec|attach)$`, path); err != nil || ok {
+			return false, nil
+		}
+
 		// Members cannot access secrets at all
 		if ok, err := regexp.MatchString(`^/api/v1/orgs/\d+/secrets(?:/.*)?$`, path); err != nil || ok {
 			return false, errors.WithStackIf(err)
@@ -244,6 +244,30 @@ func TestRbacEnforcer_Enforce(t *testing.T) {
 			method:   "GET",
 			expected: false,
 		},
+		{
+			role:     RoleAdmin,
+			path:     "/api/v1/orgs/1/clusters/1/pods/default/app/exec",
+			method:   "GET",
+			expected: true,
+		},
+		{
+			role:     RoleMember,
+			path:     "/api/v1/orgs/1/clusters/1/pods/default/app/exec",
+			method:   "GET",
+			expected: false,
+		},
+		{
+			role:     RoleMember,
+			path:     "/api/v1/orgs/1/clusters/1/pods/default/app/attach",
+			method:   "GET",
+			expected: false,
+		},
+		{
+			role:     RoleMember,
+			path:     "/api/v1/orgs/1/clusters/1/pods/default/app/logs",
+			method:   "GET",
+			expected: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -147,6 +147,9 @@ type OrganizationStore interface {
 type OrganizationEvents interface {
 	// OrganizationCreated dispatches an OrganizationCreated event.
 	OrganizationCreated(ctx context.Context, event OrganizationCreated) error
+
+	// OrganizationMembershipChanged dispatches an OrganizationMembershipChanged event.
+	OrganizationMembershipChanged(ctx context.Context, event OrganizationMembershipChanged) error
 }
 
 // OrganizationCreated event is triggered when an organization is created in the system.
@@ -158,6 +161,16 @@ type OrganizationCreated struct {
 	UserID uint
 }
 
+// OrganizationMembershipChanged event is triggered when a user is added to or removed from an organization
+// or the role of a user in an organization changes.
+type OrganizationMembershipChanged struct {
+	// OrganizationID is the ID of the organization whose members changed.
+	OrganizationID uint
+
+	// UserID is the ID of the user whose membership changed.
+	UserID uint
+}
+
 // UpstreamOrganizationMembership represents an organization membership of a user
 // from the upstream authentication source.
 type UpstreamOrganizationMembership struct {
@@ -234,6 +247,11 @@ func (s organizationSyncer) SyncOrganizations(ctx context.Context, user User, up
 				return err
 			}
 
+			err = s.membershipChanged(ctx, currentMembership.OrganizationID, user.ID)
+			if err != nil {
+				return err
+			}
+
 			continue
 		}
 
@@ -259,6 +277,11 @@ func (s organizationSyncer) SyncOrganizations(ctx context.Context, user User, up
 			return err
 		}
 
+		err = s.membershipChanged(ctx, currentMembership.OrganizationID, user.ID)
+		if err != nil {
+			return err
+		}
+
 		// Membership already exists, no need to add
 		delete(membershipsToAdd, currentMembership.Organization.Name)
 	}
@@ -273,9 +296,23 @@ func (s organizationSyncer) SyncOrganizations(ctx context.Context, user User, up
 		if err != nil {
 			return err
 		}
+
+		err = s.membershipChanged(ctx, organizations[organizationName], user.ID)
+		if err != nil {
+			return err
+		}
 	}
 
 	logger.Info("organizations synchronized successfully for user")
 
 	return nil
 }
+
+func (s organizationSyncer) membershipChanged(ctx context.Context, organizationID uint, userID uint) error {
+	event := OrganizationMembershipChanged{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	}
+
+	return s.events.OrganizationMembershipChanged(ctx, event)
+}
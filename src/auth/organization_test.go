@@ -129,6 +129,10 @@ func TestOrganizationSyncer_SyncOrganizations(t *testing.T) { // TODO: rewrite t
 	store.On("ApplyUserMembership", ctx, currentMemberships[2].OrganizationID, user.ID, RoleAdmin).Return(nil)
 	store.On("ApplyUserMembership", ctx, uint(5), user.ID, RoleAdmin).Return(nil)
 
+	for _, organizationID := range []uint{2, 3, 4, 5} {
+		events.On("OrganizationMembershipChanged", ctx, OrganizationMembershipChanged{organizationID, user.ID}).Return(nil)
+	}
+
 	err := syncer.SyncOrganizations(ctx, user, upstreamMemberships)
 	require.NoError(t, err)
 
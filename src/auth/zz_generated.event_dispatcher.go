@@ -34,3 +34,13 @@ func (d OrganizationEventDispatcher) OrganizationCreated(ctx context.Context, ev
 
 	return nil
 }
+
+// OrganizationMembershipChanged dispatches a(n) OrganizationMembershipChanged event.
+func (d OrganizationEventDispatcher) OrganizationMembershipChanged(ctx context.Context, event OrganizationMembershipChanged) error {
+	err := d.bus.Publish(ctx, event)
+	if err != nil {
+		return errors.WithDetails(errors.WithMessage(err, "failed to dispatch event"), "event", "OrganizationMembershipChanged")
+	}
+
+	return nil
+}
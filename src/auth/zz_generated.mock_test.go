@@ -182,3 +182,17 @@ func (_m *MockOrganizationEvents) OrganizationCreated(ctx context.Context, event
 
 	return r0
 }
+
+// OrganizationMembershipChanged provides a mock function.
+func (_m *MockOrganizationEvents) OrganizationMembershipChanged(ctx context.Context, event OrganizationMembershipChanged) error {
+	ret := _m.Called(ctx, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, OrganizationMembershipChanged) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -17,6 +17,7 @@ package cluster
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -38,6 +39,7 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/providers/alibaba/alibabaadapter"
 	"github.com/banzaicloud/pipeline/internal/secret/ssh/sshadapter"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
@@ -319,7 +321,9 @@ func CreateACKClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId
 			NodePools:                nodePools,
 			VSwitchID:                request.Properties.CreateClusterACK.VSwitchID,
 		},
-		CreatedBy: userId,
+		CreatedBy:          userId,
+		DeletionProtection: request.DeletionProtection,
+		Labels:             clustermodel.NewLabelModels(request.Labels),
 	}
 	updateScaleOptions(&cluster.modelCluster.ScaleOptions, request.ScaleOptions)
 
@@ -813,6 +817,11 @@ func (c *ACKCluster) SetStatus(status, statusMessage string) error {
 	return c.modelCluster.UpdateStatus(status, statusMessage)
 }
 
+// SetStatusWithContext sets the cluster's status recording the user and the process acting on the cluster from the context
+func (c *ACKCluster) SetStatusWithContext(ctx context.Context, status, statusMessage string) error {
+	return c.modelCluster.UpdateStatusWithContext(ctx, status, statusMessage)
+}
+
 // IsReady checks if the cluster is running according to the cloud provider.
 func (c *ACKCluster) IsReady() (bool, error) {
 	client, err := c.GetAlibabaCSClient(nil)
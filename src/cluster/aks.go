@@ -31,6 +31,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/sirupsen/logrus"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/global"
 	internalAzure "github.com/banzaicloud/pipeline/internal/providers/azure"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/azureadapter"
@@ -72,19 +73,22 @@ func CreateAKSClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgID
 			NodeInstanceType: np.NodeInstanceType,
 			VNetSubnetID:     np.VNetSubnetID,
 			Labels:           np.Labels,
+			Taints:           np.Taints,
 		})
 	}
 
 	var cluster AKSCluster
 
 	cluster.modelCluster = &model.ClusterModel{
-		Name:           request.Name,
-		Location:       request.Location,
-		Cloud:          request.Cloud,
-		OrganizationId: orgID,
-		CreatedBy:      userID,
-		SecretId:       request.SecretId,
-		Distribution:   pkgCluster.AKS,
+		Name:               request.Name,
+		Location:           request.Location,
+		Cloud:              request.Cloud,
+		OrganizationId:     orgID,
+		CreatedBy:          userID,
+		SecretId:           request.SecretId,
+		Distribution:       pkgCluster.AKS,
+		DeletionProtection: request.DeletionProtection,
+		Labels:             clustermodel.NewLabelModels(request.Labels),
 		AKS: azureadapter.AKSClusterModel{
 			ResourceGroup:     request.Properties.CreateClusterAKS.ResourceGroup,
 			KubernetesVersion: request.Properties.CreateClusterAKS.KubernetesVersion,
@@ -189,6 +193,17 @@ func getVNetSubnetID(np *azureadapter.AKSNodePoolModel) *string {
 	return &np.VNetSubnetID
 }
 
+func getNodeTaints(np *azureadapter.AKSNodePoolModel) *[]string {
+	if len(np.Taints) == 0 {
+		return nil
+	}
+	taints := make([]string, 0, len(np.Taints))
+	for _, taint := range np.Taints {
+		taints = append(taints, taint.String())
+	}
+	return &taints
+}
+
 // CreateCluster creates a new cluster
 func (c *AKSCluster) CreateCluster() error {
 	c.log.Info("Creating cluster...")
@@ -214,6 +229,7 @@ func (c *AKSCluster) CreateCluster() error {
 				NodeLabels: map[string]*string{
 					pkgCommon.LabelKey: &name,
 				},
+				NodeTaints: getNodeTaints(np),
 			})
 		}
 	}
@@ -730,6 +746,38 @@ func (c *AKSCluster) CheckEqualityToUpdate(r *pkgCluster.UpdateClusterRequest) e
 	return isDifferent(r.AKS, preCl)
 }
 
+// GetCreateClusterRequest reconstructs a cluster creation request from the stored cluster model
+func (c *AKSCluster) GetCreateClusterRequest() (*pkgCluster.CreateClusterRequest, error) {
+	nodePools := make(map[string]*pkgClusterAzure.NodePoolCreate, len(c.modelCluster.AKS.NodePools))
+	for _, np := range c.modelCluster.AKS.NodePools {
+		if np != nil {
+			nodePools[np.Name] = &pkgClusterAzure.NodePoolCreate{
+				Autoscaling:      np.Autoscaling,
+				MinCount:         np.NodeMinCount,
+				MaxCount:         np.NodeMaxCount,
+				Count:            np.Count,
+				NodeInstanceType: np.NodeInstanceType,
+				VNetSubnetID:     np.VNetSubnetID,
+			}
+		}
+	}
+
+	return &pkgCluster.CreateClusterRequest{
+		Name:         c.modelCluster.Name,
+		Location:     c.modelCluster.Location,
+		Cloud:        c.modelCluster.Cloud,
+		SecretId:     c.modelCluster.SecretId,
+		ScaleOptions: c.GetScaleOptions(),
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterAKS: &pkgClusterAzure.CreateClusterAKS{
+				ResourceGroup:     c.modelCluster.AKS.ResourceGroup,
+				KubernetesVersion: c.modelCluster.AKS.KubernetesVersion,
+				NodePools:         nodePools,
+			},
+		},
+	}, nil
+}
+
 // DeleteFromDatabase deletes model from the database
 func (c *AKSCluster) DeleteFromDatabase() error {
 	err := c.modelCluster.Delete()
@@ -811,6 +859,11 @@ func (c *AKSCluster) SetStatus(status, statusMessage string) error {
 	return c.modelCluster.UpdateStatus(status, statusMessage)
 }
 
+// SetStatusWithContext sets the cluster's status recording the user and the process acting on the cluster from the context
+func (c *AKSCluster) SetStatusWithContext(ctx context.Context, status, statusMessage string) error {
+	return c.modelCluster.UpdateStatusWithContext(ctx, status, statusMessage)
+}
+
 // NodePoolExists returns true if node pool with nodePoolName exists
 func (c *AKSCluster) NodePoolExists(nodePoolName string) bool {
 	for _, np := range c.modelCluster.AKS.NodePools {
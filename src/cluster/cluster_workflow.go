@@ -16,6 +16,10 @@ package cluster
 
 import (
 	"context"
+
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
 )
 
 const UpdateClusterStatusActivityName = "update-cluster-status"
@@ -42,5 +46,7 @@ func (a *UpdateClusterStatusActivity) Execute(ctx context.Context, input UpdateC
 		return err
 	}
 
-	return c.SetStatus(input.Status, input.StatusMessage)
+	ctx = ctxutil.WithProcessID(ctx, activity.GetInfo(ctx).WorkflowExecution.ID)
+
+	return SetClusterStatus(ctx, c, input.Status, input.StatusMessage)
 }
@@ -15,6 +15,7 @@
 package cluster
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net"
@@ -98,6 +99,20 @@ type CommonCluster interface {
 	SetStatus(status, statusMessage string) error
 }
 
+// statusSetterWithContext is implemented by clusters that can record the actor of a status change.
+type statusSetterWithContext interface {
+	SetStatusWithContext(ctx context.Context, status, statusMessage string) error
+}
+
+// SetClusterStatus sets the status of a cluster recording the user and the process from the context (when supported).
+func SetClusterStatus(ctx context.Context, cluster CommonCluster, status, statusMessage string) error {
+	if c, ok := cluster.(statusSetterWithContext); ok {
+		return c.SetStatusWithContext(ctx, status, statusMessage)
+	}
+
+	return cluster.SetStatus(status, statusMessage)
+}
+
 // CommonClusterBase holds the fields that is common to all cluster types
 // also provides default implementation for common interface methods.
 type CommonClusterBase struct {
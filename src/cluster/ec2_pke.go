@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"emperror.dev/errors"
@@ -35,6 +36,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.uber.org/cadence/client"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/global"
@@ -166,7 +168,12 @@ func (c *EC2ClusterPKE) Persist() error {
 }
 
 // SetStatus sets the cluster's status
-func (c *EC2ClusterPKE) SetStatus(status, statusMessage string) error {
+func (c *EC2ClusterPKE) SetStatus(status string, statusMessage string) error {
+	return c.SetStatusWithContext(context.Background(), status, statusMessage)
+}
+
+// SetStatusWithContext sets the cluster's status recording the user and the process acting on the cluster from the context
+func (c *EC2ClusterPKE) SetStatusWithContext(ctx context.Context, status string, statusMessage string) error {
 	if c.model.Cluster.Status == status && c.model.Cluster.StatusMessage == statusMessage {
 		return nil
 	}
@@ -183,6 +190,7 @@ func (c *EC2ClusterPKE) SetStatus(status, statusMessage string) error {
 			ToStatus:          status,
 			ToStatusMessage:   statusMessage,
 		}
+		statusHistory.SetActor(ctx)
 
 		if err := c.db.Save(&statusHistory).Error; err != nil {
 			return errors.Wrap(err, "failed to record cluster status change to history")
@@ -324,6 +332,10 @@ func (c *EC2ClusterPKE) ValidateCreationFields(r *pkgCluster.CreateClusterReques
 		if err := common.ValidateNodePoolLabels(np.Name, np.Labels); err != nil {
 			return err
 		}
+
+		if err := cluster.ValidateNodePoolTaints(np.Name, np.Taints); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -361,6 +373,7 @@ func createNodePoolsFromPKERequest(nodePools pke.UpdateNodePools) []pkeworkflow.
 			Autoscaling:  nodePool.Autoscaling,
 			InstanceType: nodePool.InstanceType,
 			SpotPrice:    nodePool.SpotPrice,
+			Taints:       nodePool.Taints,
 		}
 		for _, subnet := range nodePool.Subnets {
 			out[i].Subnets = append(out[i].Subnets, string(subnet))
@@ -465,6 +478,11 @@ func (c *EC2ClusterPKE) UpdatePKECluster(ctx context.Context, request *pkgCluste
 		if reqNodePool, ok := reqNodePoolsMap[np.Name]; ok { // update
 			np.Autoscaling = reqNodePool.Autoscaling
 
+			// nil taints are left intact, an empty list removes them
+			if reqNodePool.Taints != nil {
+				np.Taints = reqNodePool.Taints
+			}
+
 			providerConfig := internalPke.NodePoolProviderConfigAmazon{}
 			if err := mapstructure.Decode(np.ProviderConfig, &providerConfig); err != nil {
 				return errors.WrapIff(err, "decoding nodepool %q config", np.Name)
@@ -504,6 +522,7 @@ func (c *EC2ClusterPKE) UpdatePKECluster(ctx context.Context, request *pkgCluste
 				Provider:    internalPke.NPPAmazon,
 				ProviderConfig: internalPke.Config{
 					"autoScalingGroup": providerConfig.AutoScalingGroup},
+				Taints: np.Taints,
 			}
 			newModelNodePools = append(newModelNodePools, modelNodepool)
 		}
@@ -571,6 +590,60 @@ func (c *EC2ClusterPKE) CheckEqualityToUpdate(*pkgCluster.UpdateClusterRequest)
 func (c *EC2ClusterPKE) AddDefaultsToUpdate(*pkgCluster.UpdateClusterRequest) {
 }
 
+// GetCreateClusterRequest reconstructs a cluster creation request from the stored cluster model
+func (c *EC2ClusterPKE) GetCreateClusterRequest() (*pkgCluster.CreateClusterRequest, error) {
+	nodePools := make(pke.NodePools, 0, len(c.model.NodePools))
+	for _, np := range c.model.NodePools {
+		roles := make(pke.Roles, 0, len(np.Roles))
+		for _, role := range np.Roles {
+			roles = append(roles, pke.Role(role))
+		}
+
+		nodePools = append(nodePools, pke.NodePool{
+			Name:           np.Name,
+			Roles:          roles,
+			Provider:       pke.NodePoolProvider(np.Provider),
+			ProviderConfig: np.ProviderConfig,
+			Autoscaling:    np.Autoscaling,
+		})
+	}
+
+	extraArgs := make(pke.ExtraArgs, 0, len(c.model.KubeADM.ExtraArgs))
+	for _, arg := range c.model.KubeADM.ExtraArgs {
+		extraArgs = append(extraArgs, pke.ExtraArg(arg))
+	}
+
+	return &pkgCluster.CreateClusterRequest{
+		Name:         c.model.Cluster.Name,
+		Location:     c.model.Cluster.Location,
+		Cloud:        c.model.Cluster.Cloud,
+		SecretId:     c.model.Cluster.SecretID,
+		ScaleOptions: c.GetScaleOptions(),
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterPKE: &pke.CreateClusterPKE{
+				Network: pke.Network{
+					ServiceCIDR: c.model.Network.ServiceCIDR,
+					PodCIDR:     c.model.Network.PodCIDR,
+					Provider:    pke.NetworkProvider(c.model.Network.Provider),
+				},
+				NodePools: nodePools,
+				Kubernetes: pke.Kubernetes{
+					Version: c.model.Kubernetes.Version,
+					RBAC:    pke.RBAC{Enabled: c.model.Kubernetes.RBAC.Enabled},
+					OIDC:    pke.OIDC{Enabled: c.model.Cluster.OidcEnabled},
+				},
+				KubeADM: pke.KubeADM{
+					ExtraArgs: extraArgs,
+				},
+				CRI: pke.CRI{
+					Runtime:       pke.Runtime(c.model.CRI.Runtime),
+					RuntimeConfig: c.model.CRI.RuntimeConfig,
+				},
+			},
+		},
+	}, nil
+}
+
 func (c *EC2ClusterPKE) DeleteCluster() error {
 	return errors.New("not implemented")
 }
@@ -922,7 +995,7 @@ func (c *EC2ClusterPKE) GetBootstrapCommand(nodePoolName, url string, urlInsecur
 	}
 
 	// worker
-	return fmt.Sprintf("pke install %s "+
+	command := fmt.Sprintf("pke install %s "+
 		"--pipeline-url=%q "+
 		"--pipeline-insecure=%q "+
 		"--pipeline-token=%q "+
@@ -941,7 +1014,18 @@ func (c *EC2ClusterPKE) GetBootstrapCommand(nodePoolName, url string, urlInsecur
 		nodePoolName,
 		version,
 		infrastructureCIDR,
-	), nil
+	)
+
+	if len(np.Taints) > 0 {
+		taints := make([]string, 0, len(np.Taints))
+		for _, taint := range np.Taints {
+			taints = append(taints, taint.String())
+		}
+
+		command = fmt.Sprintf("%s --taints=%q", command, strings.Join(taints, ","))
+	}
+
+	return command, nil
 }
 
 func (c *EC2ClusterPKE) GetKubernetesVersion() (string, error) {
@@ -1034,14 +1118,16 @@ func CreateEC2ClusterPKEFromRequest(request *pkgCluster.CreateClusterRequest, or
 
 	c.model = &internalPke.EC2PKEClusterModel{
 		Cluster: clustermodel.ClusterModel{
-			Name:           request.Name,
-			Location:       request.Location,
-			Cloud:          request.Cloud,
-			Distribution:   pkgCluster.PKE,
-			OrganizationID: orgId,
-			RbacEnabled:    kubernetes.RBAC.Enabled,
-			OidcEnabled:    request.Properties.CreateClusterPKE.Kubernetes.OIDC.Enabled,
-			CreatedBy:      userId,
+			Name:               request.Name,
+			Location:           request.Location,
+			Cloud:              request.Cloud,
+			Distribution:       pkgCluster.PKE,
+			OrganizationID:     orgId,
+			RbacEnabled:        kubernetes.RBAC.Enabled,
+			OidcEnabled:        request.Properties.CreateClusterPKE.Kubernetes.OIDC.Enabled,
+			CreatedBy:          userId,
+			DeletionProtection: request.DeletionProtection,
+			Labels:             clustermodel.NewLabelModels(request.Labels),
 		},
 		MasterInstanceType: instanceType,
 		MasterImage:        image,
@@ -1097,6 +1183,7 @@ func createEC2ClusterPKENodePoolsFromRequest(pools pke.NodePools, userId uint) i
 			Provider:       convertNodePoolProvider(pool.Provider),
 			ProviderConfig: pool.ProviderConfig,
 			Labels:         pool.Labels,
+			Taints:         pool.Taints,
 			Autoscaling:    pool.Autoscaling,
 		}
 		np.CreatedBy = userId
@@ -62,14 +62,16 @@ func CreateEKSClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId
 
 	cluster.model = &eksmodel.EKSClusterModel{
 		Cluster: clustermodel.ClusterModel{
-			Name:           request.Name,
-			Location:       request.Location,
-			Cloud:          request.Cloud,
-			OrganizationID: orgId,
-			SecretID:       request.SecretId,
-			Distribution:   pkgCluster.EKS,
-			RbacEnabled:    true,
-			CreatedBy:      userId,
+			Name:               request.Name,
+			Location:           request.Location,
+			Cloud:              request.Cloud,
+			OrganizationID:     orgId,
+			SecretID:           request.SecretId,
+			Distribution:       pkgCluster.EKS,
+			RbacEnabled:        true,
+			CreatedBy:          userId,
+			DeletionProtection: request.DeletionProtection,
+			Labels:             clustermodel.NewLabelModels(request.Labels),
 		},
 		Version:               request.Properties.CreateClusterEKS.Version,
 		LogTypes:              request.Properties.CreateClusterEKS.LogTypes,
@@ -114,6 +116,7 @@ func createNodePoolsFromRequest(nodePools map[string]*pkgEks.NodePool, userId ui
 			NodeImage:        nodePool.Image,
 			NodeInstanceType: nodePool.InstanceType,
 			Labels:           nodePool.Labels,
+			Taints:           nodePool.Taints,
 			Delete:           false,
 		}
 		i++
@@ -532,6 +535,76 @@ func (c *EKSCluster) CheckEqualityToUpdate(r *pkgCluster.UpdateClusterRequest) e
 	return isDifferent(r.EKS, preCl)
 }
 
+// GetCreateClusterRequest reconstructs a cluster creation request from the stored cluster model
+// Node pool labels and node pool subnets are not stored, node pool volume and security group settings
+// are not supported by the creation request, so they are not part of the reconstructed request.
+func (c *EKSCluster) GetCreateClusterRequest() (*pkgCluster.CreateClusterRequest, error) {
+	nodePools := make(map[string]*pkgEks.NodePool, len(c.model.NodePools))
+	for _, np := range c.model.NodePools {
+		nodePools[np.Name] = &pkgEks.NodePool{
+			InstanceType: np.NodeInstanceType,
+			SpotPrice:    np.NodeSpotPrice,
+			Autoscaling:  np.Autoscaling,
+			MinCount:     np.NodeMinCount,
+			MaxCount:     np.NodeMaxCount,
+			Count:        np.Count,
+			Image:        np.NodeImage,
+			Taints:       np.Taints,
+		}
+	}
+
+	eksRequest := &pkgEks.CreateClusterEKS{
+		Version:   c.model.Version,
+		NodePools: nodePools,
+		IAM: pkgEks.ClusterIAM{
+			ClusterRoleID:      c.model.ClusterRoleId,
+			NodeInstanceRoleID: c.model.NodeInstanceRoleId,
+			DefaultUser:        c.model.DefaultUser,
+		},
+		LogTypes:              c.model.LogTypes,
+		APIServerAccessPoints: c.model.APIServerAccessPoints,
+	}
+
+	// a stored VPC CIDR means that the VPC and its subnets were created by Pipeline,
+	// so the new cluster gets its own network with the same layout
+	if vpcCidr := aws.StringValue(c.model.VpcCidr); vpcCidr != "" {
+		eksRequest.Vpc = &pkgEks.ClusterVPC{Cidr: vpcCidr}
+
+		for _, subnet := range c.model.Subnets {
+			eksRequest.Subnets = append(eksRequest.Subnets, &pkgEks.Subnet{
+				Cidr:             aws.StringValue(subnet.Cidr),
+				AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+			})
+		}
+	} else {
+		eksRequest.Vpc = &pkgEks.ClusterVPC{VpcId: aws.StringValue(c.model.VpcId)}
+		eksRequest.RouteTableId = aws.StringValue(c.model.RouteTableId)
+
+		for _, subnet := range c.model.Subnets {
+			if subnetID := aws.StringValue(subnet.SubnetId); subnetID != "" {
+				eksRequest.Subnets = append(eksRequest.Subnets, &pkgEks.Subnet{SubnetId: subnetID})
+				continue
+			}
+
+			eksRequest.Subnets = append(eksRequest.Subnets, &pkgEks.Subnet{
+				Cidr:             aws.StringValue(subnet.Cidr),
+				AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+			})
+		}
+	}
+
+	return &pkgCluster.CreateClusterRequest{
+		Name:         c.model.Cluster.Name,
+		Location:     c.model.Cluster.Location,
+		Cloud:        c.model.Cluster.Cloud,
+		SecretId:     c.model.Cluster.SecretID,
+		ScaleOptions: c.GetScaleOptions(),
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterEKS: eksRequest,
+		},
+	}, nil
+}
+
 // AddDefaultsToUpdate adds defaults to update request
 func (c *EKSCluster) AddDefaultsToUpdate(r *pkgCluster.UpdateClusterRequest) {
 	defaultImage, _ := eks2.GetDefaultImageID(c.model.Cluster.Location, c.model.Version)
@@ -575,6 +648,11 @@ func (c *EKSCluster) DeleteFromDatabase() error {
 
 // SetStatus sets the cluster's status
 func (c *EKSCluster) SetStatus(status string, statusMessage string) error {
+	return c.SetStatusWithContext(context.Background(), status, statusMessage)
+}
+
+// SetStatusWithContext sets the cluster's status recording the user and the process acting on the cluster from the context
+func (c *EKSCluster) SetStatusWithContext(ctx context.Context, status string, statusMessage string) error {
 	if c.model.Cluster.Status == status && c.model.Cluster.StatusMessage == statusMessage {
 		return nil
 	}
@@ -591,6 +669,7 @@ func (c *EKSCluster) SetStatus(status string, statusMessage string) error {
 			ToStatus:          status,
 			ToStatusMessage:   statusMessage,
 		}
+		statusHistory.SetActor(ctx)
 
 		if err := c.repository.SaveStatusHistory(&statusHistory); err != nil {
 			return errors.Wrap(err, "failed to record cluster status change to history")
@@ -18,7 +18,13 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/ekscluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/distribution/eks/eksmodel"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 )
 
 func TestCreateSubnetMappingFromRequest(t *testing.T) {
@@ -49,3 +55,86 @@ func TestCreateSubnetMappingFromRequest(t *testing.T) {
 		t.Errorf("Expected: %v, got: %v", expected, subnetMappings)
 	}
 }
+
+func TestEKSClusterGetCreateClusterRequest(t *testing.T) {
+	eksCluster := &EKSCluster{
+		model: &eksmodel.EKSClusterModel{
+			Cluster: clustermodel.ClusterModel{
+				Name:     "test",
+				Location: "eu-west-1",
+				Cloud:    pkgCluster.Amazon,
+				SecretID: "secret",
+			},
+			Version: "1.15",
+			NodePools: []*eksmodel.AmazonNodePoolsModel{
+				{
+					Name:             "pool1",
+					NodeSpotPrice:    "0.2",
+					Autoscaling:      true,
+					NodeMinCount:     1,
+					NodeMaxCount:     3,
+					Count:            2,
+					NodeImage:        "ami-123",
+					NodeInstanceType: "t2.medium",
+					Taints: []cluster.Taint{
+						{Key: "dedicated", Value: "pool1", Effect: "NoSchedule"},
+					},
+				},
+			},
+			VpcId:   aws.String("vpc-123"),
+			VpcCidr: aws.String("192.168.0.0/16"),
+			Subnets: []*eksmodel.EKSSubnetModel{
+				{
+					SubnetId:         aws.String("subnet-123"),
+					Cidr:             aws.String("192.168.64.0/20"),
+					AvailabilityZone: aws.String("eu-west-1a"),
+				},
+			},
+			ClusterRoleId:         "cluster-role",
+			LogTypes:              []string{"api"},
+			APIServerAccessPoints: []string{"public"},
+		},
+	}
+
+	expected := &pkgCluster.CreateClusterRequest{
+		Name:     "test",
+		Location: "eu-west-1",
+		Cloud:    pkgCluster.Amazon,
+		SecretId: "secret",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterEKS: &ekscluster.CreateClusterEKS{
+				Version: "1.15",
+				NodePools: map[string]*ekscluster.NodePool{
+					"pool1": {
+						InstanceType: "t2.medium",
+						SpotPrice:    "0.2",
+						Autoscaling:  true,
+						MinCount:     1,
+						MaxCount:     3,
+						Count:        2,
+						Image:        "ami-123",
+						Taints: []cluster.Taint{
+							{Key: "dedicated", Value: "pool1", Effect: "NoSchedule"},
+						},
+					},
+				},
+				Vpc: &ekscluster.ClusterVPC{Cidr: "192.168.0.0/16"},
+				Subnets: []*ekscluster.Subnet{
+					{Cidr: "192.168.64.0/20", AvailabilityZone: "eu-west-1a"},
+				},
+				IAM:                   ekscluster.ClusterIAM{ClusterRoleID: "cluster-role"},
+				LogTypes:              []string{"api"},
+				APIServerAccessPoints: []string{"public"},
+			},
+		},
+	}
+
+	request, err := eksCluster.GetCreateClusterRequest()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(request, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, request)
+	}
+}
@@ -207,6 +207,7 @@ func EKSUpdateClusterWorkflow(ctx workflow.Context, input EKSUpdateClusterstruct
 				NodeImage:        nodePool.NodeImage,
 				NodeInstanceType: nodePool.NodeInstanceType,
 				Labels:           nodePool.Labels,
+				Taints:           nodePool.Taints,
 			}
 			if input.UseGeneratedSSHKey {
 				activityInput.SSHKeyName = eksWorkflow.GenerateSSHKeyNameForCluster(input.ClusterName)
@@ -233,6 +234,7 @@ func EKSUpdateClusterWorkflow(ctx workflow.Context, input EKSUpdateClusterstruct
 				NodeImage:        nodePool.NodeImage,
 				NodeInstanceType: nodePool.NodeInstanceType,
 				Labels:           nodePool.Labels,
+				Taints:           nodePool.Taints,
 			}
 			ctx = workflow.WithActivityOptions(ctx, aoWithHeartBeat)
 			f := workflow.ExecuteActivity(ctx, eksWorkflow.UpdateAsgActivityName, activityInput)
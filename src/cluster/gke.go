@@ -95,13 +95,15 @@ func CreateGKEClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgID
 
 	c.model = &google.GKEClusterModel{
 		Cluster: clustermodel.ClusterModel{
-			Name:           request.Name,
-			Location:       request.Location,
-			OrganizationID: orgID,
-			SecretID:       request.SecretId,
-			Cloud:          google.Provider,
-			Distribution:   google.ClusterDistributionGKE,
-			CreatedBy:      userID,
+			Name:               request.Name,
+			Location:           request.Location,
+			OrganizationID:     orgID,
+			SecretID:           request.SecretId,
+			Cloud:              google.Provider,
+			Distribution:       google.ClusterDistributionGKE,
+			CreatedBy:          userID,
+			DeletionProtection: request.DeletionProtection,
+			Labels:             clustermodel.NewLabelModels(request.Labels),
 		},
 
 		MasterVersion: request.Properties.CreateClusterGKE.Master.Version,
@@ -1493,6 +1495,34 @@ func (c *GKECluster) CheckEqualityToUpdate(r *pkgCluster.UpdateClusterRequest) e
 	return isDifferent(r.GKE, preCl)
 }
 
+// GetCreateClusterRequest reconstructs a cluster creation request from the stored cluster model
+func (c *GKECluster) GetCreateClusterRequest() (*pkgCluster.CreateClusterRequest, error) {
+	nodePools, err := createNodePoolsRequestDataFromNodePoolModel(c.model.NodePools)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkgCluster.CreateClusterRequest{
+		Name:         c.model.Cluster.Name,
+		Location:     c.model.Cluster.Location,
+		Cloud:        c.model.Cluster.Cloud,
+		SecretId:     c.model.Cluster.SecretID,
+		ScaleOptions: c.GetScaleOptions(),
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterGKE: &pkgClusterGoogle.CreateClusterGKE{
+				NodeVersion: c.model.NodeVersion,
+				NodePools:   nodePools,
+				Master: &pkgClusterGoogle.Master{
+					Version: c.model.MasterVersion,
+				},
+				Vpc:       c.model.Vpc,
+				Subnet:    c.model.Subnet,
+				ProjectId: c.model.ProjectId,
+			},
+		},
+	}, nil
+}
+
 // DeleteFromDatabase deletes model from the database
 func (c *GKECluster) DeleteFromDatabase() error {
 	if err := c.repository.DeleteClusterModel(&c.model.Cluster); err != nil {
@@ -1702,7 +1732,12 @@ func (c *GKECluster) GetProjectId() (string, error) {
 }
 
 // SetStatus sets the cluster's status
-func (c *GKECluster) SetStatus(status, statusMessage string) error {
+func (c *GKECluster) SetStatus(status string, statusMessage string) error {
+	return c.SetStatusWithContext(context.Background(), status, statusMessage)
+}
+
+// SetStatusWithContext sets the cluster's status recording the user and the process acting on the cluster from the context
+func (c *GKECluster) SetStatusWithContext(ctx context.Context, status string, statusMessage string) error {
 	if c.model.Cluster.Status == status && c.model.Cluster.StatusMessage == statusMessage {
 		return nil
 	}
@@ -1719,6 +1754,7 @@ func (c *GKECluster) SetStatus(status, statusMessage string) error {
 			ToStatus:          status,
 			ToStatusMessage:   statusMessage,
 		}
+		statusHistory.SetActor(ctx)
 
 		if err := c.repository.SaveStatusHistory(&statusHistory); err != nil {
 			return errors.Wrap(err, "failed to record cluster status change to history")
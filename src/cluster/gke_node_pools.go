@@ -17,6 +17,7 @@ package cluster
 import (
 	gke "google.golang.org/api/container/v1"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/providers/google"
 	pkgClusterGoogle "github.com/banzaicloud/pipeline/pkg/cluster/gke"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -43,6 +44,7 @@ func createNodePoolsModelFromRequest(nodePoolsData map[string]*pkgClusterGoogle.
 			NodeInstanceType: nodePoolData.NodeInstanceType,
 			Preemptible:      nodePoolData.Preemptible,
 			Labels:           nodePoolData.Labels,
+			Taints:           nodePoolData.Taints,
 		}
 
 		i++
@@ -81,6 +83,7 @@ func createNodePoolsFromClusterModel(clusterModel *google.GKEClusterModel) ([]*g
 					"https://www.googleapis.com/auth/compute",
 				},
 				Preemptible: nodePoolModel.Preemptible,
+				Taints:      createNodeTaintsFromModel(nodePoolModel.Taints),
 			},
 			InitialNodeCount: int64(nodePoolModel.NodeCount),
 			Version:          clusterModel.NodeVersion,
@@ -120,8 +123,33 @@ func createNodePoolsRequestDataFromNodePoolModel(nodePoolsModel []*google.GKENod
 			Count:            nodePoolModel.NodeCount,
 			NodeInstanceType: nodePoolModel.NodeInstanceType,
 			Preemptible:      nodePoolModel.Preemptible,
+			Taints:           nodePoolModel.Taints,
 		}
 	}
 
 	return nodePools, nil
 }
+
+// createNodeTaintsFromModel converts node pool taints to GKE node taints
+func createNodeTaintsFromModel(taints []cluster.Taint) []*gke.NodeTaint {
+	if len(taints) == 0 {
+		return nil
+	}
+
+	effects := map[string]string{
+		cluster.TaintEffectNoSchedule:       "NO_SCHEDULE",
+		cluster.TaintEffectPreferNoSchedule: "PREFER_NO_SCHEDULE",
+		cluster.TaintEffectNoExecute:        "NO_EXECUTE",
+	}
+
+	nodeTaints := make([]*gke.NodeTaint, 0, len(taints))
+	for _, taint := range taints {
+		nodeTaints = append(nodeTaints, &gke.NodeTaint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: effects[taint.Effect],
+		})
+	}
+
+	return nodeTaints
+}
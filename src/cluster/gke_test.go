@@ -21,6 +21,7 @@ import (
 
 	gke "google.golang.org/api/container/v1"
 
+	"github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/providers/google"
 	pkgClusterGoogle "github.com/banzaicloud/pipeline/pkg/cluster/gke"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -54,9 +55,11 @@ var (
 		NodeVersion: nodeVersion,
 		NodePools: []*google.GKENodePoolModel{
 			{Name: pool1Name, NodeCount: pool1Count, NodeInstanceType: pool1NodeInstanceType},
-			{Name: pool2Name, NodeCount: pool2Count, NodeInstanceType: pool2NodeInstanceType},
+			{Name: pool2Name, NodeCount: pool2Count, NodeInstanceType: pool2NodeInstanceType, Taints: taints},
 		},
 	}
+
+	taints = []cluster.Taint{{Key: "dedicated", Value: "gpu", Effect: cluster.TaintEffectNoSchedule}}
 )
 
 func TestCreateNodePoolsModelFromRequest(t *testing.T) {
@@ -142,6 +145,9 @@ func TestCreateNodePoolsFromClusterModel(t *testing.T) {
 		Labels: map[string]string{
 			pkgCommon.LabelKey: pool2Name,
 		},
+		Taints: []*gke.NodeTaint{
+			{Key: "dedicated", Value: "gpu", Effect: "NO_SCHEDULE"},
+		},
 	}
 	nodePools := []*gke.NodePool{
 		{Name: pool1Name, Autoscaling: &gke.NodePoolAutoscaling{Enabled: false, MinNodeCount: 0, MaxNodeCount: 0}, InitialNodeCount: pool1Count, Version: nodeVersion, Config: nodeConfig1},
@@ -196,6 +202,7 @@ func TestCreateRequestNodePoolsFromNodePoolModel(t *testing.T) {
 		pool2Name: {
 			Count:            pool2Count,
 			NodeInstanceType: pool2NodeInstanceType,
+			Taints:           taints,
 		},
 	}
 
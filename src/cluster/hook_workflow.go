@@ -24,6 +24,8 @@ import (
 	"go.uber.org/cadence/activity"
 	"go.uber.org/cadence/workflow"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterworkflow"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
 	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
 
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
@@ -84,6 +86,19 @@ func RunPostHooksWorkflow(ctx workflow.Context, input RunPostHooksWorkflowInput)
 		}
 	}
 
+	// Add the cluster to the matching cluster groups
+	{
+		activityInput := clusterworkflow.ReconcileClusterGroupMembersActivityInput{
+			ClusterID: input.ClusterID,
+		}
+
+		err := workflow.ExecuteActivity(ctx, clusterworkflow.ReconcileClusterGroupMembersActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			// the cluster itself is ready, failing to join a cluster group should not fail the creation
+			workflow.GetLogger(ctx).Sugar().Warnw("failed to reconcile cluster group members", "clusterID", input.ClusterID, "error", err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -161,6 +176,7 @@ func (a *RunPostHookActivity) Execute(ctx context.Context, input RunPostHookActi
 	}
 
 	info := activity.GetInfo(ctx)
+	ctx = ctxutil.WithProcessID(ctx, info.WorkflowExecution.ID)
 	logger := activity.GetLogger(ctx).Sugar().With(
 		"clusterID", input.ClusterID,
 		"postHook", input.HookName,
@@ -186,7 +202,7 @@ func (a *RunPostHookActivity) Execute(ctx context.Context, input RunPostHookActi
 	if status == "" {
 		status = pkgCluster.Creating
 	}
-	if err := cluster.SetStatus(status, statusMsg); err != nil {
+	if err := SetClusterStatus(ctx, cluster, status, statusMsg); err != nil {
 		return errors.WrapIf(err, "failed to write status to db")
 	}
 
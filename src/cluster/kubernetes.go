@@ -15,6 +15,7 @@
 package cluster
 
 import (
+	"context"
 	"encoding/base64"
 	"strings"
 
@@ -25,6 +26,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	storageUtil "k8s.io/kubernetes/pkg/apis/storage/util"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/global"
 	"github.com/banzaicloud/pipeline/internal/providers/kubernetes/kubernetesadapter"
 	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
@@ -44,13 +46,15 @@ func CreateKubernetesClusterFromRequest(request *pkgCluster.CreateClusterRequest
 	}
 
 	cluster.modelCluster = &model.ClusterModel{
-		Name:           request.Name,
-		Location:       request.Location,
-		Cloud:          request.Cloud,
-		OrganizationId: orgId,
-		CreatedBy:      userId,
-		SecretId:       request.SecretId,
-		Distribution:   pkgCluster.Unknown,
+		Name:               request.Name,
+		Location:           request.Location,
+		Cloud:              request.Cloud,
+		OrganizationId:     orgId,
+		CreatedBy:          userId,
+		SecretId:           request.SecretId,
+		DeletionProtection: request.DeletionProtection,
+		Labels:             clustermodel.NewLabelModels(request.Labels),
+		Distribution:       pkgCluster.Unknown,
 		Kubernetes: kubernetesadapter.KubernetesClusterModel{
 			Metadata: request.Properties.CreateClusterKubernetes.Metadata,
 		},
@@ -269,6 +273,11 @@ func (c *KubeCluster) SetStatus(status, statusMessage string) error {
 	return c.modelCluster.UpdateStatus(status, statusMessage)
 }
 
+// SetStatusWithContext sets the cluster's status recording the user and the process acting on the cluster from the context
+func (c *KubeCluster) SetStatusWithContext(ctx context.Context, status, statusMessage string) error {
+	return c.modelCluster.UpdateStatusWithContext(ctx, status, statusMessage)
+}
+
 // NodePoolExists returns true if node pool with nodePoolName exists
 func (c *KubeCluster) NodePoolExists(nodePoolName string) bool {
 	return false
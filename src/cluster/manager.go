@@ -125,6 +125,7 @@ func (m *Manager) KubeConfigFunc() func(ctx context.Context, clusterID uint) ([]
 }
 
 type clusterErrorHandler struct {
+	ctx           context.Context
 	handler       emperror.Handler
 	status        string
 	statusMessage string
@@ -137,7 +138,7 @@ func (c clusterErrorHandler) Handle(err error) {
 		if strings.Contains(statusMessage, "%") {
 			statusMessage = fmt.Sprintf(statusMessage, err)
 		}
-		_ = c.cluster.SetStatus(c.status, statusMessage)
+		_ = SetClusterStatus(c.ctx, c.cluster, c.status, statusMessage)
 	}
 
 	err = errors.WithDetails(
@@ -151,6 +152,7 @@ func (c clusterErrorHandler) Handle(err error) {
 
 func (c clusterErrorHandler) WithStatus(status, statusMessage string) clusterErrorHandler {
 	return clusterErrorHandler{
+		ctx:           c.ctx,
 		cluster:       c.cluster,
 		status:        status,
 		statusMessage: statusMessage,
@@ -160,6 +162,7 @@ func (c clusterErrorHandler) WithStatus(status, statusMessage string) clusterErr
 
 func (m *Manager) getClusterErrorHandler(ctx context.Context, commonCluster CommonCluster) clusterErrorHandler {
 	return clusterErrorHandler{
+		ctx:     ctx,
 		handler: pipelineContext.ErrorHandlerWithCorrelationID(ctx, m.errorHandler),
 		cluster: commonCluster,
 	}
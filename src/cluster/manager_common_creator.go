@@ -52,7 +52,7 @@ func (c *commonCreator) Prepare(ctx context.Context) (CommonCluster, error) {
 		return nil, err
 	}
 
-	if err := c.cluster.SetStatus(pkgCluster.Creating, pkgCluster.CreatingMessage); err != nil {
+	if err := SetClusterStatus(ctx, c.cluster, pkgCluster.Creating, pkgCluster.CreatingMessage); err != nil {
 		return nil, err
 	}
 
@@ -92,7 +92,7 @@ func (c *commonNodepoolUpdater) Validate(ctx context.Context) error {
 
 // Prepare implements the clusterUpdater interface.
 func (c *commonNodepoolUpdater) Prepare(ctx context.Context) (CommonCluster, error) {
-	if err := c.cluster.SetStatus(cluster.Updating, cluster.UpdatingMessage); err != nil {
+	if err := SetClusterStatus(ctx, c.cluster, cluster.Updating, cluster.UpdatingMessage); err != nil {
 		return nil, err
 	}
 	return c.cluster, c.cluster.Persist()
@@ -146,7 +146,7 @@ func (c *commonUpdater) Prepare(ctx context.Context) (CommonCluster, error) {
 		}
 	}
 
-	if err := c.cluster.SetStatus(cluster.Updating, cluster.UpdatingMessage); err != nil {
+	if err := SetClusterStatus(ctx, c.cluster, cluster.Updating, cluster.UpdatingMessage); err != nil {
 		return nil, err
 	}
 	return c.cluster, c.cluster.Persist()
@@ -134,7 +134,7 @@ func (m *Manager) CreateCluster(
 		return nil, err
 	}
 
-	if err := cluster.SetStatus(pkgCluster.Creating, pkgCluster.CreatingMessage); err != nil {
+	if err := SetClusterStatus(ctx, cluster, pkgCluster.Creating, pkgCluster.CreatingMessage); err != nil {
 		return nil, err
 	}
 
@@ -186,23 +186,23 @@ func (m *Manager) createCluster(
 
 		sshKey, err := ssh.NewKeyPairGenerator().Generate()
 		if err != nil {
-			_ = cluster.SetStatus(pkgCluster.Error, "internal error")
+			_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, "internal error")
 			return errors.WrapIf(err, "failed to generate SSH key")
 		}
 
 		sshSecretId, err := sshdriver.StoreSSHKeyPair(sshKey, cluster.GetOrganizationId(), cluster.GetID(), cluster.GetName(), cluster.GetUID())
 		if err != nil {
-			_ = cluster.SetStatus(pkgCluster.Error, "internal error")
+			_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, "internal error")
 			return errors.WrapIf(err, "failed to store SSH key")
 		}
 
 		if err := cluster.SaveSshSecretId(sshSecretId); err != nil {
-			_ = cluster.SetStatus(pkgCluster.Error, "internal error")
+			_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, "internal error")
 			return errors.WrapIf(err, "failed to save SSH key secret ID")
 		}
 	}
 	if err := creator.Create(ctx); err != nil {
-		_ = cluster.SetStatus(pkgCluster.Error, err.Error())
+		_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error())
 		return err
 	}
 
@@ -210,7 +210,7 @@ func (m *Manager) createCluster(
 	// the cluster create and the posthook run workflows.
 	// Status setting should be updated everywhere to only change the status fields in the database.
 	// (and/or reload the model between workflow executions which is probably a good idea anyway)
-	err := cluster.SetStatus(pkgCluster.Creating, "running posthooks")
+	err := SetClusterStatus(ctx, cluster, pkgCluster.Creating, "running posthooks")
 	if err != nil {
 		return errors.WrapIf(err, "failed to update cluster status")
 	}
@@ -232,7 +232,7 @@ func (m *Manager) createCluster(
 	}
 	labelsMap, err := GetDesiredLabelsForCluster(ctx, cluster, nodePoolLabels)
 	if err != nil {
-		_ = cluster.SetStatus(pkgCluster.Error, "failed to get desired labels")
+		_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, "failed to get desired labels")
 
 		return err
 	}
@@ -262,7 +262,7 @@ func (m *Manager) createCluster(
 
 		exec, err := m.workflowClient.ExecuteWorkflow(ctx, workflowOptions, CreateClusterWorkflowName, input)
 		if err != nil {
-			_ = cluster.SetStatus(pkgCluster.Error, "failed to run setup jobs")
+			_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, "failed to run setup jobs")
 
 			return errors.WrapIfWithDetails(err, "failed to start workflow", "workflowName", CreateClusterWorkflowName)
 		}
@@ -306,7 +306,7 @@ func (m *Manager) createCluster(
 
 		exec, err := m.workflowClient.ExecuteWorkflow(ctx, workflowOptions, RunPostHooksWorkflowName, input)
 		if err != nil {
-			_ = cluster.SetStatus(pkgCluster.Error, "failed to run posthooks")
+			_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, "failed to run posthooks")
 
 			return errors.WrapIfWithDetails(err, "failed to start workflow", "workflowName", RunPostHooksWorkflowName)
 		}
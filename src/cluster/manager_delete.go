@@ -143,7 +143,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 
 	logger.Info("deleting cluster")
 
-	if err := cluster.SetStatus(pkgCluster.Deleting, pkgCluster.DeletingMessage); err != nil {
+	if err := SetClusterStatus(ctx, cluster, pkgCluster.Deleting, pkgCluster.DeletingMessage); err != nil {
 		return errors.WrapIfWithDetails(err, "cluster status update failed", "cluster_id", cluster.GetID())
 	}
 
@@ -154,7 +154,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 			if err = cls.DeleteFromDatabase(); err != nil {
 				err = errors.WrapIf(err, "failed to delete from the database")
 				if !force {
-					cls.SetStatus(pkgCluster.Error, err.Error())
+					SetClusterStatus(ctx, cls, pkgCluster.Error, err.Error())
 					return err
 				}
 				logger.Error(err)
@@ -180,7 +180,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 		err = errors.WrapIf(err, "cannot access Kubernetes cluster")
 
 		if !force {
-			_ = cluster.SetStatus(pkgCluster.Error, err.Error())
+			_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error())
 
 			return err
 		}
@@ -206,7 +206,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 				err = errors.WrapIf(err, "can not list namespaces")
 
 				if !force {
-					_ = cluster.SetStatus(pkgCluster.Error, err.Error())
+					_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error())
 
 					return err
 				}
@@ -228,7 +228,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 			err = errors.WrapIf(err, "failed to delete deployments")
 
 			if !force {
-				_ = cluster.SetStatus(pkgCluster.Error, err.Error())
+				_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error())
 
 				return err
 			}
@@ -241,7 +241,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 			err = errors.WrapIf(err, "failed to delete Kubernetes resources")
 
 			if !force {
-				_ = cluster.SetStatus(pkgCluster.Error, err.Error())
+				_ = SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error())
 
 				return err
 			}
@@ -267,7 +267,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 	if err != nil {
 		err = errors.WrapIf(err, "failed to delete cluster from the provider")
 		if !force {
-			cluster.SetStatus(pkgCluster.Error, err.Error()) // nolint: errcheck
+			SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error()) // nolint: errcheck
 			return err
 		}
 		logger.Error(err)
@@ -280,7 +280,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 	if err != nil {
 		err = errors.WrapIf(err, "failed to delete unused cluster secrets")
 		if !force {
-			cluster.SetStatus(pkgCluster.Error, err.Error()) // nolint: errcheck
+			SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error()) // nolint: errcheck
 			return err
 		}
 		logger.Error(err)
@@ -293,7 +293,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 	if err != nil {
 		err = errors.WrapIf(err, "failed to delete from the database")
 		if !force {
-			cluster.SetStatus(pkgCluster.Error, err.Error()) // nolint: errcheck
+			SetClusterStatus(ctx, cluster, pkgCluster.Error, err.Error()) // nolint: errcheck
 			return err
 		}
 		logger.Error(err)
@@ -69,7 +69,7 @@ func (m *Manager) UpdateCluster(ctx context.Context, updateCtx UpdateContext, up
 		return err
 	}
 
-	if err := cluster.SetStatus(pkgCluster.Updating, pkgCluster.UpdatingMessage); err != nil {
+	if err := SetClusterStatus(ctx, cluster, pkgCluster.Updating, pkgCluster.UpdatingMessage); err != nil {
 		return errors.WrapIf(err, "could not update cluster status")
 	}
 
@@ -100,7 +100,7 @@ func (m *Manager) updateCluster(ctx context.Context, updateCtx UpdateContext, cl
 	logger.Info("updating cluster")
 
 	if err := updater.Update(ctx); err != nil {
-		if setErr := cluster.SetStatus(pkgCluster.Warning, err.Error()); setErr != nil {
+		if setErr := SetClusterStatus(ctx, cluster, pkgCluster.Warning, err.Error()); setErr != nil {
 			log.Error(setErr, "could not set cluster status")
 		} else {
 			m.events.ClusterUpdated(updateCtx.ClusterID)
@@ -109,7 +109,7 @@ func (m *Manager) updateCluster(ctx context.Context, updateCtx UpdateContext, cl
 		return errors.WrapIf(err, "error updating cluster")
 	}
 
-	if err := cluster.SetStatus(pkgCluster.Running, pkgCluster.RunningMessage); err != nil {
+	if err := SetClusterStatus(ctx, cluster, pkgCluster.Running, pkgCluster.RunningMessage); err != nil {
 		return errors.WrapIf(err, "could not update cluster status")
 	}
 	m.events.ClusterUpdated(updateCtx.ClusterID)
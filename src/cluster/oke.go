@@ -15,6 +15,7 @@
 package cluster
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -29,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter/clustermodel"
 	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
 	"github.com/banzaicloud/pipeline/internal/secret/ssh/sshadapter"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
@@ -64,13 +66,15 @@ func CreateOKEClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId
 	var oke OKECluster
 
 	oke.modelCluster = &model.ClusterModel{
-		Name:           request.Name,
-		Location:       request.Location,
-		Cloud:          request.Cloud,
-		OrganizationId: orgId,
-		SecretId:       request.SecretId,
-		CreatedBy:      userId,
-		Distribution:   pkgCluster.OKE,
+		Name:               request.Name,
+		Location:           request.Location,
+		Cloud:              request.Cloud,
+		OrganizationId:     orgId,
+		SecretId:           request.SecretId,
+		CreatedBy:          userId,
+		Distribution:       pkgCluster.OKE,
+		DeletionProtection: request.DeletionProtection,
+		Labels:             clustermodel.NewLabelModels(request.Labels),
 	}
 	updateScaleOptions(&oke.modelCluster.ScaleOptions, request.ScaleOptions)
 
@@ -452,6 +456,11 @@ func (o *OKECluster) SetStatus(status, statusMessage string) error {
 	return o.modelCluster.UpdateStatus(status, statusMessage)
 }
 
+// SetStatusWithContext sets the cluster's status recording the user and the process acting on the cluster from the context
+func (o *OKECluster) SetStatusWithContext(ctx context.Context, status, statusMessage string) error {
+	return o.modelCluster.UpdateStatusWithContext(ctx, status, statusMessage)
+}
+
 // NodePoolExists returns true if node pool with nodePoolName exists
 func (o *OKECluster) NodePoolExists(nodePoolName string) bool {
 	for _, np := range o.modelCluster.OKE.NodePools {
@@ -16,6 +16,7 @@ package model
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"time"
 
@@ -59,6 +60,11 @@ type ClusterModel struct {
 	Kubernetes     kubernetesadapter.KubernetesClusterModel `gorm:"foreignkey:ID"`
 	OKE            modelOracle.Cluster
 	CreatedBy      uint
+
+	DeletionProtection bool `gorm:"default:false;not null"`
+
+	// Labels are only saved together with a new cluster, use the label store to change them.
+	Labels []clustermodel.LabelModel `gorm:"foreignkey:ClusterID;association_autoupdate:false"`
 }
 
 // TableName sets ClusterModel's table name
@@ -125,6 +131,12 @@ func (cs *ClusterModel) String() string {
 
 // UpdateStatus updates the model's status and status message in database
 func (cs *ClusterModel) UpdateStatus(status, statusMessage string) error {
+	return cs.UpdateStatusWithContext(context.Background(), status, statusMessage)
+}
+
+// UpdateStatusWithContext updates the model's status and status message in database
+// recording the user and the process acting on the cluster from the context.
+func (cs *ClusterModel) UpdateStatusWithContext(ctx context.Context, status, statusMessage string) error {
 	if cs.Status == status && cs.StatusMessage == statusMessage {
 		return nil
 	}
@@ -141,6 +153,7 @@ func (cs *ClusterModel) UpdateStatus(status, statusMessage string) error {
 			ToStatus:          status,
 			ToStatusMessage:   statusMessage,
 		}
+		statusHistory.SetActor(ctx)
 
 		if err := global.DB().Save(&statusHistory).Error; err != nil {
 			return errors.Wrap(err, "failed to record cluster status change to history")
@@ -0,0 +1,74 @@
+// Copyright © 2020 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+type secretEvents interface {
+	// SecretCreated event is emitted when a secret is stored.
+	SecretCreated(organizationID uint, secretID string)
+
+	// SecretUpdated event is emitted when a secret is updated.
+	SecretUpdated(organizationID uint, secretID string)
+
+	// SecretDeleted event is emitted when a secret is deleted.
+	SecretDeleted(organizationID uint, secretID string)
+}
+
+type nopSecretEvents struct {
+}
+
+func NewNopSecretEvents() *nopSecretEvents {
+	return &nopSecretEvents{}
+}
+
+func (*nopSecretEvents) SecretCreated(organizationID uint, secretID string) {
+}
+
+func (*nopSecretEvents) SecretUpdated(organizationID uint, secretID string) {
+}
+
+func (*nopSecretEvents) SecretDeleted(organizationID uint, secretID string) {
+}
+
+type eventBus interface {
+	Publish(topic string, args ...interface{})
+}
+
+type secretEventBus struct {
+	eb eventBus
+}
+
+const (
+	secretCreatedTopic = "secret_created"
+	secretUpdatedTopic = "secret_updated"
+	secretDeletedTopic = "secret_deleted"
+)
+
+func NewSecretEvents(eb eventBus) *secretEventBus {
+	return &secretEventBus{
+		eb: eb,
+	}
+}
+
+func (s *secretEventBus) SecretCreated(organizationID uint, secretID string) {
+	s.eb.Publish(secretCreatedTopic, organizationID, secretID)
+}
+
+func (s *secretEventBus) SecretUpdated(organizationID uint, secretID string) {
+	s.eb.Publish(secretUpdatedTopic, organizationID, secretID)
+}
+
+func (s *secretEventBus) SecretDeleted(organizationID uint, secretID string) {
+	s.eb.Publish(secretDeletedTopic, organizationID, secretID)
+}
@@ -44,12 +44,14 @@ func InitSecretStore(store secret.Store, types secret.TypeList) {
 	Store = &secretStore{
 		SecretStore: store,
 		Types:       types,
+		Events:      NewNopSecretEvents(),
 	}
 }
 
 type secretStore struct {
 	SecretStore secret.Store
 	Types       secret.TypeList
+	Events      secretEvents
 }
 
 // CreateSecretRequest param for secretStore.Store
@@ -170,6 +172,8 @@ func (ss *secretStore) Delete(organizationID uint, secretID string) error {
 		}
 	}
 
+	ss.Events.SecretDeleted(organizationID, secretID)
+
 	return nil
 }
 
@@ -239,6 +243,8 @@ func (ss *secretStore) Store(organizationID uint, request *CreateSecretRequest)
 		return "", err
 	}
 
+	ss.Events.SecretCreated(organizationID, secretID)
+
 	return secretID, nil
 }
 
@@ -294,6 +300,8 @@ func (ss *secretStore) Update(organizationID uint, secretID string, request *Cre
 		return err
 	}
 
+	ss.Events.SecretUpdated(organizationID, secretID)
+
 	return nil
 }
 